@@ -0,0 +1,190 @@
+// Package cache is a small on-disk key/value cache with per-entry expiry,
+// shared by sources and enrichers that want to remember remote lookups
+// between runs. It lives in its own SQLite file, apart from the post store,
+// so it stays local (even with storage.driver: postgres) and can be deleted
+// at any time without losing data.
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS entries (
+    namespace TEXT NOT NULL,
+    key TEXT NOT NULL,
+    value BLOB NOT NULL,
+    expires_at INTEGER NOT NULL,
+    PRIMARY KEY (namespace, key)
+);
+CREATE INDEX IF NOT EXISTS idx_entries_expires_at ON entries(expires_at);
+`
+
+// busyTimeout is how long a write waits for another process (a second
+// "noisepan pull") holding the file's lock.
+const busyTimeout = 5 * time.Second
+
+// Cache stores byte values under a namespace and key until they expire.
+// Namespaces keep users apart ("hn-item", ...). A nil *Cache is valid and
+// caches nothing, so callers need not check whether caching is configured.
+// It is safe for concurrent use.
+type Cache struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+// Open opens (creating if needed) the cache file at path and drops entries
+// that have already expired.
+func Open(path string) (*Cache, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, errors.New("cache path is required")
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create cache dir: %w", err)
+		}
+	}
+
+	params := url.Values{"_pragma": {
+		fmt.Sprintf("busy_timeout(%d)", busyTimeout.Milliseconds()),
+		"journal_mode(WAL)",
+		"synchronous(NORMAL)",
+	}}
+	db, err := sql.Open("sqlite", path+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("open cache: %w", err)
+	}
+	// One connection serializes writers from concurrent fetch workers.
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("apply cache schema: %w", err)
+	}
+
+	c := &Cache{db: db, now: time.Now}
+	if _, err := c.Purge(context.Background()); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Close closes the cache file.
+func (c *Cache) Close() error {
+	if c == nil || c.db == nil {
+		return nil
+	}
+	return c.db.Close()
+}
+
+// Get returns the unexpired value stored under namespace and key.
+func (c *Cache) Get(ctx context.Context, namespace, key string) ([]byte, bool, error) {
+	if c == nil || c.db == nil {
+		return nil, false, nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var value []byte
+	err := c.db.QueryRowContext(ctx,
+		"SELECT value FROM entries WHERE namespace = ? AND key = ? AND expires_at > ?",
+		namespace, key, c.now().UnixNano()).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("cache get %s/%s: %w", namespace, key, err)
+	}
+	return value, true, nil
+}
+
+// Set stores value under namespace and key for ttl, replacing any previous
+// value. A ttl of zero or less deletes the entry.
+func (c *Cache) Set(ctx context.Context, namespace, key string, value []byte, ttl time.Duration) error {
+	if c == nil || c.db == nil {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if ttl <= 0 {
+		return c.Delete(ctx, namespace, key)
+	}
+
+	_, err := c.db.ExecContext(ctx,
+		`INSERT INTO entries(namespace, key, value, expires_at) VALUES(?, ?, ?, ?)
+		ON CONFLICT(namespace, key) DO UPDATE SET value = excluded.value, expires_at = excluded.expires_at`,
+		namespace, key, value, c.now().Add(ttl).UnixNano())
+	if err != nil {
+		return fmt.Errorf("cache set %s/%s: %w", namespace, key, err)
+	}
+	return nil
+}
+
+// Delete removes the entry under namespace and key, if any.
+func (c *Cache) Delete(ctx context.Context, namespace, key string) error {
+	if c == nil || c.db == nil {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if _, err := c.db.ExecContext(ctx, "DELETE FROM entries WHERE namespace = ? AND key = ?", namespace, key); err != nil {
+		return fmt.Errorf("cache delete %s/%s: %w", namespace, key, err)
+	}
+	return nil
+}
+
+// Purge deletes expired entries and returns how many there were.
+func (c *Cache) Purge(ctx context.Context) (int64, error) {
+	if c == nil || c.db == nil {
+		return 0, nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	res, err := c.db.ExecContext(ctx, "DELETE FROM entries WHERE expires_at <= ?", c.now().UnixNano())
+	if err != nil {
+		return 0, fmt.Errorf("purge cache: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// GetJSON decodes the value under namespace and key into v. It reports false
+// when there is no unexpired entry.
+func (c *Cache) GetJSON(ctx context.Context, namespace, key string, v any) (bool, error) {
+	data, ok, err := c.Get(ctx, namespace, key)
+	if err != nil || !ok {
+		return false, err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("cache decode %s/%s: %w", namespace, key, err)
+	}
+	return true, nil
+}
+
+// SetJSON stores v encoded as JSON under namespace and key for ttl.
+func (c *Cache) SetJSON(ctx context.Context, namespace, key string, v any, ttl time.Duration) error {
+	if c == nil || c.db == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("cache encode %s/%s: %w", namespace, key, err)
+	}
+	return c.Set(ctx, namespace, key, data, ttl)
+}
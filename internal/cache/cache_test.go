@@ -0,0 +1,141 @@
+package cache
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func openTestCache(t *testing.T) *Cache {
+	t.Helper()
+	c, err := Open(filepath.Join(t.TempDir(), "cache", "cache.db"))
+	if err != nil {
+		t.Fatalf("open cache: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func TestCacheGetSet(t *testing.T) {
+	c := openTestCache(t)
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	if _, ok, err := c.Get(ctx, "hn-item", "1"); err != nil || ok {
+		t.Fatalf("empty get = %v, %v", ok, err)
+	}
+	if err := c.Set(ctx, "hn-item", "1", []byte("one"), time.Minute); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if err := c.Set(ctx, "other", "1", []byte("other"), time.Hour); err != nil {
+		t.Fatalf("set other: %v", err)
+	}
+	if v, ok, err := c.Get(ctx, "hn-item", "1"); err != nil || !ok || string(v) != "one" {
+		t.Fatalf("get = %q, %v, %v", v, ok, err)
+	}
+
+	// Overwrite keeps one entry with the new value and expiry.
+	if err := c.Set(ctx, "hn-item", "1", []byte("uno"), 2*time.Minute); err != nil {
+		t.Fatalf("overwrite: %v", err)
+	}
+	now = now.Add(90 * time.Second)
+	if v, ok, _ := c.Get(ctx, "hn-item", "1"); !ok || string(v) != "uno" {
+		t.Fatalf("after overwrite get = %q, %v", v, ok)
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok, _ := c.Get(ctx, "hn-item", "1"); ok {
+		t.Error("expired entry returned")
+	}
+	if v, ok, _ := c.Get(ctx, "other", "1"); !ok || string(v) != "other" {
+		t.Errorf("namespaces not separate: %q, %v", v, ok)
+	}
+
+	n, err := c.Purge(ctx)
+	if err != nil || n != 1 {
+		t.Errorf("purge = %d, %v, want 1", n, err)
+	}
+
+	if err := c.Set(ctx, "other", "1", []byte("x"), 0); err != nil {
+		t.Fatalf("set zero ttl: %v", err)
+	}
+	if _, ok, _ := c.Get(ctx, "other", "1"); ok {
+		t.Error("zero ttl should delete the entry")
+	}
+}
+
+func TestCacheJSON(t *testing.T) {
+	c := openTestCache(t)
+	ctx := context.Background()
+
+	type item struct {
+		ID    int    `json:"id"`
+		Title string `json:"title"`
+	}
+	if err := c.SetJSON(ctx, "hn-item", "7", item{ID: 7, Title: "Show HN"}, time.Hour); err != nil {
+		t.Fatalf("set json: %v", err)
+	}
+	var got item
+	ok, err := c.GetJSON(ctx, "hn-item", "7", &got)
+	if err != nil || !ok || got.Title != "Show HN" {
+		t.Fatalf("get json = %+v, %v, %v", got, ok, err)
+	}
+
+	if err := c.Set(ctx, "hn-item", "8", []byte("{broken"), time.Hour); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if _, err := c.GetJSON(ctx, "hn-item", "8", &got); err == nil {
+		t.Error("expected decode error")
+	}
+}
+
+func TestCacheNil(t *testing.T) {
+	var c *Cache
+	ctx := context.Background()
+	if err := c.Set(ctx, "ns", "k", []byte("v"), time.Hour); err != nil {
+		t.Fatalf("nil set: %v", err)
+	}
+	if _, ok, err := c.Get(ctx, "ns", "k"); ok || err != nil {
+		t.Errorf("nil get = %v, %v", ok, err)
+	}
+	if err := c.Close(); err != nil {
+		t.Errorf("nil close: %v", err)
+	}
+}
+
+func TestCacheConcurrentWriters(t *testing.T) {
+	c := openTestCache(t)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for w := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 25 {
+				if err := c.Set(ctx, "ns", string(rune('a'+w))+string(rune('0'+i%10)), []byte("v"), time.Hour); err != nil {
+					t.Errorf("set: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	var n int
+	if err := c.db.QueryRow("SELECT COUNT(*) FROM entries").Scan(&n); err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if n != 40 {
+		t.Errorf("entries = %d, want 40", n)
+	}
+}
+
+func TestOpen_RequiresPath(t *testing.T) {
+	if _, err := Open(" "); err == nil {
+		t.Fatal("expected error for empty path")
+	}
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var boilerplateCmd = &cobra.Command{
+	Use:   "boilerplate",
+	Short: "Show text blocks learned as per-channel boilerplate",
+	RunE:  boilerplateAction,
+}
+
+func init() {
+	rootCmd.AddCommand(boilerplateCmd)
+}
+
+// boilerplatePreviewRunes caps how much of each block is printed.
+const boilerplatePreviewRunes = 80
+
+func boilerplateAction(cmd *cobra.Command, _ []string) error {
+	cfg, err := config.Load(configDir)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	db, err := openStore(cfg)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	blocks, err := db.GetBoilerplate(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("get boilerplate: %w", err)
+	}
+
+	if cfg.Boilerplate.Disabled {
+		fmt.Fprintln(os.Stdout, "Boilerplate detection is disabled; learned blocks are not applied.")
+	}
+	if len(blocks) == 0 {
+		fmt.Fprintln(os.Stdout, "No boilerplate learned yet. Run 'noisepan pull' first.")
+		return nil
+	}
+	printBoilerplate(os.Stdout, blocks)
+	return nil
+}
+
+// printBoilerplate lists learned blocks grouped by channel. blocks must be
+// ordered by source and channel, as GetBoilerplate returns them.
+func printBoilerplate(w io.Writer, blocks []store.BoilerplateBlock) {
+	for i, b := range blocks {
+		if i == 0 || b.Source != blocks[i-1].Source || b.Channel != blocks[i-1].Channel {
+			n := 0
+			for _, o := range blocks[i:] {
+				if o.Source != b.Source || o.Channel != b.Channel {
+					break
+				}
+				n++
+			}
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintf(w, "%s/%s — %d blocks learned from %d posts (%s)\n",
+				b.Source, b.Channel, n, b.Sample, b.LearnedAt.Local().Format("2006-01-02 15:04"))
+		}
+		preview := strings.ReplaceAll(b.Text, "\n", " ⏎ ")
+		if short := firstNRunes(preview, boilerplatePreviewRunes); short != preview {
+			preview = short + "…"
+		}
+		fmt.Fprintf(w, "  [%d/%d] %s\n", b.Posts, b.Sample, preview)
+	}
+}
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/store"
+)
+
+func TestPrintBoilerplate(t *testing.T) {
+	learned := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	blocks := []store.BoilerplateBlock{
+		{Source: "rss", Channel: "Vendor Blog", Text: "Subscribe to our newsletter for more", Posts: 40, Sample: 50, LearnedAt: learned},
+		{Source: "rss", Channel: "Vendor Blog", Text: "Sponsored by Acme\nTry it free today", Posts: 31, Sample: 50, LearnedAt: learned},
+		{Source: "telegram", Channel: "devops", Text: strings.Repeat("long footer ", 20), Posts: 9, Sample: 12, LearnedAt: learned},
+	}
+
+	var buf bytes.Buffer
+	printBoilerplate(&buf, blocks)
+	out := buf.String()
+
+	for _, want := range []string{
+		"rss/Vendor Blog — 2 blocks learned from 50 posts",
+		"  [40/50] Subscribe to our newsletter for more\n",
+		"  [31/50] Sponsored by Acme ⏎ Try it free today\n",
+		"telegram/devops — 1 blocks learned from 12 posts",
+		"…\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/summarize"
+	"github.com/spf13/cobra"
+)
+
+var (
+	costsSince  string
+	costsBy     string
+	costsFormat string
+)
+
+var costsCmd = &cobra.Command{
+	Use:   "costs",
+	Short: "Show LLM calls, tokens, and their cost by day and model",
+	Long: `Lists the LLM API calls summaries and triage made, the prompt and
+completion tokens the API reported for them, and what they cost at
+summarize.llm.prices (USD per million tokens), per UTC day and model, or
+per model with --by model. Models without a price show no cost.
+
+With summarize.llm.monthly_budget set, also shows this month's spend
+against it. Once the month's calls cost as much as the budget, summaries
+fall back to heuristic and triage stops until the next month.`,
+	Args: cobra.NoArgs,
+	RunE: costsAction,
+}
+
+func init() {
+	costsCmd.Flags().StringVar(&costsSince, "since", "30d", "time window (e.g. 7d, 48h)")
+	costsCmd.Flags().StringVar(&costsBy, "by", "day", "rows per: day (and model), model")
+	costsCmd.Flags().StringVar(&costsFormat, "format", "terminal", "output format: terminal, json")
+	rootCmd.AddCommand(costsCmd)
+}
+
+func costsAction(cmd *cobra.Command, _ []string) error {
+	cfg, err := config.Load(configDir)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	if costsBy != "day" && costsBy != "model" {
+		return fmt.Errorf("unknown --by %q (want day or model)", costsBy)
+	}
+	if costsFormat != "terminal" && costsFormat != "json" {
+		return fmt.Errorf("unknown format %q (want terminal or json)", costsFormat)
+	}
+
+	db, err := openStore(cfg)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	sinceDur, err := parseDuration(costsSince)
+	if err != nil {
+		return fmt.Errorf("parse --since: %w", err)
+	}
+	ctx := cmd.Context()
+	now := time.Now()
+	usage, err := db.GetLLMUsage(ctx, now.Add(-sinceDur))
+	if err != nil {
+		return err
+	}
+	if costsBy == "model" {
+		usage = usageByModel(usage)
+	}
+	spent, err := monthSpend(ctx, db, cfg.Summarize.LLM, now)
+	if err != nil {
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+	if costsFormat == "json" {
+		return printCostsJSON(w, usage, cfg.Summarize.LLM, spent)
+	}
+	printCosts(w, usage, cfg.Summarize.LLM, spent, sinceDur)
+	return nil
+}
+
+// usageByModel sums usage over days, per model, in order of first use.
+func usageByModel(usage []store.LLMUsage) []store.LLMUsage {
+	var out []store.LLMUsage
+	index := make(map[string]int)
+	for _, u := range usage {
+		i, ok := index[u.Model]
+		if !ok {
+			index[u.Model] = len(out)
+			out = append(out, store.LLMUsage{Model: u.Model})
+			i = len(out) - 1
+		}
+		out[i].Calls += u.Calls
+		out[i].PromptTokens += u.PromptTokens
+		out[i].CompletionTokens += u.CompletionTokens
+	}
+	return out
+}
+
+// monthSpend returns what this UTC month's LLM calls cost at
+// summarize.llm.prices; unpriced models count nothing.
+func monthSpend(ctx context.Context, db *store.Store, llm config.LLMConfig, now time.Time) (float64, error) {
+	utc := now.UTC()
+	usage, err := db.GetLLMUsage(ctx, time.Date(utc.Year(), utc.Month(), 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		return 0, err
+	}
+	var spent float64
+	for _, u := range usage {
+		cost, _ := llm.Cost(u.Model, u.PromptTokens, u.CompletionTokens)
+		spent += cost
+	}
+	return spent, nil
+}
+
+func printCosts(w io.Writer, usage []store.LLMUsage, llm config.LLMConfig, spent float64, since time.Duration) {
+	fmt.Fprintf(w, "noisepan LLM costs — %s\n\n", formatStatsDuration(since))
+	if len(usage) == 0 {
+		fmt.Fprintln(w, "No LLM calls recorded. Set summarize.mode: llm or llm_triage on a channel to use one.")
+	} else {
+		// Rows summed per model have no day, and no Day column.
+		byDay := !usage[0].Day.IsZero()
+		row := func(day, model string, calls, prompt, completion any, cost string) {
+			if byDay {
+				fmt.Fprintf(w, "%-10s  ", day)
+			}
+			fmt.Fprintf(w, "%-24s  %6v  %10v  %10v  %9s\n", model, calls, prompt, completion, cost)
+		}
+		row("Day", "Model", "Calls", "Prompt", "Completion", "Cost")
+		var total store.LLMUsage
+		var totalCost float64
+		priced := true
+		for _, u := range usage {
+			cost, ok := llm.Cost(u.Model, u.PromptTokens, u.CompletionTokens)
+			priced = priced && ok
+			totalCost += cost
+			total.Calls += u.Calls
+			total.PromptTokens += u.PromptTokens
+			total.CompletionTokens += u.CompletionTokens
+			row(u.Day.Format(time.DateOnly), u.Model, u.Calls, u.PromptTokens, u.CompletionTokens, formatCost(cost, ok))
+		}
+		label, model := "Total", ""
+		if !byDay {
+			label, model = "", "Total"
+		}
+		row(label, model, total.Calls, total.PromptTokens, total.CompletionTokens, formatCost(totalCost, true))
+		if !priced {
+			fmt.Fprintln(w, "\nModels without a summarize.llm.prices entry show no cost and count nothing toward the total.")
+		}
+	}
+	if llm.MonthlyBudget > 0 {
+		fmt.Fprintf(w, "\nThis month: %s of %s budget", formatCost(spent, true), formatCost(llm.MonthlyBudget, true))
+		if spent >= llm.MonthlyBudget {
+			fmt.Fprint(w, " — spent; summaries use the heuristic until next month")
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// formatCost prints USD with four decimals, small enough for single calls,
+// or "-" when ok is false.
+func formatCost(usd float64, ok bool) string {
+	if !ok {
+		return "-"
+	}
+	return fmt.Sprintf("$%.4f", usd)
+}
+
+type jsonCost struct {
+	Day              string   `json:"day,omitempty"`
+	Model            string   `json:"model"`
+	Calls            int64    `json:"calls"`
+	PromptTokens     int64    `json:"prompt_tokens"`
+	CompletionTokens int64    `json:"completion_tokens"`
+	CostUSD          *float64 `json:"cost_usd"` // null without a price
+}
+
+type jsonCosts struct {
+	Usage            []jsonCost `json:"usage"`
+	MonthSpentUSD    float64    `json:"month_spent_usd"`
+	MonthlyBudgetUSD float64    `json:"monthly_budget_usd,omitempty"`
+}
+
+func printCostsJSON(w io.Writer, usage []store.LLMUsage, llm config.LLMConfig, spent float64) error {
+	out := jsonCosts{Usage: []jsonCost{}, MonthSpentUSD: spent, MonthlyBudgetUSD: llm.MonthlyBudget}
+	for _, u := range usage {
+		c := jsonCost{Model: u.Model, Calls: u.Calls, PromptTokens: u.PromptTokens, CompletionTokens: u.CompletionTokens}
+		if !u.Day.IsZero() {
+			c.Day = u.Day.Format(time.DateOnly)
+		}
+		if cost, ok := llm.Cost(u.Model, u.PromptTokens, u.CompletionTokens); ok {
+			c.CostUSD = &cost
+		}
+		out.Usage = append(out.Usage, c)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// llmMeter records the token usage of LLM calls in the store and, with
+// summarize.llm.monthly_budget set, reports the budget spent once this
+// month's calls cost as much. Spend is read from the store on every check,
+// so summaries and triage in one run share the budget.
+type llmMeter struct {
+	ctx  context.Context
+	db   *store.Store
+	llm  config.LLMConfig
+	warn sync.Once
+}
+
+func newLLMMeter(ctx context.Context, llm config.LLMConfig, db *store.Store) *llmMeter {
+	return &llmMeter{ctx: ctx, db: db, llm: llm}
+}
+
+func (m *llmMeter) Record(model string, u summarize.Usage) {
+	if err := m.db.AddLLMUsage(m.ctx, time.Now(), model, int64(u.PromptTokens), int64(u.CompletionTokens)); err != nil {
+		slog.Warn("record llm usage", "err", err)
+	}
+}
+
+func (m *llmMeter) Exhausted() bool {
+	if m.llm.MonthlyBudget <= 0 {
+		return false
+	}
+	spent, err := monthSpend(m.ctx, m.db, m.llm, time.Now())
+	if err != nil {
+		slog.Warn("read llm usage", "err", err)
+		return false
+	}
+	if spent < m.llm.MonthlyBudget {
+		return false
+	}
+	m.warn.Do(func() {
+		slog.Warn("llm monthly budget spent; using heuristic summaries",
+			"spent_usd", fmt.Sprintf("%.2f", spent), "budget_usd", fmt.Sprintf("%.2f", m.llm.MonthlyBudget))
+	})
+	return true
+}
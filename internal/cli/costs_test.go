@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/summarize"
+	"github.com/spf13/cobra"
+)
+
+func TestCostsAction(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	scriptPath := filepath.Join(tmpDir, "forge-plan.sh")
+	writeTestForgePlanScript(t, scriptPath)
+	content := "sources:\n  forgeplan:\n    script: \"" + scriptPath + "\"\n" +
+		"storage:\n  path: \"" + dbPath + "\"\n" +
+		"summarize:\n  llm:\n    model: gpt-4.1-mini\n" +
+		"    prices:\n      gpt-4.1-mini: {input: 0.4, output: 1.6}\n" +
+		"    monthly_budget: 5\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.yaml"), []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	st, err := store.Open(dbPath)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	ctx := context.Background()
+	now := time.Now()
+	for _, c := range []struct {
+		at    time.Time
+		model string
+	}{{now, "gpt-4.1-mini"}, {now, "gpt-4.1-mini"}, {now, "llama3.2"}} {
+		if err := st.AddLLMUsage(ctx, c.at, c.model, 1_000_000, 250_000); err != nil {
+			t.Fatalf("add usage: %v", err)
+		}
+	}
+	_ = st.Close()
+
+	oldConfigDir, oldSince, oldBy, oldFormat := configDir, costsSince, costsBy, costsFormat
+	t.Cleanup(func() { configDir, costsSince, costsBy, costsFormat = oldConfigDir, oldSince, oldBy, oldFormat })
+	configDir, costsSince, costsBy, costsFormat = tmpDir, "30d", "model", "terminal"
+
+	var buf bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetContext(ctx)
+	cmd.SetOut(&buf)
+	if err := costsAction(cmd, nil); err != nil {
+		t.Fatalf("costs: %v", err)
+	}
+	out := buf.String()
+	// Two calls of 1M prompt and 250k completion tokens at $0.40/$1.60.
+	for _, want := range []string{"gpt-4.1-mini", "2000000", "$1.6000", "llama3.2"} {
+		requireContains(t, out, want)
+	}
+	requireContains(t, out, "This month: $1.6000 of $5.0000 budget")
+	requireContains(t, out, "without a summarize.llm.prices entry")
+
+	costsBy = "week"
+	if err := costsAction(cmd, nil); err == nil {
+		t.Error("--by week: want error")
+	}
+}
+
+func TestLLMMeter(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "noisepan.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+	ctx := context.Background()
+
+	llm := config.LLMConfig{
+		Prices:        map[string]config.LLMPrice{"gpt-4.1-mini": {Input: 1, Output: 4}},
+		MonthlyBudget: 2,
+	}
+	m := newLLMMeter(ctx, llm, st)
+	if m.Exhausted() {
+		t.Fatal("exhausted before any call")
+	}
+	// Last month's spend does not count toward this month's budget.
+	now := time.Now().UTC()
+	lastMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).Add(-time.Hour)
+	if err := st.AddLLMUsage(ctx, lastMonth, "gpt-4.1-mini", 10_000_000, 0); err != nil {
+		t.Fatalf("add usage: %v", err)
+	}
+	m.Record("gpt-4.1-mini", summarize.Usage{PromptTokens: 1_000_000, CompletionTokens: 100_000})
+	if m.Exhausted() {
+		t.Error("exhausted at $1.40 of $2")
+	}
+	m.Record("gpt-4.1-mini", summarize.Usage{PromptTokens: 500_000, CompletionTokens: 100_000})
+	if !m.Exhausted() {
+		t.Error("not exhausted at $2.30 of $2")
+	}
+
+	usage, err := st.GetLLMUsage(ctx, time.Now())
+	if err != nil || len(usage) != 1 || usage[0].Calls != 2 || usage[0].PromptTokens != 1_500_000 {
+		t.Errorf("recorded = %+v, %v", usage, err)
+	}
+}
@@ -0,0 +1,261 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/digest"
+	"github.com/ppiankov/noisepan/internal/server"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/taste"
+)
+
+// dashboardBackend serves the web dashboard and JSON API from the store.
+type dashboardBackend struct {
+	db      *store.Store
+	scorer  *postScorer
+	digests *digestPipeline                 // summarizes /api/feed; nil disables it
+	notes   map[string]config.ChannelConfig // channel notes and owners, by channel
+	mu      sync.Mutex                      // serializes scoring between requests and the stream watcher
+}
+
+// pipeline returns the digest pipeline stages the dashboard reuses: load
+// and score.
+func (b *dashboardBackend) pipeline() *digestPipeline {
+	return &digestPipeline{db: b.db, scorer: b.scorer}
+}
+
+// score scores posts that need it, as digest does, and orders them by score.
+func (b *dashboardBackend) score(ctx context.Context, posts []store.PostWithScore) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.pipeline().score(ctx, posts, time.Now())
+}
+
+func (b *dashboardBackend) Posts(ctx context.Context, q server.PostQuery) ([]server.Item, error) {
+	posts, err := b.db.GetPosts(ctx, time.Now().Add(-q.Since), q.Tier, store.PostFilter{
+		Source: q.Source, Channel: q.Channel, UnreadOnly: q.UnreadOnly, Limit: q.Limit, Offset: q.Offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get posts: %w", err)
+	}
+	starred, votes, err := postMarks(ctx, b.db)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]server.Item, 0, len(posts))
+	for _, p := range posts {
+		items = append(items, dashboardItem(p, starred, votes))
+	}
+	return items, nil
+}
+
+func (b *dashboardBackend) Digest(ctx context.Context, since time.Duration) (server.Digest, error) {
+	posts, err := b.pipeline().load(ctx, time.Now().Add(-since), store.PostFilter{})
+	if err != nil {
+		return server.Digest{}, err
+	}
+	if err := b.score(ctx, posts); err != nil {
+		return server.Digest{}, err
+	}
+	starred, votes, err := postMarks(ctx, b.db)
+	if err != nil {
+		return server.Digest{}, err
+	}
+
+	d := server.Digest{Since: since.String()}
+	for _, p := range posts {
+		switch p.Score.Tier {
+		case taste.TierReadNow:
+			d.ReadNow = append(d.ReadNow, dashboardItem(p, starred, votes))
+		case taste.TierIgnore:
+			d.Ignored++
+		default:
+			d.Skim = append(d.Skim, dashboardItem(p, starred, votes))
+		}
+	}
+	return d, nil
+}
+
+// Feed scores and summarizes the window as digest does, without recording
+// it, and renders it as Atom. Summaries run outside the scoring lock.
+func (b *dashboardBackend) Feed(ctx context.Context, since time.Duration, self string) ([]byte, error) {
+	if b.digests == nil {
+		return nil, errors.New("feed is not available")
+	}
+	posts, err := b.pipeline().load(ctx, time.Now().Add(-since), store.PostFilter{})
+	if err != nil {
+		return nil, err
+	}
+	if err := b.score(ctx, posts); err != nil {
+		return nil, err
+	}
+	built, err := b.digests.summarize(ctx, posts, nil)
+	if err != nil {
+		return nil, err
+	}
+	built.Input.Since = since
+	var buf bytes.Buffer
+	if err := renderDigest(&buf, digest.NewAtom(feedTitle, self), built.Input); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (b *dashboardBackend) Search(ctx context.Context, query string, limit int) ([]server.Item, error) {
+	results, err := b.db.Search(ctx, query, store.SearchFilter{Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	starred, votes, err := postMarks(ctx, b.db)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]server.Item, 0, len(results))
+	for _, r := range results {
+		items = append(items, dashboardItem(r.PostWithScore, starred, votes))
+	}
+	return items, nil
+}
+
+func (b *dashboardBackend) Channels(ctx context.Context, since time.Duration) ([]server.ChannelStat, error) {
+	stats, err := b.db.GetChannelStats(ctx, time.Now().Add(-since))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]server.ChannelStat, 0, len(stats))
+	for _, cs := range stats {
+		out = append(out, server.ChannelStat{
+			Source: cs.Source, Channel: cs.Channel, Total: cs.Total,
+			ReadNow: cs.ReadNow, Skim: cs.Skim, Ignored: cs.Ignored,
+			Owner: b.notes[cs.Channel].Owner, Note: b.notes[cs.Channel].Note,
+		})
+	}
+	return out, nil
+}
+
+func (b *dashboardBackend) Stats(ctx context.Context, since time.Duration) (server.Stats, error) {
+	channels, err := b.Channels(ctx, since)
+	if err != nil {
+		return server.Stats{}, err
+	}
+	st := server.Stats{Since: since.String(), Channels: channels}
+	for _, cs := range channels {
+		st.Posts += cs.Total
+		st.ReadNow += cs.ReadNow
+		st.Skim += cs.Skim
+		st.Ignored += cs.Ignored
+	}
+
+	starred, err := b.db.GetStarred(ctx)
+	if err != nil {
+		return server.Stats{}, fmt.Errorf("get starred: %w", err)
+	}
+	st.Starred = len(starred)
+
+	feedback, err := b.db.GetFeedbackByTier(ctx, time.Now().Add(-since))
+	if err != nil {
+		return server.Stats{}, fmt.Errorf("get feedback: %w", err)
+	}
+	for _, tf := range feedback {
+		st.Up += tf.Up
+		st.Down += tf.Down
+	}
+	return st, nil
+}
+
+func (b *dashboardBackend) Post(ctx context.Context, id int64) (server.PostDetail, error) {
+	p, err := b.post(ctx, id)
+	if err != nil {
+		return server.PostDetail{}, err
+	}
+	if p.Score == nil {
+		posts := []store.PostWithScore{p}
+		if err := b.score(ctx, posts); err != nil {
+			return server.PostDetail{}, err
+		}
+		p = posts[0]
+	}
+	starred, votes, err := postMarks(ctx, b.db)
+	if err != nil {
+		return server.PostDetail{}, err
+	}
+	alsoIn, err := b.db.GetAlsoIn(ctx, []int64{id})
+	if err != nil {
+		return server.PostDetail{}, fmt.Errorf("get also-in: %w", err)
+	}
+
+	detail := server.PostDetail{
+		Item:        dashboardItem(p, starred, votes),
+		Text:        postText(p.Post),
+		AlsoIn:      alsoIn[id],
+		Changed:     p.Changed(),
+		Explanation: []server.Contribution{},
+	}
+	var contributions []taste.ScoreContribution
+	if len(p.Score.Explanation) > 0 && json.Unmarshal(p.Score.Explanation, &contributions) == nil {
+		for _, c := range contributions {
+			detail.Explanation = append(detail.Explanation, server.Contribution{Reason: c.Reason, Points: c.Points})
+		}
+	}
+	return detail, nil
+}
+
+func (b *dashboardBackend) Feedback(ctx context.Context, id int64, up bool) error {
+	if _, err := b.post(ctx, id); err != nil {
+		return err
+	}
+	vote := store.FeedbackDown
+	if up {
+		vote = store.FeedbackUp
+	}
+	return b.db.SaveFeedback(ctx, store.Feedback{PostID: id, Vote: vote, CreatedAt: time.Now()})
+}
+
+func (b *dashboardBackend) SetStarred(ctx context.Context, id int64, starred bool) error {
+	if _, err := b.post(ctx, id); err != nil {
+		return err
+	}
+	if starred {
+		return b.db.Star(ctx, id, time.Now())
+	}
+	_, err := b.db.Unstar(ctx, id)
+	return err
+}
+
+// post looks up a post, mapping a missing one to server.ErrNotFound.
+func (b *dashboardBackend) post(ctx context.Context, id int64) (store.PostWithScore, error) {
+	p, err := b.db.GetPostByID(ctx, id)
+	if errors.Is(err, store.ErrPostNotFound) {
+		return p, fmt.Errorf("post %d: %w", id, server.ErrNotFound)
+	}
+	if err != nil {
+		return p, fmt.Errorf("get post: %w", err)
+	}
+	return p, nil
+}
+
+func dashboardItem(p store.PostWithScore, starred map[int64]bool, votes map[int64]int) server.Item {
+	item := server.Item{
+		ID:       p.Post.ID,
+		Source:   p.Post.Source,
+		Channel:  p.Post.Channel,
+		URL:      p.Post.URL,
+		PostedAt: p.Post.PostedAt.UTC().Format(time.RFC3339),
+		Snippet:  searchSnippet(p.Post),
+		Starred:  starred[p.Post.ID],
+		Vote:     votes[p.Post.ID],
+	}
+	if p.Score != nil {
+		item.Score = p.Score.Score
+		item.Tier = p.Score.Tier
+		item.Labels = p.Score.Labels
+	}
+	return item
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/server"
+	"github.com/ppiankov/noisepan/internal/store"
+)
+
+func TestDashboardBackend(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "noisepan.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, p := range []struct{ id, text string }{
+		{"hot", "cve OpenSSL exploited"},
+		{"meh", "cve in a changelog"},
+		{"dull", "office hours moved"},
+	} {
+		if _, err := st.InsertPost(ctx, store.PostInput{
+			Source: "rss", Channel: "security", ExternalID: p.id,
+			Text: p.text, URL: "https://example.com/" + p.id, PostedAt: now, FetchedAt: now,
+		}); err != nil {
+			t.Fatalf("insert %s: %v", p.id, err)
+		}
+	}
+
+	profile := testScorerProfile()
+	profile.Weights.HighSignal["exploited"] = 3
+	b := &dashboardBackend{db: st, scorer: &postScorer{profile: profile},
+		notes: map[string]config.ChannelConfig{"security": {Note: "vendor advisories", Owner: "secops"}}}
+
+	// Detail scores an unscored post on demand.
+	detail, err := b.Post(ctx, 1)
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	if detail.Score != 8 || detail.Tier != "read_now" || detail.Text != "cve OpenSSL exploited" || len(detail.Explanation) != 2 {
+		t.Errorf("detail = %+v", detail)
+	}
+	if _, err := b.Post(ctx, 99); !errors.Is(err, server.ErrNotFound) {
+		t.Errorf("missing post err = %v, want ErrNotFound", err)
+	}
+
+	if err := b.SetStarred(ctx, 1, true); err != nil {
+		t.Fatalf("star: %v", err)
+	}
+	if err := b.Feedback(ctx, 2, false); err != nil {
+		t.Fatalf("feedback: %v", err)
+	}
+	if err := b.Feedback(ctx, 99, true); !errors.Is(err, server.ErrNotFound) {
+		t.Errorf("feedback on missing post err = %v, want ErrNotFound", err)
+	}
+
+	d, err := b.Digest(ctx, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("digest: %v", err)
+	}
+	if len(d.ReadNow) != 1 || len(d.Skim) != 1 || d.Ignored != 1 {
+		t.Fatalf("digest = %+v", d)
+	}
+	if !d.ReadNow[0].Starred || d.Skim[0].Vote != store.FeedbackDown {
+		t.Errorf("marks not shown: read_now %+v, skim %+v", d.ReadNow[0], d.Skim[0])
+	}
+
+	results, err := b.Search(ctx, "changelog", 10)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != 2 || results[0].Tier != "skim" {
+		t.Errorf("search = %+v", results)
+	}
+
+	channels, err := b.Channels(ctx, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("channels: %v", err)
+	}
+	if len(channels) != 1 || channels[0].Total != 3 || channels[0].ReadNow != 1 || channels[0].Ignored != 1 {
+		t.Errorf("channels = %+v", channels)
+	}
+	if channels[0].Note != "vendor advisories" || channels[0].Owner != "secops" {
+		t.Errorf("channel note = %q, owner = %q", channels[0].Note, channels[0].Owner)
+	}
+
+	posts, err := b.Posts(ctx, server.PostQuery{Since: time.Hour, Tier: "skim", Limit: 10})
+	if err != nil {
+		t.Fatalf("posts: %v", err)
+	}
+	if len(posts) != 1 || posts[0].ID != 2 || posts[0].Vote != store.FeedbackDown {
+		t.Errorf("posts = %+v", posts)
+	}
+	if posts, err = b.Posts(ctx, server.PostQuery{Since: time.Hour, Limit: 2, Offset: 2}); err != nil || len(posts) != 1 {
+		t.Errorf("paged posts = %+v, err %v", posts, err)
+	}
+
+	stats, err := b.Stats(ctx, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	if stats.Posts != 3 || stats.ReadNow != 1 || stats.Skim != 1 || stats.Starred != 1 || stats.Down != 1 || len(stats.Channels) != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	if err := b.SetStarred(ctx, 1, false); err != nil {
+		t.Fatalf("unstar: %v", err)
+	}
+	if detail, err = b.Post(ctx, 1); err != nil || detail.Starred {
+		t.Errorf("after unstar: starred=%v err=%v", detail.Starred, err)
+	}
+}
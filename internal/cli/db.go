@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dbMaintainNoVacuum bool
+	dbPurgeOlderThan   string
+)
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Inspect and maintain the database",
+}
+
+var dbMaintainCmd = &cobra.Command{
+	Use:   "maintain",
+	Short: "Check integrity, refresh statistics, and reclaim free space",
+	Long: `Runs an integrity check, ANALYZE, and VACUUM, then reports the database
+size and per-table row counts. "db purge" deletes rows but leaves the file
+its old size; VACUUM rewrites it to return the space. VACUUM blocks other
+writers while it runs and needs free disk space about the size of the
+database.`,
+	Args: cobra.NoArgs,
+	RunE: dbMaintainAction,
+}
+
+var dbPurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Permanently delete pruned posts",
+	Long: `Pruning past retain_days only tombstones posts: they leave digests, search,
+and stats but keep their text, score, and votes, so "db restore" can undo an
+over-aggressive retention setting. Purge deletes tombstoned posts for good.
+Run "db maintain" afterwards to shrink the database file.`,
+	Args: cobra.NoArgs,
+	RunE: dbPurgeAction,
+}
+
+var dbRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Bring back pruned posts that were not purged yet",
+	Long: `Clears every tombstone. Raise retain_days (or storage.retention) first, or
+the next pull prunes the same posts again.`,
+	Args: cobra.NoArgs,
+	RunE: dbRestoreAction,
+}
+
+func init() {
+	dbMaintainCmd.Flags().BoolVar(&dbMaintainNoVacuum, "no-vacuum", false, "skip VACUUM (check and analyze only)")
+	dbPurgeCmd.Flags().StringVar(&dbPurgeOlderThan, "older-than", "", "only posts pruned at least this long ago (e.g. 7d)")
+	dbCmd.AddCommand(dbMaintainCmd)
+	dbCmd.AddCommand(dbPurgeCmd)
+	dbCmd.AddCommand(dbRestoreCmd)
+	rootCmd.AddCommand(dbCmd)
+}
+
+func dbMaintainAction(cmd *cobra.Command, _ []string) error {
+	cfg, err := config.Load(configDir)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	db, err := openStore(cfg)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	// VACUUM cannot run inside the dry-run transaction.
+	return maintainStore(cmd.Context(), cmd.OutOrStdout(), db, !dbMaintainNoVacuum && !db.DryRun())
+}
+
+func dbPurgeAction(cmd *cobra.Command, _ []string) error {
+	before := time.Now()
+	if dbPurgeOlderThan != "" {
+		d, err := parseDuration(dbPurgeOlderThan)
+		if err != nil {
+			return fmt.Errorf("parse --older-than: %w", err)
+		}
+		before = before.Add(-d)
+	}
+
+	db, err := openConfiguredStore()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	n, err := db.Purge(cmd.Context(), before)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Purged %d pruned posts\n", n)
+	return nil
+}
+
+func dbRestoreAction(cmd *cobra.Command, _ []string) error {
+	db, err := openConfiguredStore()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	n, err := db.Restore(cmd.Context())
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Restored %d pruned posts\n", n)
+	return nil
+}
+
+// maintainStore runs the maintenance steps, reporting progress to w. It
+// stops at the integrity check if the database is damaged, since vacuuming
+// a corrupt file can lose more data.
+func maintainStore(ctx context.Context, w io.Writer, db *store.Store, vacuum bool) error {
+	before, err := db.Size(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(w, "Checking integrity... ")
+	problems, err := db.IntegrityCheck(ctx)
+	switch {
+	case errors.Is(err, errors.ErrUnsupported):
+		fmt.Fprintln(w, "skipped (not supported by this storage driver)")
+	case err != nil:
+		fmt.Fprintln(w, "failed")
+		return err
+	case len(problems) > 0:
+		fmt.Fprintf(w, "%d problems\n", len(problems))
+		for _, p := range problems {
+			fmt.Fprintf(w, "  %s\n", p)
+		}
+		return fmt.Errorf("integrity check found %d problems; restore from a backup or re-pull", len(problems))
+	default:
+		fmt.Fprintln(w, "ok")
+	}
+
+	fmt.Fprint(w, "Analyzing... ")
+	start := time.Now()
+	if err := db.Analyze(ctx); err != nil {
+		fmt.Fprintln(w, "failed")
+		return err
+	}
+	fmt.Fprintf(w, "done (%s)\n", time.Since(start).Round(time.Millisecond))
+
+	after := before
+	if vacuum {
+		fmt.Fprint(w, "Vacuuming... ")
+		start = time.Now()
+		if err := db.Vacuum(ctx); err != nil {
+			fmt.Fprintln(w, "failed")
+			return err
+		}
+		if after, err = db.Size(ctx); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "done (%s), %s → %s\n", time.Since(start).Round(time.Millisecond), formatBytes(before), formatBytes(after))
+	}
+
+	counts, err := db.TableRowCounts(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\nDatabase size: %s\n\n", formatBytes(after))
+	width := len("Table")
+	for _, c := range counts {
+		width = max(width, len(c.Table))
+	}
+	fmt.Fprintf(w, "  %-*s  %10s\n", width, "Table", "Rows")
+	for _, c := range counts {
+		fmt.Fprintf(w, "  %-*s  %10d\n", width, c.Table, c.Rows)
+	}
+	return nil
+}
+
+// formatBytes renders n in the largest binary unit that keeps it above 1.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
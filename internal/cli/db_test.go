@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func TestMaintainStore(t *testing.T) {
+	db := openStoreForPipelineTest(t, filepath.Join(t.TempDir(), "noisepan.db"))
+	ctx := context.Background()
+	now := time.Now()
+	for _, id := range []string{"1", "2", "3"} {
+		if _, err := db.InsertPost(ctx, store.PostInput{
+			Source: "rss", Channel: "News", ExternalID: id, Text: "post " + id, PostedAt: now, FetchedAt: now,
+		}); err != nil {
+			t.Fatalf("insert post: %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := maintainStore(ctx, &buf, db, true); err != nil {
+		t.Fatalf("maintainStore: %v", err)
+	}
+	out := buf.String()
+	requireContains(t, out, "Checking integrity... ok")
+	requireContains(t, out, "Analyzing... done")
+	requireContains(t, out, "Vacuuming... done")
+	requireContains(t, out, "Database size: ")
+	if !strings.Contains(out, "  posts ") || !strings.Contains(out, "         3\n") {
+		t.Errorf("missing posts row count:\n%s", out)
+	}
+
+	buf.Reset()
+	if err := maintainStore(ctx, &buf, db, false); err != nil {
+		t.Fatalf("maintainStore without vacuum: %v", err)
+	}
+	if strings.Contains(buf.String(), "Vacuuming") {
+		t.Errorf("vacuum ran with vacuum=false:\n%s", buf.String())
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		in   int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{5 << 20, "5.0 MiB"},
+		{3 << 30, "3.0 GiB"},
+	}
+	for _, tt := range tests {
+		if got := formatBytes(tt.in); got != tt.want {
+			t.Errorf("formatBytes(%d) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDBPurgeAndRestoreActions(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	scriptPath := filepath.Join(tmpDir, "forge-plan.sh")
+	writeTestForgePlanScript(t, scriptPath)
+	writeTestConfig(t, tmpDir, dbPath, scriptPath)
+
+	ctx := context.Background()
+	st := openStoreForPipelineTest(t, dbPath)
+	old := time.Now().AddDate(0, 0, -90)
+	if _, err := st.InsertPost(ctx, store.PostInput{
+		Source: "rss", Channel: "News", ExternalID: "1", Text: "old post", PostedAt: old, FetchedAt: old,
+	}); err != nil {
+		t.Fatalf("insert post: %v", err)
+	}
+	if _, err := st.PruneOld(ctx, 30); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+
+	oldConfigDir := configDir
+	t.Cleanup(func() { configDir = oldConfigDir; dbPurgeOlderThan = "" })
+	configDir = tmpDir
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(ctx)
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+
+	if err := dbRestoreAction(cmd, nil); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	requireContains(t, buf.String(), "Restored 1 pruned posts")
+	if _, err := st.PruneOld(ctx, 30); err != nil {
+		t.Fatalf("prune again: %v", err)
+	}
+
+	buf.Reset()
+	dbPurgeOlderThan = "7d"
+	if err := dbPurgeAction(cmd, nil); err != nil {
+		t.Fatalf("purge --older-than: %v", err)
+	}
+	requireContains(t, buf.String(), "Purged 0 pruned posts")
+
+	buf.Reset()
+	dbPurgeOlderThan = ""
+	if err := dbPurgeAction(cmd, nil); err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	requireContains(t, buf.String(), "Purged 1 pruned posts")
+	if n, err := st.Tombstoned(ctx); err != nil || n != 0 {
+		t.Errorf("tombstoned = %d, %v; want 0", n, err)
+	}
+}
@@ -2,8 +2,13 @@ package cli
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -12,21 +17,36 @@ import (
 
 	"github.com/ppiankov/noisepan/internal/config"
 	"github.com/ppiankov/noisepan/internal/digest"
+	"github.com/ppiankov/noisepan/internal/events"
+	"github.com/ppiankov/noisepan/internal/network"
 	"github.com/ppiankov/noisepan/internal/source"
 	"github.com/ppiankov/noisepan/internal/store"
 	"github.com/ppiankov/noisepan/internal/summarize"
 	"github.com/ppiankov/noisepan/internal/taste"
+	"github.com/ppiankov/noisepan/internal/telemetry"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var (
-	digestSince   string
-	digestFormat  string
-	digestSource  string
-	digestChannel string
-	noColor       bool
-	digestOutput  string
-	digestWebhook string
+	digestSince    string
+	digestFormat   string
+	digestTemplate string
+	digestSource   string
+	digestChannel  string
+	noColor        bool
+	digestOutput   string
+	digestWebhook  string
+	digestPublish  string
+	digestEmail    bool
+	digestTelegram bool
+	digestDiscord  bool
+	digestUnread   bool
+	digestMarkRead bool
+	digestStarred  bool
+	digestSave     bool
+	digestNewOnly  bool
+	digestGroupBy  string
 )
 
 var digestCmd = &cobra.Command{
@@ -37,12 +57,23 @@ var digestCmd = &cobra.Command{
 
 func init() {
 	digestCmd.Flags().StringVar(&digestSince, "since", "", "time window (e.g. 48h)")
-	digestCmd.Flags().StringVar(&digestFormat, "format", "", "output format: terminal, json, markdown")
+	digestCmd.Flags().StringVar(&digestFormat, "format", "", "output format: terminal, json, markdown, print")
+	digestCmd.Flags().StringVar(&digestTemplate, "template", "", "render through a Go template file instead of a format (default digest.template)")
 	digestCmd.Flags().StringVar(&digestSource, "source", "", "filter by source (e.g. rss, telegram, reddit)")
 	digestCmd.Flags().StringVar(&digestChannel, "channel", "", "filter by channel name")
 	digestCmd.Flags().BoolVar(&noColor, "no-color", false, "disable ANSI colors")
 	digestCmd.Flags().StringVar(&digestOutput, "output", "", "write digest to file (- for stdout)")
 	digestCmd.Flags().StringVar(&digestWebhook, "webhook", "", "POST digest JSON to URL")
+	digestCmd.Flags().StringVar(&digestPublish, "publish", "", "publish digest as a page: notion, confluence (comma-separated)")
+	digestCmd.Flags().BoolVar(&digestEmail, "email", false, "email digest to delivery.email recipients")
+	digestCmd.Flags().BoolVar(&digestTelegram, "telegram", false, "post digest to the delivery.telegram chat")
+	digestCmd.Flags().BoolVar(&digestDiscord, "discord", false, "post digest to the delivery.discord webhook")
+	digestCmd.Flags().BoolVar(&digestUnread, "unread-only", false, "skip posts already marked read")
+	digestCmd.Flags().BoolVar(&digestMarkRead, "mark-read", false, "mark read_now and skim items shown as read")
+	digestCmd.Flags().BoolVar(&digestStarred, "starred", false, "only starred posts")
+	digestCmd.Flags().BoolVar(&digestSave, "save", false, "keep the digest for noisepan history")
+	digestCmd.Flags().BoolVar(&digestNewOnly, "new-only", false, "skip posts a digest saved with --save listed")
+	digestCmd.Flags().StringVar(&digestGroupBy, "group-by", "", "section by label, channel or source instead of tier")
 }
 
 func digestAction(cmd *cobra.Command, _ []string) error {
@@ -51,13 +82,37 @@ func digestAction(cmd *cobra.Command, _ []string) error {
 		return fmt.Errorf("load config: %w", err)
 	}
 
-	tastePath := filepath.Join(configDir, config.DefaultTasteFile)
-	profile, err := config.LoadTaste(tastePath)
+	publishers, err := newPublishers(cfg, digestPublish)
+	if err != nil {
+		return err
+	}
+	emailer, err := newEmailSender(cfg, digestEmail)
+	if err != nil {
+		return err
+	}
+	telegram, err := newTelegramSender(cfg, digestTelegram)
+	if err != nil {
+		return err
+	}
+	discord, err := newDiscordSender(cfg, digestDiscord)
+	if err != nil {
+		return err
+	}
+	formatter, err := digestFormatterFor(cfg, digestFormat, digestTemplate, !noColor)
+	if err != nil {
+		return err
+	}
+	if err := digest.ValidateGroupBy(digestGroupBy); err != nil {
+		return fmt.Errorf("--group-by: %w", err)
+	}
+
+	tastePath := profileTastePath(cfg)
+	profile, err := loadTaste(cfg, tastePath)
 	if err != nil {
 		return fmt.Errorf("load taste: %w", err)
 	}
 
-	db, err := store.Open(cfg.Storage.Path)
+	db, err := openStore(cfg)
 	if err != nil {
 		return fmt.Errorf("open store: %w", err)
 	}
@@ -71,154 +126,21 @@ func digestAction(cmd *cobra.Command, _ []string) error {
 			return fmt.Errorf("parse --since: %w", err)
 		}
 	}
-	sinceTime := time.Now().Add(-sinceDur)
 
 	ctx := cmd.Context()
-
-	// Get all posts in window
-	filter := store.PostFilter{Source: digestSource, Channel: digestChannel}
-	posts, err := db.GetPosts(ctx, sinceTime, "", filter)
-	if err != nil {
-		return fmt.Errorf("get posts: %w", err)
-	}
-
-	// Score unscored posts
 	now := time.Now()
-	for i := range posts {
-		if posts[i].Score != nil {
-			continue
-		}
-		sp := taste.Score(storePostToSourcePost(posts[i].Post), profile)
-		explanation, _ := json.Marshal(sp.Explanation)
-
-		storeScore := store.Score{
-			PostID:      posts[i].Post.ID,
-			Score:       sp.Score,
-			Labels:      sp.Labels,
-			Tier:        sp.Tier,
-			ScoredAt:    now,
-			Explanation: explanation,
-		}
-		if err := db.SaveScore(ctx, storeScore); err != nil {
-			return fmt.Errorf("save score: %w", err)
-		}
-
-		posts[i].Score = &storeScore
-	}
 
-	// Build summarizers
-	heuristic := &summarize.HeuristicSummarizer{}
-	var llmSummarizer summarize.Summarizer
-	if cfg.Summarize.Mode == "llm" && cfg.Summarize.LLM.APIKey != "" {
-		maxTokens := cfg.Summarize.LLM.MaxTokensPerPost
-		if maxTokens == 0 {
-			maxTokens = 200
-		}
-		llmSummarizer = summarize.NewLLM(
-			cfg.Summarize.LLM.APIKey,
-			cfg.Summarize.LLM.Model,
-			maxTokens,
-			heuristic,
-		)
-	}
-
-	// Build digest items
-	channels := make(map[string]bool)
-	var items []digest.DigestItem
-
-	for _, pws := range posts {
-		channels[pws.Post.Channel] = true
-
-		text := pws.Post.Text
-		if text == "" {
-			text = pws.Post.Snippet
-		}
-
-		scored := taste.ScoredPost{
-			Post:  storePostToSourcePost(pws.Post),
-			Score: pws.Score.Score,
-			Tier:  pws.Score.Tier,
-		}
-		if pws.Score.Labels != nil {
-			scored.Labels = pws.Score.Labels
-		}
-
-		// Use LLM for read_now posts, heuristic for everything else
-		var summer summarize.Summarizer = heuristic
-		if llmSummarizer != nil && pws.Score.Tier == taste.TierReadNow {
-			summer = llmSummarizer
-		}
-
-		items = append(items, digest.DigestItem{
-			ScoredPost: scored,
-			Summary:    summer.Summarize(text),
-		})
-	}
-
-	// Populate "also in" annotations
-	var postIDs []int64
-	for _, pws := range posts {
-		postIDs = append(postIDs, pws.Post.ID)
-	}
-	alsoInMap, err := db.GetAlsoIn(ctx, postIDs)
+	pipeline, err := newDigestPipeline(ctx, cfg, profile, db)
 	if err != nil {
-		return fmt.Errorf("get also_in: %w", err)
-	}
-	for i, pws := range posts {
-		if channels, ok := alsoInMap[pws.Post.ID]; ok {
-			items[i].AlsoIn = channels
-		}
-	}
-
-	// Apply digest limits (top_n for read_now, include_skims for skim)
-	sort.Slice(items, func(i, j int) bool {
-		return items[i].Score > items[j].Score
-	})
-	var limited []digest.DigestItem
-	readNowCount, skimCount := 0, 0
-	for _, item := range items {
-		switch item.Tier {
-		case taste.TierReadNow:
-			if readNowCount < cfg.Digest.TopN {
-				limited = append(limited, item)
-				readNowCount++
-			}
-		case taste.TierSkim:
-			if skimCount < cfg.Digest.IncludeSkims {
-				limited = append(limited, item)
-				skimCount++
-			}
-		default:
-			limited = append(limited, item)
-		}
-	}
-	items = limited
-
-	// Detect trending topics across channels
-	var scoredPosts []taste.ScoredPost
-	for _, item := range items {
-		scoredPosts = append(scoredPosts, item.ScoredPost)
-	}
-	trending := taste.FindTrending(scoredPosts, profile, 3)
-
-	input := digest.DigestInput{
-		Items:      items,
-		Trending:   trending,
-		Channels:   len(channels),
-		TotalPosts: len(posts),
-		Since:      sinceDur,
+		return err
 	}
-
-	var formatter digest.Formatter
-	switch digestFormat {
-	case "json":
-		formatter = digest.NewJSON()
-	case "markdown", "md":
-		formatter = digest.NewMarkdown()
-	case "terminal", "":
-		formatter = digest.NewTerminal(!noColor)
-	default:
-		return fmt.Errorf("unknown format %q (want terminal, json, or markdown)", digestFormat)
+	built, err := pipeline.build(ctx, digestRequest{
+		Since:   sinceDur,
+		Filter:  store.PostFilter{Source: digestSource, Channel: digestChannel, UnreadOnly: digestUnread, StarredOnly: digestStarred, NewOnly: digestNewOnly},
+		GroupBy: digestGroupBy,
+	}, now)
+	if err != nil {
+		return err
 	}
 
 	// Determine output writer
@@ -238,22 +160,104 @@ func digestAction(cmd *cobra.Command, _ []string) error {
 		w = f
 	}
 
-	if err := formatter.Format(w, input); err != nil {
+	// Hash what is written, so a saved digest records the exact output.
+	hash := sha256.New()
+	if err := renderDigest(io.MultiWriter(w, hash), formatter, built.Input); err != nil {
 		return err
 	}
+	events.Publish(ctx, digestEvent(built.Input))
+	if err := pipeline.record(ctx, built, now, digestMarkRead); err != nil {
+		return err
+	}
+	if digestSave {
+		if _, err := pipeline.save(ctx, built, now, digestFormatName(cfg, digestFormat, digestTemplate), hash.Sum(nil)); err != nil {
+			return err
+		}
+	}
 
-	// Webhook: always POST as JSON regardless of --format
-	if digestWebhook != "" {
-		if err := postWebhook(digestWebhook, input); err != nil {
-			fmt.Fprintf(os.Stderr, "warning: webhook failed: %v\n", err)
+	deliveries := digestDeliveries(cfg, digestWebhook, publishers, emailer, telegram, discord)
+	if dryRun {
+		// Nothing leaves the machine on a dry run.
+		if len(deliveries) > 0 {
+			slog.Info("dry run: skipping post_digest hook, webhook, publishing, email, telegram, and discord")
 		}
+		return nil
 	}
+	deliverDigest(ctx, deliveries, built.Input, now)
+	return nil
+}
+
+// feedChanges collects channels that appeared, went silent, or started
+// failing between prev and now.
+func feedChanges(ctx context.Context, db *store.Store, prev, now time.Time) (digest.FeedChanges, error) {
+	var changes digest.FeedChanges
+
+	newChannels, err := db.GetNewChannels(ctx, prev)
+	if err != nil {
+		return changes, err
+	}
+	for _, ca := range newChannels {
+		changes.NewChannels = append(changes.NewChannels, ca.Source+"/"+ca.Channel)
+	}
+
+	silent, err := db.GetSilentChannels(ctx, prev, now, staleDays*24*time.Hour)
+	if err != nil {
+		return changes, err
+	}
+	for _, ca := range silent {
+		changes.SilentChannels = append(changes.SilentChannels, digest.SilentChannel{
+			Channel:  ca.Source + "/" + ca.Channel,
+			LastPost: ca.LastPost,
+		})
+	}
+
+	failing, err := db.GetFailingFeeds(ctx, prev)
+	if err != nil {
+		return changes, err
+	}
+	for _, fs := range failing {
+		changes.FailingFeeds = append(changes.FailingFeeds, digest.FailingFeed{
+			Feed:  fs.Source + "/" + fs.Feed,
+			Error: fs.LastError,
+			Since: fs.FailingSince,
+		})
+	}
+
+	return changes, nil
+}
 
+// recordDigestUsage counts the digest in the local usage counters: the posts
+// it covered and the posts it listed above ignore, with their words.
+func recordDigestUsage(ctx context.Context, db *store.Store, now time.Time, items []digest.DigestItem, words map[int64]int) error {
+	var totalWords, shown, shownWords int
+	for _, n := range words {
+		totalWords += n
+	}
+	for _, item := range items {
+		if item.Tier != taste.TierIgnore {
+			shown++
+			shownWords += words[item.PostID]
+		}
+	}
+	for _, c := range []struct {
+		counter string
+		n       int
+	}{
+		{store.UsageDigests, 1},
+		{store.UsageDigestPosts, len(words)},
+		{store.UsageDigestShown, shown},
+		{store.UsageDigestWords, totalWords},
+		{store.UsageDigestShownWords, shownWords},
+	} {
+		if err := db.AddUsage(ctx, now, c.counter, int64(c.n)); err != nil {
+			return fmt.Errorf("record usage: %w", err)
+		}
+	}
 	return nil
 }
 
-func postWebhook(url string, input digest.DigestInput) error {
-	jsonFormatter := digest.NewJSON()
+func postWebhook(url string, opts digest.JSONOptions, input digest.DigestInput) error {
+	jsonFormatter := digest.NewJSONWithOptions(opts)
 	var buf bytes.Buffer
 	if err := jsonFormatter.Format(&buf, input); err != nil {
 		return fmt.Errorf("format json: %w", err)
@@ -272,6 +276,136 @@ func postWebhook(url string, input digest.DigestInput) error {
 	return nil
 }
 
+// newLLMSummarizer returns the LLM summarizer used for read_now posts, falling
+// back to fallback on errors and reusing summaries saved in db, which also
+// records token usage against summarize.llm.monthly_budget; or nil when
+// summarize.mode is not llm or the provider needs an API key and none is set.
+func newLLMSummarizer(ctx context.Context, cfg *config.Config, db *store.Store, fallback summarize.Summarizer) (summarize.Summarizer, error) {
+	if cfg.Summarize.Mode != "llm" || (cfg.Summarize.LLM.APIKey == "" && cfg.Summarize.LLM.NeedsAPIKey()) {
+		return nil, nil
+	}
+	maxTokens := cfg.Summarize.LLM.MaxTokensPerPost
+	if maxTokens == 0 {
+		maxTokens = 200
+	}
+	provider, err := summarize.NewProvider(cfg.Summarize.LLM.Provider, cfg.Summarize.LLM.Endpoint, cfg.Summarize.LLM.APIKey)
+	if err != nil {
+		return nil, err
+	}
+	llm := summarize.NewLLM(
+		provider,
+		cfg.Summarize.LLM.Model,
+		maxTokens,
+		fallback,
+	)
+	transport, err := network.NewTransport(cfg.Network)
+	if err != nil {
+		return nil, fmt.Errorf("build http transport: %w", err)
+	}
+	llm.SetTransport(telemetry.NewTransport("llm", transport, func() context.Context { return ctx }))
+	llm.SetTimeout(cfg.Summarize.LLM.Timeout.Duration)
+	if db != nil {
+		llm.SetCache(summaryCache{ctx: ctx, db: db})
+		llm.SetMeter(newLLMMeter(ctx, cfg.Summarize.LLM, db))
+	}
+	return llm, nil
+}
+
+// summaryCache keeps LLM summaries in the store's summaries table. A lookup
+// that fails is a miss, so the summary comes from the API instead.
+type summaryCache struct {
+	ctx context.Context
+	db  *store.Store
+}
+
+func (c summaryCache) Summary(textHash, model string) ([]string, bool) {
+	bullets, err := c.db.GetSummary(c.ctx, textHash, model)
+	if err != nil {
+		if !errors.Is(err, store.ErrNoSummary) {
+			slog.Warn("read cached summary", "err", err)
+		}
+		return nil, false
+	}
+	return bullets, true
+}
+
+func (c summaryCache) SaveSummary(textHash, model string, bullets []string) {
+	if err := c.db.SaveSummary(c.ctx, textHash, model, bullets, time.Now()); err != nil {
+		slog.Warn("cache summary", "err", err)
+	}
+}
+
+// scoreUnscored scores and saves every post in posts that has no score yet
+// (or, with digest.rescore_changed, was edited since scoring), filling in
+// its Score field.
+func scoreUnscored(ctx context.Context, db *store.Store, scorer *postScorer, posts []store.PostWithScore, now time.Time) (err error) {
+	var (
+		unscored []store.Post
+		order    []int // indexes of unscored in posts
+	)
+	for i, p := range posts {
+		if scorer.needsScore(p) {
+			unscored = append(unscored, p.Post)
+			order = append(order, i)
+		}
+	}
+	if len(unscored) == 0 {
+		return nil
+	}
+
+	ctx, span := telemetry.Start(ctx, "score", attribute.Int("noisepan.posts", len(unscored)))
+	defer func() { telemetry.End(span, err) }()
+	scorer.traceCtx = ctx
+	defer func() { scorer.traceCtx = nil }()
+
+	scorer.runPreScore(ctx, unscored)
+	if err := scorer.loadCooldowns(ctx, db); err != nil {
+		return fmt.Errorf("load rule cooldowns: %w", err)
+	}
+	defer func() { scorer.cooldowns = nil }()
+	scorer.meterTriage(ctx, db)
+	if err := scorer.loadRecurring(ctx, db); err != nil {
+		return fmt.Errorf("load recurring texts: %w", err)
+	}
+	defer func() { scorer.repeated = nil }()
+	if err := scorer.loadVectors(ctx, db, unscored); err != nil {
+		return fmt.Errorf("load embeddings: %w", err)
+	}
+	defer func() { scorer.vectors = nil }()
+	if err := scorer.saveTasteSnapshot(ctx, db); err != nil {
+		return err
+	}
+
+	// Oldest first, so a rule's cooldown starts at the first post it boosts.
+	sort.SliceStable(order, func(a, b int) bool {
+		return posts[order[a]].Post.PostedAt.Before(posts[order[b]].Post.PostedAt)
+	})
+
+	for _, i := range order {
+		sp := scorer.scorePost(posts[i].Post)
+		explanation, _ := json.Marshal(sp.Explanation)
+
+		storeScore := store.Score{
+			PostID:      posts[i].Post.ID,
+			Score:       sp.Score,
+			Labels:      sp.Labels,
+			Tier:        sp.Tier,
+			ScoredAt:    now,
+			Explanation: explanation,
+			TasteHash:   scorer.tasteHash,
+		}
+		if err := db.SaveScore(ctx, storeScore); err != nil {
+			return fmt.Errorf("save score: %w", err)
+		}
+
+		posts[i].Score = &storeScore
+		if sp.Tier == taste.TierReadNow {
+			events.Publish(ctx, postEvent(events.PostReadNow, posts[i]))
+		}
+	}
+	return scorer.saveCooldowns(ctx, db)
+}
+
 func storePostToSourcePost(p store.Post) source.Post {
 	text := p.Text
 	if text == "" {
@@ -283,6 +417,8 @@ func storePostToSourcePost(p store.Post) source.Post {
 		ExternalID: p.ExternalID,
 		Text:       text,
 		URL:        p.URL,
+		Author:     p.Author,
+		Language:   p.Language,
 		PostedAt:   p.PostedAt,
 	}
 }
@@ -0,0 +1,597 @@
+package cli
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/digest"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/summarize"
+	"github.com/ppiankov/noisepan/internal/taste"
+)
+
+// summarizeWorkers bounds concurrent summaries, which with summarize.mode
+// llm are API calls.
+const summarizeWorkers = 4
+
+// digestPipeline builds a digest in stages, each usable on its own:
+//
+//	load → score → summarize → render → deliver
+//
+// digest and run use all of them; serve and mcp only load and score, with a
+// pipeline holding just the store and scorer.
+type digestPipeline struct {
+	cfg     *config.Config
+	profile *config.TasteProfile
+	db      *store.Store
+	scorer  *postScorer
+
+	heuristic summarize.Summarizer
+	llm       summarize.Summarizer // nil unless summarize.mode is llm
+}
+
+// digestRequest selects the posts of one digest.
+type digestRequest struct {
+	Since   time.Duration
+	Filter  store.PostFilter
+	GroupBy string // digest.DigestInput.GroupBy
+
+	// NoStillUnread keeps read_now posts of earlier digests in their
+	// sections instead of carrying them as digest.still_unread, for feeds,
+	// which readers already keep unread.
+	NoStillUnread bool
+}
+
+// builtDigest is a summarized digest ready to render and deliver.
+type builtDigest struct {
+	Input digest.DigestInput
+	words map[int64]int // words per covered post, for the usage counters
+}
+
+// newDigestPipeline builds the scorer and summarizers for cfg and profile.
+func newDigestPipeline(ctx context.Context, cfg *config.Config, profile *config.TasteProfile, db *store.Store) (*digestPipeline, error) {
+	scorer, err := newPostScorer(cfg, profile)
+	if err != nil {
+		return nil, err
+	}
+	if err := scorer.loadBoilerplate(ctx, db); err != nil {
+		return nil, fmt.Errorf("load boilerplate: %w", err)
+	}
+
+	heuristic := &summarize.HeuristicSummarizer{Products: cfg.Summarize.Products}
+	llm, err := newLLMSummarizer(ctx, cfg, db, heuristic)
+	if err != nil {
+		return nil, err
+	}
+	return &digestPipeline{cfg: cfg, profile: profile, db: db, scorer: scorer, heuristic: heuristic, llm: llm}, nil
+}
+
+// build runs load, score, and summarize for req.
+func (p *digestPipeline) build(ctx context.Context, req digestRequest, now time.Time) (builtDigest, error) {
+	sinceTime := now.Add(-req.Since)
+	posts, err := p.load(ctx, sinceTime, req.Filter)
+	if err != nil {
+		return builtDigest{}, err
+	}
+	if err := p.score(ctx, posts, now); err != nil {
+		return builtDigest{}, err
+	}
+	var stillUnread []store.PostWithScore
+	if window := p.cfg.Digest.StillUnread.Duration; window > 0 && !req.NoStillUnread && !req.Filter.StarredOnly && !req.Filter.NewOnly {
+		if stillUnread, err = p.db.GetStillUnread(ctx, now.Add(-window), req.Filter); err != nil {
+			return builtDigest{}, err
+		}
+	}
+	b, err := p.summarize(ctx, posts, stillUnread)
+	if err != nil {
+		return builtDigest{}, err
+	}
+	b.Input.Since = req.Since
+	b.Input.GroupBy = req.GroupBy
+	if p.cfg.Digest.ExecutiveSummary {
+		b.Input.Executive = executiveSummary(p.llm, b.Input.Items)
+	}
+
+	if p.cfg.Digest.Changes {
+		prev, err := p.db.LastDigest(ctx)
+		if err != nil {
+			return builtDigest{}, err
+		}
+		if prev.IsZero() {
+			prev = sinceTime
+		}
+		b.Input.Changes, err = feedChanges(ctx, p.db, prev, now)
+		if err != nil {
+			return builtDigest{}, err
+		}
+	}
+	return b, nil
+}
+
+// load returns the posts fetched since since that match filter.
+func (p *digestPipeline) load(ctx context.Context, since time.Time, filter store.PostFilter) ([]store.PostWithScore, error) {
+	posts, err := p.db.GetPosts(ctx, since, "", filter)
+	if err != nil {
+		return nil, fmt.Errorf("get posts: %w", err)
+	}
+	return posts, nil
+}
+
+// score scores the posts that need it, applies the taste profile's age
+// decay as of now, and orders posts by score, highest first. Decayed scores
+// are not saved. The scorer is not safe for concurrent use; serve
+// serializes calls.
+func (p *digestPipeline) score(ctx context.Context, posts []store.PostWithScore, now time.Time) error {
+	if err := scoreUnscored(ctx, p.db, p.scorer, posts, now); err != nil {
+		return err
+	}
+	decayScores(posts, p.scorer.profile, now)
+	sort.SliceStable(posts, func(i, j int) bool {
+		return postScore(posts[i]) > postScore(posts[j])
+	})
+	return nil
+}
+
+// decayScores replaces the score of every post whose score decays by now
+// with a decayed copy.
+func decayScores(posts []store.PostWithScore, profile *config.TasteProfile, now time.Time) {
+	for i, pws := range posts {
+		if pws.Score == nil || pws.Score.Score <= 0 {
+			continue
+		}
+		sp := taste.Decay(scoredPost(pws), profile, now)
+		if sp.Score == pws.Score.Score {
+			continue
+		}
+		decayed := *pws.Score
+		decayed.Score, decayed.Tier = sp.Score, sp.Tier
+		decayed.Explanation, _ = json.Marshal(sp.Explanation)
+		posts[i].Score = &decayed
+	}
+}
+
+func postScore(p store.PostWithScore) int {
+	if p.Score == nil {
+		return 0
+	}
+	return p.Score.Score
+}
+
+// summarize turns scored posts, highest first, into digest items: it applies
+// digest.top_n and digest.include_skims, then summarizes the items kept,
+// read_now with the LLM when configured, concurrently. stillUnread, read_now
+// posts of earlier digests, become one-line items with heuristic summaries
+// and are left out of the other sections.
+func (p *digestPipeline) summarize(ctx context.Context, posts, stillUnread []store.PostWithScore) (builtDigest, error) {
+	channels := make(map[string]bool)
+	words := make(map[int64]int, len(posts))
+	texts := make(map[int64]string, len(posts))
+	for _, pws := range posts {
+		channels[pws.Post.Channel] = true
+		text := p.scorer.stripBoilerplate(pws.Post.Source, pws.Post.Channel, postText(pws.Post))
+		texts[pws.Post.ID] = text
+		words[pws.Post.ID] = len(strings.Fields(text))
+	}
+
+	carried := make(map[int64]bool, len(stillUnread))
+	var unread []digest.DigestItem
+	for _, pws := range stillUnread {
+		if pws.Score == nil {
+			continue
+		}
+		carried[pws.Post.ID] = true
+		item := digestItem(pws)
+		item.Summary = p.heuristic.Summarize(p.scorer.stripBoilerplate(pws.Post.Source, pws.Post.Channel, postText(pws.Post)))
+		unread = append(unread, item)
+	}
+
+	kept := p.limit(slices.DeleteFunc(slices.Clone(posts), func(pws store.PostWithScore) bool {
+		return carried[pws.Post.ID]
+	}))
+
+	items := make([]digest.DigestItem, len(kept))
+	for i, pws := range kept {
+		items[i] = digestItem(pws)
+	}
+	p.summarizeItems(items, texts)
+
+	// Populate "also in" annotations
+	var postIDs []int64
+	for _, item := range items {
+		postIDs = append(postIDs, item.PostID)
+	}
+	alsoInMap, err := p.db.GetAlsoIn(ctx, postIDs)
+	if err != nil {
+		return builtDigest{}, fmt.Errorf("get also_in: %w", err)
+	}
+	for i := range items {
+		items[i].AlsoIn = orderAlsoIn(alsoInMap[items[i].PostID], p.cfg.Digest.AlsoInOrder)
+	}
+
+	// Annotate read_now items with what verify last found
+	var readNowIDs []int64
+	for _, item := range items {
+		if item.Tier == taste.TierReadNow {
+			readNowIDs = append(readNowIDs, item.PostID)
+		}
+	}
+	verifications, err := p.db.GetVerifications(ctx, readNowIDs)
+	if err != nil {
+		return builtDigest{}, fmt.Errorf("get verifications: %w", err)
+	}
+	for i := range items {
+		if v, ok := verifications[items[i].PostID]; ok && items[i].Tier == taste.TierReadNow {
+			items[i].Verification = &digest.Verification{Support: v.Support, Confidence: v.Confidence, Conflict: v.Conflict}
+		}
+	}
+
+	// Detect trending topics across channels
+	var scoredPosts []taste.ScoredPost
+	for _, item := range items {
+		scoredPosts = append(scoredPosts, item.ScoredPost)
+	}
+
+	return builtDigest{
+		Input: digest.DigestInput{
+			Items:       items,
+			Trending:    taste.FindTrending(scoredPosts, p.profile, 3),
+			Channels:    len(channels),
+			TotalPosts:  len(posts),
+			StillUnread: unread,
+			Tiers:       taste.TierNames(p.profile),
+		},
+		words: words,
+	}, nil
+}
+
+// digestItem is the unsummarized digest item of a scored post.
+func digestItem(pws store.PostWithScore) digest.DigestItem {
+	return digest.DigestItem{PostID: pws.Post.ID, ScoredPost: scoredPost(pws), Changed: pws.Changed()}
+}
+
+// scoredPost rebuilds the taste result of a scored post from its stored
+// score.
+func scoredPost(pws store.PostWithScore) taste.ScoredPost {
+	scored := taste.ScoredPost{
+		Post:  storePostToSourcePost(pws.Post),
+		Score: pws.Score.Score,
+		Tier:  pws.Score.Tier,
+	}
+	if pws.Score.Labels != nil {
+		scored.Labels = pws.Score.Labels
+	}
+	if len(pws.Score.Explanation) > 0 {
+		// Only the terminal's explain mode shows it; a bad breakdown just
+		// goes unshown.
+		_ = json.Unmarshal(pws.Score.Explanation, &scored.Explanation)
+	}
+	return scored
+}
+
+// limit keeps the top digest.top_n read_now posts, the top
+// digest.include_skims posts of each tier between read_now and ignore, and
+// every ignored post, in order.
+func (p *digestPipeline) limit(posts []store.PostWithScore) []store.PostWithScore {
+	var kept []store.PostWithScore
+	counts := make(map[string]int)
+	for _, pws := range posts {
+		tier := pws.Score.Tier
+		limit := p.cfg.Digest.IncludeSkims
+		switch tier {
+		case taste.TierReadNow:
+			limit = p.cfg.Digest.TopN
+		case taste.TierIgnore:
+			kept = append(kept, pws)
+			continue
+		}
+		if counts[tier] < limit {
+			kept = append(kept, pws)
+			counts[tier]++
+		}
+	}
+	return kept
+}
+
+// summarizeItems fills in each item's summary from texts, using the LLM for
+// read_now items, and those of tiers with their own prompt, when there is
+// one and the heuristic for everything else.
+func (p *digestPipeline) summarizeItems(items []digest.DigestItem, texts map[int64]string) {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(summarizeWorkers, len(items)) {
+		wg.Go(func() {
+			for i := range jobs {
+				items[i].Summary = summarizeScored(p.llm, p.heuristic, p.cfg.Summarize.LLM.Prompt,
+					items[i].Tier, items[i].Labels, texts[items[i].PostID])
+			}
+		})
+	}
+	for i := range items {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// promptSummarizer is an LLM summarizer taking the system prompt per post.
+type promptSummarizer interface {
+	SummarizeWith(text, prompt string) summarize.Summary
+}
+
+// summarizeScored summarizes the text of a post in tier with labels: with
+// llm, when there is one and the tier is read_now or has its own
+// summarize.llm.prompt, using the prompt for the tier and labels; otherwise
+// with heuristic.
+func summarizeScored(llm, heuristic summarize.Summarizer, prompts config.LLMPrompts, tier string, labels []string, text string) summarize.Summary {
+	if llm == nil || (tier != taste.TierReadNow && prompts.Tiers[tier] == "") {
+		return heuristic.Summarize(text)
+	}
+	if ps, ok := llm.(promptSummarizer); ok {
+		return ps.SummarizeWith(text, prompts.For(tier, labels))
+	}
+	return llm.Summarize(text)
+}
+
+// digestSummarizer is an LLM summarizer that also overviews a whole digest.
+type digestSummarizer interface {
+	SummarizeDigest(digest string) (summarize.DigestSummary, error)
+}
+
+// executiveSummary asks llm for an overview of the listed items, one line
+// each with tier, channel, and summary. It returns nil without an LLM or
+// listed items, and on error, so the digest goes out without one.
+func executiveSummary(llm summarize.Summarizer, items []digest.DigestItem) *summarize.DigestSummary {
+	ds, ok := llm.(digestSummarizer)
+	if !ok {
+		return nil
+	}
+	var b strings.Builder
+	for _, item := range items {
+		if item.Tier == taste.TierIgnore {
+			continue
+		}
+		text := strings.Join(item.Summary.Bullets, "; ")
+		if text == "" {
+			text = headline(item.Post.Text)
+		}
+		fmt.Fprintf(&b, "[%s] %s — %s\n", item.Tier, item.Post.Channel, text)
+	}
+	if b.Len() == 0 {
+		return nil
+	}
+	s, err := ds.SummarizeDigest(b.String())
+	if err != nil {
+		slog.Warn("executive summary", "err", err)
+		return nil
+	}
+	return &s
+}
+
+// orderAlsoIn sorts "source/channel" entries by the rank of their source in
+// order, most preferred first; unlisted sources follow, alphabetically.
+func orderAlsoIn(channels, order []string) []string {
+	rank := func(entry string) int {
+		src, _, _ := strings.Cut(entry, "/")
+		if i := slices.Index(order, src); i >= 0 {
+			return i
+		}
+		return len(order)
+	}
+	sorted := slices.Clone(channels)
+	slices.SortStableFunc(sorted, func(a, b string) int {
+		if ra, rb := rank(a), rank(b); ra != rb {
+			return ra - rb
+		}
+		return strings.Compare(a, b)
+	})
+	return sorted
+}
+
+// record stores what the digest changed: the last digest time, the usage
+// counters, the read_now items shown (for later "Still unread" sections),
+// and with markRead every listed item, ignored ones aside, as read.
+func (p *digestPipeline) record(ctx context.Context, b builtDigest, now time.Time, markRead bool) error {
+	if err := p.db.SetLastDigest(ctx, now); err != nil {
+		return fmt.Errorf("record last digest: %w", err)
+	}
+	if err := recordDigestUsage(ctx, p.db, now, b.Input.Items, b.words); err != nil {
+		return err
+	}
+
+	var readNow []int64
+	for _, item := range b.Input.Items {
+		if item.Tier == taste.TierReadNow {
+			readNow = append(readNow, item.PostID)
+		}
+	}
+	if err := p.db.MarkShown(ctx, now, readNow...); err != nil {
+		return err
+	}
+
+	if markRead {
+		var shown []int64
+		for _, item := range b.Input.Items {
+			if item.Tier != taste.TierIgnore {
+				shown = append(shown, item.PostID)
+			}
+		}
+		for _, item := range b.Input.StillUnread {
+			shown = append(shown, item.PostID)
+		}
+		if err := p.db.MarkRead(ctx, now, shown...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// save keeps the digest for history as rendered with format, whose output
+// hashed to outputHash, and returns its ID. The items it listed above ignore
+// are left out of later --new-only digests.
+func (p *digestPipeline) save(ctx context.Context, b builtDigest, now time.Time, format string, outputHash []byte) (int64, error) {
+	input, err := json.Marshal(b.Input)
+	if err != nil {
+		return 0, fmt.Errorf("encode digest: %w", err)
+	}
+	counts := make(map[string]int)
+	var listed []int64
+	for _, item := range b.Input.Items {
+		counts[item.Tier]++
+		if item.Tier != taste.TierIgnore {
+			listed = append(listed, item.PostID)
+		}
+	}
+	for _, item := range b.Input.StillUnread {
+		listed = append(listed, item.PostID)
+	}
+	id, err := p.db.SaveDigest(ctx, store.DigestSnapshot{
+		CreatedAt:  now,
+		Since:      b.Input.Since,
+		Format:     format,
+		TotalPosts: b.Input.TotalPosts,
+		Counts:     counts,
+		OutputHash: hex.EncodeToString(outputHash),
+		Input:      input,
+		PostIDs:    listed,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// newDigestFormatter returns the formatter of a --format value; opts shape
+// the json format.
+func newDigestFormatter(format string, color bool, opts digest.JSONOptions) (digest.Formatter, error) {
+	switch format {
+	case "json":
+		return digest.NewJSONWithOptions(opts), nil
+	case "markdown", "md":
+		return digest.NewMarkdown(), nil
+	case "print":
+		return digest.NewPrint(), nil
+	case "terminal", "":
+		return digest.NewTerminal(color), nil
+	default:
+		return nil, fmt.Errorf("unknown format %q (want terminal, json, markdown, or print)", format)
+	}
+}
+
+// digestFormatterFor picks the formatter of the printed digest: --format,
+// else the template of --template or digest.template, else the terminal.
+func digestFormatterFor(cfg *config.Config, format, tmpl string, color bool) (digest.Formatter, error) {
+	if format != "" && tmpl != "" {
+		return nil, errors.New("--format and --template are mutually exclusive")
+	}
+	if format == "" && tmpl == "" {
+		tmpl = cfg.Digest.Template
+	}
+	if tmpl == "" {
+		return newDigestFormatter(format, color, jsonOptions(cfg))
+	}
+	f, err := digest.NewTemplate(tmpl)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// digestFormatName names the formatter digestFormatterFor picks: the
+// template path, or the format.
+func digestFormatName(cfg *config.Config, format, tmpl string) string {
+	switch {
+	case tmpl != "":
+		return tmpl
+	case format != "":
+		return format
+	case cfg.Digest.Template != "":
+		return cfg.Digest.Template
+	}
+	return "terminal"
+}
+
+// jsonOptions returns the digest.json settings as formatter options.
+func jsonOptions(cfg *config.Config) digest.JSONOptions {
+	return digest.JSONOptions{
+		EscapeHTML:  cfg.Digest.JSON.EscapeHTML,
+		MaxHeadline: cfg.Digest.JSON.MaxHeadline,
+		MaxBullet:   cfg.Digest.JSON.MaxBullet,
+	}
+}
+
+// renderDigest writes the digest with formatter to w.
+func renderDigest(w io.Writer, formatter digest.Formatter, input digest.DigestInput) error {
+	return formatter.Format(w, input)
+}
+
+// digestDelivery sends a digest somewhere besides the output: a hook, a
+// webhook, or a publisher. New targets only need a name and a deliver func.
+type digestDelivery struct {
+	name    string
+	deliver func(ctx context.Context, input digest.DigestInput, now time.Time) error
+}
+
+// digestDeliveries returns the deliveries configured by config.yaml and the
+// digest flags.
+func digestDeliveries(cfg *config.Config, webhook string, publishers []publishTarget, email *digest.EmailSender, telegram *digest.TelegramSender, discord *digest.DiscordSender) []digestDelivery {
+	var out []digestDelivery
+	opts := jsonOptions(cfg)
+	if script := cfg.Hooks.PostDigest; script != "" {
+		timeout := cfg.Hooks.Timeout.Duration
+		out = append(out, digestDelivery{"post_digest hook", func(ctx context.Context, input digest.DigestInput, _ time.Time) error {
+			return runPostDigest(ctx, script, timeout, opts, input)
+		}})
+	}
+	if webhook != "" {
+		// Always POSTed as JSON regardless of --format.
+		out = append(out, digestDelivery{"webhook", func(_ context.Context, input digest.DigestInput, _ time.Time) error {
+			return postWebhook(webhook, opts, input)
+		}})
+	}
+	for _, t := range publishers {
+		out = append(out, digestDelivery{"publish " + t.name, func(ctx context.Context, input digest.DigestInput, now time.Time) error {
+			return publishDigest(ctx, t, input, now)
+		}})
+	}
+	if email != nil {
+		out = append(out, digestDelivery{"email", func(ctx context.Context, input digest.DigestInput, now time.Time) error {
+			return emailDigest(ctx, email, input, now)
+		}})
+	}
+	if telegram != nil {
+		out = append(out, digestDelivery{"telegram", func(ctx context.Context, input digest.DigestInput, now time.Time) error {
+			return telegramDigest(ctx, telegram, input, now)
+		}})
+	}
+	if discord != nil {
+		out = append(out, digestDelivery{"discord", func(ctx context.Context, input digest.DigestInput, now time.Time) error {
+			return discordDigest(ctx, discord, input, now)
+		}})
+	}
+	return out
+}
+
+// deliverDigest runs every delivery at once and waits for them. Failures are
+// warnings: the digest has already been printed.
+func deliverDigest(ctx context.Context, deliveries []digestDelivery, input digest.DigestInput, now time.Time) {
+	var wg sync.WaitGroup
+	for _, d := range deliveries {
+		wg.Go(func() {
+			if err := d.deliver(ctx, input, now); err != nil {
+				slog.Warn("digest delivery failed", "target", d.name, "err", err)
+			}
+		})
+	}
+	wg.Wait()
+}
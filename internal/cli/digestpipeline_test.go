@@ -0,0 +1,549 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/digest"
+	"github.com/ppiankov/noisepan/internal/source"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/summarize"
+	"github.com/ppiankov/noisepan/internal/taste"
+)
+
+// recordingSummarizer records the texts it summarized.
+type recordingSummarizer struct {
+	name  string
+	mu    sync.Mutex
+	texts []string
+}
+
+func (r *recordingSummarizer) Summarize(text string) summarize.Summary {
+	r.mu.Lock()
+	r.texts = append(r.texts, text)
+	r.mu.Unlock()
+	return summarize.Summary{Bullets: []string{r.name + ": " + text}}
+}
+
+func TestDigestPipeline_Build(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "noisepan.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, p := range []struct{ id, text string }{
+		{"hot", "cve OpenSSL exploited"},
+		{"warm", "cve OpenSSL patched"},
+		{"meh", "cve in a changelog"},
+		{"dull", "office hours moved"},
+	} {
+		if _, err := st.InsertPost(ctx, store.PostInput{
+			Source: "rss", Channel: "security", ExternalID: p.id,
+			Text: p.text, PostedAt: now, FetchedAt: now,
+		}); err != nil {
+			t.Fatalf("insert %s: %v", p.id, err)
+		}
+	}
+
+	profile := testScorerProfile()
+	profile.Weights.HighSignal["exploited"] = 3
+	profile.Weights.HighSignal["openssl"] = 2
+	cfg := &config.Config{Digest: config.DigestConfig{TopN: 1, IncludeSkims: 5}}
+	heuristic := &recordingSummarizer{name: "heuristic"}
+	llm := &recordingSummarizer{name: "llm"}
+	p := &digestPipeline{
+		cfg: cfg, profile: profile, db: st, scorer: &postScorer{profile: profile},
+		heuristic: heuristic, llm: llm,
+	}
+
+	built, err := p.build(ctx, digestRequest{Since: time.Hour}, now)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	in := built.Input
+	if in.TotalPosts != 4 || in.Channels != 1 || in.Since != time.Hour {
+		t.Errorf("input totals = %d posts, %d channels, since %v", in.TotalPosts, in.Channels, in.Since)
+	}
+
+	var tiers []string
+	for _, item := range in.Items {
+		tiers = append(tiers, item.Tier)
+		if len(item.Summary.Bullets) == 0 {
+			t.Errorf("item %d has no summary", item.PostID)
+		}
+	}
+	// top_n 1 drops the second read_now post; items stay highest first.
+	if !slices.IsSortedFunc(in.Items, func(a, b digest.DigestItem) int { return b.Score - a.Score }) {
+		t.Errorf("items not ordered by score: %+v", in.Items)
+	}
+	if n := slices.Index(tiers, taste.TierReadNow); n != 0 || slices.Index(tiers[1:], taste.TierReadNow) >= 0 {
+		t.Errorf("tiers = %v, want a single read_now first", tiers)
+	}
+	if len(llm.texts) != 1 || llm.texts[0] != "cve OpenSSL exploited" {
+		t.Errorf("llm summarized %v, want only the kept read_now post", llm.texts)
+	}
+	if len(heuristic.texts) != len(in.Items)-1 {
+		t.Errorf("heuristic summarized %d posts, want %d", len(heuristic.texts), len(in.Items)-1)
+	}
+	if built.words[in.Items[0].PostID] != 3 || len(built.words) != 4 {
+		t.Errorf("words = %v", built.words)
+	}
+}
+
+func TestDigestPipeline_Decay(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "noisepan.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, p := range []struct {
+		id  string
+		age time.Duration
+	}{{"stale", 72 * time.Hour}, {"fresh", time.Hour}} {
+		if _, err := st.InsertPost(ctx, store.PostInput{
+			Source: "rss", Channel: "security", ExternalID: p.id,
+			Text: "cve in " + p.id + " news", PostedAt: now.Add(-p.age), FetchedAt: now,
+		}); err != nil {
+			t.Fatalf("insert %s: %v", p.id, err)
+		}
+	}
+
+	profile := testScorerProfile()
+	profile.Decay.HalfLife = config.Duration{Duration: 24 * time.Hour}
+	p := &digestPipeline{
+		cfg: &config.Config{}, profile: profile, db: st, scorer: &postScorer{profile: profile},
+		heuristic: &recordingSummarizer{name: "heuristic"},
+	}
+	posts, err := p.load(ctx, now.Add(-96*time.Hour), store.PostFilter{})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if err := p.score(ctx, posts, now); err != nil {
+		t.Fatalf("score: %v", err)
+	}
+	if len(posts) != 2 || posts[0].Post.ExternalID != "fresh" || posts[0].Score.Tier != taste.TierSkim {
+		t.Fatalf("posts = %+v, want the fresh skim post first", posts)
+	}
+	if stale := posts[1].Score; stale.Score != 1 || stale.Tier != taste.TierIgnore {
+		t.Errorf("stale score %d tier %s, want 1 ignore", stale.Score, stale.Tier)
+	}
+
+	// The stored score keeps the undecayed value.
+	stored, err := st.GetPosts(ctx, now.Add(-96*time.Hour), "")
+	if err != nil {
+		t.Fatalf("get posts: %v", err)
+	}
+	for _, pws := range stored {
+		if pws.Score == nil || pws.Score.Score != 5 {
+			t.Errorf("stored score of %s = %+v, want 5", pws.Post.ExternalID, pws.Score)
+		}
+	}
+}
+
+func TestDigestPipeline_StillUnread(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "noisepan.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, id := range []string{"old", "unread"} {
+		if _, err := st.InsertPost(ctx, store.PostInput{
+			Source: "rss", Channel: "security", ExternalID: id,
+			Text: "cve OpenSSL exploited in " + id, PostedAt: now, FetchedAt: now,
+		}); err != nil {
+			t.Fatalf("insert %s: %v", id, err)
+		}
+	}
+
+	profile := testScorerProfile()
+	profile.Weights.HighSignal["exploited"] = 3
+	cfg := &config.Config{Digest: config.DigestConfig{
+		TopN: 5, IncludeSkims: 5, StillUnread: config.Duration{Duration: 7 * 24 * time.Hour},
+	}}
+	p := &digestPipeline{
+		cfg: cfg, profile: profile, db: st, scorer: &postScorer{profile: profile},
+		heuristic: &recordingSummarizer{name: "heuristic"}, llm: &recordingSummarizer{name: "llm"},
+	}
+	req := digestRequest{Since: time.Hour}
+
+	first, err := p.build(ctx, req, now)
+	if err != nil {
+		t.Fatalf("first build: %v", err)
+	}
+	if len(first.Input.Items) != 2 || len(first.Input.StillUnread) != 0 {
+		t.Fatalf("first digest = %d items, %d still unread", len(first.Input.Items), len(first.Input.StillUnread))
+	}
+	if err := p.record(ctx, first, now, false); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	var readID int64
+	for _, item := range first.Input.Items {
+		if item.Post.ExternalID == "old" {
+			readID = item.PostID
+		}
+	}
+	if err := st.MarkRead(ctx, now, readID); err != nil {
+		t.Fatalf("mark read: %v", err)
+	}
+
+	llm := &recordingSummarizer{name: "llm"}
+	p.llm = llm
+	second, err := p.build(ctx, req, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("second build: %v", err)
+	}
+	in := second.Input
+	if len(in.StillUnread) != 1 || in.StillUnread[0].Post.ExternalID != "unread" {
+		t.Fatalf("still unread = %+v, want the unread post", in.StillUnread)
+	}
+	if len(in.StillUnread[0].Summary.Bullets) == 0 {
+		t.Error("still unread item has no headline")
+	}
+	for _, item := range in.Items {
+		if item.Post.ExternalID == "unread" {
+			t.Error("still unread post repeated in full")
+		}
+	}
+	if len(llm.texts) != 1 || in.TotalPosts != 2 {
+		t.Errorf("llm summarized %v of %d posts, want only the read post again", llm.texts, in.TotalPosts)
+	}
+
+	// Feeds keep the post where it was.
+	feed, err := p.build(ctx, digestRequest{Since: time.Hour, NoStillUnread: true}, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("feed build: %v", err)
+	}
+	if len(feed.Input.StillUnread) != 0 || len(feed.Input.Items) != 2 {
+		t.Errorf("feed = %d items, %d still unread; want 2 items", len(feed.Input.Items), len(feed.Input.StillUnread))
+	}
+
+	// Off by default.
+	p.cfg = &config.Config{Digest: config.DigestConfig{TopN: 5, IncludeSkims: 5}}
+	off, err := p.build(ctx, req, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("build without still_unread: %v", err)
+	}
+	if len(off.Input.StillUnread) != 0 || len(off.Input.Items) != 2 {
+		t.Errorf("without still_unread = %d items, %d still unread", len(off.Input.Items), len(off.Input.StillUnread))
+	}
+}
+
+func TestDigestPipeline_Verification(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "noisepan.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+	ctx := context.Background()
+	now := time.Now()
+
+	// Both posts were verified, but only read_now items show it.
+	for _, text := range []string{"cve OpenSSL exploited", "cve in a changelog"} {
+		post, err := st.InsertPost(ctx, store.PostInput{
+			Source: "rss", Channel: "security", ExternalID: text,
+			Text: text, PostedAt: now, FetchedAt: now,
+		})
+		if err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+		if err := st.SaveVerification(ctx, store.Verification{
+			PostID: post.ID, Support: 40, Confidence: "low", Conflict: true, CheckedAt: now,
+		}); err != nil {
+			t.Fatalf("save verification: %v", err)
+		}
+	}
+
+	profile := testScorerProfile()
+	profile.Weights.HighSignal["exploited"] = 3
+	p := &digestPipeline{
+		cfg: &config.Config{Digest: config.DigestConfig{TopN: 5, IncludeSkims: 5}}, profile: profile, db: st,
+		scorer: &postScorer{profile: profile}, heuristic: &recordingSummarizer{name: "heuristic"},
+	}
+	built, err := p.build(ctx, digestRequest{Since: time.Hour}, now)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	tiers := map[string]int{}
+	for _, item := range built.Input.Items {
+		tiers[item.Tier]++
+		switch v := item.Verification; {
+		case item.Tier == taste.TierReadNow && (v == nil || v.Support != 40 || !v.Conflict):
+			t.Errorf("read_now verification = %+v", v)
+		case item.Tier != taste.TierReadNow && v != nil:
+			t.Errorf("%s item has verification %+v", item.Tier, v)
+		}
+	}
+	if tiers[taste.TierReadNow] != 1 || len(built.Input.Items) != 2 {
+		t.Errorf("tiers = %v, want one read_now of two items", tiers)
+	}
+}
+
+func TestNewLLMSummarizer_Cache(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"- Local summary"}}]}`))
+	}))
+	defer srv.Close()
+
+	st, err := store.Open(filepath.Join(t.TempDir(), "noisepan.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+	ctx := context.Background()
+
+	cfg := &config.Config{Summarize: config.SummarizeConfig{Mode: "llm", LLM: config.LLMConfig{
+		Provider: "ollama", Endpoint: srv.URL, Model: "llama3.2", Timeout: config.Duration{Duration: time.Second},
+	}}}
+	// Two runs summarizing the same read_now post call the API once.
+	for run := range 2 {
+		llm, err := newLLMSummarizer(ctx, cfg, st, &summarize.HeuristicSummarizer{})
+		if err != nil || llm == nil {
+			t.Fatalf("run %d: summarizer = %v, %v", run, llm, err)
+		}
+		if got := llm.Summarize("OpenSSL 3.5 fixes a remote crash."); !slices.Equal(got.Bullets, []string{"Local summary"}) {
+			t.Errorf("run %d: bullets = %q", run, got.Bullets)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("api calls = %d, want 1", calls)
+	}
+}
+
+// promptRecorder is an LLM summarizer recording the prompt of each summary.
+type promptRecorder struct{ prompts []string }
+
+func (r *promptRecorder) Summarize(text string) summarize.Summary { return r.SummarizeWith(text, "") }
+
+func (r *promptRecorder) SummarizeWith(_, prompt string) summarize.Summary {
+	r.prompts = append(r.prompts, prompt)
+	return summarize.Summary{Bullets: []string{"llm"}}
+}
+
+func TestSummarizeScored(t *testing.T) {
+	prompts := config.LLMPrompts{
+		Tiers:  map[string]string{"skim": "One bullet."},
+		Labels: map[string]string{"security": "Extract affected versions and mitigations."},
+	}
+	heuristic := &recordingSummarizer{name: "heuristic"}
+	llm := &promptRecorder{}
+
+	for _, tc := range []struct {
+		tier   string
+		labels []string
+		want   string // first bullet
+	}{
+		{taste.TierReadNow, nil, "llm"},
+		{taste.TierReadNow, []string{"security"}, "llm"},
+		{taste.TierSkim, nil, "llm"},
+		{taste.TierIgnore, []string{"security"}, "heuristic: text"},
+	} {
+		got := summarizeScored(llm, heuristic, prompts, tc.tier, tc.labels, "text")
+		if len(got.Bullets) == 0 || got.Bullets[0] != tc.want {
+			t.Errorf("%s %v: bullets = %q, want %q", tc.tier, tc.labels, got.Bullets, tc.want)
+		}
+	}
+	want := []string{"", "Extract affected versions and mitigations.", "One bullet."}
+	if !slices.Equal(llm.prompts, want) {
+		t.Errorf("prompts = %q, want %q", llm.prompts, want)
+	}
+
+	if got := summarizeScored(nil, heuristic, prompts, taste.TierReadNow, nil, "text"); got.Bullets[0] != "heuristic: text" {
+		t.Errorf("without llm: bullets = %q", got.Bullets)
+	}
+}
+
+// digestRecorder is an LLM summarizer recording the digest it overviewed.
+type digestRecorder struct {
+	promptRecorder
+	digest string
+	err    error
+}
+
+func (r *digestRecorder) SummarizeDigest(digest string) (summarize.DigestSummary, error) {
+	r.digest = digest
+	return summarize.DigestSummary{Overview: "overview"}, r.err
+}
+
+func TestExecutiveSummary(t *testing.T) {
+	item := func(tier, channel string, bullets ...string) digest.DigestItem {
+		return digest.DigestItem{
+			ScoredPost: taste.ScoredPost{Post: source.Post{Channel: channel, Text: "Headline\nbody"}, Tier: tier},
+			Summary:    summarize.Summary{Bullets: bullets},
+		}
+	}
+	items := []digest.DigestItem{
+		item(taste.TierReadNow, "@security", "OpenSSL fix", "Patch now"),
+		item(taste.TierSkim, "blog"),
+		item(taste.TierIgnore, "spam", "ad"),
+	}
+
+	llm := &digestRecorder{}
+	got := executiveSummary(llm, items)
+	if got == nil || got.Overview != "overview" {
+		t.Fatalf("summary = %+v", got)
+	}
+	if want := "[read_now] @security — OpenSSL fix; Patch now\n[skim] blog — Headline\n"; llm.digest != want {
+		t.Errorf("digest = %q, want %q", llm.digest, want)
+	}
+
+	if got := executiveSummary(&digestRecorder{err: errors.New("boom")}, items); got != nil {
+		t.Errorf("on error: summary = %+v, want nil", got)
+	}
+	if got := executiveSummary(&digestRecorder{}, items[2:]); got != nil {
+		t.Errorf("only ignored items: summary = %+v, want nil", got)
+	}
+	if got := executiveSummary(nil, items); got != nil {
+		t.Errorf("without llm: summary = %+v, want nil", got)
+	}
+}
+
+func TestDigestPipeline_Limit(t *testing.T) {
+	p := &digestPipeline{cfg: &config.Config{Digest: config.DigestConfig{TopN: 1, IncludeSkims: 1}}}
+	post := func(id int64, tier string) store.PostWithScore {
+		return store.PostWithScore{Post: store.Post{ID: id}, Score: &store.Score{Tier: tier}}
+	}
+	kept := p.limit([]store.PostWithScore{
+		post(1, taste.TierReadNow), post(2, taste.TierReadNow),
+		post(3, taste.TierSkim), post(4, taste.TierSkim),
+		post(5, taste.TierIgnore), post(6, taste.TierIgnore),
+	})
+	var ids []int64
+	for _, k := range kept {
+		ids = append(ids, k.Post.ID)
+	}
+	if want := []int64{1, 3, 5, 6}; !slices.Equal(ids, want) {
+		t.Errorf("kept = %v, want %v", ids, want)
+	}
+
+	// include_skims applies to each tier between read_now and ignore.
+	kept = p.limit([]store.PostWithScore{
+		post(1, "today"), post(2, "today"), post(3, "weekend"), post(4, "weekend"), post(5, taste.TierIgnore),
+	})
+	ids = ids[:0]
+	for _, k := range kept {
+		ids = append(ids, k.Post.ID)
+	}
+	if want := []int64{1, 3, 5}; !slices.Equal(ids, want) {
+		t.Errorf("kept with custom tiers = %v, want %v", ids, want)
+	}
+}
+
+func TestDigestDeliveries(t *testing.T) {
+	cfg := &config.Config{Hooks: config.HooksConfig{PostDigest: "/bin/true"}}
+	publishers := []publishTarget{{name: "notion"}, {name: "confluence"}}
+
+	var names []string
+	email := digest.NewEmail("smtp.example.com", 587, "", "", "me@example.com", []string{"me@example.com"})
+	telegram := digest.NewTelegram("token", "@me")
+	discord := digest.NewDiscord("https://discord.test/api/webhooks/1/x")
+	for _, d := range digestDeliveries(cfg, "https://hooks.test/digest", publishers, email, telegram, discord) {
+		names = append(names, d.name)
+	}
+	want := []string{"post_digest hook", "webhook", "publish notion", "publish confluence", "email", "telegram", "discord"}
+	if !slices.Equal(names, want) {
+		t.Errorf("deliveries = %v, want %v", names, want)
+	}
+
+	if got := digestDeliveries(&config.Config{}, "", nil, nil, nil, nil); len(got) != 0 {
+		t.Errorf("deliveries with nothing configured = %d, want 0", len(got))
+	}
+}
+
+func TestDeliverDigest_RunsAllDespiteFailures(t *testing.T) {
+	var mu sync.Mutex
+	var ran []string
+	deliver := func(name string, err error) digestDelivery {
+		return digestDelivery{name, func(context.Context, digest.DigestInput, time.Time) error {
+			mu.Lock()
+			ran = append(ran, name)
+			mu.Unlock()
+			return err
+		}}
+	}
+
+	deliverDigest(context.Background(), []digestDelivery{
+		deliver("a", errors.New("down")),
+		deliver("b", nil),
+		deliver("c", nil),
+	}, digest.DigestInput{}, time.Now())
+
+	slices.Sort(ran)
+	if want := []string{"a", "b", "c"}; !slices.Equal(ran, want) {
+		t.Errorf("ran = %v, want %v", ran, want)
+	}
+}
+
+func TestNewDigestFormatter(t *testing.T) {
+	for _, format := range []string{"", "terminal", "json", "markdown", "md", "print"} {
+		if _, err := newDigestFormatter(format, false, digest.JSONOptions{}); err != nil {
+			t.Errorf("format %q: %v", format, err)
+		}
+	}
+	if _, err := newDigestFormatter("html", false, digest.JSONOptions{}); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestDigestFormatterFor(t *testing.T) {
+	tmpl := filepath.Join(t.TempDir(), "digest.tmpl")
+	if err := os.WriteFile(tmpl, []byte("{{len .ReadNow}} read now\n"), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	cfg := &config.Config{Digest: config.DigestConfig{Template: tmpl}}
+
+	// digest.template applies when no flag picks the output.
+	f, err := digestFormatterFor(cfg, "", "", false)
+	if err != nil {
+		t.Fatalf("config template: %v", err)
+	}
+	if _, ok := f.(*digest.TemplateFormatter); !ok {
+		t.Errorf("formatter = %T, want the template", f)
+	}
+	if f, err = digestFormatterFor(cfg, "json", "", false); err != nil {
+		t.Fatalf("format over config template: %v", err)
+	}
+	if _, ok := f.(*digest.JSONFormatter); !ok {
+		t.Errorf("formatter = %T, want json", f)
+	}
+	if _, err := digestFormatterFor(cfg, "json", tmpl, false); err == nil {
+		t.Error("expected error for --format with --template")
+	}
+	if _, err := digestFormatterFor(&config.Config{}, "", filepath.Join(t.TempDir(), "missing.tmpl"), false); err == nil {
+		t.Error("expected error for a missing template")
+	}
+}
+
+func TestOrderAlsoIn(t *testing.T) {
+	channels := []string{"hn/frontpage", "reddit/kubernetes", "rss/lwn", "telegram/devops", "rss/hnrss"}
+	got := orderAlsoIn(channels, []string{"rss", "telegram"})
+	want := []string{"rss/hnrss", "rss/lwn", "telegram/devops", "hn/frontpage", "reddit/kubernetes"}
+	if !slices.Equal(got, want) {
+		t.Errorf("orderAlsoIn = %v, want %v", got, want)
+	}
+	if channels[0] != "hn/frontpage" {
+		t.Error("orderAlsoIn modified its input")
+	}
+	if got := orderAlsoIn(nil, []string{"rss"}); len(got) != 0 {
+		t.Errorf("orderAlsoIn(nil) = %v", got)
+	}
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/digest"
+	"github.com/ppiankov/noisepan/internal/network"
+)
+
+// newDiscordSender returns the sender for "digest --discord", or nil
+// without it.
+func newDiscordSender(cfg *config.Config, enabled bool) (*digest.DiscordSender, error) {
+	if !enabled {
+		return nil, nil
+	}
+	if cfg.Delivery.Discord.Webhook == "" {
+		return nil, errors.New("--discord: delivery.discord needs a webhook")
+	}
+	transport, err := network.NewTransport(cfg.Network)
+	if err != nil {
+		return nil, fmt.Errorf("build http transport: %w", err)
+	}
+	s := digest.NewDiscord(cfg.Delivery.Discord.Webhook)
+	s.SetTransport(transport)
+	return s, nil
+}
+
+// discordDigest posts input to the channel under the digest's title.
+func discordDigest(ctx context.Context, sender *digest.DiscordSender, input digest.DigestInput, now time.Time) error {
+	if err := sender.Send(ctx, digestTitle(now), input); err != nil {
+		return err
+	}
+	slog.Info("posted digest to discord")
+	return nil
+}
@@ -0,0 +1,22 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ppiankov/noisepan/internal/config"
+)
+
+func TestNewDiscordSender(t *testing.T) {
+	if s, err := newDiscordSender(&config.Config{}, false); s != nil || err != nil {
+		t.Errorf("without --discord = %v, %v; want nil, nil", s, err)
+	}
+	if _, err := newDiscordSender(&config.Config{}, true); err == nil || !strings.Contains(err.Error(), "delivery.discord") {
+		t.Errorf("err = %v, want delivery.discord error", err)
+	}
+
+	cfg := &config.Config{Delivery: config.DeliveryConfig{Discord: config.DiscordConfig{Webhook: "https://discord.com/api/webhooks/1/x"}}}
+	if s, err := newDiscordSender(cfg, true); s == nil || err != nil {
+		t.Errorf("configured = %v, %v; want a sender", s, err)
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/source"
 	"github.com/ppiankov/noisepan/internal/store"
 	"github.com/spf13/cobra"
 )
@@ -43,12 +45,15 @@ func doctorAction(_ *cobra.Command, _ []string) error {
 		if cfg.Sources.ForgePlan.Script != "" {
 			extras += ", forgeplan"
 		}
+		if n := len(cfg.Sources.Archive.Newsletters); n > 0 {
+			extras += fmt.Sprintf(", %d newsletter archives", n)
+		}
 		printCheck(true, "config.yaml (%d telegram channels, %d rss feeds, %d subreddits%s)",
 			len(cfg.Sources.Telegram.Channels), len(cfg.Sources.RSS.Feeds), len(cfg.Sources.Reddit.Subreddits), extras)
 	}
 
 	// Taste profile
-	tastePath := filepath.Join(configDir, config.DefaultTasteFile)
+	tastePath := profileTastePath(cfg)
 	if _, err := config.LoadTaste(tastePath); err != nil {
 		printCheck(false, "taste.yaml: %v", err)
 		ok = false
@@ -59,26 +64,30 @@ func doctorAction(_ *cobra.Command, _ []string) error {
 	// Database
 	var db *store.Store
 	if cfg != nil {
-		db, err = store.Open(cfg.Storage.Path)
+		db, err = openStore(cfg)
 		if err != nil {
 			printCheck(false, "database: %v", err)
 			ok = false
 		} else {
 			defer func() { _ = db.Close() }()
-			printCheck(true, "database %s", cfg.Storage.Path)
+			printCheck(true, "database %s", storageLabel(cfg.Storage))
 		}
 	}
 
 	// Python
-	if _, err := exec.LookPath("python3"); err != nil {
-		printCheck(false, "python3 not found")
+	python, pythonArgs := source.PythonCommand()
+	if cfg != nil && cfg.Sources.Telegram.PythonPath != "" {
+		python, pythonArgs = cfg.Sources.Telegram.PythonPath, nil
+	}
+	if _, err := exec.LookPath(python); err != nil {
+		printCheck(false, "%s not found", python)
 		ok = false
 	} else {
-		printCheck(true, "python3")
+		printCheck(true, "%s", strings.Join(append([]string{python}, pythonArgs...), " "))
 	}
 
 	// Telethon
-	cmd := exec.Command("python3", "-c", "import telethon")
+	cmd := exec.Command(python, append(pythonArgs, "-c", "import telethon")...)
 	if err := cmd.Run(); err != nil {
 		printCheck(false, "telethon not installed (pip install telethon)")
 		ok = false
@@ -94,6 +103,9 @@ func doctorAction(_ *cobra.Command, _ []string) error {
 		} else if info.IsDir() {
 			printCheck(false, "forge-plan script: %s is a directory", cfg.Sources.ForgePlan.Script)
 			ok = false
+		} else if _, _, err := source.ScriptCommand(cfg.Sources.ForgePlan.Script); err != nil {
+			printCheck(false, "forge-plan script: %v", err)
+			ok = false
 		} else {
 			printCheck(true, "forge-plan script %s", cfg.Sources.ForgePlan.Script)
 		}
@@ -178,3 +190,12 @@ func printCheck(pass bool, format string, args ...any) {
 func printInfo(format string, args ...any) {
 	fmt.Printf("[INFO] %s\n", fmt.Sprintf(format, args...))
 }
+
+// storageLabel names the database without echoing a postgres DSN, which may
+// carry a password.
+func storageLabel(s config.StorageConfig) string {
+	if s.Driver == "postgres" {
+		return "postgres"
+	}
+	return s.Path
+}
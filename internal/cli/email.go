@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/digest"
+	"github.com/ppiankov/noisepan/internal/network"
+)
+
+// newEmailSender returns the sender for "digest --email", or nil without it.
+func newEmailSender(cfg *config.Config, enabled bool) (*digest.EmailSender, error) {
+	if !enabled {
+		return nil, nil
+	}
+	ec := cfg.Delivery.Email
+	if ec.Host == "" {
+		return nil, errors.New("--email: delivery.email needs host, from, and to")
+	}
+	s := digest.NewEmail(ec.Host, ec.Port, ec.User, ec.Password, ec.From, ec.To)
+	s.SetDarkMode(ec.DarkMode)
+	if ec.Favicons {
+		transport, err := network.NewTransport(cfg.Network)
+		if err != nil {
+			return nil, fmt.Errorf("build http transport: %w", err)
+		}
+		s.SetTransport(transport)
+		s.SetFavicons(true)
+	}
+	return s, nil
+}
+
+// emailDigest mails input with the digest's title as the subject.
+func emailDigest(ctx context.Context, sender *digest.EmailSender, input digest.DigestInput, now time.Time) error {
+	if err := sender.Send(ctx, digestTitle(now), input, now); err != nil {
+		return err
+	}
+	slog.Info("emailed digest")
+	return nil
+}
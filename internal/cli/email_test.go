@@ -0,0 +1,24 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ppiankov/noisepan/internal/config"
+)
+
+func TestNewEmailSender(t *testing.T) {
+	if s, err := newEmailSender(&config.Config{}, false); s != nil || err != nil {
+		t.Errorf("without --email = %v, %v; want nil, nil", s, err)
+	}
+	if _, err := newEmailSender(&config.Config{}, true); err == nil || !strings.Contains(err.Error(), "delivery.email") {
+		t.Errorf("err = %v, want delivery.email error", err)
+	}
+
+	cfg := &config.Config{Delivery: config.DeliveryConfig{Email: config.EmailConfig{
+		Host: "smtp.example.com", Port: 587, From: "me@example.com", To: []string{"me@example.com"},
+	}}}
+	if s, err := newEmailSender(cfg, true); s == nil || err != nil {
+		t.Errorf("configured = %v, %v; want a sender", s, err)
+	}
+}
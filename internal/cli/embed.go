@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/cache"
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/embed"
+	"github.com/ppiankov/noisepan/internal/network"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/taste"
+	"github.com/ppiankov/noisepan/internal/telemetry"
+)
+
+const (
+	// embedMaxPerPull caps the posts one pull embeds, newest first, so
+	// turning embeddings on over a full store backfills across pulls.
+	embedMaxPerPull = 500
+	// embedMaxRunes caps the text sent per post; the headline and first
+	// paragraphs carry the meaning, and models cut long input anyway.
+	embedMaxRunes = 2000
+)
+
+// newEmbedder returns the embedder configured under embed, or nil when
+// embeddings are off.
+func newEmbedder(ctx context.Context, cfg *config.Config) (embed.Embedder, error) {
+	return newTracedEmbedder(cfg, func() context.Context { return ctx })
+}
+
+// newTracedEmbedder is newEmbedder with requests traced under the context
+// parent returns at the time of each request.
+func newTracedEmbedder(cfg *config.Config, parent func() context.Context) (embed.Embedder, error) {
+	e := cfg.Embed
+	if e.Provider == "" {
+		return nil, nil
+	}
+	if e.Provider == "openai" && e.APIKey == "" {
+		return nil, errors.New("embed: the openai provider needs an API key (set embed.api_key_env)")
+	}
+	transport, err := network.NewTransport(cfg.Network)
+	if err != nil {
+		return nil, fmt.Errorf("build http transport: %w", err)
+	}
+	c := embed.New(e.Endpoint, e.APIKey, e.Model)
+	c.SetTransport(telemetry.NewTransport("embed", transport, parent))
+	return c, nil
+}
+
+// requireEmbedder is newEmbedder for commands that cannot work without one.
+func requireEmbedder(ctx context.Context, cfg *config.Config) (embed.Embedder, error) {
+	e, err := newEmbedder(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if e == nil {
+		return nil, errors.New("embeddings are off; set embed.provider in config.yaml")
+	}
+	return e, nil
+}
+
+// embedNewPosts embeds up to embedMaxPerPull posts that have no embedding
+// from e's model, batch texts per request, and returns how many it stored.
+func embedNewPosts(ctx context.Context, db *store.Store, e embed.Embedder, batch int) (int, error) {
+	posts, err := db.PostsWithoutEmbedding(ctx, e.Model(), time.Time{}, embedMaxPerPull)
+	if err != nil {
+		return 0, err
+	}
+	if batch <= 0 {
+		batch = len(posts)
+	}
+	stored := 0
+	for start := 0; start < len(posts); start += batch {
+		chunk := posts[start:min(start+batch, len(posts))]
+		vectors, err := embedPosts(ctx, e, chunk)
+		if err != nil {
+			return stored, err
+		}
+		if err := db.SaveEmbeddings(ctx, e.Model(), vectors, time.Now()); err != nil {
+			return stored, err
+		}
+		stored += len(vectors)
+	}
+	return stored, nil
+}
+
+// embedInterestTTL is how long an interest's embedding is cached. A
+// description embeds the same way every time, so only a model update
+// would change it.
+const embedInterestTTL = 90 * 24 * time.Hour
+
+// embedInterests embeds the taste profile's interests, reusing embeddings
+// cached by earlier runs.
+func embedInterests(ctx context.Context, e embed.Embedder, c *cache.Cache, interests []config.Interest) (*taste.Interests, error) {
+	vectors := make([]embed.Vector, len(interests))
+	var (
+		texts   []string
+		missing []int // indexes of interests not cached
+	)
+	for i, in := range interests {
+		ok, err := c.GetJSON(ctx, "interest-embedding", e.Model()+"\n"+in.Description, &vectors[i])
+		if err != nil {
+			slog.Debug("interest embedding cache read failed", "err", err)
+		}
+		if !ok || len(vectors[i]) == 0 {
+			texts = append(texts, in.Description)
+			missing = append(missing, i)
+		}
+	}
+	if len(texts) > 0 {
+		vecs, err := e.Embed(ctx, texts)
+		if err != nil {
+			return nil, fmt.Errorf("embed interests: %w", err)
+		}
+		for j, i := range missing {
+			vectors[i] = vecs[j]
+			if err := c.SetJSON(ctx, "interest-embedding", e.Model()+"\n"+interests[i].Description, vecs[j], embedInterestTTL); err != nil {
+				slog.Debug("interest embedding cache write failed", "err", err)
+			}
+		}
+	}
+	return taste.NewInterests(interests, vectors)
+}
+
+// embedPosts embeds posts in one request, by post ID.
+func embedPosts(ctx context.Context, e embed.Embedder, posts []store.Post) (map[int64]embed.Vector, error) {
+	texts := make([]string, len(posts))
+	for i, p := range posts {
+		texts[i] = embedText(p)
+	}
+	vecs, err := e.Embed(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("embed posts: %w", err)
+	}
+	vectors := make(map[int64]embed.Vector, len(posts))
+	for i, p := range posts {
+		vectors[p.ID] = vecs[i]
+	}
+	return vectors, nil
+}
+
+// embedText is the part of a post that is embedded.
+func embedText(p store.Post) string {
+	text := p.Text
+	if text == "" {
+		text = p.Snippet
+	}
+	return firstNRunes(text, embedMaxRunes)
+}
+
+// postEmbedding returns the post's embedding, computing and storing it when
+// the post has none yet.
+func postEmbedding(ctx context.Context, db *store.Store, e embed.Embedder, p store.Post) (embed.Vector, error) {
+	v, err := db.GetEmbedding(ctx, p.ID, e.Model())
+	if !errors.Is(err, store.ErrNoEmbedding) {
+		return v, err
+	}
+	vectors, err := embedPosts(ctx, e, []store.Post{p})
+	if err != nil {
+		return nil, err
+	}
+	if err := db.SaveEmbeddings(ctx, e.Model(), vectors, time.Now()); err != nil {
+		return nil, err
+	}
+	return vectors[p.ID], nil
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/ppiankov/noisepan/internal/digest"
+	"github.com/ppiankov/noisepan/internal/events"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/taste"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startEvents puts the event bus on the command's context, so everything
+// the command runs publishes to the same subscribers.
+func startEvents(cmd *cobra.Command) {
+	ctx, _ := withEventBus(cmd.Context())
+	cmd.SetContext(ctx)
+}
+
+// withEventBus returns ctx and the bus it carries, first adding one with the
+// subscribers every command has when it carries none. Actions that depend
+// on a subscriber call it, as tests run them without the root command.
+func withEventBus(ctx context.Context) (context.Context, *events.Bus) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if bus := events.FromContext(ctx); bus != nil {
+		return ctx, bus
+	}
+	bus := events.New()
+	bus.Subscribe(logEvent)
+	bus.Subscribe(traceEvent)
+	return events.WithBus(ctx, bus), bus
+}
+
+// logEvent logs failures as warnings and the rest at debug level.
+func logEvent(_ context.Context, ev events.Event) {
+	switch ev.Kind {
+	case events.SourceFailed:
+		slog.Warn("source fetch failed", "source", ev.Source, "err", ev.Err)
+	case events.PostReadNow:
+		slog.Debug("post scored read_now", "source", ev.Source, "channel", ev.Channel, "id", ev.PostID, "score", ev.Score)
+	case events.DigestGenerated:
+		slog.Debug("digest generated", "posts", ev.Posts, "items", ev.Items)
+	}
+}
+
+// traceEvent records events on the current span. Ingested posts are left
+// out: a pull stores hundreds, and its fetch spans already count them.
+func traceEvent(ctx context.Context, ev events.Event) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() || ev.Kind == events.PostIngested {
+		return
+	}
+	var attrs []attribute.KeyValue
+	if ev.Source != "" {
+		attrs = append(attrs, attribute.String("noisepan.source", ev.Source))
+	}
+	if ev.Channel != "" {
+		attrs = append(attrs, attribute.String("noisepan.channel", ev.Channel))
+	}
+	if ev.PostID != 0 {
+		attrs = append(attrs, attribute.Int64("noisepan.post_id", ev.PostID), attribute.Int("noisepan.score", ev.Score))
+	}
+	if ev.Err != nil {
+		attrs = append(attrs, attribute.String("error.message", ev.Err.Error()))
+	}
+	if ev.Kind == events.DigestGenerated {
+		attrs = append(attrs, attribute.Int("noisepan.posts", ev.Posts), attribute.Int("noisepan.items", ev.Items))
+	}
+	span.AddEvent(string(ev.Kind), trace.WithAttributes(attrs...))
+}
+
+// postEvent describes p as an event of kind.
+func postEvent(kind events.Kind, p store.PostWithScore) events.Event {
+	ev := events.Event{
+		Kind:     kind,
+		PostID:   p.Post.ID,
+		Source:   p.Post.Source,
+		Channel:  p.Post.Channel,
+		URL:      p.Post.URL,
+		PostedAt: p.Post.PostedAt,
+		Snippet:  searchSnippet(p.Post),
+	}
+	if p.Score != nil {
+		ev.Score, ev.Tier, ev.Labels = p.Score.Score, p.Score.Tier, p.Score.Labels
+	}
+	return ev
+}
+
+// digestEvent describes a rendered digest.
+func digestEvent(input digest.DigestInput) events.Event {
+	ev := events.Event{Kind: events.DigestGenerated, Posts: input.TotalPosts}
+	for _, item := range input.Items {
+		if item.Tier != taste.TierIgnore {
+			ev.Items++
+		}
+	}
+	return ev
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ppiankov/noisepan/internal/events"
+	"github.com/spf13/cobra"
+)
+
+func TestEventsPublishedByPullAndDigest(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	scriptPath := filepath.Join(tmpDir, "forge-plan.sh")
+
+	writeTestForgePlanScript(t, scriptPath)
+	writeTestConfig(t, tmpDir, dbPath, scriptPath)
+	writeTestTaste(t, tmpDir)
+
+	oldConfigDir, oldSince, oldFormat, oldNoColor := configDir, digestSince, digestFormat, noColor
+	t.Cleanup(func() { configDir, digestSince, digestFormat, noColor = oldConfigDir, oldSince, oldFormat, oldNoColor })
+	configDir = tmpDir
+	digestSince, digestFormat, noColor = "", "terminal", true
+
+	ctx, bus := withEventBus(context.Background())
+	if again, same := withEventBus(ctx); again != ctx || same != bus {
+		t.Fatal("withEventBus replaced the bus ctx carried")
+	}
+	seen := map[events.Kind][]events.Event{}
+	bus.Subscribe(func(_ context.Context, ev events.Event) { seen[ev.Kind] = append(seen[ev.Kind], ev) })
+	cmd := &cobra.Command{}
+	cmd.SetContext(ctx)
+
+	if _, err := captureStdout(t, func() error { return pullAction(cmd, nil) }); err != nil {
+		t.Fatalf("pull action: %v", err)
+	}
+	if n := len(seen[events.PostIngested]); n != 3 {
+		t.Errorf("%d posts ingested, want 3", n)
+	}
+	if _, err := captureStdout(t, func() error { return digestAction(cmd, nil) }); err != nil {
+		t.Fatalf("digest action: %v", err)
+	}
+	readNow := seen[events.PostReadNow]
+	if len(readNow) != 1 || readNow[0].Source != "forgeplan" || readNow[0].Tier != "read_now" {
+		t.Errorf("read_now events = %+v", readNow)
+	}
+	if d := seen[events.DigestGenerated]; len(d) != 1 || d[0].Posts != 3 || d[0].Items != 2 {
+		t.Errorf("digest events = %+v", d)
+	}
+
+	// A failing source is reported; being the only one, it fails the pull.
+	if err := os.WriteFile(scriptPath, []byte("#!/bin/sh\nexit 1\n"), 0o755); err != nil {
+		t.Fatalf("write script: %v", err)
+	}
+	if _, err := captureStdout(t, func() error { return pullAction(cmd, nil) }); !errors.Is(err, errAllSourcesFailed) {
+		t.Fatalf("failing pull = %v, want errAllSourcesFailed", err)
+	}
+	if f := seen[events.SourceFailed]; len(f) != 1 || f[0].Source != "forgeplan" || f[0].Err == nil {
+		t.Errorf("source failed events = %+v", f)
+	}
+}
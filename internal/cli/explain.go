@@ -3,10 +3,10 @@ package cli
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"path/filepath"
 	"strconv"
-	"time"
+	"strings"
 
 	"github.com/ppiankov/noisepan/internal/config"
 	"github.com/ppiankov/noisepan/internal/store"
@@ -32,13 +32,13 @@ func explainAction(_ *cobra.Command, args []string) error {
 		return fmt.Errorf("load config: %w", err)
 	}
 
-	tastePath := filepath.Join(configDir, config.DefaultTasteFile)
-	profile, err := config.LoadTaste(tastePath)
+	tastePath := profileTastePath(cfg)
+	profile, err := loadTaste(cfg, tastePath)
 	if err != nil {
 		return fmt.Errorf("load taste: %w", err)
 	}
 
-	db, err := store.Open(cfg.Storage.Path)
+	db, err := openStore(cfg)
 	if err != nil {
 		return fmt.Errorf("open store: %w", err)
 	}
@@ -46,27 +46,23 @@ func explainAction(_ *cobra.Command, args []string) error {
 
 	ctx := context.Background()
 
-	// Get all posts (no time filter) and find by ID
-	posts, err := db.GetPosts(ctx, time.Time{}, "")
-	if err != nil {
-		return fmt.Errorf("get posts: %w", err)
-	}
-
-	var found *store.PostWithScore
-	for i := range posts {
-		if posts[i].Post.ID == postID {
-			found = &posts[i]
-			break
-		}
-	}
-
-	if found == nil {
+	found, err := db.GetPostByID(ctx, postID)
+	if errors.Is(err, store.ErrPostNotFound) {
 		return fmt.Errorf("post %d not found", postID)
 	}
+	if err != nil {
+		return fmt.Errorf("get post: %w", err)
+	}
 
 	p := found.Post
 	fmt.Printf("Post #%d\n", p.ID)
 	fmt.Printf("  Source:  %s/%s\n", p.Source, p.Channel)
+	if p.Author != "" {
+		fmt.Printf("  Author:  %s\n", p.Author)
+	}
+	if p.Language != "" {
+		fmt.Printf("  Lang:    %s\n", p.Language)
+	}
 	fmt.Printf("  Snippet: %s\n", p.Snippet)
 	if p.URL != "" {
 		fmt.Printf("  URL:     %s\n", p.URL)
@@ -79,15 +75,17 @@ func explainAction(_ *cobra.Command, args []string) error {
 		if len(found.Score.Labels) > 0 {
 			fmt.Printf("Labels: %v\n", found.Score.Labels)
 		}
+		if found.Changed() {
+			fmt.Printf("Note: the post changed after it was scored (%s); this breakdown is for the old text. Run 'noisepan rescore' to update it.\n",
+				found.Score.ScoredAt.Local().Format("2006-01-02 15:04"))
+		}
 		fmt.Println()
 
 		if len(found.Score.Explanation) > 0 {
 			var contributions []taste.ScoreContribution
 			if err := json.Unmarshal(found.Score.Explanation, &contributions); err == nil {
 				fmt.Println("Breakdown:")
-				for _, c := range contributions {
-					fmt.Printf("  %+d  %s\n", c.Points, c.Reason)
-				}
+				printBreakdown(contributions, storePostToSourcePost(p).Text)
 			}
 		}
 	} else {
@@ -98,10 +96,42 @@ func explainAction(_ *cobra.Command, args []string) error {
 		}
 		fmt.Println()
 		fmt.Println("Breakdown:")
-		for _, c := range sp.Explanation {
-			fmt.Printf("  %+d  %s\n", c.Points, c.Reason)
-		}
+		printBreakdown(sp.Explanation, sp.Post.Text)
 	}
 
 	return nil
 }
+
+// printBreakdown prints each contribution and, for a keyword or rule term,
+// where in text it matched.
+func printBreakdown(contributions []taste.ScoreContribution, text string) {
+	for _, c := range contributions {
+		fmt.Printf("  %+d  %s\n", c.Points, c.Reason)
+		if c.Match != nil {
+			fmt.Printf("        at %d: %s\n", c.Match.Start, matchContext(text, c.Match, explainContext))
+		}
+	}
+}
+
+// explainContext is how many characters explain shows around a match.
+const explainContext = 30
+
+// matchContext returns the match in brackets with up to n characters of
+// text on either side, on one line. A match that no longer lines up with
+// text, as after an edit, is shown alone.
+func matchContext(text string, m *taste.TextMatch, n int) string {
+	runes := []rune(text)
+	if m.Start < 0 || m.End > len(runes) || m.Start > m.End || string(runes[m.Start:m.End]) != m.Text {
+		return strconv.Quote(m.Text)
+	}
+	from, to := max(0, m.Start-n), min(len(runes), m.End+n)
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	b.WriteString(string(runes[from:m.Start]) + "[" + m.Text + "]" + string(runes[m.End:to]))
+	if to < len(runes) {
+		b.WriteString("…")
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestExplainAction(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	scriptPath := filepath.Join(tmpDir, "forge-plan.sh")
+
+	writeTestForgePlanScript(t, scriptPath)
+	writeTestConfig(t, tmpDir, dbPath, scriptPath)
+	writeTestTaste(t, tmpDir)
+
+	oldConfigDir := configDir
+	t.Cleanup(func() { configDir = oldConfigDir })
+	configDir = tmpDir
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	if _, err := captureStdout(t, func() error { return pullAction(cmd, nil) }); err != nil {
+		t.Fatalf("pull: %v", err)
+	}
+
+	out, err := captureStdout(t, func() error { return explainAction(cmd, []string{"1"}) })
+	if err != nil {
+		t.Fatalf("explain: %v", err)
+	}
+	requireContains(t, out, "Post #1")
+	requireContains(t, out, "(not saved)")
+	requireContains(t, out, "Breakdown:")
+	requireContains(t, out, "at 0: [CVE]-2026-1111 Kubernetes")
+
+	if _, err := captureStdout(t, func() error { return explainAction(cmd, []string{"99"}) }); err == nil || err.Error() != "post 99 not found" {
+		t.Errorf("err = %v, want post 99 not found", err)
+	}
+}
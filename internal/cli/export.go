@@ -0,0 +1,392 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/privacy"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/taste"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportSince          string
+	exportPerTier        int
+	exportFeedbackWeight float64
+	exportSeed           uint64
+	exportOutput         string
+	exportFormat         string
+	exportNoRedactPII    bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export balanced training samples or the full post archive",
+	Long: `By default (--format samples) writes scored posts as JSON lines (text, tier,
+labels, feedback) with the same number of samples drawn from each tier, for
+fine-tuning or training a classifier outside noisepan. Posts with feedback
+are drawn more often since their labels are confirmed.
+
+--format jsonl or csv instead dumps every post in the window, oldest first,
+with its score, tier, labels, scoring explanation and feedback, for analysis
+in pandas, DuckDB and the like.
+
+Email addresses, phone numbers, IP addresses, and the configured
+privacy.redact patterns are redacted. The first three can be kept in the
+jsonl and csv archive with --no-redact-pii, so "import-posts" reads back the
+posts as stored; samples are always redacted.`,
+	Args: cobra.NoArgs,
+	RunE: exportAction,
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportSince, "since", "", "time window (e.g. 90d); default all stored posts")
+	exportCmd.Flags().IntVar(&exportPerTier, "per-tier", 0, "samples per tier (0 = size of the smallest tier)")
+	exportCmd.Flags().Float64Var(&exportFeedbackWeight, "feedback-weight", 3, "sampling weight of posts with feedback relative to others")
+	exportCmd.Flags().Uint64Var(&exportSeed, "seed", 0, "random seed for reproducible samples (0 = random)")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "write to file instead of stdout")
+	exportCmd.Flags().StringVar(&exportFormat, "format", "samples", "output: samples (balanced JSONL), jsonl or csv (all posts)")
+	exportCmd.Flags().BoolVar(&exportNoRedactPII, "no-redact-pii", false, "keep emails, phone numbers and IPs in --format jsonl/csv")
+	rootCmd.AddCommand(exportCmd)
+}
+
+// exportTiers are the classes balanced by export, listed first; tiers of a
+// profile with custom tiers follow by name.
+var exportTiers = []string{taste.TierReadNow, taste.TierSkim, taste.TierIgnore}
+
+// sampleTiers returns exportTiers, then the other tiers in present, by name.
+func sampleTiers(present []string) []string {
+	tiers := slices.Clone(exportTiers)
+	slices.Sort(present)
+	for _, tier := range present {
+		if !slices.Contains(tiers, tier) {
+			tiers = append(tiers, tier)
+		}
+	}
+	return tiers
+}
+
+type exportSample struct {
+	ID       int64    `json:"id"`
+	Source   string   `json:"source"`
+	Channel  string   `json:"channel"`
+	Text     string   `json:"text"`
+	Tier     string   `json:"tier"`
+	Score    int      `json:"score"`
+	Labels   []string `json:"labels,omitempty"`
+	Feedback string   `json:"feedback,omitempty"` // up or down
+	Reason   string   `json:"reason,omitempty"`
+}
+
+func exportAction(cmd *cobra.Command, _ []string) error {
+	if exportPerTier < 0 {
+		return errors.New("--per-tier must not be negative")
+	}
+	if exportFeedbackWeight <= 0 {
+		return errors.New("--feedback-weight must be positive")
+	}
+	switch exportFormat {
+	case "samples", "jsonl", "csv":
+	default:
+		return fmt.Errorf("unknown format %q (want samples, jsonl, or csv)", exportFormat)
+	}
+	if exportNoRedactPII && exportFormat == "samples" {
+		return errors.New("--no-redact-pii only applies to --format jsonl or csv; samples are always redacted")
+	}
+
+	cfg, err := config.Load(configDir)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	patterns := cfg.Privacy.Redact.Patterns
+	if !exportNoRedactPII {
+		patterns = append(slices.Clone(privacy.PIIPatterns), patterns...)
+	}
+	redact, err := privacy.Compile(patterns)
+	if err != nil {
+		return err
+	}
+
+	var since time.Time
+	if exportSince != "" {
+		dur, err := parseDuration(exportSince)
+		if err != nil {
+			return fmt.Errorf("parse --since: %w", err)
+		}
+		since = time.Now().Add(-dur)
+	}
+
+	db, err := openStore(cfg)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	ctx := cmd.Context()
+	posts, err := db.GetPosts(ctx, since, "", store.PostFilter{Order: store.OrderOldest})
+	if err != nil {
+		return fmt.Errorf("get posts: %w", err)
+	}
+	votes, err := db.GetFeedback(ctx, time.Time{})
+	if err != nil {
+		return fmt.Errorf("get feedback: %w", err)
+	}
+
+	w := cmd.OutOrStdout()
+	if exportOutput != "" {
+		f, err := os.Create(exportOutput)
+		if err != nil {
+			return fmt.Errorf("create output: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+
+	if exportFormat != "samples" {
+		records := buildExportRecords(posts, votes, redact)
+		write := writeExportRecordsJSONL
+		if exportFormat == "csv" {
+			write = writeExportRecordsCSV
+		}
+		if err := write(w, records); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d posts\n", len(records))
+		return nil
+	}
+
+	seed := exportSeed
+	if seed == 0 {
+		seed = uint64(time.Now().UnixNano())
+	}
+	rng := rand.New(rand.NewPCG(seed, seed))
+
+	samples := buildExportSamples(posts, votes, redact)
+	picked := sampleBalanced(samples, exportPerTier, exportFeedbackWeight, rng)
+
+	if err := writeExportJSONL(w, picked); err != nil {
+		return err
+	}
+
+	counts := make(map[string]int)
+	for _, s := range picked {
+		counts[s.Tier]++
+	}
+	var parts []string
+	for _, tier := range sampleTiers(slices.Collect(maps.Keys(counts))) {
+		parts = append(parts, fmt.Sprintf("%s %d", tier, counts[tier]))
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d samples (%s)\n", len(picked), strings.Join(parts, ", "))
+	return nil
+}
+
+// buildExportSamples turns scored posts into redacted samples. Unscored
+// posts have no label and are left out.
+func buildExportSamples(posts []store.PostWithScore, votes []store.Feedback, redact []*regexp.Regexp) []exportSample {
+	byPost := make(map[int64]store.Feedback, len(votes))
+	for _, v := range votes {
+		byPost[v.PostID] = v
+	}
+
+	samples := make([]exportSample, 0, len(posts))
+	for _, p := range posts {
+		if p.Score == nil {
+			continue
+		}
+		s := exportSample{
+			ID:      p.Post.ID,
+			Source:  p.Post.Source,
+			Channel: p.Post.Channel,
+			Text:    privacy.Apply(postText(p.Post), redact),
+			Tier:    p.Score.Tier,
+			Score:   p.Score.Score,
+			Labels:  p.Score.Labels,
+		}
+		if v, ok := byPost[p.Post.ID]; ok {
+			s.Feedback = "down"
+			if v.Vote == store.FeedbackUp {
+				s.Feedback = "up"
+			}
+			s.Reason = privacy.Apply(v.Reason, redact)
+		}
+		samples = append(samples, s)
+	}
+	return samples
+}
+
+// sampleBalanced draws perTier samples from every tier (all tiers get the
+// size of the smallest non-empty one when perTier is 0) by weighted sampling
+// without replacement: each sample gets the key -ln(u)/weight and the
+// smallest keys win (Efraimidis–Spirakis). The result is shuffled.
+func sampleBalanced(samples []exportSample, perTier int, feedbackWeight float64, rng *rand.Rand) []exportSample {
+	type keyed struct {
+		sample exportSample
+		key    float64
+	}
+	byTier := make(map[string][]keyed)
+	for _, s := range samples {
+		weight := 1.0
+		if s.Feedback != "" {
+			weight = feedbackWeight
+		}
+		// 1-Float64 is in (0, 1], keeping the logarithm finite.
+		key := -math.Log(1-rng.Float64()) / weight
+		byTier[s.Tier] = append(byTier[s.Tier], keyed{s, key})
+	}
+
+	tiers := sampleTiers(slices.Collect(maps.Keys(byTier)))
+	n := perTier
+	if n == 0 {
+		for _, tier := range tiers {
+			if c := len(byTier[tier]); c > 0 && (n == 0 || c < n) {
+				n = c
+			}
+		}
+	}
+
+	var picked []exportSample
+	for _, tier := range tiers {
+		group := byTier[tier]
+		sort.Slice(group, func(i, j int) bool { return group[i].key < group[j].key })
+		for i := 0; i < n && i < len(group); i++ {
+			picked = append(picked, group[i].sample)
+		}
+	}
+	rng.Shuffle(len(picked), func(i, j int) { picked[i], picked[j] = picked[j], picked[i] })
+	return picked
+}
+
+func writeExportJSONL(w io.Writer, samples []exportSample) error {
+	enc := json.NewEncoder(w)
+	for _, s := range samples {
+		if err := enc.Encode(s); err != nil {
+			return fmt.Errorf("write sample: %w", err)
+		}
+	}
+	return nil
+}
+
+// exportRecord is one post in the archive formats. Score, tier, labels and
+// explanation are empty for posts not scored yet.
+type exportRecord struct {
+	ID          int64           `json:"id"`
+	Source      string          `json:"source"`
+	Channel     string          `json:"channel"`
+	ExternalID  string          `json:"external_id"`
+	URL         string          `json:"url,omitempty"`
+	PostedAt    time.Time       `json:"posted_at"`
+	FetchedAt   time.Time       `json:"fetched_at"`
+	Text        string          `json:"text"`
+	Score       *int            `json:"score,omitempty"`
+	Tier        string          `json:"tier,omitempty"`
+	Labels      []string        `json:"labels,omitempty"`
+	Explanation json.RawMessage `json:"explanation,omitempty"`
+	Feedback    string          `json:"feedback,omitempty"` // up or down
+	Reason      string          `json:"reason,omitempty"`
+	Author      string          `json:"author,omitempty"`
+	Language    string          `json:"language,omitempty"`
+}
+
+// exportCSVHeader names the CSV columns, in exportRecord field order.
+var exportCSVHeader = []string{
+	"id", "source", "channel", "external_id", "url", "posted_at", "fetched_at",
+	"text", "score", "tier", "labels", "explanation", "feedback", "reason", "author", "language",
+}
+
+// buildExportRecords turns every post, scored or not, into a redacted
+// archive record.
+func buildExportRecords(posts []store.PostWithScore, votes []store.Feedback, redact []*regexp.Regexp) []exportRecord {
+	byPost := make(map[int64]store.Feedback, len(votes))
+	for _, v := range votes {
+		byPost[v.PostID] = v
+	}
+
+	records := make([]exportRecord, 0, len(posts))
+	for _, p := range posts {
+		r := exportRecord{
+			ID:         p.Post.ID,
+			Source:     p.Post.Source,
+			Channel:    p.Post.Channel,
+			ExternalID: p.Post.ExternalID,
+			URL:        p.Post.URL,
+			Author:     p.Post.Author,
+			Language:   p.Post.Language,
+			PostedAt:   p.Post.PostedAt.UTC(),
+			FetchedAt:  p.Post.FetchedAt.UTC(),
+			Text:       privacy.Apply(postText(p.Post), redact),
+		}
+		if p.Score != nil {
+			score := p.Score.Score
+			r.Score = &score
+			r.Tier = p.Score.Tier
+			r.Labels = p.Score.Labels
+			if len(p.Score.Explanation) > 0 && string(p.Score.Explanation) != "null" {
+				r.Explanation = p.Score.Explanation
+			}
+		}
+		if v, ok := byPost[p.Post.ID]; ok {
+			r.Feedback = "down"
+			if v.Vote == store.FeedbackUp {
+				r.Feedback = "up"
+			}
+			r.Reason = privacy.Apply(v.Reason, redact)
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+func writeExportRecordsJSONL(w io.Writer, records []exportRecord) error {
+	enc := json.NewEncoder(w)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return fmt.Errorf("write post: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeExportRecordsCSV writes records with a header row. Labels are joined
+// with "|" and the explanation is kept as a JSON string.
+func writeExportRecordsCSV(w io.Writer, records []exportRecord) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(exportCSVHeader); err != nil {
+		return fmt.Errorf("write csv header: %w", err)
+	}
+	for _, r := range records {
+		score := ""
+		if r.Score != nil {
+			score = strconv.Itoa(*r.Score)
+		}
+		row := []string{
+			strconv.FormatInt(r.ID, 10), r.Source, r.Channel, r.ExternalID, r.URL,
+			r.PostedAt.Format(time.RFC3339), r.FetchedAt.Format(time.RFC3339),
+			r.Text, score, r.Tier, strings.Join(r.Labels, "|"), string(r.Explanation),
+			r.Feedback, r.Reason, r.Author, r.Language,
+		}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("write csv row: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("write csv: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"math/rand/v2"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/taste"
+	"github.com/spf13/cobra"
+)
+
+func TestSampleBalanced(t *testing.T) {
+	var samples []exportSample
+	add := func(tier string, n int) {
+		for i := 0; i < n; i++ {
+			samples = append(samples, exportSample{ID: int64(len(samples) + 1), Tier: tier})
+		}
+	}
+	add(taste.TierReadNow, 2)
+	add(taste.TierSkim, 5)
+	add(taste.TierIgnore, 40)
+
+	rng := rand.New(rand.NewPCG(1, 1))
+	picked := sampleBalanced(samples, 0, 3, rng)
+	counts := make(map[string]int)
+	for _, s := range picked {
+		counts[s.Tier]++
+	}
+	for _, tier := range exportTiers {
+		if counts[tier] != 2 {
+			t.Errorf("%s: got %d samples, want 2", tier, counts[tier])
+		}
+	}
+
+	// --per-tier caps at what each tier has.
+	picked = sampleBalanced(samples, 4, 3, rng)
+	if len(picked) != 2+4+4 {
+		t.Errorf("got %d samples, want 10", len(picked))
+	}
+}
+
+func TestSampleBalanced_PrefersFeedback(t *testing.T) {
+	var samples []exportSample
+	for i := 0; i < 50; i++ {
+		samples = append(samples, exportSample{ID: int64(i), Tier: taste.TierIgnore})
+	}
+	samples[7].Feedback = "down"
+
+	hits := 0
+	rng := rand.New(rand.NewPCG(2, 2))
+	for i := 0; i < 200; i++ {
+		for _, s := range sampleBalanced(samples, 5, 1000, rng) {
+			if s.ID == 7 {
+				hits++
+			}
+		}
+	}
+	if hits < 190 {
+		t.Errorf("feedback sample picked in %d/200 draws, want nearly all", hits)
+	}
+}
+
+func TestExportAction(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	scriptPath := filepath.Join(tmpDir, "forge-plan.sh")
+
+	writeTestForgePlanScript(t, scriptPath)
+	writeTestConfig(t, tmpDir, dbPath, scriptPath)
+
+	st, err := store.Open(dbPath)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	ctx := context.Background()
+	now := time.Now()
+	tiers := []string{taste.TierReadNow, taste.TierSkim, taste.TierIgnore, taste.TierIgnore}
+	var first store.Post
+	for i, tier := range tiers {
+		p, err := st.InsertPost(ctx, store.PostInput{
+			Source: "rss", Channel: "security", ExternalID: strconv.Itoa(i),
+			Text:     "Report " + strconv.Itoa(i) + " from alice@example.com",
+			PostedAt: now.Add(-time.Hour), FetchedAt: now,
+		})
+		if err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+		if i == 0 {
+			first = p
+		}
+		if err := st.SaveScore(ctx, store.Score{PostID: p.ID, Score: 3 - i, Tier: tier, Labels: []string{"security"}, ScoredAt: now}); err != nil {
+			t.Fatalf("save score: %v", err)
+		}
+	}
+	if _, err := st.InsertPost(ctx, store.PostInput{
+		Source: "rss", Channel: "security", ExternalID: "unscored",
+		Text: "not labeled", PostedAt: now, FetchedAt: now,
+	}); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if err := st.SaveFeedback(ctx, store.Feedback{PostID: first.ID, Vote: store.FeedbackUp, Reason: "ping bob@example.org", CreatedAt: now}); err != nil {
+		t.Fatalf("save feedback: %v", err)
+	}
+	_ = st.Close()
+
+	oldConfigDir, oldSince, oldPerTier, oldWeight, oldSeed, oldOutput := configDir, exportSince, exportPerTier, exportFeedbackWeight, exportSeed, exportOutput
+	t.Cleanup(func() {
+		configDir, exportSince, exportPerTier, exportFeedbackWeight, exportSeed, exportOutput = oldConfigDir, oldSince, oldPerTier, oldWeight, oldSeed, oldOutput
+	})
+	configDir = tmpDir
+	exportSince, exportPerTier, exportFeedbackWeight, exportSeed, exportOutput = "", 0, 3, 42, ""
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(ctx)
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+
+	if err := exportAction(cmd, nil); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	requireContains(t, errOut.String(), "Exported 3 samples (read_now 1, skim 1, ignore 1)")
+
+	var samples []exportSample
+	sc := bufio.NewScanner(&out)
+	for sc.Scan() {
+		var s exportSample
+		if err := json.Unmarshal(sc.Bytes(), &s); err != nil {
+			t.Fatalf("unmarshal %q: %v", sc.Text(), err)
+		}
+		samples = append(samples, s)
+	}
+	if len(samples) != 3 {
+		t.Fatalf("got %d lines, want 3", len(samples))
+	}
+	for _, s := range samples {
+		if strings.Contains(s.Text, "@example.com") || !strings.Contains(s.Text, "[REDACTED]") {
+			t.Errorf("text not redacted: %q", s.Text)
+		}
+		if s.ID == first.ID && (s.Feedback != "up" || s.Reason != "ping [REDACTED]") {
+			t.Errorf("feedback = %q, reason = %q", s.Feedback, s.Reason)
+		}
+	}
+
+	exportFeedbackWeight = 0
+	if err := exportAction(cmd, nil); err == nil {
+		t.Error("expected error for non-positive --feedback-weight")
+	}
+}
+
+func TestExportAction_Archive(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	scriptPath := filepath.Join(tmpDir, "forge-plan.sh")
+
+	writeTestForgePlanScript(t, scriptPath)
+	writeTestConfig(t, tmpDir, dbPath, scriptPath)
+
+	st, err := store.Open(dbPath)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	scored, err := st.InsertPost(ctx, store.PostInput{
+		Source: "rss", Channel: "security", ExternalID: "a", URL: "https://example.com/a",
+		Text: "CVE, \"quoted\", from alice@example.com", PostedAt: now.Add(-2 * time.Hour), FetchedAt: now,
+	})
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	explanation := json.RawMessage(`[{"reason":"high_signal: cve","points":5}]`)
+	if err := st.SaveScore(ctx, store.Score{PostID: scored.ID, Score: 5, Tier: taste.TierSkim, Labels: []string{"security", "cve"}, ScoredAt: now, Explanation: explanation}); err != nil {
+		t.Fatalf("save score: %v", err)
+	}
+	if err := st.SaveFeedback(ctx, store.Feedback{PostID: scored.ID, Vote: store.FeedbackDown, CreatedAt: now}); err != nil {
+		t.Fatalf("save feedback: %v", err)
+	}
+	if _, err := st.InsertPost(ctx, store.PostInput{
+		Source: "rss", Channel: "security", ExternalID: "b",
+		Text: "not scored yet", PostedAt: now.Add(-time.Hour), FetchedAt: now,
+	}); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	_ = st.Close()
+
+	oldConfigDir, oldSince, oldFormat, oldOutput, oldNoPII := configDir, exportSince, exportFormat, exportOutput, exportNoRedactPII
+	t.Cleanup(func() {
+		configDir, exportSince, exportFormat, exportOutput, exportNoRedactPII = oldConfigDir, oldSince, oldFormat, oldOutput, oldNoPII
+	})
+	configDir, exportSince, exportOutput = tmpDir, "90d", ""
+
+	run := func(format string) string {
+		t.Helper()
+		exportFormat = format
+		cmd := &cobra.Command{}
+		cmd.SetContext(ctx)
+		var out, errOut bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&errOut)
+		if err := exportAction(cmd, nil); err != nil {
+			t.Fatalf("export --format %s: %v", format, err)
+		}
+		requireContains(t, errOut.String(), "Exported 2 posts")
+		return out.String()
+	}
+
+	lines := strings.Split(strings.TrimSpace(run("jsonl")), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	var first, second exportRecord
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &second); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if first.ID != scored.ID || first.Score == nil || *first.Score != 5 || first.Tier != taste.TierSkim || first.Feedback != "down" {
+		t.Errorf("scored record = %+v", first)
+	}
+	if !strings.Contains(string(first.Explanation), "high_signal: cve") || strings.Contains(first.Text, "alice@") {
+		t.Errorf("explanation = %s, text = %q", first.Explanation, first.Text)
+	}
+	if second.Score != nil || second.Tier != "" || second.Explanation != nil {
+		t.Errorf("unscored record = %+v", second)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(run("csv"))).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+	if len(records) != 3 || strings.Join(records[0], ",") != strings.Join(exportCSVHeader, ",") {
+		t.Fatalf("csv = %v", records)
+	}
+	row := records[1]
+	if row[8] != "5" || row[10] != "security|cve" || !strings.Contains(row[7], `"quoted"`) || row[5] != now.Add(-2*time.Hour).UTC().Format(time.RFC3339) {
+		t.Errorf("csv row = %v", row)
+	}
+	if records[2][8] != "" {
+		t.Errorf("unscored csv score = %q, want empty", records[2][8])
+	}
+
+	// --no-redact-pii keeps the archive as stored, for import-posts.
+	exportNoRedactPII = true
+	if err := json.Unmarshal([]byte(strings.Split(run("jsonl"), "\n")[0]), &first); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !strings.Contains(first.Text, "alice@example.com") {
+		t.Errorf("text with --no-redact-pii = %q", first.Text)
+	}
+	exportFormat = "samples"
+	if err := exportAction(&cobra.Command{}, nil); err == nil || !strings.Contains(err.Error(), "--no-redact-pii") {
+		t.Errorf("samples with --no-redact-pii: err = %v", err)
+	}
+	exportNoRedactPII = false
+
+	exportFormat = "parquet"
+	if err := exportAction(&cobra.Command{}, nil); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
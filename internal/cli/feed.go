@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/digest"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// feedTitle is the title of the Atom feed.
+const feedTitle = "noisepan digest"
+
+var (
+	feedSince   string
+	feedSource  string
+	feedChannel string
+	feedOutput  string
+	feedSelf    string
+)
+
+var feedCmd = &cobra.Command{
+	Use:   "feed",
+	Short: "Write the digest as an Atom feed",
+	Long: `Writes the read_now and skim posts of the digest window (and those of custom
+tiers between them) as an Atom feed, for feed readers to subscribe to.
+Entry IDs are derived from each post's source, channel and ID, so a reader
+shows a post once however often the feed is regenerated.
+
+Unlike digest, feed does not record the digest or mark anything read, and
+read_now posts of earlier digests stay in their sections rather than moving
+to digest.still_unread, so they do not drop out of the feed. Run it
+after each pull and serve the file, or use "noisepan serve", which serves
+the same feed at /api/feed.`,
+	Args: cobra.NoArgs,
+	RunE: feedAction,
+}
+
+func init() {
+	feedCmd.Flags().StringVar(&feedSince, "since", "", "time window (e.g. 48h); default digest.since")
+	feedCmd.Flags().StringVar(&feedSource, "source", "", "filter by source (e.g. rss, telegram, reddit)")
+	feedCmd.Flags().StringVar(&feedChannel, "channel", "", "filter by channel name")
+	feedCmd.Flags().StringVarP(&feedOutput, "output", "o", "", "write feed to file instead of stdout")
+	feedCmd.Flags().StringVar(&feedSelf, "self", "", "URL the feed is published at, linked as rel=self")
+	rootCmd.AddCommand(feedCmd)
+}
+
+func feedAction(cmd *cobra.Command, _ []string) error {
+	cfg, err := config.Load(configDir)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	sinceDur := cfg.Digest.Since.Duration
+	if feedSince != "" {
+		if sinceDur, err = parseDuration(feedSince); err != nil {
+			return fmt.Errorf("parse --since: %w", err)
+		}
+	}
+
+	tastePath := profileTastePath(cfg)
+	profile, err := loadTaste(cfg, tastePath)
+	if err != nil {
+		return fmt.Errorf("load taste: %w", err)
+	}
+
+	db, err := openStore(cfg)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	ctx := cmd.Context()
+	pipeline, err := newDigestPipeline(ctx, cfg, profile, db)
+	if err != nil {
+		return err
+	}
+	built, err := pipeline.build(ctx, digestRequest{
+		Since:         sinceDur,
+		Filter:        store.PostFilter{Source: feedSource, Channel: feedChannel},
+		NoStillUnread: true,
+	}, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := renderDigest(&buf, digest.NewAtom(feedTitle, feedSelf), built.Input); err != nil {
+		return err
+	}
+	if feedOutput == "" {
+		_, err := cmd.OutOrStdout().Write(buf.Bytes())
+		return err
+	}
+	// Replace the file in one step so a reader never fetches half a feed.
+	tmp := feedOutput + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("write feed: %w", err)
+	}
+	if err := os.Rename(tmp, feedOutput); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("write feed: %w", err)
+	}
+	return nil
+}
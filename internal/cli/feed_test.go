@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"context"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/taste"
+	"github.com/spf13/cobra"
+)
+
+func TestFeedAction(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	scriptPath := filepath.Join(tmpDir, "forge-plan.sh")
+
+	writeTestForgePlanScript(t, scriptPath)
+	writeTestConfig(t, tmpDir, dbPath, scriptPath)
+	writeTestTaste(t, tmpDir)
+
+	st, err := store.Open(dbPath)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	ctx := context.Background()
+	now := time.Now()
+	for i, tier := range []string{taste.TierReadNow, taste.TierSkim, taste.TierIgnore} {
+		p, err := st.InsertPost(ctx, store.PostInput{
+			Source: "rss", Channel: "security", ExternalID: strconv.Itoa(i),
+			Text:     "Report " + strconv.Itoa(i) + " on the fleet",
+			PostedAt: now.Add(-time.Hour), FetchedAt: now,
+		})
+		if err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+		if err := st.SaveScore(ctx, store.Score{PostID: p.ID, Score: 9 - 4*i, Tier: tier, ScoredAt: now}); err != nil {
+			t.Fatalf("save score: %v", err)
+		}
+	}
+	_ = st.Close()
+
+	out := filepath.Join(tmpDir, "digest.xml")
+	oldConfigDir, oldSince, oldOutput := configDir, feedSince, feedOutput
+	t.Cleanup(func() { configDir, feedSince, feedOutput = oldConfigDir, oldSince, oldOutput })
+	configDir, feedSince, feedOutput = tmpDir, "24h", out
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(ctx)
+	read := func() []string {
+		t.Helper()
+		if err := feedAction(cmd, nil); err != nil {
+			t.Fatalf("feed: %v", err)
+		}
+		data, err := os.ReadFile(out)
+		if err != nil {
+			t.Fatalf("read feed: %v", err)
+		}
+		var feed struct {
+			Entries []struct {
+				ID string `xml:"id"`
+			} `xml:"entry"`
+		}
+		if err := xml.Unmarshal(data, &feed); err != nil {
+			t.Fatalf("parse feed: %v", err)
+		}
+		var ids []string
+		for _, e := range feed.Entries {
+			ids = append(ids, e.ID)
+		}
+		return ids
+	}
+
+	first := read()
+	if len(first) != 2 {
+		t.Fatalf("entries = %v, want read_now and skim", first)
+	}
+	if again := read(); len(again) != 2 || again[0] != first[0] || again[1] != first[1] {
+		t.Errorf("ids changed between runs: %v, then %v", first, again)
+	}
+	if _, err := os.Stat(out + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file left behind: %v", err)
+	}
+}
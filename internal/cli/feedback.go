@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var feedbackReason string
+
+var feedbackCmd = &cobra.Command{
+	Use:   "feedback <post-id> up|down",
+	Short: "Record whether a post was worth reading",
+	Long: `Records a thumbs up (worth reading) or down (noise) for a post. A new vote
+replaces the previous one. "stats" reports how often votes agree with the
+tier each post was scored into.`,
+	Args: cobra.ExactArgs(2),
+	RunE: feedbackAction,
+}
+
+func init() {
+	feedbackCmd.Flags().StringVar(&feedbackReason, "reason", "", "optional note on why")
+	rootCmd.AddCommand(feedbackCmd)
+}
+
+func feedbackAction(cmd *cobra.Command, args []string) error {
+	postID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid post ID: %w", err)
+	}
+
+	var vote int
+	switch args[1] {
+	case "up":
+		vote = store.FeedbackUp
+	case "down":
+		vote = store.FeedbackDown
+	default:
+		return fmt.Errorf("unknown vote %q (want up or down)", args[1])
+	}
+
+	db, err := openConfiguredStore()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	if err := db.SaveFeedback(cmd.Context(), store.Feedback{
+		PostID:    postID,
+		Vote:      vote,
+		Reason:    feedbackReason,
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "recorded %s for #%d\n", args[1], postID)
+	return nil
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func TestFeedbackAction(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	scriptPath := filepath.Join(tmpDir, "forge-plan.sh")
+
+	writeTestForgePlanScript(t, scriptPath)
+	writeTestConfig(t, tmpDir, dbPath, scriptPath)
+
+	st, err := store.Open(dbPath)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	ctx := context.Background()
+	now := time.Now()
+	p, err := st.InsertPost(ctx, store.PostInput{
+		Source: "rss", Channel: "security", ExternalID: "1",
+		Text: "Critical OpenSSL CVE", PostedAt: now, FetchedAt: now,
+	})
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	_ = st.Close()
+
+	oldConfigDir, oldReason := configDir, feedbackReason
+	t.Cleanup(func() { configDir, feedbackReason = oldConfigDir, oldReason })
+	configDir = tmpDir
+	feedbackReason = "duplicate of vendor advisory"
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(ctx)
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+
+	id := strconv.FormatInt(p.ID, 10)
+	if err := feedbackAction(cmd, []string{id, "down"}); err != nil {
+		t.Fatalf("feedback: %v", err)
+	}
+	requireContains(t, buf.String(), "recorded down for #"+id)
+
+	if err := feedbackAction(cmd, []string{id, "meh"}); err == nil {
+		t.Error("expected error for unknown vote")
+	}
+	if err := feedbackAction(cmd, []string{"x", "up"}); err == nil {
+		t.Error("expected error for invalid post ID")
+	}
+	if err := feedbackAction(cmd, []string{"42", "up"}); err == nil {
+		t.Error("expected error for missing post")
+	}
+
+	st, err = store.Open(dbPath)
+	if err != nil {
+		t.Fatalf("reopen store: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+	fb, err := st.GetFeedback(ctx, time.Time{})
+	if err != nil {
+		t.Fatalf("get feedback: %v", err)
+	}
+	if len(fb) != 1 || fb[0].Vote != store.FeedbackDown || fb[0].Reason != "duplicate of vendor advisory" {
+		t.Errorf("feedback = %+v", fb)
+	}
+}
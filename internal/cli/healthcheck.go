@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var healthMaxAge string
+
+var healthcheckCmd = &cobra.Command{
+	Use:   "healthcheck",
+	Short: "Exit non-zero unless the database is reachable and the last pull is fresh",
+	Long: `Checks that the database can be opened and queried, and that the last
+successful pull happened within the freshness window (healthcheck.max_age,
+default 2h). Intended for Docker HEALTHCHECK and Kubernetes liveness probes.`,
+	RunE: healthcheckAction,
+}
+
+func init() {
+	healthcheckCmd.Flags().StringVar(&healthMaxAge, "max-age", "", "maximum age of last successful pull (e.g. 90m, 1d)")
+	rootCmd.AddCommand(healthcheckCmd)
+}
+
+func healthcheckAction(cmd *cobra.Command, _ []string) error {
+	cfg, err := config.Load(configDir)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	maxAge := cfg.Health.MaxAge.Duration
+	if healthMaxAge != "" {
+		maxAge, err = parseDuration(healthMaxAge)
+		if err != nil {
+			return fmt.Errorf("parse --max-age: %w", err)
+		}
+	}
+
+	db, err := openStore(cfg)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	ctx := cmd.Context()
+	if err := db.Ping(ctx); err != nil {
+		return fmt.Errorf("database unreachable: %w", err)
+	}
+
+	last, err := db.LastPull(ctx)
+	if err != nil {
+		return err
+	}
+	if last.IsZero() {
+		return fmt.Errorf("unhealthy: no successful pull recorded")
+	}
+
+	age := time.Since(last)
+	if age > maxAge {
+		return fmt.Errorf("unhealthy: last pull %s ago (max %s)", age.Round(time.Second), maxAge)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "ok: last pull %s ago\n", age.Round(time.Second))
+	return nil
+}
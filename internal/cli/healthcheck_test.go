@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func TestHealthcheckAction(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	scriptPath := filepath.Join(tmpDir, "forge-plan.sh")
+
+	writeTestForgePlanScript(t, scriptPath)
+	writeTestConfig(t, tmpDir, dbPath, scriptPath)
+
+	oldConfigDir := configDir
+	oldMaxAge := healthMaxAge
+	t.Cleanup(func() {
+		configDir = oldConfigDir
+		healthMaxAge = oldMaxAge
+	})
+	configDir = tmpDir
+	healthMaxAge = ""
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+
+	// No pull recorded yet
+	err := healthcheckAction(cmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "no successful pull") {
+		t.Fatalf("err = %v, want no successful pull", err)
+	}
+
+	st, err := store.Open(dbPath)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	if err := st.SetLastPull(context.Background(), time.Now().Add(-3*time.Hour)); err != nil {
+		t.Fatalf("set last pull: %v", err)
+	}
+	_ = st.Close()
+
+	// Stale against default 2h window
+	err = healthcheckAction(cmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "unhealthy: last pull") {
+		t.Fatalf("err = %v, want stale pull error", err)
+	}
+
+	// Fresh with a wider window
+	healthMaxAge = "1d"
+	if err := healthcheckAction(cmd, nil); err != nil {
+		t.Fatalf("healthcheck with 1d window: %v", err)
+	}
+	requireContains(t, buf.String(), "ok: last pull")
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/digest"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/taste"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyLimit    int
+	historyFormat   string
+	historyTemplate string
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history [ID]",
+	Short: "List digests kept with --save, or show one again",
+	Long: `Without an argument, lists the digests kept by "digest --save" (and
+"run --save"), newest first: when each was generated, its window, the posts
+it covered, the items it listed per tier, and a prefix of its output's
+SHA-256.
+
+With an ID, renders that digest again as it was generated, whatever the
+taste profile or the stored posts look like now. --format and --template
+pick the output as for digest.
+
+Saved digests are deleted with the posts, after storage.retain_days (plus
+storage.slim_days).`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: historyAction,
+}
+
+func init() {
+	historyCmd.Flags().IntVar(&historyLimit, "limit", 20, "digests to list (0 for all)")
+	historyCmd.Flags().StringVar(&historyFormat, "format", "", "output format: terminal, json, markdown, print")
+	historyCmd.Flags().StringVar(&historyTemplate, "template", "", "render through a Go template file instead of a format (default digest.template)")
+	historyCmd.Flags().BoolVar(&noColor, "no-color", false, "disable ANSI colors")
+	rootCmd.AddCommand(historyCmd)
+}
+
+func historyAction(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(configDir)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	db, err := openStore(cfg)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	ctx := cmd.Context()
+	w := cmd.OutOrStdout()
+	if len(args) == 0 {
+		digests, err := db.ListDigests(ctx, historyLimit)
+		if err != nil {
+			return err
+		}
+		writeHistory(w, digests)
+		return nil
+	}
+
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid digest ID %q", args[0])
+	}
+	formatter, err := digestFormatterFor(cfg, historyFormat, historyTemplate, !noColor)
+	if err != nil {
+		return err
+	}
+	saved, err := db.GetDigest(ctx, id)
+	if err != nil {
+		return err
+	}
+	var input digest.DigestInput
+	if err := json.Unmarshal(saved.Input, &input); err != nil {
+		return fmt.Errorf("decode digest %d: %w", id, err)
+	}
+	return renderDigest(w, formatter, input)
+}
+
+// writeHistory prints the saved digests as a table.
+func writeHistory(w io.Writer, digests []store.DigestSnapshot) {
+	if len(digests) == 0 {
+		fmt.Fprintln(w, "No saved digests. Run 'noisepan digest --save' to keep one.")
+		return
+	}
+	fmt.Fprintf(w, "%6s  %-16s  %-8s  %6s  %-12s  %s\n", "ID", "Generated", "Window", "Posts", "Output", "Listed")
+	for _, d := range digests {
+		hash := d.OutputHash
+		if len(hash) > 12 {
+			hash = hash[:12]
+		}
+		fmt.Fprintf(w, "%6d  %-16s  %-8s  %6d  %-12s  %s\n",
+			d.ID, d.CreatedAt.Local().Format("2006-01-02 15:04"), formatStatsDuration(d.Since),
+			d.TotalPosts, hash, formatTierCounts(d.Counts))
+	}
+}
+
+// formatTierCounts lists counts by tier, read_now first and ignore last:
+// "read_now 2 · skim 5 · ignore 33". Custom tiers sort by name between.
+func formatTierCounts(counts map[string]int) string {
+	tiers := make([]string, 0, len(counts))
+	for tier := range counts {
+		tiers = append(tiers, tier)
+	}
+	rank := func(tier string) int {
+		switch tier {
+		case taste.TierReadNow:
+			return 0
+		case taste.TierIgnore:
+			return 2
+		}
+		return 1
+	}
+	sort.Slice(tiers, func(i, j int) bool {
+		if ri, rj := rank(tiers[i]), rank(tiers[j]); ri != rj {
+			return ri < rj
+		}
+		return tiers[i] < tiers[j]
+	})
+	parts := make([]string, len(tiers))
+	for i, tier := range tiers {
+		parts[i] = fmt.Sprintf("%s %d", tier, counts[tier])
+	}
+	return strings.Join(parts, " · ")
+}
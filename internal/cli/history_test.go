@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func TestDigestSaveAndHistory(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	scriptPath := filepath.Join(tmpDir, "forge-plan.sh")
+
+	writeTestForgePlanScript(t, scriptPath)
+	writeTestConfig(t, tmpDir, dbPath, scriptPath)
+	writeTestTaste(t, tmpDir)
+
+	oldConfigDir, oldSince, oldFormat, oldNoColor := configDir, digestSince, digestFormat, noColor
+	oldOutput, oldSave, oldNewOnly, oldHistFormat := digestOutput, digestSave, digestNewOnly, historyFormat
+	t.Cleanup(func() {
+		configDir, digestSince, digestFormat, noColor = oldConfigDir, oldSince, oldFormat, oldNoColor
+		digestOutput, digestSave, digestNewOnly, historyFormat = oldOutput, oldSave, oldNewOnly, oldHistFormat
+	})
+	configDir = tmpDir
+	digestSince, digestFormat, noColor = "", "markdown", true
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	if _, err := captureStdout(t, func() error { return pullAction(cmd, nil) }); err != nil {
+		t.Fatalf("pull action: %v", err)
+	}
+
+	digestOutput = filepath.Join(tmpDir, "digest.md")
+	digestSave = true
+	if err := digestAction(cmd, nil); err != nil {
+		t.Fatalf("digest: %v", err)
+	}
+	rendered, err := os.ReadFile(digestOutput)
+	if err != nil {
+		t.Fatalf("read digest: %v", err)
+	}
+
+	// A taste profile change must not change what history shows.
+	if err := os.WriteFile(filepath.Join(tmpDir, config.DefaultTasteFile), []byte("weights: {}\n"), 0o644); err != nil {
+		t.Fatalf("write taste: %v", err)
+	}
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := historyAction(cmd, nil); err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	sum := sha256.Sum256(rendered)
+	requireContains(t, buf.String(), hex.EncodeToString(sum[:])[:12])
+	requireContains(t, buf.String(), "read_now 1 · skim 1 · ignore 1")
+
+	buf.Reset()
+	historyFormat = "markdown"
+	if err := historyAction(cmd, []string{"1"}); err != nil {
+		t.Fatalf("history show: %v", err)
+	}
+	if buf.String() != string(rendered) {
+		t.Errorf("re-rendered digest differs:\n%s\nwant:\n%s", buf.String(), rendered)
+	}
+
+	if err := historyAction(cmd, []string{"2"}); err == nil {
+		t.Fatal("expected error for unknown digest ID")
+	}
+
+	// --new-only leaves out what the saved digest listed; ignored posts
+	// were only counted, so they stay.
+	writeTestTaste(t, tmpDir)
+	digestSave, digestNewOnly = false, true
+	digestOutput = filepath.Join(tmpDir, "new.md")
+	if err := digestAction(cmd, nil); err != nil {
+		t.Fatalf("new-only digest: %v", err)
+	}
+	fresh, err := os.ReadFile(digestOutput)
+	if err != nil {
+		t.Fatalf("read new-only digest: %v", err)
+	}
+	if strings.Contains(string(fresh), "Read Now") || strings.Contains(string(fresh), "Skim (") {
+		t.Errorf("new-only digest repeats saved items:\n%s", fresh)
+	}
+	requireContains(t, string(fresh), "*Ignored: 1 posts*")
+}
+
+func TestPruneSavedDigests(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "noisepan.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	for _, age := range []int{1, 5, 9} {
+		if _, err := st.SaveDigest(ctx, store.DigestSnapshot{CreatedAt: now.AddDate(0, 0, -age), Input: []byte("{}")}); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+
+	if n, err := pruneSavedDigests(ctx, st, config.StorageConfig{}, now); err != nil || n != 0 {
+		t.Fatalf("retain forever pruned %d, %v", n, err)
+	}
+	n, err := pruneSavedDigests(ctx, st, config.StorageConfig{RetainDays: 3, SlimDays: 3}, now)
+	if err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("pruned %d digests, want 1", n)
+	}
+}
+
+func TestFormatTierCounts(t *testing.T) {
+	got := formatTierCounts(map[string]int{"ignore": 3, "skim": 2, "read_now": 1, "later": 4})
+	if want := "read_now 1 · later 4 · skim 2 · ignore 3"; got != want {
+		t.Errorf("formatTierCounts = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/digest"
+	"github.com/ppiankov/noisepan/internal/source"
+	"github.com/ppiankov/noisepan/internal/store"
+)
+
+// hookPost is a post as the pre_score hook reads and writes it. The hook
+// prints a JSON array of these back; ID picks the post, Text replaces the
+// text that is scored (the stored post is unchanged) and Labels are added
+// to the labels the taste profile assigns.
+type hookPost struct {
+	ID       int64     `json:"id"`
+	Source   string    `json:"source"`
+	Channel  string    `json:"channel"`
+	URL      string    `json:"url,omitempty"`
+	Text     string    `json:"text"`
+	PostedAt time.Time `json:"posted_at"`
+	Labels   []string  `json:"labels,omitempty"`
+}
+
+// runHook runs script with input on stdin and returns its stdout.
+func runHook(ctx context.Context, script string, timeout time.Duration, input []byte) ([]byte, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	name, args, err := source.ScriptCommand(script)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("run %s: %w (stderr: %s)", script, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// runPreScore passes posts to the pre_score hook and keeps its changes for
+// scorePost. A failing hook or unparsable output is logged and the posts are
+// scored as stored, so a broken enrichment script never blocks a digest.
+func (ps *postScorer) runPreScore(ctx context.Context, posts []store.Post) {
+	ps.hooked = nil
+	if ps.preScoreHook == "" || len(posts) == 0 {
+		return
+	}
+
+	in := make([]hookPost, 0, len(posts))
+	for _, p := range posts {
+		sp := storePostToSourcePost(p)
+		in = append(in, hookPost{
+			ID:       p.ID,
+			Source:   p.Source,
+			Channel:  p.Channel,
+			URL:      p.URL,
+			Text:     sp.Text,
+			PostedAt: p.PostedAt,
+		})
+	}
+	data, err := json.Marshal(in)
+	if err != nil {
+		slog.Warn("pre_score hook skipped", "err", err)
+		return
+	}
+
+	out, err := runHook(ctx, ps.preScoreHook, ps.hookTimeout, data)
+	if err != nil {
+		slog.Warn("pre_score hook failed", "err", err)
+		return
+	}
+	// No output means the hook only observed.
+	if len(bytes.TrimSpace(out)) == 0 {
+		return
+	}
+	var changed []hookPost
+	if err := json.Unmarshal(out, &changed); err != nil {
+		slog.Warn("pre_score hook output ignored", "err", fmt.Errorf("parse json: %w", err))
+		return
+	}
+	ps.hooked = make(map[int64]hookPost, len(changed))
+	for _, hp := range changed {
+		ps.hooked[hp.ID] = hp
+	}
+}
+
+// runPostDigest passes the digest, rendered as JSON, to the post_digest hook.
+func runPostDigest(ctx context.Context, script string, timeout time.Duration, opts digest.JSONOptions, input digest.DigestInput) error {
+	var buf bytes.Buffer
+	if err := digest.NewJSONWithOptions(opts).Format(&buf, input); err != nil {
+		return fmt.Errorf("format json: %w", err)
+	}
+	_, err := runHook(ctx, script, timeout, buf.Bytes())
+	return err
+}
+
+// mergeLabels appends the labels in extra that labels does not have yet.
+func mergeLabels(labels, extra []string) []string {
+	for _, l := range extra {
+		if l != "" && !slices.Contains(labels, l) {
+			labels = append(labels, l)
+		}
+	}
+	return labels
+}
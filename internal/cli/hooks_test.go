@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/digest"
+	"github.com/ppiankov/noisepan/internal/source"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/summarize"
+	"github.com/ppiankov/noisepan/internal/taste"
+)
+
+func writeTestHook(t *testing.T, dir, name, body string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0o755); err != nil {
+		t.Fatalf("write test hook: %v", err)
+	}
+	return path
+}
+
+func TestPostScorer_PreScoreHook(t *testing.T) {
+	dir := t.TempDir()
+	seen := filepath.Join(dir, "seen.json")
+	hook := writeTestHook(t, dir, "enrich.sh", `cat > "`+seen+`"
+echo '[{"id": 1, "text": "CVE-2026-9 in the wild", "labels": ["enriched"]}]'`)
+
+	ps := &postScorer{profile: testScorerProfile(), preScoreHook: hook, hookTimeout: 5 * time.Second}
+	posts := []store.Post{
+		{ID: 1, Source: "rss", Channel: "News", Text: "Vendor update"},
+		{ID: 2, Source: "rss", Channel: "News", Snippet: "Another update"},
+	}
+	ps.runPreScore(context.Background(), posts)
+
+	data, err := os.ReadFile(seen)
+	if err != nil {
+		t.Fatalf("read hook input: %v", err)
+	}
+	var in []hookPost
+	if err := json.Unmarshal(data, &in); err != nil {
+		t.Fatalf("parse hook input: %v", err)
+	}
+	if len(in) != 2 || in[0].ID != 1 || in[1].Text != "Another update" {
+		t.Errorf("hook input = %+v", in)
+	}
+
+	sp := ps.scorePost(posts[0])
+	if sp.Score != 5 || !slices.Contains(sp.Labels, "enriched") {
+		t.Errorf("hooked post = score %d labels %v, want 5 with enriched", sp.Score, sp.Labels)
+	}
+	if sp := ps.scorePost(posts[1]); sp.Score != 0 || sp.Tier != taste.TierIgnore {
+		t.Errorf("untouched post = score %d tier %s", sp.Score, sp.Tier)
+	}
+}
+
+func TestPostScorer_PreScoreHookLabelTiers(t *testing.T) {
+	dir := t.TempDir()
+	hook := writeTestHook(t, dir, "enrich.sh", `cat > /dev/null
+echo '[{"id": 1, "labels": ["critical"]}]'`)
+
+	profile := testScorerProfile()
+	profile.LabelTiers = map[string]config.LabelTier{"critical": {MinTier: taste.TierReadNow}}
+	ps := &postScorer{profile: profile, preScoreHook: hook, hookTimeout: 5 * time.Second}
+	posts := []store.Post{{ID: 1, Source: "rss", Channel: "News", Text: "Vendor update"}}
+	ps.runPreScore(context.Background(), posts)
+
+	// label_tiers bound a hook's labels regardless of the score.
+	if sp := ps.scorePost(posts[0]); sp.Tier != taste.TierReadNow {
+		t.Errorf("critical-labeled post = score %d tier %s, want read_now", sp.Score, sp.Tier)
+	}
+}
+
+func TestPostScorer_PreScoreHookFailure(t *testing.T) {
+	dir := t.TempDir()
+	posts := []store.Post{{ID: 1, Text: "CVE-2026-1 patched"}}
+	for name, body := range map[string]string{
+		"fail.sh":    "echo boom >&2; exit 1",
+		"garbage.sh": "echo not json",
+		"silent.sh":  "cat > /dev/null",
+	} {
+		ps := &postScorer{profile: testScorerProfile(), preScoreHook: writeTestHook(t, dir, name, body), hookTimeout: 5 * time.Second}
+		ps.runPreScore(context.Background(), posts)
+		if sp := ps.scorePost(posts[0]); sp.Score != 5 {
+			t.Errorf("%s: score = %d, want stored text scored", name, sp.Score)
+		}
+	}
+}
+
+func TestRunPostDigest(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "digest.json")
+	hook := writeTestHook(t, dir, "notify.sh", `cat > "`+out+`"`)
+
+	input := digest.DigestInput{
+		Items: []digest.DigestItem{{
+			PostID:     7,
+			ScoredPost: taste.ScoredPost{Post: source.Post{Channel: "News", Text: "CVE"}, Score: 9, Tier: taste.TierReadNow},
+			Summary:    summarize.Summary{Bullets: []string{"CVE"}},
+		}},
+		Channels:   1,
+		TotalPosts: 1,
+		Since:      24 * time.Hour,
+	}
+	if err := runPostDigest(context.Background(), hook, 5*time.Second, digest.JSONOptions{}, input); err != nil {
+		t.Fatalf("runPostDigest: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read hook input: %v", err)
+	}
+	if !json.Valid(data) {
+		t.Fatalf("hook input is not JSON:\n%s", data)
+	}
+	requireContains(t, string(data), `"News"`)
+
+	if err := runPostDigest(context.Background(), writeTestHook(t, dir, "fail.sh", "exit 3"), 5*time.Second, digest.JSONOptions{}, input); err == nil {
+		t.Error("expected error from failing hook")
+	}
+}
@@ -12,8 +12,6 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-var importDryRun bool
-
 var importCmd = &cobra.Command{
 	Use:   "import <file.opml>",
 	Short: "Import RSS feeds from an OPML file",
@@ -22,7 +20,6 @@ var importCmd = &cobra.Command{
 }
 
 func init() {
-	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "show what would be added without modifying config")
 	rootCmd.AddCommand(importCmd)
 }
 
@@ -85,7 +82,7 @@ func importAction(_ *cobra.Command, args []string) error {
 		return nil
 	}
 
-	if importDryRun {
+	if dryRun {
 		fmt.Printf("Would add %d feeds (skipping %d duplicates):\n", len(newFeeds), skipped)
 		for _, f := range newFeeds {
 			fmt.Printf("  + %s\n", f)
@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/taste"
+	"github.com/spf13/cobra"
+)
+
+var importPostsSkipScores bool
+
+var importPostsCmd = &cobra.Command{
+	Use:   "import-posts <file.jsonl>",
+	Short: "Load posts written by export --format jsonl",
+	Long: `Loads posts from a JSON lines file written by "noisepan export --format
+jsonl" (use - for stdin), to move a corpus between machines or backfill from
+another tool producing the same fields. Posts are upserted by source,
+channel and external_id, so importing a file twice changes nothing.
+
+Saved scores and feedback are restored with each post unless --skip-scores
+is given, in which case the next digest scores the posts against the local
+taste profile. Text goes through the same redaction and store_full_text
+handling as pulled posts.`,
+	Args: cobra.ExactArgs(1),
+	RunE: importPostsAction,
+}
+
+func init() {
+	importPostsCmd.Flags().BoolVar(&importPostsSkipScores, "skip-scores", false, "import posts only; drop saved scores and feedback")
+	rootCmd.AddCommand(importPostsCmd)
+}
+
+// importPostsMaxLine bounds one JSON line; long posts with explanations
+// easily exceed bufio.Scanner's 64 KiB default.
+const importPostsMaxLine = 16 << 20
+
+// importCounts tallies what import-posts wrote.
+type importCounts struct {
+	posts, scores, feedback int
+}
+
+func importPostsAction(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(configDir)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	redact, err := compileRedact(cfg.Privacy)
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader = cmd.InOrStdin()
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("open import file: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		r = f
+	}
+
+	db, err := openStore(cfg)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	counts, err := importPosts(cmd.Context(), db, r, cfg.Privacy, redact, !importPostsSkipScores, time.Now())
+	fmt.Fprintf(cmd.OutOrStdout(), "Imported %d posts (%d scores, %d feedback)\n", counts.posts, counts.scores, counts.feedback)
+	return err
+}
+
+// importPosts upserts every record in r. It stops at the first bad line,
+// keeping the posts imported before it.
+func importPosts(ctx context.Context, db *store.Store, r io.Reader, pc config.PrivacyConfig, redact []*regexp.Regexp, withScores bool, now time.Time) (importCounts, error) {
+	var counts importCounts
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), importPostsMaxLine)
+	line := 0
+	for sc.Scan() {
+		line++
+		data := bytes.TrimSpace(sc.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		var rec exportRecord
+		if err := json.Unmarshal(data, &rec); err != nil {
+			return counts, fmt.Errorf("line %d: %w", line, err)
+		}
+		if err := validateImportRecord(rec); err != nil {
+			return counts, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		storeText, snippet := storedText(rec.Text, pc, redact)
+		fetchedAt := rec.FetchedAt
+		if fetchedAt.IsZero() {
+			fetchedAt = now
+		}
+		post, err := db.InsertPost(ctx, store.PostInput{
+			Source:     rec.Source,
+			Channel:    rec.Channel,
+			ExternalID: rec.ExternalID,
+			Text:       storeText,
+			Snippet:    snippet,
+			URL:        rec.URL,
+			Author:     rec.Author,
+			Language:   importLanguage(rec),
+			PostedAt:   rec.PostedAt,
+			FetchedAt:  fetchedAt,
+		})
+		if err != nil {
+			return counts, fmt.Errorf("line %d: insert post: %w", line, err)
+		}
+		counts.posts++
+
+		if !withScores {
+			continue
+		}
+		if rec.Score != nil && rec.Tier != "" {
+			if err := db.SaveScore(ctx, store.Score{
+				PostID:      post.ID,
+				Score:       *rec.Score,
+				Labels:      rec.Labels,
+				Tier:        rec.Tier,
+				ScoredAt:    now,
+				Explanation: rec.Explanation,
+			}); err != nil {
+				return counts, fmt.Errorf("line %d: save score: %w", line, err)
+			}
+			counts.scores++
+		}
+		if rec.Feedback != "" {
+			vote := store.FeedbackDown
+			if rec.Feedback == "up" {
+				vote = store.FeedbackUp
+			}
+			if err := db.SaveFeedback(ctx, store.Feedback{PostID: post.ID, Vote: vote, Reason: rec.Reason, CreatedAt: now}); err != nil {
+				return counts, fmt.Errorf("line %d: save feedback: %w", line, err)
+			}
+			counts.feedback++
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return counts, fmt.Errorf("read import file: %w", err)
+	}
+	return counts, nil
+}
+
+// validateImportRecord rejects records InsertPost cannot key, with a hint
+// for the common mistake of importing training samples.
+func validateImportRecord(rec exportRecord) error {
+	if rec.ExternalID == "" || rec.PostedAt.IsZero() {
+		return errors.New("missing external_id or posted_at (export with --format jsonl, not samples)")
+	}
+	switch rec.Feedback {
+	case "", "up", "down":
+	default:
+		return fmt.Errorf("unknown feedback %q (want up or down)", rec.Feedback)
+	}
+	return nil
+}
+
+// importLanguage is the record's language, or the one detected from its
+// text for archives written before export recorded it.
+func importLanguage(rec exportRecord) string {
+	if rec.Language != "" {
+		return rec.Language
+	}
+	return taste.DetectLanguage(rec.Text)
+}
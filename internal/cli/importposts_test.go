@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/taste"
+)
+
+func TestImportPosts_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	src := openStoreForPipelineTest(t, filepath.Join(t.TempDir(), "src.db"))
+	p, err := src.InsertPost(ctx, store.PostInput{
+		Source: "telegram", Channel: "@ops", ExternalID: "42", URL: "https://t.me/ops/42",
+		Text: "Kubernetes CVE patched", PostedAt: now.Add(-time.Hour), FetchedAt: now,
+	})
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if err := src.SaveScore(ctx, store.Score{PostID: p.ID, Score: 8, Tier: taste.TierReadNow, Labels: []string{"security"}, ScoredAt: now,
+		Explanation: json.RawMessage(`[{"reason":"high_signal: cve","points":5}]`)}); err != nil {
+		t.Fatalf("save score: %v", err)
+	}
+	if err := src.SaveFeedback(ctx, store.Feedback{PostID: p.ID, Vote: store.FeedbackUp, Reason: "useful", CreatedAt: now}); err != nil {
+		t.Fatalf("save feedback: %v", err)
+	}
+	if _, err := src.InsertPost(ctx, store.PostInput{
+		Source: "rss", Channel: "Blog", ExternalID: "b", Text: "unscored", PostedAt: now.Add(-2 * time.Hour), FetchedAt: now,
+	}); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	posts, err := src.GetPosts(ctx, time.Time{}, "", store.PostFilter{Order: store.OrderOldest})
+	if err != nil {
+		t.Fatalf("get posts: %v", err)
+	}
+	votes, err := src.GetFeedback(ctx, time.Time{})
+	if err != nil {
+		t.Fatalf("get feedback: %v", err)
+	}
+	var file bytes.Buffer
+	if err := writeExportRecordsJSONL(&file, buildExportRecords(posts, votes, nil)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	dst := openStoreForPipelineTest(t, filepath.Join(t.TempDir(), "dst.db"))
+	for run := 1; run <= 2; run++ {
+		counts, err := importPosts(ctx, dst, bytes.NewReader(file.Bytes()), config.PrivacyConfig{StoreFullText: true}, nil, true, now)
+		if err != nil {
+			t.Fatalf("run %d: import: %v", run, err)
+		}
+		if counts != (importCounts{posts: 2, scores: 1, feedback: 1}) {
+			t.Errorf("run %d: counts = %+v", run, counts)
+		}
+	}
+
+	got, err := dst.GetPosts(ctx, time.Time{}, "", store.PostFilter{Order: store.OrderOldest})
+	if err != nil {
+		t.Fatalf("get imported posts: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("imported %d posts, want 2 (import must be idempotent)", len(got))
+	}
+	tg := got[1]
+	if tg.Post.ExternalID != "42" || tg.Post.Text != "Kubernetes CVE patched" || !tg.Post.PostedAt.Equal(now.Add(-time.Hour)) {
+		t.Errorf("post = %+v", tg.Post)
+	}
+	if tg.Score == nil || tg.Score.Score != 8 || tg.Score.Tier != taste.TierReadNow || !strings.Contains(string(tg.Score.Explanation), "high_signal") {
+		t.Errorf("score = %+v", tg.Score)
+	}
+	if got[0].Score != nil {
+		t.Errorf("unscored post got score %+v", got[0].Score)
+	}
+	fb, err := dst.GetFeedback(ctx, time.Time{})
+	if err != nil {
+		t.Fatalf("get feedback: %v", err)
+	}
+	if len(fb) != 1 || fb[0].Vote != store.FeedbackUp || fb[0].Reason != "useful" {
+		t.Errorf("feedback = %+v", fb)
+	}
+}
+
+func TestImportPosts_SnippetOnlyAndSkipScores(t *testing.T) {
+	ctx := context.Background()
+	db := openStoreForPipelineTest(t, filepath.Join(t.TempDir(), "noisepan.db"))
+	line := `{"id":9,"source":"rss","channel":"Blog","external_id":"x","posted_at":"2026-03-01T10:00:00Z","text":"` +
+		strings.Repeat("long ", 60) + `","score":3,"tier":"skim"}` + "\n"
+
+	counts, err := importPosts(ctx, db, strings.NewReader(line), config.PrivacyConfig{}, nil, false, time.Now())
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if counts != (importCounts{posts: 1}) {
+		t.Errorf("counts = %+v", counts)
+	}
+	got, err := db.GetPosts(ctx, time.Time{}, "")
+	if err != nil || len(got) != 1 {
+		t.Fatalf("get posts = %d, %v", len(got), err)
+	}
+	if got[0].Post.Text != "" || len([]rune(got[0].Post.Snippet)) > 200 || !strings.HasPrefix(got[0].Post.Snippet, "long long") || got[0].Score != nil {
+		t.Errorf("post = %+v, score = %+v", got[0].Post, got[0].Score)
+	}
+}
+
+func TestImportPosts_BadLine(t *testing.T) {
+	ctx := context.Background()
+	db := openStoreForPipelineTest(t, filepath.Join(t.TempDir(), "noisepan.db"))
+	in := `{"source":"rss","channel":"Blog","external_id":"a","posted_at":"2026-03-01T10:00:00Z","text":"ok"}
+
+{"id":1,"source":"rss","channel":"Blog","text":"a training sample","tier":"skim","score":3}
+`
+	counts, err := importPosts(ctx, db, strings.NewReader(in), config.PrivacyConfig{StoreFullText: true}, nil, true, time.Now())
+	if err == nil || !strings.Contains(err.Error(), "line 3") || !strings.Contains(err.Error(), "--format jsonl") {
+		t.Fatalf("err = %v, want line 3 hint", err)
+	}
+	if counts.posts != 1 {
+		t.Errorf("posts before bad line = %d, want 1", counts.posts)
+	}
+}
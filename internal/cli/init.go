@@ -16,6 +16,12 @@ var initCmd = &cobra.Command{
 }
 
 func initAction(_ *cobra.Command, _ []string) error {
+	return initConfigDir(exampleConfig)
+}
+
+// initConfigDir writes configYAML (as config.yaml) and the example taste
+// profile into the config directory, leaving files that exist alone.
+func initConfigDir(configYAML string) error {
 	if err := os.MkdirAll(configDir, 0o755); err != nil {
 		return fmt.Errorf("create config dir: %w", err)
 	}
@@ -23,7 +29,7 @@ func initAction(_ *cobra.Command, _ []string) error {
 	created := 0
 
 	configPath := filepath.Join(configDir, config.DefaultConfigFile)
-	wrote, err := writeIfNotExists(configPath, []byte(exampleConfig))
+	wrote, err := writeIfNotExists(configPath, []byte(configYAML))
 	if err != nil {
 		return err
 	}
@@ -73,6 +79,7 @@ sources:
     # script: /path/to/collector_telegram.py
     python_path: ""
     # python_path: ~/.noisepan/venv/bin/python
+    # python_path: ~/.noisepan/venv/Scripts/python.exe   # Windows
     channels:
       - "@your_channel_here"
   rss:
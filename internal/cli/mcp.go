@@ -0,0 +1,224 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/mcp"
+	"github.com/ppiankov/noisepan/internal/server"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultMCPChannelsSince = 30 * 24 * time.Hour
+	defaultMCPSearchLimit   = 20
+	maxMCPSearchLimit       = 200
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Serve the scored corpus to LLM assistants over MCP (stdio)",
+	Long: `Speaks the Model Context Protocol on stdin and stdout, so an assistant
+such as an MCP-capable desktop client or editor can query the store. Tools:
+
+  search_posts       full-text search, best match first
+  get_digest         read_now and skim posts of a time window, as digest shows
+  explain_post       a post's text and scoring breakdown
+  get_channel_stats  per-channel tier split
+
+Register it with the client as the command "noisepan mcp --config DIR".
+Posts not scored yet are scored on demand, as the dashboard does; nothing
+else is written. Logs go to stderr.`,
+	Args: cobra.NoArgs,
+	RunE: mcpAction,
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+}
+
+func mcpAction(cmd *cobra.Command, _ []string) error {
+	cfg, err := config.Load(configDir)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	tastePath := profileTastePath(cfg)
+	profile, err := loadTaste(cfg, tastePath)
+	if err != nil {
+		return fmt.Errorf("load taste: %w", err)
+	}
+
+	db, err := openStore(cfg)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	scorer, err := newPostScorer(cfg, profile)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := scorer.loadBoilerplate(ctx, db); err != nil {
+		return fmt.Errorf("load boilerplate: %w", err)
+	}
+
+	backend := &dashboardBackend{db: db, scorer: scorer, notes: cfg.Channels}
+	srv := mcp.New("noisepan", Version, mcpTools(backend, cfg.Digest.Since.Duration)...)
+	return srv.Serve(ctx, cmd.InOrStdin(), cmd.OutOrStdout())
+}
+
+// mcpTools returns the tools served by "noisepan mcp". get_digest defaults
+// to digestSince, like the digest command.
+func mcpTools(b server.Backend, digestSince time.Duration) []mcp.Tool {
+	sinceProp := func(def string) map[string]any {
+		return map[string]any{
+			"type":        "string",
+			"description": fmt.Sprintf("time window, e.g. 48h or 7d (default %s)", def),
+		}
+	}
+
+	return []mcp.Tool{
+		{
+			Name:        "search_posts",
+			Description: "Full-text search over stored posts, best match first. Returns each post's id, source, channel, URL, time, snippet, score, tier, and labels.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"query": map[string]any{"type": "string", "description": "search terms; quote phrases"},
+					"limit": map[string]any{"type": "integer", "description": fmt.Sprintf("maximum results (default %d, at most %d)", defaultMCPSearchLimit, maxMCPSearchLimit)},
+				},
+				"required": []string{"query"},
+			},
+			Handler: func(ctx context.Context, raw json.RawMessage) (any, error) {
+				var args struct {
+					Query string `json:"query"`
+					Limit int    `json:"limit"`
+				}
+				if err := mcp.DecodeArgs(raw, &args); err != nil {
+					return nil, err
+				}
+				if strings.TrimSpace(args.Query) == "" {
+					return nil, errors.New("query is required")
+				}
+				limit := defaultMCPSearchLimit
+				if args.Limit > 0 {
+					limit = min(args.Limit, maxMCPSearchLimit)
+				}
+				items, err := b.Search(ctx, args.Query, limit)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]any{"query": args.Query, "results": nonNilItems(items)}, nil
+			},
+		},
+		{
+			Name:        "get_digest",
+			Description: "The digest of a time window: read_now and skim posts, highest score first, and how many posts were ignored.",
+			InputSchema: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"since": sinceProp(formatMCPDuration(digestSince))},
+			},
+			Handler: func(ctx context.Context, raw json.RawMessage) (any, error) {
+				since, err := mcpSince(raw, digestSince)
+				if err != nil {
+					return nil, err
+				}
+				d, err := b.Digest(ctx, since)
+				if err != nil {
+					return nil, err
+				}
+				d.ReadNow, d.Skim = nonNilItems(d.ReadNow), nonNilItems(d.Skim)
+				return d, nil
+			},
+		},
+		{
+			Name:        "explain_post",
+			Description: "One post by id: its full text, score, tier, labels, channels it was also posted in, and the scoring breakdown (each keyword, rule, or adjustment with its points).",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"id": map[string]any{"type": "integer", "description": "post id, as returned by search_posts or get_digest"},
+				},
+				"required": []string{"id"},
+			},
+			Handler: func(ctx context.Context, raw json.RawMessage) (any, error) {
+				var args struct {
+					ID int64 `json:"id"`
+				}
+				if err := mcp.DecodeArgs(raw, &args); err != nil {
+					return nil, err
+				}
+				if args.ID < 1 {
+					return nil, errors.New("id is required")
+				}
+				return b.Post(ctx, args.ID)
+			},
+		},
+		{
+			Name:        "get_channel_stats",
+			Description: "Per-channel post counts and tier split (read_now, skim, ignored) over a time window, to judge which channels carry signal.",
+			InputSchema: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"since": sinceProp(formatMCPDuration(defaultMCPChannelsSince))},
+			},
+			Handler: func(ctx context.Context, raw json.RawMessage) (any, error) {
+				since, err := mcpSince(raw, defaultMCPChannelsSince)
+				if err != nil {
+					return nil, err
+				}
+				stats, err := b.Channels(ctx, since)
+				if err != nil {
+					return nil, err
+				}
+				if stats == nil {
+					stats = []server.ChannelStat{}
+				}
+				return map[string]any{"since": since.String(), "channels": stats}, nil
+			},
+		},
+	}
+}
+
+// mcpSince reads the optional since argument.
+func mcpSince(raw json.RawMessage, def time.Duration) (time.Duration, error) {
+	var args struct {
+		Since string `json:"since"`
+	}
+	if err := mcp.DecodeArgs(raw, &args); err != nil {
+		return 0, err
+	}
+	if args.Since == "" {
+		return def, nil
+	}
+	d, err := parseDuration(args.Since)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid since %q", args.Since)
+	}
+	return d, nil
+}
+
+func formatMCPDuration(d time.Duration) string {
+	if d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	return d.String()
+}
+
+// nonNilItems keeps empty lists encoding as [] rather than null.
+func nonNilItems(items []server.Item) []server.Item {
+	if items == nil {
+		return []server.Item{}
+	}
+	return items
+}
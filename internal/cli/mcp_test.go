@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/mcp"
+	"github.com/ppiankov/noisepan/internal/store"
+)
+
+func TestMCPTools(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "noisepan.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, p := range []struct{ id, text string }{
+		{"hot", "cve OpenSSL exploited"},
+		{"dull", "office hours moved"},
+	} {
+		if _, err := st.InsertPost(ctx, store.PostInput{
+			Source: "rss", Channel: "security", ExternalID: p.id,
+			Text: p.text, PostedAt: now, FetchedAt: now,
+		}); err != nil {
+			t.Fatalf("insert %s: %v", p.id, err)
+		}
+	}
+
+	profile := testScorerProfile()
+	profile.Weights.HighSignal["exploited"] = 3
+	b := &dashboardBackend{db: st, scorer: &postScorer{profile: profile}}
+	srv := mcp.New("noisepan", "test", mcpTools(b, 24*time.Hour)...)
+
+	calls := []string{
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_digest","arguments":{"since":"1d"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"search_posts","arguments":{"query":"openssl"}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"explain_post","arguments":{"id":1}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"get_channel_stats","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"explain_post","arguments":{"id":99}}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"tools/call","params":{"name":"get_digest","arguments":{"since":"soon"}}}`,
+	}
+	var out bytes.Buffer
+	if err := srv.Serve(ctx, strings.NewReader(strings.Join(calls, "\n")), &out); err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+
+	var texts []string
+	var errs []bool
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var resp struct {
+			Result struct {
+				Content []struct {
+					Text string `json:"text"`
+				} `json:"content"`
+				IsError bool `json:"isError"`
+			} `json:"result"`
+		}
+		if err := dec.Decode(&resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		texts = append(texts, resp.Result.Content[0].Text)
+		errs = append(errs, resp.Result.IsError)
+	}
+	if len(texts) != len(calls) {
+		t.Fatalf("got %d responses, want %d", len(texts), len(calls))
+	}
+
+	for i, want := range [][]string{
+		{`"read_now": [`, `"cve OpenSSL exploited"`, `"ignored": 1`},
+		{`"query": "openssl"`, `"channel": "security"`},
+		{`"text": "cve OpenSSL exploited"`, `"explanation": [`, `"reason"`},
+		{`"channels": [`, `"total": 2`},
+		{"post 99", "not found"},
+		{`invalid since "soon"`},
+	} {
+		for _, w := range want {
+			if !strings.Contains(texts[i], w) {
+				t.Errorf("call %d missing %q:\n%s", i+1, w, texts[i])
+			}
+		}
+		if wantErr := i >= 4; errs[i] != wantErr {
+			t.Errorf("call %d isError = %v, want %v", i+1, errs[i], wantErr)
+		}
+	}
+}
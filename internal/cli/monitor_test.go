@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/spf13/cobra"
+)
+
+type pingRecorder struct {
+	mu    sync.Mutex
+	paths []string
+	body  string
+}
+
+func newPingServer(t *testing.T) (*httptest.Server, *pingRecorder) {
+	t.Helper()
+	rec := &pingRecorder{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		rec.mu.Lock()
+		rec.paths = append(rec.paths, r.URL.RequestURI())
+		rec.body = string(data)
+		rec.mu.Unlock()
+	}))
+	t.Cleanup(srv.Close)
+	return srv, rec
+}
+
+func TestSendPing(t *testing.T) {
+	srv, rec := newPingServer(t)
+	cfg := &config.Config{Monitoring: config.MonitoringConfig{PingURL: srv.URL + "/0b1c/?create=1"}}
+
+	if err := sendPing(context.Background(), cfg, nil); err != nil {
+		t.Fatalf("success ping: %v", err)
+	}
+	if err := sendPing(context.Background(), cfg, errors.New("pull rss: connection refused")); err != nil {
+		t.Fatalf("fail ping: %v", err)
+	}
+
+	if want := []string{"/0b1c/?create=1", "/0b1c/fail?create=1"}; strings.Join(rec.paths, " ") != strings.Join(want, " ") {
+		t.Errorf("paths = %v, want %v", rec.paths, want)
+	}
+	if rec.body != "pull rss: connection refused" {
+		t.Errorf("fail body = %q", rec.body)
+	}
+}
+
+func TestSendPing_ErrorStatusHidesURL(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(srv.Close)
+	cfg := &config.Config{Monitoring: config.MonitoringConfig{PingURL: srv.URL + "/secret-key"}}
+
+	err := sendPing(context.Background(), cfg, nil)
+	if err == nil || !strings.Contains(err.Error(), "HTTP 404") {
+		t.Fatalf("err = %v, want HTTP 404", err)
+	}
+	if strings.Contains(err.Error(), "secret-key") {
+		t.Errorf("error leaks the ping URL: %v", err)
+	}
+}
+
+func TestPingMonitor(t *testing.T) {
+	srv, rec := newPingServer(t)
+	dir := t.TempDir()
+	content := "sources:\n  rss:\n    feeds: [\"https://example.com/feed\"]\n" +
+		"monitoring:\n  ping_url: \"" + srv.URL + "/check\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	oldConfigDir := configDir
+	t.Cleanup(func() { configDir, dryRun = oldConfigDir, false })
+	configDir = dir
+
+	cmd := &cobra.Command{}
+	runErr := errors.New("digest failed")
+	if err := pingMonitor(cmd, runErr); err != runErr {
+		t.Errorf("pingMonitor returned %v, want the run error", err)
+	}
+	if err := pingMonitor(cmd, nil); err != nil {
+		t.Errorf("pingMonitor returned %v, want nil", err)
+	}
+
+	// Nothing leaves the machine on a dry run.
+	dryRun = true
+	_ = pingMonitor(cmd, nil)
+
+	if want := "/check/fail /check"; strings.Join(rec.paths, " ") != want {
+		t.Errorf("paths = %v, want %s", rec.paths, want)
+	}
+}
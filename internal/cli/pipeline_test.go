@@ -283,3 +283,98 @@ func requireContains(t *testing.T, got, want string) {
 		t.Fatalf("expected output to contain %q, got:\n%s", want, got)
 	}
 }
+
+func TestPipelineDigestUnreadOnly(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	scriptPath := filepath.Join(tmpDir, "forge-plan.sh")
+
+	writeTestForgePlanScript(t, scriptPath)
+	writeTestConfig(t, tmpDir, dbPath, scriptPath)
+	writeTestTaste(t, tmpDir)
+
+	oldConfigDir, oldSince, oldFormat := configDir, digestSince, digestFormat
+	oldSource, oldChannel, oldNoColor := digestSource, digestChannel, noColor
+	oldUnread, oldMarkRead := digestUnread, digestMarkRead
+	t.Cleanup(func() {
+		configDir, digestSince, digestFormat = oldConfigDir, oldSince, oldFormat
+		digestSource, digestChannel, noColor = oldSource, oldChannel, oldNoColor
+		digestUnread, digestMarkRead = oldUnread, oldMarkRead
+	})
+	configDir = tmpDir
+	digestSince, digestSource, digestChannel = "", "", ""
+	digestFormat = "terminal"
+	noColor = true
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	if _, err := captureStdout(t, func() error { return pullAction(cmd, nil) }); err != nil {
+		t.Fatalf("pull action: %v", err)
+	}
+
+	digestUnread, digestMarkRead = true, true
+	first, err := captureStdout(t, func() error { return digestAction(cmd, nil) })
+	if err != nil {
+		t.Fatalf("first digest: %v", err)
+	}
+	requireContains(t, first, "--- Read Now (1) ---")
+	requireContains(t, first, "--- Skim (1) ---")
+
+	second, err := captureStdout(t, func() error { return digestAction(cmd, nil) })
+	if err != nil {
+		t.Fatalf("second digest: %v", err)
+	}
+	if strings.Contains(second, "Read Now") || strings.Contains(second, "Skim (") {
+		t.Errorf("read items shown again:\n%s", second)
+	}
+	requireContains(t, second, "Ignored: 1 posts")
+
+	// Without --unread-only everything is back.
+	digestUnread, digestMarkRead = false, false
+	all, err := captureStdout(t, func() error { return digestAction(cmd, nil) })
+	if err != nil {
+		t.Fatalf("full digest: %v", err)
+	}
+	requireContains(t, all, "--- Read Now (1) ---")
+}
+
+func TestPipelineDigestGroupBy(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	scriptPath := filepath.Join(tmpDir, "forge-plan.sh")
+
+	writeTestForgePlanScript(t, scriptPath)
+	writeTestConfig(t, tmpDir, dbPath, scriptPath)
+	writeTestTaste(t, tmpDir)
+
+	oldConfigDir, oldSince, oldFormat, oldNoColor, oldGroupBy := configDir, digestSince, digestFormat, noColor, digestGroupBy
+	t.Cleanup(func() {
+		configDir, digestSince, digestFormat, noColor, digestGroupBy = oldConfigDir, oldSince, oldFormat, oldNoColor, oldGroupBy
+	})
+	configDir = tmpDir
+	digestSince, digestFormat, noColor = "", "terminal", true
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	if _, err := captureStdout(t, func() error { return pullAction(cmd, nil) }); err != nil {
+		t.Fatalf("pull action: %v", err)
+	}
+
+	// The read_now and skim posts share their source's section.
+	digestGroupBy = "source"
+	out, err := captureStdout(t, func() error { return digestAction(cmd, nil) })
+	if err != nil {
+		t.Fatalf("grouped digest: %v", err)
+	}
+	requireContains(t, out, "--- forgeplan (2) ---")
+	requireContains(t, out, "Ignored: 1 posts")
+	if strings.Contains(out, "Read Now") || strings.Contains(out, "Skim (") {
+		t.Errorf("grouped digest kept tier sections:\n%s", out)
+	}
+
+	digestGroupBy = "tier"
+	if _, err := captureStdout(t, func() error { return digestAction(cmd, nil) }); err == nil {
+		t.Fatal("expected error for unknown --group-by")
+	}
+}
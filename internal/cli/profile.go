@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"path/filepath"
+
+	"github.com/ppiankov/noisepan/internal/config"
+)
+
+// activeProfile returns the taste profile in use: --profile, else the
+// config's profile, else "" for the default taste.yaml. cfg may be nil.
+func activeProfile(cfg *config.Config) string {
+	if profileName != "" {
+		return profileName
+	}
+	if cfg != nil {
+		return cfg.Profile
+	}
+	return ""
+}
+
+// profileTastePath returns the taste file of the active profile.
+func profileTastePath(cfg *config.Config) string {
+	name := activeProfile(cfg)
+	if name == "" {
+		return filepath.Join(configDir, config.DefaultTasteFile)
+	}
+	return filepath.Join(configDir, config.DefaultProfilesDir, name+".yaml")
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pruneSimulate bool
+	pruneDays     int
+)
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Apply the retention policy now, or simulate a change to it",
+	Long: `Tombstones unstarred posts past their retention, as every pull does:
+storage.retain_days and storage.retention, plus storage.slim_days. Pruned
+posts leave digests, search, and stats; "db purge" deletes them.
+
+--simulate changes nothing and reports, per channel, how many posts and
+scores would be pruned and an estimate of the database size once they are
+purged. --days tries another retain_days; per-source and per-channel rules
+still apply.`,
+	Args: cobra.NoArgs,
+	RunE: pruneAction,
+}
+
+func init() {
+	pruneCmd.Flags().BoolVar(&pruneSimulate, "simulate", false, "report what would be pruned without changing anything")
+	pruneCmd.Flags().IntVar(&pruneDays, "days", 0, "retain_days to apply instead of the configured one")
+	rootCmd.AddCommand(pruneCmd)
+}
+
+func pruneAction(cmd *cobra.Command, _ []string) error {
+	if pruneDays < 0 {
+		return errors.New("--days must not be negative")
+	}
+
+	cfg, err := config.Load(configDir)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	db, err := openStore(cfg)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	sc := cfg.Storage
+	if pruneDays > 0 {
+		sc.RetainDays = pruneDays
+	}
+	policy := retentionPolicy(sc)
+	if sc.SlimDays > 0 {
+		policy = policy.Extend(sc.SlimDays)
+	}
+
+	ctx := cmd.Context()
+	w := cmd.OutOrStdout()
+	if pruneSimulate {
+		return simulatePrune(ctx, w, db, sc, policy)
+	}
+
+	n, err := db.PruneWithPolicy(ctx, policy)
+	if err != nil {
+		return fmt.Errorf("prune old: %w", err)
+	}
+	fmt.Fprintf(w, "Pruned %d posts (\"db restore\" brings them back, \"db purge\" deletes them)\n", n)
+	saved, err := pruneSavedDigests(ctx, db, sc, time.Now())
+	if err != nil {
+		return err
+	}
+	if saved > 0 {
+		fmt.Fprintf(w, "Deleted %d saved digests\n", saved)
+	}
+	return nil
+}
+
+// simulatePrune prints what pruning with policy would remove.
+func simulatePrune(ctx context.Context, w io.Writer, db *store.Store, sc config.StorageConfig, policy store.RetentionPolicy) error {
+	sim, err := db.SimulatePrune(ctx, policy)
+	if err != nil {
+		return err
+	}
+	size, err := db.Size(ctx)
+	if err != nil {
+		return err
+	}
+
+	keep := "forever"
+	if sc.RetainDays > 0 {
+		keep = fmt.Sprintf("%d days", sc.RetainDays)
+		if sc.SlimDays > 0 {
+			keep += fmt.Sprintf(" + %d slim days", sc.SlimDays)
+		}
+	}
+	fmt.Fprintf(w, "Retention: %s, %d per-source/channel rules\n", keep, len(sc.Retention))
+
+	var posts, scores, bytes int64
+	for _, im := range sim.Channels {
+		posts += im.Posts
+		scores += im.Scores
+		bytes += im.Bytes
+	}
+	fmt.Fprintf(w, "Would prune %d of %d posts (%d scores)\n", posts, sim.LivePosts, scores)
+
+	if len(sim.Channels) > 0 {
+		width := len("Channel")
+		for _, im := range sim.Channels {
+			width = max(width, len(im.Source)+1+len(im.Channel))
+		}
+		fmt.Fprintf(w, "\n  %-*s  %7s  %7s\n", width, "Channel", "Posts", "Scores")
+		for _, im := range sim.Channels {
+			fmt.Fprintf(w, "  %-*s  %7d  %7d\n", width, im.Source+"/"+im.Channel, im.Posts, im.Scores)
+		}
+	}
+
+	// Rows are assumed to take space in proportion to their text.
+	after := size
+	if sim.LiveBytes > 0 {
+		after = size - int64(float64(size)*float64(bytes)/float64(sim.LiveBytes))
+	}
+	fmt.Fprintf(w, "\nDatabase size: %s now, about %s after \"db purge\" and \"db maintain\"\n", formatBytes(size), formatBytes(after))
+	if sim.Tombstoned > 0 {
+		fmt.Fprintf(w, "%d posts pruned earlier are awaiting \"db purge\" and not counted above.\n", sim.Tombstoned)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func TestPruneAction_Simulate(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	scriptPath := filepath.Join(tmpDir, "forge-plan.sh")
+	writeTestForgePlanScript(t, scriptPath)
+	writeTestConfig(t, tmpDir, dbPath, scriptPath)
+
+	ctx := context.Background()
+	st := openStoreForPipelineTest(t, dbPath)
+	for i, age := range []int{90, 60, 45, 10} {
+		at := time.Now().AddDate(0, 0, -age)
+		channel := "News"
+		if i == 0 {
+			channel = "Archive"
+		}
+		if _, err := st.InsertPost(ctx, store.PostInput{
+			Source: "rss", Channel: channel, ExternalID: fmt.Sprint(i), Text: fmt.Sprintf("post from %d days ago", age),
+			PostedAt: at, FetchedAt: at,
+		}); err != nil {
+			t.Fatalf("insert post: %v", err)
+		}
+	}
+
+	oldConfigDir := configDir
+	t.Cleanup(func() { configDir = oldConfigDir; pruneSimulate = false; pruneDays = 0 })
+	configDir = tmpDir
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(ctx)
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+
+	// The configured 30 days would prune three posts.
+	pruneSimulate = true
+	if err := pruneAction(cmd, nil); err != nil {
+		t.Fatalf("simulate: %v", err)
+	}
+	out := buf.String()
+	requireContains(t, out, "Retention: 30 days, 0 per-source/channel rules")
+	requireContains(t, out, "Would prune 3 of 4 posts (0 scores)")
+	requireContains(t, out, "  rss/News           2        0\n")
+	requireContains(t, out, "  rss/Archive        1        0\n")
+	requireContains(t, out, "Database size: ")
+
+	// A longer retention prunes only the oldest.
+	buf.Reset()
+	pruneDays = 75
+	if err := pruneAction(cmd, nil); err != nil {
+		t.Fatalf("simulate --days: %v", err)
+	}
+	requireContains(t, buf.String(), "Would prune 1 of 4 posts")
+	if n, err := st.Tombstoned(ctx); err != nil || n != 0 {
+		t.Fatalf("simulation tombstoned %d posts (err %v)", n, err)
+	}
+
+	buf.Reset()
+	pruneSimulate = false
+	if err := pruneAction(cmd, nil); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	requireContains(t, buf.String(), "Pruned 1 posts")
+
+	buf.Reset()
+	pruneSimulate, pruneDays = true, 0
+	if err := pruneAction(cmd, nil); err != nil {
+		t.Fatalf("simulate after prune: %v", err)
+	}
+	requireContains(t, buf.String(), "Would prune 2 of 3 posts")
+	requireContains(t, buf.String(), "1 posts pruned earlier are awaiting \"db purge\"")
+
+	pruneDays = -1
+	if err := pruneAction(cmd, nil); err == nil {
+		t.Error("expected error for negative --days")
+	}
+}
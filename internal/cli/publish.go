@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/digest"
+	"github.com/ppiankov/noisepan/internal/network"
+)
+
+// publishTarget is a publisher with the name it was asked for by.
+type publishTarget struct {
+	name string
+	digest.Publisher
+}
+
+// newPublishers builds the publishers named in list ("notion,confluence")
+// from the publish section of config.yaml.
+func newPublishers(cfg *config.Config, list string) ([]publishTarget, error) {
+	if list == "" {
+		return nil, nil
+	}
+	transport, err := network.NewTransport(cfg.Network)
+	if err != nil {
+		return nil, fmt.Errorf("build http transport: %w", err)
+	}
+
+	var targets []publishTarget
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		switch name {
+		case "notion":
+			nc := cfg.Publish.Notion
+			if nc.Token == "" || nc.ParentPage == "" {
+				return nil, fmt.Errorf("--publish notion: publish.notion needs a token and parent_page")
+			}
+			p := digest.NewNotion(nc.Token, nc.ParentPage)
+			p.SetTransport(transport)
+			targets = append(targets, publishTarget{name, p})
+		case "confluence":
+			cc := cfg.Publish.Confluence
+			if cc.BaseURL == "" || cc.Token == "" || cc.Space == "" {
+				return nil, fmt.Errorf("--publish confluence: publish.confluence needs base_url, a token, and space")
+			}
+			p := digest.NewConfluence(cc.BaseURL, cc.User, cc.Token, cc.Space, cc.ParentPage)
+			p.SetTransport(transport)
+			targets = append(targets, publishTarget{name, p})
+		default:
+			return nil, fmt.Errorf("unknown publish target %q (want notion or confluence)", name)
+		}
+	}
+	return targets, nil
+}
+
+// digestTitle names the digest of now in page titles and email subjects.
+func digestTitle(now time.Time) string {
+	return "noisepan digest " + now.Format("2006-01-02 15:04")
+}
+
+// publishDigest publishes input to target as a page titled with the time.
+func publishDigest(ctx context.Context, target publishTarget, input digest.DigestInput, now time.Time) error {
+	url, err := target.Publish(ctx, digestTitle(now), input)
+	if err != nil {
+		return err
+	}
+	slog.Info("published digest", "target", target.name, "url", url)
+	return nil
+}
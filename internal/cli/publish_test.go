@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ppiankov/noisepan/internal/config"
+)
+
+func TestNewPublishers(t *testing.T) {
+	cfg := &config.Config{Publish: config.PublishConfig{
+		Notion:     config.NotionConfig{Token: "n", ParentPage: "p"},
+		Confluence: config.ConfluenceConfig{BaseURL: "https://wiki.test", Token: "c", Space: "ENG"},
+	}}
+
+	targets, err := newPublishers(cfg, "notion, confluence")
+	if err != nil {
+		t.Fatalf("newPublishers: %v", err)
+	}
+	if len(targets) != 2 || targets[0].name != "notion" || targets[1].name != "confluence" {
+		t.Errorf("targets = %+v", targets)
+	}
+
+	if targets, err := newPublishers(cfg, ""); err != nil || targets != nil {
+		t.Errorf("empty list = %v, %v", targets, err)
+	}
+
+	if _, err := newPublishers(cfg, "slack"); err == nil || !strings.Contains(err.Error(), "unknown publish target") {
+		t.Errorf("unknown target err = %v", err)
+	}
+
+	cfg.Publish.Confluence.Space = ""
+	if _, err := newPublishers(cfg, "confluence"); err == nil || !strings.Contains(err.Error(), "space") {
+		t.Errorf("incomplete config err = %v", err)
+	}
+}
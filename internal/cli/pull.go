@@ -1,22 +1,46 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/ppiankov/noisepan/internal/cache"
 	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/events"
+	"github.com/ppiankov/noisepan/internal/network"
 	"github.com/ppiankov/noisepan/internal/privacy"
 	"github.com/ppiankov/noisepan/internal/source"
 	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/taste"
+	"github.com/ppiankov/noisepan/internal/telemetry"
+	"github.com/ppiankov/noisepan/internal/transform"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// pullLimit, when positive, keeps only the newest posts of each source;
+// quickstart sets it for a small first pull.
+var pullLimit int
+
+// errAllSourcesFailed is returned by a pull whose every source failed; it
+// does not count as a pull.
+var errAllSourcesFailed = errors.New("every source failed")
+
 var pullCmd = &cobra.Command{
 	Use:   "pull",
 	Short: "Fetch posts from all configured sources",
-	RunE:  pullAction,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return pingMonitor(cmd, pullAction(cmd, args))
+	},
 }
 
 func pullAction(cmd *cobra.Command, _ []string) error {
@@ -25,14 +49,35 @@ func pullAction(cmd *cobra.Command, _ []string) error {
 		return fmt.Errorf("load config: %w", err)
 	}
 
-	db, err := store.Open(cfg.Storage.Path)
+	db, err := openStore(cfg)
 	if err != nil {
 		return fmt.Errorf("open store: %w", err)
 	}
 	defer func() { _ = db.Close() }()
 
 	since := time.Now().Add(-cfg.Digest.Since.Duration)
-	ctx := cmd.Context()
+	ctx, _ := withEventBus(cmd.Context())
+
+	transport, err := network.NewTransport(cfg.Network)
+	if err != nil {
+		return fmt.Errorf("build http transport: %w", err)
+	}
+	// Sources fetch without a context; their requests are traced under the
+	// span of the source being fetched.
+	fetchCtx := ctx
+	traced := func(name string) http.RoundTripper {
+		return telemetry.NewTransport(name, transport, func() context.Context { return fetchCtx })
+	}
+
+	// The lookup cache is opened once, by the first source that uses it.
+	var lookups *cache.Cache
+	lookupCache := func() *cache.Cache {
+		if lookups == nil {
+			lookups = openCache(cfg)
+		}
+		return lookups
+	}
+	defer func() { _ = lookups.Close() }()
 
 	// Build sources
 	var sources []source.Source
@@ -53,6 +98,7 @@ func pullAction(cmd *cobra.Command, _ []string) error {
 		if err != nil {
 			return fmt.Errorf("create telegram source: %w", err)
 		}
+		applyFetchConfig(tg, cfg.Sources.Telegram.FetchConfig)
 		sources = append(sources, tg)
 	}
 
@@ -61,6 +107,9 @@ func pullAction(cmd *cobra.Command, _ []string) error {
 		if err != nil {
 			return fmt.Errorf("create rss source: %w", err)
 		}
+		rs.SetTransport(traced("rss"))
+		rs.SetCache(lookupCache())
+		applyFetchConfig(rs, cfg.Sources.RSS.FetchConfig)
 		sources = append(sources, rs)
 	}
 
@@ -69,6 +118,12 @@ func pullAction(cmd *cobra.Command, _ []string) error {
 		if err != nil {
 			return fmt.Errorf("create reddit source: %w", err)
 		}
+		rd.SetTransport(traced("reddit"))
+		if cfg.Sources.Reddit.Polite {
+			rd.SetPolite(true)
+			rd.SetCache(lookupCache())
+		}
+		applyFetchConfig(rd, cfg.Sources.Reddit.FetchConfig)
 		sources = append(sources, rd)
 	}
 
@@ -77,6 +132,15 @@ func pullAction(cmd *cobra.Command, _ []string) error {
 		if err != nil {
 			return fmt.Errorf("create hn source: %w", err)
 		}
+		if err := hn.SetAPI(cfg.Sources.HN.API); err != nil {
+			return err
+		}
+		hn.SetTransport(traced("hn"))
+		if cfg.Sources.HN.API != source.HNAPIAlgolia {
+			// Only item-by-item Firebase lookups are worth caching.
+			hn.SetCache(lookupCache())
+		}
+		applyFetchConfig(hn, cfg.Sources.HN.FetchConfig)
 		sources = append(sources, hn)
 	}
 
@@ -85,79 +149,184 @@ func pullAction(cmd *cobra.Command, _ []string) error {
 		if err != nil {
 			return fmt.Errorf("create forgeplan source: %w", err)
 		}
+		applyFetchConfig(fp, cfg.Sources.ForgePlan.FetchConfig)
 		sources = append(sources, fp)
 	}
 
-	// Compile redact patterns if enabled
-	var redactPatterns []*regexp.Regexp
-	if cfg.Privacy.Redact.Enabled && len(cfg.Privacy.Redact.Patterns) > 0 {
-		redactPatterns, err = privacy.Compile(cfg.Privacy.Redact.Patterns)
+	if len(cfg.Sources.Archive.Newsletters) > 0 {
+		newsletters := make([]source.Newsletter, 0, len(cfg.Sources.Archive.Newsletters))
+		gemini := false
+		for _, nl := range cfg.Sources.Archive.Newsletters {
+			gemini = gemini || strings.HasPrefix(nl.URLPattern, "gemini://")
+			newsletters = append(newsletters, source.Newsletter{
+				Name:       nl.Name,
+				URLPattern: nl.URLPattern,
+				DateFormat: nl.DateFormat,
+			})
+		}
+		ar, err := source.NewArchive(newsletters)
 		if err != nil {
-			return fmt.Errorf("compile redact patterns: %w", err)
+			return fmt.Errorf("create archive source: %w", err)
 		}
+		ar.SetTransport(traced("archive"))
+		if gemini {
+			// Gemini certificates are pinned there.
+			ar.SetCache(lookupCache())
+		}
+		applyFetchConfig(ar, cfg.Sources.Archive.FetchConfig)
+		sources = append(sources, ar)
+	}
+
+	transforms, err := transform.Compile(cfg.Transforms)
+	if err != nil {
+		return err
+	}
+
+	redactPatterns, err := compileRedact(cfg.Privacy)
+	if err != nil {
+		return err
+	}
+
+	embedder, err := newEmbedder(ctx, cfg)
+	if err != nil {
+		return err
 	}
 
 	totalInserted := 0
 	channels := make(map[string]bool)
+	touched := make(map[channelKey]bool)
+	var fetchErrs []error
 
 	for _, src := range sources {
+		var span trace.Span
+		fetchCtx, span = telemetry.Start(ctx, "fetch "+src.Name(), attribute.String("noisepan.source", src.Name()))
 		posts, err := src.Fetch(since)
+		span.SetAttributes(attribute.Int("noisepan.posts", len(posts)))
+		telemetry.End(span, err)
+		if err := recordFeedStatuses(ctx, db, src, err); err != nil {
+			return err
+		}
 		if err != nil {
-			fmt.Printf("warning: %s: %v\n", src.Name(), err)
+			events.Publish(ctx, events.Event{Kind: events.SourceFailed, Source: src.Name(), Err: err})
+			fetchErrs = append(fetchErrs, err)
 			continue
 		}
+		slog.Debug("source fetched", "source", src.Name(), "posts", len(posts))
+		posts = newestPosts(posts, pullLimit)
 
 		now := time.Now()
+		skewed := make(map[string]time.Duration)
 		for _, p := range posts {
 			channels[p.Channel] = true
+			touched[channelKey{source: p.Source, channel: p.Channel}] = true
 
-			text := p.Text
-
-			// Apply redaction before snippet extraction
-			if len(redactPatterns) > 0 {
-				text = privacy.Apply(text, redactPatterns)
+			postedAt, skew := clampFuture(p.PostedAt, now, cfg.ClockSkew)
+			if skew > skewed[p.Channel] {
+				skewed[p.Channel] = skew
 			}
 
-			// Generate snippet from (possibly redacted) text, then
-			// clear full text if store_full_text is false.
-			snippet := ""
-			storeText := text
-			if !cfg.Privacy.StoreFullText {
-				snippet = firstNRunes(text, 200)
-				storeText = ""
+			text := transforms.Apply(p.Channel, p.Text)
+			if strings.TrimSpace(text) == "" && strings.TrimSpace(p.Text) != "" {
+				// A post is not dropped by its transforms: keep it as fetched.
+				slog.Warn("transforms left no text; storing the post untransformed",
+					"source", p.Source, "channel", p.Channel, "external_id", p.ExternalID)
+				text = p.Text
 			}
+			storeText, snippet := storedText(text, cfg.Privacy, redactPatterns)
 
-			_, err := db.InsertPost(ctx, store.PostInput{
+			post, err := db.InsertPost(ctx, store.PostInput{
 				Source:     p.Source,
 				Channel:    p.Channel,
 				ExternalID: p.ExternalID,
 				Text:       storeText,
 				Snippet:    snippet,
 				URL:        p.URL,
-				PostedAt:   p.PostedAt,
+				Author:     p.Author,
+				Language:   taste.DetectLanguage(text),
+				PostedAt:   postedAt,
 				FetchedAt:  now,
 			})
 			if err != nil {
 				return fmt.Errorf("insert post: %w", err)
 			}
 			totalInserted++
+			events.Publish(ctx, postEvent(events.PostIngested, store.PostWithScore{Post: post}))
+		}
+		for ch, skew := range skewed {
+			slog.Warn("future-dated posts", "source", src.Name(), "channel", ch,
+				"max_skew", skew.Round(time.Second), "mode", cfg.ClockSkew.Mode)
 		}
 	}
 
-	dupes, err := db.Deduplicate(ctx)
+	// Nothing was fetched: keep the last pull time, so health and stale
+	// checks see the outage, and fail so monitoring does too.
+	if len(sources) > 0 && len(fetchErrs) == len(sources) {
+		return fmt.Errorf("%w: %w", errAllSourcesFailed, errors.Join(fetchErrs...))
+	}
+
+	keeper := store.DedupKeeper{
+		Strategy:    cfg.Dedup.Keep,
+		SourceOrder: cfg.Dedup.SourceOrder,
+		Similarity:  cfg.Dedup.Similarity,
+		ByURL:       !cfg.Dedup.TextOnly,
+
+		RecurringGap: recurringGap(cfg.Dedup.Recurring),
+	}
+	// Embedding is best effort: an unreachable model leaves the posts to
+	// the next pull and only semantic dedup waits for them.
+	if embedder != nil {
+		embedded, err := embedNewPosts(ctx, db, embedder, cfg.Embed.BatchSize)
+		if err != nil {
+			slog.Warn("embedding posts failed", "model", embedder.Model(), "err", err)
+		}
+		slog.Debug("posts embedded", "model", embedder.Model(), "posts", embedded)
+		keeper.SemanticModel = embedder.Model()
+		keeper.SemanticSimilarity = cfg.Dedup.Semantic
+	}
+
+	dupes, err := db.DeduplicateWith(ctx, keeper)
 	if err != nil {
 		return fmt.Errorf("deduplicate: %w", err)
 	}
 
-	pruned, err := db.PruneOld(ctx, cfg.Storage.RetainDays)
+	// With slim_days set, posts past their retention lose their full text and
+	// are deleted slim_days later; otherwise they are deleted right away.
+	var slimmed int64
+	policy := retentionPolicy(cfg.Storage)
+	prunePolicy := policy
+	if cfg.Storage.SlimDays > 0 {
+		slimmed, err = db.SlimWithPolicy(ctx, policy)
+		if err != nil {
+			return fmt.Errorf("slim old: %w", err)
+		}
+		prunePolicy = policy.Extend(cfg.Storage.SlimDays)
+	}
+
+	pruned, err := db.PruneWithPolicy(ctx, prunePolicy)
 	if err != nil {
 		return fmt.Errorf("prune old: %w", err)
 	}
+	if _, err := pruneSavedDigests(ctx, db, cfg.Storage, time.Now()); err != nil {
+		return err
+	}
+
+	if !cfg.Boilerplate.Disabled {
+		if err := learnBoilerplate(ctx, db, cfg.Boilerplate, touched, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	if err := db.SetLastPull(ctx, time.Now()); err != nil {
+		return fmt.Errorf("record last pull: %w", err)
+	}
 
 	fmt.Printf("Pulled %d posts from %d channels", totalInserted, len(channels))
 	if dupes > 0 {
 		fmt.Printf(" (%d duplicates removed)", dupes)
 	}
+	if slimmed > 0 {
+		fmt.Printf(" (%d old posts slimmed)", slimmed)
+	}
 	if pruned > 0 {
 		fmt.Printf(" (%d old posts pruned)", pruned)
 	}
@@ -166,6 +335,158 @@ func pullAction(cmd *cobra.Command, _ []string) error {
 	return nil
 }
 
+// newestPosts returns the n most recent posts, or all of them when n is not
+// positive.
+func newestPosts(posts []source.Post, n int) []source.Post {
+	if n <= 0 || len(posts) <= n {
+		return posts
+	}
+	sorted := append([]source.Post(nil), posts...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].PostedAt.After(sorted[j].PostedAt) })
+	return sorted[:n]
+}
+
+// channelKey identifies a channel within a source.
+type channelKey struct {
+	source  string
+	channel string
+}
+
+// learnBoilerplate re-detects the repeated blocks of each channel that
+// received posts, from its most recent posts.
+func learnBoilerplate(ctx context.Context, db *store.Store, bc config.BoilerplateConfig, channels map[channelKey]bool, now time.Time) error {
+	for key := range channels {
+		texts, err := db.RecentTexts(ctx, key.source, key.channel, bc.Sample)
+		if err != nil {
+			return fmt.Errorf("learn boilerplate: %w", err)
+		}
+		found := transform.DetectBoilerplate(texts, bc.MinShare, bc.MinPosts)
+		blocks := make([]store.BoilerplateBlock, 0, len(found))
+		for _, b := range found {
+			blocks = append(blocks, store.BoilerplateBlock{
+				Text:      b.Text,
+				Posts:     b.Posts,
+				Sample:    len(texts),
+				LearnedAt: now,
+			})
+		}
+		if err := db.ReplaceBoilerplate(ctx, key.source, key.channel, blocks); err != nil {
+			return fmt.Errorf("learn boilerplate: %w", err)
+		}
+		if len(blocks) > 0 {
+			slog.Debug("boilerplate learned", "source", key.source, "channel", key.channel, "blocks", len(blocks))
+		}
+	}
+	return nil
+}
+
+// policySource is implemented by sources with a tunable fetch policy.
+type policySource interface {
+	Policy() source.FetchPolicy
+	SetPolicy(source.FetchPolicy)
+}
+
+// recordFeedStatuses stores per-feed fetch outcomes so the digest can report
+// feeds that started failing. Sources without per-feed reporting are tracked
+// as a single feed named after the source.
+func recordFeedStatuses(ctx context.Context, db *store.Store, src source.Source, fetchErr error) error {
+	statuses := []source.FeedStatus{{Feed: src.Name(), Err: fetchErr}}
+	if sr, ok := src.(source.StatusReporter); ok && fetchErr == nil {
+		statuses = sr.FeedStatuses()
+	}
+
+	now := time.Now()
+	for _, st := range statuses {
+		msg := ""
+		if st.Err != nil {
+			msg = st.Err.Error()
+		}
+		if err := db.RecordFeedStatus(ctx, src.Name(), st.Feed, msg, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clampFuture checks postedAt against the fetch time. Posts dated more than
+// the tolerance ahead return their skew; in clamp mode the returned time is
+// the fetch time, in flag mode it is left as-is (the store caps it on read).
+func clampFuture(postedAt, now time.Time, cs config.ClockSkewConfig) (time.Time, time.Duration) {
+	skew := postedAt.Sub(now)
+	if skew <= cs.Tolerance.Duration {
+		return postedAt, 0
+	}
+	if cs.Mode == "clamp" {
+		return now, skew
+	}
+	return postedAt, skew
+}
+
+// locationSource is implemented by sources that read timestamps without an offset.
+type locationSource interface {
+	SetLocation(*time.Location)
+}
+
+// applyFetchConfig overrides the source's default policy with the fields set
+// in fc, and sets its timezone for naive timestamps where supported.
+func applyFetchConfig(src policySource, fc config.FetchConfig) {
+	if ls, ok := src.(locationSource); ok {
+		if loc := fc.Location(); loc != nil {
+			ls.SetLocation(loc)
+		}
+	}
+
+	p := src.Policy()
+	if fc.Timeout.Duration > 0 {
+		p.Timeout = fc.Timeout.Duration
+	}
+	if fc.MaxRetries != nil {
+		p.MaxRetries = *fc.MaxRetries
+	}
+	if fc.Backoff.Duration > 0 {
+		p.Backoff = fc.Backoff.Duration
+	}
+	if fc.Delay.Duration > 0 {
+		p.Delay = fc.Delay.Duration
+	}
+	if fc.Workers > 0 {
+		p.Workers = fc.Workers
+	}
+	src.SetPolicy(p)
+}
+
+// compileRedact compiles privacy.redact.patterns, or returns nil when
+// redaction is off.
+func compileRedact(pc config.PrivacyConfig) ([]*regexp.Regexp, error) {
+	if !pc.Redact.Enabled || len(pc.Redact.Patterns) == 0 {
+		return nil, nil
+	}
+	patterns, err := privacy.Compile(pc.Redact.Patterns)
+	if err != nil {
+		return nil, fmt.Errorf("compile redact patterns: %w", err)
+	}
+	return patterns, nil
+}
+
+// storedText applies the privacy settings to a post's text: it redacts the
+// text and, unless store_full_text is set, keeps only a snippet of
+// privacy.snippet_length characters. Full text is stored without a snippet,
+// which the store cuts itself.
+func storedText(text string, pc config.PrivacyConfig, redact []*regexp.Regexp) (fullText, snippet string) {
+	text = privacy.Apply(text, redact)
+	if pc.StoreFullText {
+		return text, ""
+	}
+	return "", firstNRunes(text, snippetLength(pc))
+}
+
+func snippetLength(pc config.PrivacyConfig) int {
+	if pc.SnippetLength <= 0 {
+		return config.DefaultSnippetLength
+	}
+	return pc.SnippetLength
+}
+
 func firstNRunes(s string, n int) string {
 	if n <= 0 || s == "" {
 		return ""
@@ -179,3 +500,41 @@ func firstNRunes(s string, n int) string {
 	}
 	return s
 }
+
+// retentionPolicy builds the store retention policy from storage.retain_days
+// and its per-source/channel overrides.
+func retentionPolicy(sc config.StorageConfig) store.RetentionPolicy {
+	policy := store.RetentionPolicy{DefaultDays: sc.RetainDays}
+	for _, r := range sc.Retention {
+		policy.Rules = append(policy.Rules, store.RetentionRule{
+			Source: r.Source, Channel: r.Channel, Days: r.RetainDays,
+		})
+	}
+	return policy
+}
+
+// pruneSavedDigests deletes the digests saved by "digest --save" that are
+// older than storage.retain_days plus slim_days, since they hold copies of
+// post text. Per-source and per-channel retention rules do not apply.
+func pruneSavedDigests(ctx context.Context, db *store.Store, sc config.StorageConfig, now time.Time) (int64, error) {
+	if sc.RetainDays <= 0 {
+		return 0, nil
+	}
+	days := sc.RetainDays + sc.SlimDays
+	return db.PruneDigests(ctx, now.AddDate(0, 0, -days))
+}
+
+// openCache opens the lookup cache, or returns nil (no caching) when it is
+// disabled, cannot be opened, or this is a dry run, whose writes cache.db
+// could not roll back; a broken cache must not stop a pull.
+func openCache(cfg *config.Config) *cache.Cache {
+	if cfg.Cache.Disabled || dryRun {
+		return nil
+	}
+	c, err := cache.Open(cfg.Cache.Path)
+	if err != nil {
+		slog.Warn("cache unavailable; continuing without it", "path", cfg.Cache.Path, "err", err)
+		return nil
+	}
+	return c
+}
@@ -0,0 +1,265 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/source"
+	"github.com/spf13/cobra"
+)
+
+func TestApplyFetchConfig(t *testing.T) {
+	rs, err := source.NewRSS([]string{"https://example.com/feed.xml"})
+	if err != nil {
+		t.Fatalf("new rss: %v", err)
+	}
+	defaults := rs.Policy()
+
+	zero := 0
+	applyFetchConfig(rs, config.FetchConfig{
+		Timeout:    config.Duration{Duration: 2 * time.Minute},
+		MaxRetries: &zero,
+	})
+
+	got := rs.Policy()
+	if got.Timeout != 2*time.Minute {
+		t.Errorf("timeout = %v, want 2m", got.Timeout)
+	}
+	if got.MaxRetries != 0 {
+		t.Errorf("max_retries = %d, want 0", got.MaxRetries)
+	}
+	if got.Backoff != defaults.Backoff || got.Delay != defaults.Delay || got.Workers != defaults.Workers {
+		t.Errorf("unset fields changed: got %+v, defaults %+v", got, defaults)
+	}
+}
+
+func TestClampFuture(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	clamp := config.ClockSkewConfig{Tolerance: config.Duration{Duration: 15 * time.Minute}, Mode: "clamp"}
+	flag := config.ClockSkewConfig{Tolerance: config.Duration{Duration: 15 * time.Minute}, Mode: "flag"}
+
+	tests := []struct {
+		name     string
+		postedAt time.Time
+		cs       config.ClockSkewConfig
+		wantTime time.Time
+		wantSkew time.Duration
+	}{
+		{"past", now.Add(-time.Hour), clamp, now.Add(-time.Hour), 0},
+		{"within tolerance", now.Add(10 * time.Minute), clamp, now.Add(10 * time.Minute), 0},
+		{"clamped", now.Add(13 * time.Hour), clamp, now, 13 * time.Hour},
+		{"flagged", now.Add(13 * time.Hour), flag, now.Add(13 * time.Hour), 13 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, skew := clampFuture(tt.postedAt, now, tt.cs)
+			if !got.Equal(tt.wantTime) || skew != tt.wantSkew {
+				t.Errorf("clampFuture = %v, %v; want %v, %v", got, skew, tt.wantTime, tt.wantSkew)
+			}
+		})
+	}
+}
+
+func TestPullAction_Transforms(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	scriptPath := filepath.Join(tmpDir, "forge-plan.sh")
+
+	writeTestForgePlanScript(t, scriptPath)
+	writeTestConfig(t, tmpDir, dbPath, scriptPath)
+	cfgPath := filepath.Join(tmpDir, "config.yaml")
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	data = append(data, []byte(`transforms:
+  - strip: ["(?m)^\\s*kubectl .*$"]
+    replace:
+      - pattern: "https://example\\.com/\\S+"
+        with: "<link>"
+`)...)
+	if err := os.WriteFile(cfgPath, data, 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	oldConfigDir := configDir
+	t.Cleanup(func() { configDir = oldConfigDir })
+	configDir = tmpDir
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	if _, err := captureStdout(t, func() error { return pullAction(cmd, nil) }); err != nil {
+		t.Fatalf("pull: %v", err)
+	}
+
+	st := openStoreForPipelineTest(t, dbPath)
+	posts, err := st.GetUnscored(context.Background())
+	if err != nil {
+		t.Fatalf("get unscored: %v", err)
+	}
+	if len(posts) != 3 {
+		t.Fatalf("got %d posts, want 3", len(posts))
+	}
+	for _, p := range posts {
+		if strings.Contains(p.Text, "kubectl") || strings.Contains(p.Text, "example.com") {
+			t.Errorf("transform not applied: %q", p.Text)
+		}
+	}
+}
+
+func TestPullAction_TransformRemovesEverything(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	scriptPath := filepath.Join(tmpDir, "forge-plan.sh")
+
+	writeTestForgePlanScript(t, scriptPath)
+	writeTestConfig(t, tmpDir, dbPath, scriptPath)
+	cfgPath := filepath.Join(tmpDir, "config.yaml")
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	data = append(data, []byte(`transforms:
+  - strip: ["(?s).*"]
+`)...)
+	if err := os.WriteFile(cfgPath, data, 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	oldConfigDir := configDir
+	t.Cleanup(func() { configDir = oldConfigDir })
+	configDir = tmpDir
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	if _, err := captureStdout(t, func() error { return pullAction(cmd, nil) }); err != nil {
+		t.Fatalf("pull: %v", err)
+	}
+
+	st := openStoreForPipelineTest(t, dbPath)
+	posts, err := st.GetUnscored(context.Background())
+	if err != nil {
+		t.Fatalf("get unscored: %v", err)
+	}
+	if len(posts) != 3 {
+		t.Fatalf("got %d posts, want 3", len(posts))
+	}
+	for _, p := range posts {
+		if strings.TrimSpace(p.Text+p.Snippet) == "" {
+			t.Errorf("post %s stored without text", p.ExternalID)
+		}
+	}
+}
+
+func TestPullAction_DryRun(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	scriptPath := filepath.Join(tmpDir, "forge-plan.sh")
+
+	writeTestForgePlanScript(t, scriptPath)
+	writeTestConfig(t, tmpDir, dbPath, scriptPath)
+
+	oldConfigDir := configDir
+	t.Cleanup(func() { configDir, dryRun = oldConfigDir, false })
+	configDir, dryRun = tmpDir, true
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	out, err := captureStdout(t, func() error { return pullAction(cmd, nil) })
+	if err != nil {
+		t.Fatalf("pull: %v", err)
+	}
+	requireContains(t, out, "Pulled 3 posts")
+
+	st := openStoreForPipelineTest(t, dbPath)
+	posts, err := st.GetPosts(context.Background(), time.Time{}, "")
+	if err != nil {
+		t.Fatalf("get posts: %v", err)
+	}
+	if len(posts) != 0 {
+		t.Errorf("dry-run pull stored %d posts", len(posts))
+	}
+}
+
+func TestPullAction_AllSourcesFail(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	scriptPath := filepath.Join(tmpDir, "forge-plan.sh")
+
+	if err := os.WriteFile(scriptPath, []byte("#!/bin/sh\nexit 1\n"), 0o755); err != nil {
+		t.Fatalf("write script: %v", err)
+	}
+	writeTestConfig(t, tmpDir, dbPath, scriptPath)
+
+	oldConfigDir := configDir
+	t.Cleanup(func() { configDir = oldConfigDir })
+	configDir = tmpDir
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	if _, err := captureStdout(t, func() error { return pullAction(cmd, nil) }); !errors.Is(err, errAllSourcesFailed) {
+		t.Fatalf("pull = %v, want errAllSourcesFailed", err)
+	}
+
+	st := openStoreForPipelineTest(t, dbPath)
+	if last, err := st.LastPull(context.Background()); err != nil || !last.IsZero() {
+		t.Errorf("last pull = %v, %v; want none recorded", last, err)
+	}
+}
+
+func TestPullAction_LearnsBoilerplate(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	scriptPath := filepath.Join(tmpDir, "forge-plan.sh")
+
+	script := `#!/bin/sh
+cat <<'EOF'
+Suggested actions
+
+  1. CVE-2026-1111 Kubernetes breaking change affects control plane.
+  Brought to you by the cluster ops newsletter
+
+  2. Kubernetes migration checklist for v1.2.3.
+  Brought to you by the cluster ops newsletter
+
+  3. Join our webinar on cluster best practices.
+  Brought to you by the cluster ops newsletter
+EOF
+`
+	if err := os.WriteFile(scriptPath, []byte(script), 0o755); err != nil {
+		t.Fatalf("write script: %v", err)
+	}
+	writeTestConfig(t, tmpDir, dbPath, scriptPath)
+	cfgPath := filepath.Join(tmpDir, "config.yaml")
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	data = append(data, []byte("boilerplate:\n  min_posts: 3\n")...)
+	if err := os.WriteFile(cfgPath, data, 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	oldConfigDir := configDir
+	t.Cleanup(func() { configDir = oldConfigDir })
+	configDir = tmpDir
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	if _, err := captureStdout(t, func() error { return pullAction(cmd, nil) }); err != nil {
+		t.Fatalf("pull: %v", err)
+	}
+
+	out, err := captureStdout(t, func() error { return boilerplateAction(cmd, nil) })
+	if err != nil {
+		t.Fatalf("boilerplate: %v", err)
+	}
+	requireContains(t, out, "1 blocks learned from 3 posts")
+	requireContains(t, out, "[3/3] Brought to you by the cluster ops newsletter")
+}
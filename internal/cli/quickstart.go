@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/digest"
+	"github.com/spf13/cobra"
+)
+
+var quickstartLimit int
+
+var quickstartCmd = &cobra.Command{
+	Use:   "quickstart",
+	Short: "Set up, pull a small sample, and show a first digest with scores explained",
+	Long: "quickstart creates the config directory if needed (with a few public RSS feeds),\n" +
+		"pulls the newest posts of each source, and prints a digest that explains every score.\n" +
+		"Run it again after editing config.yaml and taste.yaml; existing files are left alone.",
+	RunE: quickstartAction,
+}
+
+func init() {
+	quickstartCmd.Flags().IntVar(&quickstartLimit, "limit", 20, "posts to keep per source")
+	quickstartCmd.Flags().BoolVar(&noColor, "no-color", false, "disable ANSI colors")
+}
+
+func quickstartAction(cmd *cobra.Command, args []string) error {
+	if quickstartLimit <= 0 {
+		return fmt.Errorf("--limit must be greater than zero")
+	}
+
+	fmt.Println("Step 1/3: config")
+	if err := initConfigDir(quickstartConfig); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nStep 2/3: pull the newest %d posts per source\n", quickstartLimit)
+	oldLimit := pullLimit
+	pullLimit = quickstartLimit
+	defer func() { pullLimit = oldLimit }()
+	if err := pullAction(cmd, args); err != nil {
+		return err
+	}
+
+	fmt.Println("\nStep 3/3: score and digest")
+	fmt.Println()
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := quickstartDigest(ctx); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println("Next:")
+	fmt.Printf("  - add your own feeds and channels to %s\n", filepath.Join(configDir, config.DefaultConfigFile))
+	fmt.Printf("  - tune the weights in %s; each \"why:\" line names the keywords and rules that scored\n", filepath.Join(configDir, config.DefaultTasteFile))
+	fmt.Println("  - run 'noisepan run' for the daily digest, or 'noisepan doctor' if a source came back empty")
+	return nil
+}
+
+// quickstartDigest prints a terminal digest with the scoring breakdown under
+// each post. It is a sample: nothing is recorded as shown, so the first real
+// digest still has these posts.
+func quickstartDigest(ctx context.Context) error {
+	cfg, err := config.Load(configDir)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	profile, err := loadTaste(cfg, profileTastePath(cfg))
+	if err != nil {
+		return fmt.Errorf("load taste: %w", err)
+	}
+	db, err := openStore(cfg)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	pipeline, err := newDigestPipeline(ctx, cfg, profile, db)
+	if err != nil {
+		return err
+	}
+	built, err := pipeline.build(ctx, digestRequest{Since: cfg.Digest.Since.Duration}, time.Now())
+	if err != nil {
+		return err
+	}
+	formatter := digest.NewTerminal(!noColor)
+	formatter.SetExplain(true)
+	return renderDigest(os.Stdout, formatter, built.Input)
+}
+
+// quickstartConfig is the example config with public feeds in place of the
+// Telegram placeholder, so the first pull has something to show.
+const quickstartConfig = `# noisepan configuration (created by quickstart)
+
+sources:
+  rss:
+    feeds:
+      - "https://hnrss.org/frontpage"
+      - "https://lwn.net/headlines/rss"
+      - "https://kubernetes.io/feed.xml"
+  # telegram, reddit, hn: see 'noisepan init' or configs/config.example.yaml
+
+storage:
+  path: .noisepan/noisepan.db
+  retain_days: 30
+
+digest:
+  timezone: "UTC"
+  top_n: 7
+  include_skims: 5
+  since: 24h
+
+summarize:
+  mode: heuristic
+
+privacy:
+  store_full_text: false
+  redact:
+    enabled: false
+    patterns: []
+`
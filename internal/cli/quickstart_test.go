@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/source"
+	"github.com/spf13/cobra"
+)
+
+func TestQuickstartAction(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	scriptPath := filepath.Join(tmpDir, "forge-plan.sh")
+	writeTestForgePlanScript(t, scriptPath)
+	writeTestConfig(t, tmpDir, dbPath, scriptPath)
+
+	oldConfigDir, oldLimit, oldNoColor := configDir, quickstartLimit, noColor
+	t.Cleanup(func() { configDir, quickstartLimit, noColor = oldConfigDir, oldLimit, oldNoColor })
+	configDir, quickstartLimit, noColor = tmpDir, 2, true
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	out, err := captureStdout(t, func() error { return quickstartAction(cmd, nil) })
+	if err != nil {
+		t.Fatalf("quickstart: %v\n%s", err, out)
+	}
+
+	// The existing config is kept and the missing taste profile created.
+	requireContains(t, out, "exists: "+filepath.Join(tmpDir, "config.yaml"))
+	requireContains(t, out, "created: "+filepath.Join(tmpDir, "taste.yaml"))
+	requireContains(t, out, "Pulled 2 posts from 1 channels")
+	requireContains(t, out, "--- Read Now (1) ---")
+	requireContains(t, out, "why: ")
+	requireContains(t, out, "+5 keyword: cve")
+	requireContains(t, out, "Next:")
+	if pullLimit != 0 {
+		t.Errorf("pullLimit = %d after quickstart, want it reset", pullLimit)
+	}
+
+	// Nothing was recorded as shown: the next digest starts fresh.
+	st := openStoreForPipelineTest(t, dbPath)
+	last, err := st.LastDigest(context.Background())
+	if err != nil {
+		t.Fatalf("last digest: %v", err)
+	}
+	if !last.IsZero() {
+		t.Errorf("last digest = %v, want none", last)
+	}
+}
+
+func TestQuickstartAction_CreatesConfig(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cfg")
+	oldConfigDir, oldLimit := configDir, quickstartLimit
+	t.Cleanup(func() { configDir, quickstartLimit = oldConfigDir, oldLimit })
+	configDir, quickstartLimit = dir, 0
+
+	// --limit 0 is rejected before anything is written.
+	if err := quickstartAction(&cobra.Command{}, nil); err == nil {
+		t.Fatal("expected error for --limit 0")
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("config dir created despite the error: %v", err)
+	}
+
+	if _, err := captureStdout(t, func() error { return initConfigDir(quickstartConfig) }); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "https://hnrss.org/frontpage") {
+		t.Errorf("config.yaml has no starter feeds:\n%s", data)
+	}
+}
+
+func TestNewestPosts(t *testing.T) {
+	now := time.Now()
+	posts := []source.Post{
+		{ExternalID: "old", PostedAt: now.Add(-3 * time.Hour)},
+		{ExternalID: "new", PostedAt: now},
+		{ExternalID: "mid", PostedAt: now.Add(-time.Hour)},
+	}
+	got := newestPosts(posts, 2)
+	if len(got) != 2 || got[0].ExternalID != "new" || got[1].ExternalID != "mid" {
+		t.Errorf("newestPosts = %+v", got)
+	}
+	if posts[0].ExternalID != "old" {
+		t.Error("newestPosts reordered its input")
+	}
+	if got := newestPosts(posts, 0); len(got) != 3 {
+		t.Errorf("newestPosts(0) = %d posts, want all", len(got))
+	}
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/privacy"
+	"github.com/spf13/cobra"
+)
+
+var reprocessSnippets bool
+
+var reprocessCmd = &cobra.Command{
+	Use:   "reprocess",
+	Short: "Rebuild stored fields after privacy settings change",
+	Long: `--snippets cuts every stored post's snippet again with the current
+privacy.snippet_length and privacy.redact patterns, so posts pulled before
+a change follow the new policy. Posts stored with their full text get a
+snippet of the redacted text; posts stored without it only have their old
+snippet, which is redacted and shortened but cannot grow.
+
+Stored full text is left as is. Use --dry-run to count the posts that would
+change.`,
+	Args: cobra.NoArgs,
+	RunE: reprocessAction,
+}
+
+func init() {
+	reprocessCmd.Flags().BoolVar(&reprocessSnippets, "snippets", false, "regenerate snippets from stored text")
+	rootCmd.AddCommand(reprocessCmd)
+}
+
+func reprocessAction(cmd *cobra.Command, _ []string) error {
+	if !reprocessSnippets {
+		return errors.New("nothing to reprocess (want --snippets)")
+	}
+
+	cfg, err := config.Load(configDir)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	redact, err := compileRedact(cfg.Privacy)
+	if err != nil {
+		return err
+	}
+
+	db, err := openStore(cfg)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	changed, err := db.RewriteSnippets(cmd.Context(), snippetRewriter(cfg.Privacy, redact))
+	if err != nil {
+		return fmt.Errorf("rewrite snippets: %w", err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Regenerated %d snippets\n", changed)
+	return nil
+}
+
+// snippetRewriter cuts a snippet with the current settings: from the
+// redacted stored text when there is one, otherwise from the old snippet.
+func snippetRewriter(pc config.PrivacyConfig, redact []*regexp.Regexp) func(text, snippet string) string {
+	return func(text, snippet string) string {
+		if text == "" {
+			text = snippet
+		}
+		return firstNRunes(privacy.Apply(text, redact), snippetLength(pc))
+	}
+}
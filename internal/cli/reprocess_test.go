@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func TestSnippetRewriter(t *testing.T) {
+	redact := []*regexp.Regexp{regexp.MustCompile(`token=\S+`)}
+	rewrite := snippetRewriter(config.PrivacyConfig{SnippetLength: 12}, redact)
+
+	if got := rewrite("token=abc deployed the fix", "token=abc deployed"); got != "[REDACTED] d" {
+		t.Errorf("from text = %q", got)
+	}
+	// Without stored text the old snippet is all there is.
+	if got := rewrite("", "token=abc rotated"); got != "[REDACTED] r" {
+		t.Errorf("from snippet = %q", got)
+	}
+	if got := snippetRewriter(config.PrivacyConfig{}, nil)("", strings.Repeat("a", 300)); len(got) != config.DefaultSnippetLength {
+		t.Errorf("default length = %d, want %d", len(got), config.DefaultSnippetLength)
+	}
+}
+
+func TestStoredText(t *testing.T) {
+	redact := []*regexp.Regexp{regexp.MustCompile(`secret`)}
+	// Full text is stored as is; the store cuts the snippet.
+	full, snippet := storedText("a secret plan", config.PrivacyConfig{StoreFullText: true, SnippetLength: 4}, redact)
+	if full != "a [REDACTED] plan" || snippet != "" {
+		t.Errorf("store_full_text = %q, %q", full, snippet)
+	}
+	if full, snippet := storedText("a secret plan", config.PrivacyConfig{SnippetLength: 4}, redact); full != "" || snippet != "a [R" {
+		t.Errorf("without store_full_text = %q, %q", full, snippet)
+	}
+}
+
+func TestReprocessAction_RequiresTarget(t *testing.T) {
+	old := reprocessSnippets
+	t.Cleanup(func() { reprocessSnippets = old })
+	reprocessSnippets = false
+	if err := reprocessAction(&cobra.Command{}, nil); err == nil || !strings.Contains(err.Error(), "--snippets") {
+		t.Errorf("err = %v, want a hint at --snippets", err)
+	}
+}
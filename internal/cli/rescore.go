@@ -1,9 +1,11 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/ppiankov/noisepan/internal/config"
@@ -12,16 +14,34 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var rescoreSince string
+var (
+	rescoreSince       string
+	rescoreIncremental bool
+)
+
+// rescoreBatch is how many posts rescore loads per page.
+const rescoreBatch = 500
 
 var rescoreCmd = &cobra.Command{
 	Use:   "rescore",
 	Short: "Recompute scores for all posts using current taste profile",
-	RunE:  rescoreAction,
+	Long: `Deletes the scores in the window and scores the posts again with the
+current taste profile.
+
+With --incremental, scores are kept and only posts the profile change can
+reach are rescored: those whose breakdown names a keyword or rule that
+changed or went, and those a new or changed keyword or rule matches. Any
+other change to the profile (thresholds, modifiers, languages, ...), a
+score from before profiles were recorded, and a post edited since it was
+scored mean a rescore. Profiles with rule cooldowns are rescored in full,
+and so must be stored posts after retraining the classifier or changing
+config.yaml, which the profile does not cover.`,
+	RunE: rescoreAction,
 }
 
 func init() {
 	rescoreCmd.Flags().StringVar(&rescoreSince, "since", "", "time window (e.g. 7d, 48h)")
+	rescoreCmd.Flags().BoolVar(&rescoreIncremental, "incremental", false, "keep scores the taste profile change does not reach")
 	rootCmd.AddCommand(rescoreCmd)
 }
 
@@ -31,26 +51,37 @@ func rescoreAction(cmd *cobra.Command, _ []string) error {
 		return fmt.Errorf("load config: %w", err)
 	}
 
-	tastePath := filepath.Join(configDir, config.DefaultTasteFile)
-	profile, err := config.LoadTaste(tastePath)
+	tastePath := profileTastePath(cfg)
+	profile, err := loadTaste(cfg, tastePath)
 	if err != nil {
 		return fmt.Errorf("load taste: %w", err)
 	}
 
-	db, err := store.Open(cfg.Storage.Path)
+	db, err := openStore(cfg)
 	if err != nil {
 		return fmt.Errorf("open store: %w", err)
 	}
 	defer func() { _ = db.Close() }()
 
 	ctx := cmd.Context()
+	w := cmd.OutOrStdout()
 
-	// Delete existing scores
-	deleted, err := db.DeleteAllScores(ctx)
-	if err != nil {
-		return fmt.Errorf("delete scores: %w", err)
+	incremental := rescoreIncremental
+	if incremental && slices.ContainsFunc(profile.Rules, func(r config.Rule) bool { return r.Cooldown.Duration > 0 }) {
+		fmt.Fprintln(w, "Rule cooldowns depend on every post being scored in order; rescoring in full")
+		incremental = false
+	}
+
+	if !incremental {
+		deleted, err := db.DeleteAllScores(ctx)
+		if err != nil {
+			return fmt.Errorf("delete scores: %w", err)
+		}
+		fmt.Fprintf(w, "Deleted %d existing scores\n", deleted)
+		if _, err := db.DeleteRuleHits(ctx); err != nil {
+			return fmt.Errorf("delete rule cooldowns: %w", err)
+		}
 	}
-	fmt.Fprintf(cmd.OutOrStdout(), "Deleted %d existing scores\n", deleted)
 
 	// Determine time window
 	sinceDur := cfg.Digest.Since.Duration
@@ -62,31 +93,141 @@ func rescoreAction(cmd *cobra.Command, _ []string) error {
 	}
 	sinceTime := time.Now().Add(-sinceDur)
 
-	// Get all posts in window (all unscored now)
-	posts, err := db.GetPosts(ctx, sinceTime, "")
+	scorer, err := newPostScorer(cfg, profile)
 	if err != nil {
-		return fmt.Errorf("get posts: %w", err)
+		return err
 	}
+	if err := scorer.loadBoilerplate(ctx, db); err != nil {
+		return fmt.Errorf("load boilerplate: %w", err)
+	}
+	if err := scorer.loadCooldowns(ctx, db); err != nil {
+		return fmt.Errorf("load rule cooldowns: %w", err)
+	}
+	scorer.meterTriage(ctx, db)
+	if err := scorer.loadRecurring(ctx, db); err != nil {
+		return fmt.Errorf("load recurring texts: %w", err)
+	}
+	if err := scorer.saveTasteSnapshot(ctx, db); err != nil {
+		return err
+	}
+	changes := &profileChanges{db: db, current: profile, hash: scorer.tasteHash, byHash: make(map[string]*taste.ProfileChange)}
 
-	// Re-score each post
+	// Re-score posts in the window (all unscored now, unless incremental)
+	// a page at a time
 	now := time.Now()
-	for _, pws := range posts {
-		sp := taste.Score(storePostToSourcePost(pws.Post), profile)
-		explanation, _ := json.Marshal(sp.Explanation)
-
-		storeScore := store.Score{
-			PostID:      pws.Post.ID,
-			Score:       sp.Score,
-			Labels:      sp.Labels,
-			Tier:        sp.Tier,
-			ScoredAt:    now,
-			Explanation: explanation,
+	rescored, kept := 0, 0
+	filter := store.PostFilter{Order: store.OrderIngested, Limit: rescoreBatch}
+	for {
+		posts, err := db.GetPosts(ctx, sinceTime, "", filter)
+		if err != nil {
+			return fmt.Errorf("get posts: %w", err)
+		}
+		page := len(posts)
+		var lastID int64
+		if page > 0 {
+			lastID = posts[page-1].Post.ID
 		}
-		if err := db.SaveScore(ctx, storeScore); err != nil {
-			return fmt.Errorf("save score for post %d: %w", pws.Post.ID, err)
+		var unaffected []int64 // scores still right, recorded with an older profile
+		if incremental {
+			affected := posts[:0:0]
+			for _, pws := range posts {
+				ok, err := changes.affects(ctx, pws)
+				if err != nil {
+					return err
+				}
+				switch {
+				case ok:
+					affected = append(affected, pws)
+				case pws.Score.TasteHash != scorer.tasteHash:
+					unaffected = append(unaffected, pws.Post.ID)
+				}
+			}
+			kept += len(posts) - len(affected)
+			posts = affected
 		}
+		if _, err := db.SetScoreTasteHash(ctx, unaffected, scorer.tasteHash); err != nil {
+			return err
+		}
+
+		batch := make([]store.Post, 0, len(posts))
+		for _, pws := range posts {
+			batch = append(batch, pws.Post)
+		}
+		scorer.runPreScore(ctx, batch)
+		if err := scorer.loadVectors(ctx, db, batch); err != nil {
+			return fmt.Errorf("load embeddings: %w", err)
+		}
+		for _, pws := range posts {
+			sp := scorer.scorePost(pws.Post)
+			explanation, _ := json.Marshal(sp.Explanation)
+
+			storeScore := store.Score{
+				PostID:      pws.Post.ID,
+				Score:       sp.Score,
+				Labels:      sp.Labels,
+				Tier:        sp.Tier,
+				ScoredAt:    now,
+				Explanation: explanation,
+				TasteHash:   scorer.tasteHash,
+			}
+			if err := db.SaveScore(ctx, storeScore); err != nil {
+				return fmt.Errorf("save score for post %d: %w", pws.Post.ID, err)
+			}
+		}
+		if err := scorer.saveCooldowns(ctx, db); err != nil {
+			return err
+		}
+		rescored += len(posts)
+		if page < rescoreBatch {
+			break
+		}
+		filter.AfterID = lastID
 	}
 
-	fmt.Fprintf(cmd.OutOrStdout(), "Rescored %d posts\n", len(posts))
+	fmt.Fprintf(w, "Rescored %d posts\n", rescored)
+	if incremental {
+		fmt.Fprintf(w, "Kept %d scores the taste profile change does not reach\n", kept)
+	}
 	return nil
 }
+
+// profileChanges tells which stored scores a taste profile change reaches,
+// diffing the current profile against each one scores were computed with
+// once.
+type profileChanges struct {
+	db      *store.Store
+	current *config.TasteProfile
+	hash    string
+	byHash  map[string]*taste.ProfileChange // nil when the snapshot is gone
+}
+
+// affects reports whether p must be rescored with the current profile.
+func (pc *profileChanges) affects(ctx context.Context, p store.PostWithScore) (bool, error) {
+	if p.Score == nil || p.Changed() || p.Score.TasteHash == "" {
+		return true, nil
+	}
+	if p.Score.TasteHash == pc.hash {
+		return false, nil
+	}
+	change, ok := pc.byHash[p.Score.TasteHash]
+	if !ok {
+		data, err := pc.db.GetTasteSnapshot(ctx, p.Score.TasteHash)
+		switch {
+		case errors.Is(err, store.ErrSnapshotNotFound):
+		case err != nil:
+			return false, err
+		default:
+			old, err := config.ParseTasteSnapshot(data)
+			if err != nil {
+				return false, err
+			}
+			change = taste.Changes(old, pc.current)
+		}
+		pc.byHash[p.Score.TasteHash] = change
+	}
+	var explanation []taste.ScoreContribution
+	if change == nil || len(p.Score.Explanation) == 0 || json.Unmarshal(p.Score.Explanation, &explanation) != nil {
+		return true, nil
+	}
+	return change.Affects(storePostToSourcePost(p.Post).Text, explanation), nil
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"bytes"
 	"context"
+	"os"
 	"path/filepath"
 	"testing"
 	"time"
@@ -152,6 +153,69 @@ func TestRescoreAction_NoPosts(t *testing.T) {
 	}
 }
 
+func TestRescoreAction_Profile(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	scriptPath := filepath.Join(tmpDir, "forge-plan.sh")
+
+	writeTestForgePlanScript(t, scriptPath)
+	writeTestConfig(t, tmpDir, dbPath, scriptPath)
+	writeTestTaste(t, tmpDir)
+	if err := os.MkdirAll(filepath.Join(tmpDir, "profiles"), 0o755); err != nil {
+		t.Fatalf("mkdir profiles: %v", err)
+	}
+	hobby := "weights:\n  high_signal:\n    \"webinar\": 8\nthresholds:\n  read_now: 7\n  skim: 3\n  ignore: 0\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "profiles", "hobby.yaml"), []byte(hobby), 0o644); err != nil {
+		t.Fatalf("write hobby profile: %v", err)
+	}
+
+	oldConfigDir, oldRescoreSince, oldProfile := configDir, rescoreSince, profileName
+	t.Cleanup(func() { configDir, rescoreSince, profileName = oldConfigDir, oldRescoreSince, oldProfile })
+	configDir, rescoreSince = tmpDir, ""
+
+	st, err := store.Open(dbPath)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	base := time.Now().Add(-2 * time.Hour)
+	for id, text := range map[string]string{"1": "CVE-2026-1234 kubernetes", "2": "join our webinar"} {
+		if _, err := st.InsertPost(context.Background(), store.PostInput{
+			Source: "rss", Channel: "blog", ExternalID: id, Text: text, PostedAt: base, FetchedAt: base,
+		}); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+	}
+	_ = st.Close()
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	cmd.SetOut(&bytes.Buffer{})
+	for _, name := range []string{"", "hobby"} {
+		profileName = name
+		if err := rescoreAction(cmd, nil); err != nil {
+			t.Fatalf("rescore %q: %v", name, err)
+		}
+	}
+
+	// Rescoring hobby left the default profile's scores alone.
+	st = openStoreForPipelineTest(t, dbPath)
+	for name, want := range map[string]map[string]string{
+		"":      {"1": "read_now", "2": "ignore"},
+		"hobby": {"1": "ignore", "2": "read_now"},
+	} {
+		st.SetProfile(name)
+		posts, err := st.GetPosts(context.Background(), time.Time{}, "")
+		if err != nil {
+			t.Fatalf("get posts: %v", err)
+		}
+		for _, pws := range posts {
+			if pws.Score == nil || pws.Score.Tier != want[pws.Post.ExternalID] {
+				t.Errorf("profile %q post %s score = %+v, want %s", name, pws.Post.ExternalID, pws.Score, want[pws.Post.ExternalID])
+			}
+		}
+	}
+}
+
 func containsStr(s, substr string) bool {
 	return len(s) > 0 && len(substr) > 0 && (s == substr || len(s) >= len(substr) && searchStr(s, substr))
 }
@@ -164,3 +228,115 @@ func searchStr(s, substr string) bool {
 	}
 	return false
 }
+
+func TestRescoreAction_Incremental(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	scriptPath := filepath.Join(tmpDir, "forge-plan.sh")
+
+	writeTestForgePlanScript(t, scriptPath)
+	writeTestConfig(t, tmpDir, dbPath, scriptPath)
+	writeTestTaste(t, tmpDir)
+
+	oldConfigDir := configDir
+	oldRescoreSince := rescoreSince
+	oldIncremental := rescoreIncremental
+	t.Cleanup(func() {
+		configDir = oldConfigDir
+		rescoreSince = oldRescoreSince
+		rescoreIncremental = oldIncremental
+	})
+	configDir = tmpDir
+	rescoreSince = ""
+
+	st, err := store.Open(dbPath)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	base := time.Now().Add(-2 * time.Hour)
+	for i, text := range []string{
+		"CVE-2026-1234 kubernetes breaking change affects control plane",
+		"join our webinar on best practices",
+		"postgres vacuum tuning notes",
+	} {
+		if _, err := st.InsertPost(context.Background(), store.PostInput{
+			Source: "rss", Channel: "blog", ExternalID: string(rune('1' + i)), Text: text,
+			PostedAt: base.Add(time.Duration(i) * time.Minute), FetchedAt: base,
+		}); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+	}
+	_ = st.Close()
+
+	run := func() string {
+		t.Helper()
+		cmd := &cobra.Command{}
+		cmd.SetContext(context.Background())
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		if err := rescoreAction(cmd, nil); err != nil {
+			t.Fatalf("rescore: %v", err)
+		}
+		return buf.String()
+	}
+	rescoreIncremental = false
+	run()
+
+	// Reweight webinar and add postgres: the CVE post is left alone.
+	profile := `weights:
+  high_signal:
+    "cve": 5
+    "kubernetes": 3
+    "postgres": 4
+  low_signal:
+    "webinar": -5
+labels: {}
+rules:
+  - if:
+      contains_any: ["breaking change"]
+    then:
+      score_add: 2
+      labels: ["ops"]
+thresholds:
+  read_now: 7
+  skim: 3
+  ignore: 0
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "taste.yaml"), []byte(profile), 0o644); err != nil {
+		t.Fatalf("write taste: %v", err)
+	}
+	rescoreIncremental = true
+	out := run()
+	requireContains(t, out, "Rescored 2 posts")
+	requireContains(t, out, "Kept 1 scores")
+	if containsStr(out, "Deleted") {
+		t.Errorf("incremental rescore deleted scores:\n%s", out)
+	}
+
+	st, err = store.Open(dbPath)
+	if err != nil {
+		t.Fatalf("reopen store: %v", err)
+	}
+	posts, err := st.GetPosts(context.Background(), time.Time{}, "")
+	_ = st.Close()
+	if err != nil {
+		t.Fatalf("get posts: %v", err)
+	}
+	scores := make(map[string]int)
+	hashes := make(map[string]bool)
+	for _, pws := range posts {
+		scores[pws.Post.ExternalID] = pws.Score.Score
+		hashes[pws.Score.TasteHash] = true
+	}
+	if scores["1"] != 10 || scores["2"] != -5 || scores["3"] != 4 {
+		t.Errorf("scores = %v, want 1:10 2:-5 3:4", scores)
+	}
+	if len(hashes) != 1 {
+		t.Errorf("scores carry %d taste hashes, want the current one only", len(hashes))
+	}
+
+	// Nothing changed since: nothing to rescore.
+	out = run()
+	requireContains(t, out, "Rescored 0 posts")
+	requireContains(t, out, "Kept 3 scores")
+}
@@ -2,9 +2,17 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"os"
+	"time"
 
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/logging"
+	"github.com/ppiankov/noisepan/internal/telemetry"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Version and Commit are set via ldflags at build time.
@@ -13,12 +21,60 @@ var (
 	Commit  = "none"
 )
 
-var configDir string
+var (
+	configDir   string
+	logLevel    string
+	logFormat   string
+	dryRun      bool
+	profileName string
+)
 
 var rootCmd = &cobra.Command{
 	Use:   "noisepan",
 	Short: "Extract signal from noisy information streams",
 	Long:  "noisepan reads Telegram channels, RSS feeds, and other sources, scores posts by relevance, and produces a concise terminal digest.",
+	PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+		if err := logging.Setup(os.Stderr, logLevel, logFormat); err != nil {
+			return err
+		}
+		if profileName != "" {
+			if err := config.ValidateProfile(profileName); err != nil {
+				return fmt.Errorf("--profile: %w", err)
+			}
+		}
+		if err := startTracing(cmd); err != nil {
+			return err
+		}
+		startEvents(cmd)
+		return nil
+	},
+}
+
+// Tracing state for the running command, set by startTracing and finished
+// by Execute.
+var (
+	commandSpan     trace.Span
+	shutdownTracing = func(context.Context) error { return nil }
+)
+
+// tracingFlushTimeout bounds how long exiting waits for spans to export.
+const tracingFlushTimeout = 5 * time.Second
+
+// startTracing sets up the OTLP exporter from the OTEL_* environment and
+// opens a span covering the whole command, which later spans nest under.
+func startTracing(cmd *cobra.Command) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	shutdown, err := telemetry.Setup(ctx, Version)
+	if err != nil {
+		return err
+	}
+	shutdownTracing = shutdown
+	ctx, commandSpan = telemetry.Start(ctx, cmd.CommandPath())
+	cmd.SetContext(ctx)
+	return nil
 }
 
 var versionCmd = &cobra.Command{
@@ -31,9 +87,14 @@ var versionCmd = &cobra.Command{
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&configDir, "config", ".noisepan", "config directory")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "log level: debug, info, warn, error")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "log format: text, json (logs go to stderr)")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "taste profile: profiles/<name>.yaml in the config directory, scored separately (default: config's profile, else taste.yaml)")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "run without saving: store writes are rolled back, config and taste files are left alone")
 
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(quickstartCmd)
 	rootCmd.AddCommand(pullCmd)
 	rootCmd.AddCommand(digestCmd)
 	rootCmd.AddCommand(runCmd)
@@ -43,5 +104,14 @@ func init() {
 
 // Execute runs the root command.
 func Execute() error {
-	return rootCmd.Execute()
+	err := rootCmd.Execute()
+	if commandSpan != nil {
+		telemetry.End(commandSpan, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), tracingFlushTimeout)
+	defer cancel()
+	if serr := shutdownTracing(ctx); serr != nil {
+		slog.Warn("flush traces", "err", serr)
+	}
+	return err
 }
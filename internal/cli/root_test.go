@@ -14,3 +14,14 @@ func TestExecuteVersion(t *testing.T) {
 		t.Fatalf("version command failed: %v", err)
 	}
 }
+
+func TestExecuteInvalidLogLevel(t *testing.T) {
+	t.Cleanup(func() {
+		logLevel = "info"
+		rootCmd.SetArgs(nil)
+	})
+	rootCmd.SetArgs([]string{"version", "--log-level", "loud"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Fatal("expected error for unknown log level")
+	}
+}
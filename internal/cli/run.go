@@ -2,12 +2,15 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/ppiankov/noisepan/internal/telemetry"
 	"github.com/spf13/cobra"
 )
 
@@ -26,12 +29,23 @@ var runCmd = &cobra.Command{
 func init() {
 	runCmd.Flags().StringVar(&runEvery, "every", "", "run continuously at interval (e.g. 30m)")
 	runCmd.Flags().StringVar(&digestSince, "since", "", "time window (e.g. 48h)")
-	runCmd.Flags().StringVar(&digestFormat, "format", "", "output format: terminal, json, markdown")
+	runCmd.Flags().StringVar(&digestFormat, "format", "", "output format: terminal, json, markdown, print")
+	runCmd.Flags().StringVar(&digestTemplate, "template", "", "render through a Go template file instead of a format (default digest.template)")
 	runCmd.Flags().StringVar(&digestSource, "source", "", "filter by source")
 	runCmd.Flags().StringVar(&digestChannel, "channel", "", "filter by channel name")
 	runCmd.Flags().BoolVar(&noColor, "no-color", false, "disable ANSI colors")
 	runCmd.Flags().StringVar(&digestOutput, "output", "", "write digest to file")
 	runCmd.Flags().StringVar(&digestWebhook, "webhook", "", "POST digest JSON to URL")
+	runCmd.Flags().StringVar(&digestPublish, "publish", "", "publish digest as a page: notion, confluence (comma-separated)")
+	runCmd.Flags().BoolVar(&digestEmail, "email", false, "email digest to delivery.email recipients")
+	runCmd.Flags().BoolVar(&digestTelegram, "telegram", false, "post digest to the delivery.telegram chat")
+	runCmd.Flags().BoolVar(&digestDiscord, "discord", false, "post digest to the delivery.discord webhook")
+	runCmd.Flags().BoolVar(&digestUnread, "unread-only", false, "skip posts already marked read")
+	runCmd.Flags().BoolVar(&digestMarkRead, "mark-read", false, "mark read_now and skim items shown as read")
+	runCmd.Flags().BoolVar(&digestStarred, "starred", false, "only starred posts")
+	runCmd.Flags().BoolVar(&digestSave, "save", false, "keep the digest for noisepan history")
+	runCmd.Flags().BoolVar(&digestNewOnly, "new-only", false, "skip posts a digest saved with --save listed")
+	runCmd.Flags().StringVar(&digestGroupBy, "group-by", "", "section by label, channel or source instead of tier")
 }
 
 func runAction(cmd *cobra.Command, args []string) error {
@@ -44,7 +58,8 @@ func runAction(cmd *cobra.Command, args []string) error {
 		return runPipeline(cmd, args)
 	}
 
-	ctx := cmd.Context()
+	base := cmd.Context()
+	ctx := base
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -53,7 +68,17 @@ func runAction(cmd *cobra.Command, args []string) error {
 	defer stopSignals()
 
 	return runWatch(ctx, interval, func() error {
-		return runPipeline(cmd, args)
+		// One trace per cycle rather than one that lasts as long as the process.
+		cycleCtx, span := telemetry.StartRoot(base, "noisepan run cycle")
+		cmd.SetContext(cycleCtx)
+		err := runPipeline(cmd, args)
+		telemetry.End(span, err)
+		if errors.Is(err, errAllSourcesFailed) {
+			// Monitoring has been told; an outage should not stop the loop.
+			slog.Warn("pull failed; retrying next cycle", "err", err)
+			return nil
+		}
+		return err
 	})
 }
 
@@ -72,11 +97,14 @@ func parseRunEvery(value string) (time.Duration, error) {
 	return d, nil
 }
 
+// runPipeline pulls and prints the digest once, then pings
+// monitoring.ping_url with the outcome.
 func runPipeline(cmd *cobra.Command, args []string) error {
-	if err := runPullAction(cmd, args); err != nil {
-		return err
+	err := runPullAction(cmd, args)
+	if err == nil {
+		err = runDigestAction(cmd, args)
 	}
-	return runDigestAction(cmd, args)
+	return pingMonitor(cmd, err)
 }
 
 func runWatch(ctx context.Context, interval time.Duration, runOnce func() error) error {
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"testing"
 	"time"
@@ -168,6 +169,43 @@ func TestRunActionWatchModeImmediateThenInterval(t *testing.T) {
 	}
 }
 
+func TestRunActionWatchModeSurvivesSourceOutage(t *testing.T) {
+	oldEvery := runEvery
+	oldPull := runPullAction
+	oldDigest := runDigestAction
+	t.Cleanup(func() {
+		runEvery = oldEvery
+		runPullAction = oldPull
+		runDigestAction = oldDigest
+	})
+	runEvery = (20 * time.Millisecond).String()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pulls := 0
+	runPullAction = func(_ *cobra.Command, _ []string) error {
+		pulls++
+		if pulls == 1 {
+			return fmt.Errorf("%w: rss: connection refused", errAllSourcesFailed)
+		}
+		return nil
+	}
+	runDigestAction = func(_ *cobra.Command, _ []string) error {
+		cancel()
+		return nil
+	}
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(ctx)
+	if err := runAction(cmd, nil); err != nil {
+		t.Fatalf("runAction = %v, want the loop to carry on after the outage", err)
+	}
+	if pulls != 2 {
+		t.Errorf("pulls = %d, want 2", pulls)
+	}
+}
+
 func TestRunWatchStopsOnContextCancel(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	t.Cleanup(cancel)
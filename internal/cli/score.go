@@ -0,0 +1,437 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/cache"
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/embed"
+	"github.com/ppiankov/noisepan/internal/network"
+	"github.com/ppiankov/noisepan/internal/source"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/summarize"
+	"github.com/ppiankov/noisepan/internal/taste"
+	"github.com/ppiankov/noisepan/internal/telemetry"
+	"github.com/ppiankov/noisepan/internal/transform"
+)
+
+// headlineClassifier classifies a headline into a tier.
+type headlineClassifier interface {
+	Classify(headline string) (string, error)
+}
+
+// postScorer scores posts against the taste profile and, for channels with
+// llm_triage enabled, asks an LLM about headlines that scored 0 on keywords.
+// With taste interests and embeddings configured, posts close in meaning to
+// an interest gain its points; when the trained classifier is enabled its
+// contribution is added last.
+// Boilerplate learned for a channel is stripped before anything is scored,
+// and the pre_score hook, if configured, may rewrite the text first. Posts
+// repeating a text their channel posted earlier are labeled recurring.
+type postScorer struct {
+	profile        *config.TasteProfile
+	triage         headlineClassifier
+	triageChannels map[string]bool
+	classifier     *taste.Classifier
+	embedder       embed.Embedder         // nil without interests or embeddings
+	embedBatch     int                    // embed.batch_size
+	cachePath      string                 // interest embeddings are cached here
+	interests      *taste.Interests       // embedded on first use
+	vectors        map[int64]embed.Vector // post ID -> embedding, while scoring
+	useBoilerplate bool
+	boilerplate    map[string]map[string]bool // "source/channel" -> blocks
+	preScoreHook   string
+	hookTimeout    time.Duration
+	rescoreChanged bool                 // treat posts edited since scoring as unscored
+	cooldowns      *taste.Cooldowns     // rule cooldowns, while scoring; nil when no rule has one
+	recurringGap   time.Duration        // dedup.recurring.min_gap; 0 when recurrences merge as duplicates
+	suppressRecur  bool                 // rank recurring posts as ignore
+	repeated       map[string]time.Time // "source/channel/text_hash" -> first posted, while scoring
+	hooked         map[int64]hookPost   // post ID -> pre_score hook output
+	traceCtx       context.Context      // span triage calls are traced under, while scoring
+	tasteHash      string               // profile.Hash(), saved with every score
+	llm            config.LLMConfig     // summarize.llm, for metering triage
+}
+
+// loadTaste loads the taste profile at path with summarize.products, the
+// dictionary its has_entity rules look products up in. Cfg may be nil.
+func loadTaste(cfg *config.Config, path string) (*config.TasteProfile, error) {
+	profile, err := config.LoadTaste(path)
+	if err != nil {
+		return nil, err
+	}
+	if cfg != nil {
+		profile.Products = cfg.Summarize.Products
+	}
+	return profile, nil
+}
+
+func newPostScorer(cfg *config.Config, profile *config.TasteProfile) (*postScorer, error) {
+	ps := &postScorer{
+		profile:        profile,
+		useBoilerplate: !cfg.Boilerplate.Disabled,
+		preScoreHook:   cfg.Hooks.PreScore,
+		hookTimeout:    cfg.Hooks.Timeout.Duration,
+		rescoreChanged: cfg.Digest.RescoreChanged,
+		recurringGap:   recurringGap(cfg.Dedup.Recurring),
+		suppressRecur:  cfg.Dedup.Recurring.Mode == "suppress",
+		tasteHash:      profile.Hash(),
+	}
+
+	if profile.Classifier.Enabled {
+		c, err := taste.LoadClassifier(filepath.Join(configDir, config.DefaultClassifierFile))
+		if err != nil {
+			slog.Warn("classifier enabled but unavailable; run 'noisepan taste train'", "err", err)
+		} else {
+			ps.classifier = c
+		}
+	}
+
+	if len(profile.Interests) > 0 {
+		e, err := newTracedEmbedder(cfg, ps.traceParent)
+		switch {
+		case err != nil:
+			slog.Warn("taste interests unavailable", "err", err)
+		case e == nil:
+			slog.Warn("taste interests need embeddings; set embed.provider in config.yaml")
+		default:
+			ps.embedder = e
+			ps.embedBatch = cfg.Embed.BatchSize
+			// A dry run leaves cache.db alone: it is not part of the
+			// store's transaction.
+			if !cfg.Cache.Disabled && !dryRun {
+				ps.cachePath = cfg.Cache.Path
+			}
+		}
+	}
+
+	for name, ch := range cfg.Channels {
+		if ch.LLMTriage {
+			if ps.triageChannels == nil {
+				ps.triageChannels = make(map[string]bool)
+			}
+			ps.triageChannels[name] = true
+		}
+	}
+	if len(ps.triageChannels) == 0 || (cfg.Summarize.LLM.APIKey == "" && cfg.Summarize.LLM.NeedsAPIKey()) {
+		return ps, nil
+	}
+
+	tc := cfg.Summarize.LLM.Triage
+	provider, err := summarize.NewProvider(cfg.Summarize.LLM.Provider, cfg.Summarize.LLM.Endpoint, cfg.Summarize.LLM.APIKey)
+	if err != nil {
+		return nil, err
+	}
+	tr := summarize.NewTriage(provider, tc.Model, tc.Interval.Duration, tc.MaxPerRun)
+	transport, err := network.NewTransport(cfg.Network)
+	if err != nil {
+		return nil, fmt.Errorf("build http transport: %w", err)
+	}
+	tr.SetTransport(telemetry.NewTransport("llm", transport, ps.traceParent))
+	tr.SetTimeout(cfg.Summarize.LLM.Timeout.Duration)
+	ps.triage = tr
+	ps.llm = cfg.Summarize.LLM
+
+	return ps, nil
+}
+
+// traceParent is the context LLM triage requests are traced under.
+func (ps *postScorer) traceParent() context.Context {
+	if ps.traceCtx != nil {
+		return ps.traceCtx
+	}
+	return context.Background()
+}
+
+// loadBoilerplate reads the blocks learned by pull. It is a no-op when
+// boilerplate detection is disabled.
+func (ps *postScorer) loadBoilerplate(ctx context.Context, db *store.Store) error {
+	if !ps.useBoilerplate {
+		return nil
+	}
+	blocks, err := db.GetBoilerplate(ctx)
+	if err != nil {
+		return err
+	}
+	ps.boilerplate = make(map[string]map[string]bool)
+	for _, b := range blocks {
+		key := b.Source + "/" + b.Channel
+		if ps.boilerplate[key] == nil {
+			ps.boilerplate[key] = make(map[string]bool)
+		}
+		ps.boilerplate[key][b.Text] = true
+	}
+	return nil
+}
+
+// meterTriage records the token usage of triage calls in db and stops
+// triage once summarize.llm.monthly_budget is spent.
+func (ps *postScorer) meterTriage(ctx context.Context, db *store.Store) {
+	if tr, ok := ps.triage.(*summarize.Triager); ok {
+		tr.SetMeter(newLLMMeter(ctx, ps.llm, db))
+	}
+}
+
+// loadCooldowns reads the saved rule hits when a taste rule has a cooldown.
+func (ps *postScorer) loadCooldowns(ctx context.Context, db *store.Store) error {
+	ps.cooldowns = nil
+	if !slices.ContainsFunc(ps.profile.Rules, func(r config.Rule) bool { return r.Cooldown.Duration > 0 }) {
+		return nil
+	}
+	saved, err := db.GetRuleHits(ctx)
+	if err != nil {
+		return err
+	}
+	hits := make([]taste.CooldownHit, len(saved))
+	for i, h := range saved {
+		hits[i] = taste.CooldownHit{Rule: h.Rule, Source: h.Source, Channel: h.Channel, At: h.HitAt}
+	}
+	ps.cooldowns = taste.NewCooldowns(hits)
+	return nil
+}
+
+// loadRecurring reads the texts channels have posted more than once. It is
+// a no-op when recurrences merge as duplicates, as pull then removed them.
+func (ps *postScorer) loadRecurring(ctx context.Context, db *store.Store) error {
+	ps.repeated = nil
+	if ps.recurringGap == 0 {
+		return nil
+	}
+	texts, err := db.GetRepeatedTexts(ctx)
+	if err != nil {
+		return err
+	}
+	ps.repeated = make(map[string]time.Time, len(texts))
+	for _, rt := range texts {
+		ps.repeated[rt.Source+"/"+rt.Channel+"/"+rt.TextHash] = rt.FirstPosted
+	}
+	return nil
+}
+
+// loadVectors prepares interest scoring of posts: it embeds the profile's
+// interests on first use and reads the posts' embeddings, embedding those
+// pull has not. It is a no-op without interests; embedding failures only
+// leave the interests out.
+func (ps *postScorer) loadVectors(ctx context.Context, db *store.Store, posts []store.Post) error {
+	ps.vectors = nil
+	if ps.embedder == nil || len(posts) == 0 {
+		return nil
+	}
+	if ps.interests == nil {
+		var c *cache.Cache
+		if ps.cachePath != "" {
+			if opened, err := cache.Open(ps.cachePath); err == nil {
+				c = opened
+				defer func() { _ = c.Close() }()
+			}
+		}
+		in, err := embedInterests(ctx, ps.embedder, c, ps.profile.Interests)
+		if err != nil {
+			slog.Warn("scoring without taste interests", "err", err)
+			ps.embedder = nil
+			return nil
+		}
+		ps.interests = in
+	}
+
+	model := ps.embedder.Model()
+	ids := make([]int64, len(posts))
+	for i, p := range posts {
+		ids[i] = p.ID
+	}
+	vectors, err := db.GetEmbeddings(ctx, model, ids)
+	if err != nil {
+		return err
+	}
+	if vectors == nil {
+		vectors = make(map[int64]embed.Vector, len(posts))
+	}
+	var missing []store.Post
+	for _, p := range posts {
+		if _, ok := vectors[p.ID]; !ok {
+			missing = append(missing, p)
+		}
+	}
+	batch := ps.embedBatch
+	if batch <= 0 {
+		batch = len(missing)
+	}
+	for start := 0; start < len(missing); start += batch {
+		embedded, err := embedPosts(ctx, ps.embedder, missing[start:min(start+batch, len(missing))])
+		if err != nil {
+			slog.Warn("embedding posts failed; scoring them without interests", "err", err)
+			break
+		}
+		if err := db.SaveEmbeddings(ctx, model, embedded, time.Now()); err != nil {
+			return err
+		}
+		maps.Copy(vectors, embedded)
+	}
+	ps.vectors = vectors
+	return nil
+}
+
+// recurring reports whether p repeats a text its channel first posted at
+// least dedup.recurring.min_gap earlier.
+func (ps *postScorer) recurring(p store.Post) bool {
+	first, ok := ps.repeated[p.Source+"/"+p.Channel+"/"+p.TextHash]
+	return ok && p.PostedAt.Sub(first) >= ps.recurringGap
+}
+
+// recurringGap is how far apart a channel's identical posts must be to
+// recur, or 0 when dedup.recurring.mode merges them.
+func recurringGap(rc config.RecurringConfig) time.Duration {
+	if rc.Mode == "merge" {
+		return 0
+	}
+	return rc.MinGap.Duration
+}
+
+// saveCooldowns stores the rule hits recorded since loadCooldowns.
+func (ps *postScorer) saveCooldowns(ctx context.Context, db *store.Store) error {
+	var hits []store.RuleHit
+	for _, h := range ps.cooldowns.Changed() {
+		hits = append(hits, store.RuleHit{Rule: h.Rule, Source: h.Source, Channel: h.Channel, HitAt: h.At})
+	}
+	if err := db.SaveRuleHits(ctx, hits); err != nil {
+		return fmt.Errorf("save rule cooldowns: %w", err)
+	}
+	return nil
+}
+
+// stripBoilerplate removes the channel's learned boilerplate from text.
+func (ps *postScorer) stripBoilerplate(src, channel, text string) string {
+	return transform.StripBoilerplate(text, ps.boilerplate[src+"/"+channel])
+}
+
+// saveTasteSnapshot records the profile scores are about to be saved with,
+// under the hash they carry, for "rescore --incremental" to diff against.
+func (ps *postScorer) saveTasteSnapshot(ctx context.Context, db *store.Store) error {
+	if ps.tasteHash == "" {
+		return nil
+	}
+	data, err := ps.profile.Snapshot()
+	if err != nil {
+		return err
+	}
+	if err := db.SaveTasteSnapshot(ctx, ps.tasteHash, data); err != nil {
+		return fmt.Errorf("save taste snapshot: %w", err)
+	}
+	return nil
+}
+
+// needsScore reports whether p must be (re)scored: it has no score, or it
+// was edited since and digest.rescore_changed is on.
+func (ps *postScorer) needsScore(p store.PostWithScore) bool {
+	return p.Score == nil || (ps.rescoreChanged && p.Changed())
+}
+
+// scorePost scores a stored post, applying the pre_score hook's changes
+// from the last runPreScore.
+func (ps *postScorer) scorePost(p store.Post) taste.ScoredPost {
+	post := storePostToSourcePost(p)
+	hp, ok := ps.hooked[p.ID]
+	if ok && hp.Text != "" {
+		post.Text = hp.Text
+	}
+	sp := ps.scoreEmbedded(post, ps.vectors[p.ID])
+	if ok {
+		sp.Labels = mergeLabels(sp.Labels, hp.Labels)
+	}
+	recurring := ps.recurring(p)
+	if recurring {
+		sp.Labels = mergeLabels(sp.Labels, []string{recurringLabel})
+	}
+	// label_tiers bound the labels added here as well as the profile's.
+	sp = taste.RebindLabels(sp, ps.profile)
+	if recurring && ps.suppressRecur {
+		sp.Tier = taste.TierIgnore
+		sp.Explanation = append(sp.Explanation, taste.ScoreContribution{Reason: "recurring: suppressed"})
+	}
+	return sp
+}
+
+// recurringLabel marks a post whose text its channel posted before.
+const recurringLabel = "recurring"
+
+func (ps *postScorer) score(post source.Post) taste.ScoredPost {
+	return ps.scoreEmbedded(post, nil)
+}
+
+// scoreEmbedded is score with vector, the post's embedding, for its
+// interests.
+func (ps *postScorer) scoreEmbedded(post source.Post, vector embed.Vector) taste.ScoredPost {
+	post.Text = ps.stripBoilerplate(post.Source, post.Channel, post.Text)
+	sp := ps.baseScore(post)
+	sp = ps.interests.Apply(sp, vector, ps.profile.TierSet())
+	if ps.classifier != nil {
+		sp = ps.classifier.Apply(sp, ps.profile.Classifier.MaxPoints, ps.profile.TierSet())
+	}
+	return sp
+}
+
+func (ps *postScorer) baseScore(post source.Post) taste.ScoredPost {
+	sp := taste.ScoreWithCooldowns(post, ps.profile, ps.cooldowns)
+	if sp.Score != 0 || sp.Pinned() || ps.triage == nil || !ps.triageChannels[post.Channel] {
+		return sp
+	}
+
+	tier, err := ps.triage.Classify(headline(post.Text))
+	if errors.Is(err, summarize.ErrTriageBudget) || errors.Is(err, summarize.ErrBudget) {
+		slog.Warn("llm triage disabled for remainder of run", "err", err)
+		ps.triage = nil
+		return sp
+	}
+	if err != nil {
+		slog.Warn("llm triage failed", "channel", post.Channel, "err", err)
+		return sp
+	}
+
+	points := 0
+	switch tier {
+	case taste.TierReadNow:
+		points = ps.profile.Thresholds.ReadNow
+	case taste.TierSkim:
+		points = ps.profile.Thresholds.Skim
+		tier = skimTier(ps.profile)
+	}
+	sp.Score += points
+	sp.Tier = sp.BoundTier(tier, ps.profile.TierSet())
+	sp.Explanation = append(sp.Explanation, taste.ScoreContribution{
+		Reason: "llm triage: " + tier,
+		Points: points,
+	})
+	return sp
+}
+
+// skimTier is the tier a skim triage answer files a post under: skim itself,
+// or with custom tiers the lowest one above ignore, or ignore if there is
+// none.
+func skimTier(profile *config.TasteProfile) string {
+	tiers := taste.TierNames(profile)
+	switch {
+	case slices.Contains(tiers, taste.TierSkim):
+		return taste.TierSkim
+	case len(tiers) > 2:
+		return tiers[len(tiers)-2]
+	}
+	return taste.TierIgnore
+}
+
+// headline returns the first non-empty line of text.
+func headline(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
@@ -0,0 +1,342 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/source"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/taste"
+)
+
+type fakeClassifier struct {
+	tier  string
+	err   error
+	calls []string
+}
+
+func (f *fakeClassifier) Classify(h string) (string, error) {
+	f.calls = append(f.calls, h)
+	return f.tier, f.err
+}
+
+func testScorerProfile() *config.TasteProfile {
+	return &config.TasteProfile{
+		Weights:    config.Weights{HighSignal: map[string]int{"cve": 5}},
+		Thresholds: config.Thresholds{ReadNow: 7, Skim: 3, Ignore: 0},
+	}
+}
+
+func TestPostScorer_Triage(t *testing.T) {
+	fc := &fakeClassifier{tier: taste.TierSkim}
+	ps := &postScorer{
+		profile:        testScorerProfile(),
+		triage:         fc,
+		triageChannels: map[string]bool{"Tech News": true},
+	}
+
+	sp := ps.score(source.Post{Channel: "Tech News", Text: "\nNew datacenter chip announced\nbody"})
+	if sp.Tier != taste.TierSkim || sp.Score != 3 {
+		t.Errorf("got tier %q score %d, want skim 3", sp.Tier, sp.Score)
+	}
+	if len(fc.calls) != 1 || fc.calls[0] != "New datacenter chip announced" {
+		t.Errorf("classifier calls = %q, want headline only", fc.calls)
+	}
+	last := sp.Explanation[len(sp.Explanation)-1]
+	if last.Reason != "llm triage: skim" || last.Points != 3 {
+		t.Errorf("explanation = %+v", last)
+	}
+
+	// Keyword-scored posts and other channels are left alone
+	ps.score(source.Post{Channel: "Tech News", Text: "cve in openssl"})
+	ps.score(source.Post{Channel: "Other", Text: "anything"})
+	if len(fc.calls) != 1 {
+		t.Errorf("classifier calls = %d, want 1", len(fc.calls))
+	}
+}
+
+func TestPostScorer_TriageCustomTiers(t *testing.T) {
+	profile := testScorerProfile()
+	profile.Tiers = []config.Tier{{Name: taste.TierReadNow, MinScore: 7}, {Name: "today", MinScore: 5}, {Name: "weekend", MinScore: 3}, {Name: taste.TierIgnore}}
+	ps := &postScorer{
+		profile:        profile,
+		triage:         &fakeClassifier{tier: taste.TierSkim},
+		triageChannels: map[string]bool{"Tech News": true},
+	}
+
+	// Skim answers land in the lowest tier above ignore.
+	sp := ps.score(source.Post{Channel: "Tech News", Text: "New datacenter chip announced"})
+	if sp.Tier != "weekend" || sp.Score != 3 {
+		t.Errorf("got tier %q score %d, want weekend 3", sp.Tier, sp.Score)
+	}
+
+	profile.Tiers = []config.Tier{{Name: taste.TierReadNow, MinScore: 7}, {Name: taste.TierIgnore}}
+	if got := skimTier(profile); got != taste.TierIgnore {
+		t.Errorf("skimTier without middle tiers = %q, want ignore", got)
+	}
+}
+
+func TestPostScorer_TriageErrorKeepsKeywordScore(t *testing.T) {
+	fc := &fakeClassifier{err: errors.New("api down")}
+	ps := &postScorer{
+		profile:        testScorerProfile(),
+		triage:         fc,
+		triageChannels: map[string]bool{"Tech News": true},
+	}
+
+	sp := ps.score(source.Post{Channel: "Tech News", Text: "headline"})
+	if sp.Tier != taste.TierIgnore || sp.Score != 0 {
+		t.Errorf("got tier %q score %d, want ignore 0", sp.Tier, sp.Score)
+	}
+}
+
+func TestNewPostScorer_NoAPIKey(t *testing.T) {
+	cfg := &config.Config{Channels: map[string]config.ChannelConfig{"Tech News": {LLMTriage: true}}}
+	ps, err := newPostScorer(cfg, testScorerProfile())
+	if err != nil {
+		t.Fatalf("new scorer: %v", err)
+	}
+	if ps.triage != nil {
+		t.Error("triage enabled without api key")
+	}
+}
+
+func TestPostScorer_StripsBoilerplate(t *testing.T) {
+	profile := testScorerProfile()
+	profile.Weights.LowSignal = map[string]int{"webinar": -4}
+	ps := &postScorer{
+		profile: profile,
+		boilerplate: map[string]map[string]bool{
+			"rss/Vendor Blog": {"Register for our weekly webinar at example.com": true},
+		},
+	}
+
+	text := "CVE-2026-1 patched\n\nRegister for our weekly webinar at example.com"
+	sp := ps.score(source.Post{Source: "rss", Channel: "Vendor Blog", Text: text})
+	if sp.Score != 5 {
+		t.Errorf("score = %d, want 5 with footer ignored", sp.Score)
+	}
+
+	// Other channels keep the block.
+	sp = ps.score(source.Post{Source: "rss", Channel: "Other", Text: text})
+	if sp.Score != 1 {
+		t.Errorf("other channel score = %d, want 1", sp.Score)
+	}
+}
+
+func TestPostScorer_Recurring(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "noisepan.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+	ctx := context.Background()
+
+	base := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	for i, at := range []time.Time{base, base.Add(24 * time.Hour)} {
+		if _, err := st.InsertPost(ctx, store.PostInput{
+			Source: "rss", Channel: "standup", ExternalID: string(rune('a' + i)),
+			Text: "cve triage standup", PostedAt: at, FetchedAt: at,
+		}); err != nil {
+			t.Fatalf("insert %d: %v", i, err)
+		}
+	}
+	posts, err := st.GetUnscored(ctx)
+	if err != nil || len(posts) != 2 {
+		t.Fatalf("unscored = %d posts, %v", len(posts), err)
+	}
+
+	cfg := &config.Config{Dedup: config.DedupConfig{Recurring: config.RecurringConfig{
+		Mode: "label", MinGap: config.Duration{Duration: 20 * time.Hour},
+	}}}
+	ps, err := newPostScorer(cfg, testScorerProfile())
+	if err != nil {
+		t.Fatalf("new scorer: %v", err)
+	}
+	if err := ps.loadRecurring(ctx, st); err != nil {
+		t.Fatalf("load recurring: %v", err)
+	}
+	for _, p := range posts {
+		sp := ps.scorePost(p)
+		want := p.PostedAt.After(base)
+		if got := slices.Contains(sp.Labels, recurringLabel); got != want {
+			t.Errorf("post at %v recurring = %v, want %v", p.PostedAt, got, want)
+		}
+		if sp.Tier != taste.TierSkim {
+			t.Errorf("label mode tier = %q, want skim", sp.Tier)
+		}
+	}
+
+	cfg.Dedup.Recurring.Mode = "suppress"
+	if ps, err = newPostScorer(cfg, testScorerProfile()); err != nil {
+		t.Fatalf("new scorer: %v", err)
+	}
+	if err := ps.loadRecurring(ctx, st); err != nil {
+		t.Fatalf("load recurring: %v", err)
+	}
+	for _, p := range posts {
+		if sp := ps.scorePost(p); p.PostedAt.After(base) && sp.Tier != taste.TierIgnore {
+			t.Errorf("suppressed tier = %q, want ignore", sp.Tier)
+		}
+	}
+
+	cfg.Dedup.Recurring.Mode = "merge"
+	if ps, err = newPostScorer(cfg, testScorerProfile()); err != nil {
+		t.Fatalf("new scorer: %v", err)
+	}
+	if err := ps.loadRecurring(ctx, st); err != nil || ps.repeated != nil {
+		t.Errorf("merge mode loaded %v, %v; want nothing", ps.repeated, err)
+	}
+}
+
+func TestPostScorer_NeedsScore(t *testing.T) {
+	scored := store.PostWithScore{
+		Post:  store.Post{TextHash: "new"},
+		Score: &store.Score{TextHash: "old"},
+	}
+	unscored := store.PostWithScore{Post: store.Post{TextHash: "new"}}
+
+	ps := &postScorer{profile: testScorerProfile()}
+	if !ps.needsScore(unscored) {
+		t.Error("unscored post not selected")
+	}
+	if ps.needsScore(scored) {
+		t.Error("edited post selected without rescore_changed")
+	}
+
+	ps.rescoreChanged = true
+	if !ps.needsScore(scored) {
+		t.Error("edited post not selected with rescore_changed")
+	}
+	scored.Score.TextHash = "new"
+	if ps.needsScore(scored) {
+		t.Error("unchanged post selected with rescore_changed")
+	}
+}
+
+func TestScoreUnscored_RuleCooldown(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "noisepan.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+	ctx := context.Background()
+	day := time.Date(2026, 3, 2, 6, 0, 0, 0, time.UTC)
+
+	profile := testScorerProfile()
+	profile.Rules = []config.Rule{{
+		If:       config.RuleCondition{ContainsAny: []string{"certificate expired"}},
+		Then:     config.RuleAction{ScoreAdd: 4},
+		Cooldown: config.Duration{Duration: 7 * 24 * time.Hour},
+	}}
+	insert := func(id string, at time.Time) {
+		t.Helper()
+		if _, err := st.InsertPost(ctx, store.PostInput{
+			Source: "telegram", Channel: "@alerts", ExternalID: id,
+			Text: "certificate expired on " + id, PostedAt: at, FetchedAt: at,
+		}); err != nil {
+			t.Fatalf("insert %s: %v", id, err)
+		}
+	}
+	scores := func() map[string]int {
+		t.Helper()
+		posts, err := st.GetPosts(ctx, time.Time{}, "", store.PostFilter{})
+		if err != nil {
+			t.Fatalf("get posts: %v", err)
+		}
+		if err := scoreUnscored(ctx, st, &postScorer{profile: profile}, posts, day); err != nil {
+			t.Fatalf("score: %v", err)
+		}
+		out := make(map[string]int)
+		for _, p := range posts {
+			out[p.Post.ExternalID] = p.Score.Score
+		}
+		return out
+	}
+
+	insert("a", day)
+	insert("b", day.Add(time.Hour))
+	if got := scores(); got["a"] != 4 || got["b"] != 0 {
+		t.Errorf("first run = %v, want only the oldest boosted", got)
+	}
+
+	// The window carries over to later runs, then expires.
+	insert("c", day.Add(3*24*time.Hour))
+	insert("d", day.Add(8*24*time.Hour))
+	if got := scores(); got["c"] != 0 || got["d"] != 4 {
+		t.Errorf("second run = %v, want c on cooldown and d boosted", got)
+	}
+}
+
+func TestPostScorer_Interests(t *testing.T) {
+	srv := newTestEmbedServer(t)
+	dbPath := setupEmbedPipeline(t, srv, "")
+	writeTestTaste(t, configDir)
+	tastePath := filepath.Join(configDir, config.DefaultTasteFile)
+	data, err := os.ReadFile(tastePath)
+	if err != nil {
+		t.Fatalf("read taste: %v", err)
+	}
+	data = append(data, []byte("interests:\n  - description: webinar events\n    points: 12\n")...)
+	if err := os.WriteFile(tastePath, data, 0o644); err != nil {
+		t.Fatalf("write taste: %v", err)
+	}
+
+	cfg, err := config.Load(configDir)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	profile, err := config.LoadTaste(tastePath)
+	if err != nil {
+		t.Fatalf("load taste: %v", err)
+	}
+	ps, err := newPostScorer(cfg, profile)
+	if err != nil {
+		t.Fatalf("new scorer: %v", err)
+	}
+
+	// Posts pull has not embedded are embedded while scoring.
+	ctx := context.Background()
+	st, err := store.Open(dbPath)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+	now := time.Now()
+	for i, text := range []string{"Join our webinar on cluster best practices", "Kubernetes 1.34 released"} {
+		if _, err := st.InsertPost(ctx, store.PostInput{
+			Source: "rss", Channel: "blog", ExternalID: strconv.Itoa(i), Text: text, PostedAt: now, FetchedAt: now,
+		}); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+	}
+	posts, err := st.GetPosts(ctx, time.Time{}, "")
+	if err != nil {
+		t.Fatalf("get posts: %v", err)
+	}
+	if err := scoreUnscored(ctx, st, ps, posts, now); err != nil {
+		t.Fatalf("score: %v", err)
+	}
+
+	for _, p := range posts {
+		webinar := strings.Contains(p.Post.Text, "webinar")
+		// The webinar keyword's -4 is outweighed by the interest.
+		if webinar && (p.Score.Score != 8 || p.Score.Tier != taste.TierReadNow || !strings.Contains(string(p.Score.Explanation), "interest: webinar events (1.00)")) {
+			t.Errorf("webinar post score = %d %s %s", p.Score.Score, p.Score.Tier, p.Score.Explanation)
+		}
+		if !webinar && strings.Contains(string(p.Score.Explanation), "interest") {
+			t.Errorf("post %q matched the interest: %s", p.Post.Text, p.Score.Explanation)
+		}
+	}
+	if missing, err := st.PostsWithoutEmbedding(ctx, "test-embed", time.Time{}, 0); err != nil || len(missing) != 0 {
+		t.Errorf("%d posts left unembedded, err %v", len(missing), err)
+	}
+}
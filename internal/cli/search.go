@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/taste"
+	"github.com/spf13/cobra"
+)
+
+var (
+	searchTier     string
+	searchSince    string
+	searchFormat   string
+	searchLimit    int
+	searchStarred  bool
+	searchSemantic bool
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Full-text search over stored posts",
+	Long: `Searches post text with the SQLite full-text index and prints matches
+ranked by relevance. All terms must match; punctuation such as CVE-2026-1234
+is matched literally.
+
+With --semantic the query is embedded instead and posts are ranked by how
+close their meaning is, so "cdn outage" also finds "Cloudflare is down".
+It needs embed.provider in config.yaml and matches embedded posts only.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: searchAction,
+}
+
+func init() {
+	searchCmd.Flags().StringVar(&searchTier, "tier", "", "only posts in tier: read_now, skim, ignore")
+	searchCmd.Flags().StringVar(&searchSince, "since", "", "time window (e.g. 7d, 48h); default all stored posts")
+	searchCmd.Flags().StringVar(&searchFormat, "format", "terminal", "output format: terminal, json")
+	searchCmd.Flags().IntVar(&searchLimit, "limit", 20, "maximum number of results (0 for all)")
+	searchCmd.Flags().BoolVar(&searchStarred, "starred", false, "only starred posts")
+	searchCmd.Flags().BoolVar(&searchSemantic, "semantic", false, "rank by meaning using embeddings")
+	rootCmd.AddCommand(searchCmd)
+}
+
+type searchItem struct {
+	ID       int64    `json:"id"`
+	Source   string   `json:"source"`
+	Channel  string   `json:"channel"`
+	URL      string   `json:"url,omitempty"`
+	PostedAt string   `json:"posted_at"`
+	Score    *int     `json:"score,omitempty"`
+	Tier     string   `json:"tier,omitempty"`
+	Snippet  string   `json:"snippet"`
+	AlsoIn   []string `json:"also_in,omitempty"`
+	Rank     float64  `json:"rank"`
+}
+
+func searchAction(cmd *cobra.Command, args []string) error {
+	if err := validateSearchFormat(searchFormat); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(configDir)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	if searchTier != "" {
+		// Search works without a taste profile; the tiers are then the
+		// default three.
+		profile, err := loadTaste(cfg, profileTastePath(cfg))
+		if err != nil {
+			profile = &config.TasteProfile{}
+		}
+		if tiers := taste.TierNames(profile); !slices.Contains(tiers, searchTier) {
+			return fmt.Errorf("unknown tier %q (want %s)", searchTier, strings.Join(tiers, ", "))
+		}
+	}
+
+	filter := store.SearchFilter{Tier: searchTier, Limit: searchLimit, StarredOnly: searchStarred}
+	if searchSince != "" {
+		sinceDur, err := parseDuration(searchSince)
+		if err != nil {
+			return fmt.Errorf("parse --since: %w", err)
+		}
+		filter.Since = time.Now().Add(-sinceDur)
+	}
+
+	db, err := openStore(cfg)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	ctx := cmd.Context()
+	query := strings.Join(args, " ")
+
+	var results []store.SearchResult
+	if searchSemantic {
+		results, err = semanticSearch(ctx, db, cfg, query, filter)
+	} else {
+		results, err = db.Search(ctx, query, filter)
+	}
+	if err != nil {
+		return err
+	}
+	return writeSearchResults(cmd, db, cfg, results, searchFormat)
+}
+
+// semanticSearch embeds query and returns the posts closest to it.
+func semanticSearch(ctx context.Context, db *store.Store, cfg *config.Config, query string, filter store.SearchFilter) ([]store.SearchResult, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, errors.New("search query is empty")
+	}
+	embedder, err := requireEmbedder(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	vecs, err := embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("embed query: %w", err)
+	}
+	return db.SemanticSearch(ctx, embedder.Model(), vecs[0], filter)
+}
+
+func validateSearchFormat(format string) error {
+	if format != "terminal" && format != "json" && format != "" {
+		return fmt.Errorf("unknown format %q (want terminal or json)", format)
+	}
+	return nil
+}
+
+// writeSearchResults prints results, with their also-in channels, in format.
+func writeSearchResults(cmd *cobra.Command, db *store.Store, cfg *config.Config, results []store.SearchResult, format string) error {
+	ctx := cmd.Context()
+	ids := make([]int64, len(results))
+	for i, r := range results {
+		ids[i] = r.Post.ID
+	}
+	alsoIn, err := db.GetAlsoIn(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("get also_in: %w", err)
+	}
+
+	items := make([]searchItem, 0, len(results))
+	for _, r := range results {
+		item := searchItem{
+			ID:       r.Post.ID,
+			Source:   r.Post.Source,
+			Channel:  r.Post.Channel,
+			URL:      r.Post.URL,
+			PostedAt: r.Post.PostedAt.UTC().Format(time.RFC3339),
+			Snippet:  searchSnippet(r.Post),
+			AlsoIn:   orderAlsoIn(alsoIn[r.Post.ID], cfg.Digest.AlsoInOrder),
+			Rank:     r.Rank,
+		}
+		if r.Score != nil {
+			score := r.Score.Score
+			item.Score = &score
+			item.Tier = r.Score.Tier
+		}
+		items = append(items, item)
+	}
+
+	w := cmd.OutOrStdout()
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(items)
+	}
+	printSearchResults(w, items)
+	return nil
+}
+
+func printSearchResults(w io.Writer, items []searchItem) {
+	if len(items) == 0 {
+		fmt.Fprintln(w, "No matches.")
+		return
+	}
+	for _, it := range items {
+		score, tier := "-", "unscored"
+		if it.Score != nil {
+			score, tier = fmt.Sprintf("%d", *it.Score), it.Tier
+		}
+		fmt.Fprintf(w, "#%d [%s] %s %s/%s — %s\n", it.ID, score, tier, it.Source, it.Channel, it.Snippet)
+		if it.URL != "" {
+			fmt.Fprintf(w, "    %s\n", it.URL)
+		}
+		if len(it.AlsoIn) > 0 {
+			fmt.Fprintf(w, "    also in: %s\n", strings.Join(it.AlsoIn, ", "))
+		}
+	}
+}
+
+// searchSnippet returns the first line of the post, capped at 120 runes.
+func searchSnippet(p store.Post) string {
+	text := p.Text
+	if text == "" {
+		text = p.Snippet
+	}
+	return firstNRunes(headline(text), 120)
+}
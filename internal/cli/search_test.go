@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func TestSearchAction(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	scriptPath := filepath.Join(tmpDir, "forge-plan.sh")
+
+	writeTestForgePlanScript(t, scriptPath)
+	writeTestConfig(t, tmpDir, dbPath, scriptPath)
+
+	st, err := store.Open(dbPath)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	ctx := context.Background()
+	now := time.Now()
+	p, err := st.InsertPost(ctx, store.PostInput{
+		Source: "rss", Channel: "security", ExternalID: "1",
+		Text: "Critical OpenSSL CVE-2026-1234\nPatch now", URL: "https://example.com/cve",
+		PostedAt: now.Add(-time.Hour), FetchedAt: now,
+	})
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if err := st.SaveScore(ctx, store.Score{PostID: p.ID, Score: 8, Tier: "read_now", ScoredAt: now}); err != nil {
+		t.Fatalf("save score: %v", err)
+	}
+	if _, err := st.InsertPost(ctx, store.PostInput{
+		Source: "telegram", Channel: "devops", ExternalID: "2",
+		Text: "Old OpenSSL news", PostedAt: now.Add(-10 * 24 * time.Hour), FetchedAt: now,
+	}); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	_ = st.Close()
+
+	oldConfigDir, oldTier, oldSince, oldFormat, oldLimit := configDir, searchTier, searchSince, searchFormat, searchLimit
+	t.Cleanup(func() {
+		configDir, searchTier, searchSince, searchFormat, searchLimit = oldConfigDir, oldTier, oldSince, oldFormat, oldLimit
+	})
+	configDir = tmpDir
+	searchLimit = 20
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(ctx)
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+
+	// Terminal, all time
+	searchTier, searchSince, searchFormat = "", "", "terminal"
+	if err := searchAction(cmd, []string{"openssl"}); err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	out := buf.String()
+	requireContains(t, out, "[8] read_now rss/security — Critical OpenSSL CVE-2026-1234")
+	requireContains(t, out, "[-] unscored telegram/devops — Old OpenSSL news")
+
+	// JSON with since + tier filters
+	buf.Reset()
+	searchTier, searchSince, searchFormat = "read_now", "7d", "json"
+	if err := searchAction(cmd, []string{"openssl"}); err != nil {
+		t.Fatalf("search json: %v", err)
+	}
+	var items []searchItem
+	if err := json.Unmarshal(buf.Bytes(), &items); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, buf.String())
+	}
+	if len(items) != 1 || items[0].Channel != "security" || items[0].Score == nil || *items[0].Score != 8 {
+		t.Fatalf("items = %+v", items)
+	}
+}
+
+func TestSearchAction_InvalidTier(t *testing.T) {
+	oldTier := searchTier
+	t.Cleanup(func() { searchTier = oldTier })
+	searchTier = "urgent"
+
+	if err := searchAction(&cobra.Command{}, []string{"x"}); err == nil {
+		t.Fatal("expected error for unknown tier")
+	}
+}
@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/events"
+	"github.com/ppiankov/noisepan/internal/server"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/taste"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveAddr     string
+	serveInterval string
+)
+
+const serveShutdownTimeout = 5 * time.Second
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the web dashboard and HTTP API",
+	Long: `Starts an HTTP server with a local web dashboard at /: the digest, search,
+per-channel charts, and each post's scoring breakdown, with buttons to vote
+and star. The dashboard reads the JSON API under /api (see the README for
+the endpoints); set serve.token in config.yaml to require a bearer token.
+
+GET /api/feed serves the digest as an Atom feed, like "noisepan feed"; feed
+readers that cannot send a header pass the token as ?token=.
+
+GET /api/stream is a server-sent event stream that pushes each newly scored
+read_now post as a "post" event. The server watches the store for new posts,
+so run it next to "noisepan run --every" (or a scheduled pull).`,
+	RunE: serveAction,
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:8080", "listen address")
+	serveCmd.Flags().StringVar(&serveInterval, "interval", "10s", "how often to check for new posts")
+	rootCmd.AddCommand(serveCmd)
+}
+
+func serveAction(cmd *cobra.Command, _ []string) error {
+	interval, err := parseDuration(serveInterval)
+	if err != nil {
+		return fmt.Errorf("parse --interval: %w", err)
+	}
+	if interval <= 0 {
+		return fmt.Errorf("--interval must be greater than zero")
+	}
+
+	cfg, err := config.Load(configDir)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	if cfg.Serve.TokenEnv != "" && cfg.Serve.Token == "" {
+		// Refuse to fall back to an open API when the secret is missing.
+		return fmt.Errorf("serve.token_env: %s is not set", cfg.Serve.TokenEnv)
+	}
+
+	tastePath := profileTastePath(cfg)
+	profile, err := loadTaste(cfg, tastePath)
+	if err != nil {
+		return fmt.Errorf("load taste: %w", err)
+	}
+
+	db, err := openStore(cfg)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	ctx, bus := withEventBus(cmd.Context())
+	pipeline, err := newDigestPipeline(ctx, cfg, profile, db)
+	if err != nil {
+		return err
+	}
+	scorer := pipeline.scorer
+	ctx, stopSignals := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+
+	cursor, err := db.LatestPostID(ctx)
+	if err != nil {
+		return err
+	}
+
+	hub := server.NewHub()
+	backend := &dashboardBackend{db: db, scorer: scorer, digests: pipeline, notes: cfg.Channels}
+	srv := server.New(hub, backend)
+	srv.SetToken(cfg.Serve.Token)
+	srv.SetTiers(taste.TierNames(profile))
+	// Whatever scores a read_now post in this process streams it.
+	defer bus.Subscribe(streamToHub(hub), events.PostReadNow)()
+	httpServer := &http.Server{
+		Addr:              serveAddr,
+		Handler:           srv.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		// Cancel open streams on shutdown; they never go idle on their own.
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+
+	ln, err := net.Listen("tcp", serveAddr)
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- httpServer.Serve(ln) }()
+	slog.Info("serving", "addr", ln.Addr().String())
+
+	go func() {
+		_ = runWatch(ctx, interval, func() error {
+			backend.mu.Lock()
+			next, err := streamNewPosts(ctx, db, scorer, cursor)
+			backend.mu.Unlock()
+			if err != nil {
+				slog.Warn("stream poll failed", "err", err)
+				return nil
+			}
+			cursor = next
+			return nil
+		})
+	}()
+
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("serve: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+	defer cancel()
+	return httpServer.Shutdown(shutdownCtx)
+}
+
+// streamNewPosts scores posts ingested after cursor, which publishes a
+// read_now event for each read_now one, and publishes the events itself for
+// read_now posts another process scored. It returns the new cursor.
+func streamNewPosts(ctx context.Context, db *store.Store, scorer *postScorer, cursor int64) (int64, error) {
+	posts, err := db.GetPosts(ctx, time.Time{}, "", store.PostFilter{AfterID: cursor, Order: store.OrderIngested})
+	if err != nil {
+		return cursor, fmt.Errorf("get posts: %w", err)
+	}
+	if len(posts) == 0 {
+		return cursor, nil
+	}
+	var scored []store.PostWithScore
+	for _, p := range posts {
+		if !scorer.needsScore(p) {
+			scored = append(scored, p)
+		}
+	}
+	if err := scoreUnscored(ctx, db, scorer, posts, time.Now()); err != nil {
+		return cursor, err
+	}
+	for _, p := range scored {
+		if p.Score.Tier == taste.TierReadNow {
+			events.Publish(ctx, postEvent(events.PostReadNow, p))
+		}
+	}
+	return posts[len(posts)-1].Post.ID, nil
+}
+
+// streamToHub forwards read_now events to the live stream.
+func streamToHub(hub *server.Hub) events.Handler {
+	return func(_ context.Context, ev events.Event) {
+		hub.Publish(server.Item{
+			ID:       ev.PostID,
+			Source:   ev.Source,
+			Channel:  ev.Channel,
+			URL:      ev.URL,
+			PostedAt: ev.PostedAt.UTC().Format(time.RFC3339),
+			Score:    ev.Score,
+			Tier:     ev.Tier,
+			Labels:   ev.Labels,
+			Snippet:  ev.Snippet,
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/events"
+	"github.com/ppiankov/noisepan/internal/server"
+	"github.com/ppiankov/noisepan/internal/store"
+)
+
+func TestStreamNewPosts(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "noisepan.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, p := range []struct{ id, text string }{
+		{"hot", "cve OpenSSL exploited"},
+		{"meh", "cve in a changelog"},
+	} {
+		if _, err := st.InsertPost(ctx, store.PostInput{
+			Source: "rss", Channel: "security", ExternalID: p.id,
+			Text: p.text, PostedAt: now, FetchedAt: now,
+		}); err != nil {
+			t.Fatalf("insert %s: %v", p.id, err)
+		}
+	}
+
+	profile := testScorerProfile()
+	profile.Weights.HighSignal["exploited"] = 3
+	hub := server.NewHub()
+	items, unsubscribe := hub.Subscribe()
+	defer unsubscribe()
+	bus := events.New()
+	defer bus.Subscribe(streamToHub(hub), events.PostReadNow)()
+	streamCtx := events.WithBus(ctx, bus)
+	scorer := &postScorer{profile: profile}
+
+	cursor, err := streamNewPosts(streamCtx, st, scorer, 0)
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	if cursor != 2 {
+		t.Errorf("cursor = %d, want 2", cursor)
+	}
+	if len(items) != 1 {
+		t.Fatalf("published %d items, want only the read_now post", len(items))
+	}
+	got := <-items
+	if got.Tier != "read_now" || got.Score != 8 || got.Snippet != "cve OpenSSL exploited" {
+		t.Errorf("item = %+v", got)
+	}
+
+	// A post another process already scored streams once too.
+	post, err := st.InsertPost(ctx, store.PostInput{
+		Source: "rss", Channel: "security", ExternalID: "scored",
+		Text: "cve OpenSSL exploited again", PostedAt: now, FetchedAt: now,
+	})
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if err := scoreUnscored(ctx, st, scorer, []store.PostWithScore{{Post: post}}, now); err != nil {
+		t.Fatalf("score elsewhere: %v", err)
+	}
+	if cursor, err = streamNewPosts(streamCtx, st, scorer, cursor); err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	if cursor != post.ID || len(items) != 1 {
+		t.Fatalf("cursor = %d, %d items published, want %d and 1", cursor, len(items), post.ID)
+	}
+	if got := <-items; got.ID != post.ID {
+		t.Errorf("item = %+v, want post %d", got, post.ID)
+	}
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var (
+	similarSince  string
+	similarFormat string
+	similarLimit  int
+)
+
+var similarCmd = &cobra.Command{
+	Use:   "similar <post-id>",
+	Short: "List stored posts closest in meaning to a post",
+	Long: `Ranks stored posts by how close their embeddings are to the given post's,
+most similar first: other takes on the same story, follow-ups and related
+news. Needs embed.provider in config.yaml; posts are embedded on pull, and the
+given post is embedded now if it is not yet.`,
+	Args: cobra.ExactArgs(1),
+	RunE: similarAction,
+}
+
+func init() {
+	similarCmd.Flags().StringVar(&similarSince, "since", "", "time window (e.g. 7d, 48h); default all stored posts")
+	similarCmd.Flags().StringVar(&similarFormat, "format", "terminal", "output format: terminal, json")
+	similarCmd.Flags().IntVar(&similarLimit, "limit", 10, "maximum number of results (0 for all)")
+	rootCmd.AddCommand(similarCmd)
+}
+
+func similarAction(cmd *cobra.Command, args []string) error {
+	postID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid post ID: %w", err)
+	}
+	if err := validateSearchFormat(similarFormat); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(configDir)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	filter := store.SearchFilter{Limit: similarLimit, ExcludeID: postID}
+	if similarSince != "" {
+		sinceDur, err := parseDuration(similarSince)
+		if err != nil {
+			return fmt.Errorf("parse --since: %w", err)
+		}
+		filter.Since = time.Now().Add(-sinceDur)
+	}
+
+	ctx := cmd.Context()
+	embedder, err := requireEmbedder(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	db, err := openStore(cfg)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	post, err := db.GetPostByID(ctx, postID)
+	if err != nil {
+		return err
+	}
+	vector, err := postEmbedding(ctx, db, embedder, post.Post)
+	if err != nil {
+		return err
+	}
+	results, err := db.SemanticSearch(ctx, embedder.Model(), vector, filter)
+	if err != nil {
+		return err
+	}
+	return writeSearchResults(cmd, db, cfg, results, similarFormat)
+}
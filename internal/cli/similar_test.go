@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// newTestEmbedServer serves embeddings with one dimension per keyword, so
+// texts sharing keywords are similar.
+func newTestEmbedServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input []string `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode embed request: %v", err)
+		}
+		type datum struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		}
+		var resp struct {
+			Data []datum `json:"data"`
+		}
+		for i, text := range req.Input {
+			text = strings.ToLower(text)
+			vec := []float32{0.01, 0, 0, 0}
+			for j, word := range []string{"kubernetes", "cve", "webinar"} {
+				if strings.Contains(text, word) {
+					vec[j+1] = 1
+				}
+			}
+			resp.Data = append(resp.Data, datum{Index: i, Embedding: vec})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// setupEmbedPipeline writes a forge-plan config with local embeddings served
+// by srv plus extra, points configDir at it and returns the database path.
+func setupEmbedPipeline(t *testing.T, srv *httptest.Server, extra string) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	scriptPath := filepath.Join(tmpDir, "forge-plan.sh")
+	writeTestForgePlanScript(t, scriptPath)
+	writeTestConfig(t, tmpDir, dbPath, scriptPath)
+
+	cfgPath := filepath.Join(tmpDir, "config.yaml")
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	data = append(data, []byte("embed:\n  provider: local\n  endpoint: \""+srv.URL+"\"\n  model: test-embed\n"+extra)...)
+	if err := os.WriteFile(cfgPath, data, 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	oldConfigDir := configDir
+	t.Cleanup(func() { configDir = oldConfigDir })
+	configDir = tmpDir
+	return dbPath
+}
+
+func TestSimilarAction(t *testing.T) {
+	srv := newTestEmbedServer(t)
+	dbPath := setupEmbedPipeline(t, srv, "")
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	if _, err := captureStdout(t, func() error { return pullAction(cmd, nil) }); err != nil {
+		t.Fatalf("pull: %v", err)
+	}
+
+	st, err := store.Open(dbPath)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	hits, err := st.Search(context.Background(), "CVE-2026-1111", store.SearchFilter{})
+	if err != nil || len(hits) != 1 {
+		t.Fatalf("find cve post: %v, %v", hits, err)
+	}
+	cveID := hits[0].Post.ID
+	missing, err := st.PostsWithoutEmbedding(context.Background(), "test-embed", hits[0].Post.FetchedAt.AddDate(0, 0, -1), 0)
+	_ = st.Close()
+	if err != nil || len(missing) != 0 {
+		t.Fatalf("pull left %d posts unembedded, err %v", len(missing), err)
+	}
+
+	oldFormat, oldLimit, oldSince := similarFormat, similarLimit, similarSince
+	t.Cleanup(func() { similarFormat, similarLimit, similarSince = oldFormat, oldLimit, oldSince })
+	similarFormat, similarLimit, similarSince = "json", 10, ""
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := similarAction(cmd, []string{strconv.FormatInt(cveID, 10)}); err != nil {
+		t.Fatalf("similar: %v", err)
+	}
+	var items []searchItem
+	if err := json.Unmarshal(buf.Bytes(), &items); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, buf.String())
+	}
+	// The other Kubernetes post comes first; the post itself is left out.
+	if len(items) != 2 || !strings.Contains(items[0].Snippet, "migration checklist") || items[0].Rank >= items[1].Rank {
+		t.Fatalf("similar = %+v", items)
+	}
+	for _, it := range items {
+		if it.ID == cveID {
+			t.Errorf("similar lists the post itself: %+v", it)
+		}
+	}
+
+	oldSemantic, oldSearchFormat, oldSearchLimit := searchSemantic, searchFormat, searchLimit
+	t.Cleanup(func() { searchSemantic, searchFormat, searchLimit = oldSemantic, oldSearchFormat, oldSearchLimit })
+	searchSemantic, searchFormat, searchLimit = true, "terminal", 1
+
+	buf.Reset()
+	if err := searchAction(cmd, []string{"webinar", "invites"}); err != nil {
+		t.Fatalf("semantic search: %v", err)
+	}
+	requireContains(t, buf.String(), "Join our webinar")
+	if strings.Contains(buf.String(), "Kubernetes") {
+		t.Errorf("semantic search output:\n%s", buf.String())
+	}
+}
+
+func TestPullSemanticDedup(t *testing.T) {
+	srv := newTestEmbedServer(t)
+	setupEmbedPipeline(t, srv, "dedup:\n  semantic: 0.7\n")
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	out, err := captureStdout(t, func() error { return pullAction(cmd, nil) })
+	if err != nil {
+		t.Fatalf("pull: %v", err)
+	}
+	// The two Kubernetes posts are about 0.71 similar.
+	requireContains(t, out, "(1 duplicates removed)")
+}
+
+func TestSimilarAction_EmbeddingsOff(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestConfig(t, tmpDir, filepath.Join(tmpDir, "noisepan.db"), filepath.Join(tmpDir, "forge-plan.sh"))
+	oldConfigDir := configDir
+	t.Cleanup(func() { configDir = oldConfigDir })
+	configDir = tmpDir
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	if err := similarAction(cmd, []string{"1"}); err == nil || !strings.Contains(err.Error(), "embed.provider") {
+		t.Errorf("err = %v, want embed.provider hint", err)
+	}
+
+	cfg := &config.Config{Embed: config.EmbedConfig{Provider: "openai"}}
+	if _, err := newEmbedder(context.Background(), cfg); err == nil {
+		t.Error("expected error for openai without a key")
+	}
+}
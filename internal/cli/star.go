@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var starCmd = &cobra.Command{
+	Use:   "star <post-id>...",
+	Short: "Add posts to the reading queue",
+	Long: `Stars posts so they can be listed with "digest --starred", "search --starred",
+and "stats". Starred posts are exempt from retention pruning.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: starAction,
+}
+
+var unstarCmd = &cobra.Command{
+	Use:   "unstar <post-id>...",
+	Short: "Remove posts from the reading queue",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  unstarAction,
+}
+
+func init() {
+	rootCmd.AddCommand(starCmd)
+	rootCmd.AddCommand(unstarCmd)
+}
+
+func starAction(cmd *cobra.Command, args []string) error {
+	ids, err := parsePostIDs(args)
+	if err != nil {
+		return err
+	}
+
+	db, err := openConfiguredStore()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	now := time.Now()
+	for _, id := range ids {
+		if err := db.Star(cmd.Context(), id, now); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "starred #%d\n", id)
+	}
+	return nil
+}
+
+func unstarAction(cmd *cobra.Command, args []string) error {
+	ids, err := parsePostIDs(args)
+	if err != nil {
+		return err
+	}
+
+	db, err := openConfiguredStore()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	for _, id := range ids {
+		removed, err := db.Unstar(cmd.Context(), id)
+		if err != nil {
+			return err
+		}
+		if removed {
+			fmt.Fprintf(cmd.OutOrStdout(), "unstarred #%d\n", id)
+		} else {
+			fmt.Fprintf(cmd.OutOrStdout(), "#%d was not starred\n", id)
+		}
+	}
+	return nil
+}
+
+func parsePostIDs(args []string) ([]int64, error) {
+	ids := make([]int64, 0, len(args))
+	for _, arg := range args {
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid post ID %q: %w", arg, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// openStore opens the store on the configured backend.
+func openStore(cfg *config.Config) (*store.Store, error) {
+	backend, err := store.BackendFor(cfg.Storage.Driver)
+	if err != nil {
+		return nil, err
+	}
+	if !dryRun {
+		db, err := store.OpenBackend(backend, cfg.Storage.Target())
+		if err != nil {
+			return nil, err
+		}
+		db.SetProfile(activeProfile(cfg))
+		return db, nil
+	}
+	// --dry-run: commands, and the migration, write as usual into a
+	// transaction Close discards.
+	db, err := store.OpenDryRun(backend, cfg.Storage.Target())
+	if err != nil {
+		return nil, err
+	}
+	db.SetProfile(activeProfile(cfg))
+	slog.Info("dry run: store changes will be rolled back")
+	return db, nil
+}
+
+func openConfiguredStore() (*store.Store, error) {
+	cfg, err := config.Load(configDir)
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	db, err := openStore(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("open store: %w", err)
+	}
+	return db, nil
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func TestStarAndUnstarActions(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	scriptPath := filepath.Join(tmpDir, "forge-plan.sh")
+
+	writeTestForgePlanScript(t, scriptPath)
+	writeTestConfig(t, tmpDir, dbPath, scriptPath)
+
+	st, err := store.Open(dbPath)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	ctx := context.Background()
+	now := time.Now()
+	p, err := st.InsertPost(ctx, store.PostInput{
+		Source: "rss", Channel: "security", ExternalID: "1",
+		Text: "Critical OpenSSL CVE", PostedAt: now, FetchedAt: now,
+	})
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	_ = st.Close()
+
+	oldConfigDir := configDir
+	t.Cleanup(func() { configDir = oldConfigDir })
+	configDir = tmpDir
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(ctx)
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+
+	id := []string{strconv.FormatInt(p.ID, 10)}
+	if err := starAction(cmd, id); err != nil {
+		t.Fatalf("star: %v", err)
+	}
+	requireContains(t, buf.String(), "starred #1")
+
+	if err := starAction(cmd, []string{"42"}); err == nil {
+		t.Error("expected error starring missing post")
+	}
+	if err := starAction(cmd, []string{"abc"}); err == nil {
+		t.Error("expected error for invalid post ID")
+	}
+
+	buf.Reset()
+	if err := unstarAction(cmd, id); err != nil {
+		t.Fatalf("unstar: %v", err)
+	}
+	if err := unstarAction(cmd, id); err != nil {
+		t.Fatalf("unstar again: %v", err)
+	}
+	requireContains(t, buf.String(), "unstarred #1")
+	requireContains(t, buf.String(), "#1 was not starred")
+}
@@ -5,17 +5,21 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/ppiankov/noisepan/internal/config"
 	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/taste"
 	"github.com/spf13/cobra"
 )
 
 var (
 	statsSince  string
 	statsFormat string
+	statsMe     bool
 )
 
 var statsCmd = &cobra.Command{
@@ -27,6 +31,7 @@ var statsCmd = &cobra.Command{
 func init() {
 	statsCmd.Flags().StringVar(&statsSince, "since", "30d", "time window (e.g. 7d, 48h)")
 	statsCmd.Flags().StringVar(&statsFormat, "format", "terminal", "output format: terminal, json")
+	statsCmd.Flags().BoolVar(&statsMe, "me", false, "show your own usage (digests, posts read and starred, reading time) instead of feed stats")
 	rootCmd.AddCommand(statsCmd)
 }
 
@@ -41,7 +46,7 @@ func statsAction(cmd *cobra.Command, _ []string) error {
 		return fmt.Errorf("load config: %w", err)
 	}
 
-	db, err := store.Open(cfg.Storage.Path)
+	db, err := openStore(cfg)
 	if err != nil {
 		return fmt.Errorf("open store: %w", err)
 	}
@@ -55,34 +60,125 @@ func statsAction(cmd *cobra.Command, _ []string) error {
 
 	ctx := cmd.Context()
 
+	if statsMe {
+		usage, err := db.GetUsage(ctx, sinceTime)
+		if err != nil {
+			return fmt.Errorf("get usage: %w", err)
+		}
+		switch statsFormat {
+		case "json":
+			return printUsageJSON(os.Stdout, usage, sinceDur)
+		case "terminal", "":
+			printUsage(os.Stdout, usage, sinceDur)
+			return nil
+		default:
+			return fmt.Errorf("unknown format %q (want terminal or json)", statsFormat)
+		}
+	}
+
 	stats, err := db.GetChannelStats(ctx, sinceTime)
 	if err != nil {
 		return fmt.Errorf("get stats: %w", err)
 	}
 
-	if len(stats) == 0 {
-		if statsFormat == "json" {
-			fmt.Fprintln(os.Stdout, `{"channels":[],"distribution":{}}`)
-			return nil
-		}
+	starred, err := db.GetStarred(ctx)
+	if err != nil {
+		return fmt.Errorf("get starred: %w", err)
+	}
+
+	feedback, err := db.GetFeedbackByTier(ctx, sinceTime)
+	if err != nil {
+		return fmt.Errorf("get feedback: %w", err)
+	}
+
+	posts, err := db.GetPosts(ctx, sinceTime, "")
+	if err != nil {
+		return fmt.Errorf("get posts: %w", err)
+	}
+	// The taste profile is optional here: without it the script mix is
+	// still reported, just without coverage hints, and the tiers are the
+	// default three.
+	var covered map[string]bool
+	var tiers []string
+	if profile, err := loadTaste(cfg, profileTastePath(cfg)); err == nil {
+		covered = taste.ProfileScripts(profile)
+		tiers = taste.TierNames(profile)
+	}
+	scripts := collectScriptMix(posts, covered)
+
+	if len(stats) == 0 && statsFormat != "json" {
 		fmt.Fprintln(os.Stdout, "No posts found. Run 'noisepan pull' first.")
 		return nil
 	}
 
+	report := statsReport{
+		channels: stats,
+		starred:  starred,
+		feedback: feedback,
+		scripts:  scripts,
+		notes:    cfg.Channels,
+		tiers:    tiers,
+		since:    sinceDur,
+	}
 	switch statsFormat {
 	case "json":
-		return printStatsJSON(os.Stdout, stats, sinceDur)
+		return printStatsJSON(os.Stdout, report)
 	case "terminal", "":
-		printStats(os.Stdout, stats, sinceDur)
+		printStats(os.Stdout, report)
 		return nil
 	default:
 		return fmt.Errorf("unknown format %q (want terminal or json)", statsFormat)
 	}
 }
 
+// statsReport is everything the terminal and JSON stats reports show.
+type statsReport struct {
+	channels []store.ChannelStats
+	starred  []store.PostWithScore
+	feedback []store.TierFeedback
+	scripts  scriptMix
+	notes    map[string]config.ChannelConfig
+	tiers    []string // taste profile tier names, highest first; nil means the default three
+	since    time.Duration
+}
+
+// tierOrder returns every tier the report covers, highest first: read_now,
+// the middle tiers, then ignore.
+func (rep statsReport) tierOrder() []string {
+	order := []string{taste.TierReadNow}
+	order = append(order, middleTiers(rep.tiers, rep.channels)...)
+	return append(order, taste.TierIgnore)
+}
+
 type jsonStatsOutput struct {
+	Since        string             `json:"since"`
+	Tiers        []string           `json:"tiers"`
 	Channels     []jsonChannelStats `json:"channels"`
 	Distribution jsonDistribution   `json:"distribution"`
+	Starred      []jsonStarredPost  `json:"starred,omitempty"`
+	Feedback     *jsonFeedback      `json:"feedback,omitempty"`
+}
+
+type jsonFeedback struct {
+	Tiers     []jsonTierFeedback `json:"tiers"`
+	Agree     int                `json:"agree"`
+	Total     int                `json:"total"`
+	Agreement float64            `json:"agreement_pct"`
+}
+
+type jsonTierFeedback struct {
+	Tier string `json:"tier"`
+	Up   int    `json:"up"`
+	Down int    `json:"down"`
+}
+
+type jsonStarredPost struct {
+	ID      int64  `json:"id"`
+	Source  string `json:"source"`
+	Channel string `json:"channel"`
+	URL     string `json:"url,omitempty"`
+	Tier    string `json:"tier,omitempty"`
+	Snippet string `json:"snippet"`
 }
 
 type jsonChannelStats struct {
@@ -94,21 +190,30 @@ type jsonChannelStats struct {
 	Ignored  int     `json:"ignored"`
 	Signal   float64 `json:"signal_pct"`
 	DataDays int     `json:"data_days"`
+
+	Tiers map[string]int `json:"tiers,omitempty"`
+
+	Scripts          map[string]int `json:"scripts,omitempty"`
+	UncoveredScripts []string       `json:"uncovered_scripts,omitempty"`
+
+	Owner string `json:"owner,omitempty"`
+	Note  string `json:"note,omitempty"`
 }
 
 type jsonDistribution struct {
-	ReadNow int `json:"read_now"`
-	Skim    int `json:"skim"`
-	Ignored int `json:"ignored"`
-	Total   int `json:"total"`
+	ReadNow int            `json:"read_now"`
+	Skim    int            `json:"skim"`
+	Ignored int            `json:"ignored"`
+	Total   int            `json:"total"`
+	Tiers   map[string]int `json:"tiers,omitempty"`
 }
 
-func printStatsJSON(w io.Writer, stats []store.ChannelStats, _ time.Duration) error {
+func printStatsJSON(w io.Writer, rep statsReport) error {
 	now := time.Now()
-	channels := make([]jsonChannelStats, 0, len(stats))
+	channels := make([]jsonChannelStats, 0, len(rep.channels))
 	dist := jsonDistribution{}
 
-	for _, cs := range stats {
+	for _, cs := range rep.channels {
 		dataDays := int(now.Sub(cs.FirstSeen).Hours() / 24)
 		if dataDays < 1 {
 			dataDays = 1
@@ -122,43 +227,84 @@ func printStatsJSON(w io.Writer, stats []store.ChannelStats, _ time.Duration) er
 			Ignored:  cs.Ignored,
 			Signal:   signalPct(cs),
 			DataDays: dataDays,
+
+			Tiers: cs.Tiers,
+
+			Scripts:          rep.scripts.counts[scriptKey(cs.Source, cs.Channel)],
+			UncoveredScripts: rep.scripts.uncovered(cs.Source, cs.Channel),
+
+			Owner: rep.notes[cs.Channel].Owner,
+			Note:  rep.notes[cs.Channel].Note,
 		})
 		dist.ReadNow += cs.ReadNow
 		dist.Skim += cs.Skim
 		dist.Ignored += cs.Ignored
 		dist.Total += cs.Total
+		for tier, n := range cs.Tiers {
+			if dist.Tiers == nil {
+				dist.Tiers = make(map[string]int)
+			}
+			dist.Tiers[tier] += n
+		}
 	}
 
 	out := jsonStatsOutput{
+		Since:        formatStatsDuration(rep.since),
+		Tiers:        rep.tierOrder(),
 		Channels:     channels,
 		Distribution: dist,
 	}
+	for _, p := range rep.starred {
+		sp := jsonStarredPost{
+			ID:      p.Post.ID,
+			Source:  p.Post.Source,
+			Channel: p.Post.Channel,
+			URL:     p.Post.URL,
+			Snippet: searchSnippet(p.Post),
+		}
+		if p.Score != nil {
+			sp.Tier = p.Score.Tier
+		}
+		out.Starred = append(out.Starred, sp)
+	}
+	if len(rep.feedback) > 0 {
+		agree, total := feedbackAgreement(rep.feedback)
+		fb := &jsonFeedback{Agree: agree, Total: total, Agreement: pct(agree, total)}
+		for _, tf := range rep.feedback {
+			fb.Tiers = append(fb.Tiers, jsonTierFeedback{Tier: tf.Tier, Up: tf.Up, Down: tf.Down})
+		}
+		out.Feedback = fb
+	}
 
 	enc := json.NewEncoder(w)
 	enc.SetIndent("", "  ")
 	return enc.Encode(out)
 }
 
-func printStats(w *os.File, stats []store.ChannelStats, since time.Duration) {
+// printStats writes the terminal report.
+func printStats(w *os.File, rep statsReport) {
 	now := time.Now()
+	middle := middleTiers(rep.tiers, rep.channels)
 
 	totalPosts := 0
 	totalReadNow := 0
-	totalSkim := 0
+	totalMiddle := make(map[string]int)
 	totalIgnored := 0
-	for _, cs := range stats {
+	for _, cs := range rep.channels {
 		totalPosts += cs.Total
 		totalReadNow += cs.ReadNow
-		totalSkim += cs.Skim
+		for _, tier := range middle {
+			totalMiddle[tier] += middleCount(cs, middle, tier)
+		}
 		totalIgnored += cs.Ignored
 	}
 
-	sinceStr := formatStatsDuration(since)
-	fmt.Fprintf(w, "noisepan stats — %s, %d posts from %d channels\n\n", sinceStr, totalPosts, len(stats))
+	sinceStr := formatStatsDuration(rep.since)
+	fmt.Fprintf(w, "noisepan stats — %s, %d posts from %d channels\n\n", sinceStr, totalPosts, len(rep.channels))
 
 	// Signal-to-noise by channel, sorted by signal % descending
-	sorted := make([]store.ChannelStats, len(stats))
-	copy(sorted, stats)
+	sorted := make([]store.ChannelStats, len(rep.channels))
+	copy(sorted, rep.channels)
 	sort.Slice(sorted, func(i, j int) bool {
 		return signalPct(sorted[i]) > signalPct(sorted[j])
 	})
@@ -178,7 +324,11 @@ func printStats(w *os.File, stats []store.ChannelStats, since time.Duration) {
 		maxChan = 40
 	}
 
-	fmt.Fprintf(w, "  %-*s  %5s  %8s  %4s  %7s  %6s\n", maxChan, "Channel", "Posts", "Read Now", "Skim", "Ignored", "Signal")
+	fmt.Fprintf(w, "  %-*s  %5s  %8s", maxChan, "Channel", "Posts", "Read Now")
+	for _, tier := range middle {
+		fmt.Fprintf(w, "  %*s", tierColumnWidth(tier), taste.TierTitle(tier))
+	}
+	fmt.Fprintf(w, "  %7s  %6s\n", "Ignored", "Signal")
 	for _, cs := range sorted {
 		name := cs.Channel
 		if len(name) > maxChan {
@@ -189,23 +339,28 @@ func printStats(w *os.File, stats []store.ChannelStats, since time.Duration) {
 		if dataDays < maturityThreshold {
 			signal = fmt.Sprintf("%5.0f%% (%dd data)", signalPct(cs), dataDays)
 		}
-		fmt.Fprintf(w, "  %-*s  %5d  %8d  %4d  %7d  %s\n",
-			maxChan, name, cs.Total, cs.ReadNow, cs.Skim, cs.Ignored, signal)
+		fmt.Fprintf(w, "  %-*s  %5d  %8d", maxChan, name, cs.Total, cs.ReadNow)
+		for _, tier := range middle {
+			fmt.Fprintf(w, "  %*d", tierColumnWidth(tier), middleCount(cs, middle, tier))
+		}
+		fmt.Fprintf(w, "  %7d  %s\n", cs.Ignored, signal)
 	}
 	fmt.Fprintln(w)
 
 	// Scoring distribution
 	fmt.Fprintln(w, "--- Scoring Distribution ---")
 	fmt.Fprintln(w)
-	fmt.Fprintf(w, "  Read Now:  %5d  (%.1f%%)\n", totalReadNow, pct(totalReadNow, totalPosts))
-	fmt.Fprintf(w, "  Skim:      %5d  (%.1f%%)\n", totalSkim, pct(totalSkim, totalPosts))
-	fmt.Fprintf(w, "  Ignored:   %5d  (%.1f%%)\n", totalIgnored, pct(totalIgnored, totalPosts))
+	fmt.Fprintf(w, "  %-11s%5d  (%.1f%%)\n", "Read Now:", totalReadNow, pct(totalReadNow, totalPosts))
+	for _, tier := range middle {
+		fmt.Fprintf(w, "  %-11s%5d  (%.1f%%)\n", taste.TierTitle(tier)+":", totalMiddle[tier], pct(totalMiddle[tier], totalPosts))
+	}
+	fmt.Fprintf(w, "  %-11s%5d  (%.1f%%)\n", "Ignored:", totalIgnored, pct(totalIgnored, totalPosts))
 	fmt.Fprintln(w)
 
 	// Stale channels
 	staleThreshold := now.AddDate(0, 0, -staleDays)
 	var stale []store.ChannelStats
-	for _, cs := range stats {
+	for _, cs := range rep.channels {
 		if cs.LastSeen.Before(staleThreshold) {
 			stale = append(stale, cs)
 		}
@@ -214,10 +369,327 @@ func printStats(w *os.File, stats []store.ChannelStats, since time.Duration) {
 		fmt.Fprintf(w, "--- Stale Channels (no posts in %d+ days) ---\n\n", staleDays)
 		for _, cs := range stale {
 			daysAgo := int(now.Sub(cs.LastSeen).Hours() / 24)
-			fmt.Fprintf(w, "  %s — last post %d days ago\n", cs.Channel, daysAgo)
+			line := fmt.Sprintf("  %s — last post %d days ago", cs.Channel, daysAgo)
+			if owner := rep.notes[cs.Channel].Owner; owner != "" {
+				line += " (owner: " + owner + ")"
+			}
+			fmt.Fprintln(w, line)
 		}
 		fmt.Fprintln(w)
 	}
+
+	// Why channels were added and who looks after them
+	var annotated []store.ChannelStats
+	for _, cs := range rep.channels {
+		if n := rep.notes[cs.Channel]; n.Note != "" || n.Owner != "" {
+			annotated = append(annotated, cs)
+		}
+	}
+	if len(annotated) > 0 {
+		fmt.Fprintln(w, "--- Channel Notes ---")
+		fmt.Fprintln(w)
+		for _, cs := range annotated {
+			n := rep.notes[cs.Channel]
+			var parts []string
+			if n.Owner != "" {
+				parts = append(parts, "owner: "+n.Owner)
+			}
+			if n.Note != "" {
+				parts = append(parts, n.Note)
+			}
+			fmt.Fprintf(w, "  %s — %s\n", cs.Channel, strings.Join(parts, " — "))
+		}
+		fmt.Fprintln(w)
+	}
+
+	// Channels posting in several scripts or in one the profile can't match
+	var mixed []store.ChannelStats
+	for _, cs := range sorted {
+		if rep.scripts.notable(cs.Source, cs.Channel) {
+			mixed = append(mixed, cs)
+		}
+	}
+	if len(mixed) > 0 {
+		fmt.Fprintln(w, "--- Scripts by Channel ---")
+		fmt.Fprintln(w)
+		for _, cs := range mixed {
+			line := rep.scripts.describe(cs.Source, cs.Channel)
+			if unc := rep.scripts.uncovered(cs.Source, cs.Channel); len(unc) > 0 {
+				line += " (not in taste profile: " + strings.Join(unc, ", ") + ")"
+			}
+			fmt.Fprintf(w, "  %s — %s\n", cs.Channel, line)
+		}
+		fmt.Fprintln(w)
+	}
+
+	// Feedback vs. assigned tiers
+	if len(rep.feedback) > 0 {
+		fmt.Fprintln(w, "--- Feedback Agreement ---")
+		fmt.Fprintln(w)
+		for _, tf := range rep.feedback {
+			fmt.Fprintf(w, "  %-10s %3d up, %3d down\n", tierLabel(tf.Tier)+":", tf.Up, tf.Down)
+		}
+		agree, total := feedbackAgreement(rep.feedback)
+		fmt.Fprintf(w, "  Agreement: %d/%d (%.0f%%)\n", agree, total, pct(agree, total))
+		fmt.Fprintln(w)
+	}
+
+	// Reading queue
+	if len(rep.starred) > 0 {
+		fmt.Fprintf(w, "--- Starred (%d) ---\n\n", len(rep.starred))
+		for _, p := range rep.starred {
+			fmt.Fprintf(w, "  #%d %s/%s — %s\n", p.Post.ID, p.Post.Source, p.Post.Channel, searchSnippet(p.Post))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// readingWordsPerMinute turns word counts into the reading-time estimate of
+// stats --me.
+const readingWordsPerMinute = 230
+
+type jsonUsage struct {
+	Since          string  `json:"since"`
+	Days           int     `json:"days_active"`
+	First          string  `json:"first_day,omitempty"`
+	Digests        int64   `json:"digests"`
+	PostsCovered   int64   `json:"posts_covered"`
+	PostsListed    int64   `json:"posts_listed"`
+	PostsRead      int64   `json:"posts_read"`
+	PostsStarred   int64   `json:"posts_starred"`
+	MinutesCovered float64 `json:"reading_minutes_covered"`
+	MinutesListed  float64 `json:"reading_minutes_listed"`
+	SavedPct       float64 `json:"reading_time_saved_pct"`
+}
+
+func printUsageJSON(w io.Writer, u store.Usage, since time.Duration) error {
+	out := jsonUsage{
+		Since:          formatStatsDuration(since),
+		Days:           u.Days,
+		Digests:        u.Digests,
+		PostsCovered:   u.DigestPosts,
+		PostsListed:    u.DigestShown,
+		PostsRead:      u.Read,
+		PostsStarred:   u.Starred,
+		MinutesCovered: readingMinutes(u.DigestWords),
+		MinutesListed:  readingMinutes(u.DigestShownWords),
+		SavedPct:       readingTimeSaved(u),
+	}
+	if !u.First.IsZero() {
+		out.First = u.First.Format(time.DateOnly)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func printUsage(w io.Writer, u store.Usage, since time.Duration) {
+	fmt.Fprintf(w, "noisepan usage — %s (recorded locally, never sent anywhere)\n\n", formatStatsDuration(since))
+	if u.Days == 0 {
+		fmt.Fprintln(w, "No usage recorded yet. Run 'noisepan digest' first.")
+		return
+	}
+
+	fmt.Fprintf(w, "  Active days:    %d (since %s)\n", u.Days, u.First.Format(time.DateOnly))
+	fmt.Fprintf(w, "  Digests:        %d\n", u.Digests)
+	fmt.Fprintf(w, "  Posts covered:  %d, %d listed (%.1f%%)\n", u.DigestPosts, u.DigestShown, pct(int(u.DigestShown), int(u.DigestPosts)))
+	fmt.Fprintf(w, "  Posts read:     %d\n", u.Read)
+	fmt.Fprintf(w, "  Posts starred:  %d\n", u.Starred)
+	fmt.Fprintln(w)
+
+	// Time to read everything the digests covered vs. what they listed.
+	fmt.Fprintln(w, "--- Reading Time (estimated) ---")
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  All posts:      %s\n", formatReadingTime(readingMinutes(u.DigestWords)))
+	fmt.Fprintf(w, "  Digest listing: %s\n", formatReadingTime(readingMinutes(u.DigestShownWords)))
+	if u.DigestWords > 0 {
+		fmt.Fprintf(w, "  Saved:          %.0f%%\n", readingTimeSaved(u))
+	}
+	fmt.Fprintln(w)
+}
+
+func readingMinutes(words int64) float64 {
+	return float64(words) / readingWordsPerMinute
+}
+
+// readingTimeSaved is the share of the covered posts' reading time the
+// digests left out.
+func readingTimeSaved(u store.Usage) float64 {
+	if u.DigestWords == 0 {
+		return 0
+	}
+	return float64(u.DigestWords-u.DigestShownWords) / float64(u.DigestWords) * 100
+}
+
+func formatReadingTime(minutes float64) string {
+	m := int(minutes + 0.5)
+	switch {
+	case minutes == 0:
+		return "0m"
+	case m < 1:
+		return "<1m"
+	case m < 60:
+		return fmt.Sprintf("~%dm", m)
+	}
+	return fmt.Sprintf("~%dh %02dm", m/60, m%60)
+}
+
+// minScriptShare is the share of a channel's posts a script needs before
+// stats calls it out.
+const minScriptShare = 10.0
+
+// scriptMix counts posts per dominant script for each "source/channel".
+// covered holds the scripts the taste profile has terms in; nil when no
+// profile was loaded.
+type scriptMix struct {
+	counts  map[string]map[string]int
+	covered map[string]bool
+}
+
+func scriptKey(source, channel string) string {
+	return source + "/" + channel
+}
+
+func collectScriptMix(posts []store.PostWithScore, covered map[string]bool) scriptMix {
+	mix := scriptMix{counts: make(map[string]map[string]int), covered: covered}
+	for _, p := range posts {
+		text := p.Post.Text
+		if text == "" {
+			text = p.Post.Snippet
+		}
+		script := taste.DominantScript(text)
+		if script == "" {
+			continue
+		}
+		key := scriptKey(p.Post.Source, p.Post.Channel)
+		if mix.counts[key] == nil {
+			mix.counts[key] = make(map[string]int)
+		}
+		mix.counts[key][script]++
+	}
+	return mix
+}
+
+// shares returns the channel's scripts with their share of its posts, the
+// most common first.
+func (m scriptMix) shares(source, channel string) ([]string, map[string]float64) {
+	counts := m.counts[scriptKey(source, channel)]
+	total := 0
+	names := make([]string, 0, len(counts))
+	for name, n := range counts {
+		total += n
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	share := make(map[string]float64, len(names))
+	for _, name := range names {
+		share[name] = pct(counts[name], total)
+	}
+	return names, share
+}
+
+// uncovered lists scripts making up at least minScriptShare of the channel
+// that the taste profile has no terms in.
+func (m scriptMix) uncovered(source, channel string) []string {
+	if m.covered == nil {
+		return nil
+	}
+	names, share := m.shares(source, channel)
+	var out []string
+	for _, name := range names {
+		if share[name] >= minScriptShare && !m.covered[name] {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// notable reports whether the channel posts in more than one script at
+// minScriptShare or more, or in a script the profile does not cover.
+func (m scriptMix) notable(source, channel string) bool {
+	names, share := m.shares(source, channel)
+	n := 0
+	for _, name := range names {
+		if share[name] >= minScriptShare {
+			n++
+		}
+	}
+	return n > 1 || len(m.uncovered(source, channel)) > 0
+}
+
+func (m scriptMix) describe(source, channel string) string {
+	names, share := m.shares(source, channel)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s %.0f%%", name, share[name]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// feedbackAgreement counts votes that agree with the assigned tier: up on
+// any tier above ignore, down on ignore.
+func feedbackAgreement(tiers []store.TierFeedback) (agree, total int) {
+	for _, tf := range tiers {
+		total += tf.Up + tf.Down
+		if tf.Tier == taste.TierIgnore {
+			agree += tf.Down
+		} else {
+			agree += tf.Up
+		}
+	}
+	return agree, total
+}
+
+func tierLabel(tier string) string {
+	if tier == taste.TierIgnore {
+		return "Ignored"
+	}
+	return taste.TierTitle(tier)
+}
+
+// middleTiers returns the tiers between read_now and ignore that stats has
+// columns for: those of tiers, highest first, then any other tier posts were
+// scored under, by name.
+func middleTiers(tiers []string, stats []store.ChannelStats) []string {
+	if tiers == nil {
+		tiers = []string{taste.TierReadNow, taste.TierSkim, taste.TierIgnore}
+	}
+	var middle []string
+	for _, tier := range tiers {
+		if tier != taste.TierReadNow && tier != taste.TierIgnore {
+			middle = append(middle, tier)
+		}
+	}
+	var extra []string
+	for _, cs := range stats {
+		for tier := range cs.Tiers {
+			if tier != taste.TierReadNow && tier != taste.TierIgnore &&
+				!slices.Contains(middle, tier) && !slices.Contains(extra, tier) {
+				extra = append(extra, tier)
+			}
+		}
+	}
+	sort.Strings(extra)
+	return append(middle, extra...)
+}
+
+// middleCount is cs's post count in tier, one of middle. A single middle
+// tier holds every post ChannelStats.Skim counts.
+func middleCount(cs store.ChannelStats, middle []string, tier string) int {
+	if len(middle) == 1 {
+		return cs.Skim
+	}
+	return cs.Tiers[tier]
+}
+
+// tierColumnWidth fits a tier's column to its title, and to four digits.
+func tierColumnWidth(tier string) int {
+	return max(len(taste.TierTitle(tier)), 4)
 }
 
 func signalPct(cs store.ChannelStats) float64 {
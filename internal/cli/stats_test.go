@@ -2,13 +2,19 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/digest"
 	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/taste"
 )
 
 func TestPrintStats(t *testing.T) {
@@ -23,7 +29,7 @@ func TestPrintStats(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	printStats(w, stats, 30*24*time.Hour)
+	printStats(w, statsReport{channels: stats, since: 30 * 24 * time.Hour})
 	_ = w.Close()
 
 	buf := make([]byte, 8192)
@@ -48,6 +54,75 @@ func TestPrintStats(t *testing.T) {
 	}
 }
 
+func TestPrintStats_CustomTiers(t *testing.T) {
+	stats := []store.ChannelStats{
+		{Source: "rss", Channel: "CISA", Total: 10, ReadNow: 2, Skim: 5, Ignored: 3,
+			Tiers:     map[string]int{"read_now": 2, "today": 1, "weekend_reads": 4, "ignore": 3},
+			FirstSeen: time.Now().AddDate(0, 0, -60), LastSeen: time.Now()},
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	printStats(w, statsReport{channels: stats, tiers: []string{"read_now", "today", "weekend_reads", "ignore"}, since: 30 * 24 * time.Hour})
+	_ = w.Close()
+
+	buf := make([]byte, 8192)
+	n, _ := r.Read(buf)
+	output := string(buf[:n])
+	_ = r.Close()
+
+	for _, want := range []string{
+		"Read Now  Today  Weekend reads  Ignored",
+		"Today:         1  (10.0%)",
+		"Weekend reads:    4  (40.0%)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("missing %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "Skim") {
+		t.Errorf("skim shown for a profile without it:\n%s", output)
+	}
+}
+
+func TestPrintStatsJSON_CustomTiers(t *testing.T) {
+	stats := []store.ChannelStats{
+		{Source: "rss", Channel: "CISA", Total: 10, ReadNow: 2, Skim: 5, Ignored: 3,
+			Tiers:     map[string]int{"read_now": 2, "today": 1, "weekend_reads": 4, "ignore": 3},
+			FirstSeen: time.Now().AddDate(0, 0, -60), LastSeen: time.Now()},
+	}
+
+	var buf bytes.Buffer
+	rep := statsReport{channels: stats, tiers: []string{"read_now", "today", "weekend_reads", "ignore"}, since: 7 * 24 * time.Hour}
+	if err := printStatsJSON(&buf, rep); err != nil {
+		t.Fatalf("print stats json: %v", err)
+	}
+
+	var got jsonStatsOutput
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("parse json: %v\noutput:\n%s", err, buf.String())
+	}
+	if want := []string{"read_now", "today", "weekend_reads", "ignore"}; !slices.Equal(got.Tiers, want) {
+		t.Errorf("tiers = %v, want %v", got.Tiers, want)
+	}
+	if got.Since != "7 days" {
+		t.Errorf("since = %q, want 7 days", got.Since)
+	}
+}
+
+func TestMiddleTiers(t *testing.T) {
+	stats := []store.ChannelStats{{Tiers: map[string]int{"read_now": 1, "skim": 2, "later": 1, "ignore": 1}}}
+	got := middleTiers([]string{"read_now", "today", "ignore"}, stats)
+	if want := []string{"today", "later", "skim"}; !slices.Equal(got, want) {
+		t.Errorf("middleTiers = %v, want %v", got, want)
+	}
+	if got := middleTiers(nil, nil); !slices.Equal(got, []string{"skim"}) {
+		t.Errorf("middleTiers(nil) = %v, want [skim]", got)
+	}
+}
+
 func TestPrintStats_StaleChannels(t *testing.T) {
 	staleTime := time.Now().AddDate(0, 0, -14)
 	stats := []store.ChannelStats{
@@ -61,7 +136,7 @@ func TestPrintStats_StaleChannels(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	printStats(w, stats, 30*24*time.Hour)
+	printStats(w, statsReport{channels: stats, since: 30 * 24 * time.Hour})
 	_ = w.Close()
 
 	buf := make([]byte, 8192)
@@ -93,7 +168,7 @@ func TestPrintStats_MaturityAnnotation(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	printStats(w, stats, 30*24*time.Hour)
+	printStats(w, statsReport{channels: stats, since: 30 * 24 * time.Hour})
 	_ = w.Close()
 
 	buf := make([]byte, 8192)
@@ -120,7 +195,7 @@ func TestPrintStatsJSON(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	if err := printStatsJSON(&buf, stats, 30*24*time.Hour); err != nil {
+	if err := printStatsJSON(&buf, statsReport{channels: stats, since: 30 * 24 * time.Hour}); err != nil {
 		t.Fatalf("print stats json: %v", err)
 	}
 
@@ -201,3 +276,282 @@ func TestSignalPct(t *testing.T) {
 		}
 	}
 }
+
+func TestPrintStats_Starred(t *testing.T) {
+	stats := []store.ChannelStats{
+		{Source: "rss", Channel: "CISA", Total: 1, ReadNow: 1,
+			FirstSeen: time.Now().AddDate(0, 0, -60), LastSeen: time.Now()},
+	}
+	starred := []store.PostWithScore{
+		{Post: store.Post{ID: 7, Source: "rss", Channel: "CISA", Text: "KEV update\nbody"},
+			Score: &store.Score{Tier: "read_now"}},
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	printStats(w, statsReport{channels: stats, starred: starred, since: 30 * 24 * time.Hour})
+	_ = w.Close()
+
+	buf := make([]byte, 8192)
+	n, _ := r.Read(buf)
+	output := string(buf[:n])
+	_ = r.Close()
+
+	if !strings.Contains(output, "--- Starred (1) ---") || !strings.Contains(output, "#7 rss/CISA — KEV update") {
+		t.Errorf("missing starred section, got:\n%s", output)
+	}
+
+	var jbuf bytes.Buffer
+	if err := printStatsJSON(&jbuf, statsReport{channels: stats, starred: starred, since: 30 * 24 * time.Hour}); err != nil {
+		t.Fatalf("print stats json: %v", err)
+	}
+	var got jsonStatsOutput
+	if err := json.Unmarshal(jbuf.Bytes(), &got); err != nil {
+		t.Fatalf("parse json: %v", err)
+	}
+	if len(got.Starred) != 1 || got.Starred[0].ID != 7 || got.Starred[0].Tier != "read_now" {
+		t.Errorf("starred = %+v", got.Starred)
+	}
+}
+
+func TestPrintStats_Feedback(t *testing.T) {
+	stats := []store.ChannelStats{
+		{Source: "rss", Channel: "CISA", Total: 10, ReadNow: 4, Skim: 2, Ignored: 4,
+			FirstSeen: time.Now().AddDate(0, 0, -60), LastSeen: time.Now()},
+	}
+	feedback := []store.TierFeedback{
+		{Tier: "ignore", Up: 1, Down: 3},
+		{Tier: "read_now", Up: 3, Down: 1},
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	printStats(w, statsReport{channels: stats, feedback: feedback, since: 30 * 24 * time.Hour})
+	_ = w.Close()
+
+	buf := make([]byte, 8192)
+	n, _ := r.Read(buf)
+	output := string(buf[:n])
+	_ = r.Close()
+
+	if !strings.Contains(output, "--- Feedback Agreement ---") || !strings.Contains(output, "Agreement: 6/8 (75%)") {
+		t.Errorf("missing feedback section, got:\n%s", output)
+	}
+
+	var jbuf bytes.Buffer
+	if err := printStatsJSON(&jbuf, statsReport{channels: stats, feedback: feedback, since: 30 * 24 * time.Hour}); err != nil {
+		t.Fatalf("print stats json: %v", err)
+	}
+	var got jsonStatsOutput
+	if err := json.Unmarshal(jbuf.Bytes(), &got); err != nil {
+		t.Fatalf("parse json: %v", err)
+	}
+	if got.Feedback == nil || got.Feedback.Agree != 6 || got.Feedback.Total != 8 || len(got.Feedback.Tiers) != 2 {
+		t.Errorf("feedback = %+v", got.Feedback)
+	}
+}
+
+func TestPrintStats_Scripts(t *testing.T) {
+	stats := []store.ChannelStats{
+		{Source: "telegram", Channel: "devops_ru", Total: 5, ReadNow: 1, Skim: 1, Ignored: 3,
+			FirstSeen: time.Now().AddDate(0, 0, -60), LastSeen: time.Now()},
+		{Source: "rss", Channel: "CISA", Total: 2, ReadNow: 1, Skim: 1,
+			FirstSeen: time.Now().AddDate(0, 0, -60), LastSeen: time.Now()},
+	}
+	post := func(src, ch, text string) store.PostWithScore {
+		return store.PostWithScore{Post: store.Post{Source: src, Channel: ch, Text: text}}
+	}
+	posts := []store.PostWithScore{
+		post("telegram", "devops_ru", "Kubernetes 1.31 released"),
+		post("telegram", "devops_ru", "Вышел новый релиз Kubernetes"),
+		post("telegram", "devops_ru", "Обновление ядра Linux"),
+		post("telegram", "devops_ru", "Вебинар по облакам"),
+		post("telegram", "devops_ru", "12345"),
+		post("rss", "CISA", "Critical CVE in OpenSSH"),
+		post("rss", "CISA", "Patch Tuesday roundup"),
+	}
+	mix := collectScriptMix(posts, map[string]bool{"Latin": true})
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	printStats(w, statsReport{channels: stats, scripts: mix, since: 30 * 24 * time.Hour})
+	_ = w.Close()
+
+	buf := make([]byte, 8192)
+	n, _ := r.Read(buf)
+	output := string(buf[:n])
+	_ = r.Close()
+
+	if !strings.Contains(output, "--- Scripts by Channel ---") {
+		t.Fatalf("missing scripts section, got:\n%s", output)
+	}
+	if !strings.Contains(output, "devops_ru — Cyrillic 75%, Latin 25% (not in taste profile: Cyrillic)") {
+		t.Errorf("missing devops_ru script mix, got:\n%s", output)
+	}
+	if strings.Contains(output, "CISA — Latin") {
+		t.Errorf("single covered script should not be listed, got:\n%s", output)
+	}
+
+	var jbuf bytes.Buffer
+	if err := printStatsJSON(&jbuf, statsReport{channels: stats, scripts: mix, since: 30 * 24 * time.Hour}); err != nil {
+		t.Fatalf("print stats json: %v", err)
+	}
+	var got jsonStatsOutput
+	if err := json.Unmarshal(jbuf.Bytes(), &got); err != nil {
+		t.Fatalf("parse json: %v", err)
+	}
+	ru := got.Channels[0]
+	if ru.Scripts["Cyrillic"] != 3 || ru.Scripts["Latin"] != 1 || len(ru.UncoveredScripts) != 1 || ru.UncoveredScripts[0] != "Cyrillic" {
+		t.Errorf("devops_ru = %+v", ru)
+	}
+	if cisa := got.Channels[1]; cisa.Scripts["Latin"] != 2 || len(cisa.UncoveredScripts) != 0 {
+		t.Errorf("CISA = %+v", cisa)
+	}
+}
+
+func TestPrintStats_ChannelNotes(t *testing.T) {
+	stats := []store.ChannelStats{
+		{Source: "rss", Channel: "CISA", Total: 2, ReadNow: 1, Skim: 1,
+			FirstSeen: time.Now().AddDate(0, 0, -60), LastSeen: time.Now()},
+		{Source: "rss", Channel: "Old Blog", Total: 1, Ignored: 1,
+			FirstSeen: time.Now().AddDate(0, 0, -60), LastSeen: time.Now().AddDate(0, 0, -20)},
+		{Source: "rss", Channel: "Plain", Total: 1, Ignored: 1,
+			FirstSeen: time.Now().AddDate(0, 0, -60), LastSeen: time.Now()},
+	}
+	notes := map[string]config.ChannelConfig{
+		"CISA":     {Note: "Required by the vuln management runbook", Owner: "secops"},
+		"Old Blog": {Owner: "alice"},
+		"Plain":    {LLMTriage: true},
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	printStats(w, statsReport{channels: stats, notes: notes, since: 30 * 24 * time.Hour})
+	_ = w.Close()
+
+	buf := make([]byte, 8192)
+	n, _ := r.Read(buf)
+	output := string(buf[:n])
+	_ = r.Close()
+
+	requireContains(t, output, "--- Channel Notes ---")
+	requireContains(t, output, "CISA — owner: secops — Required by the vuln management runbook")
+	requireContains(t, output, "Old Blog — last post 20 days ago (owner: alice)")
+	if strings.Contains(output, "Plain — ") {
+		t.Errorf("channel without notes listed, got:\n%s", output)
+	}
+
+	var jbuf bytes.Buffer
+	if err := printStatsJSON(&jbuf, statsReport{channels: stats, notes: notes, since: 30 * 24 * time.Hour}); err != nil {
+		t.Fatalf("print stats json: %v", err)
+	}
+	var got jsonStatsOutput
+	if err := json.Unmarshal(jbuf.Bytes(), &got); err != nil {
+		t.Fatalf("parse json: %v", err)
+	}
+	if c := got.Channels[0]; c.Owner != "secops" || c.Note != "Required by the vuln management runbook" {
+		t.Errorf("CISA = %+v", c)
+	}
+	if c := got.Channels[2]; c.Owner != "" || c.Note != "" {
+		t.Errorf("Plain = %+v", c)
+	}
+}
+
+func TestPrintUsage(t *testing.T) {
+	u := store.Usage{
+		Digests: 14, DigestPosts: 1200, DigestShown: 90, DigestWords: 138000, DigestShownWords: 13800,
+		Read: 40, Starred: 6, Days: 14, First: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+	var buf bytes.Buffer
+	printUsage(&buf, u, 30*24*time.Hour)
+	output := buf.String()
+
+	for _, want := range []string{
+		"30 days",
+		"Active days:    14 (since 2026-03-01)",
+		"Digests:        14",
+		"1200, 90 listed (7.5%)",
+		"Posts read:     40",
+		"Posts starred:  6",
+		"All posts:      ~10h 00m",
+		"Digest listing: ~1h 00m",
+		"Saved:          90%",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("missing %q in:\n%s", want, output)
+		}
+	}
+
+	buf.Reset()
+	printUsage(&buf, store.Usage{}, 7*24*time.Hour)
+	if !strings.Contains(buf.String(), "No usage recorded yet") {
+		t.Errorf("empty usage output:\n%s", buf.String())
+	}
+}
+
+func TestPrintUsageJSON(t *testing.T) {
+	u := store.Usage{Digests: 2, DigestPosts: 10, DigestShown: 3, DigestWords: 2300, DigestShownWords: 460, Days: 2,
+		First: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+	var buf bytes.Buffer
+	if err := printUsageJSON(&buf, u, 7*24*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	var out jsonUsage
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if out.Digests != 2 || out.PostsListed != 3 || out.MinutesCovered != 10 || out.MinutesListed != 2 || out.SavedPct != 80 || out.First != "2026-03-01" {
+		t.Errorf("usage JSON = %+v", out)
+	}
+}
+
+func TestFormatReadingTime(t *testing.T) {
+	tests := []struct {
+		minutes float64
+		want    string
+	}{
+		{0, "0m"},
+		{0.2, "<1m"},
+		{41.4, "~41m"},
+		{135, "~2h 15m"},
+	}
+	for _, tt := range tests {
+		if got := formatReadingTime(tt.minutes); got != tt.want {
+			t.Errorf("formatReadingTime(%v) = %q, want %q", tt.minutes, got, tt.want)
+		}
+	}
+}
+
+func TestRecordDigestUsage(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "noisepan.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = st.Close() }()
+
+	items := []digest.DigestItem{
+		{PostID: 1, ScoredPost: taste.ScoredPost{Tier: taste.TierReadNow}},
+		{PostID: 2, ScoredPost: taste.ScoredPost{Tier: taste.TierSkim}},
+		{PostID: 3, ScoredPost: taste.ScoredPost{Tier: taste.TierIgnore}},
+	}
+	words := map[int64]int{1: 100, 2: 50, 3: 400, 4: 450} // post 4 cut by top_n
+	if err := recordDigestUsage(context.Background(), st, time.Now(), items, words); err != nil {
+		t.Fatalf("record usage: %v", err)
+	}
+
+	u, err := st.GetUsage(context.Background(), time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Digests != 1 || u.DigestPosts != 4 || u.DigestShown != 2 || u.DigestWords != 1000 || u.DigestShownWords != 150 {
+		t.Errorf("usage = %+v", u)
+	}
+}
@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/taste"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tailInterval string
+	tailMinTier  string
+)
+
+var tailCmd = &cobra.Command{
+	Use:   "tail",
+	Short: "Stream newly ingested posts as one-liners",
+	Long: `Watches the store for posts ingested after tail starts, scores them, and
+prints one tier-colored line per post. Run it next to "noisepan run --every"
+(or a scheduled pull) to follow feeds live when a digest cycle is too slow.`,
+	RunE: tailAction,
+}
+
+func init() {
+	tailCmd.Flags().StringVar(&tailInterval, "interval", "10s", "how often to check for new posts")
+	tailCmd.Flags().StringVar(&tailMinTier, "min-tier", "", "lowest tier to show, e.g. read_now, skim, ignore (default: every tier above ignore)")
+	tailCmd.Flags().BoolVar(&noColor, "no-color", false, "disable ANSI colors")
+	rootCmd.AddCommand(tailCmd)
+}
+
+func tailAction(cmd *cobra.Command, _ []string) error {
+	interval, err := parseDuration(tailInterval)
+	if err != nil {
+		return fmt.Errorf("parse --interval: %w", err)
+	}
+	if interval <= 0 {
+		return fmt.Errorf("--interval must be greater than zero")
+	}
+	cfg, err := config.Load(configDir)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	tastePath := profileTastePath(cfg)
+	profile, err := loadTaste(cfg, tastePath)
+	if err != nil {
+		return fmt.Errorf("load taste: %w", err)
+	}
+	if _, err := minTierRank(tailMinTier, taste.TierNames(profile)); err != nil {
+		return err
+	}
+
+	db, err := openStore(cfg)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	scorer, err := newPostScorer(cfg, profile)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := scorer.loadBoilerplate(ctx, db); err != nil {
+		return fmt.Errorf("load boilerplate: %w", err)
+	}
+	ctx, stopSignals := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+
+	cursor, err := db.LatestPostID(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+	fmt.Fprintf(w, "Tailing new posts every %s (Ctrl-C to stop)\n", interval)
+
+	// A failed poll (e.g. the database is busy with a pull) is retried on the
+	// next tick rather than ending the tail.
+	return runWatch(ctx, interval, func() error {
+		next, err := tailOnce(ctx, db, scorer, cursor, w, !noColor)
+		if err != nil {
+			slog.Warn("tail poll failed", "err", err)
+			return nil
+		}
+		cursor = next
+		return nil
+	})
+}
+
+// tailOnce prints posts ingested after cursor, oldest first, scoring any that
+// are unscored. It returns the new cursor.
+func tailOnce(ctx context.Context, db *store.Store, scorer *postScorer, cursor int64, w io.Writer, color bool) (int64, error) {
+	posts, cursor, err := pollNewPosts(ctx, db, scorer, cursor)
+	if err != nil {
+		return cursor, err
+	}
+
+	tiers := taste.TierNames(scorer.profile)
+	minRank, _ := minTierRank(tailMinTier, tiers)
+	for _, p := range posts {
+		if rank, _ := tierRank(p.Score.Tier, tiers); rank < minRank {
+			continue
+		}
+		fmt.Fprintln(w, tailLine(p, color))
+	}
+	return cursor, nil
+}
+
+// pollNewPosts returns posts ingested after cursor, oldest first, scoring and
+// saving any that are unscored, and the new cursor.
+func pollNewPosts(ctx context.Context, db *store.Store, scorer *postScorer, cursor int64) ([]store.PostWithScore, int64, error) {
+	posts, err := db.GetPosts(ctx, time.Time{}, "", store.PostFilter{AfterID: cursor, Order: store.OrderIngested})
+	if err != nil {
+		return nil, cursor, fmt.Errorf("get posts: %w", err)
+	}
+	if len(posts) == 0 {
+		return nil, cursor, nil
+	}
+	if err := scoreUnscored(ctx, db, scorer, posts, time.Now()); err != nil {
+		return nil, cursor, err
+	}
+	return posts, posts[len(posts)-1].Post.ID, nil
+}
+
+func tailLine(p store.PostWithScore, color bool) string {
+	line := fmt.Sprintf("%s [%d] %-8s %s/%s — %s",
+		p.Post.PostedAt.Local().Format("15:04"), p.Score.Score, p.Score.Tier,
+		p.Post.Source, p.Post.Channel, searchSnippet(p.Post))
+	if p.Post.URL != "" {
+		line += "  " + p.Post.URL
+	}
+	if !color {
+		return line
+	}
+	switch p.Score.Tier {
+	case taste.TierReadNow:
+		return "\033[32m" + line + "\033[0m"
+	case taste.TierIgnore:
+		return "\033[2m" + line + "\033[0m"
+	}
+	return "\033[33m" + line + "\033[0m"
+}
+
+// minTierRank resolves a --min-tier value against tiers, a profile's tier
+// names highest first. Empty means every tier above ignore.
+func minTierRank(name string, tiers []string) (int, error) {
+	if name == "" {
+		return 1, nil
+	}
+	rank, ok := tierRank(name, tiers)
+	if !ok {
+		return 0, fmt.Errorf("unknown tier %q (want %s)", name, strings.Join(tiers, ", "))
+	}
+	return rank, nil
+}
+
+// tierRank ranks tier by its place in tiers: ignore is 0 and each tier above
+// it one more. Tiers not in the list, say from an older profile, rank 0.
+func tierRank(tier string, tiers []string) (int, bool) {
+	i := slices.Index(tiers, tier)
+	if i < 0 {
+		return 0, false
+	}
+	return len(tiers) - 1 - i, true
+}
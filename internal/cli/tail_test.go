@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/taste"
+)
+
+func TestTailOnce(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "noisepan.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+	ctx := context.Background()
+	now := time.Now()
+
+	insert := func(id, text string) {
+		t.Helper()
+		if _, err := st.InsertPost(ctx, store.PostInput{
+			Source: "rss", Channel: "security", ExternalID: id,
+			Text: text, URL: "https://example.com/" + id, PostedAt: now, FetchedAt: now,
+		}); err != nil {
+			t.Fatalf("insert %s: %v", id, err)
+		}
+	}
+	insert("before", "cve seen before tail started")
+
+	cursor, err := st.LatestPostID(ctx)
+	if err != nil {
+		t.Fatalf("latest id: %v", err)
+	}
+
+	oldMinTier := tailMinTier
+	t.Cleanup(func() { tailMinTier = oldMinTier })
+	tailMinTier = taste.TierSkim
+
+	insert("hot", "cve OpenSSL exploited")
+	insert("meh", "cve in a changelog")
+	insert("noise", "team offsite photos")
+
+	profile := testScorerProfile()
+	profile.Weights.HighSignal["exploited"] = 3
+	scorer := &postScorer{profile: profile}
+	var buf bytes.Buffer
+	next, err := tailOnce(ctx, st, scorer, cursor, &buf, false)
+	if err != nil {
+		t.Fatalf("tail: %v", err)
+	}
+	if next != cursor+3 {
+		t.Errorf("cursor = %d, want %d", next, cursor+3)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("lines = %q, want read_now and skim only", lines)
+	}
+	requireContains(t, lines[0], "[8] read_now rss/security — cve OpenSSL exploited  https://example.com/hot")
+	requireContains(t, lines[1], "[5] skim     rss/security — cve in a changelog")
+
+	// New posts were scored and saved.
+	posts, err := st.GetPosts(ctx, time.Time{}, taste.TierIgnore)
+	if err != nil {
+		t.Fatalf("get posts: %v", err)
+	}
+	if len(posts) != 1 {
+		t.Errorf("ignored posts = %d, want 1", len(posts))
+	}
+
+	// Nothing new: no output, cursor unchanged.
+	buf.Reset()
+	if again, err := tailOnce(ctx, st, scorer, next, &buf, false); err != nil || again != next || buf.Len() != 0 {
+		t.Errorf("idle tail = %d, %v, %q", again, err, buf.String())
+	}
+}
+
+func TestTailLine_Color(t *testing.T) {
+	p := store.PostWithScore{
+		Post:  store.Post{Source: "rss", Channel: "c", Text: "x", PostedAt: time.Now()},
+		Score: &store.Score{Score: 9, Tier: taste.TierReadNow},
+	}
+	if got := tailLine(p, true); !strings.HasPrefix(got, "\033[32m") {
+		t.Errorf("read_now line not green: %q", got)
+	}
+	if got := tailLine(p, false); strings.Contains(got, "\033[") {
+		t.Errorf("uncolored line has ANSI codes: %q", got)
+	}
+}
+
+func TestMinTierRank(t *testing.T) {
+	tiers := []string{taste.TierReadNow, "today", "weekend", taste.TierIgnore}
+	for name, want := range map[string]int{"": 1, taste.TierReadNow: 3, "today": 2, taste.TierIgnore: 0} {
+		if got, err := minTierRank(name, tiers); err != nil || got != want {
+			t.Errorf("minTierRank(%q) = %d, %v; want %d", name, got, err, want)
+		}
+	}
+	if _, err := minTierRank(taste.TierSkim, tiers); err == nil || !strings.Contains(err.Error(), "read_now, today, weekend, ignore") {
+		t.Errorf("err = %v, want the profile's tiers listed", err)
+	}
+	if rank, ok := tierRank("retired", tiers); ok || rank != 0 {
+		t.Errorf("tierRank of unknown tier = %d, %v", rank, ok)
+	}
+}
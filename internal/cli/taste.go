@@ -0,0 +1,414 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/source"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/taste"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tasteSuggestMinVotes int
+	tasteSuggestApply    bool
+	tasteTrainMinVotes   int
+	tasteReportSince     string
+	tasteReportOutput    string
+)
+
+// Training weights: an explicit vote outweighs a tier inferred from keywords.
+const (
+	trainFeedbackWeight = 1.0
+	trainTierWeight     = 0.25
+)
+
+var tasteCmd = &cobra.Command{
+	Use:   "taste",
+	Short: "Inspect and tune the taste profile",
+}
+
+var tasteSuggestCmd = &cobra.Command{
+	Use:   "suggest",
+	Short: "Propose keyword weight changes from feedback",
+	Long: `Compares "noisepan feedback" votes with the tiers posts were scored into and
+proposes weight changes for keywords whose posts are consistently voted
+against their tier. Prints a diff for taste.yaml; --apply writes it.`,
+	RunE: tasteSuggestAction,
+}
+
+var tasteTrainCmd = &cobra.Command{
+	Use:   "train",
+	Short: "Train the on-device classifier from feedback and tier history",
+	Long: `Trains a naive Bayes classifier on feedback votes (up = worth reading,
+down = noise) and, with a quarter of the weight, on posts the keyword profile
+puts in read_now or ignore. The model is written to classifier.json in the
+config directory. With classifier.enabled in taste.yaml its probability adds
+up to ±classifier.max_points to every score.`,
+	Args: cobra.NoArgs,
+	RunE: tasteTrainAction,
+}
+
+var tasteReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Write a markdown report of how well the taste profile performs",
+	Long: `Measures taste.yaml against the posts scored in the window and writes a
+markdown report: keyword hit rates, rules that never fired, the label
+distribution, and threshold sensitivity (how the tier split changes if
+read_now or skim moves by one point). Meant to be generated periodically and
+kept next to the profile. Unscored posts are skipped; run "digest" first.`,
+	Args: cobra.NoArgs,
+	RunE: tasteReportAction,
+}
+
+func init() {
+	tasteReportCmd.Flags().StringVar(&tasteReportSince, "since", "90d", "time window (e.g. 90d, 720h)")
+	tasteReportCmd.Flags().StringVarP(&tasteReportOutput, "output", "o", "", "write to file instead of stdout")
+	tasteCmd.AddCommand(tasteReportCmd)
+	tasteTrainCmd.Flags().IntVar(&tasteTrainMinVotes, "min-votes", 10, "feedback votes required before training")
+	tasteCmd.AddCommand(tasteTrainCmd)
+	tasteSuggestCmd.Flags().IntVar(&tasteSuggestMinVotes, "min-votes", 3, "votes a keyword needs before it is considered")
+	tasteSuggestCmd.Flags().BoolVar(&tasteSuggestApply, "apply", false, "write the suggested weights to taste.yaml")
+	tasteCmd.AddCommand(tasteSuggestCmd)
+	rootCmd.AddCommand(tasteCmd)
+}
+
+func tasteSuggestAction(cmd *cobra.Command, _ []string) error {
+	cfg, err := config.Load(configDir)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	tastePath := profileTastePath(cfg)
+	profile, err := loadTaste(cfg, tastePath)
+	if err != nil {
+		return fmt.Errorf("load taste: %w", err)
+	}
+
+	db, err := openStore(cfg)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	voted, err := db.GetFeedbackPosts(cmd.Context(), time.Time{})
+	if err != nil {
+		return err
+	}
+
+	samples := make([]taste.Sample, 0, len(voted))
+	for _, fp := range voted {
+		if fp.Score == nil {
+			continue // tier unknown until the next digest scores it
+		}
+		samples = append(samples, taste.Sample{Text: postText(fp.Post), Tier: fp.Score.Tier, Vote: fp.Feedback.Vote})
+	}
+
+	w := cmd.OutOrStdout()
+	suggestions := taste.Suggest(profile, samples, tasteSuggestMinVotes)
+	if len(suggestions) == 0 {
+		fmt.Fprintf(w, "No suggestions from %d votes (keywords need %d+ consistent votes against their tier).\n",
+			len(samples), tasteSuggestMinVotes)
+		return nil
+	}
+
+	data, err := os.ReadFile(tastePath)
+	if err != nil {
+		return fmt.Errorf("read taste: %w", err)
+	}
+	patched, applied := writeTasteDiff(w, data, suggestions)
+
+	if !tasteSuggestApply || applied == 0 {
+		return nil
+	}
+	if dryRun {
+		fmt.Fprintf(w, "\nDry run: would apply %d suggestions to %s.\n", applied, tastePath)
+		return nil
+	}
+	info, err := os.Stat(tastePath)
+	if err != nil {
+		return fmt.Errorf("stat taste: %w", err)
+	}
+	if err := os.WriteFile(tastePath, patched, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("write taste: %w", err)
+	}
+	fmt.Fprintf(w, "\nApplied %d suggestions to %s. Run 'noisepan rescore' to rescore stored posts.\n", applied, tastePath)
+	return nil
+}
+
+func tasteTrainAction(cmd *cobra.Command, _ []string) error {
+	cfg, err := config.Load(configDir)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	profile, err := loadTaste(cfg, profileTastePath(cfg))
+	if err != nil {
+		return fmt.Errorf("load taste: %w", err)
+	}
+
+	db, err := openStore(cfg)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	ctx := cmd.Context()
+	voted, err := db.GetFeedbackPosts(ctx, time.Time{})
+	if err != nil {
+		return err
+	}
+	if len(voted) < tasteTrainMinVotes {
+		return fmt.Errorf("need %d feedback votes to train, have %d (use 'noisepan feedback <id> up|down')",
+			tasteTrainMinVotes, len(voted))
+	}
+	posts, err := db.GetPosts(ctx, time.Time{}, "")
+	if err != nil {
+		return fmt.Errorf("get posts: %w", err)
+	}
+
+	examples, inferred := trainingExamples(voted, posts, profile)
+	model, err := taste.Train(examples)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(configDir, config.DefaultClassifierFile)
+	w := cmd.OutOrStdout()
+	if dryRun {
+		fmt.Fprintf(w, "Dry run: would train on %d votes and %d tier-inferred posts (vocabulary %d) and write %s.\n",
+			len(voted), inferred, model.Vocab, path)
+		return nil
+	}
+	if err := model.Save(path); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Trained on %d votes and %d tier-inferred posts (vocabulary %d). Model written to %s.\n",
+		len(voted), inferred, model.Vocab, path)
+	if !profile.Classifier.Enabled {
+		fmt.Fprintln(w, "Set classifier.enabled: true in taste.yaml to use it, then run 'noisepan rescore'.")
+	} else {
+		fmt.Fprintln(w, "Run 'noisepan rescore' to apply it to stored posts.")
+	}
+	return nil
+}
+
+func tasteReportAction(cmd *cobra.Command, _ []string) error {
+	since, err := parseDuration(tasteReportSince)
+	if err != nil {
+		return fmt.Errorf("parse --since: %w", err)
+	}
+
+	cfg, err := config.Load(configDir)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	profile, err := loadTaste(cfg, profileTastePath(cfg))
+	if err != nil {
+		return fmt.Errorf("load taste: %w", err)
+	}
+
+	db, err := openStore(cfg)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	posts, err := db.GetPosts(cmd.Context(), time.Now().Add(-since), "")
+	if err != nil {
+		return fmt.Errorf("get posts: %w", err)
+	}
+	scored := make([]taste.ReportPost, 0, len(posts))
+	for _, p := range posts {
+		if p.Score == nil {
+			continue
+		}
+		scored = append(scored, taste.ReportPost{
+			Text: postText(p.Post), Score: p.Score.Score, Tier: p.Score.Tier, Labels: p.Score.Labels,
+		})
+	}
+
+	w := cmd.OutOrStdout()
+	if tasteReportOutput != "" {
+		f, err := os.Create(tasteReportOutput)
+		if err != nil {
+			return fmt.Errorf("create output: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+	writeTasteReport(w, taste.Report(profile, scored), since, time.Now())
+	return nil
+}
+
+// writeTasteReport renders a profile report as markdown.
+func writeTasteReport(w io.Writer, rep taste.ProfileReport, since time.Duration, now time.Time) {
+	fmt.Fprintf(w, "# Taste profile report\n\n")
+	fmt.Fprintf(w, "Generated %s from %d scored posts in the last %s.\n",
+		now.Format("2006-01-02"), rep.Posts, formatStatsDuration(since))
+
+	fmt.Fprintf(w, "\n## Keywords\n\n")
+	if len(rep.Keywords) == 0 {
+		fmt.Fprintln(w, "No keywords in the profile.")
+	} else {
+		fmt.Fprintln(w, "| Keyword | Section | Weight | Hits | Hit rate | read_now | ignore |")
+		fmt.Fprintln(w, "|---|---|---:|---:|---:|---:|---:|")
+		var unused []string
+		for _, k := range rep.Keywords {
+			fmt.Fprintf(w, "| %s | %s | %+d | %d | %s | %d | %d |\n",
+				markdownCell(k.Keyword), k.Section, k.Weight, k.Hits, percent(k.Hits, rep.Posts), k.ReadNow, k.Ignore)
+			if k.Hits == 0 {
+				unused = append(unused, k.Keyword)
+			}
+		}
+		if len(unused) > 0 {
+			fmt.Fprintf(w, "\n%d keywords never matched: %s.\n", len(unused), strings.Join(unused, ", "))
+		}
+	}
+
+	fmt.Fprintf(w, "\n## Rules\n\n")
+	if len(rep.Rules) == 0 {
+		fmt.Fprintln(w, "No rules in the profile.")
+	} else {
+		fmt.Fprintln(w, "| # | Contains any | Labels | Fired |")
+		fmt.Fprintln(w, "|---:|---|---|---:|")
+		var never []string
+		for _, r := range rep.Rules {
+			fmt.Fprintf(w, "| %d | %s | %s | %d |\n",
+				r.Index, markdownCell(strings.Join(r.Match, ", ")), markdownCell(strings.Join(r.Labels, ", ")), r.Fired)
+			if r.Fired == 0 {
+				never = append(never, fmt.Sprintf("- rule %d (%s)", r.Index, strings.Join(r.Match, ", ")))
+			}
+		}
+		if len(never) > 0 {
+			fmt.Fprintf(w, "\nNever fired:\n\n%s\n", strings.Join(never, "\n"))
+		}
+	}
+
+	fmt.Fprintf(w, "\n## Labels\n\n")
+	if len(rep.Labels) == 0 {
+		fmt.Fprintln(w, "No labelled posts.")
+	} else {
+		fmt.Fprintln(w, "| Label | Posts | Share |")
+		fmt.Fprintln(w, "|---|---:|---:|")
+		for _, l := range rep.Labels {
+			fmt.Fprintf(w, "| %s | %d | %s |\n", markdownCell(l.Label), l.Posts, percent(l.Posts, rep.Posts))
+		}
+	}
+
+	fmt.Fprintf(w, "\n## Threshold sensitivity\n\n")
+	fmt.Fprintln(w, "| Shift | read_now at | skim at | read_now posts | skim posts | ignore posts |")
+	fmt.Fprintln(w, "|---|---:|---:|---:|---:|---:|")
+	cur := rep.Shifts[0]
+	for _, s := range rep.Shifts {
+		fmt.Fprintf(w, "| %s | %d | %d | %s | %s | %s |\n", s.Name, s.Thresholds.ReadNow, s.Thresholds.Skim,
+			shiftCount(s.ReadNow, cur.ReadNow), shiftCount(s.Skim, cur.Skim), shiftCount(s.Ignore, cur.Ignore))
+	}
+}
+
+// percent formats n as a share of total.
+func percent(n, total int) string {
+	if total == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%.1f%%", 100*float64(n)/float64(total))
+}
+
+// shiftCount formats a tier count with its change from the current one.
+func shiftCount(n, current int) string {
+	if n == current {
+		return fmt.Sprint(n)
+	}
+	return fmt.Sprintf("%d (%+d)", n, n-current)
+}
+
+// markdownCell escapes pipes so text stays in its table cell.
+func markdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
+
+// trainingExamples labels voted posts by their vote and other posts by the
+// tier the keyword profile alone gives them (skim is too uncertain to use).
+// Keyword tiers are recomputed so an enabled classifier never trains on its
+// own output. It returns the examples and how many were tier-inferred.
+func trainingExamples(voted []store.FeedbackPost, posts []store.PostWithScore, profile *config.TasteProfile) ([]taste.Example, int) {
+	examples := make([]taste.Example, 0, len(voted)+len(posts))
+	seen := make(map[int64]bool, len(voted))
+	for _, fp := range voted {
+		seen[fp.Post.ID] = true
+		examples = append(examples, taste.Example{
+			Text:     postText(fp.Post),
+			Positive: fp.Feedback.Vote > 0,
+			Weight:   trainFeedbackWeight,
+		})
+	}
+
+	inferred := 0
+	for _, p := range posts {
+		if seen[p.Post.ID] {
+			continue
+		}
+		text := postText(p.Post)
+		switch taste.Score(source.Post{Text: text}, profile).Tier {
+		case taste.TierReadNow:
+			examples = append(examples, taste.Example{Text: text, Positive: true, Weight: trainTierWeight})
+		case taste.TierIgnore:
+			examples = append(examples, taste.Example{Text: text, Positive: false, Weight: trainTierWeight})
+		default:
+			continue
+		}
+		inferred++
+	}
+	return examples, inferred
+}
+
+// postText returns the stored full text, or the snippet if it was not kept.
+func postText(p store.Post) string {
+	if p.Text != "" {
+		return p.Text
+	}
+	return p.Snippet
+}
+
+// writeTasteDiff prints a diff of the suggested changes against taste.yaml
+// and returns the patched document and the number of changes it contains.
+func writeTasteDiff(w io.Writer, data []byte, suggestions []taste.Suggestion) ([]byte, int) {
+	fmt.Fprintf(w, "--- %s\n+++ %s (suggested)\n", config.DefaultTasteFile, config.DefaultTasteFile)
+
+	applied := 0
+	for _, sg := range suggestions {
+		verb := "downvoted"
+		votes := sg.Down
+		if sg.Suggested > sg.Current {
+			verb, votes = "upvoted", sg.Up
+		}
+		fmt.Fprintf(w, "# posts containing %q were %s %d of %d times (%d against their tier): suggest %+d\n",
+			sg.Keyword, verb, votes, sg.Up+sg.Down, sg.Disagreed, sg.Suggested-sg.Current)
+
+		next, ok := config.SetTasteWeight(data, sg.Section, sg.Keyword, sg.Suggested)
+		if !ok {
+			fmt.Fprintf(w, "# could not find %s.%s in %s; edit it by hand\n", sg.Section, sg.Keyword, config.DefaultTasteFile)
+			continue
+		}
+		before, after := changedLine(data, next)
+		fmt.Fprintf(w, "-%s\n+%s\n", before, after)
+		data = next
+		applied++
+	}
+	return data, applied
+}
+
+// changedLine returns the first line that differs between a and b.
+func changedLine(a, b []byte) (string, string) {
+	al, bl := strings.Split(string(a), "\n"), strings.Split(string(b), "\n")
+	for i := range al {
+		if i < len(bl) && al[i] != bl[i] {
+			return al[i], bl[i]
+		}
+	}
+	return "", ""
+}
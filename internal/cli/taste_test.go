@@ -0,0 +1,267 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/source"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/taste"
+	"github.com/spf13/cobra"
+)
+
+func TestTasteSuggestAction(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	scriptPath := filepath.Join(tmpDir, "forge-plan.sh")
+
+	writeTestForgePlanScript(t, scriptPath)
+	writeTestConfig(t, tmpDir, dbPath, scriptPath)
+	writeTestTaste(t, tmpDir)
+
+	st, err := store.Open(dbPath)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	ctx := context.Background()
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		p, err := st.InsertPost(ctx, store.PostInput{
+			Source: "rss", Channel: "k8s", ExternalID: strconv.Itoa(i),
+			Text: "Kubernetes community update " + strconv.Itoa(i), PostedAt: now, FetchedAt: now,
+		})
+		if err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+		if err := st.SaveScore(ctx, store.Score{PostID: p.ID, Score: 3, Tier: "skim", ScoredAt: now}); err != nil {
+			t.Fatalf("save score: %v", err)
+		}
+		if err := st.SaveFeedback(ctx, store.Feedback{PostID: p.ID, Vote: store.FeedbackDown, CreatedAt: now}); err != nil {
+			t.Fatalf("save feedback: %v", err)
+		}
+	}
+	_ = st.Close()
+
+	oldConfigDir, oldMin, oldApply := configDir, tasteSuggestMinVotes, tasteSuggestApply
+	t.Cleanup(func() { configDir, tasteSuggestMinVotes, tasteSuggestApply = oldConfigDir, oldMin, oldApply })
+	configDir = tmpDir
+	tasteSuggestMinVotes = 3
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(ctx)
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+
+	// Dry run prints the diff and leaves the file alone.
+	tasteSuggestApply = false
+	if err := tasteSuggestAction(cmd, nil); err != nil {
+		t.Fatalf("suggest: %v", err)
+	}
+	out := buf.String()
+	requireContains(t, out, `posts containing "kubernetes" were downvoted 3 of 3 times (3 against their tier): suggest -2`)
+	requireContains(t, out, "-    \"kubernetes\": 3\n+    \"kubernetes\": 1\n")
+
+	tastePath := filepath.Join(tmpDir, "taste.yaml")
+	data, err := os.ReadFile(tastePath)
+	if err != nil {
+		t.Fatalf("read taste: %v", err)
+	}
+	if !strings.Contains(string(data), `"kubernetes": 3`) {
+		t.Fatal("dry run modified taste.yaml")
+	}
+
+	buf.Reset()
+	tasteSuggestApply = true
+	if err := tasteSuggestAction(cmd, nil); err != nil {
+		t.Fatalf("suggest --apply: %v", err)
+	}
+	requireContains(t, buf.String(), "Applied 1 suggestions")
+	data, err = os.ReadFile(tastePath)
+	if err != nil {
+		t.Fatalf("read taste: %v", err)
+	}
+	if !strings.Contains(string(data), `"kubernetes": 1`) || !strings.Contains(string(data), `"cve": 5`) {
+		t.Errorf("taste.yaml after apply:\n%s", data)
+	}
+}
+
+func TestTasteTrainAction(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	scriptPath := filepath.Join(tmpDir, "forge-plan.sh")
+
+	writeTestForgePlanScript(t, scriptPath)
+	writeTestConfig(t, tmpDir, dbPath, scriptPath)
+	writeTestTaste(t, tmpDir)
+
+	st, err := store.Open(dbPath)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	ctx := context.Background()
+	now := time.Now()
+	posts := []struct {
+		text string
+		vote int
+	}{
+		{"Kernel exploit chain disclosed", store.FeedbackUp},
+		{"Kernel exploit mitigations land", store.FeedbackUp},
+		{"Sponsored cloud roundup", store.FeedbackDown},
+		{"Sponsored cloud newsletter", store.FeedbackDown},
+		{"CVE in kubernetes admission controller", 0}, // read_now by keywords
+		{"Register for our webinar", 0},               // ignore by keywords
+	}
+	for i, in := range posts {
+		p, err := st.InsertPost(ctx, store.PostInput{
+			Source: "rss", Channel: "news", ExternalID: strconv.Itoa(i),
+			Text: in.text, PostedAt: now, FetchedAt: now,
+		})
+		if err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+		if in.vote != 0 {
+			if err := st.SaveFeedback(ctx, store.Feedback{PostID: p.ID, Vote: in.vote, CreatedAt: now}); err != nil {
+				t.Fatalf("save feedback: %v", err)
+			}
+		}
+	}
+	_ = st.Close()
+
+	oldConfigDir, oldMin := configDir, tasteTrainMinVotes
+	t.Cleanup(func() { configDir, tasteTrainMinVotes = oldConfigDir, oldMin })
+	configDir = tmpDir
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(ctx)
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+
+	tasteTrainMinVotes = 5
+	if err := tasteTrainAction(cmd, nil); err == nil || !strings.Contains(err.Error(), "need 5 feedback votes") {
+		t.Fatalf("err = %v, want not enough votes", err)
+	}
+
+	tasteTrainMinVotes = 4
+	if err := tasteTrainAction(cmd, nil); err != nil {
+		t.Fatalf("train: %v", err)
+	}
+	requireContains(t, buf.String(), "Trained on 4 votes and 2 tier-inferred posts")
+	requireContains(t, buf.String(), "Set classifier.enabled: true")
+
+	// Enabled, the model lifts a post no keyword matches.
+	tastePath := filepath.Join(tmpDir, "taste.yaml")
+	data, err := os.ReadFile(tastePath)
+	if err != nil {
+		t.Fatalf("read taste: %v", err)
+	}
+	if err := os.WriteFile(tastePath, append(data, []byte("classifier:\n  enabled: true\n")...), 0o644); err != nil {
+		t.Fatalf("write taste: %v", err)
+	}
+	cfg, err := config.Load(tmpDir)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	profile, err := config.LoadTaste(tastePath)
+	if err != nil {
+		t.Fatalf("load taste: %v", err)
+	}
+	scorer, err := newPostScorer(cfg, profile)
+	if err != nil {
+		t.Fatalf("newPostScorer: %v", err)
+	}
+	sp := scorer.score(source.Post{Text: "New kernel exploit found"})
+	if sp.Score <= 0 || !strings.HasPrefix(sp.Explanation[len(sp.Explanation)-1].Reason, "classifier:") {
+		t.Errorf("score = %d, explanation = %+v, want classifier boost", sp.Score, sp.Explanation)
+	}
+}
+
+func TestTasteReportAction(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	scriptPath := filepath.Join(tmpDir, "forge-plan.sh")
+
+	writeTestForgePlanScript(t, scriptPath)
+	writeTestConfig(t, tmpDir, dbPath, scriptPath)
+	writeTestTaste(t, tmpDir)
+
+	st, err := store.Open(dbPath)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	ctx := context.Background()
+	now := time.Now()
+	for i, p := range []struct {
+		text   string
+		score  int
+		tier   string
+		labels []string
+	}{
+		{"CVE-2026-1 kubernetes breaking change", 10, "read_now", []string{"ops"}},
+		{"Kubernetes 1.40 released", 3, "skim", nil},
+		{"Untagged post", 0, "ignore", nil},
+	} {
+		post, err := st.InsertPost(ctx, store.PostInput{
+			Source: "rss", Channel: "k8s", ExternalID: strconv.Itoa(i),
+			Text: p.text, PostedAt: now, FetchedAt: now,
+		})
+		if err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+		if err := st.SaveScore(ctx, store.Score{PostID: post.ID, Score: p.score, Tier: p.tier, Labels: p.labels, ScoredAt: now}); err != nil {
+			t.Fatalf("save score: %v", err)
+		}
+	}
+	_ = st.Close()
+
+	oldConfigDir, oldSince, oldOutput := configDir, tasteReportSince, tasteReportOutput
+	t.Cleanup(func() { configDir, tasteReportSince, tasteReportOutput = oldConfigDir, oldSince, oldOutput })
+	configDir = tmpDir
+	tasteReportSince = "90d"
+	tasteReportOutput = filepath.Join(tmpDir, "report.md")
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(ctx)
+	if err := tasteReportAction(cmd, nil); err != nil {
+		t.Fatalf("report: %v", err)
+	}
+	data, err := os.ReadFile(tasteReportOutput)
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	out := string(data)
+	requireContains(t, out, "# Taste profile report")
+	requireContains(t, out, "from 3 scored posts in the last 90 days.")
+	requireContains(t, out, "| kubernetes | high_signal | +3 | 2 | 66.7% | 1 | 0 |")
+	requireContains(t, out, "| webinar | low_signal | -4 | 0 | 0.0% | 0 | 0 |")
+	requireContains(t, out, "1 keywords never matched: webinar.")
+	requireContains(t, out, "| 1 | breaking change | ops | 1 |")
+	requireContains(t, out, "| ops | 1 | 33.3% |")
+	requireContains(t, out, "| current | 7 | 3 | 1 | 1 | 1 |")
+	requireContains(t, out, "| skim +1 | 7 | 4 | 1 | 0 (-1) | 2 (+1) |")
+
+	tasteReportSince = "soon"
+	if err := tasteReportAction(cmd, nil); err == nil {
+		t.Error("expected error for bad --since")
+	}
+}
+
+func TestWriteTasteReport_NeverFiredRules(t *testing.T) {
+	profile := &config.TasteProfile{
+		Rules: []config.Rule{{If: config.RuleCondition{ContainsAny: []string{"a|b", "outage"}}}},
+	}
+	var buf bytes.Buffer
+	writeTasteReport(&buf, taste.Report(profile, nil), 90*24*time.Hour, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC))
+	out := buf.String()
+	requireContains(t, out, "Generated 2026-10-01 from 0 scored posts")
+	requireContains(t, out, "No keywords in the profile.")
+	requireContains(t, out, "| 1 | a\\|b, outage |  | 0 |")
+	requireContains(t, out, "Never fired:\n\n- rule 1 (a|b, outage)\n")
+	requireContains(t, out, "No labelled posts.")
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tasteDiffSince    string
+	tasteDiffExamples int
+)
+
+var tasteDiffCmd = &cobra.Command{
+	Use:   "diff <proposed.yaml>",
+	Short: "Preview how a proposed taste profile would change stored posts' tiers",
+	Long: `Scores the posts stored in the window with both the active taste profile
+and a proposed one (keyword and rule scores only, no classifier or LLM
+triage) and reports how the tier counts change and which posts move, with
+a few examples per move. Nothing is written; run "rescore" after adopting
+the proposal.`,
+	Args: cobra.ExactArgs(1),
+	RunE: tasteDiffAction,
+}
+
+func init() {
+	tasteDiffCmd.Flags().StringVar(&tasteDiffSince, "since", "7d", "window of stored posts to compare on")
+	tasteDiffCmd.Flags().IntVar(&tasteDiffExamples, "examples", 3, "example posts shown per tier change")
+	tasteCmd.AddCommand(tasteDiffCmd)
+}
+
+func tasteDiffAction(cmd *cobra.Command, args []string) error {
+	since, err := parseDuration(tasteDiffSince)
+	if err != nil {
+		return fmt.Errorf("parse --since: %w", err)
+	}
+	cfg, err := config.Load(configDir)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	currentPath := profileTastePath(cfg)
+	current, err := loadTaste(cfg, currentPath)
+	if err != nil {
+		return fmt.Errorf("load taste: %w", err)
+	}
+	proposed, err := loadTaste(cfg, args[0])
+	if err != nil {
+		return fmt.Errorf("load proposed taste: %w", err)
+	}
+
+	db, err := openStore(cfg)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+	posts, err := db.GetPosts(cmd.Context(), time.Now().Add(-since), "")
+	if err != nil {
+		return fmt.Errorf("get posts: %w", err)
+	}
+
+	w := cmd.OutOrStdout()
+	fmt.Fprintf(w, "%s → %s\n", currentPath, args[0])
+	sim := tierShift(posts, current, proposed)
+	printTierShift(w, sim, len(posts), since)
+	printTierMoves(w, sim.moves, tasteDiffExamples)
+	return nil
+}
+
+// printTierMoves groups moves by tier change, largest group first, and
+// prints up to examples posts of each.
+func printTierMoves(w io.Writer, moves []tierMove, examples int) {
+	type group struct {
+		before, after string
+		moves         []tierMove
+	}
+	var groups []*group
+	byKey := make(map[[2]string]*group)
+	for _, m := range moves {
+		key := [2]string{m.before, m.after}
+		g, ok := byKey[key]
+		if !ok {
+			g = &group{before: m.before, after: m.after}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		g.moves = append(g.moves, m)
+	}
+	slices.SortStableFunc(groups, func(a, b *group) int {
+		return cmp.Compare(len(b.moves), len(a.moves))
+	})
+
+	for _, g := range groups {
+		fmt.Fprintf(w, "\n%s → %s: %d posts\n", g.before, g.after, len(g.moves))
+		shown := max(0, min(examples, len(g.moves)))
+		for _, m := range g.moves[:shown] {
+			fmt.Fprintf(w, "  #%d  %+d → %+d  %s/%s: %s\n",
+				m.post.ID, m.beforeScore, m.afterScore, m.post.Source, m.post.Channel, headline(storePostToSourcePost(m.post).Text))
+		}
+		if more := len(g.moves) - shown; more > 0 && shown > 0 {
+			fmt.Fprintf(w, "  ... and %d more\n", more)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func TestTasteDiffAction(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	writeTestConfig(t, tmpDir, dbPath, filepath.Join(tmpDir, "forge-plan.sh"))
+	writeTestTaste(t, tmpDir)
+
+	st, err := store.Open(dbPath)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	now := time.Now()
+	for i, text := range []string{"Join our webinar on Kubernetes", "Webinar: CVE triage", "Kubernetes CVE-2026-1111 patch", "Weekly links"} {
+		if _, err := st.InsertPost(context.Background(), store.PostInput{
+			Source: "rss", Channel: "k8s", ExternalID: string(rune('a' + i)),
+			Text: text, PostedAt: now, FetchedAt: now,
+		}); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+	}
+	_ = st.Close()
+
+	// The proposal boosts webinars and drops every other keyword.
+	proposed := filepath.Join(tmpDir, "proposed.yaml")
+	if err := os.WriteFile(proposed, []byte("weights:\n  high_signal:\n    \"webinar\": 4\nthresholds:\n  read_now: 7\n  skim: 3\n  ignore: 0\n"), 0o644); err != nil {
+		t.Fatalf("write proposal: %v", err)
+	}
+
+	oldConfigDir, oldSince, oldExamples := configDir, tasteDiffSince, tasteDiffExamples
+	t.Cleanup(func() { configDir, tasteDiffSince, tasteDiffExamples = oldConfigDir, oldSince, oldExamples })
+	configDir, tasteDiffSince, tasteDiffExamples = tmpDir, "7d", 1
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := tasteDiffAction(cmd, []string{proposed}); err != nil {
+		t.Fatalf("taste diff: %v", err)
+	}
+	out := buf.String()
+	requireContains(t, out, "Tried on 4 posts")
+	requireContains(t, out, "3 posts change tier")
+	requireContains(t, out, "ignore → skim: 2 posts")
+	requireContains(t, out, "read_now → ignore: 1 posts")
+	requireContains(t, out, "... and 1 more")
+	if strings.Index(out, "ignore → skim") > strings.Index(out, "read_now → ignore") {
+		t.Errorf("larger change not listed first:\n%s", out)
+	}
+	if strings.Contains(out, "Weekly links") {
+		t.Errorf("unchanged post listed:\n%s", out)
+	}
+
+	if err := tasteDiffAction(cmd, []string{filepath.Join(tmpDir, "missing.yaml")}); err == nil || !strings.Contains(err.Error(), "proposed") {
+		t.Errorf("missing proposal err = %v", err)
+	}
+}
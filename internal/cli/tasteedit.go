@@ -0,0 +1,253 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/taste"
+	"github.com/spf13/cobra"
+)
+
+var tasteEditSince string
+
+var tasteEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit taste.yaml in $EDITOR, validated before it is saved",
+	Long: `Opens a copy of taste.yaml in $VISUAL or $EDITOR. When the editor exits the
+copy is validated; an invalid profile can be edited again or discarded, so
+a typo never reaches the file scheduled runs load.
+
+A valid profile is tried on the posts stored in the window (keyword and
+rule scores only, no classifier or LLM triage) and the change in tier
+counts is shown before asking to save. Saving replaces taste.yaml in one
+step; --dry-run only shows the summary.`,
+	Args: cobra.NoArgs,
+	RunE: tasteEditAction,
+}
+
+func init() {
+	tasteEditCmd.Flags().StringVar(&tasteEditSince, "since", "7d", "window of stored posts the edit is tried on")
+	tasteCmd.AddCommand(tasteEditCmd)
+}
+
+// runEditor opens path in the user's editor and waits for it. Tests
+// replace it.
+var runEditor = func(ctx context.Context, path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+	args := strings.Fields(editor)
+	c := exec.CommandContext(ctx, args[0], append(args[1:], path)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("run editor %q: %w", editor, err)
+	}
+	return nil
+}
+
+func tasteEditAction(cmd *cobra.Command, _ []string) error {
+	since, err := parseDuration(tasteEditSince)
+	if err != nil {
+		return fmt.Errorf("parse --since: %w", err)
+	}
+
+	cfg, err := config.Load(configDir)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	tastePath := profileTastePath(cfg)
+	original, err := os.ReadFile(tastePath)
+	if err != nil {
+		return fmt.Errorf("read taste: %w", err)
+	}
+	info, err := os.Stat(tastePath)
+	if err != nil {
+		return fmt.Errorf("stat taste: %w", err)
+	}
+	// An already broken profile can still be fixed here; it just has no
+	// tiers to compare against.
+	current, _ := loadTaste(cfg, tastePath)
+
+	// The copy sits next to taste.yaml so the rename that saves it stays on
+	// one file system.
+	f, err := os.CreateTemp(filepath.Dir(tastePath), ".taste-*.yaml")
+	if err != nil {
+		return fmt.Errorf("create edit copy: %w", err)
+	}
+	editPath := f.Name()
+	defer func() { _ = os.Remove(editPath) }()
+	_, err = f.Write(original)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("write edit copy: %w", err)
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	in := bufio.NewScanner(cmd.InOrStdin())
+	w := cmd.OutOrStdout()
+	for {
+		if err := runEditor(ctx, editPath); err != nil {
+			return err
+		}
+		edited, err := os.ReadFile(editPath)
+		if err != nil {
+			return fmt.Errorf("read edit copy: %w", err)
+		}
+		if bytes.Equal(edited, original) {
+			fmt.Fprintln(w, "No changes; taste.yaml left as is.")
+			return nil
+		}
+
+		profile, err := loadTaste(cfg, editPath)
+		if err != nil {
+			fmt.Fprintf(w, "Invalid taste profile: %v\n", err)
+			if promptEdit(in, w, "[e]dit again or [d]iscard > ", "ed") == 'e' {
+				continue
+			}
+			return errors.New("taste.yaml left unchanged: the edit did not validate")
+		}
+
+		if err := simulateTasteEdit(ctx, w, current, profile, since); err != nil {
+			fmt.Fprintf(w, "Simulation skipped: %v\n", err)
+		}
+		if dryRun {
+			fmt.Fprintln(w, "Dry run: taste.yaml left as is.")
+			return nil
+		}
+		switch promptEdit(in, w, "[s]ave, [e]dit again or [d]iscard > ", "sed") {
+		case 'e':
+			continue
+		case 's':
+			if err := os.Chmod(editPath, info.Mode().Perm()); err != nil {
+				return fmt.Errorf("save taste: %w", err)
+			}
+			if err := os.Rename(editPath, tastePath); err != nil {
+				return fmt.Errorf("save taste: %w", err)
+			}
+			fmt.Fprintf(w, "Saved %s. Run 'noisepan rescore' to rescore stored posts.\n", tastePath)
+			return nil
+		default:
+			fmt.Fprintln(w, "Discarded; taste.yaml left as is.")
+			return nil
+		}
+	}
+}
+
+// promptEdit asks until the answer starts with one of choices and returns
+// it. End of input discards (returns 'd').
+func promptEdit(in *bufio.Scanner, w io.Writer, prompt, choices string) byte {
+	for {
+		fmt.Fprint(w, prompt)
+		if !in.Scan() {
+			fmt.Fprintln(w)
+			return 'd'
+		}
+		answer := strings.ToLower(strings.TrimSpace(in.Text()))
+		if answer != "" && strings.IndexByte(choices, answer[0]) >= 0 {
+			return answer[0]
+		}
+		fmt.Fprintln(w, "  unknown choice")
+	}
+}
+
+// simulateTasteEdit scores the stored posts of the window with the current
+// and the edited profile and prints how the tier counts change. current is
+// nil when taste.yaml did not load.
+func simulateTasteEdit(ctx context.Context, w io.Writer, current, edited *config.TasteProfile, since time.Duration) error {
+	db, err := openConfiguredStore()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+	posts, err := db.GetPosts(ctx, time.Now().Add(-since), "")
+	if err != nil {
+		return fmt.Errorf("get posts: %w", err)
+	}
+	printTierShift(w, tierShift(posts, current, edited), len(posts), since)
+	return nil
+}
+
+// printTierShift prints the tier counts of sim, tried on n posts of the
+// window.
+func printTierShift(w io.Writer, sim tierSimulation, n int, since time.Duration) {
+	fmt.Fprintf(w, "Tried on %d posts from the last %s (keyword and rule scores):\n", n, formatStatsDuration(since))
+	width := 0
+	for _, tier := range sim.tiers {
+		width = max(width, len(tier))
+	}
+	for _, tier := range sim.tiers {
+		before, after := sim.before[tier], sim.after[tier]
+		fmt.Fprintf(w, "  %-*s %5d → %-5d (%+d)\n", width, tier, before, after, after-before)
+	}
+	fmt.Fprintf(w, "  %d posts change tier\n", len(sim.moves))
+}
+
+// tierSimulation counts posts per tier under two profiles.
+type tierSimulation struct {
+	tiers         []string // the edited profile's tiers, then tiers only the current one has
+	before, after map[string]int
+	moves         []tierMove // posts whose tier changed, in the order scored
+}
+
+// tierMove is a post the edited profile files under another tier.
+type tierMove struct {
+	post                    store.Post
+	before, after           string
+	beforeScore, afterScore int
+}
+
+// tierShift scores posts with both profiles. With no current profile every
+// post counts as moved.
+func tierShift(posts []store.PostWithScore, current, edited *config.TasteProfile) tierSimulation {
+	sim := tierSimulation{tiers: taste.TierNames(edited), before: map[string]int{}, after: map[string]int{}}
+	var prev *postScorer
+	if current != nil {
+		prev = &postScorer{profile: current}
+		for _, tier := range taste.TierNames(current) {
+			if !slices.Contains(sim.tiers, tier) {
+				sim.tiers = append(sim.tiers, tier)
+			}
+		}
+	}
+	next := &postScorer{profile: edited}
+	for _, p := range posts {
+		post := storePostToSourcePost(p.Post)
+		n := next.score(post)
+		sim.after[n.Tier]++
+		move := tierMove{post: p.Post, after: n.Tier, afterScore: n.Score}
+		if prev != nil {
+			b := prev.score(post)
+			move.before, move.beforeScore = b.Tier, b.Score
+			sim.before[b.Tier]++
+		}
+		if move.before != move.after {
+			sim.moves = append(sim.moves, move)
+		}
+	}
+	return sim
+}
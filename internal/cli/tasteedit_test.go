@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func TestTasteEditAction(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	writeTestConfig(t, tmpDir, dbPath, filepath.Join(tmpDir, "forge-plan.sh"))
+	writeTestTaste(t, tmpDir)
+	tastePath := filepath.Join(tmpDir, "taste.yaml")
+	original, err := os.ReadFile(tastePath)
+	if err != nil {
+		t.Fatalf("read taste: %v", err)
+	}
+
+	st, err := store.Open(dbPath)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	ctx := context.Background()
+	now := time.Now()
+	for i, text := range []string{"Kubernetes release notes", "Join our webinar", "Weekly links"} {
+		if _, err := st.InsertPost(ctx, store.PostInput{
+			Source: "rss", Channel: "k8s", ExternalID: string(rune('a' + i)),
+			Text: text, PostedAt: now, FetchedAt: now,
+		}); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+	}
+	_ = st.Close()
+
+	oldConfigDir, oldEditor, oldDryRun := configDir, runEditor, dryRun
+	t.Cleanup(func() { configDir, runEditor, dryRun = oldConfigDir, oldEditor, oldDryRun })
+	configDir = tmpDir
+
+	// edits are written by successive editor runs.
+	run := func(input string, edits ...string) (string, error) {
+		t.Helper()
+		runEditor = func(_ context.Context, path string) error {
+			if len(edits) == 0 {
+				t.Fatal("editor opened more often than expected")
+			}
+			err := os.WriteFile(path, []byte(edits[0]), 0o600)
+			edits = edits[1:]
+			return err
+		}
+		cmd := &cobra.Command{}
+		cmd.SetContext(ctx)
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetIn(strings.NewReader(input))
+		err := tasteEditAction(cmd, nil)
+		return buf.String(), err
+	}
+	requireTaste := func(want string) {
+		t.Helper()
+		got, err := os.ReadFile(tastePath)
+		if err != nil {
+			t.Fatalf("read taste: %v", err)
+		}
+		if string(got) != want {
+			t.Fatalf("taste.yaml = %q, want %q", got, want)
+		}
+	}
+	boosted := strings.Replace(string(original), `"kubernetes": 3`, `"kubernetes": 8`, 1)
+
+	out, err := run("", string(original))
+	if err != nil {
+		t.Fatalf("unchanged: %v", err)
+	}
+	requireContains(t, out, "No changes")
+
+	// A broken edit is never saved, even when the user gives up.
+	broken := "weights: [\n"
+	if _, err := run("d\n", broken); err == nil {
+		t.Fatal("expected error for discarded invalid edit")
+	}
+	requireTaste(string(original))
+
+	// Fixing it on the second pass shows the tier shift, then saves.
+	out, err = run("e\ns\n", broken, boosted)
+	if err != nil {
+		t.Fatalf("edit: %v", err)
+	}
+	requireContains(t, out, "Invalid taste profile")
+	requireContains(t, out, "Tried on 3 posts")
+	requireContains(t, out, "read_now     0 → 1     (+1)")
+	requireContains(t, out, "1 posts change tier")
+	requireContains(t, out, "Saved ")
+	requireTaste(boosted)
+	info, err := os.Stat(tastePath)
+	if err != nil {
+		t.Fatalf("stat taste: %v", err)
+	}
+	if info.Mode().Perm() != 0o644 {
+		t.Fatalf("mode = %v, want 0644", info.Mode().Perm())
+	}
+
+	// Discarding and dry runs leave the file alone.
+	if _, err := run("d\n", string(original)); err != nil {
+		t.Fatalf("discard: %v", err)
+	}
+	requireTaste(boosted)
+	dryRun = true
+	out, err = run("", string(original))
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	requireContains(t, out, "Dry run")
+	requireTaste(boosted)
+
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".taste-") {
+			t.Fatalf("edit copy %s left behind", e.Name())
+		}
+	}
+}
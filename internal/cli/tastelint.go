@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/taste"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tasteLintFormat string
+	tasteLintStrict bool
+)
+
+var tasteLintCmd = &cobra.Command{
+	Use:   "lint [taste.yaml]",
+	Short: "Check a taste profile for rules that never fire and conflicting keywords",
+	Long: `Validates a taste profile (the active one by default) and checks it for
+mistakes validation allows: keywords in both high_signal and low_signal,
+rules that can never match, labels rules use but labels does not define,
+terms written as regular expressions (terms match literally), and tiers no
+score can reach.
+
+Exits non-zero when an error is found, or with --strict a warning too, so
+it can gate CI on a dotfiles repository; --format json prints the issues
+for tools.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: tasteLintAction,
+}
+
+func init() {
+	tasteLintCmd.Flags().StringVar(&tasteLintFormat, "format", "terminal", "output format: terminal, json")
+	tasteLintCmd.Flags().BoolVar(&tasteLintStrict, "strict", false, "fail on warnings too")
+	tasteCmd.AddCommand(tasteLintCmd)
+}
+
+// tasteLintReport is the json output of taste lint.
+type tasteLintReport struct {
+	Profile  string            `json:"profile"`
+	Errors   int               `json:"errors"`
+	Warnings int               `json:"warnings"`
+	Issues   []taste.LintIssue `json:"issues"`
+}
+
+func tasteLintAction(cmd *cobra.Command, args []string) error {
+	if tasteLintFormat != "terminal" && tasteLintFormat != "json" {
+		return fmt.Errorf("unknown format %q (want terminal or json)", tasteLintFormat)
+	}
+	var path string
+	if len(args) == 1 {
+		path = args[0]
+	} else {
+		cfg, err := config.Load(configDir)
+		if err != nil {
+			return fmt.Errorf("load config: %w", err)
+		}
+		path = profileTastePath(cfg)
+	}
+
+	rep := tasteLintReport{Profile: path, Issues: []taste.LintIssue{}}
+	// A profile that does not validate is one error; the checks need a
+	// valid one.
+	if profile, err := config.LoadTaste(path); err != nil {
+		rep.Issues = append(rep.Issues, taste.LintIssue{Severity: taste.LintError, Message: err.Error()})
+	} else {
+		rep.Issues = append(rep.Issues, taste.Lint(profile)...)
+	}
+	for _, is := range rep.Issues {
+		if is.Severity == taste.LintError {
+			rep.Errors++
+		} else {
+			rep.Warnings++
+		}
+	}
+
+	w := cmd.OutOrStdout()
+	if tasteLintFormat == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(rep); err != nil {
+			return err
+		}
+	} else {
+		printTasteLint(w, rep)
+	}
+
+	if rep.Errors > 0 || (tasteLintStrict && rep.Warnings > 0) {
+		return fmt.Errorf("taste lint: %d errors, %d warnings", rep.Errors, rep.Warnings)
+	}
+	return nil
+}
+
+func printTasteLint(w io.Writer, rep tasteLintReport) {
+	if len(rep.Issues) == 0 {
+		fmt.Fprintf(w, "%s: no issues\n", rep.Profile)
+		return
+	}
+	fmt.Fprintf(w, "%s: %d errors, %d warnings\n", rep.Profile, rep.Errors, rep.Warnings)
+	for _, is := range rep.Issues {
+		if is.Path == "" {
+			fmt.Fprintf(w, "  %-7s  %s\n", is.Severity, is.Message)
+			continue
+		}
+		fmt.Fprintf(w, "  %-7s  %s: %s\n", is.Severity, is.Path, is.Message)
+	}
+}
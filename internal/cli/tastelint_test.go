@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestTasteLintAction(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestConfig(t, tmpDir, filepath.Join(tmpDir, "noisepan.db"), filepath.Join(tmpDir, "forge-plan.sh"))
+	writeTestTaste(t, tmpDir)
+
+	oldConfigDir, oldFormat, oldStrict := configDir, tasteLintFormat, tasteLintStrict
+	t.Cleanup(func() { configDir, tasteLintFormat, tasteLintStrict = oldConfigDir, oldFormat, oldStrict })
+	configDir, tasteLintFormat, tasteLintStrict = tmpDir, "terminal", false
+
+	run := func(args ...string) (string, error) {
+		t.Helper()
+		cmd := &cobra.Command{}
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		err := tasteLintAction(cmd, args)
+		return buf.String(), err
+	}
+
+	// The test profile's rule adds a label labels does not define: a
+	// warning, which fails only with --strict.
+	out, err := run()
+	if err != nil {
+		t.Fatalf("lint: %v", err)
+	}
+	requireContains(t, out, "0 errors, 1 warnings")
+	requireContains(t, out, `warning  rules[0].then.labels: label "ops" is not defined under labels`)
+	tasteLintStrict = true
+	if _, err := run(); err == nil {
+		t.Error("expected --strict to fail on a warning")
+	}
+	tasteLintStrict = false
+
+	broken := filepath.Join(tmpDir, "broken.yaml")
+	if err := os.WriteFile(broken, []byte("thresholds:\n  read_now: 1\n  skim: 3\n"), 0o644); err != nil {
+		t.Fatalf("write broken profile: %v", err)
+	}
+	tasteLintFormat = "json"
+	out, err = run(broken)
+	if err == nil || !strings.Contains(err.Error(), "1 errors") {
+		t.Errorf("err = %v, want 1 error", err)
+	}
+	var rep tasteLintReport
+	if err := json.Unmarshal([]byte(out), &rep); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, out)
+	}
+	if rep.Profile != broken || rep.Errors != 1 || len(rep.Issues) != 1 || !strings.Contains(rep.Issues[0].Message, "read_now") {
+		t.Errorf("report = %+v", rep)
+	}
+}
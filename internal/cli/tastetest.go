@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/source"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/taste"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tasteTestText    string
+	tasteTestInput   string
+	tasteTestPostID  int64
+	tasteTestFile    string
+	tasteTestSource  string
+	tasteTestChannel string
+	tasteTestAuthor  string
+)
+
+var tasteTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Score a text against the taste profile without touching the store",
+	Long: `Scores one text and prints the full breakdown: keywords, rules, labels,
+channel and author modifiers, and the classifier when it is enabled. The
+text comes from --text, --input (a file, "-" for stdin), --post-id (a
+stored post, read only), or stdin when none is given.
+
+--file scores against a candidate taste profile instead of the active one,
+so rules can be iterated on before they are saved. Nothing is written:
+rule cooldowns and stored scores are left alone.`,
+	Args: cobra.NoArgs,
+	RunE: tasteTestAction,
+}
+
+func init() {
+	tasteTestCmd.Flags().StringVar(&tasteTestText, "text", "", "text to score")
+	tasteTestCmd.Flags().StringVar(&tasteTestInput, "input", "", `file with the text to score ("-" for stdin)`)
+	tasteTestCmd.Flags().Int64Var(&tasteTestPostID, "post-id", 0, "score a stored post")
+	tasteTestCmd.Flags().StringVar(&tasteTestFile, "file", "", "candidate taste profile to score against (default: the active profile)")
+	tasteTestCmd.Flags().StringVar(&tasteTestSource, "source", "", "source the text is scored as, for per-source modifiers")
+	tasteTestCmd.Flags().StringVar(&tasteTestChannel, "channel", "", "channel the text is scored as, for per-channel modifiers")
+	tasteTestCmd.Flags().StringVar(&tasteTestAuthor, "author", "", "author the text is scored as, for per-author modifiers")
+	tasteCmd.AddCommand(tasteTestCmd)
+}
+
+func tasteTestAction(cmd *cobra.Command, _ []string) error {
+	given := 0
+	for _, set := range []bool{tasteTestText != "", tasteTestInput != "", tasteTestPostID != 0} {
+		if set {
+			given++
+		}
+	}
+	if given > 1 {
+		return errors.New("--text, --input, and --post-id are mutually exclusive")
+	}
+
+	// config.yaml is only needed to find the active profile or the store.
+	var cfg *config.Config
+	if tasteTestFile == "" || tasteTestPostID != 0 {
+		var err error
+		if cfg, err = config.Load(configDir); err != nil {
+			return fmt.Errorf("load config: %w", err)
+		}
+	}
+	tastePath := tasteTestFile
+	if tastePath == "" {
+		tastePath = profileTastePath(cfg)
+	}
+	profile, err := loadTaste(cfg, tastePath)
+	if err != nil {
+		return fmt.Errorf("load taste: %w", err)
+	}
+
+	post, err := tasteTestPost(cmd, cfg)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(post.Text) == "" {
+		return errors.New("no text to score (use --text, --input, --post-id, or stdin)")
+	}
+
+	sp := taste.Score(post, profile)
+	if profile.Classifier.Enabled {
+		c, err := taste.LoadClassifier(filepath.Join(configDir, config.DefaultClassifierFile))
+		if err != nil {
+			return fmt.Errorf("classifier enabled but unavailable (run 'noisepan taste train'): %w", err)
+		}
+		sp = c.Apply(sp, profile.Classifier.MaxPoints, profile.TierSet())
+	}
+	printTasteTest(cmd.OutOrStdout(), tastePath, post, sp)
+	return nil
+}
+
+// tasteTestPost builds the post taste test scores from its flags.
+func tasteTestPost(cmd *cobra.Command, cfg *config.Config) (source.Post, error) {
+	if tasteTestPostID != 0 {
+		db, err := openStore(cfg)
+		if err != nil {
+			return source.Post{}, fmt.Errorf("open store: %w", err)
+		}
+		defer func() { _ = db.Close() }()
+		found, err := db.GetPostByID(cmd.Context(), tasteTestPostID)
+		if errors.Is(err, store.ErrPostNotFound) {
+			return source.Post{}, fmt.Errorf("post %d not found", tasteTestPostID)
+		}
+		if err != nil {
+			return source.Post{}, fmt.Errorf("get post: %w", err)
+		}
+		return storePostToSourcePost(found.Post), nil
+	}
+
+	post := source.Post{Source: tasteTestSource, Channel: tasteTestChannel, Author: tasteTestAuthor}
+	switch {
+	case tasteTestText != "":
+		post.Text = tasteTestText
+	case tasteTestInput != "" && tasteTestInput != "-":
+		data, err := os.ReadFile(tasteTestInput)
+		if err != nil {
+			return source.Post{}, fmt.Errorf("read input: %w", err)
+		}
+		post.Text = string(data)
+	default:
+		data, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return source.Post{}, fmt.Errorf("read stdin: %w", err)
+		}
+		post.Text = string(data)
+	}
+	return post, nil
+}
+
+func printTasteTest(w io.Writer, tastePath string, post source.Post, sp taste.ScoredPost) {
+	fmt.Fprintf(w, "Profile: %s\n", tastePath)
+	fmt.Fprintf(w, "Post:    %s\n", headline(post.Text))
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Score: %d  Tier: %s\n", sp.Score, sp.Tier)
+	if len(sp.Labels) > 0 {
+		fmt.Fprintf(w, "Labels: %v\n", sp.Labels)
+	}
+	fmt.Fprintln(w)
+	if len(sp.Explanation) == 0 {
+		fmt.Fprintln(w, "Breakdown: nothing in the profile matched")
+		return
+	}
+	fmt.Fprintln(w, "Breakdown:")
+	for _, c := range sp.Explanation {
+		fmt.Fprintf(w, "  %+d  %s\n", c.Points, c.Reason)
+	}
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func TestTasteTestAction(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "noisepan.db")
+	writeTestConfig(t, tmpDir, dbPath, filepath.Join(tmpDir, "forge-plan.sh"))
+	writeTestTaste(t, tmpDir)
+
+	oldConfigDir := configDir
+	oldText, oldInput, oldPostID, oldFile := tasteTestText, tasteTestInput, tasteTestPostID, tasteTestFile
+	t.Cleanup(func() {
+		configDir = oldConfigDir
+		tasteTestText, tasteTestInput, tasteTestPostID, tasteTestFile = oldText, oldInput, oldPostID, oldFile
+	})
+	configDir = tmpDir
+
+	run := func(stdin string) (string, error) {
+		t.Helper()
+		cmd := &cobra.Command{}
+		cmd.SetContext(context.Background())
+		cmd.SetIn(strings.NewReader(stdin))
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		err := tasteTestAction(cmd, nil)
+		return buf.String(), err
+	}
+
+	tasteTestText = "CVE-2026-1234 kubernetes breaking change"
+	out, err := run("")
+	if err != nil {
+		t.Fatalf("taste test: %v", err)
+	}
+	requireContains(t, out, "Score: 10  Tier: read_now")
+	requireContains(t, out, "Labels: [ops]")
+	requireContains(t, out, "+5  keyword: cve")
+
+	// A candidate profile is scored instead of taste.yaml; stdin is read
+	// when no text is given.
+	candidate := filepath.Join(tmpDir, "candidate.yaml")
+	if err := os.WriteFile(candidate, []byte("weights:\n  low_signal:\n    \"cve\": -1\nthresholds:\n  read_now: 7\n  skim: 3\n  ignore: 0\n"), 0o644); err != nil {
+		t.Fatalf("write candidate: %v", err)
+	}
+	tasteTestText, tasteTestFile = "", candidate
+	if out, err = run("CVE-2026-1234 again"); err != nil {
+		t.Fatalf("taste test --file: %v", err)
+	}
+	requireContains(t, out, "Profile: "+candidate)
+	requireContains(t, out, "Score: -1  Tier: ignore")
+
+	// A stored post is scored without saving a score.
+	st, err := store.Open(dbPath)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	now := time.Now()
+	p, err := st.InsertPost(context.Background(), store.PostInput{
+		Source: "rss", Channel: "k8s", ExternalID: "1", Text: "Join our webinar", PostedAt: now, FetchedAt: now,
+	})
+	_ = st.Close()
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	tasteTestFile, tasteTestPostID = "", p.ID
+	if out, err = run(""); err != nil {
+		t.Fatalf("taste test --post-id: %v", err)
+	}
+	requireContains(t, out, "Score: -4  Tier: ignore")
+	st = openStoreForPipelineTest(t, dbPath)
+	if found, err := st.GetPostByID(context.Background(), p.ID); err != nil || found.Score != nil {
+		t.Errorf("post %d after taste test = %+v, %v; want no score", p.ID, found.Score, err)
+	}
+
+	tasteTestPostID = 0
+	if _, err := run("  "); err == nil || !strings.Contains(err.Error(), "no text") {
+		t.Errorf("empty input err = %v, want no text error", err)
+	}
+	tasteTestText, tasteTestPostID = "x", 1
+	if _, err := run(""); err == nil || !strings.Contains(err.Error(), "mutually exclusive") {
+		t.Errorf("text and post-id err = %v, want mutually exclusive", err)
+	}
+}
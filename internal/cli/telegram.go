@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/digest"
+	"github.com/ppiankov/noisepan/internal/network"
+)
+
+// newTelegramSender returns the sender for "digest --telegram", or nil
+// without it.
+func newTelegramSender(cfg *config.Config, enabled bool) (*digest.TelegramSender, error) {
+	if !enabled {
+		return nil, nil
+	}
+	tc := cfg.Delivery.Telegram
+	if tc.BotToken == "" || tc.ChatID == "" {
+		return nil, errors.New("--telegram: delivery.telegram needs a bot token and chat_id")
+	}
+	transport, err := network.NewTransport(cfg.Network)
+	if err != nil {
+		return nil, fmt.Errorf("build http transport: %w", err)
+	}
+	s := digest.NewTelegram(tc.BotToken, tc.ChatID)
+	s.SetTransport(transport)
+	return s, nil
+}
+
+// telegramDigest posts input to the chat under the digest's title.
+func telegramDigest(ctx context.Context, sender *digest.TelegramSender, input digest.DigestInput, now time.Time) error {
+	if err := sender.Send(ctx, digestTitle(now), input); err != nil {
+		return err
+	}
+	slog.Info("posted digest to telegram")
+	return nil
+}
@@ -0,0 +1,24 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ppiankov/noisepan/internal/config"
+)
+
+func TestNewTelegramSender(t *testing.T) {
+	if s, err := newTelegramSender(&config.Config{}, false); s != nil || err != nil {
+		t.Errorf("without --telegram = %v, %v; want nil, nil", s, err)
+	}
+	// bot_token_env pointing at an unset variable leaves the token empty.
+	cfg := &config.Config{Delivery: config.DeliveryConfig{Telegram: config.TelegramDeliveryConfig{BotTokenEnv: "UNSET", ChatID: "@me"}}}
+	if _, err := newTelegramSender(cfg, true); err == nil || !strings.Contains(err.Error(), "delivery.telegram") {
+		t.Errorf("err = %v, want delivery.telegram error", err)
+	}
+
+	cfg.Delivery.Telegram.BotToken = "123:abc"
+	if s, err := newTelegramSender(cfg, true); s == nil || err != nil {
+		t.Errorf("configured = %v, %v; want a sender", s, err)
+	}
+}
@@ -0,0 +1,265 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/summarize"
+	"github.com/ppiankov/noisepan/internal/taste"
+	"github.com/spf13/cobra"
+)
+
+var (
+	triageSince   string
+	triageSource  string
+	triageChannel string
+)
+
+var triageCmd = &cobra.Command{
+	Use:   "triage",
+	Short: "Walk through unread read_now posts one at a time",
+	Long: `Shows each unread read_now post in the digest window with its summary and
+asks what to do with it:
+
+  o  open    open the link in the browser and mark the post read
+  s  star    add the post to the reading queue and mark it read
+  d  done    mark the post read
+  m  mute    vote the post down as noise and mark it read
+  k  skip    leave the post unread for next time
+  q  quit    stop; remaining posts stay unread
+
+Posts are shown highest score first. Unscored posts are scored first, as in
+"digest".`,
+	Args: cobra.NoArgs,
+	RunE: triageAction,
+}
+
+func init() {
+	triageCmd.Flags().StringVar(&triageSince, "since", "", "time window (e.g. 48h)")
+	triageCmd.Flags().StringVar(&triageSource, "source", "", "filter by source (e.g. rss, telegram, reddit)")
+	triageCmd.Flags().StringVar(&triageChannel, "channel", "", "filter by channel name")
+	rootCmd.AddCommand(triageCmd)
+}
+
+// openBrowser opens rawURL in the desktop's default browser. URLs come from
+// feeds, so anything but http and https is refused rather than handed to
+// the desktop's handler for file:, smb: or custom schemes.
+func openBrowser(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("parse url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("refusing to open %q: not an http or https URL", rawURL)
+	}
+	return startBrowser(u.String())
+}
+
+// startBrowser launches the default browser on url. Tests replace it.
+var startBrowser = func(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
+
+func triageAction(cmd *cobra.Command, _ []string) error {
+	cfg, err := config.Load(configDir)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	profile, err := config.LoadTaste(profileTastePath(cfg))
+	if err != nil {
+		return fmt.Errorf("load taste: %w", err)
+	}
+
+	db, err := openStore(cfg)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	sinceDur := cfg.Digest.Since.Duration
+	if triageSince != "" {
+		sinceDur, err = time.ParseDuration(triageSince)
+		if err != nil {
+			return fmt.Errorf("parse --since: %w", err)
+		}
+	}
+
+	ctx := cmd.Context()
+	posts, err := db.GetPosts(ctx, time.Now().Add(-sinceDur), "", store.PostFilter{
+		Source: triageSource, Channel: triageChannel, UnreadOnly: true,
+	})
+	if err != nil {
+		return fmt.Errorf("get posts: %w", err)
+	}
+
+	scorer, err := newPostScorer(cfg, profile)
+	if err != nil {
+		return err
+	}
+	if err := scorer.loadBoilerplate(ctx, db); err != nil {
+		return fmt.Errorf("load boilerplate: %w", err)
+	}
+	if err := scoreUnscored(ctx, db, scorer, posts, time.Now()); err != nil {
+		return err
+	}
+
+	var queue []store.PostWithScore
+	for _, p := range posts {
+		if p.Score.Tier == taste.TierReadNow {
+			queue = append(queue, p)
+		}
+	}
+	sort.SliceStable(queue, func(i, j int) bool {
+		return queue[i].Score.Score > queue[j].Score.Score
+	})
+
+	heuristic := &summarize.HeuristicSummarizer{Products: cfg.Summarize.Products}
+	llm, err := newLLMSummarizer(ctx, cfg, db, heuristic)
+	if err != nil {
+		return err
+	}
+	labels := make(map[int64][]string, len(queue))
+	for _, p := range queue {
+		labels[p.Post.ID] = p.Score.Labels
+	}
+
+	out := cmd.OutOrStdout()
+	if len(queue) == 0 {
+		fmt.Fprintln(out, "Nothing to triage: no unread read_now posts.")
+		return nil
+	}
+	counts, err := triagePosts(ctx, db, cmd.InOrStdin(), out, queue, func(p store.Post) summarize.Summary {
+		text := p.Text
+		if text == "" {
+			text = p.Snippet
+		}
+		text = scorer.stripBoilerplate(p.Source, p.Channel, text)
+		return summarizeScored(llm, heuristic, cfg.Summarize.LLM.Prompt, taste.TierReadNow, labels[p.ID], text)
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "\n%s\n", counts)
+	return nil
+}
+
+// triageCounts tallies what triagePosts did with each post.
+type triageCounts struct {
+	opened, starred, done, muted, skipped, left int
+}
+
+func (c triageCounts) String() string {
+	s := fmt.Sprintf("opened %d, starred %d, done %d, muted %d, skipped %d",
+		c.opened, c.starred, c.done, c.muted, c.skipped)
+	if c.left > 0 {
+		s += fmt.Sprintf(", %d left", c.left)
+	}
+	return s
+}
+
+// triagePosts shows posts one by one on out and applies the action read
+// from in to each. Input ending early is treated as quit.
+func triagePosts(ctx context.Context, db *store.Store, in io.Reader, out io.Writer, posts []store.PostWithScore, summarizePost func(store.Post) summarize.Summary) (triageCounts, error) {
+	var counts triageCounts
+	scanner := bufio.NewScanner(in)
+
+	for i, pws := range posts {
+		p := pws.Post
+		fmt.Fprintf(out, "\n[%d/%d] #%d  %s/%s  score %d\n", i+1, len(posts), p.ID, p.Source, p.Channel, pws.Score.Score)
+		for _, b := range summarizePost(p).Bullets {
+			fmt.Fprintf(out, "  - %s\n", b)
+		}
+		if p.URL != "" {
+			fmt.Fprintf(out, "  %s\n", p.URL)
+		}
+
+		action, ok := promptTriage(scanner, out)
+		if !ok {
+			counts.left = len(posts) - i
+			return counts, nil
+		}
+
+		now := time.Now()
+		switch action {
+		case "open":
+			if p.URL == "" {
+				fmt.Fprintln(out, "  no link to open")
+			} else if err := openBrowser(p.URL); err != nil {
+				fmt.Fprintf(out, "  open failed: %v\n", err)
+			}
+			counts.opened++
+		case "star":
+			if err := db.Star(ctx, p.ID, now); err != nil {
+				return counts, err
+			}
+			counts.starred++
+		case "done":
+			counts.done++
+		case "mute":
+			if err := db.SaveFeedback(ctx, store.Feedback{
+				PostID: p.ID, Vote: store.FeedbackDown, Reason: "muted in triage", CreatedAt: now,
+			}); err != nil {
+				return counts, err
+			}
+			counts.muted++
+		case "skip":
+			counts.skipped++
+			continue
+		}
+		if err := db.MarkRead(ctx, now, p.ID); err != nil {
+			return counts, err
+		}
+	}
+	return counts, nil
+}
+
+// triageKeys maps prompt answers to actions.
+var triageKeys = map[string]string{
+	"o": "open", "open": "open",
+	"s": "star", "star": "star",
+	"d": "done", "done": "done",
+	"m": "mute", "mute": "mute",
+	"k": "skip", "skip": "skip",
+	"q": "quit", "quit": "quit",
+}
+
+// promptTriage asks for an action until it gets a valid one. ok is false on
+// quit or end of input.
+func promptTriage(scanner *bufio.Scanner, out io.Writer) (action string, ok bool) {
+	for {
+		fmt.Fprint(out, "[o]pen [s]tar [d]one [m]ute s[k]ip [q]uit > ")
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return "", false
+		}
+		action, valid := triageKeys[strings.ToLower(strings.TrimSpace(scanner.Text()))]
+		switch {
+		case !valid:
+			fmt.Fprintln(out, "  unknown choice")
+		case action == "quit":
+			return "", false
+		default:
+			return action, true
+		}
+	}
+}
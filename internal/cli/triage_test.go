@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/summarize"
+	"github.com/ppiankov/noisepan/internal/taste"
+)
+
+func TestTriagePosts(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "noisepan.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+	ctx := context.Background()
+
+	now := time.Now()
+	var queue []store.PostWithScore
+	for i := 1; i <= 5; i++ {
+		p, err := st.InsertPost(ctx, store.PostInput{
+			Source: "rss", Channel: "security", ExternalID: fmt.Sprint(i),
+			Text: fmt.Sprintf("CVE-2026-%d patched", i), URL: fmt.Sprintf("https://example.com/%d", i),
+			PostedAt: now, FetchedAt: now,
+		})
+		if err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+		sc := store.Score{PostID: p.ID, Score: 9, Tier: taste.TierReadNow, ScoredAt: now}
+		if err := st.SaveScore(ctx, sc); err != nil {
+			t.Fatalf("save score: %v", err)
+		}
+		queue = append(queue, store.PostWithScore{Post: p, Score: &sc})
+	}
+
+	var opened []string
+	oldOpen := startBrowser
+	t.Cleanup(func() { startBrowser = oldOpen })
+	startBrowser = func(url string) error {
+		opened = append(opened, url)
+		return nil
+	}
+
+	// open, star, an invalid answer then done, skip, then input ends.
+	in := strings.NewReader("o\ns\nwhat\nd\nk\n")
+	var out bytes.Buffer
+	counts, err := triagePosts(ctx, st, in, &out, queue, func(p store.Post) summarize.Summary {
+		return summarize.Summary{Bullets: []string{p.Text}}
+	})
+	if err != nil {
+		t.Fatalf("triage: %v", err)
+	}
+
+	want := triageCounts{opened: 1, starred: 1, done: 1, skipped: 1, left: 1}
+	if counts != want {
+		t.Errorf("counts = %+v, want %+v", counts, want)
+	}
+	requireContains(t, out.String(), "[1/5] #1  rss/security  score 9")
+	requireContains(t, out.String(), "  - CVE-2026-1 patched")
+	requireContains(t, out.String(), "unknown choice")
+	if len(opened) != 1 || opened[0] != "https://example.com/1" {
+		t.Errorf("opened = %v", opened)
+	}
+
+	unread, err := st.GetPosts(ctx, time.Time{}, "", store.PostFilter{UnreadOnly: true, Order: store.OrderIngested})
+	if err != nil {
+		t.Fatalf("get unread: %v", err)
+	}
+	var ids []int64
+	for _, p := range unread {
+		ids = append(ids, p.Post.ID)
+	}
+	if fmt.Sprint(ids) != "[4 5]" {
+		t.Errorf("unread = %v, want [4 5]", ids)
+	}
+
+	starred, err := st.GetStarred(ctx)
+	if err != nil {
+		t.Fatalf("get starred: %v", err)
+	}
+	if len(starred) != 1 || starred[0].Post.ID != 2 {
+		t.Errorf("starred = %+v, want #2", starred)
+	}
+
+	// Mute records a down vote.
+	counts, err = triagePosts(ctx, st, strings.NewReader("m\n"), &out, queue[3:4], func(store.Post) summarize.Summary {
+		return summarize.Summary{}
+	})
+	if err != nil {
+		t.Fatalf("triage mute: %v", err)
+	}
+	if counts.muted != 1 {
+		t.Errorf("muted = %d, want 1", counts.muted)
+	}
+	votes, err := st.GetFeedback(ctx, time.Time{})
+	if err != nil {
+		t.Fatalf("get feedback: %v", err)
+	}
+	if len(votes) != 1 || votes[0].PostID != 4 || votes[0].Vote != store.FeedbackDown {
+		t.Errorf("feedback = %+v, want down vote on #4", votes)
+	}
+}
+
+func TestOpenBrowser_SchemeCheck(t *testing.T) {
+	var opened []string
+	oldOpen := startBrowser
+	t.Cleanup(func() { startBrowser = oldOpen })
+	startBrowser = func(url string) error {
+		opened = append(opened, url)
+		return nil
+	}
+
+	for _, u := range []string{"file:///etc/passwd", `smb://host/share`, "slack://open", "javascript:alert(1)", "/relative"} {
+		if err := openBrowser(u); err == nil {
+			t.Errorf("openBrowser(%q): want error", u)
+		}
+	}
+	for _, u := range []string{"https://example.com/a", "http://example.com/b"} {
+		if err := openBrowser(u); err != nil {
+			t.Errorf("openBrowser(%q): %v", u, err)
+		}
+	}
+	if !slices.Equal(opened, []string{"https://example.com/a", "http://example.com/b"}) {
+		t.Errorf("opened = %q", opened)
+	}
+}
@@ -0,0 +1,213 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/summarize"
+	"github.com/ppiankov/noisepan/internal/taste"
+	"github.com/ppiankov/noisepan/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tuiSince   string
+	tuiSource  string
+	tuiChannel string
+	tuiUnread  bool
+	tuiMinTier string
+)
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Browse scored posts interactively",
+	Long: `Opens a full-screen reader over the posts in the digest window, grouped by
+tier. Move with j/k, expand a summary with enter, open the link with o
+(marks the post read), mark read with r, star with s, and vote with + or -.
+Changes are saved as they are made.`,
+	Args: cobra.NoArgs,
+	RunE: tuiAction,
+}
+
+func init() {
+	tuiCmd.Flags().StringVar(&tuiSince, "since", "", "time window (e.g. 48h)")
+	tuiCmd.Flags().StringVar(&tuiSource, "source", "", "filter by source (e.g. rss, telegram, reddit)")
+	tuiCmd.Flags().StringVar(&tuiChannel, "channel", "", "filter by channel name")
+	tuiCmd.Flags().BoolVar(&tuiUnread, "unread-only", false, "skip posts already marked read")
+	tuiCmd.Flags().StringVar(&tuiMinTier, "min-tier", "", "lowest tier to show, e.g. read_now, skim, ignore (default: every tier above ignore)")
+	tuiCmd.Flags().BoolVar(&noColor, "no-color", false, "disable ANSI colors")
+	rootCmd.AddCommand(tuiCmd)
+}
+
+func tuiAction(cmd *cobra.Command, _ []string) error {
+	cfg, err := config.Load(configDir)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	profile, err := config.LoadTaste(profileTastePath(cfg))
+	if err != nil {
+		return fmt.Errorf("load taste: %w", err)
+	}
+	tiers := taste.TierNames(profile)
+	minRank, err := minTierRank(tuiMinTier, tiers)
+	if err != nil {
+		return err
+	}
+
+	db, err := openStore(cfg)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	sinceDur := cfg.Digest.Since.Duration
+	if tuiSince != "" {
+		sinceDur, err = time.ParseDuration(tuiSince)
+		if err != nil {
+			return fmt.Errorf("parse --since: %w", err)
+		}
+	}
+	sinceTime := time.Now().Add(-sinceDur)
+
+	ctx := cmd.Context()
+	filter := store.PostFilter{Source: tuiSource, Channel: tuiChannel, UnreadOnly: tuiUnread}
+	posts, err := db.GetPosts(ctx, sinceTime, "", filter)
+	if err != nil {
+		return fmt.Errorf("get posts: %w", err)
+	}
+
+	scorer, err := newPostScorer(cfg, profile)
+	if err != nil {
+		return err
+	}
+	if err := scorer.loadBoilerplate(ctx, db); err != nil {
+		return fmt.Errorf("load boilerplate: %w", err)
+	}
+	if err := scoreUnscored(ctx, db, scorer, posts, time.Now()); err != nil {
+		return err
+	}
+
+	var shown []store.PostWithScore
+	for _, p := range posts {
+		if rank, _ := tierRank(p.Score.Tier, tiers); rank >= minRank {
+			shown = append(shown, p)
+		}
+	}
+
+	heuristic := &summarize.HeuristicSummarizer{Products: cfg.Summarize.Products}
+	llm, err := newLLMSummarizer(ctx, cfg, db, heuristic)
+	if err != nil {
+		return err
+	}
+	items, err := buildTUIItems(ctx, db, shown, sinceTime, filter, func(p store.PostWithScore) summarize.Summary {
+		text := p.Post.Text
+		if text == "" {
+			text = p.Post.Snippet
+		}
+		text = scorer.stripBoilerplate(p.Post.Source, p.Post.Channel, text)
+		return summarizeScored(llm, heuristic, cfg.Summarize.LLM.Prompt, p.Score.Tier, p.Score.Labels, text)
+	})
+	if err != nil {
+		return err
+	}
+
+	model := tui.New(items, &storeActions{ctx: ctx, db: db}, !noColor)
+	model.SetTiers(tiers)
+	_, err = tea.NewProgram(model, tea.WithAltScreen(), tea.WithContext(ctx),
+		tea.WithInput(cmd.InOrStdin()), tea.WithOutput(cmd.OutOrStdout())).Run()
+	return err
+}
+
+// buildTUIItems converts posts to reader items with their read, starred, and
+// vote state.
+func buildTUIItems(ctx context.Context, db *store.Store, posts []store.PostWithScore, since time.Time, filter store.PostFilter, summarizePost func(store.PostWithScore) summarize.Summary) ([]tui.Item, error) {
+	filter.UnreadOnly = true
+	unread, err := db.GetPosts(ctx, since, "", filter)
+	if err != nil {
+		return nil, fmt.Errorf("get unread posts: %w", err)
+	}
+	isUnread := make(map[int64]bool, len(unread))
+	for _, p := range unread {
+		isUnread[p.Post.ID] = true
+	}
+
+	isStarred, vote, err := postMarks(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]tui.Item, 0, len(posts))
+	for _, p := range posts {
+		items = append(items, tui.Item{
+			ID:      p.Post.ID,
+			Source:  p.Post.Source,
+			Channel: p.Post.Channel,
+			Title:   searchSnippet(p.Post),
+			URL:     p.Post.URL,
+			Tier:    p.Score.Tier,
+			Score:   p.Score.Score,
+			Summary: summarizePost(p).Bullets,
+			Read:    !isUnread[p.Post.ID],
+			Starred: isStarred[p.Post.ID],
+			Vote:    vote[p.Post.ID],
+		})
+	}
+	return items, nil
+}
+
+// postMarks returns the IDs of starred posts and the vote on each voted post.
+func postMarks(ctx context.Context, db *store.Store) (map[int64]bool, map[int64]int, error) {
+	starred, err := db.GetStarred(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get starred: %w", err)
+	}
+	isStarred := make(map[int64]bool, len(starred))
+	for _, p := range starred {
+		isStarred[p.Post.ID] = true
+	}
+
+	votes, err := db.GetFeedback(ctx, time.Time{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("get feedback: %w", err)
+	}
+	vote := make(map[int64]int, len(votes))
+	for _, fb := range votes {
+		vote[fb.PostID] = fb.Vote
+	}
+	return isStarred, vote, nil
+}
+
+// storeActions saves reader actions to the store.
+type storeActions struct {
+	ctx context.Context
+	db  *store.Store
+}
+
+func (a *storeActions) MarkRead(id int64) error {
+	return a.db.MarkRead(a.ctx, time.Now(), id)
+}
+
+func (a *storeActions) SetStarred(id int64, starred bool) error {
+	if starred {
+		return a.db.Star(a.ctx, id, time.Now())
+	}
+	_, err := a.db.Unstar(a.ctx, id)
+	return err
+}
+
+func (a *storeActions) Vote(id int64, up bool) error {
+	vote := store.FeedbackDown
+	if up {
+		vote = store.FeedbackUp
+	}
+	return a.db.SaveFeedback(a.ctx, store.Feedback{PostID: id, Vote: vote, CreatedAt: time.Now()})
+}
+
+func (a *storeActions) Open(url string) error {
+	return openBrowser(url)
+}
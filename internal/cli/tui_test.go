@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/store"
+	"github.com/ppiankov/noisepan/internal/summarize"
+	"github.com/ppiankov/noisepan/internal/taste"
+)
+
+func TestBuildTUIItemsAndStoreActions(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "noisepan.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+	ctx := context.Background()
+
+	now := time.Now()
+	var posts []store.PostWithScore
+	for i := 1; i <= 3; i++ {
+		p, err := st.InsertPost(ctx, store.PostInput{
+			Source: "rss", Channel: "security", ExternalID: fmt.Sprint(i),
+			Text: fmt.Sprintf("CVE-2026-%d patched", i), URL: fmt.Sprintf("https://example.com/%d", i),
+			PostedAt: now, FetchedAt: now,
+		})
+		if err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+		sc := store.Score{PostID: p.ID, Score: 10 - i, Tier: taste.TierReadNow, ScoredAt: now}
+		if err := st.SaveScore(ctx, sc); err != nil {
+			t.Fatalf("save score: %v", err)
+		}
+		posts = append(posts, store.PostWithScore{Post: p, Score: &sc})
+	}
+
+	var opened []string
+	oldOpen := startBrowser
+	t.Cleanup(func() { startBrowser = oldOpen })
+	startBrowser = func(url string) error {
+		opened = append(opened, url)
+		return nil
+	}
+
+	actions := &storeActions{ctx: ctx, db: st}
+	if err := actions.MarkRead(1); err != nil {
+		t.Fatalf("mark read: %v", err)
+	}
+	if err := actions.SetStarred(2, true); err != nil {
+		t.Fatalf("star: %v", err)
+	}
+	if err := actions.Vote(2, true); err != nil {
+		t.Fatalf("vote up: %v", err)
+	}
+	if err := actions.Vote(3, false); err != nil {
+		t.Fatalf("vote down: %v", err)
+	}
+	if err := actions.Open("https://example.com/1"); err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if len(opened) != 1 {
+		t.Errorf("opened = %v", opened)
+	}
+
+	items, err := buildTUIItems(ctx, st, posts, now.Add(-time.Hour), store.PostFilter{}, func(p store.PostWithScore) summarize.Summary {
+		return summarize.Summary{Bullets: []string{p.Post.Text}}
+	})
+	if err != nil {
+		t.Fatalf("build items: %v", err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("items = %d, want 3", len(items))
+	}
+	if !items[0].Read || items[1].Read || items[2].Read {
+		t.Errorf("read = %v %v %v, want only #1", items[0].Read, items[1].Read, items[2].Read)
+	}
+	if items[0].Starred || !items[1].Starred {
+		t.Errorf("starred = %v %v, want only #2", items[0].Starred, items[1].Starred)
+	}
+	if items[0].Vote != 0 || items[1].Vote != store.FeedbackUp || items[2].Vote != store.FeedbackDown {
+		t.Errorf("votes = %d %d %d", items[0].Vote, items[1].Vote, items[2].Vote)
+	}
+	if items[1].Score != 8 || items[1].URL != "https://example.com/2" || items[1].Summary[0] != "CVE-2026-2 patched" {
+		t.Errorf("item = %+v", items[1])
+	}
+
+	if err := actions.SetStarred(2, false); err != nil {
+		t.Fatalf("unstar: %v", err)
+	}
+	starred, err := st.GetStarred(ctx)
+	if err != nil {
+		t.Fatalf("get starred: %v", err)
+	}
+	if len(starred) != 0 {
+		t.Errorf("starred = %d after unstar, want 0", len(starred))
+	}
+}
@@ -21,7 +21,8 @@ var verifyCmd = &cobra.Command{
 	Use:   "verify",
 	Short: "Verify read_now posts with Entropia",
 	Long: `Runs entropia scan on URLs from read_now posts to display support index 
-and verification details. Requires 'entropia' binary in PATH.`,
+and verification details. Results are kept, and later digests show them on
+the post. Requires 'entropia' binary in PATH.`,
 	RunE: verifyAction,
 }
 
@@ -51,7 +52,7 @@ func verifyAction(cmd *cobra.Command, _ []string) error {
 		return fmt.Errorf("load config: %w", err)
 	}
 
-	db, err := store.Open(cfg.Storage.Path)
+	db, err := openStore(cfg)
 	if err != nil {
 		return fmt.Errorf("open store: %w", err)
 	}
@@ -107,6 +108,17 @@ func verifyAction(cmd *cobra.Command, _ []string) error {
 
 		printEntropiaResult(result)
 		fmt.Println()
+
+		if err := db.SaveVerification(ctx, store.Verification{
+			PostID:     item.Post.ID,
+			Support:    result.Score.Index,
+			Confidence: result.Score.Confidence,
+			Conflict:   result.Score.Conflict,
+			Signals:    result.Score.Signals,
+			CheckedAt:  time.Now(),
+		}); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -3,8 +3,13 @@ package config
 import (
 	"errors"
 	"fmt"
+	"maps"
+	"net/mail"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -12,15 +17,61 @@ import (
 )
 
 const (
-	DefaultConfigFile    = "config.yaml"
-	DefaultTasteFile     = "taste.yaml"
-	DefaultStoragePath   = ".noisepan/noisepan.db"
-	DefaultRetainDays    = 30
-	DefaultTopN          = 7
-	DefaultIncludeSkims  = 5
-	DefaultSince         = 24 * time.Hour
-	DefaultTimezone      = "UTC"
-	DefaultSummarizeMode = "heuristic"
+	DefaultConfigFile     = "config.yaml"
+	DefaultTasteFile      = "taste.yaml"
+	DefaultClassifierFile = "classifier.json"
+	DefaultProfilesDir    = "profiles"
+	DefaultStorageDriver  = "sqlite"
+	DefaultStoragePath    = ".noisepan/noisepan.db"
+	DefaultRetainDays     = 30
+	DefaultTopN           = 7
+	DefaultIncludeSkims   = 5
+	DefaultSince          = 24 * time.Hour
+	DefaultTimezone       = "UTC"
+	DefaultSummarizeMode  = "heuristic"
+	DefaultLLMProvider    = "openai"
+	DefaultLLMTimeout     = 30 * time.Second
+	DefaultHealthMaxAge   = 2 * time.Hour
+	DefaultDedupKeep      = "earliest"
+
+	DefaultRecurringMode   = "label"
+	DefaultRecurringMinGap = 20 * time.Hour
+
+	DefaultClockSkewTolerance = 15 * time.Minute
+	DefaultClockSkewMode      = "clamp"
+
+	DefaultClassifierMaxPoints = 3
+
+	DefaultInterestPoints        = 3
+	DefaultInterestMinSimilarity = 0.5
+
+	DefaultBoilerplateMinShare = 0.6
+	DefaultBoilerplateMinPosts = 5
+	DefaultBoilerplateSample   = 50
+
+	DefaultTriageInterval  = 1 * time.Second
+	DefaultTriageMaxPerRun = 50
+
+	DefaultHookTimeout = 30 * time.Second
+
+	DefaultCacheFile = "cache.db"
+
+	DefaultSMTPPort = 587
+
+	DefaultSnippetLength = 200
+
+	DefaultEmbedBatchSize = 64
+	// The openai provider's defaults, and the local provider's: Ollama's
+	// OpenAI-compatible endpoint and a small open model.
+	DefaultOpenAIEmbedEndpoint = "https://api.openai.com/v1/embeddings"
+	DefaultOpenAIEmbedModel    = "text-embedding-3-small"
+	DefaultLocalEmbedEndpoint  = "http://localhost:11434/v1/embeddings"
+	DefaultLocalEmbedModel     = "nomic-embed-text"
+
+	// The ollama provider's model, and its timeout: local inference on a
+	// laptop can take minutes for a long post.
+	DefaultOllamaLLMModel   = "llama3.2"
+	DefaultOllamaLLMTimeout = 5 * time.Minute
 )
 
 // Duration wraps time.Duration for YAML unmarshaling from strings like "24h".
@@ -41,12 +92,168 @@ func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
 	return nil
 }
 
+// MarshalYAML writes d the way UnmarshalYAML reads it.
+func (d Duration) MarshalYAML() (any, error) {
+	return d.String(), nil
+}
+
 type Config struct {
-	Sources   SourcesConfig   `yaml:"sources"`
-	Storage   StorageConfig   `yaml:"storage"`
-	Digest    DigestConfig    `yaml:"digest"`
-	Summarize SummarizeConfig `yaml:"summarize"`
-	Privacy   PrivacyConfig   `yaml:"privacy"`
+	Sources     SourcesConfig     `yaml:"sources"`
+	Storage     StorageConfig     `yaml:"storage"`
+	Digest      DigestConfig      `yaml:"digest"`
+	Summarize   SummarizeConfig   `yaml:"summarize"`
+	Privacy     PrivacyConfig     `yaml:"privacy"`
+	Network     NetworkConfig     `yaml:"network"`
+	Health      HealthConfig      `yaml:"healthcheck"`
+	Dedup       DedupConfig       `yaml:"dedup"`
+	ClockSkew   ClockSkewConfig   `yaml:"clock_skew"`
+	Boilerplate BoilerplateConfig `yaml:"boilerplate"`
+	Hooks       HooksConfig       `yaml:"hooks"`
+	Cache       CacheConfig       `yaml:"cache"`
+	Serve       ServeConfig       `yaml:"serve"`
+	Publish     PublishConfig     `yaml:"publish"`
+	Monitoring  MonitoringConfig  `yaml:"monitoring"`
+	Delivery    DeliveryConfig    `yaml:"delivery"`
+	Embed       EmbedConfig       `yaml:"embed"`
+
+	// Profile names the taste profile used when --profile is not given:
+	// profiles/<name>.yaml in the config directory, scored separately from
+	// the others. Empty means taste.yaml.
+	Profile string `yaml:"profile"`
+
+	// Channels holds optional per-channel settings keyed by channel name
+	// (as shown in the digest, e.g. "@devops_news" or a feed title).
+	Channels map[string]ChannelConfig `yaml:"channels"`
+
+	// Transforms rewrite post text between fetch and store, in order.
+	Transforms []TransformConfig `yaml:"transforms"`
+}
+
+// TransformConfig cleans up post text for the listed channels (all channels
+// when empty): DropAfter cuts everything from the first line starting with
+// one of its markers, then Strip regexes are removed and Replace applied.
+type TransformConfig struct {
+	Channels  []string        `yaml:"channels"`
+	DropAfter []string        `yaml:"drop_after"`
+	Strip     []string        `yaml:"strip"`
+	Replace   []ReplaceConfig `yaml:"replace"`
+}
+
+// ReplaceConfig replaces matches of Pattern with With ($1 expands groups).
+type ReplaceConfig struct {
+	Pattern string `yaml:"pattern"`
+	With    string `yaml:"with"`
+}
+
+// BoilerplateConfig controls learning of text blocks (footers, promos) repeated
+// across a channel's posts, which are then ignored when scoring and
+// summarizing. Learning runs on every pull over the channel's Sample newest
+// posts once it has MinPosts; a block must appear in MinShare of them.
+type BoilerplateConfig struct {
+	Disabled bool    `yaml:"disabled"`
+	MinShare float64 `yaml:"min_share"`
+	MinPosts int     `yaml:"min_posts"`
+	Sample   int     `yaml:"sample"`
+}
+
+// HooksConfig names scripts run at fixed points of the pipeline. Each gets
+// JSON on stdin: PreScore receives the posts about to be scored and may
+// print them back with changed text or extra labels; PostDigest receives
+// the digest as the JSON formatter renders it and only observes.
+type HooksConfig struct {
+	PreScore   string   `yaml:"pre_score"`
+	PostDigest string   `yaml:"post_digest"`
+	Timeout    Duration `yaml:"timeout"` // per hook run
+}
+
+// CacheConfig locates the on-disk cache of remote lookups (HN items, ...).
+// It is always a local SQLite file, whatever the storage driver, and can be
+// deleted at any time.
+type CacheConfig struct {
+	Disabled bool   `yaml:"disabled"`
+	Path     string `yaml:"path"` // default: cache.db next to storage.path
+}
+
+// ServeConfig configures "noisepan serve". When Token (or the variable named
+// by TokenEnv) is set, every /api request must present it.
+type ServeConfig struct {
+	Token    string `yaml:"token"`
+	TokenEnv string `yaml:"token_env"`
+}
+
+// PublishConfig holds the targets "digest --publish" can create pages in.
+type PublishConfig struct {
+	Notion     NotionConfig     `yaml:"notion"`
+	Confluence ConfluenceConfig `yaml:"confluence"`
+}
+
+// NotionConfig creates digest pages under ParentPage (a page ID) with an
+// integration token.
+type NotionConfig struct {
+	Token      string `yaml:"token"`
+	TokenEnv   string `yaml:"token_env"`
+	ParentPage string `yaml:"parent_page"`
+}
+
+// ConfluenceConfig creates digest pages in Space, under ParentPage when set.
+// With User, Token is a Cloud API token; without, a personal access token.
+type ConfluenceConfig struct {
+	BaseURL    string `yaml:"base_url"` // e.g. https://example.atlassian.net/wiki
+	User       string `yaml:"user"`
+	Token      string `yaml:"token"`
+	TokenEnv   string `yaml:"token_env"`
+	Space      string `yaml:"space"`
+	ParentPage string `yaml:"parent_page"`
+}
+
+// DeliveryConfig holds the targets the digest can be sent to.
+type DeliveryConfig struct {
+	Email    EmailConfig            `yaml:"email"`
+	Telegram TelegramDeliveryConfig `yaml:"telegram"`
+	Discord  DiscordConfig          `yaml:"discord"`
+}
+
+// EmailConfig sends "digest --email" through an SMTP server. Port 465 uses
+// implicit TLS; other ports upgrade with STARTTLS when the server offers it.
+// Credentials are only sent over TLS (or to localhost).
+type EmailConfig struct {
+	Host        string   `yaml:"host"`
+	Port        int      `yaml:"port"` // default 587
+	User        string   `yaml:"user"`
+	Password    string   `yaml:"password"`
+	PasswordEnv string   `yaml:"password_env"`
+	From        string   `yaml:"from"`
+	To          []string `yaml:"to"`
+	Favicons    bool     `yaml:"favicons"`  // inline each channel's favicon, fetched through network:
+	DarkMode    bool     `yaml:"dark_mode"` // add a prefers-color-scheme: dark stylesheet
+}
+
+// TelegramDeliveryConfig posts "digest --telegram" to a chat through a Telegram bot.
+// ChatID is a numeric chat ID or a public channel's @username.
+type TelegramDeliveryConfig struct {
+	BotToken    string `yaml:"bot_token"`
+	BotTokenEnv string `yaml:"bot_token_env"`
+	ChatID      string `yaml:"chat_id"`
+}
+
+// DiscordConfig posts "digest --discord" to a channel through its webhook.
+// The webhook URL is a credential, so prefer webhook_env.
+type DiscordConfig struct {
+	Webhook    string `yaml:"webhook"`
+	WebhookEnv string `yaml:"webhook_env"`
+}
+
+// ChannelConfig holds per-channel options.
+type ChannelConfig struct {
+	// LLMTriage sends headlines that score 0 on keywords through a cheap
+	// LLM classification (summarize.llm.triage).
+	LLMTriage bool `yaml:"llm_triage"`
+
+	// Note and Owner record why the channel was added and who to ask
+	// before removing it. They are shown in stats and the web dashboard and
+	// change nothing else.
+	Note  string `yaml:"note"`
+	Owner string `yaml:"owner"`
 }
 
 type SourcesConfig struct {
@@ -55,22 +262,70 @@ type SourcesConfig struct {
 	Reddit    RedditConfig    `yaml:"reddit"`
 	HN        HNConfig        `yaml:"hn"`
 	ForgePlan ForgePlanConfig `yaml:"forgeplan"`
+	Archive   ArchiveConfig   `yaml:"archive"`
+}
+
+// FetchConfig overrides a source's built-in network behavior and how it reads
+// timestamps. Unset fields keep the source defaults.
+type FetchConfig struct {
+	Timeout    Duration `yaml:"timeout"`
+	MaxRetries *int     `yaml:"max_retries"` // retries after the first attempt; 0 disables
+	Backoff    Duration `yaml:"backoff"`     // initial retry delay, doubled per retry
+	Delay      Duration `yaml:"delay"`       // pause between requests to the same host
+	Workers    int      `yaml:"workers"`
+	Timezone   string   `yaml:"timezone"` // IANA zone for timestamps without an offset; default UTC
+}
+
+// Location returns the configured timezone, or nil if unset or invalid.
+// Validation rejects invalid names, so nil after Load means "unset".
+func (fc FetchConfig) Location() *time.Location {
+	if fc.Timezone == "" {
+		return nil
+	}
+	loc, err := time.LoadLocation(fc.Timezone)
+	if err != nil {
+		return nil
+	}
+	return loc
 }
 
 type HNConfig struct {
-	MinPoints int `yaml:"min_points"`
+	MinPoints   int    `yaml:"min_points"`
+	API         string `yaml:"api"` // firebase (default) or algolia
+	FetchConfig `yaml:",inline"`
 }
 
 type ForgePlanConfig struct {
-	Script string `yaml:"script"`
+	Script      string `yaml:"script"`
+	FetchConfig `yaml:",inline"`
+}
+
+// ArchiveConfig lists newsletters published as dated plaintext/markdown files.
+type ArchiveConfig struct {
+	Newsletters []NewsletterConfig `yaml:"newsletters"`
+	FetchConfig `yaml:",inline"`
+}
+
+// NewsletterConfig describes one archive. URLPattern must contain {date},
+// which is expanded with DateFormat (Go layout, default 2006-01-02).
+type NewsletterConfig struct {
+	Name       string `yaml:"name"`
+	URLPattern string `yaml:"url_pattern"`
+	DateFormat string `yaml:"date_format"`
 }
 
 type RSSConfig struct {
-	Feeds []string `yaml:"feeds"`
+	Feeds       []string `yaml:"feeds"`
+	FetchConfig `yaml:",inline"`
 }
 
 type RedditConfig struct {
 	Subreddits []string `yaml:"subreddits"`
+	// Polite asks for smaller listings, pauses longer between subreddits,
+	// reuses listings for a few minutes, and skips subreddits that were
+	// rate limited recently, for anonymous setups Reddit keeps throttling.
+	Polite      bool `yaml:"polite"`
+	FetchConfig `yaml:",inline"`
 }
 
 type TelegramConfig struct {
@@ -81,40 +336,195 @@ type TelegramConfig struct {
 	Script     string   `yaml:"script"`
 	PythonPath string   `yaml:"python_path"`
 
+	FetchConfig `yaml:",inline"`
+
 	// Resolved from env vars at load time.
 	APIID   string `yaml:"-"`
 	APIHash string `yaml:"-"`
 }
 
 type StorageConfig struct {
-	Path       string `yaml:"path"`
+	Driver     string `yaml:"driver"` // sqlite (default) or postgres
+	Path       string `yaml:"path"`   // sqlite database file
+	DSN        string `yaml:"dsn"`    // postgres connection string
+	DSNEnv     string `yaml:"dsn_env"`
+	RetainDays int    `yaml:"retain_days"`
+	SlimDays   int    `yaml:"slim_days"` // keep snippet/score/metadata this many days past retain_days; 0 deletes at retain_days
+
+	// Retention overrides RetainDays for matching posts. The most specific
+	// rule wins: source and channel, then channel, then source.
+	Retention []RetentionRule `yaml:"retention"`
+}
+
+// RetentionRule keeps posts of a source, a channel (on any source), or one
+// channel of a source for RetainDays instead of storage.retain_days.
+type RetentionRule struct {
+	Source     string `yaml:"source"`
+	Channel    string `yaml:"channel"`
 	RetainDays int    `yaml:"retain_days"`
 }
 
+// Target returns the value passed to the storage backend: the file path for
+// sqlite, the DSN for postgres.
+func (s StorageConfig) Target() string {
+	if s.Driver == "postgres" {
+		return s.DSN
+	}
+	return s.Path
+}
+
 type DigestConfig struct {
 	Timezone     string   `yaml:"timezone"`
 	TopN         int      `yaml:"top_n"`
 	IncludeSkims int      `yaml:"include_skims"`
 	Since        Duration `yaml:"since"`
+	Changes      bool     `yaml:"changes"` // add a "feed changes since last digest" section
+
+	// RescoreChanged rescores posts whose text was edited after they were
+	// scored instead of only flagging them.
+	RescoreChanged bool `yaml:"rescore_changed"`
+
+	// AlsoInOrder lists sources most preferred first for ordering the
+	// channels a duplicate was also posted in; defaults to dedup.source_order.
+	AlsoInOrder []string `yaml:"also_in_order"`
+
+	// StillUnread, when set, lists read_now posts that earlier digests
+	// within this window showed but that were neither read nor starred in a
+	// compact "Still unread" section instead of repeating them in full.
+	StillUnread Duration `yaml:"still_unread"`
+
+	// Template is a Go template file the digest is rendered through when
+	// no --format is given; see digest.TemplateData for what it receives.
+	Template string `yaml:"template"`
+
+	// ExecutiveSummary adds an LLM overview of the listed posts — a
+	// paragraph, the top themes, and the most urgent items — at the top of
+	// the digest. Needs summarize.mode llm.
+	ExecutiveSummary bool `yaml:"executive_summary"`
+
+	JSON DigestJSONConfig `yaml:"json"`
+}
+
+// DigestJSONConfig shapes the text of JSON digests — --format json, the
+// webhook, and the post_digest hook — for consumers such as chat
+// integrations. Lengths are in characters; 0 means no limit.
+type DigestJSONConfig struct {
+	EscapeHTML  bool `yaml:"escape_html"`
+	MaxHeadline int  `yaml:"max_headline"`
+	MaxBullet   int  `yaml:"max_bullet"`
 }
 
 type SummarizeConfig struct {
 	Mode string    `yaml:"mode"`
 	LLM  LLMConfig `yaml:"llm"`
+
+	// Products lists, per vendor, the product names summaries pick out of
+	// post text, e.g. {"Microsoft": ["Exchange", "Windows"]}. A vendor's
+	// own name counts as naming it.
+	Products map[string][]string `yaml:"products"`
 }
 
 type LLMConfig struct {
-	Provider         string `yaml:"provider"`
-	Model            string `yaml:"model"`
-	APIKeyEnv        string `yaml:"api_key_env"`
-	MaxTokensPerPost int    `yaml:"max_tokens_per_post"`
+	Provider         string   `yaml:"provider"` // openai | anthropic | ollama
+	Endpoint         string   `yaml:"endpoint"` // API URL, defaulted per provider
+	Model            string   `yaml:"model"`
+	APIKeyEnv        string   `yaml:"api_key_env"`
+	MaxTokensPerPost int      `yaml:"max_tokens_per_post"`
+	Timeout          Duration `yaml:"timeout"` // per request; default 30s, 5m for ollama
+
+	Prompt LLMPrompts `yaml:"prompt"`
+
+	// USD per million tokens by model, and the spend per calendar month
+	// (UTC) after which summaries fall back to heuristic and triage stops;
+	// 0 means no limit.
+	Prices        map[string]LLMPrice `yaml:"prices"`
+	MonthlyBudget float64             `yaml:"monthly_budget"`
+
+	Triage TriageConfig `yaml:"triage"`
+
+	// Resolved from env var at load time.
+	APIKey string `yaml:"-"`
+}
+
+// NeedsAPIKey reports whether the provider authenticates with an API key;
+// a local Ollama server does not.
+func (c LLMConfig) NeedsAPIKey() bool {
+	return c.Provider != "ollama"
+}
+
+// LLMPrompts replace the built-in DevOps system prompt of LLM summaries:
+// Default for every post, a tier's for posts in it, and a label's for posts
+// with it, which wins over both. A tier with a prompt is summarized with
+// the LLM as read_now is. A plain string is Default.
+type LLMPrompts struct {
+	Default string            `yaml:"default"`
+	Tiers   map[string]string `yaml:"tiers"`
+	Labels  map[string]string `yaml:"labels"`
+}
+
+func (p *LLMPrompts) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		return value.Decode(&p.Default)
+	}
+	type plain LLMPrompts
+	return value.Decode((*plain)(p))
+}
+
+// For returns the prompt for a post in tier with labels: that of the first
+// of its labels with one, else the tier's, else Default. "" means the
+// built-in prompt.
+func (p LLMPrompts) For(tier string, labels []string) string {
+	for _, l := range labels {
+		if prompt := p.Labels[l]; prompt != "" {
+			return prompt
+		}
+	}
+	if prompt := p.Tiers[tier]; prompt != "" {
+		return prompt
+	}
+	return p.Default
+}
+
+// LLMPrice is what a model costs, in USD per million tokens.
+type LLMPrice struct {
+	Input  float64 `yaml:"input"`  // prompt tokens
+	Output float64 `yaml:"output"` // completion tokens
+}
+
+// Cost returns what prompt and completion tokens of model cost at its
+// price, and false when summarize.llm.prices has none for it.
+func (c LLMConfig) Cost(model string, prompt, completion int64) (float64, bool) {
+	p, ok := c.Prices[model]
+	if !ok {
+		return 0, false
+	}
+	return (float64(prompt)*p.Input + float64(completion)*p.Output) / 1e6, true
+}
+
+// EmbedConfig turns on embeddings of post text, used by the similar command,
+// search --semantic and dedup.semantic. Provider openai calls the OpenAI API;
+// local calls an OpenAI-compatible server such as Ollama, with no key.
+type EmbedConfig struct {
+	Provider  string `yaml:"provider"` // openai | local; empty turns embeddings off
+	Endpoint  string `yaml:"endpoint"` // embeddings URL, defaulted per provider
+	Model     string `yaml:"model"`
+	APIKeyEnv string `yaml:"api_key_env"`
+	BatchSize int    `yaml:"batch_size"` // texts per request; default 64
 
 	// Resolved from env var at load time.
 	APIKey string `yaml:"-"`
 }
 
+// TriageConfig controls headline classification for channels with llm_triage.
+type TriageConfig struct {
+	Model     string   `yaml:"model"`       // defaults to summarize.llm.model
+	Interval  Duration `yaml:"interval"`    // minimum gap between requests
+	MaxPerRun int      `yaml:"max_per_run"` // call budget per digest/rescore run
+}
+
 type PrivacyConfig struct {
 	StoreFullText bool         `yaml:"store_full_text"`
+	SnippetLength int          `yaml:"snippet_length"` // characters kept of posts stored without full text; default 200
 	Redact        RedactConfig `yaml:"redact"`
 }
 
@@ -123,6 +533,57 @@ type RedactConfig struct {
 	Patterns []string `yaml:"patterns"`
 }
 
+// HealthConfig controls the healthcheck command.
+type HealthConfig struct {
+	MaxAge Duration `yaml:"max_age"` // last successful pull must be newer than this
+}
+
+// MonitoringConfig sets a dead man's switch for scheduled runs: pull and
+// run request PingURL when they succeed and PingURL + "/fail" when they
+// fail, as healthchecks.io and compatible services expect.
+type MonitoringConfig struct {
+	PingURL    string `yaml:"ping_url"`
+	PingURLEnv string `yaml:"ping_url_env"`
+}
+
+// DedupConfig chooses which copy of a duplicated post is kept and what
+// counts as a duplicate besides identical text: reworded copies (Similarity),
+// posts saying the same thing in other words (Semantic) and posts linking to
+// the same page (unless TextOnly).
+type DedupConfig struct {
+	Keep        string   `yaml:"keep"`         // earliest | source | longest
+	SourceOrder []string `yaml:"source_order"` // for keep: source, most preferred first
+	Similarity  float64  `yaml:"similarity"`   // 0 = identical text only; 0.5-1, e.g. 0.8
+	TextOnly    bool     `yaml:"text_only"`    // do not merge posts by canonical URL
+	Semantic    float64  `yaml:"semantic"`     // 0 = off; embedding similarity 0.5-1, e.g. 0.92; needs embed
+
+	Recurring RecurringConfig `yaml:"recurring"`
+}
+
+// RecurringConfig handles posts a channel repeats word for word on a
+// schedule, such as a daily standup bot or a weekly thread. Copies posted at
+// least MinGap apart are kept as separate posts and labeled "recurring"
+// instead of being merged as duplicates.
+type RecurringConfig struct {
+	Mode   string   `yaml:"mode"`    // label | suppress (label and rank as ignore) | merge (treat as duplicates)
+	MinGap Duration `yaml:"min_gap"` // default 20h
+}
+
+// ClockSkewConfig controls handling of posts dated in the future.
+type ClockSkewConfig struct {
+	Tolerance Duration `yaml:"tolerance"` // allowed lead over the fetch time
+	Mode      string   `yaml:"mode"`      // clamp (rewrite to fetch time) | flag (keep, warn)
+}
+
+// NetworkConfig controls the HTTP transport shared by RSS, Reddit, HN, and LLM clients.
+type NetworkConfig struct {
+	HTTPProxy          string   `yaml:"http_proxy"`           // e.g. http://proxy.corp:3128
+	SOCKS5Proxy        string   `yaml:"socks5_proxy"`         // e.g. socks5://127.0.0.1:1080, wins over http_proxy
+	NoProxy            []string `yaml:"no_proxy"`             // hosts that bypass the proxy
+	CABundle           string   `yaml:"ca_bundle"`            // PEM file added to the system roots
+	InsecureSkipVerify []string `yaml:"insecure_skip_verify"` // hosts whose certificates are not verified
+}
+
 // Load reads config.yaml from dir, applies defaults, resolves env vars, and validates.
 func Load(dir string) (*Config, error) {
 	if strings.TrimSpace(dir) == "" {
@@ -142,6 +603,7 @@ func Load(dir string) (*Config, error) {
 
 	applyDefaults(&cfg)
 	resolveEnv(&cfg)
+	expandPaths(&cfg)
 
 	if err := validate(&cfg); err != nil {
 		return nil, fmt.Errorf("validate config: %w", err)
@@ -151,6 +613,18 @@ func Load(dir string) (*Config, error) {
 }
 
 func applyDefaults(cfg *Config) {
+	if cfg.Boilerplate.MinShare == 0 {
+		cfg.Boilerplate.MinShare = DefaultBoilerplateMinShare
+	}
+	if cfg.Boilerplate.MinPosts == 0 {
+		cfg.Boilerplate.MinPosts = DefaultBoilerplateMinPosts
+	}
+	if cfg.Boilerplate.Sample == 0 {
+		cfg.Boilerplate.Sample = DefaultBoilerplateSample
+	}
+	if cfg.Storage.Driver == "" {
+		cfg.Storage.Driver = DefaultStorageDriver
+	}
 	if cfg.Storage.Path == "" {
 		cfg.Storage.Path = DefaultStoragePath
 	}
@@ -169,9 +643,82 @@ func applyDefaults(cfg *Config) {
 	if cfg.Digest.Timezone == "" {
 		cfg.Digest.Timezone = DefaultTimezone
 	}
+	if len(cfg.Digest.AlsoInOrder) == 0 {
+		cfg.Digest.AlsoInOrder = cfg.Dedup.SourceOrder
+	}
 	if cfg.Summarize.Mode == "" {
 		cfg.Summarize.Mode = DefaultSummarizeMode
 	}
+	if cfg.Summarize.LLM.Provider == "" {
+		cfg.Summarize.LLM.Provider = DefaultLLMProvider
+	}
+	if cfg.Summarize.LLM.Timeout.Duration == 0 {
+		cfg.Summarize.LLM.Timeout.Duration = DefaultLLMTimeout
+		if cfg.Summarize.LLM.Provider == "ollama" {
+			cfg.Summarize.LLM.Timeout.Duration = DefaultOllamaLLMTimeout
+		}
+	}
+	if cfg.Summarize.LLM.Provider == "ollama" && cfg.Summarize.LLM.Model == "" {
+		cfg.Summarize.LLM.Model = DefaultOllamaLLMModel
+	}
+	if cfg.Summarize.LLM.Triage.Model == "" {
+		cfg.Summarize.LLM.Triage.Model = cfg.Summarize.LLM.Model
+	}
+	if cfg.Summarize.LLM.Triage.Interval.Duration == 0 {
+		cfg.Summarize.LLM.Triage.Interval.Duration = DefaultTriageInterval
+	}
+	if cfg.Summarize.LLM.Triage.MaxPerRun == 0 {
+		cfg.Summarize.LLM.Triage.MaxPerRun = DefaultTriageMaxPerRun
+	}
+	if cfg.Health.MaxAge.Duration == 0 {
+		cfg.Health.MaxAge.Duration = DefaultHealthMaxAge
+	}
+	if cfg.Privacy.SnippetLength == 0 {
+		cfg.Privacy.SnippetLength = DefaultSnippetLength
+	}
+	if cfg.Dedup.Keep == "" {
+		cfg.Dedup.Keep = DefaultDedupKeep
+	}
+	if cfg.Dedup.Recurring.Mode == "" {
+		cfg.Dedup.Recurring.Mode = DefaultRecurringMode
+	}
+	if cfg.Dedup.Recurring.MinGap.Duration == 0 {
+		cfg.Dedup.Recurring.MinGap.Duration = DefaultRecurringMinGap
+	}
+	if cfg.ClockSkew.Tolerance.Duration == 0 {
+		cfg.ClockSkew.Tolerance.Duration = DefaultClockSkewTolerance
+	}
+	if cfg.ClockSkew.Mode == "" {
+		cfg.ClockSkew.Mode = DefaultClockSkewMode
+	}
+	if cfg.Cache.Path == "" {
+		cfg.Cache.Path = filepath.Join(filepath.Dir(cfg.Storage.Path), DefaultCacheFile)
+	}
+	if cfg.Hooks.Timeout.Duration == 0 {
+		cfg.Hooks.Timeout.Duration = DefaultHookTimeout
+	}
+	if cfg.Delivery.Email.Host != "" && cfg.Delivery.Email.Port == 0 {
+		cfg.Delivery.Email.Port = DefaultSMTPPort
+	}
+	switch cfg.Embed.Provider {
+	case "openai":
+		if cfg.Embed.Endpoint == "" {
+			cfg.Embed.Endpoint = DefaultOpenAIEmbedEndpoint
+		}
+		if cfg.Embed.Model == "" {
+			cfg.Embed.Model = DefaultOpenAIEmbedModel
+		}
+	case "local":
+		if cfg.Embed.Endpoint == "" {
+			cfg.Embed.Endpoint = DefaultLocalEmbedEndpoint
+		}
+		if cfg.Embed.Model == "" {
+			cfg.Embed.Model = DefaultLocalEmbedModel
+		}
+	}
+	if cfg.Embed.BatchSize == 0 {
+		cfg.Embed.BatchSize = DefaultEmbedBatchSize
+	}
 }
 
 func resolveEnv(cfg *Config) {
@@ -184,28 +731,372 @@ func resolveEnv(cfg *Config) {
 	if cfg.Summarize.LLM.APIKeyEnv != "" {
 		cfg.Summarize.LLM.APIKey = os.Getenv(cfg.Summarize.LLM.APIKeyEnv)
 	}
+	if cfg.Embed.APIKeyEnv != "" {
+		cfg.Embed.APIKey = os.Getenv(cfg.Embed.APIKeyEnv)
+	}
+	if cfg.Storage.DSNEnv != "" {
+		cfg.Storage.DSN = os.Getenv(cfg.Storage.DSNEnv)
+	}
+	if cfg.Serve.TokenEnv != "" {
+		cfg.Serve.Token = os.Getenv(cfg.Serve.TokenEnv)
+	}
+	if cfg.Publish.Notion.TokenEnv != "" {
+		cfg.Publish.Notion.Token = os.Getenv(cfg.Publish.Notion.TokenEnv)
+	}
+	if cfg.Publish.Confluence.TokenEnv != "" {
+		cfg.Publish.Confluence.Token = os.Getenv(cfg.Publish.Confluence.TokenEnv)
+	}
+	if cfg.Monitoring.PingURLEnv != "" {
+		cfg.Monitoring.PingURL = os.Getenv(cfg.Monitoring.PingURLEnv)
+	}
+	if cfg.Delivery.Email.PasswordEnv != "" {
+		cfg.Delivery.Email.Password = os.Getenv(cfg.Delivery.Email.PasswordEnv)
+	}
+	if cfg.Delivery.Telegram.BotTokenEnv != "" {
+		cfg.Delivery.Telegram.BotToken = os.Getenv(cfg.Delivery.Telegram.BotTokenEnv)
+	}
+	if cfg.Delivery.Discord.WebhookEnv != "" {
+		cfg.Delivery.Discord.Webhook = os.Getenv(cfg.Delivery.Discord.WebhookEnv)
+	}
+}
+
+// expandPaths expands a leading ~ in file paths, since no shell does it for
+// the config file (and Windows shells never do), and normalizes separators.
+func expandPaths(cfg *Config) {
+	for _, p := range []*string{
+		&cfg.Storage.Path,
+		&cfg.Sources.Telegram.SessionDir,
+		&cfg.Sources.Telegram.Script,
+		&cfg.Sources.Telegram.PythonPath,
+		&cfg.Sources.ForgePlan.Script,
+		&cfg.Hooks.PreScore,
+		&cfg.Hooks.PostDigest,
+		&cfg.Cache.Path,
+		&cfg.Digest.Template,
+	} {
+		*p = ExpandPath(*p)
+	}
+}
+
+// ExpandPath replaces a leading "~" or "~/" with the user's home directory
+// and converts slashes to the OS separator. Other paths, and all paths when
+// the home directory is unknown, only have their separators converted.
+func ExpandPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, `~\`) {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[1:])
+		}
+	}
+	return filepath.FromSlash(path)
+}
+
+var profileNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ValidateProfile reports whether name can name a taste profile: lowercase
+// letters, digits, "-" and "_", so it is safe as a file name.
+func ValidateProfile(name string) error {
+	if !profileNameRe.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q (use lowercase letters, digits, - and _)", name)
+	}
+	return nil
 }
 
 func validate(cfg *Config) error {
+	if cfg.Profile != "" {
+		if err := ValidateProfile(cfg.Profile); err != nil {
+			return fmt.Errorf("profile: %w", err)
+		}
+	}
+
 	hasTelegram := len(cfg.Sources.Telegram.Channels) > 0
 	hasRSS := len(cfg.Sources.RSS.Feeds) > 0
 	hasReddit := len(cfg.Sources.Reddit.Subreddits) > 0
 	hasHN := cfg.Sources.HN.MinPoints > 0
 	hasForgePlan := cfg.Sources.ForgePlan.Script != ""
-	if !hasTelegram && !hasRSS && !hasReddit && !hasHN && !hasForgePlan {
+	hasArchive := len(cfg.Sources.Archive.Newsletters) > 0
+	if !hasTelegram && !hasRSS && !hasReddit && !hasHN && !hasForgePlan && !hasArchive {
 		return errors.New("sources: at least one source must be configured")
 	}
 
+	switch cfg.Sources.HN.API {
+	case "", "firebase", "algolia":
+	default:
+		return fmt.Errorf("sources.hn.api: unknown api %q (want firebase or algolia)", cfg.Sources.HN.API)
+	}
+
+	for i, nl := range cfg.Sources.Archive.Newsletters {
+		if nl.Name == "" {
+			return fmt.Errorf("sources.archive.newsletters[%d]: name is required", i)
+		}
+		if !strings.Contains(nl.URLPattern, "{date}") {
+			return fmt.Errorf("sources.archive.newsletters[%d]: url_pattern must contain {date}", i)
+		}
+	}
+
+	switch cfg.Storage.Driver {
+	case "sqlite":
+	case "postgres":
+		if cfg.Storage.DSN == "" {
+			return errors.New("storage.dsn: required for the postgres driver (or set storage.dsn_env)")
+		}
+	default:
+		return fmt.Errorf("storage.driver: unknown driver %q (want sqlite or postgres)", cfg.Storage.Driver)
+	}
+	if cfg.Boilerplate.MinShare < 0 || cfg.Boilerplate.MinShare > 1 {
+		return errors.New("boilerplate.min_share: must be between 0 and 1")
+	}
+	if cfg.Boilerplate.MinPosts < 2 {
+		return errors.New("boilerplate.min_posts: must be at least 2")
+	}
+	if cfg.Boilerplate.Sample < cfg.Boilerplate.MinPosts {
+		return errors.New("boilerplate.sample: must be at least min_posts")
+	}
+
+	for i, tr := range cfg.Transforms {
+		for j, p := range tr.Strip {
+			if _, err := regexp.Compile(p); err != nil {
+				return fmt.Errorf("transforms[%d].strip[%d]: %w", i, j, err)
+			}
+		}
+		for j, r := range tr.Replace {
+			if _, err := regexp.Compile(r.Pattern); err != nil {
+				return fmt.Errorf("transforms[%d].replace[%d]: %w", i, j, err)
+			}
+		}
+		for j, m := range tr.DropAfter {
+			if strings.TrimSpace(m) == "" {
+				return fmt.Errorf("transforms[%d].drop_after[%d]: marker must not be blank", i, j)
+			}
+		}
+	}
+
+	if cfg.Storage.SlimDays < 0 {
+		return errors.New("storage.slim_days: must not be negative")
+	}
+	for i, r := range cfg.Storage.Retention {
+		if r.Source == "" && r.Channel == "" {
+			return fmt.Errorf("storage.retention[%d]: source or channel is required", i)
+		}
+		if r.RetainDays <= 0 {
+			return fmt.Errorf("storage.retention[%d].retain_days: must be positive", i)
+		}
+	}
+
+	if u := cfg.Monitoring.PingURL; u != "" {
+		if parsed, err := url.Parse(u); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return errors.New("monitoring.ping_url: must be an http or https URL")
+		}
+	}
+
+	if err := validateEmail(cfg.Delivery.Email); err != nil {
+		return fmt.Errorf("delivery.email.%w", err)
+	}
+	if tc := cfg.Delivery.Telegram; (tc.BotToken != "" || tc.BotTokenEnv != "") && tc.ChatID == "" {
+		return errors.New("delivery.telegram.chat_id: is required with a bot token")
+	}
+	if u := cfg.Delivery.Discord.Webhook; u != "" {
+		if parsed, err := url.Parse(u); err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+			return errors.New("delivery.discord.webhook: must be an https URL")
+		}
+	}
+
+	if cfg.Digest.StillUnread.Duration < 0 {
+		return errors.New("digest.still_unread: must not be negative")
+	}
+	if cfg.Digest.JSON.MaxHeadline < 0 {
+		return errors.New("digest.json.max_headline: must not be negative")
+	}
+	if cfg.Digest.JSON.MaxBullet < 0 {
+		return errors.New("digest.json.max_bullet: must not be negative")
+	}
+	if cfg.Digest.ExecutiveSummary && cfg.Summarize.Mode != "llm" {
+		return errors.New("digest.executive_summary: needs summarize.mode llm")
+	}
+
 	if _, err := time.LoadLocation(cfg.Digest.Timezone); err != nil {
 		return fmt.Errorf("digest.timezone: %w", err)
 	}
 
+	fetches := []struct {
+		name string
+		fc   FetchConfig
+	}{
+		{"telegram", cfg.Sources.Telegram.FetchConfig},
+		{"rss", cfg.Sources.RSS.FetchConfig},
+		{"reddit", cfg.Sources.Reddit.FetchConfig},
+		{"hn", cfg.Sources.HN.FetchConfig},
+		{"forgeplan", cfg.Sources.ForgePlan.FetchConfig},
+		{"archive", cfg.Sources.Archive.FetchConfig},
+	}
+	for _, f := range fetches {
+		if err := validateFetch(f.fc); err != nil {
+			return fmt.Errorf("sources.%s: %w", f.name, err)
+		}
+	}
+
+	if p := cfg.Network.SOCKS5Proxy; p != "" && !strings.HasPrefix(p, "socks5://") && !strings.HasPrefix(p, "socks5h://") {
+		return fmt.Errorf("network.socks5_proxy: %q must start with socks5://", p)
+	}
+
+	switch cfg.Dedup.Keep {
+	case "earliest", "longest":
+		// valid
+	case "source":
+		if len(cfg.Dedup.SourceOrder) == 0 {
+			return errors.New("dedup.source_order: required when dedup.keep is source")
+		}
+	default:
+		return fmt.Errorf("dedup.keep: unknown strategy %q (want earliest, source, or longest)", cfg.Dedup.Keep)
+	}
+	// Unrelated texts already share about half their fingerprint bits.
+	if s := cfg.Dedup.Similarity; s != 0 && (s < 0.5 || s > 1) {
+		return fmt.Errorf("dedup.similarity: %v must be 0 (off) or between 0.5 and 1", s)
+	}
+	if s := cfg.Dedup.Semantic; s != 0 && (s < 0.5 || s > 1) {
+		return fmt.Errorf("dedup.semantic: %v must be 0 (off) or between 0.5 and 1", s)
+	}
+	if cfg.Dedup.Semantic != 0 && cfg.Embed.Provider == "" {
+		return errors.New("dedup.semantic: requires embed.provider")
+	}
+	switch cfg.Dedup.Recurring.Mode {
+	case "label", "suppress", "merge":
+		// valid
+	default:
+		return fmt.Errorf("dedup.recurring.mode: unknown mode %q (want label, suppress, or merge)", cfg.Dedup.Recurring.Mode)
+	}
+	if cfg.Dedup.Recurring.MinGap.Duration < 0 {
+		return errors.New("dedup.recurring.min_gap: must not be negative")
+	}
+
+	if cfg.Privacy.SnippetLength < 0 {
+		return errors.New("privacy.snippet_length: must not be negative")
+	}
+
+	switch cfg.Embed.Provider {
+	case "", "openai", "local":
+		// valid
+	default:
+		return fmt.Errorf("embed.provider: unknown provider %q (want openai or local)", cfg.Embed.Provider)
+	}
+	if cfg.Embed.BatchSize < 0 {
+		return errors.New("embed.batch_size: must not be negative")
+	}
+
+	switch cfg.ClockSkew.Mode {
+	case "clamp", "flag":
+		// valid
+	default:
+		return fmt.Errorf("clock_skew.mode: unknown mode %q (want clamp or flag)", cfg.ClockSkew.Mode)
+	}
+	if cfg.ClockSkew.Tolerance.Duration < 0 {
+		return errors.New("clock_skew.tolerance: must not be negative")
+	}
+	if cfg.Hooks.Timeout.Duration < 0 {
+		return errors.New("hooks.timeout: must not be negative")
+	}
+
 	switch cfg.Summarize.Mode {
 	case "heuristic", "llm":
 		// valid
 	default:
 		return fmt.Errorf("summarize.mode: unknown mode %q (want heuristic or llm)", cfg.Summarize.Mode)
 	}
+	for _, vendor := range slices.Sorted(maps.Keys(cfg.Summarize.Products)) {
+		if strings.TrimSpace(vendor) == "" {
+			return errors.New("summarize.products: empty vendor name")
+		}
+		if slices.ContainsFunc(cfg.Summarize.Products[vendor], func(p string) bool { return strings.TrimSpace(p) == "" }) {
+			return fmt.Errorf("summarize.products.%s: empty product name", vendor)
+		}
+	}
+	switch cfg.Summarize.LLM.Provider {
+	case "openai", "anthropic", "ollama":
+		// valid
+	default:
+		return fmt.Errorf("summarize.llm.provider: unknown provider %q (want openai, anthropic, or ollama)", cfg.Summarize.LLM.Provider)
+	}
+	if u := cfg.Summarize.LLM.Endpoint; u != "" {
+		if parsed, err := url.Parse(u); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return errors.New("summarize.llm.endpoint: must be an http or https URL")
+		}
+	}
+	if cfg.Summarize.LLM.Timeout.Duration < 0 {
+		return errors.New("summarize.llm.timeout: must not be negative")
+	}
+	for _, model := range slices.Sorted(maps.Keys(cfg.Summarize.LLM.Prices)) {
+		if p := cfg.Summarize.LLM.Prices[model]; p.Input < 0 || p.Output < 0 {
+			return fmt.Errorf("summarize.llm.prices.%s: must not be negative", model)
+		}
+	}
+	if budget := cfg.Summarize.LLM.MonthlyBudget; budget < 0 {
+		return errors.New("summarize.llm.monthly_budget: must not be negative")
+	} else if budget > 0 {
+		models := []string{cfg.Summarize.LLM.Model}
+		for _, ch := range cfg.Channels {
+			if ch.LLMTriage {
+				models = append(models, cfg.Summarize.LLM.Triage.Model)
+				break
+			}
+		}
+		for _, model := range models {
+			if _, ok := cfg.Summarize.LLM.Prices[model]; !ok {
+				return fmt.Errorf("summarize.llm.monthly_budget: needs a price for model %q under summarize.llm.prices", model)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateEmail checks an email delivery; errors start with the offending
+// field.
+func validateEmail(ec EmailConfig) error {
+	if ec.Host == "" {
+		if ec.From != "" || len(ec.To) > 0 {
+			return errors.New("host: is required")
+		}
+		return nil
+	}
+	if ec.Port < 1 || ec.Port > 65535 {
+		return fmt.Errorf("port: %d is not a valid port", ec.Port)
+	}
+	if _, err := mail.ParseAddress(ec.From); err != nil {
+		return fmt.Errorf("from: %w", err)
+	}
+	if len(ec.To) == 0 {
+		return errors.New("to: at least one recipient is required")
+	}
+	for i, to := range ec.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			return fmt.Errorf("to[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
 
+func validateFetch(fc FetchConfig) error {
+	if fc.Timeout.Duration < 0 {
+		return errors.New("timeout must not be negative")
+	}
+	if fc.MaxRetries != nil && *fc.MaxRetries < 0 {
+		return errors.New("max_retries must not be negative")
+	}
+	if fc.Backoff.Duration < 0 {
+		return errors.New("backoff must not be negative")
+	}
+	if fc.Delay.Duration < 0 {
+		return errors.New("delay must not be negative")
+	}
+	if fc.Workers < 0 {
+		return errors.New("workers must not be negative")
+	}
+	if fc.Timezone != "" {
+		if _, err := time.LoadLocation(fc.Timezone); err != nil {
+			return fmt.Errorf("timezone: %w", err)
+		}
+	}
 	return nil
 }
@@ -3,9 +3,13 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 func writeTestYAML(t *testing.T, dir, filename, content string) string {
@@ -149,6 +153,12 @@ sources:
 	if cfg.Digest.Since.Duration != DefaultSince {
 		t.Errorf("since = %v, want %v", cfg.Digest.Since.Duration, DefaultSince)
 	}
+	if cfg.ClockSkew.Mode != DefaultClockSkewMode || cfg.ClockSkew.Tolerance.Duration != DefaultClockSkewTolerance {
+		t.Errorf("clock_skew = %+v, want defaults", cfg.ClockSkew)
+	}
+	if cfg.Dedup.Keep != DefaultDedupKeep {
+		t.Errorf("dedup.keep = %q, want %q", cfg.Dedup.Keep, DefaultDedupKeep)
+	}
 	if cfg.Digest.Timezone != DefaultTimezone {
 		t.Errorf("timezone = %q, want %q", cfg.Digest.Timezone, DefaultTimezone)
 	}
@@ -249,6 +259,46 @@ sources:
 	}
 }
 
+func TestLoad_ArchiveOnly(t *testing.T) {
+	dir := t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  archive:
+    newsletters:
+      - name: "Ops Weekly"
+        url_pattern: "https://example.com/{date}.md"
+    delay: 5s
+`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	nl := cfg.Sources.Archive.Newsletters
+	if len(nl) != 1 || nl[0].Name != "Ops Weekly" || nl[0].URLPattern != "https://example.com/{date}.md" {
+		t.Errorf("newsletters = %+v", nl)
+	}
+	if cfg.Sources.Archive.Delay.Duration != 5*time.Second {
+		t.Errorf("delay = %v, want 5s", cfg.Sources.Archive.Delay.Duration)
+	}
+}
+
+func TestLoad_ArchivePatternWithoutDate(t *testing.T) {
+	dir := t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  archive:
+    newsletters:
+      - name: "Ops Weekly"
+        url_pattern: "https://example.com/latest.md"
+`)
+
+	_, err := Load(dir)
+	if err == nil || !strings.Contains(err.Error(), "{date}") {
+		t.Errorf("error = %v, want {date} error", err)
+	}
+}
+
 func TestLoad_InvalidTimezone(t *testing.T) {
 	dir := t.TempDir()
 	writeTestYAML(t, dir, DefaultConfigFile, `
@@ -268,245 +318,1864 @@ digest:
 	}
 }
 
-func TestLoad_InvalidSummarizeMode(t *testing.T) {
+func TestLoad_NegativeSlimDays(t *testing.T) {
 	dir := t.TempDir()
 	writeTestYAML(t, dir, DefaultConfigFile, `
 sources:
   telegram:
     channels: ["@ch"]
-summarize:
-  mode: quantum
+storage:
+  slim_days: -1
 `)
 
 	_, err := Load(dir)
 	if err == nil {
-		t.Fatal("expected error for invalid mode")
-	}
-	if want := "unknown mode"; !strings.Contains(err.Error(), want) {
-		t.Errorf("error = %q, want containing %q", err, want)
-	}
-}
-
-func TestLoad_FileNotFound(t *testing.T) {
-	_, err := Load(t.TempDir())
-	if err == nil {
-		t.Fatal("expected error for missing file")
+		t.Fatal("expected error for negative slim_days")
 	}
-	if want := "read config"; !strings.Contains(err.Error(), want) {
+	if want := "storage.slim_days"; !strings.Contains(err.Error(), want) {
 		t.Errorf("error = %q, want containing %q", err, want)
 	}
 }
 
-func TestLoad_InvalidYAML(t *testing.T) {
+func TestLoad_PostgresStorage(t *testing.T) {
 	dir := t.TempDir()
-	writeTestYAML(t, dir, DefaultConfigFile, `{{{invalid`)
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+storage:
+  driver: postgres
+  dsn_env: NOISEPAN_TEST_DSN
+`)
+	t.Setenv("NOISEPAN_TEST_DSN", "postgres://db.internal/noisepan")
 
-	_, err := Load(dir)
-	if err == nil {
-		t.Fatal("expected error for malformed yaml")
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
 	}
-	if want := "parse config"; !strings.Contains(err.Error(), want) {
-		t.Errorf("error = %q, want containing %q", err, want)
+	if got := cfg.Storage.Target(); got != "postgres://db.internal/noisepan" {
+		t.Errorf("Target = %q, want DSN from env", got)
 	}
 }
 
-func TestLoad_EmptyDir(t *testing.T) {
-	_, err := Load("")
-	if err == nil {
-		t.Fatal("expected error for empty dir")
-	}
-	if want := "config dir is required"; !strings.Contains(err.Error(), want) {
-		t.Errorf("error = %q, want containing %q", err, want)
+func TestLoad_StorageDriverValidation(t *testing.T) {
+	for name, tc := range map[string]struct{ storage, want string }{
+		"missing dsn":    {"driver: postgres", "storage.dsn"},
+		"unknown driver": {"driver: mysql", "storage.driver"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+storage:
+  `+tc.storage+`
+`)
+			_, err := Load(dir)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("error = %v, want containing %q", err, tc.want)
+			}
+		})
 	}
 }
 
-func TestLoad_EnvVarResolution(t *testing.T) {
+func TestLoad_Transforms(t *testing.T) {
 	dir := t.TempDir()
-	t.Setenv("NP_TEST_KEY", "resolved-value")
-
 	writeTestYAML(t, dir, DefaultConfigFile, `
 sources:
   telegram:
     channels: ["@ch"]
-summarize:
-  mode: llm
-  llm:
-    api_key_env: NP_TEST_KEY
+transforms:
+  - channels: ["Ops Weekly"]
+    drop_after: ["--"]
+    strip: ["(?s)Unsubscribe.*$"]
+    replace:
+      - pattern: "\\s+"
+        with: " "
 `)
 
 	cfg, err := Load(dir)
 	if err != nil {
 		t.Fatalf("load: %v", err)
 	}
-	if cfg.Summarize.LLM.APIKey != "resolved-value" {
-		t.Errorf("api_key = %q, want resolved-value", cfg.Summarize.LLM.APIKey)
+	if len(cfg.Transforms) != 1 || cfg.Transforms[0].Channels[0] != "Ops Weekly" || cfg.Transforms[0].Replace[0].With != " " {
+		t.Errorf("transforms = %+v", cfg.Transforms)
+	}
+
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+transforms:
+  - strip: ["[unclosed"]
+`)
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "transforms[0].strip[0]") {
+		t.Errorf("error = %v, want transforms[0].strip[0]", err)
 	}
 }
 
-func TestLoad_EnvVarMissing(t *testing.T) {
+func TestLoad_DedupSourceOrder(t *testing.T) {
 	dir := t.TempDir()
 	writeTestYAML(t, dir, DefaultConfigFile, `
 sources:
   telegram:
-    api_id_env: NONEXISTENT_VAR_12345
     channels: ["@ch"]
+dedup:
+  keep: source
+  source_order: [rss, telegram]
 `)
 
 	cfg, err := Load(dir)
 	if err != nil {
 		t.Fatalf("load: %v", err)
 	}
-	if cfg.Sources.Telegram.APIID != "" {
-		t.Errorf("api_id = %q, want empty", cfg.Sources.Telegram.APIID)
+	if cfg.Dedup.Keep != "source" || len(cfg.Dedup.SourceOrder) != 2 || cfg.Dedup.SourceOrder[0] != "rss" {
+		t.Errorf("dedup = %+v", cfg.Dedup)
+	}
+	// also_in_order follows the dedup priority unless set.
+	if len(cfg.Digest.AlsoInOrder) != 2 || cfg.Digest.AlsoInOrder[0] != "rss" {
+		t.Errorf("digest.also_in_order = %v, want dedup.source_order", cfg.Digest.AlsoInOrder)
 	}
 }
 
-// --- LoadTaste tests ---
+func TestLoad_DedupInvalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown strategy":    "dedup:\n  keep: newest\n",
+		"source missing list": "dedup:\n  keep: source\n",
+		"similarity too low":  "dedup:\n  similarity: 0.3\n",
+		"semantic too low":    "embed:\n  provider: local\ndedup:\n  semantic: 0.2\n",
+		"semantic no embed":   "dedup:\n  semantic: 0.9\n",
+	}
+	for name, extra := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTestYAML(t, dir, DefaultConfigFile, "sources:\n  telegram:\n    channels: [\"@ch\"]\n"+extra)
+			if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "dedup") {
+				t.Errorf("error = %v, want dedup error", err)
+			}
+		})
+	}
+}
 
-func TestLoadTaste_Full(t *testing.T) {
+func TestLoad_Embed(t *testing.T) {
+	t.Setenv("TEST_EMBED_KEY", "sk-embed")
 	dir := t.TempDir()
-	path := writeTestYAML(t, dir, "taste.yaml", `
-weights:
-  high_signal:
-    "cve": 5
-    "kubernetes": 3
-  low_signal:
-    "hiring": -3
-    "webinar": -4
-labels:
-  critical:
-    - "cve"
-    - "zero-day"
-  ops:
-    - "kubernetes"
-rules:
-  - if:
-      contains_any: ["expired", "certificate"]
-    then:
-      score_add: 4
-      labels: ["ops", "certs"]
-  - if:
-      contains_any: ["webinar"]
-    then:
-      score_add: -6
-      labels: ["noise"]
-thresholds:
-  read_now: 7
-  skim: 3
-  ignore: 0
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+embed:
+  provider: openai
+  api_key_env: TEST_EMBED_KEY
+dedup:
+  semantic: 0.9
 `)
-
-	tp, err := LoadTaste(path)
+	cfg, err := Load(dir)
 	if err != nil {
-		t.Fatalf("load taste: %v", err)
+		t.Fatalf("load: %v", err)
+	}
+	e := cfg.Embed
+	if e.Endpoint != DefaultOpenAIEmbedEndpoint || e.Model != DefaultOpenAIEmbedModel || e.APIKey != "sk-embed" || e.BatchSize != DefaultEmbedBatchSize {
+		t.Errorf("embed = %+v", e)
+	}
+	if cfg.Dedup.Semantic != 0.9 {
+		t.Errorf("dedup.semantic = %v, want 0.9", cfg.Dedup.Semantic)
 	}
 
-	// Weights
-	if tp.Weights.HighSignal["cve"] != 5 {
-		t.Errorf("high_signal[cve] = %d, want 5", tp.Weights.HighSignal["cve"])
+	dir = t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, "sources:\n  telegram:\n    channels: [\"@ch\"]\nembed:\n  provider: local\n")
+	if cfg, err = Load(dir); err != nil {
+		t.Fatalf("load local: %v", err)
 	}
-	if tp.Weights.LowSignal["webinar"] != -4 {
-		t.Errorf("low_signal[webinar] = %d, want -4", tp.Weights.LowSignal["webinar"])
+	if cfg.Embed.Endpoint != DefaultLocalEmbedEndpoint || cfg.Embed.Model != DefaultLocalEmbedModel {
+		t.Errorf("local embed = %+v", cfg.Embed)
 	}
 
-	// Labels
-	if len(tp.Labels["critical"]) != 2 {
-		t.Errorf("labels[critical] = %v", tp.Labels["critical"])
+	dir = t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, "sources:\n  telegram:\n    channels: [\"@ch\"]\nembed:\n  provider: cohere\n")
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "embed.provider") {
+		t.Errorf("error = %v, want embed.provider error", err)
 	}
+}
 
-	// Rules
-	if len(tp.Rules) != 2 {
-		t.Errorf("rules count = %d, want 2", len(tp.Rules))
+func TestLoad_Profile(t *testing.T) {
+	dir := t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, "profile: work\nsources:\n  telegram:\n    channels: [\"@ch\"]\n")
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
 	}
-	if tp.Rules[0].Then.ScoreAdd != 4 {
-		t.Errorf("rule[0] score_add = %d, want 4", tp.Rules[0].Then.ScoreAdd)
+	if cfg.Profile != "work" {
+		t.Errorf("profile = %q, want work", cfg.Profile)
 	}
-	if len(tp.Rules[0].If.ContainsAny) != 2 {
-		t.Errorf("rule[0] contains_any = %v", tp.Rules[0].If.ContainsAny)
+
+	dir = t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, "profile: ../work\nsources:\n  telegram:\n    channels: [\"@ch\"]\n")
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "profile:") {
+		t.Errorf("error = %v, want profile error", err)
 	}
+}
 
-	// Thresholds
-	if tp.Thresholds.ReadNow != 7 {
-		t.Errorf("read_now = %d, want 7", tp.Thresholds.ReadNow)
+func TestLoad_DedupRecurring(t *testing.T) {
+	dir := t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, "sources:\n  telegram:\n    channels: [\"@ch\"]\n")
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
 	}
-	if tp.Thresholds.Skim != 3 {
-		t.Errorf("skim = %d, want 3", tp.Thresholds.Skim)
+	if r := cfg.Dedup.Recurring; r.Mode != "label" || r.MinGap.Duration != 20*time.Hour {
+		t.Errorf("recurring defaults = %+v, want label after 20h", r)
+	}
+
+	for name, extra := range map[string]string{
+		"unknown mode": "dedup:\n  recurring:\n    mode: drop\n",
+		"negative gap": "dedup:\n  recurring:\n    min_gap: -1h\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTestYAML(t, dir, DefaultConfigFile, "sources:\n  telegram:\n    channels: [\"@ch\"]\n"+extra)
+			if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "dedup.recurring") {
+				t.Errorf("error = %v, want dedup.recurring error", err)
+			}
+		})
 	}
 }
 
-func TestLoadTaste_InvalidThresholds(t *testing.T) {
+func TestLoad_PrivacySnippetLength(t *testing.T) {
 	dir := t.TempDir()
-	path := writeTestYAML(t, dir, "taste.yaml", `
-thresholds:
-  read_now: 3
-  skim: 7
-  ignore: 0
-`)
+	writeTestYAML(t, dir, DefaultConfigFile, "sources:\n  telegram:\n    channels: [\"@ch\"]\n")
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Privacy.SnippetLength != DefaultSnippetLength {
+		t.Errorf("privacy.snippet_length = %d, want %d", cfg.Privacy.SnippetLength, DefaultSnippetLength)
+	}
 
-	_, err := LoadTaste(path)
-	if err == nil {
-		t.Fatal("expected error for invalid thresholds")
+	writeTestYAML(t, dir, DefaultConfigFile, "sources:\n  telegram:\n    channels: [\"@ch\"]\nprivacy:\n  snippet_length: -1\n")
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "privacy.snippet_length") {
+		t.Errorf("error = %v, want privacy.snippet_length error", err)
 	}
-	if want := "read_now (3) must be greater than skim (7)"; !strings.Contains(err.Error(), want) {
-		t.Errorf("error = %q, want containing %q", err, want)
+}
+
+func TestLoad_DigestStillUnread(t *testing.T) {
+	dir := t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, "sources:\n  telegram:\n    channels: [\"@ch\"]\ndigest:\n  still_unread: 72h\n")
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Digest.StillUnread.Duration != 72*time.Hour {
+		t.Errorf("digest.still_unread = %v, want 72h", cfg.Digest.StillUnread.Duration)
+	}
+
+	writeTestYAML(t, dir, DefaultConfigFile, "sources:\n  telegram:\n    channels: [\"@ch\"]\ndigest:\n  still_unread: -1h\n")
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "digest.still_unread") {
+		t.Errorf("error = %v, want digest.still_unread", err)
 	}
 }
 
-func TestLoadTaste_InvalidThresholds_SkimLEIgnore(t *testing.T) {
+func TestLoad_DigestJSON(t *testing.T) {
 	dir := t.TempDir()
-	path := writeTestYAML(t, dir, "taste.yaml", `
-thresholds:
-  read_now: 7
-  skim: 0
-  ignore: 0
-`)
+	writeTestYAML(t, dir, DefaultConfigFile, "sources:\n  telegram:\n    channels: [\"@ch\"]\ndigest:\n  json:\n    escape_html: true\n    max_headline: 200\n    max_bullet: 120\n")
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if j := cfg.Digest.JSON; !j.EscapeHTML || j.MaxHeadline != 200 || j.MaxBullet != 120 {
+		t.Errorf("digest.json = %+v", j)
+	}
 
-	_, err := LoadTaste(path)
-	if err == nil {
-		t.Fatal("expected error for skim <= ignore")
+	writeTestYAML(t, dir, DefaultConfigFile, "sources:\n  telegram:\n    channels: [\"@ch\"]\ndigest:\n  json:\n    max_bullet: -1\n")
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "digest.json.max_bullet") {
+		t.Errorf("error = %v, want digest.json.max_bullet", err)
 	}
-	if want := "skim (0) must be greater than ignore (0)"; !strings.Contains(err.Error(), want) {
-		t.Errorf("error = %q, want containing %q", err, want)
+}
+
+func TestLoad_DigestExecutiveSummary(t *testing.T) {
+	dir := t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, "sources:\n  telegram:\n    channels: [\"@ch\"]\nsummarize:\n  mode: llm\ndigest:\n  executive_summary: true\n")
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !cfg.Digest.ExecutiveSummary {
+		t.Error("digest.executive_summary not loaded")
+	}
+
+	writeTestYAML(t, dir, DefaultConfigFile, "sources:\n  telegram:\n    channels: [\"@ch\"]\ndigest:\n  executive_summary: true\n")
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "digest.executive_summary") {
+		t.Errorf("error = %v, want digest.executive_summary", err)
 	}
 }
 
-func TestLoadTaste_MinimalValid(t *testing.T) {
+func TestLoad_SummarizeProducts(t *testing.T) {
 	dir := t.TempDir()
-	path := writeTestYAML(t, dir, "taste.yaml", `
-thresholds:
-  read_now: 7
-  skim: 3
-  ignore: 0
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+summarize:
+  products:
+    Microsoft: [Exchange, Windows]
 `)
-
-	tp, err := LoadTaste(path)
+	cfg, err := Load(dir)
 	if err != nil {
-		t.Fatalf("load taste: %v", err)
+		t.Fatalf("load: %v", err)
 	}
-	if tp.Weights.HighSignal != nil {
-		t.Errorf("expected nil high_signal, got %v", tp.Weights.HighSignal)
+	if got := cfg.Summarize.Products["Microsoft"]; len(got) != 2 || got[0] != "Exchange" {
+		t.Errorf("summarize.products = %v", cfg.Summarize.Products)
 	}
-	if len(tp.Rules) != 0 {
-		t.Errorf("expected 0 rules, got %d", len(tp.Rules))
+
+	writeTestYAML(t, dir, DefaultConfigFile, "sources:\n  telegram:\n    channels: [\"@ch\"]\nsummarize:\n  products:\n    Microsoft: [\"\"]\n")
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "summarize.products.Microsoft") {
+		t.Errorf("error = %v, want summarize.products.Microsoft", err)
 	}
 }
 
-func TestLoadTaste_EmptyPath(t *testing.T) {
-	_, err := LoadTaste("")
-	if err == nil {
-		t.Fatal("expected error for empty path")
-	}
-	if want := "taste profile path is required"; !strings.Contains(err.Error(), want) {
-		t.Errorf("error = %q, want containing %q", err, want)
+func TestLoad_InvalidClockSkewMode(t *testing.T) {
+	dir := t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+clock_skew:
+  mode: ignore
+`)
+
+	_, err := Load(dir)
+	if err == nil || !strings.Contains(err.Error(), "clock_skew.mode") {
+		t.Errorf("error = %v, want clock_skew.mode error", err)
 	}
 }
 
-func TestLoadTaste_FileNotFound(t *testing.T) {
-	_, err := LoadTaste(filepath.Join(t.TempDir(), "nope.yaml"))
+func TestLoad_InvalidSummarizeMode(t *testing.T) {
+	dir := t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+summarize:
+  mode: quantum
+`)
+
+	_, err := Load(dir)
+	if err == nil {
+		t.Fatal("expected error for invalid mode")
+	}
+	if want := "unknown mode"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %q, want containing %q", err, want)
+	}
+}
+
+func TestLoad_LLMProvider(t *testing.T) {
+	dir := t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+`)
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Summarize.LLM.Provider != DefaultLLMProvider {
+		t.Errorf("provider = %q, want %q", cfg.Summarize.LLM.Provider, DefaultLLMProvider)
+	}
+
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+summarize:
+  llm:
+    provider: anthropic
+`)
+	if cfg, err := Load(dir); err != nil || cfg.Summarize.LLM.Provider != "anthropic" {
+		t.Errorf("anthropic: provider = %v, %v", cfg, err)
+	}
+
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+summarize:
+  llm:
+    provider: gemini
+`)
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "summarize.llm.provider") {
+		t.Errorf("error = %v, want summarize.llm.provider", err)
+	}
+}
+
+func TestLoad_LLMOllama(t *testing.T) {
+	dir := t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+summarize:
+  llm:
+    provider: ollama
+`)
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	llm := cfg.Summarize.LLM
+	if llm.Model != DefaultOllamaLLMModel || llm.Timeout.Duration != DefaultOllamaLLMTimeout || llm.NeedsAPIKey() {
+		t.Errorf("llm = %+v, want ollama defaults and no key needed", llm)
+	}
+	if cfg.Summarize.LLM.Triage.Model != DefaultOllamaLLMModel {
+		t.Errorf("triage model = %q, want %q", cfg.Summarize.LLM.Triage.Model, DefaultOllamaLLMModel)
+	}
+
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+`)
+	if cfg, err := Load(dir); err != nil || cfg.Summarize.LLM.Timeout.Duration != DefaultLLMTimeout || !cfg.Summarize.LLM.NeedsAPIKey() {
+		t.Errorf("openai: llm = %+v, %v", cfg.Summarize.LLM, err)
+	}
+
+	for _, bad := range []string{"endpoint: localhost:11434", "timeout: -1s"} {
+		writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+summarize:
+  llm:
+    provider: ollama
+    `+bad+`
+`)
+		if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "summarize.llm.") {
+			t.Errorf("%s: error = %v, want summarize.llm", bad, err)
+		}
+	}
+}
+
+func TestLoad_LLMBudget(t *testing.T) {
+	dir := t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+summarize:
+  llm:
+    model: gpt-4.1-mini
+    prices:
+      gpt-4.1-mini: {input: 0.4, output: 1.6}
+    monthly_budget: 5
+`)
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cost, ok := cfg.Summarize.LLM.Cost("gpt-4.1-mini", 1_000_000, 500_000); !ok || cost != 1.2 {
+		t.Errorf("cost = %v, %v; want 1.2", cost, ok)
+	}
+	if _, ok := cfg.Summarize.LLM.Cost("gpt-4.1-nano", 1, 1); ok {
+		t.Error("cost of unpriced model: want false")
+	}
+
+	for name, llm := range map[string]string{
+		"unpriced model":  "model: gpt-4.1-mini\n    monthly_budget: 5",
+		"unpriced triage": "model: gpt-4.1-mini\n    monthly_budget: 5\n    prices: {gpt-4.1-mini: {input: 1}}\n    triage: {model: gpt-4.1-nano}\nchannels:\n  \"@ch\": {llm_triage: true}",
+		"negative budget": "monthly_budget: -1",
+		"negative price":  "prices: {gpt-4.1-mini: {input: -1}}",
+	} {
+		writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+summarize:
+  llm:
+    `+llm+`
+`)
+		if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "summarize.llm.") {
+			t.Errorf("%s: error = %v, want summarize.llm", name, err)
+		}
+	}
+}
+
+func TestLoad_LLMPrompt(t *testing.T) {
+	dir := t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+summarize:
+  llm:
+    prompt: Summarize in two bullets.
+`)
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := cfg.Summarize.LLM.Prompt.For("read_now", nil); got != "Summarize in two bullets." {
+		t.Errorf("plain string prompt = %q", got)
+	}
+
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+summarize:
+  llm:
+    prompt:
+      tiers:
+        skim: One bullet.
+      labels:
+        security: Extract affected versions and mitigations.
+`)
+	cfg, err = Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	p := cfg.Summarize.LLM.Prompt
+	for _, tc := range []struct {
+		tier   string
+		labels []string
+		want   string
+	}{
+		{"read_now", nil, ""},
+		{"skim", nil, "One bullet."},
+		{"skim", []string{"release", "security"}, "Extract affected versions and mitigations."},
+		{"read_now", []string{"security"}, "Extract affected versions and mitigations."},
+	} {
+		if got := p.For(tc.tier, tc.labels); got != tc.want {
+			t.Errorf("For(%s, %v) = %q, want %q", tc.tier, tc.labels, got, tc.want)
+		}
+	}
+}
+
+func TestLoad_FileNotFound(t *testing.T) {
+	_, err := Load(t.TempDir())
 	if err == nil {
 		t.Fatal("expected error for missing file")
 	}
-	if want := "read taste profile"; !strings.Contains(err.Error(), want) {
+	if want := "read config"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %q, want containing %q", err, want)
+	}
+}
+
+func TestLoad_InvalidYAML(t *testing.T) {
+	dir := t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, `{{{invalid`)
+
+	_, err := Load(dir)
+	if err == nil {
+		t.Fatal("expected error for malformed yaml")
+	}
+	if want := "parse config"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %q, want containing %q", err, want)
+	}
+}
+
+func TestLoad_EmptyDir(t *testing.T) {
+	_, err := Load("")
+	if err == nil {
+		t.Fatal("expected error for empty dir")
+	}
+	if want := "config dir is required"; !strings.Contains(err.Error(), want) {
 		t.Errorf("error = %q, want containing %q", err, want)
 	}
 }
+
+func TestLoad_EnvVarResolution(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("NP_TEST_KEY", "resolved-value")
+
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+summarize:
+  mode: llm
+  llm:
+    api_key_env: NP_TEST_KEY
+`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Summarize.LLM.APIKey != "resolved-value" {
+		t.Errorf("api_key = %q, want resolved-value", cfg.Summarize.LLM.APIKey)
+	}
+}
+
+func TestLoad_ServeToken(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("NP_TEST_API_TOKEN", "s3cret")
+
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+serve:
+  token: ignored
+  token_env: NP_TEST_API_TOKEN
+`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Serve.Token != "s3cret" {
+		t.Errorf("serve.token = %q, want the token_env value", cfg.Serve.Token)
+	}
+}
+
+func TestLoad_PublishTokens(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("NP_TEST_NOTION", "secret_n")
+	t.Setenv("NP_TEST_CONFLUENCE", "secret_c")
+
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+publish:
+  notion:
+    token_env: NP_TEST_NOTION
+    parent_page: 0123abcd
+  confluence:
+    base_url: https://example.atlassian.net/wiki
+    user: me@example.com
+    token_env: NP_TEST_CONFLUENCE
+    space: ENG
+`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if n := cfg.Publish.Notion; n.Token != "secret_n" || n.ParentPage != "0123abcd" {
+		t.Errorf("publish.notion = %+v", n)
+	}
+	if c := cfg.Publish.Confluence; c.Token != "secret_c" || c.Space != "ENG" || c.User != "me@example.com" {
+		t.Errorf("publish.confluence = %+v", c)
+	}
+}
+
+func TestLoad_MonitoringPingURL(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("NP_TEST_PING", "https://hc-ping.com/0b1c")
+
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+monitoring:
+  ping_url_env: NP_TEST_PING
+`)
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Monitoring.PingURL != "https://hc-ping.com/0b1c" {
+		t.Errorf("ping_url = %q", cfg.Monitoring.PingURL)
+	}
+
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+monitoring:
+  ping_url: hc-ping.com/0b1c
+`)
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "monitoring.ping_url") {
+		t.Errorf("err = %v, want monitoring.ping_url error", err)
+	}
+}
+
+func TestLoad_DeliveryEmail(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("NP_TEST_SMTP", "hunter2")
+
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+delivery:
+  email:
+    host: smtp.example.com
+    user: me
+    password_env: NP_TEST_SMTP
+    from: "noisepan <me@example.com>"
+    to: [me@example.com]
+    favicons: true
+    dark_mode: true
+`)
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	ec := cfg.Delivery.Email
+	if ec.Port != DefaultSMTPPort || ec.Password != "hunter2" || len(ec.To) != 1 || !ec.Favicons || !ec.DarkMode {
+		t.Errorf("delivery.email = %+v", ec)
+	}
+
+	tests := map[string]string{
+		"missing host":  "  email:\n    to: [me@example.com]\n",
+		"bad from":      "  email:\n    host: smtp.example.com\n    from: nobody\n    to: [me@example.com]\n",
+		"no recipients": "  email:\n    host: smtp.example.com\n    from: me@example.com\n",
+		"bad recipient": "  email:\n    host: smtp.example.com\n    from: me@example.com\n    to: [me@example.com, you]\n",
+		"bad port":      "  email:\n    host: smtp.example.com\n    port: 70000\n    from: me@example.com\n    to: [me@example.com]\n",
+	}
+	want := map[string]string{
+		"missing host":  "delivery.email.host",
+		"bad from":      "delivery.email.from",
+		"no recipients": "delivery.email.to",
+		"bad recipient": "delivery.email.to[1]",
+		"bad port":      "delivery.email.port",
+	}
+	for name, yaml := range tests {
+		t.Run(name, func(t *testing.T) {
+			writeTestYAML(t, dir, DefaultConfigFile, "sources:\n  telegram:\n    channels: [\"@ch\"]\ndelivery:\n"+yaml)
+			if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), want[name]) {
+				t.Errorf("err = %v, want %s", err, want[name])
+			}
+		})
+	}
+}
+
+func TestLoad_DeliveryTelegram(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("NP_TEST_BOT", "123:abc")
+
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+delivery:
+  telegram:
+    bot_token_env: NP_TEST_BOT
+    chat_id: "-100200"
+`)
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if tc := cfg.Delivery.Telegram; tc.BotToken != "123:abc" || tc.ChatID != "-100200" {
+		t.Errorf("delivery.telegram = %+v", tc)
+	}
+
+	writeTestYAML(t, dir, DefaultConfigFile, "sources:\n  telegram:\n    channels: [\"@ch\"]\ndelivery:\n  telegram:\n    bot_token_env: NP_TEST_BOT\n")
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "delivery.telegram.chat_id") {
+		t.Errorf("err = %v, want delivery.telegram.chat_id", err)
+	}
+}
+
+func TestLoad_DeliveryDiscord(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("NP_TEST_DISCORD", "https://discord.com/api/webhooks/1/token")
+
+	writeTestYAML(t, dir, DefaultConfigFile, "sources:\n  telegram:\n    channels: [\"@ch\"]\ndelivery:\n  discord:\n    webhook_env: NP_TEST_DISCORD\n")
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Delivery.Discord.Webhook != "https://discord.com/api/webhooks/1/token" {
+		t.Errorf("delivery.discord.webhook = %q", cfg.Delivery.Discord.Webhook)
+	}
+
+	t.Setenv("NP_TEST_DISCORD", "http://discord.com/api/webhooks/1/token")
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "delivery.discord.webhook") {
+		t.Errorf("err = %v, want delivery.discord.webhook", err)
+	} else if strings.Contains(err.Error(), "token") {
+		t.Errorf("error leaks the webhook: %v", err)
+	}
+}
+
+func TestLoad_EnvVarMissing(t *testing.T) {
+	dir := t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    api_id_env: NONEXISTENT_VAR_12345
+    channels: ["@ch"]
+`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Sources.Telegram.APIID != "" {
+		t.Errorf("api_id = %q, want empty", cfg.Sources.Telegram.APIID)
+	}
+}
+
+// --- LoadTaste tests ---
+
+func TestLoadTaste_Full(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestYAML(t, dir, "taste.yaml", `
+weights:
+  high_signal:
+    "cve": 5
+    "kubernetes": 3
+  low_signal:
+    "hiring": -3
+    "webinar": -4
+labels:
+  critical:
+    - "cve"
+    - "zero-day"
+  ops:
+    - "kubernetes"
+rules:
+  - if:
+      contains_any: ["expired", "certificate"]
+    then:
+      score_add: 4
+      labels: ["ops", "certs"]
+  - if:
+      contains_any: ["webinar"]
+    then:
+      score_add: -6
+      labels: ["noise"]
+thresholds:
+  read_now: 7
+  skim: 3
+  ignore: 0
+`)
+
+	tp, err := LoadTaste(path)
+	if err != nil {
+		t.Fatalf("load taste: %v", err)
+	}
+
+	// Weights
+	if tp.Weights.HighSignal["cve"] != 5 {
+		t.Errorf("high_signal[cve] = %d, want 5", tp.Weights.HighSignal["cve"])
+	}
+	if tp.Weights.LowSignal["webinar"] != -4 {
+		t.Errorf("low_signal[webinar] = %d, want -4", tp.Weights.LowSignal["webinar"])
+	}
+
+	// Labels
+	if len(tp.Labels["critical"]) != 2 {
+		t.Errorf("labels[critical] = %v", tp.Labels["critical"])
+	}
+
+	// Rules
+	if len(tp.Rules) != 2 {
+		t.Errorf("rules count = %d, want 2", len(tp.Rules))
+	}
+	if tp.Rules[0].Then.ScoreAdd != 4 {
+		t.Errorf("rule[0] score_add = %d, want 4", tp.Rules[0].Then.ScoreAdd)
+	}
+	if len(tp.Rules[0].If.ContainsAny) != 2 {
+		t.Errorf("rule[0] contains_any = %v", tp.Rules[0].If.ContainsAny)
+	}
+
+	// Thresholds
+	if tp.Thresholds.ReadNow != 7 {
+		t.Errorf("read_now = %d, want 7", tp.Thresholds.ReadNow)
+	}
+	if tp.Thresholds.Skim != 3 {
+		t.Errorf("skim = %d, want 3", tp.Thresholds.Skim)
+	}
+}
+
+func TestLoadTaste_Interests(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestYAML(t, dir, "taste.yaml", `
+thresholds:
+  read_now: 7
+  skim: 3
+  ignore: 0
+interests:
+  - description: platform engineering incidents
+  - description: crypto price talk
+    points: -4
+    min_similarity: 0.6
+`)
+
+	tp, err := LoadTaste(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	want := []Interest{
+		{Description: "platform engineering incidents", Points: DefaultInterestPoints, MinSimilarity: DefaultInterestMinSimilarity},
+		{Description: "crypto price talk", Points: -4, MinSimilarity: 0.6},
+	}
+	if !slices.Equal(tp.Interests, want) {
+		t.Errorf("interests = %+v, want %+v", tp.Interests, want)
+	}
+
+	for name, extra := range map[string]string{
+		"no description":   "interests:\n  - points: 2\n",
+		"similarity above": "interests:\n  - description: x\n    min_similarity: 1.5\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := writeTestYAML(t, t.TempDir(), "taste.yaml", "thresholds:\n  read_now: 7\n  skim: 3\n  ignore: 0\n"+extra)
+			if _, err := LoadTaste(path); err == nil || !strings.Contains(err.Error(), "interests[0]") {
+				t.Errorf("error = %v, want interests[0] error", err)
+			}
+		})
+	}
+}
+
+func TestLoadTaste_Classifier(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestYAML(t, dir, "taste.yaml", `
+thresholds:
+  read_now: 7
+  skim: 3
+  ignore: 0
+classifier:
+  enabled: true
+`)
+
+	tp, err := LoadTaste(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !tp.Classifier.Enabled || tp.Classifier.MaxPoints != DefaultClassifierMaxPoints {
+		t.Errorf("classifier = %+v, want enabled with default max_points", tp.Classifier)
+	}
+
+	path = writeTestYAML(t, dir, "taste.yaml", `
+thresholds:
+  read_now: 7
+  skim: 3
+  ignore: 0
+classifier:
+  max_points: -1
+`)
+	if _, err := LoadTaste(path); err == nil || !strings.Contains(err.Error(), "classifier.max_points") {
+		t.Errorf("error = %v, want classifier.max_points", err)
+	}
+}
+
+func TestLoadTaste_RuleCooldown(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestYAML(t, dir, "taste.yaml", `
+rules:
+  - if:
+      contains_any: ["certificate expired"]
+    then:
+      score_add: 4
+    cooldown: 168h
+thresholds:
+  read_now: 7
+  skim: 3
+  ignore: 0
+`)
+
+	tp, err := LoadTaste(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(tp.Rules) != 1 || tp.Rules[0].Cooldown.Duration != 168*time.Hour {
+		t.Errorf("rules = %+v, want a 168h cooldown", tp.Rules)
+	}
+
+	path = writeTestYAML(t, dir, "taste.yaml", `
+rules:
+  - if:
+      contains_any: ["x"]
+    cooldown: -1h
+thresholds:
+  read_now: 7
+  skim: 3
+  ignore: 0
+`)
+	if _, err := LoadTaste(path); err == nil || !strings.Contains(err.Error(), "rules[0].cooldown") {
+		t.Errorf("error = %v, want rules[0].cooldown", err)
+	}
+}
+
+func TestLoadTaste_Channels(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestYAML(t, dir, "taste.yaml", `
+channels:
+  rss:
+    score_add: 1
+  telegram/@vendor_channel:
+    score_add: -3
+    labels: ["vendor"]
+  telegram/@security:
+    tier: read_now
+thresholds:
+  read_now: 7
+  skim: 3
+  ignore: 0
+`)
+	tp, err := LoadTaste(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if m := tp.Channels["telegram/@vendor_channel"]; m.ScoreAdd != -3 || !slices.Equal(m.Labels, []string{"vendor"}) {
+		t.Errorf("vendor modifier = %+v", m)
+	}
+	if tp.Channels["telegram/@security"].Tier != "read_now" || tp.Channels["rss"].ScoreAdd != 1 {
+		t.Errorf("channels = %+v", tp.Channels)
+	}
+
+	for _, tc := range []struct{ channels, want string }{
+		{"  telegram/@x:\n    tier: weekend\n", "channels.telegram/@x.tier"},
+		{"  telegram/:\n    score_add: 1\n", "channels.telegram/"},
+		{"  /@x:\n    score_add: 1\n", "channels./@x"},
+	} {
+		path := writeTestYAML(t, dir, "taste.yaml", "channels:\n"+tc.channels+"thresholds:\n  read_now: 7\n  skim: 3\n  ignore: 0\n")
+		if _, err := LoadTaste(path); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("error = %v, want %s", err, tc.want)
+		}
+	}
+}
+
+func TestLoadTaste_Authors(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestYAML(t, dir, "taste.yaml", `
+authors:
+  tptacek:
+    score_add: 3
+  reddit/spam_account:
+    tier: ignore
+thresholds:
+  read_now: 7
+  skim: 3
+  ignore: 0
+`)
+	tp, err := LoadTaste(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if tp.Authors["tptacek"].ScoreAdd != 3 || tp.Authors["reddit/spam_account"].Tier != "ignore" {
+		t.Errorf("authors = %+v", tp.Authors)
+	}
+
+	path = writeTestYAML(t, dir, "taste.yaml", "authors:\n  reddit/:\n    score_add: 1\nthresholds:\n  read_now: 7\n  skim: 3\n  ignore: 0\n")
+	if _, err := LoadTaste(path); err == nil || !strings.Contains(err.Error(), "authors.reddit/") {
+		t.Errorf("error = %v, want authors.reddit/", err)
+	}
+	path = writeTestYAML(t, dir, "taste.yaml", "authors:\n  x:\n    tier: later\nthresholds:\n  read_now: 7\n  skim: 3\n  ignore: 0\n")
+	if _, err := LoadTaste(path); err == nil || !strings.Contains(err.Error(), "authors.x.tier") {
+		t.Errorf("error = %v, want authors.x.tier", err)
+	}
+}
+
+func TestLoadTaste_Decay(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestYAML(t, dir, "taste.yaml", `
+decay:
+  half_life: 72h
+  tiers:
+    skim: 24h
+  sources:
+    github: 0s
+thresholds:
+  read_now: 7
+  skim: 3
+  ignore: 0
+`)
+	tp, err := LoadTaste(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	for _, tc := range []struct {
+		source, tier string
+		want         time.Duration
+	}{
+		{"rss", "read_now", 72 * time.Hour},
+		{"rss", "skim", 24 * time.Hour},
+		{"github", "skim", 0},
+	} {
+		if got := tp.Decay.HalfLifeFor(tc.source, tc.tier); got != tc.want {
+			t.Errorf("HalfLifeFor(%s, %s) = %v, want %v", tc.source, tc.tier, got, tc.want)
+		}
+	}
+
+	for _, tc := range []struct{ decay, want string }{
+		{"  half_life: -1h\n", "decay.half_life"},
+		{"  tiers:\n    later: 1h\n", "decay.tiers.later"},
+		{"  sources:\n    rss: -2h\n", "decay.sources.rss"},
+	} {
+		path := writeTestYAML(t, dir, "taste.yaml", "decay:\n"+tc.decay+"thresholds:\n  read_now: 7\n  skim: 3\n  ignore: 0\n")
+		if _, err := LoadTaste(path); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("error = %v, want %s", err, tc.want)
+		}
+	}
+}
+
+func TestLoadTaste_LabelTiers(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestYAML(t, dir, "taste.yaml", `
+label_tiers:
+  critical:
+    min_tier: read_now
+  noise:
+    max_tier: skim
+thresholds:
+  read_now: 7
+  skim: 3
+  ignore: 0
+`)
+	tp, err := LoadTaste(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if tp.LabelTiers["critical"].MinTier != "read_now" || tp.LabelTiers["noise"].MaxTier != "skim" {
+		t.Errorf("label_tiers = %+v", tp.LabelTiers)
+	}
+
+	for _, tc := range []struct{ tiers, want string }{
+		{"  x: {}\n", "label_tiers.x: needs"},
+		{"  x:\n    min_tier: urgent\n", "label_tiers.x.min_tier"},
+		{"  x:\n    max_tier: urgent\n", "label_tiers.x.max_tier"},
+		{"  x:\n    min_tier: skim\n    max_tier: ignore\n", "label_tiers.x: min_tier skim is above max_tier ignore"},
+	} {
+		path := writeTestYAML(t, dir, "taste.yaml", "label_tiers:\n"+tc.tiers+"thresholds:\n  read_now: 7\n  skim: 3\n  ignore: 0\n")
+		if _, err := LoadTaste(path); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("error = %v, want %s", err, tc.want)
+		}
+	}
+}
+
+func TestLoadTaste_Match(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestYAML(t, dir, "taste.yaml", `
+weights:
+  high_signal:
+    "go": 2
+    "AWS": 3
+rules:
+  - if:
+      contains_any: ["deprecated"]
+    then:
+      score_add: 1
+match:
+  word_boundary: true
+  keywords:
+    "AWS":
+      case_sensitive: true
+    "deprecated":
+      word_boundary: false
+      stem: true
+thresholds:
+  read_now: 7
+  skim: 3
+  ignore: 0
+`)
+	tp, err := LoadTaste(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := tp.Match.For("go"); got != (KeywordMatch{WordBoundary: true}) {
+		t.Errorf("go = %+v, want the global word_boundary", got)
+	}
+	if got := tp.Match.For("AWS"); got != (KeywordMatch{WordBoundary: true, CaseSensitive: true}) {
+		t.Errorf("AWS = %+v", got)
+	}
+	if got := tp.Match.For("deprecated"); got != (KeywordMatch{Stem: true}) {
+		t.Errorf("deprecated = %+v", got)
+	}
+
+	path = writeTestYAML(t, dir, "taste.yaml", "match:\n  keywords:\n    \"gopher\":\n      stem: true\nthresholds:\n  read_now: 7\n  skim: 3\n  ignore: 0\n")
+	if _, err := LoadTaste(path); err == nil || !strings.Contains(err.Error(), "match.keywords.gopher") {
+		t.Errorf("error = %v, want match.keywords.gopher", err)
+	}
+}
+
+func TestLoadTaste_RuleConditions(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestYAML(t, dir, "taste.yaml", `
+rules:
+  - if:
+      contains_all: ["kubernetes", "deprecation"]
+      not_contains_any: ["webinar"]
+    then:
+      score_add: 4
+thresholds:
+  read_now: 7
+  skim: 3
+  ignore: 0
+`)
+	tp, err := LoadTaste(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	cond := tp.Rules[0].If
+	if !slices.Equal(cond.ContainsAll, []string{"kubernetes", "deprecation"}) || !slices.Equal(cond.NotContainsAny, []string{"webinar"}) {
+		t.Errorf("condition = %+v", cond)
+	}
+
+	path = writeTestYAML(t, dir, "taste.yaml", `
+rules:
+  - if:
+      not_contains_any: ["webinar"]
+thresholds:
+  read_now: 7
+  skim: 3
+  ignore: 0
+`)
+	if _, err := LoadTaste(path); err == nil || !strings.Contains(err.Error(), "rules[0].if") {
+		t.Errorf("error = %v, want rules[0].if", err)
+	}
+
+	path = writeTestYAML(t, dir, "taste.yaml", `
+rules:
+  - if:
+      has_entity: [cve, product]
+      not_contains_any: ["webinar"]
+    then:
+      score_add: 3
+  - if:
+      has_entity: [cves]
+thresholds:
+  read_now: 7
+  skim: 3
+  ignore: 0
+`)
+	if _, err := LoadTaste(path); err == nil || !strings.Contains(err.Error(), `rules[1].if.has_entity: unknown kind "cves"`) {
+		t.Errorf("error = %v, want rules[1].if.has_entity", err)
+	}
+}
+
+func TestLoadTaste_InvalidThresholds(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestYAML(t, dir, "taste.yaml", `
+thresholds:
+  read_now: 3
+  skim: 7
+  ignore: 0
+`)
+
+	_, err := LoadTaste(path)
+	if err == nil {
+		t.Fatal("expected error for invalid thresholds")
+	}
+	if want := "read_now (3) must be greater than skim (7)"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %q, want containing %q", err, want)
+	}
+}
+
+func TestLoadTaste_InvalidThresholds_SkimLEIgnore(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestYAML(t, dir, "taste.yaml", `
+thresholds:
+  read_now: 7
+  skim: 0
+  ignore: 0
+`)
+
+	_, err := LoadTaste(path)
+	if err == nil {
+		t.Fatal("expected error for skim <= ignore")
+	}
+	if want := "skim (0) must be greater than ignore (0)"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %q, want containing %q", err, want)
+	}
+}
+
+func TestLoadTaste_MinimalValid(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestYAML(t, dir, "taste.yaml", `
+thresholds:
+  read_now: 7
+  skim: 3
+  ignore: 0
+`)
+
+	tp, err := LoadTaste(path)
+	if err != nil {
+		t.Fatalf("load taste: %v", err)
+	}
+	if tp.Weights.HighSignal != nil {
+		t.Errorf("expected nil high_signal, got %v", tp.Weights.HighSignal)
+	}
+	if len(tp.Rules) != 0 {
+		t.Errorf("expected 0 rules, got %d", len(tp.Rules))
+	}
+}
+
+func TestLoadTaste_Tiers(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestYAML(t, dir, "taste.yaml", `
+tiers:
+  - name: read_now
+    min_score: 8
+  - name: today
+    min_score: 5
+  - name: weekend
+    min_score: 2
+  - name: ignore
+`)
+
+	tp, err := LoadTaste(path)
+	if err != nil {
+		t.Fatalf("load taste: %v", err)
+	}
+	want := []Tier{{"read_now", 8}, {"today", 5}, {"weekend", 2}, {"ignore", 0}}
+	if got := tp.TierSet(); !slices.Equal(got, want) {
+		t.Errorf("tiers = %v, want %v", got, want)
+	}
+	if want := (Thresholds{ReadNow: 8, Skim: 2, Ignore: 1}); tp.Thresholds != want {
+		t.Errorf("derived thresholds = %+v, want %+v", tp.Thresholds, want)
+	}
+}
+
+func TestTierSet_Default(t *testing.T) {
+	tp := &TasteProfile{Thresholds: Thresholds{ReadNow: 7, Skim: 3, Ignore: 0}}
+	want := []Tier{{"read_now", 7}, {"skim", 3}, {"ignore", 0}}
+	if got := tp.TierSet(); !slices.Equal(got, want) {
+		t.Errorf("tiers = %v, want %v", got, want)
+	}
+}
+
+func TestLoadTaste_InvalidTiers(t *testing.T) {
+	for _, tt := range []struct {
+		name, yaml, want string
+	}{
+		{"single", "tiers:\n  - name: read_now\n", "at least read_now and ignore"},
+		{"first", "tiers:\n  - name: urgent\n  - name: ignore\n", `tiers[0].name: must be read_now, not "urgent"`},
+		{"last", "tiers:\n  - name: read_now\n  - name: later\n", `tiers[1].name: must be ignore, not "later"`},
+		{"name", "tiers:\n  - name: read_now\n  - name: Later\n    min_score: -1\n  - name: ignore\n", `tiers[1].name: "Later" must be lowercase`},
+		{"duplicate", "tiers:\n  - name: read_now\n    min_score: 5\n  - name: read_now\n  - name: ignore\n", `tiers[1].name: duplicate tier "read_now"`},
+		{"order", "tiers:\n  - name: read_now\n    min_score: 5\n  - name: today\n    min_score: 5\n  - name: ignore\n", "tiers[1].min_score: 5 must be below read_now (5)"},
+		{"thresholds", "thresholds:\n  read_now: 7\ntiers:\n  - name: read_now\n  - name: ignore\n", "tiers replaces thresholds"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestYAML(t, t.TempDir(), "taste.yaml", tt.yaml)
+			_, err := LoadTaste(path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadTaste_EmptyPath(t *testing.T) {
+	_, err := LoadTaste("")
+	if err == nil {
+		t.Fatal("expected error for empty path")
+	}
+	if want := "taste profile path is required"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %q, want containing %q", err, want)
+	}
+}
+
+func TestLoadTaste_FileNotFound(t *testing.T) {
+	_, err := LoadTaste(filepath.Join(t.TempDir(), "nope.yaml"))
+	if err == nil {
+		t.Fatal("expected error for missing file")
+	}
+	if want := "read taste profile"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %q, want containing %q", err, want)
+	}
+}
+
+func TestSetTasteWeight(t *testing.T) {
+	doc := `# my taste
+weights:
+  high_signal:
+    "cve": 5
+    terraform: 3   # infra
+  low_signal:
+    "cve": -1
+    "webinar": -4
+rules:
+  - if:
+      contains_any: ["terraform"]
+    then:
+      score_add: 2
+thresholds:
+  read_now: 7
+`
+	got, ok := SetTasteWeight([]byte(doc), "high_signal", "terraform", 1)
+	if !ok {
+		t.Fatal("terraform not found")
+	}
+	if want := "    terraform: 1   # infra\n"; !strings.Contains(string(got), want) {
+		t.Errorf("missing %q in:\n%s", want, got)
+	}
+
+	got, ok = SetTasteWeight(got, "low_signal", "cve", -3)
+	if !ok {
+		t.Fatal("low_signal cve not found")
+	}
+	if !strings.Contains(string(got), `    "cve": 5`) || !strings.Contains(string(got), `    "cve": -3`) {
+		t.Errorf("wrong cve line changed:\n%s", got)
+	}
+
+	var tp TasteProfile
+	if err := yaml.Unmarshal(got, &tp); err != nil {
+		t.Fatalf("patched yaml invalid: %v", err)
+	}
+	if tp.Weights.HighSignal["terraform"] != 1 || tp.Rules[0].Then.ScoreAdd != 2 {
+		t.Errorf("parsed = %+v", tp)
+	}
+
+	if _, ok := SetTasteWeight([]byte(doc), "high_signal", "kubernetes", 1); ok {
+		t.Error("missing keyword reported as found")
+	}
+}
+
+func TestLoad_NetworkConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  rss:
+    feeds: ["https://example.com/feed.xml"]
+network:
+  http_proxy: "http://proxy.corp:3128"
+  no_proxy: ["intranet.corp"]
+  ca_bundle: /etc/ssl/corp.pem
+  insecure_skip_verify: ["legacy.corp"]
+`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Network.HTTPProxy != "http://proxy.corp:3128" {
+		t.Errorf("http_proxy = %q", cfg.Network.HTTPProxy)
+	}
+	if len(cfg.Network.NoProxy) != 1 || cfg.Network.NoProxy[0] != "intranet.corp" {
+		t.Errorf("no_proxy = %v", cfg.Network.NoProxy)
+	}
+	if cfg.Network.CABundle != "/etc/ssl/corp.pem" {
+		t.Errorf("ca_bundle = %q", cfg.Network.CABundle)
+	}
+	if len(cfg.Network.InsecureSkipVerify) != 1 || cfg.Network.InsecureSkipVerify[0] != "legacy.corp" {
+		t.Errorf("insecure_skip_verify = %v", cfg.Network.InsecureSkipVerify)
+	}
+}
+
+func TestLoad_InvalidSOCKS5Proxy(t *testing.T) {
+	dir := t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  rss:
+    feeds: ["https://example.com/feed.xml"]
+network:
+  socks5_proxy: "http://127.0.0.1:1080"
+`)
+
+	_, err := Load(dir)
+	if err == nil {
+		t.Fatal("expected error for non-socks5 scheme")
+	}
+	if want := "network.socks5_proxy"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %q, want containing %q", err, want)
+	}
+}
+
+func TestLoad_FetchConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  rss:
+    feeds: ["https://example.com/feed.xml"]
+    timeout: 90s
+    max_retries: 0
+    backoff: 5s
+    delay: 10s
+    workers: 2
+  hn:
+    min_points: 100
+    timeout: 2m
+`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	rss := cfg.Sources.RSS.FetchConfig
+	if rss.Timeout.Duration != 90*time.Second {
+		t.Errorf("rss timeout = %v, want 90s", rss.Timeout.Duration)
+	}
+	if rss.MaxRetries == nil || *rss.MaxRetries != 0 {
+		t.Errorf("rss max_retries = %v, want explicit 0", rss.MaxRetries)
+	}
+	if rss.Backoff.Duration != 5*time.Second || rss.Delay.Duration != 10*time.Second || rss.Workers != 2 {
+		t.Errorf("rss fetch = %+v", rss)
+	}
+	if cfg.Sources.HN.Timeout.Duration != 2*time.Minute {
+		t.Errorf("hn timeout = %v, want 2m", cfg.Sources.HN.Timeout.Duration)
+	}
+	if cfg.Sources.HN.MaxRetries != nil {
+		t.Errorf("hn max_retries = %v, want unset", *cfg.Sources.HN.MaxRetries)
+	}
+}
+
+func TestLoad_NegativeFetchConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  reddit:
+    subreddits: ["devops"]
+    max_retries: -1
+`)
+
+	_, err := Load(dir)
+	if err == nil {
+		t.Fatal("expected error for negative max_retries")
+	}
+	if want := "sources.reddit: max_retries"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %q, want containing %q", err, want)
+	}
+}
+
+func TestLoad_FetchTimezone(t *testing.T) {
+	dir := t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  rss:
+    feeds: ["https://example.com/feed.xml"]
+    timezone: "America/New_York"
+`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	loc := cfg.Sources.RSS.Location()
+	if loc == nil || loc.String() != "America/New_York" {
+		t.Errorf("rss location = %v, want America/New_York", loc)
+	}
+	if cfg.Sources.HN.Location() != nil {
+		t.Error("unset timezone should return nil location")
+	}
+
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  rss:
+    feeds: ["https://example.com/feed.xml"]
+    timezone: "Not/AZone"
+`)
+	_, err = Load(dir)
+	if err == nil {
+		t.Fatal("expected error for invalid timezone")
+	}
+	if want := "sources.rss: timezone"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %q, want containing %q", err, want)
+	}
+}
+
+func TestLoad_ChannelTriage(t *testing.T) {
+	dir := t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  hn:
+    min_points: 100
+summarize:
+  llm:
+    model: gpt-4.1-mini
+channels:
+  "Hacker News":
+    llm_triage: true
+`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !cfg.Channels["Hacker News"].LLMTriage {
+		t.Errorf("channels = %+v, want Hacker News llm_triage", cfg.Channels)
+	}
+	tc := cfg.Summarize.LLM.Triage
+	if tc.Model != "gpt-4.1-mini" {
+		t.Errorf("triage model = %q, want fallback to llm model", tc.Model)
+	}
+	if tc.Interval.Duration != DefaultTriageInterval || tc.MaxPerRun != DefaultTriageMaxPerRun {
+		t.Errorf("triage defaults = %+v", tc)
+	}
+}
+
+func TestLoad_Boilerplate(t *testing.T) {
+	dir := t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+`)
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	bc := cfg.Boilerplate
+	if bc.Disabled || bc.MinShare != DefaultBoilerplateMinShare || bc.MinPosts != DefaultBoilerplateMinPosts || bc.Sample != DefaultBoilerplateSample {
+		t.Errorf("defaults = %+v", bc)
+	}
+
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+boilerplate:
+  min_posts: 20
+  sample: 10
+`)
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "boilerplate.sample") {
+		t.Errorf("error = %v, want boilerplate.sample", err)
+	}
+}
+
+func TestLoad_Hooks(t *testing.T) {
+	dir := t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+hooks:
+  pre_score: ./enrich.sh
+  post_digest: ./notify.sh
+`)
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	h := cfg.Hooks
+	if h.PreScore != filepath.FromSlash("./enrich.sh") || h.PostDigest != filepath.FromSlash("./notify.sh") || h.Timeout.Duration != DefaultHookTimeout {
+		t.Errorf("hooks = %+v", h)
+	}
+
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+hooks:
+  timeout: -1s
+`)
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "hooks.timeout") {
+		t.Errorf("error = %v, want hooks.timeout", err)
+	}
+}
+
+func TestLoad_CachePath(t *testing.T) {
+	dir := t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+storage:
+  path: /var/lib/noisepan/noisepan.db
+`)
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if want := filepath.FromSlash("/var/lib/noisepan/cache.db"); cfg.Cache.Path != want {
+		t.Errorf("cache path = %q, want %q", cfg.Cache.Path, want)
+	}
+
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+cache:
+  path: /tmp/lookups.db
+`)
+	cfg, err = Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if want := filepath.FromSlash("/tmp/lookups.db"); cfg.Cache.Path != want {
+		t.Errorf("cache path = %q, want %q", cfg.Cache.Path, want)
+	}
+}
+
+func TestLoad_ExpandsDigestTemplate(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+digest:
+  template: ~/.noisepan/digest.tmpl
+`)
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if want := filepath.Join(home, ".noisepan", "digest.tmpl"); cfg.Digest.Template != want {
+		t.Errorf("template = %q, want %q", cfg.Digest.Template, want)
+	}
+}
+
+func TestExpandPath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"~", home},
+		{"~/.noisepan/session", filepath.Join(home, ".noisepan", "session")},
+		{"relative/noisepan.db", filepath.Join("relative", "noisepan.db")},
+		{"~other/x", filepath.FromSlash("~other/x")},
+	}
+	for _, tt := range tests {
+		if got := ExpandPath(tt.in); got != tt.want {
+			t.Errorf("ExpandPath(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLoad_Retention(t *testing.T) {
+	dir := t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+storage:
+  retain_days: 30
+  retention:
+    - channel: "Security Advisories"
+      retain_days: 365
+    - source: reddit
+      retain_days: 14
+`)
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	want := []RetentionRule{
+		{Channel: "Security Advisories", RetainDays: 365},
+		{Source: "reddit", RetainDays: 14},
+	}
+	if !reflect.DeepEqual(cfg.Storage.Retention, want) {
+		t.Errorf("retention = %+v, want %+v", cfg.Storage.Retention, want)
+	}
+
+	for _, tc := range []struct{ rule, want string }{
+		{"{retain_days: 7}", "source or channel is required"},
+		{"{source: rss, retain_days: 0}", "retain_days: must be positive"},
+	} {
+		writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  telegram:
+    channels: ["@ch"]
+storage:
+  retention: [`+tc.rule+`]
+`)
+		if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: error = %v, want %q", tc.rule, err, tc.want)
+		}
+	}
+}
+
+func TestLoad_HNAPI(t *testing.T) {
+	dir := t.TempDir()
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  hn:
+    min_points: 100
+    api: algolia
+`)
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Sources.HN.API != "algolia" {
+		t.Errorf("api = %q, want algolia", cfg.Sources.HN.API)
+	}
+
+	writeTestYAML(t, dir, DefaultConfigFile, `
+sources:
+  hn:
+    min_points: 100
+    api: bigquery
+`)
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "sources.hn.api") {
+		t.Errorf("error = %v, want sources.hn.api", err)
+	}
+}
+
+func TestLoadTaste_Languages(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestYAML(t, dir, "taste.yaml", `
+weights:
+  high_signal:
+    "cve": 5
+languages:
+  ru:
+    weights:
+      high_signal:
+        "уязвимость": 5
+    rules:
+      - if:
+          contains_any: ["релиз"]
+        then:
+          score_add: 2
+thresholds:
+  read_now: 7
+  skim: 3
+  ignore: 0
+`)
+	tp, err := LoadTaste(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	ru, ok := tp.Languages["ru"]
+	if !ok {
+		t.Fatal("languages.ru missing")
+	}
+	if ru.Weights.HighSignal["уязвимость"] != 5 || len(ru.Rules) != 1 || ru.Rules[0].Then.ScoreAdd != 2 {
+		t.Errorf("languages.ru = %+v", ru)
+	}
+
+	path = writeTestYAML(t, dir, "taste.yaml", "languages:\n  russian:\n    weights:\n      high_signal:\n        \"x\": 1\nthresholds:\n  read_now: 7\n  skim: 3\n  ignore: 0\n")
+	if _, err := LoadTaste(path); err == nil || !strings.Contains(err.Error(), "languages.russian") {
+		t.Errorf("error = %v, want languages.russian", err)
+	}
+}
+
+func TestTasteSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestYAML(t, dir, "taste.yaml", `
+weights:
+  high_signal:
+    "cve": 5
+decay:
+  half_life: 72h
+match:
+  word_boundary: true
+  keywords:
+    "cve":
+      stem: true
+thresholds:
+  read_now: 7
+  skim: 3
+  ignore: 0
+`)
+	tp, err := LoadTaste(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	data, err := tp.Snapshot()
+	if err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	back, err := ParseTasteSnapshot(data)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if back.Hash() != tp.Hash() {
+		t.Errorf("hash changed across a snapshot: %s → %s\n%s", tp.Hash(), back.Hash(), data)
+	}
+	if back.Decay.HalfLife.Duration != 72*time.Hour || !back.Match.For("cve").Stem {
+		t.Errorf("snapshot lost settings:\n%s", data)
+	}
+
+	// Formatting does not change the hash; content does.
+	writeTestYAML(t, dir, "taste.yaml", "thresholds: {read_now: 7, skim: 3, ignore: 0}\ndecay: {half_life: 72h}\nmatch: {word_boundary: true, keywords: {cve: {stem: true}}}\nweights: {high_signal: {cve: 5}}\n")
+	if again, err := LoadTaste(path); err != nil || again.Hash() != tp.Hash() {
+		t.Errorf("reformatted hash = %v, %v; want %s", again, err, tp.Hash())
+	}
+	tp.Weights.HighSignal["cve"] = 6
+	if tp.Hash() == back.Hash() {
+		t.Error("hash unchanged after a weight change")
+	}
+}
@@ -1,11 +1,19 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/ppiankov/noisepan/internal/entities"
 	"gopkg.in/yaml.v3"
 )
 
@@ -14,6 +22,169 @@ type TasteProfile struct {
 	Labels     map[string][]string `yaml:"labels"`
 	Rules      []Rule              `yaml:"rules"`
 	Thresholds Thresholds          `yaml:"thresholds"`
+	Classifier ClassifierConfig    `yaml:"classifier"`
+	Interests  []Interest          `yaml:"interests"`
+
+	// Channels adjusts every post of a source ("rss") or of one channel
+	// ("telegram/@vendor_channel"), keyed by either.
+	Channels map[string]ScoreModifier `yaml:"channels"`
+	// Authors adjusts every post by an author, keyed by the name ("tptacek")
+	// or by source and name ("reddit/spam_account"), case-insensitively.
+	Authors map[string]ScoreModifier `yaml:"authors"`
+
+	Decay DecayConfig `yaml:"decay"`
+
+	// Languages adds weights and rules for posts in one language, keyed by
+	// ISO 639-1 code ("ru"), on top of the ones above.
+	Languages map[string]LanguageProfile `yaml:"languages"`
+
+	// Match sets how keywords and rule terms are found in post text.
+	Match MatchConfig `yaml:"match"`
+
+	// LabelTiers bounds the tier of posts carrying a label, whatever their
+	// score: "critical" at least read_now, "noise" at most skim.
+	LabelTiers map[string]LabelTier `yaml:"label_tiers"`
+
+	// Tiers, when set, replaces thresholds with an ordered tier set, highest
+	// first. It starts with read_now and ends with ignore, which summaries,
+	// verify, and noise suppression build on; the tiers between take the
+	// place of skim and are named freely.
+	Tiers []Tier `yaml:"tiers"`
+
+	// Products is summarize.products from config.yaml, the dictionary rules
+	// look up has_entity product and vendor in. It is not part of the
+	// taste file; commands that score set it.
+	Products map[string][]string `yaml:"-"`
+}
+
+// Tier is a named score band: a post falls in the first tier whose MinScore
+// it reaches. The last tier takes every post below the others, so its
+// MinScore is not used.
+type Tier struct {
+	Name     string `yaml:"name"`
+	MinScore int    `yaml:"min_score"`
+}
+
+// TierSet returns the tiers posts are ranked into, highest first: Tiers when
+// set, otherwise read_now, skim, and ignore from thresholds.
+func (tp *TasteProfile) TierSet() []Tier {
+	if len(tp.Tiers) > 0 {
+		return tp.Tiers
+	}
+	return []Tier{
+		{Name: "read_now", MinScore: tp.Thresholds.ReadNow},
+		{Name: "skim", MinScore: tp.Thresholds.Skim},
+		{Name: "ignore", MinScore: tp.Thresholds.Ignore},
+	}
+}
+
+// ClassifierConfig enables the model trained by "noisepan taste train". Its
+// probability adds between -MaxPoints and +MaxPoints to each post's score.
+type ClassifierConfig struct {
+	Enabled   bool `yaml:"enabled"`
+	MaxPoints int  `yaml:"max_points"`
+}
+
+// Interest describes a topic in plain words ("Postgres internals"). With
+// embed configured, posts whose embeddings are at least MinSimilarity close
+// to the description's gain Points, so relevant posts count even when they
+// use none of the keywords. Negative points push a topic down.
+type Interest struct {
+	Description   string  `yaml:"description"`
+	Points        int     `yaml:"points"`         // default 3
+	MinSimilarity float64 `yaml:"min_similarity"` // cosine similarity, default 0.5
+}
+
+// ScoreModifier is applied to every post of its channel or author after
+// keywords and rules: ScoreAdd is added, Labels are attached, and Tier, when
+// set, places the post in that tier whatever its score.
+type ScoreModifier struct {
+	ScoreAdd int      `yaml:"score_add"`
+	Labels   []string `yaml:"labels"`
+	Tier     string   `yaml:"tier"`
+}
+
+// LabelTier is the range of tiers a labeled post may land in. MinTier
+// lifts it to at least that tier, MaxTier holds it at or below.
+type LabelTier struct {
+	MinTier string `yaml:"min_tier"`
+	MaxTier string `yaml:"max_tier"`
+}
+
+// DecayConfig lowers positive scores as posts age, halving them every
+// half-life, so older posts give way to fresh ones in the digest. Sources
+// and Tiers override HalfLife per source or per tier the post scored into,
+// source first; a zero half-life turns decay off.
+type DecayConfig struct {
+	HalfLife Duration            `yaml:"half_life"`
+	Tiers    map[string]Duration `yaml:"tiers"`
+	Sources  map[string]Duration `yaml:"sources"`
+}
+
+// HalfLifeFor returns the half-life of a post of source scored into tier,
+// or 0 when its score does not decay.
+func (d DecayConfig) HalfLifeFor(source, tier string) time.Duration {
+	if h, ok := d.Sources[source]; ok {
+		return h.Duration
+	}
+	if h, ok := d.Tiers[tier]; ok {
+		return h.Duration
+	}
+	return d.HalfLife.Duration
+}
+
+// MatchConfig sets how keywords and rule terms match: by default anywhere
+// in the text, ignoring case, so "go" also matches "google". The options
+// apply to every term; Keywords overrides them per term, keyed as written
+// under weights or rules.
+type MatchConfig struct {
+	KeywordMatch `yaml:",inline"`
+	Keywords     map[string]KeywordOverride `yaml:"keywords"`
+}
+
+// KeywordMatch is how one term matches. WordBoundary needs the term to start
+// and end at word edges; Stem compares words after stripping English
+// suffixes ("deployed" matches "deploys") and implies word edges;
+// CaseSensitive matches the term's case as written.
+type KeywordMatch struct {
+	WordBoundary  bool `yaml:"word_boundary"`
+	Stem          bool `yaml:"stem"`
+	CaseSensitive bool `yaml:"case_sensitive"`
+}
+
+// KeywordOverride sets the options of one term; unset ones are taken from
+// the profile's match section.
+type KeywordOverride struct {
+	WordBoundary  *bool `yaml:"word_boundary"`
+	Stem          *bool `yaml:"stem"`
+	CaseSensitive *bool `yaml:"case_sensitive"`
+}
+
+// For returns how term matches: the profile's options with term's
+// overrides applied.
+func (m MatchConfig) For(term string) KeywordMatch {
+	km := m.KeywordMatch
+	o, ok := m.Keywords[term]
+	if !ok {
+		return km
+	}
+	if o.WordBoundary != nil {
+		km.WordBoundary = *o.WordBoundary
+	}
+	if o.Stem != nil {
+		km.Stem = *o.Stem
+	}
+	if o.CaseSensitive != nil {
+		km.CaseSensitive = *o.CaseSensitive
+	}
+	return km
+}
+
+// LanguageProfile holds the weights and rules that apply only to posts
+// detected in its language.
+type LanguageProfile struct {
+	Weights Weights `yaml:"weights"`
+	Rules   []Rule  `yaml:"rules"`
 }
 
 type Weights struct {
@@ -24,10 +195,28 @@ type Weights struct {
 type Rule struct {
 	If   RuleCondition `yaml:"if"`
 	Then RuleAction    `yaml:"then"`
+	// Cooldown, when set, lets the rule add points at most once per channel
+	// within the window, so a recurring bot message is not boosted daily.
+	Cooldown Duration `yaml:"cooldown"`
 }
 
+// RuleCondition matches a post containing any of ContainsAny and all of
+// ContainsAll, with any of the HasEntity kinds of entity found in it, each
+// part only when set, and none of NotContainsAny. At least one of
+// ContainsAny, ContainsAll and HasEntity must be set for a rule to fire.
 type RuleCondition struct {
-	ContainsAny []string `yaml:"contains_any"`
+	ContainsAny    []string `yaml:"contains_any"`
+	ContainsAll    []string `yaml:"contains_all"`
+	NotContainsAny []string `yaml:"not_contains_any"`
+	// HasEntity lists kinds of entity (entities.Kinds): product and vendor,
+	// from summarize.products, version, cve, ghsa, ip, and date.
+	HasEntity []string `yaml:"has_entity"`
+}
+
+// Terms returns the terms a post must contain for the condition to match:
+// ContainsAny, then ContainsAll.
+func (c RuleCondition) Terms() []string {
+	return append(append([]string(nil), c.ContainsAny...), c.ContainsAll...)
 }
 
 type RuleAction struct {
@@ -57,6 +246,35 @@ func LoadTaste(path string) (*TasteProfile, error) {
 		return nil, fmt.Errorf("parse taste profile: %w", err)
 	}
 
+	if tp.Classifier.MaxPoints == 0 {
+		tp.Classifier.MaxPoints = DefaultClassifierMaxPoints
+	}
+	for i := range tp.Interests {
+		if tp.Interests[i].Points == 0 {
+			tp.Interests[i].Points = DefaultInterestPoints
+		}
+		if tp.Interests[i].MinSimilarity == 0 {
+			tp.Interests[i].MinSimilarity = DefaultInterestMinSimilarity
+		}
+	}
+
+	if len(tp.Tiers) > 0 {
+		if tp.Thresholds != (Thresholds{}) {
+			return nil, errors.New("validate taste profile: tiers replaces thresholds; remove the thresholds section")
+		}
+		if err := validateTiers(tp.Tiers); err != nil {
+			return nil, fmt.Errorf("validate taste profile: %w", err)
+		}
+		// Thresholds stay meaningful for their other readers (LLM triage
+		// points, taste report): read_now's bar and the lowest one above
+		// ignore.
+		readNow, lowest := tp.Tiers[0].MinScore, tp.Tiers[0].MinScore-1
+		if n := len(tp.Tiers); n > 2 {
+			lowest = tp.Tiers[n-2].MinScore
+		}
+		tp.Thresholds = Thresholds{ReadNow: readNow, Skim: lowest, Ignore: lowest - 1}
+	}
+
 	if err := validateTaste(&tp); err != nil {
 		return nil, fmt.Errorf("validate taste profile: %w", err)
 	}
@@ -64,6 +282,43 @@ func LoadTaste(path string) (*TasteProfile, error) {
 	return &tp, nil
 }
 
+// Snapshot returns the profile as YAML, defaults applied, for
+// ParseTasteSnapshot to read back.
+func (tp *TasteProfile) Snapshot() ([]byte, error) {
+	data, err := yaml.Marshal(tp)
+	if err != nil {
+		return nil, fmt.Errorf("encode taste profile: %w", err)
+	}
+	return data, nil
+}
+
+// Hash identifies the profile's content: two profiles that score alike
+// from the same YAML have the same hash, whatever their formatting.
+func (tp *TasteProfile) Hash() string {
+	data, err := tp.Snapshot()
+	if err != nil {
+		return ""
+	}
+	// The products dictionary changes what has_entity rules match.
+	if len(tp.Products) > 0 && tp.hasEntityRules() {
+		for _, vendor := range slices.Sorted(maps.Keys(tp.Products)) {
+			data = fmt.Appendf(data, "\x00%s%q", vendor, tp.Products[vendor])
+		}
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// ParseTasteSnapshot reads a profile written by Snapshot. It was validated
+// when loaded, so it is not validated again.
+func ParseTasteSnapshot(data []byte) (*TasteProfile, error) {
+	var tp TasteProfile
+	if err := yaml.Unmarshal(data, &tp); err != nil {
+		return nil, fmt.Errorf("parse taste snapshot: %w", err)
+	}
+	return &tp, nil
+}
+
 func validateTaste(tp *TasteProfile) error {
 	if tp.Thresholds.ReadNow <= tp.Thresholds.Skim {
 		return fmt.Errorf("thresholds: read_now (%d) must be greater than skim (%d)",
@@ -73,5 +328,221 @@ func validateTaste(tp *TasteProfile) error {
 		return fmt.Errorf("thresholds: skim (%d) must be greater than ignore (%d)",
 			tp.Thresholds.Skim, tp.Thresholds.Ignore)
 	}
+	if err := validateRules("rules", tp.Rules); err != nil {
+		return err
+	}
+	for lang, lp := range tp.Languages {
+		if !languageCode.MatchString(lang) {
+			return fmt.Errorf("languages.%s: key must be an ISO 639-1 code such as ru or en", lang)
+		}
+		if err := validateRules("languages."+lang+".rules", lp.Rules); err != nil {
+			return err
+		}
+	}
+	if tp.Classifier.MaxPoints < 0 {
+		return errors.New("classifier.max_points: must not be negative")
+	}
+	for key, m := range tp.Channels {
+		if src, ch, found := strings.Cut(key, "/"); src == "" || (found && ch == "") {
+			return fmt.Errorf("channels.%s: key must be a source or source/channel", key)
+		}
+		if err := validateModifierTier(tp, "channels."+key, m); err != nil {
+			return err
+		}
+	}
+	for key, m := range tp.Authors {
+		if src, name, found := strings.Cut(key, "/"); src == "" || (found && name == "") {
+			return fmt.Errorf("authors.%s: key must be an author or source/author", key)
+		}
+		if err := validateModifierTier(tp, "authors."+key, m); err != nil {
+			return err
+		}
+	}
+	tierIndex := func(name string) int {
+		return slices.IndexFunc(tp.TierSet(), func(t Tier) bool { return t.Name == name })
+	}
+	for label, lt := range tp.LabelTiers {
+		if lt.MinTier == "" && lt.MaxTier == "" {
+			return fmt.Errorf("label_tiers.%s: needs min_tier or max_tier", label)
+		}
+		if lt.MinTier != "" && tierIndex(lt.MinTier) < 0 {
+			return fmt.Errorf("label_tiers.%s.min_tier: unknown tier %q", label, lt.MinTier)
+		}
+		if lt.MaxTier != "" && tierIndex(lt.MaxTier) < 0 {
+			return fmt.Errorf("label_tiers.%s.max_tier: unknown tier %q", label, lt.MaxTier)
+		}
+		if lt.MinTier != "" && lt.MaxTier != "" && tierIndex(lt.MinTier) < tierIndex(lt.MaxTier) {
+			return fmt.Errorf("label_tiers.%s: min_tier %s is above max_tier %s", label, lt.MinTier, lt.MaxTier)
+		}
+	}
+	if tp.Decay.HalfLife.Duration < 0 {
+		return errors.New("decay.half_life: must not be negative")
+	}
+	for tier, h := range tp.Decay.Tiers {
+		if !slices.ContainsFunc(tp.TierSet(), func(t Tier) bool { return t.Name == tier }) {
+			return fmt.Errorf("decay.tiers.%s: unknown tier", tier)
+		}
+		if h.Duration < 0 {
+			return fmt.Errorf("decay.tiers.%s: must not be negative", tier)
+		}
+	}
+	for src, h := range tp.Decay.Sources {
+		if h.Duration < 0 {
+			return fmt.Errorf("decay.sources.%s: must not be negative", src)
+		}
+	}
+	for term := range tp.Match.Keywords {
+		if !tp.hasTerm(term) {
+			return fmt.Errorf("match.keywords.%s: not a keyword or rule term of the profile", term)
+		}
+	}
+	for i, in := range tp.Interests {
+		if strings.TrimSpace(in.Description) == "" {
+			return fmt.Errorf("interests[%d].description: required", i)
+		}
+		if in.MinSimilarity <= 0 || in.MinSimilarity > 1 {
+			return fmt.Errorf("interests[%d].min_similarity: %v must be between 0 and 1", i, in.MinSimilarity)
+		}
+	}
+	return nil
+}
+
+var languageCode = regexp.MustCompile(`^[a-z]{2}$`)
+
+// validateRules checks rules listed at path.
+func validateRules(path string, rules []Rule) error {
+	for i, r := range rules {
+		if len(r.If.NotContainsAny) > 0 && len(r.If.Terms()) == 0 && len(r.If.HasEntity) == 0 {
+			return fmt.Errorf("%s[%d].if: not_contains_any needs contains_any, contains_all, or has_entity", path, i)
+		}
+		for _, kind := range r.If.HasEntity {
+			if !slices.Contains(entities.Kinds, kind) {
+				return fmt.Errorf("%s[%d].if.has_entity: unknown kind %q (want one of %s)", path, i, kind, strings.Join(entities.Kinds, ", "))
+			}
+		}
+		if r.Cooldown.Duration < 0 {
+			return fmt.Errorf("%s[%d].cooldown: must not be negative", path, i)
+		}
+	}
 	return nil
 }
+
+// hasEntityRules reports whether any rule, top-level or of a language, has
+// a has_entity condition.
+func (tp *TasteProfile) hasEntityRules() bool {
+	has := func(rules []Rule) bool {
+		return slices.ContainsFunc(rules, func(r Rule) bool { return len(r.If.HasEntity) > 0 })
+	}
+	if has(tp.Rules) {
+		return true
+	}
+	for _, lp := range tp.Languages {
+		if has(lp.Rules) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasTerm reports whether term is a keyword under weights or a term of a
+// rule, top-level or of a language, as written.
+func (tp *TasteProfile) hasTerm(term string) bool {
+	has := func(w Weights, rules []Rule) bool {
+		if _, ok := w.HighSignal[term]; ok {
+			return true
+		}
+		if _, ok := w.LowSignal[term]; ok {
+			return true
+		}
+		return slices.ContainsFunc(rules, func(r Rule) bool {
+			return slices.Contains(r.If.Terms(), term) || slices.Contains(r.If.NotContainsAny, term)
+		})
+	}
+	if has(tp.Weights, tp.Rules) {
+		return true
+	}
+	for _, lp := range tp.Languages {
+		if has(lp.Weights, lp.Rules) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateModifierTier checks that a modifier's tier, when set, is one of
+// the profile's; path names the modifier in errors.
+func validateModifierTier(tp *TasteProfile, path string, m ScoreModifier) error {
+	if m.Tier != "" && !slices.ContainsFunc(tp.TierSet(), func(t Tier) bool { return t.Name == m.Tier }) {
+		return fmt.Errorf("%s.tier: unknown tier %q", path, m.Tier)
+	}
+	return nil
+}
+
+var tierName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// validateTiers checks a custom tier set; errors name the offending entry.
+func validateTiers(tiers []Tier) error {
+	if len(tiers) < 2 {
+		return errors.New("tiers: at least read_now and ignore are required")
+	}
+	if tiers[0].Name != "read_now" {
+		return fmt.Errorf("tiers[0].name: must be read_now, not %q", tiers[0].Name)
+	}
+	if last := len(tiers) - 1; tiers[last].Name != "ignore" {
+		return fmt.Errorf("tiers[%d].name: must be ignore, not %q", last, tiers[last].Name)
+	}
+	seen := make(map[string]bool, len(tiers))
+	for i, t := range tiers {
+		if !tierName.MatchString(t.Name) {
+			return fmt.Errorf("tiers[%d].name: %q must be lowercase letters, digits, and underscores", i, t.Name)
+		}
+		if seen[t.Name] {
+			return fmt.Errorf("tiers[%d].name: duplicate tier %q", i, t.Name)
+		}
+		seen[t.Name] = true
+		if i > 0 && i < len(tiers)-1 && t.MinScore >= tiers[i-1].MinScore {
+			return fmt.Errorf("tiers[%d].min_score: %d must be below %s (%d)", i, t.MinScore, tiers[i-1].Name, tiers[i-1].MinScore)
+		}
+	}
+	return nil
+}
+
+var tasteWeightLine = regexp.MustCompile(`^(\s+)(["']?)(.+?)(["']?)(\s*:\s*)(-?\d+)(.*)$`)
+
+// SetTasteWeight rewrites the weight of keyword under weights.<section> in a
+// taste.yaml document, leaving comments and layout untouched. It reports
+// false if the keyword line was not found.
+func SetTasteWeight(data []byte, section, keyword string, weight int) ([]byte, bool) {
+	lines := strings.Split(string(data), "\n")
+	inWeights, inSection := false, false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indented := line[0] == ' ' || line[0] == '\t'
+		if !indented {
+			inWeights = strings.HasPrefix(trimmed, "weights:")
+			inSection = false
+			continue
+		}
+		if !inWeights {
+			continue
+		}
+		if strings.HasSuffix(trimmed, ":") {
+			inSection = strings.TrimSuffix(trimmed, ":") == section
+			continue
+		}
+		if !inSection {
+			continue
+		}
+
+		m := tasteWeightLine.FindStringSubmatch(line)
+		if m == nil || m[2] != m[4] || m[3] != keyword {
+			continue
+		}
+		lines[i] = m[1] + m[2] + m[3] + m[4] + m[5] + strconv.Itoa(weight) + m[7]
+		return []byte(strings.Join(lines, "\n")), true
+	}
+	return data, false
+}
@@ -0,0 +1,175 @@
+package digest
+
+import (
+	"crypto/sha1"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/source"
+)
+
+// atomMaxTitle cuts entry titles, which readers show on one line.
+const atomMaxTitle = 200
+
+// AtomFormatter formats the read_now and middle-tier items of a digest as
+// an Atom feed, so that feed readers can subscribe to the filtered stream.
+// Ignored and still-unread items are left out.
+type AtomFormatter struct {
+	title string
+	self  string
+}
+
+// NewAtom creates an Atom formatter. self is the URL the feed is served
+// from, linked as rel="self" when set.
+func NewAtom(title, self string) *AtomFormatter {
+	return &AtomFormatter{title: title, self: self}
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomPerson  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published"`
+	Author     atomPerson     `xml:"author"`
+	Links      []atomLink     `xml:"link"`
+	Categories []atomCategory `xml:"category"`
+	Content    atomText       `xml:"content"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// Format writes the digest as an Atom feed to w. The feed's updated time is
+// that of its newest entry, so it only changes when an entry is added.
+func (f *AtomFormatter) Format(w io.Writer, input DigestInput) error {
+	readNow, sections, _ := groupItems(input)
+	items := readNow
+	for _, sec := range sections {
+		items = append(items, sec.Items...)
+	}
+
+	feed := atomFeed{
+		ID:     atomUUID("feed/" + f.self),
+		Title:  f.title,
+		Author: atomPerson{Name: "noisepan"},
+	}
+	if f.self != "" {
+		feed.Links = append(feed.Links, atomLink{Rel: "self", Href: f.self})
+	}
+
+	var updated time.Time
+	for _, item := range items {
+		at := item.Post.PostedAt.UTC()
+		if at.After(updated) {
+			updated = at
+		}
+		feed.Entries = append(feed.Entries, atomItemEntry(item))
+	}
+	if updated.IsZero() {
+		updated = time.Now().UTC()
+	}
+	feed.Updated = updated.Format(time.RFC3339)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("write atom feed: %w", err)
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(feed); err != nil {
+		return fmt.Errorf("write atom feed: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// atomItemEntry renders an item as an entry: its headline as the title, the
+// remaining bullets as HTML content, and tier and labels as categories.
+func atomItemEntry(item DigestItem) atomEntry {
+	at := item.Post.PostedAt.UTC().Format(time.RFC3339)
+	title := headline(item)
+	if title == "" {
+		title = item.Post.Channel
+	}
+	e := atomEntry{
+		ID:         atomID(item.Post),
+		Title:      truncate(title, atomMaxTitle),
+		Updated:    at,
+		Published:  at,
+		Author:     atomPerson{Name: item.Post.Source + "/" + item.Post.Channel},
+		Categories: []atomCategory{{Term: item.Tier}},
+	}
+	if item.Post.URL != "" {
+		e.Links = append(e.Links, atomLink{Rel: "alternate", Href: item.Post.URL})
+	}
+	for _, l := range item.Labels {
+		e.Categories = append(e.Categories, atomCategory{Term: l})
+	}
+
+	var b strings.Builder
+	if note := verifiedNote(item); note != "" {
+		if item.Verification.Conflict {
+			note = "<strong>" + html.EscapeString(note) + "</strong>"
+		} else {
+			note = html.EscapeString(note)
+		}
+		b.WriteString("<p>" + note + "</p>")
+	}
+	if bullets := bulletsAfterHeadline(item); len(bullets) > 0 {
+		b.WriteString("<ul>")
+		for _, bullet := range bullets {
+			b.WriteString("<li>" + html.EscapeString(bullet) + "</li>")
+		}
+		b.WriteString("</ul>")
+	}
+	fmt.Fprintf(&b, "<p>Score %d · %s", item.Score, html.EscapeString(item.Post.Source+"/"+item.Post.Channel))
+	if len(item.AlsoIn) > 0 {
+		b.WriteString(" · " + html.EscapeString(alsoIn(item)))
+	}
+	b.WriteString("</p>")
+	e.Content = atomText{Type: "html", Body: b.String()}
+	return e
+}
+
+// atomID is the stable entry ID of a post: a name-based UUID of its source,
+// channel and external ID, so rescoring or a new digest never makes a
+// reader show the post again.
+func atomID(p source.Post) string {
+	return atomUUID("post/" + p.Source + "/" + p.Channel + "/" + p.ExternalID)
+}
+
+// atomUUID derives a version 5 style URN from name.
+func atomUUID(name string) string {
+	sum := sha1.Sum([]byte("noisepan/" + name))
+	sum[6] = sum[6]&0x0f | 0x50
+	sum[8] = sum[8]&0x3f | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
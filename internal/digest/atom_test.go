@@ -0,0 +1,97 @@
+package digest
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/source"
+	"github.com/ppiankov/noisepan/internal/summarize"
+	"github.com/ppiankov/noisepan/internal/taste"
+)
+
+func TestAtomFormat(t *testing.T) {
+	newest := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	input := DigestInput{
+		Items: []DigestItem{
+			{
+				ScoredPost: taste.ScoredPost{
+					Post:   source.Post{Source: "rss", Channel: "blog", ExternalID: "1", URL: "https://example.com/1", PostedAt: newest},
+					Score:  9,
+					Tier:   taste.TierReadNow,
+					Labels: []string{"critical"},
+				},
+				Summary: summarize.Summary{Bullets: []string{"CVE found", "Affects <v2.0> & later"}},
+				AlsoIn:  []string{"telegram/@sec"},
+			},
+			{
+				ScoredPost: taste.ScoredPost{
+					Post:  source.Post{Source: "reddit", Channel: "devops", ExternalID: "2", PostedAt: newest.Add(-2 * time.Hour)},
+					Score: 4,
+					Tier:  taste.TierSkim,
+				},
+				Summary: summarize.Summary{Bullets: []string{"K8s update"}},
+			},
+			{
+				ScoredPost: taste.ScoredPost{
+					Post:  source.Post{Source: "rss", Channel: "noise", ExternalID: "3", PostedAt: newest.Add(time.Hour)},
+					Score: 1,
+					Tier:  taste.TierIgnore,
+				},
+				Summary: summarize.Summary{Bullets: []string{"Ad"}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := NewAtom("noisepan digest", "https://example.com/feed.xml").Format(&buf, input); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	var feed atomFeed
+	if err := xml.Unmarshal(buf.Bytes(), &feed); err != nil {
+		t.Fatalf("parse feed: %v\n%s", err, buf.String())
+	}
+
+	if feed.Title != "noisepan digest" || feed.Updated != "2025-01-01T12:00:00Z" {
+		t.Errorf("feed title %q, updated %q", feed.Title, feed.Updated)
+	}
+	if len(feed.Links) != 1 || feed.Links[0].Rel != "self" {
+		t.Errorf("feed links = %+v", feed.Links)
+	}
+	if len(feed.Entries) != 2 {
+		t.Fatalf("entries = %d, want read_now and skim only", len(feed.Entries))
+	}
+	e := feed.Entries[0]
+	if e.Title != "CVE found" || len(e.Links) != 1 || e.Links[0].Href != "https://example.com/1" {
+		t.Errorf("entry = %+v", e)
+	}
+	if len(e.Categories) != 2 || e.Categories[0].Term != taste.TierReadNow || e.Categories[1].Term != "critical" {
+		t.Errorf("categories = %+v", e.Categories)
+	}
+	for _, want := range []string{"<li>Affects &lt;v2.0&gt; &amp; later</li>", "Score 9", "also in: telegram/@sec"} {
+		if !strings.Contains(e.Content.Body, want) {
+			t.Errorf("content %q missing %q", e.Content.Body, want)
+		}
+	}
+	if !strings.HasPrefix(e.ID, "urn:uuid:") || e.ID == feed.Entries[1].ID {
+		t.Errorf("entry ids = %q, %q", e.ID, feed.Entries[1].ID)
+	}
+}
+
+func TestAtomID_Stable(t *testing.T) {
+	p := source.Post{Source: "rss", Channel: "blog", ExternalID: "1", Text: "first"}
+	id := atomID(p)
+	p.Text = "edited"
+	if atomID(p) != id {
+		t.Error("id changed with the post text")
+	}
+	p.Channel = "other"
+	if atomID(p) == id {
+		t.Error("posts of different channels share an id")
+	}
+	if len(id) != len("urn:uuid:")+36 || id[len("urn:uuid:")+14] != '5' {
+		t.Errorf("id %q is not a version 5 UUID URN", id)
+	}
+}
@@ -0,0 +1,95 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ConfluencePublisher creates the digest as a page in a Confluence space,
+// optionally under a parent page. With a user it authenticates with basic
+// auth and an API token (Confluence Cloud), otherwise with the token as a
+// bearer personal access token (Data Center).
+type ConfluencePublisher struct {
+	baseURL string // e.g. https://example.atlassian.net/wiki
+	user    string
+	token   string
+	space   string
+	parent  string // ancestor page ID, optional
+	client  *http.Client
+}
+
+// NewConfluence creates a Confluence publisher.
+func NewConfluence(baseURL, user, token, space, parentPage string) *ConfluencePublisher {
+	return &ConfluencePublisher{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		user:    user,
+		token:   token,
+		space:   space,
+		parent:  parentPage,
+		client:  &http.Client{Timeout: publishTimeout},
+	}
+}
+
+// SetTransport replaces the HTTP transport used for API requests.
+func (c *ConfluencePublisher) SetTransport(rt http.RoundTripper) {
+	c.client.Transport = rt
+}
+
+// Publish creates the page in storage format. Confluence rejects a title
+// already used in the space, so titles should carry the date.
+func (c *ConfluencePublisher) Publish(ctx context.Context, title string, input DigestInput) (string, error) {
+	content := map[string]any{
+		"type":  "page",
+		"title": title,
+		"space": map[string]string{"key": c.space},
+		"body": map[string]any{
+			"storage": map[string]string{"value": pageHTML(pageBlocks(input), nil), "representation": "storage"},
+		},
+	}
+	if c.parent != "" {
+		content["ancestors"] = []map[string]string{{"id": c.parent}}
+	}
+	data, err := json.Marshal(content)
+	if err != nil {
+		return "", fmt.Errorf("marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/rest/api/content", bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.user != "" {
+		req.SetBasicAuth(c.user, c.token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("create confluence page: http request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("create confluence page: api returned status %d: %s", resp.StatusCode, readError(resp.Body))
+	}
+	var page struct {
+		Links struct {
+			Base  string `json:"base"`
+			WebUI string `json:"webui"`
+		} `json:"_links"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return "", fmt.Errorf("decode response: %w", err)
+	}
+	if page.Links.Base == "" {
+		page.Links.Base = c.baseURL
+	}
+	return page.Links.Base + page.Links.WebUI, nil
+}
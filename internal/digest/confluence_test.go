@@ -0,0 +1,85 @@
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestConfluencePublish(t *testing.T) {
+	var got struct {
+		Type      string              `json:"type"`
+		Title     string              `json:"title"`
+		Space     map[string]string   `json:"space"`
+		Ancestors []map[string]string `json:"ancestors"`
+		Body      struct {
+			Storage struct {
+				Value          string `json:"value"`
+				Representation string `json:"representation"`
+			} `json:"storage"`
+		} `json:"body"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/wiki/rest/api/content" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		if user, pass, ok := r.BasicAuth(); !ok || user != "me@example.com" || pass != "secret" {
+			t.Errorf("basic auth = %q %q %v", user, pass, ok)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		fmt.Fprint(w, `{"id": "42", "_links": {"base": "https://example.atlassian.net/wiki", "webui": "/spaces/ENG/pages/42"}}`)
+	}))
+	defer srv.Close()
+
+	p := NewConfluence(srv.URL+"/wiki/", "me@example.com", "secret", "ENG", "7")
+	url, err := p.Publish(context.Background(), "noisepan digest", publishTestInput())
+	if err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if url != "https://example.atlassian.net/wiki/spaces/ENG/pages/42" {
+		t.Errorf("url = %q", url)
+	}
+	if got.Type != "page" || got.Title != "noisepan digest" || got.Space["key"] != "ENG" {
+		t.Errorf("page = %+v", got)
+	}
+	if len(got.Ancestors) != 1 || got.Ancestors[0]["id"] != "7" {
+		t.Errorf("ancestors = %v", got.Ancestors)
+	}
+	if got.Body.Storage.Representation != "storage" {
+		t.Errorf("representation = %q", got.Body.Storage.Representation)
+	}
+	for _, want := range []string{
+		"<h2>Read Now (1)</h2>",
+		"<h3>[9] blog — CVE &lt;found&gt;</h3>",
+		"<ul><li>Patch available</li></ul>",
+		`<p><a href="https://example.com/1">Link</a></p>`,
+		`<ul><li><a href="https://example.com/2">[4] devops — K8s update</a></li></ul>`,
+		"<p>Ignored: 1 posts</p>",
+	} {
+		if !strings.Contains(got.Body.Storage.Value, want) {
+			t.Errorf("storage missing %q:\n%s", want, got.Body.Storage.Value)
+		}
+	}
+}
+
+func TestConfluencePublish_BearerAndError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer pat" {
+			t.Errorf("authorization = %q", r.Header.Get("Authorization"))
+		}
+		http.Error(w, "A page with this title already exists", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	p := NewConfluence(srv.URL, "", "pat", "ENG", "")
+	_, err := p.Publish(context.Background(), "t", publishTestInput())
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("err = %v, want the API message", err)
+	}
+}
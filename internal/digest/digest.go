@@ -1,7 +1,10 @@
 package digest
 
 import (
+	"fmt"
 	"io"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/ppiankov/noisepan/internal/summarize"
@@ -13,11 +16,100 @@ type Trend = taste.Trend
 
 // DigestItem pairs a scored post with its summary.
 type DigestItem struct {
+	PostID int64 // store ID, zero when not persisted
 	taste.ScoredPost
 	Summary summarize.Summary
 	AlsoIn  []string
+	Changed bool // post text edited since it was scored
+
+	// Verification is the last verify result for the post's URL, shown on
+	// read_now items; nil when it was never verified.
+	Verification *Verification
+}
+
+// Verification is what "noisepan verify" found about a post's URL.
+type Verification struct {
+	Support    int    // support index, 0-100
+	Confidence string // e.g. "high"
+	Conflict   bool   // sources disagree
+}
+
+// conflictNote flags items whose sources disagree.
+const conflictNote = "⚠ conflicting sources"
+
+// verifiedNote describes an item's verification: "verified 75/100, high
+// confidence", led by conflictNote when sources disagree. Empty when the
+// item was never verified.
+func verifiedNote(item DigestItem) string {
+	v := item.Verification
+	if v == nil {
+		return ""
+	}
+	note := fmt.Sprintf("verified %d/100", v.Support)
+	if v.Confidence != "" {
+		note += ", " + v.Confidence + " confidence"
+	}
+	if v.Conflict {
+		note = conflictNote + " — " + note
+	}
+	return note
+}
+
+// alsoInShown is how many channels an "also in" note names; the rest are
+// only counted, so a viral story does not list dozens.
+const alsoInShown = 3
+
+// alsoIn describes where else an item was posted, in AlsoIn order: "also
+// in: rss/a, hn/b", or past alsoInShown channels "also in 6 channels: rss/a,
+// hn/b, reddit/c and 3 more". Empty when there are none.
+func alsoIn(item DigestItem) string {
+	n := len(item.AlsoIn)
+	switch {
+	case n == 0:
+		return ""
+	case n <= alsoInShown:
+		return "also in: " + strings.Join(item.AlsoIn, ", ")
+	}
+	return fmt.Sprintf("also in %d channels: %s and %d more", n, strings.Join(item.AlsoIn[:alsoInShown], ", "), n-alsoInShown)
+}
+
+// capitalAlsoIn is alsoIn starting a sentence.
+func capitalAlsoIn(item DigestItem) string {
+	if s := alsoIn(item); s != "" {
+		return "A" + s[1:]
+	}
+	return ""
 }
 
+// affected lists the entities extracted from an item that say what it
+// concerns — products, or their vendors when none are named, then versions,
+// CVE and GHSA IDs, and addresses: "Affected: OpenSSL · >= 3.0.0, < 3.5.1 ·
+// CVE-2026-1234". IDs and versions a bullet already shows, like the
+// heuristic summary's "CVE: ..." line, are left out. Empty when nothing is
+// left.
+func affected(item DigestItem) string {
+	s := item.Summary
+	parts := s.Products
+	if len(parts) == 0 {
+		parts = s.Vendors
+	}
+	parts = slices.Clone(parts)
+	for _, xs := range [][]string{s.Versions, s.CVEs, s.GHSAs, s.IPs} {
+		for _, x := range xs {
+			if !slices.ContainsFunc(s.Bullets, func(b string) bool { return strings.Contains(b, x) }) {
+				parts = append(parts, x)
+			}
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "Affected: " + strings.Join(parts, " · ")
+}
+
+// changedNote marks items whose score predates an edit of the post.
+const changedNote = "edited since scored"
+
 // DigestInput is the full input for a digest formatter.
 type DigestInput struct {
 	Items      []DigestItem
@@ -25,6 +117,54 @@ type DigestInput struct {
 	Channels   int           // number of channels fetched
 	TotalPosts int           // total posts before filtering
 	Since      time.Duration // time window
+	Changes    FeedChanges   // feed-level changes since the last digest
+
+	// Tiers lists the taste profile's tier names, highest first, to order
+	// the sections between read_now and ignore. Empty means the default
+	// read_now, skim and ignore.
+	Tiers []string
+
+	// StillUnread holds read_now items earlier digests showed that are
+	// still neither read nor starred, listed one line each.
+	StillUnread []DigestItem
+
+	// GroupBy sections the listed items by topic instead of tier: one of
+	// GroupByValues, or empty for tiers.
+	GroupBy string
+
+	// Executive is the LLM's overview of the listed items, shown above
+	// them with digest.executive_summary. Nil leaves it out.
+	Executive *summarize.DigestSummary
+}
+
+// stillUnreadTitle heads the section of StillUnread items.
+func stillUnreadTitle(input DigestInput) string {
+	return fmt.Sprintf("Still unread (%d)", len(input.StillUnread))
+}
+
+// FeedChanges lists feed-level changes since the previous digest.
+type FeedChanges struct {
+	NewChannels    []string        // "source/channel" seen for the first time
+	SilentChannels []SilentChannel // channels with no posts for longer than the stale window
+	FailingFeeds   []FailingFeed   // feeds that started erroring
+}
+
+// SilentChannel is a channel that went quiet.
+type SilentChannel struct {
+	Channel  string // "source/channel"
+	LastPost time.Time
+}
+
+// FailingFeed is a configured feed whose fetches started failing.
+type FailingFeed struct {
+	Feed  string // "source/feed"
+	Error string
+	Since time.Time
+}
+
+// Empty reports whether there is nothing to show.
+func (c FeedChanges) Empty() bool {
+	return len(c.NewChannels) == 0 && len(c.SilentChannels) == 0 && len(c.FailingFeeds) == 0
 }
 
 // Formatter writes a formatted digest to w.
@@ -0,0 +1,320 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Discord limits on what a webhook message may carry.
+const (
+	discordMaxContent    = 2000 // characters of message content
+	discordMaxEmbeds     = 10   // embeds per message
+	discordMaxEmbedTotal = 6000 // characters across a message's embeds
+	discordMaxTitle      = 256
+	discordMaxFieldValue = 1024
+	// discordMaxDescription is below Discord's 4096 so that an embed with
+	// every field full still fits the per-message total on its own.
+	discordMaxDescription = 3500
+	// discordMaxRetryAfter caps how long a rate-limited send waits before
+	// its single retry.
+	discordMaxRetryAfter = 10 * time.Second
+	discordEmbedColor    = 0xE67E22
+	// discordConflictColor marks the embed of a post verify found
+	// conflicting sources for.
+	discordConflictColor = 0xE74C3C
+)
+
+// discordSpecial are the characters Discord markdown treats as markup.
+const discordSpecial = "\\*_~|`>#[]"
+
+// DiscordSender posts the digest to a Discord channel through a webhook:
+// read_now posts as embeds, the rest as text.
+type DiscordSender struct {
+	webhook string
+	client  *http.Client
+}
+
+// NewDiscord creates a Discord sender for a channel webhook URL.
+func NewDiscord(webhook string) *DiscordSender {
+	return &DiscordSender{
+		webhook: webhook,
+		client:  &http.Client{Timeout: publishTimeout},
+	}
+}
+
+// SetTransport replaces the HTTP transport used for API requests.
+func (d *DiscordSender) SetTransport(rt http.RoundTripper) {
+	d.client.Transport = rt
+}
+
+type discordMessage struct {
+	Content string         `json:"content,omitempty"`
+	Embeds  []discordEmbed `json:"embeds,omitempty"`
+	// AllowedMentions is always empty so that post text never pings anyone.
+	AllowedMentions discordMentions `json:"allowed_mentions"`
+}
+
+type discordMentions struct {
+	Parse []string `json:"parse"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title"`
+	URL         string         `json:"url,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Color       int            `json:"color"`
+	Fields      []discordField `json:"fields,omitempty"`
+	Footer      *discordFooter `json:"footer,omitempty"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+type discordFooter struct {
+	Text string `json:"text"`
+}
+
+// size counts the characters Discord holds against the per-message total.
+func (e discordEmbed) size() int {
+	n := utf8.RuneCountInString(e.Title) + utf8.RuneCountInString(e.Description)
+	for _, f := range e.Fields {
+		n += utf8.RuneCountInString(f.Name) + utf8.RuneCountInString(f.Value)
+	}
+	if e.Footer != nil {
+		n += utf8.RuneCountInString(e.Footer.Text)
+	}
+	return n
+}
+
+// Send posts input with title in as many messages as Discord's limits
+// require. A failed message stops the rest.
+func (d *DiscordSender) Send(ctx context.Context, title string, input DigestInput) error {
+	messages := discordMessages(title, input)
+	for i, m := range messages {
+		if err := d.post(ctx, m); err != nil {
+			return fmt.Errorf("send discord message %d of %d: %w", i+1, len(messages), err)
+		}
+	}
+	return nil
+}
+
+// post sends one message, waiting out a rate limit once.
+func (d *DiscordSender) post(ctx context.Context, m discordMessage) error {
+	m.AllowedMentions.Parse = []string{}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.webhook, bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := d.client.Do(req)
+		if err != nil {
+			// The webhook URL is its own credential; keep it out of logs.
+			var ue *url.Error
+			if errors.As(err, &ue) {
+				err = ue.Err
+			}
+			return fmt.Errorf("http request: %w", err)
+		}
+		if resp.StatusCode == http.StatusTooManyRequests && attempt == 0 {
+			wait := retryAfter(resp.Header.Get("Retry-After"))
+			_ = resp.Body.Close()
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+			continue
+		}
+		defer func() { _ = resp.Body.Close() }()
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("api returned status %d: %s", resp.StatusCode, readError(resp.Body))
+		}
+		return nil
+	}
+}
+
+// retryAfter parses a Retry-After header in seconds, capped at
+// discordMaxRetryAfter.
+func retryAfter(v string) time.Duration {
+	secs, err := strconv.ParseFloat(v, 64)
+	if err != nil || secs < 0 {
+		return time.Second
+	}
+	return min(time.Duration(secs*float64(time.Second)), discordMaxRetryAfter)
+}
+
+// discordMessages lays out input as webhook messages: a header with the
+// totals and feed changes, the read_now posts as embeds, and the remaining
+// sections as text, each within Discord's limits.
+func discordMessages(title string, input DigestInput) []discordMessage {
+	readNow, sections, ignoreCount := groupItems(input)
+
+	header := []string{
+		"**" + discordEscape(title) + "**",
+		discordEscape(fmt.Sprintf("%d channels, %d posts, since %s", input.Channels, input.TotalPosts, formatDuration(input.Since))),
+	}
+	if c := input.Changes; !c.Empty() {
+		header = append(header, "", "**Feed changes**")
+		for _, ch := range c.NewChannels {
+			header = append(header, discordEscape("• New: "+ch))
+		}
+		for _, sc := range c.SilentChannels {
+			header = append(header, discordEscape(fmt.Sprintf("• Silent: %s (last post %s)", sc.Channel, sc.LastPost.Format("2006-01-02"))))
+		}
+		for _, ff := range c.FailingFeeds {
+			header = append(header, discordEscape(fmt.Sprintf("• Erroring: %s — %s", ff.Feed, ff.Error)))
+		}
+	}
+	if len(readNow) == 0 && len(sections) == 0 && ignoreCount == 0 && len(input.StillUnread) == 0 {
+		header = append(header, "No posts found.")
+	}
+
+	var rest []string
+	if len(input.Trending) > 0 {
+		rest = append(rest, "", fmt.Sprintf("**Trending (appeared in %d+ sources)**", 3))
+		for _, tr := range input.Trending {
+			rest = append(rest, discordEscape(fmt.Sprintf("• %q — mentioned in %d channels: %s",
+				tr.Keyword, len(tr.Channels), strings.Join(tr.Channels, ", "))))
+		}
+	}
+	for _, sec := range sections {
+		rest = append(rest, "", "**"+discordEscape(sec.title())+"**")
+		for _, item := range sec.Items {
+			text := fmt.Sprintf("[%d] %s — %s", item.Score, item.Post.Channel, headline(item))
+			if len(item.AlsoIn) > 0 {
+				text += " (" + alsoIn(item) + ")"
+			}
+			if item.Changed {
+				text += fmt.Sprintf(" (%s)", changedNote)
+			}
+			// Embeds go out ahead of the text, so read_now posts in a
+			// grouped section stay lines, bold, with their verify note.
+			if shownInFull(item) {
+				if note := verifiedNote(item); note != "" {
+					text += " (" + note + ")"
+				}
+				rest = append(rest, "• **"+discordLink(text, item.Post.URL)+"**")
+				continue
+			}
+			rest = append(rest, "• "+discordLink(text, item.Post.URL))
+		}
+	}
+	if len(input.StillUnread) > 0 {
+		rest = append(rest, "", "**"+discordEscape(stillUnreadTitle(input))+"**")
+		for _, item := range input.StillUnread {
+			rest = append(rest, "• "+discordLink(fmt.Sprintf("[%d] %s — %s", item.Score, item.Post.Channel, headline(item)), item.Post.URL))
+		}
+	}
+	if ignoreCount > 0 {
+		rest = append(rest, "", fmt.Sprintf("_Ignored: %d posts_", ignoreCount))
+	}
+
+	// The header carries the first batch of embeds; Discord shows content
+	// above them.
+	var messages []discordMessage
+	cur := discordMessage{Content: truncate(strings.Join(header, "\n"), discordMaxContent)}
+	total := 0
+	for _, item := range readNow {
+		e := discordItemEmbed(item)
+		if len(cur.Embeds) == discordMaxEmbeds || (len(cur.Embeds) > 0 && total+e.size() > discordMaxEmbedTotal) {
+			messages = append(messages, cur)
+			cur, total = discordMessage{}, 0
+		}
+		cur.Embeds = append(cur.Embeds, e)
+		total += e.size()
+	}
+	messages = append(messages, cur)
+
+	var text strings.Builder
+	for _, line := range rest {
+		line = truncate(line, discordMaxContent)
+		if text.Len() > 0 && utf8.RuneCountInString(text.String())+1+utf8.RuneCountInString(line) > discordMaxContent {
+			messages = append(messages, discordMessage{Content: strings.TrimSpace(text.String())})
+			text.Reset()
+		}
+		if text.Len() > 0 {
+			text.WriteByte('\n')
+		}
+		text.WriteString(line)
+	}
+	if s := strings.TrimSpace(text.String()); s != "" {
+		messages = append(messages, discordMessage{Content: s})
+	}
+	return messages
+}
+
+// discordItemEmbed renders a read_now post as an embed.
+func discordItemEmbed(item DigestItem) discordEmbed {
+	e := discordEmbed{
+		Title: truncate(fmt.Sprintf("%s — %s", item.Post.Channel, headline(item)), discordMaxTitle),
+		URL:   item.Post.URL,
+		Color: discordEmbedColor,
+	}
+	var lines []string
+	for _, b := range bulletsAfterHeadline(item) {
+		lines = append(lines, "• "+discordEscape(b))
+	}
+	e.Description = truncate(strings.Join(lines, "\n"), discordMaxDescription)
+
+	e.Fields = append(e.Fields, discordField{Name: "Score", Value: strconv.Itoa(item.Score), Inline: true})
+	if len(item.Labels) > 0 {
+		e.Fields = append(e.Fields, discordField{
+			Name: "Labels", Value: truncate(discordEscape(strings.Join(item.Labels, ", ")), discordMaxFieldValue), Inline: true,
+		})
+	}
+	if v := item.Verification; v != nil {
+		name := "Verified"
+		if v.Conflict {
+			name = conflictNote
+			e.Color = discordConflictColor
+		}
+		e.Fields = append(e.Fields, discordField{Name: name, Value: discordEscape(strings.TrimPrefix(verifiedNote(item), conflictNote+" — ")), Inline: true})
+	}
+	if len(item.AlsoIn) > 0 {
+		e.Fields = append(e.Fields, discordField{
+			Name: "Also in", Value: truncate(discordEscape(strings.Join(item.AlsoIn, ", ")), discordMaxFieldValue),
+		})
+	}
+	if item.Changed {
+		e.Footer = &discordFooter{Text: changedNote}
+	}
+	return e
+}
+
+// discordLink escapes text and, with a URL, makes it a masked link.
+func discordLink(text, u string) string {
+	if u == "" {
+		return discordEscape(text)
+	}
+	return "[" + discordEscape(text) + "](<" + u + ">)"
+}
+
+// discordEscape escapes Discord markdown in s.
+func discordEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(discordSpecial, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
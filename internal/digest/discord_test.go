@@ -0,0 +1,156 @@
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/source"
+	"github.com/ppiankov/noisepan/internal/summarize"
+	"github.com/ppiankov/noisepan/internal/taste"
+)
+
+func TestDiscordSend(t *testing.T) {
+	var got []discordMessage
+	var raw string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/webhooks/1/token" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		data, _ := io.ReadAll(r.Body)
+		var m discordMessage
+		if err := json.Unmarshal(data, &m); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		raw += string(data)
+		got = append(got, m)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	s := NewDiscord(srv.URL + "/api/webhooks/1/token")
+	if err := s.Send(context.Background(), "noisepan digest", publishTestInput()); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("messages = %d, want header with embeds and the text sections", len(got))
+	}
+	if !strings.HasPrefix(got[0].Content, "**noisepan digest**\n3 channels, 10 posts, since 7d") {
+		t.Errorf("header = %q", got[0].Content)
+	}
+	if len(got[0].Embeds) != 1 {
+		t.Fatalf("embeds = %+v", got[0].Embeds)
+	}
+	e := got[0].Embeds[0]
+	if e.Title != "blog — CVE <found>" || e.URL != "https://example.com/1" || e.Description != "• Patch available" {
+		t.Errorf("embed = %+v", e)
+	}
+	if len(e.Fields) != 2 || e.Fields[0] != (discordField{"Score", "9", true}) || e.Fields[1].Value != "security" {
+		t.Errorf("fields = %+v", e.Fields)
+	}
+	for _, want := range []string{"**Skim (1)**", `• [\[4\] devops — K8s update](<https://example.com/2>)`, "_Ignored: 1 posts_"} {
+		if !strings.Contains(got[1].Content, want) {
+			t.Errorf("text missing %q:\n%s", want, got[1].Content)
+		}
+	}
+	if !strings.Contains(raw, `"allowed_mentions":{"parse":[]}`) {
+		t.Errorf("mentions not disabled: %s", raw)
+	}
+}
+
+func TestDiscordSend_RetriesRateLimit(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0.01")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	s := NewDiscord(srv.URL)
+	if err := s.Send(context.Background(), "t", DigestInput{}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
+
+func TestDiscordSend_ErrorHidesWebhook(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	s := NewDiscord(srv.URL + "/api/webhooks/1/secret")
+	srv.Close()
+	err := s.Send(context.Background(), "t", DigestInput{})
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("err = %v, want a connection error without the webhook", err)
+	}
+}
+
+func TestDiscordMessages_Limits(t *testing.T) {
+	input := DigestInput{Channels: 1, TotalPosts: 25, Since: 24 * time.Hour}
+	for i := range 25 {
+		input.Items = append(input.Items, DigestItem{
+			ScoredPost: taste.ScoredPost{Post: source.Post{Channel: "ch"}, Score: 9, Tier: taste.TierReadNow},
+			Summary:    summarize.Summary{Bullets: []string{fmt.Sprintf("post %d", i), strings.Repeat("x", 1500)}},
+		})
+	}
+
+	messages := discordMessages("t", input)
+	embeds := 0
+	for i, m := range messages {
+		total := 0
+		for _, e := range m.Embeds {
+			total += e.size()
+		}
+		if len(m.Embeds) > discordMaxEmbeds || total > discordMaxEmbedTotal {
+			t.Errorf("message %d has %d embeds, %d characters", i, len(m.Embeds), total)
+		}
+		embeds += len(m.Embeds)
+	}
+	// About 1.5k characters each: three embeds fit under the total, not ten.
+	if embeds != 25 || len(messages) != 9 {
+		t.Errorf("embeds = %d in %d messages, want 25 in 9", embeds, len(messages))
+	}
+}
+
+func TestDiscordEscape(t *testing.T) {
+	if got := discordEscape("*bold* _it_ `code` > quote [x]"); got != "\\*bold\\* \\_it\\_ \\`code\\` \\> quote \\[x\\]" {
+		t.Errorf("discordEscape = %q", got)
+	}
+}
+
+func TestDiscordMessages_Verification(t *testing.T) {
+	input := DigestInput{Channels: 1, TotalPosts: 2, Since: 24 * time.Hour, Items: []DigestItem{
+		{
+			ScoredPost:   taste.ScoredPost{Post: source.Post{Channel: "ch"}, Score: 9, Tier: taste.TierReadNow},
+			Summary:      summarize.Summary{Bullets: []string{"verified"}},
+			Verification: &Verification{Support: 75, Confidence: "high"},
+		},
+		{
+			ScoredPost:   taste.ScoredPost{Post: source.Post{Channel: "ch"}, Score: 8, Tier: taste.TierReadNow},
+			Summary:      summarize.Summary{Bullets: []string{"disputed"}},
+			Verification: &Verification{Support: 30, Confidence: "low", Conflict: true},
+		},
+	}}
+
+	embeds := discordMessages("t", input)[0].Embeds
+	if len(embeds) != 2 {
+		t.Fatalf("embeds = %d, want 2", len(embeds))
+	}
+	if f := embeds[0].Fields; len(f) < 2 || f[1].Name != "Verified" || f[1].Value != "verified 75/100, high confidence" || embeds[0].Color != discordEmbedColor {
+		t.Errorf("verified embed = %+v", embeds[0])
+	}
+	if f := embeds[1].Fields; len(f) < 2 || f[1].Name != conflictNote || f[1].Value != "verified 30/100, low confidence" || embeds[1].Color != discordConflictColor {
+		t.Errorf("conflict embed = %+v", embeds[1])
+	}
+}
@@ -0,0 +1,361 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"maps"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	emailTimeout = 30 * time.Second
+	// smtpsPort is the submission port with implicit TLS (RFC 8314); other
+	// ports start in plain text and upgrade with STARTTLS.
+	smtpsPort = 465
+
+	faviconTimeout = 5 * time.Second
+	// maxFaviconBytes caps an inlined favicon; larger icons are left out.
+	maxFaviconBytes = 64 << 10
+)
+
+// darkModeStyle follows the mail client's dark theme, in clients that honor
+// prefers-color-scheme.
+const darkModeStyle = `<meta name="color-scheme" content="light dark"><meta name="supported-color-schemes" content="light dark">` +
+	`<style>:root{color-scheme:light dark}` +
+	`@media (prefers-color-scheme: dark){body{background:#121212;color:#e4e4e4}a{color:#8ab4f8}}</style>`
+
+// EmailSender mails the digest through an SMTP server as a multipart message:
+// the Markdown rendering as plain text and the page layout as HTML.
+type EmailSender struct {
+	host     string
+	port     int
+	user     string
+	password string
+	from     string
+	to       []string
+	tls      *tls.Config
+	client   *http.Client // fetches favicons
+	favicons bool
+	darkMode bool
+}
+
+// emailStyle is how the HTML part looks beyond the page layout.
+type emailStyle struct {
+	darkMode bool
+	favicons map[string]*favicon // by channel; channels of one site share it
+}
+
+// favicon is an image inlined into the HTML part.
+type favicon struct {
+	contentType string
+	data        []byte
+}
+
+// NewEmail creates an email sender. Without user it sends unauthenticated.
+func NewEmail(host string, port int, user, password, from string, to []string) *EmailSender {
+	return &EmailSender{
+		host:     host,
+		port:     port,
+		user:     user,
+		password: password,
+		from:     from,
+		to:       to,
+		tls:      &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12},
+		client:   &http.Client{Timeout: faviconTimeout},
+	}
+}
+
+// SetTransport replaces the HTTP transport used to fetch favicons.
+func (e *EmailSender) SetTransport(rt http.RoundTripper) {
+	e.client.Transport = rt
+}
+
+// SetFavicons inlines each channel's favicon before its posts in the HTML
+// part. Icons that cannot be fetched are left out.
+func (e *EmailSender) SetFavicons(on bool) {
+	e.favicons = on
+}
+
+// SetDarkMode adds a stylesheet for mail clients in dark mode.
+func (e *EmailSender) SetDarkMode(on bool) {
+	e.darkMode = on
+}
+
+// Send mails input with subject to every recipient.
+func (e *EmailSender) Send(ctx context.Context, subject string, input DigestInput, now time.Time) error {
+	from, err := mail.ParseAddress(e.from)
+	if err != nil {
+		return fmt.Errorf("parse from: %w", err)
+	}
+	var to []*mail.Address
+	for _, addr := range e.to {
+		a, err := mail.ParseAddress(addr)
+		if err != nil {
+			return fmt.Errorf("parse recipient: %w", err)
+		}
+		to = append(to, a)
+	}
+	style := emailStyle{darkMode: e.darkMode}
+	if e.favicons {
+		style.favicons = e.fetchFavicons(ctx, input)
+	}
+	msg, err := emailMessage(from, to, subject, input, now, style)
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(e.host, strconv.Itoa(e.port))
+	dialer := &net.Dialer{Timeout: emailTimeout}
+	var conn net.Conn
+	if e.port == smtpsPort {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: e.tls}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("connect to %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	} else {
+		_ = conn.SetDeadline(time.Now().Add(emailTimeout))
+	}
+
+	c, err := smtp.NewClient(conn, e.host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("smtp handshake: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	if e.port != smtpsPort {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(e.tls); err != nil {
+				return fmt.Errorf("starttls: %w", err)
+			}
+		}
+	}
+	if e.user != "" {
+		// PlainAuth refuses to send credentials without TLS, except to localhost.
+		if err := c.Auth(smtp.PlainAuth("", e.user, e.password, e.host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+	if err := c.Mail(from.Address); err != nil {
+		return fmt.Errorf("smtp mail from: %w", err)
+	}
+	for _, a := range to {
+		if err := c.Rcpt(a.Address); err != nil {
+			return fmt.Errorf("smtp rcpt %s: %w", a.Address, err)
+		}
+	}
+	wc, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err := wc.Write(msg); err != nil {
+		_ = wc.Close()
+		return fmt.Errorf("write message: %w", err)
+	}
+	if err := wc.Close(); err != nil {
+		return fmt.Errorf("send message: %w", err)
+	}
+	return c.Quit()
+}
+
+// fetchFavicons fetches /favicon.ico from the site of each channel's first
+// linked post, once per site. Channels whose icon fails to fetch, or is not
+// a small image, are left out.
+func (e *EmailSender) fetchFavicons(ctx context.Context, input DigestInput) map[string]*favicon {
+	icons := map[string]*favicon{}
+	bySite := map[string]*favicon{}
+	for _, item := range append(append([]DigestItem(nil), input.Items...), input.StillUnread...) {
+		ch := item.Post.Channel
+		if _, done := icons[ch]; done {
+			continue
+		}
+		u, err := url.Parse(item.Post.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			continue
+		}
+		site := u.Scheme + "://" + u.Host
+		icon, seen := bySite[site]
+		if !seen {
+			icon = e.fetchFavicon(ctx, site+"/favicon.ico")
+			bySite[site] = icon
+		}
+		if icon != nil {
+			icons[ch] = icon
+		}
+	}
+	return icons
+}
+
+// fetchFavicon returns the image at iconURL, or nil.
+func (e *EmailSender) fetchFavicon(ctx context.Context, iconURL string) *favicon {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, iconURL, nil)
+	if err != nil {
+		return nil
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFaviconBytes+1))
+	if err != nil || len(data) == 0 || len(data) > maxFaviconBytes {
+		return nil
+	}
+	ct := http.DetectContentType(data)
+	if !strings.HasPrefix(ct, "image/") {
+		return nil
+	}
+	return &favicon{contentType: ct, data: data}
+}
+
+// emailMessage builds the RFC 5322 message: a multipart/alternative body with
+// the Markdown digest as text/plain and the page layout as text/html. With
+// favicons, the HTML part is multipart/related with the icons attached by
+// Content-ID.
+func emailMessage(from *mail.Address, to []*mail.Address, subject string, input DigestInput, now time.Time, style emailStyle) ([]byte, error) {
+	var text bytes.Buffer
+	if err := NewMarkdown().Format(&text, input); err != nil {
+		return nil, fmt.Errorf("format text: %w", err)
+	}
+
+	// Icons are numbered in channel order, so the same input makes the same
+	// message.
+	var attached []*favicon
+	srcs := map[string]string{}
+	for _, ch := range slices.Sorted(maps.Keys(style.favicons)) {
+		icon := style.favicons[ch]
+		n := slices.Index(attached, icon)
+		if n < 0 {
+			n = len(attached)
+			attached = append(attached, icon)
+		}
+		srcs[ch] = "cid:" + faviconCID(n)
+	}
+
+	head := `<meta charset="utf-8">`
+	if style.darkMode {
+		head += darkModeStyle
+	}
+	page := "<!DOCTYPE html>\n<html><head>" + head + "<title>" + html.EscapeString(subject) +
+		"</title></head>\n<body><h1>" + html.EscapeString(subject) + "</h1>\n" +
+		pageHTML(pageBlocks(input), srcs) + "\n</body></html>\n"
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	if err := writeQuotedPart(mw, "text/plain; charset=utf-8", text.String()); err != nil {
+		return nil, err
+	}
+	if len(attached) == 0 {
+		if err := writeQuotedPart(mw, "text/html; charset=utf-8", page); err != nil {
+			return nil, err
+		}
+	} else {
+		var related bytes.Buffer
+		rw := multipart.NewWriter(&related)
+		if err := writeQuotedPart(rw, "text/html; charset=utf-8", page); err != nil {
+			return nil, err
+		}
+		for n, icon := range attached {
+			pw, err := rw.CreatePart(textproto.MIMEHeader{
+				"Content-Type":              {icon.contentType},
+				"Content-Transfer-Encoding": {"base64"},
+				"Content-Disposition":       {"inline"},
+				"Content-Id":                {"<" + faviconCID(n) + ">"},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("create part: %w", err)
+			}
+			encoded := base64.StdEncoding.EncodeToString(icon.data)
+			for len(encoded) > 0 {
+				line := encoded[:min(76, len(encoded))]
+				encoded = encoded[len(line):]
+				if _, err := io.WriteString(pw, line+"\r\n"); err != nil {
+					return nil, fmt.Errorf("encode part: %w", err)
+				}
+			}
+		}
+		if err := rw.Close(); err != nil {
+			return nil, fmt.Errorf("close multipart: %w", err)
+		}
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type": {`multipart/related; type="text/html"; boundary=` + rw.Boundary()},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create part: %w", err)
+		}
+		if _, err := pw.Write(related.Bytes()); err != nil {
+			return nil, fmt.Errorf("write part: %w", err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, fmt.Errorf("close multipart: %w", err)
+	}
+
+	recipients := make([]string, len(to))
+	for i, a := range to {
+		recipients[i] = a.String()
+	}
+	var msg bytes.Buffer
+	for _, h := range [][2]string{
+		{"From", from.String()},
+		{"To", strings.Join(recipients, ", ")},
+		{"Subject", mime.QEncoding.Encode("utf-8", subject)},
+		{"Date", now.Format(time.RFC1123Z)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "multipart/alternative; boundary=" + mw.Boundary()},
+	} {
+		fmt.Fprintf(&msg, "%s: %s\r\n", h[0], h[1])
+	}
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+// writeQuotedPart adds a quoted-printable part with content to mw.
+func writeQuotedPart(mw *multipart.Writer, contentType, content string) error {
+	pw, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return fmt.Errorf("create part: %w", err)
+	}
+	qp := quotedprintable.NewWriter(pw)
+	if _, err := qp.Write([]byte(content)); err != nil {
+		return fmt.Errorf("encode part: %w", err)
+	}
+	if err := qp.Close(); err != nil {
+		return fmt.Errorf("encode part: %w", err)
+	}
+	return nil
+}
+
+// faviconCID is the Content-ID of the nth attached favicon.
+func faviconCID(n int) string {
+	return fmt.Sprintf("favicon-%d@noisepan", n)
+}
@@ -0,0 +1,307 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/source"
+	"github.com/ppiankov/noisepan/internal/taste"
+)
+
+// smtpSession is what the fake SMTP server received.
+type smtpSession struct {
+	auth string
+	from string
+	rcpt []string
+	data string
+}
+
+// fakeSMTP accepts one session on localhost, without STARTTLS, and returns
+// its port and a channel with what it received.
+func fakeSMTP(t *testing.T) (int, <-chan smtpSession) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	done := make(chan smtpSession, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		tc := textproto.NewConn(conn)
+		var s smtpSession
+		_ = tc.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := tc.ReadLine()
+			if err != nil {
+				return
+			}
+			verb, arg, _ := strings.Cut(line, " ")
+			switch strings.ToUpper(verb) {
+			case "EHLO":
+				_ = tc.PrintfLine("250-localhost")
+				_ = tc.PrintfLine("250 AUTH PLAIN")
+			case "AUTH":
+				s.auth = arg
+				_ = tc.PrintfLine("235 ok")
+			case "MAIL":
+				s.from = arg
+				_ = tc.PrintfLine("250 ok")
+			case "RCPT":
+				s.rcpt = append(s.rcpt, arg)
+				_ = tc.PrintfLine("250 ok")
+			case "DATA":
+				_ = tc.PrintfLine("354 go ahead")
+				data, err := tc.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				s.data = string(data)
+				_ = tc.PrintfLine("250 queued")
+			case "QUIT":
+				_ = tc.PrintfLine("221 bye")
+				done <- s
+				return
+			default:
+				_ = tc.PrintfLine("502 unknown")
+			}
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port, done
+}
+
+func TestEmailSend(t *testing.T) {
+	port, done := fakeSMTP(t)
+	e := NewEmail("localhost", port, "me", "secret", "noisepan <digest@example.com>", []string{"me@example.com", "team@example.com"})
+	now := time.Date(2026, 3, 1, 7, 0, 0, 0, time.UTC)
+
+	if err := e.Send(context.Background(), "noisepan digest 2026-03-01", publishTestInput(), now); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	s := <-done
+
+	if want := "PLAIN " + base64.StdEncoding.EncodeToString([]byte("\x00me\x00secret")); s.auth != want {
+		t.Errorf("auth = %q, want %q", s.auth, want)
+	}
+	if s.from != "FROM:<digest@example.com>" {
+		t.Errorf("mail from = %q", s.from)
+	}
+	if len(s.rcpt) != 2 || s.rcpt[1] != "TO:<team@example.com>" {
+		t.Errorf("rcpt = %v", s.rcpt)
+	}
+	if !strings.Contains(s.data, "Subject: noisepan digest 2026-03-01\n") {
+		t.Errorf("message has no subject:\n%s", s.data)
+	}
+}
+
+func TestEmailSend_ConnectError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	_ = ln.Close()
+
+	e := NewEmail("127.0.0.1", port, "", "", "me@example.com", []string{"me@example.com"})
+	err = e.Send(context.Background(), "t", DigestInput{}, time.Now())
+	if err == nil || !strings.Contains(err.Error(), "connect to 127.0.0.1:"+strconv.Itoa(port)) {
+		t.Errorf("err = %v, want connect error", err)
+	}
+}
+
+func TestEmailMessage(t *testing.T) {
+	from := &mail.Address{Name: "noisepan", Address: "digest@example.com"}
+	to := []*mail.Address{{Address: "me@example.com"}}
+	data, err := emailMessage(from, to, "Digest — Monday", publishTestInput(), time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC), emailStyle{})
+	if err != nil {
+		t.Fatalf("message: %v", err)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != "Digest — Monday" {
+		t.Errorf("subject = %q (%v)", subject, err)
+	}
+	if got := msg.Header.Get("To"); got != "<me@example.com>" {
+		t.Errorf("to = %q", got)
+	}
+	if got := msg.Header.Get("Date"); got != "Mon, 02 Mar 2026 07:00:00 +0000" {
+		t.Errorf("date = %q", got)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("content type = %q (%v)", mediaType, err)
+	}
+	parts := map[string]string{}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("next part: %v", err)
+		}
+		body, err := io.ReadAll(p) // NextPart decodes quoted-printable
+		if err != nil {
+			t.Fatalf("read part: %v", err)
+		}
+		parts[p.Header.Get("Content-Type")] = string(body)
+	}
+
+	text := parts["text/plain; charset=utf-8"]
+	if !strings.Contains(text, "# noisepan digest") || !strings.Contains(text, "CVE <found>") {
+		t.Errorf("text part:\n%s", text)
+	}
+	page := parts["text/html; charset=utf-8"]
+	for _, want := range []string{
+		"<h1>Digest — Monday</h1>",
+		"<h3>[9] blog — CVE &lt;found&gt;</h3>",
+		`<li><a href="https://example.com/2">[4] devops — K8s update</a></li>`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("html part missing %q:\n%s", want, page)
+		}
+	}
+}
+
+// faviconTransport serves a PNG at /favicon.ico of example.com and fails
+// every other request, counting them.
+type faviconTransport struct{ requests []string }
+
+var testPNG = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x10\x00\x00\x00\x10")
+
+func (ft *faviconTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	ft.requests = append(ft.requests, r.URL.String())
+	if r.URL.String() != "https://example.com/favicon.ico" {
+		return nil, errors.New("unreachable")
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(testPNG)), Request: r}, nil
+}
+
+func TestEmailMessage_FaviconsAndDarkMode(t *testing.T) {
+	input := publishTestInput()
+	input.StillUnread = []DigestItem{{ScoredPost: taste.ScoredPost{
+		Post: source.Post{Channel: "other", URL: "https://down.example.org/post"}, Score: 8, Tier: taste.TierReadNow,
+	}}}
+
+	ft := &faviconTransport{}
+	e := NewEmail("localhost", 25, "", "", "digest@example.com", []string{"me@example.com"})
+	e.SetTransport(ft)
+	icons := e.fetchFavicons(context.Background(), input)
+	// blog and devops share example.com: one fetch, one attachment.
+	if len(ft.requests) != 2 || len(icons) != 2 || icons["blog"] != icons["devops"] {
+		t.Fatalf("requests = %q, icons = %v", ft.requests, icons)
+	}
+
+	from := &mail.Address{Address: "digest@example.com"}
+	to := []*mail.Address{{Address: "me@example.com"}}
+	data, err := emailMessage(from, to, "Digest", input, time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC), emailStyle{darkMode: true, favicons: icons})
+	if err != nil {
+		t.Fatalf("message: %v", err)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	_, params, _ := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	var related *multipart.Part
+	for {
+		p, err := mr.NextPart()
+		if err != nil {
+			break
+		}
+		if strings.HasPrefix(p.Header.Get("Content-Type"), "multipart/related") {
+			related = p
+			break
+		}
+	}
+	if related == nil {
+		t.Fatal("no multipart/related part")
+	}
+	mediaType, params, err := mime.ParseMediaType(related.Header.Get("Content-Type"))
+	if err != nil || params["type"] != "text/html" {
+		t.Fatalf("related content type = %q %v (%v)", mediaType, params, err)
+	}
+	rr := multipart.NewReader(related, params["boundary"])
+	var parts []*multipart.Part
+	var bodies []string
+	for {
+		p, err := rr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("next related part: %v", err)
+		}
+		body, _ := io.ReadAll(p)
+		parts = append(parts, p)
+		bodies = append(bodies, string(body))
+	}
+	if len(parts) != 2 {
+		t.Fatalf("related parts = %d, want html and one icon", len(parts))
+	}
+
+	page := bodies[0]
+	for _, want := range []string{
+		"@media (prefers-color-scheme: dark)",
+		`<meta name="color-scheme" content="light dark">`,
+		`<h3><img src="cid:favicon-0@noisepan" alt="" width="16" height="16" style="vertical-align:middle"/> [9] blog`,
+		`<li><img src="cid:favicon-0@noisepan" alt="" width="16" height="16" style="vertical-align:middle"/> <a href="https://example.com/2">`,
+		`<li><a href="https://down.example.org/post">`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("html part missing %q:\n%s", want, page)
+		}
+	}
+
+	icon := parts[1]
+	if icon.Header.Get("Content-Id") != "<favicon-0@noisepan>" || icon.Header.Get("Content-Type") != "image/png" {
+		t.Errorf("icon headers = %v", icon.Header)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(bodies[1], "\r\n", ""))
+	if err != nil || !bytes.Equal(decoded, testPNG) {
+		t.Errorf("icon = %q (%v)", decoded, err)
+	}
+
+	plain, err := emailMessage(from, to, "Digest", input, time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC), emailStyle{})
+	if err != nil {
+		t.Fatalf("message: %v", err)
+	}
+	if s := string(plain); strings.Contains(s, "multipart/related") || strings.Contains(s, "prefers-color-scheme") {
+		t.Error("default style has favicons or dark mode")
+	}
+}
+
+func TestPageHTML_Executive(t *testing.T) {
+	out := pageHTML(pageBlocks(executiveInput()), nil)
+	want := "<h2>Executive summary</h2><p>A quiet day apart from an OpenSSL fix.</p>" +
+		"<h3>Themes</h3><ul><li>OpenSSL patches</li></ul>" +
+		"<h3>Most urgent</h3><ul><li>Patch OpenSSL (@security)</li></ul><h2>Trending"
+	if !strings.Contains(out, want) {
+		t.Errorf("html missing %q:\n%s", want, out)
+	}
+}
@@ -0,0 +1,95 @@
+package digest
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ppiankov/noisepan/internal/taste"
+)
+
+// Groupings for DigestInput.GroupBy besides the default by tier.
+const (
+	GroupByLabel   = "label"   // an item's first label; unlabelled items last
+	GroupByChannel = "channel" // source/channel
+	GroupBySource  = "source"
+)
+
+// GroupByValues lists the values DigestInput.GroupBy accepts besides "".
+var GroupByValues = []string{GroupByLabel, GroupByChannel, GroupBySource}
+
+// unlabelledGroup heads the label section of items without labels.
+const unlabelledGroup = "unlabelled"
+
+// groupItems splits input's items into the sections every formatter lays
+// out. By default that is groupByTier. With input.GroupBy set, there is no
+// separate read_now list: the listed items of every tier share topical
+// sections, ordered by their best item, and formatters show read_now items
+// in full wherever they land.
+func groupItems(input DigestInput) (readNow []DigestItem, sections []tierSection, ignoreCount int) {
+	readNow, sections, ignoreCount = groupByTier(input)
+	if input.GroupBy == "" {
+		return readNow, sections, ignoreCount
+	}
+
+	listed := readNow
+	for _, sec := range sections {
+		listed = append(listed, sec.Items...)
+	}
+	// Items arrive highest first per tier; keep that across tiers so the
+	// sections open with their best post.
+	slices.SortStableFunc(listed, func(a, b DigestItem) int { return b.Score - a.Score })
+
+	var unlabelled []DigestItem
+	index := make(map[string]int)
+	sections = nil
+	for _, item := range listed {
+		key := groupKey(input.GroupBy, item)
+		if key == "" {
+			unlabelled = append(unlabelled, item)
+			continue
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(sections)
+			index[key] = i
+			sections = append(sections, tierSection{Group: key})
+		}
+		sections[i].Items = append(sections[i].Items, item)
+	}
+	if len(unlabelled) > 0 {
+		sections = append(sections, tierSection{Group: unlabelledGroup, Items: unlabelled})
+	}
+	return nil, sections, ignoreCount
+}
+
+// groupKey is the section an item belongs to under groupBy, or "" for a
+// label grouping of an item without labels.
+func groupKey(groupBy string, item DigestItem) string {
+	switch groupBy {
+	case GroupByLabel:
+		if len(item.Labels) == 0 {
+			return ""
+		}
+		return item.Labels[0]
+	case GroupByChannel:
+		return item.Post.Source + "/" + item.Post.Channel
+	case GroupBySource:
+		return item.Post.Source
+	}
+	return ""
+}
+
+// shownInFull reports whether a section item is laid out like a read_now
+// item, which only happens in grouped sections.
+func shownInFull(item DigestItem) bool {
+	return item.Tier == taste.TierReadNow
+}
+
+// ValidateGroupBy reports whether groupBy is "" or one of GroupByValues.
+func ValidateGroupBy(groupBy string) error {
+	if groupBy == "" || slices.Contains(GroupByValues, groupBy) {
+		return nil
+	}
+	return fmt.Errorf("unknown grouping %q (want %s)", groupBy, strings.Join(GroupByValues, ", "))
+}
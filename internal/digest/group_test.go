@@ -0,0 +1,132 @@
+package digest
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/source"
+	"github.com/ppiankov/noisepan/internal/summarize"
+	"github.com/ppiankov/noisepan/internal/taste"
+)
+
+func groupInput(groupBy string) DigestInput {
+	item := func(src, channel, tier string, score int, labels ...string) DigestItem {
+		return DigestItem{
+			ScoredPost: taste.ScoredPost{
+				Post:   source.Post{Source: src, Channel: channel},
+				Score:  score,
+				Labels: labels,
+				Tier:   tier,
+			},
+			Summary: summarize.Summary{Bullets: []string{channel + " headline", channel + " detail"}},
+		}
+	}
+	return DigestInput{
+		Items: []DigestItem{
+			item("rss", "cisa", taste.TierReadNow, 10, "critical"),
+			item("reddit", "kubernetes", taste.TierReadNow, 8, "ops", "critical"),
+			item("rss", "blog", taste.TierSkim, 9, "critical"),
+			item("hn", "front", taste.TierSkim, 4),
+			item("reddit", "kubernetes", taste.TierSkim, 3, "ops"),
+			item("rss", "noise", taste.TierIgnore, 0, "critical"),
+		},
+		Channels:   5,
+		TotalPosts: 6,
+		Since:      24 * time.Hour,
+		GroupBy:    groupBy,
+	}
+}
+
+func TestGroupItems(t *testing.T) {
+	tests := []struct {
+		groupBy string
+		want    map[string][]int // section title to item scores, in order
+		order   []string
+	}{
+		{GroupByLabel, map[string][]int{"critical": {10, 9}, "ops": {8, 3}, unlabelledGroup: {4}}, []string{"critical", "ops", unlabelledGroup}},
+		{GroupByChannel, map[string][]int{"rss/cisa": {10}, "rss/blog": {9}, "reddit/kubernetes": {8, 3}, "hn/front": {4}}, []string{"rss/cisa", "rss/blog", "reddit/kubernetes", "hn/front"}},
+		{GroupBySource, map[string][]int{"rss": {10, 9}, "reddit": {8, 3}, "hn": {4}}, []string{"rss", "reddit", "hn"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.groupBy, func(t *testing.T) {
+			readNow, sections, ignoreCount := groupItems(groupInput(tt.groupBy))
+			if len(readNow) != 0 || ignoreCount != 1 {
+				t.Errorf("read_now = %d, ignored = %d, want 0 and 1", len(readNow), ignoreCount)
+			}
+			var order []string
+			for _, sec := range sections {
+				order = append(order, sec.Group)
+				var scores []int
+				for _, item := range sec.Items {
+					scores = append(scores, item.Score)
+				}
+				if !slices.Equal(scores, tt.want[sec.Group]) {
+					t.Errorf("%s scores = %v, want %v", sec.Group, scores, tt.want[sec.Group])
+				}
+			}
+			if !slices.Equal(order, tt.order) {
+				t.Errorf("sections = %v, want %v", order, tt.order)
+			}
+		})
+	}
+
+	// Without a grouping the tiers stay.
+	readNow, sections, _ := groupItems(groupInput(""))
+	if len(readNow) != 2 || len(sections) != 1 || sections[0].Tier != taste.TierSkim {
+		t.Errorf("tier grouping = %d read_now, sections %+v", len(readNow), sections)
+	}
+}
+
+func TestGroupItems_Formatters(t *testing.T) {
+	input := groupInput(GroupByLabel)
+
+	var buf bytes.Buffer
+	if err := NewTerminal(false).Format(&buf, input); err != nil {
+		t.Fatalf("terminal: %v", err)
+	}
+	out := buf.String()
+	if strings.Contains(out, "Read Now") {
+		t.Errorf("grouped terminal digest has a Read Now section:\n%s", out)
+	}
+	// read_now items keep their details inside the group.
+	for _, want := range []string{"--- critical (2) ---", "--- ops (2) ---", "--- unlabelled (1) ---", "cisa detail"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("terminal output missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	if err := NewMarkdown().Format(&buf, input); err != nil {
+		t.Fatalf("markdown: %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, "## critical (2)\n\n### [10] cisa — cisa headline") {
+		t.Errorf("markdown output:\n%s", out)
+	}
+
+	buf.Reset()
+	if err := NewJSON().Format(&buf, input); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	var got jsonDigest
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(got.ReadNow) != 2 || len(got.Groups) != 3 || got.Groups[0].Name != "critical" || len(got.Groups[0].Items) != 2 {
+		t.Errorf("json digest = %+v", got)
+	}
+}
+
+func TestValidateGroupBy(t *testing.T) {
+	for _, v := range []string{"", GroupByLabel, GroupByChannel, GroupBySource} {
+		if err := ValidateGroupBy(v); err != nil {
+			t.Errorf("ValidateGroupBy(%q) = %v", v, err)
+		}
+	}
+	if err := ValidateGroupBy("tier"); err == nil {
+		t.Error("expected error for unknown grouping")
+	}
+}
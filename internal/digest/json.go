@@ -2,7 +2,12 @@ package digest
 
 import (
 	"encoding/json"
+	"html"
 	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ppiankov/noisepan/internal/summarize"
 )
 
 type jsonTrend struct {
@@ -10,12 +15,39 @@ type jsonTrend struct {
 	Channels []string `json:"channels"`
 }
 
+type jsonChanges struct {
+	NewChannels    []string            `json:"new_channels,omitempty"`
+	SilentChannels []jsonSilentChannel `json:"silent_channels,omitempty"`
+	FailingFeeds   []jsonFailingFeed   `json:"failing_feeds,omitempty"`
+}
+
+type jsonSilentChannel struct {
+	Channel  string `json:"channel"`
+	LastPost string `json:"last_post"`
+}
+
+type jsonFailingFeed struct {
+	Feed  string `json:"feed"`
+	Error string `json:"error"`
+	Since string `json:"since"`
+}
+
 type jsonDigest struct {
-	Meta     jsonMeta    `json:"meta"`
-	Trending []jsonTrend `json:"trending,omitempty"`
-	ReadNow  []jsonItem  `json:"read_now"`
-	Skims    []jsonItem  `json:"skims"`
-	Ignored  int         `json:"ignored"`
+	Meta        jsonMeta                 `json:"meta"`
+	Changes     *jsonChanges             `json:"changes,omitempty"`
+	Executive   *summarize.DigestSummary `json:"executive_summary,omitempty"`
+	Trending    []jsonTrend              `json:"trending,omitempty"`
+	ReadNow     []jsonItem               `json:"read_now"`
+	Skims       []jsonItem               `json:"skims"`
+	StillUnread []jsonItem               `json:"still_unread,omitempty"`
+	Groups      []jsonGroup              `json:"groups,omitempty"`
+	Ignored     int                      `json:"ignored"`
+}
+
+// jsonGroup is one section of a digest grouped by topic.
+type jsonGroup struct {
+	Name  string     `json:"name"`
+	Items []jsonItem `json:"items"`
 }
 
 type jsonMeta struct {
@@ -35,19 +67,64 @@ type jsonItem struct {
 	Headline string   `json:"headline"`
 	Bullets  []string `json:"bullets,omitempty"`
 	AlsoIn   []string `json:"also_in,omitempty"`
+	Changed  bool     `json:"changed_since_scoring,omitempty"`
+
+	Verification *jsonVerification `json:"verification,omitempty"`
+	Entities     *jsonEntities     `json:"entities,omitempty"`
+}
+
+// jsonEntities are the entities summaries found in a post's text.
+type jsonEntities struct {
+	Products []string `json:"products,omitempty"`
+	Vendors  []string `json:"vendors,omitempty"`
+	Versions []string `json:"versions,omitempty"`
+	CVEs     []string `json:"cves,omitempty"`
+	GHSAs    []string `json:"ghsas,omitempty"`
+	IPs      []string `json:"ips,omitempty"`
+	Dates    []string `json:"dates,omitempty"`
+}
+
+type jsonVerification struct {
+	Support    int    `json:"support"`
+	Confidence string `json:"confidence,omitempty"`
+	Conflict   bool   `json:"conflict"`
+}
+
+// JSONOptions shapes headlines and bullets for consumers with limits, such
+// as chat integrations that render HTML or cut long messages. Lengths are
+// in characters, counted before escaping; 0 means no limit.
+type JSONOptions struct {
+	EscapeHTML  bool // replace <, >, &, ' and " with HTML entities
+	MaxHeadline int
+	MaxBullet   int
 }
 
 // JSONFormatter formats a digest as JSON.
-type JSONFormatter struct{}
+type JSONFormatter struct {
+	opts JSONOptions
+}
 
-// NewJSON creates a JSON formatter.
+// NewJSON creates a JSON formatter that leaves text as summarized.
 func NewJSON() *JSONFormatter {
 	return &JSONFormatter{}
 }
 
+// NewJSONWithOptions creates a JSON formatter that shapes text per opts.
+func NewJSONWithOptions(opts JSONOptions) *JSONFormatter {
+	return &JSONFormatter{opts: opts}
+}
+
 // Format writes the digest as JSON to w.
 func (f *JSONFormatter) Format(w io.Writer, input DigestInput) error {
-	readNow, skims, ignoreCount := groupByTier(input.Items)
+	// read_now and skims keep their tiers whatever the grouping, so
+	// consumers can rely on them; a grouped digest adds groups.
+	readNow, sections, ignoreCount := groupByTier(input)
+	// Every tier between read_now and ignore goes under skims; each item
+	// still names its own tier.
+	var middle []DigestItem
+	for _, sec := range sections {
+		middle = append(middle, sec.Items...)
+	}
 
 	var trends []jsonTrend
 	for _, tr := range input.Trending {
@@ -60,10 +137,21 @@ func (f *JSONFormatter) Format(w io.Writer, input DigestInput) error {
 			TotalPosts: input.TotalPosts,
 			Since:      formatDuration(input.Since),
 		},
-		Trending: trends,
-		ReadNow:  toJSONItems(readNow),
-		Skims:    toJSONItems(skims),
-		Ignored:  ignoreCount,
+		Changes:   toJSONChanges(input.Changes),
+		Executive: input.Executive,
+		Trending:  trends,
+		ReadNow:   f.toJSONItems(readNow),
+		Skims:     f.toJSONItems(middle),
+		Ignored:   ignoreCount,
+	}
+	if len(input.StillUnread) > 0 {
+		out.StillUnread = f.toJSONItems(input.StillUnread)
+	}
+	if input.GroupBy != "" {
+		_, groups, _ := groupItems(input)
+		for _, g := range groups {
+			out.Groups = append(out.Groups, jsonGroup{Name: g.Group, Items: f.toJSONItems(g.Items)})
+		}
 	}
 
 	enc := json.NewEncoder(w)
@@ -71,12 +159,37 @@ func (f *JSONFormatter) Format(w io.Writer, input DigestInput) error {
 	return enc.Encode(out)
 }
 
-func toJSONItems(items []DigestItem) []jsonItem {
+func toJSONChanges(c FeedChanges) *jsonChanges {
+	if c.Empty() {
+		return nil
+	}
+	out := &jsonChanges{NewChannels: c.NewChannels}
+	for _, sc := range c.SilentChannels {
+		out.SilentChannels = append(out.SilentChannels, jsonSilentChannel{
+			Channel:  sc.Channel,
+			LastPost: sc.LastPost.Format("2006-01-02T15:04:05Z"),
+		})
+	}
+	for _, ff := range c.FailingFeeds {
+		out.FailingFeeds = append(out.FailingFeeds, jsonFailingFeed{
+			Feed:  ff.Feed,
+			Error: ff.Error,
+			Since: ff.Since.Format("2006-01-02T15:04:05Z"),
+		})
+	}
+	return out
+}
+
+func (f *JSONFormatter) toJSONItems(items []DigestItem) []jsonItem {
 	result := make([]jsonItem, 0, len(items))
 	for _, item := range items {
 		headline := ""
 		if len(item.Summary.Bullets) > 0 {
-			headline = item.Summary.Bullets[0]
+			headline = f.text(item.Summary.Bullets[0], f.opts.MaxHeadline)
+		}
+		var bullets []string
+		for _, b := range item.Summary.Bullets[min(1, len(item.Summary.Bullets)):] {
+			bullets = append(bullets, f.text(b, f.opts.MaxBullet))
 		}
 
 		ji := jsonItem{
@@ -88,13 +201,39 @@ func toJSONItems(items []DigestItem) []jsonItem {
 			Tier:     item.Tier,
 			Labels:   item.Labels,
 			Headline: headline,
-			Bullets:  item.Summary.Bullets[1:],
+			Bullets:  bullets,
 			AlsoIn:   item.AlsoIn,
+			Changed:  item.Changed,
+		}
+		if v := item.Verification; v != nil {
+			ji.Verification = &jsonVerification{Support: v.Support, Confidence: v.Confidence, Conflict: v.Conflict}
 		}
-		if len(ji.Bullets) == 0 {
-			ji.Bullets = nil
+		if s := item.Summary; len(s.Products)+len(s.Vendors)+len(s.Versions)+len(s.CVEs)+len(s.GHSAs)+len(s.IPs)+len(s.Dates) > 0 {
+			ji.Entities = &jsonEntities{
+				Products: s.Products, Vendors: s.Vendors, Versions: s.Versions,
+				CVEs: s.CVEs, GHSAs: s.GHSAs, IPs: s.IPs, Dates: s.Dates,
+			}
 		}
 		result = append(result, ji)
 	}
 	return result
 }
+
+// text truncates s to max characters and escapes it, per the options.
+func (f *JSONFormatter) text(s string, max int) string {
+	s = truncate(s, max)
+	if f.opts.EscapeHTML {
+		s = html.EscapeString(s)
+	}
+	return s
+}
+
+// truncate shortens s to at most max characters, ending in "…" when cut.
+// max <= 0 leaves s alone.
+func truncate(s string, max int) string {
+	if max <= 0 || utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimRight(string(runes[:max-1]), " ") + "…"
+}
@@ -163,3 +163,173 @@ func TestJSONFormat_Omitempty(t *testing.T) {
 		t.Error("bullets should be omitted when empty")
 	}
 }
+
+func TestJSONFormat_Changes(t *testing.T) {
+	input := DigestInput{
+		Changes: FeedChanges{
+			NewChannels:  []string{"rss/blog"},
+			FailingFeeds: []FailingFeed{{Feed: "reddit/devops", Error: "HTTP 429", Since: time.Date(2025, 1, 2, 6, 0, 0, 0, time.UTC)}},
+		},
+		Since: 24 * time.Hour,
+	}
+
+	var buf bytes.Buffer
+	if err := NewJSON().Format(&buf, input); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+
+	var out jsonDigest
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if out.Changes == nil {
+		t.Fatal("changes missing")
+	}
+	if len(out.Changes.NewChannels) != 1 || out.Changes.NewChannels[0] != "rss/blog" {
+		t.Errorf("new_channels = %v", out.Changes.NewChannels)
+	}
+	if len(out.Changes.FailingFeeds) != 1 || out.Changes.FailingFeeds[0].Since != "2025-01-02T06:00:00Z" {
+		t.Errorf("failing_feeds = %+v", out.Changes.FailingFeeds)
+	}
+}
+
+func TestJSONFormat_StillUnread(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewJSON().Format(&buf, stillUnreadInput()); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	var out jsonDigest
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(out.StillUnread) != 1 || out.StillUnread[0].Headline != "Cert expires Friday" || len(out.ReadNow) != 0 {
+		t.Errorf("digest = %+v", out)
+	}
+
+	buf.Reset()
+	if err := NewJSON().Format(&buf, DigestInput{}); err != nil {
+		t.Fatalf("format empty: %v", err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte("still_unread")) {
+		t.Errorf("empty still_unread not omitted:\n%s", buf.String())
+	}
+}
+
+func TestJSONFormat_Options(t *testing.T) {
+	input := DigestInput{Items: []DigestItem{{
+		ScoredPost: taste.ScoredPost{Post: source.Post{Source: "rss", Channel: "blog"}, Score: 9, Tier: taste.TierReadNow},
+		Summary:    summarize.Summary{Bullets: []string{"CVE in <libfoo> & friends", "Patch with apt upgrade now"}},
+	}}}
+
+	var buf bytes.Buffer
+	f := NewJSONWithOptions(JSONOptions{EscapeHTML: true, MaxHeadline: 14, MaxBullet: 10})
+	if err := f.Format(&buf, input); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	var out jsonDigest
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	item := out.ReadNow[0]
+	// Cut to the limit first, so entities are never split.
+	if item.Headline != "CVE in &lt;libfo…" {
+		t.Errorf("headline = %q", item.Headline)
+	}
+	if len(item.Bullets) != 1 || item.Bullets[0] != "Patch wit…" {
+		t.Errorf("bullets = %q", item.Bullets)
+	}
+
+	buf.Reset()
+	if err := NewJSON().Format(&buf, input); err != nil {
+		t.Fatalf("format without options: %v", err)
+	}
+	out = jsonDigest{}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if out.ReadNow[0].Headline != "CVE in <libfoo> & friends" {
+		t.Errorf("headline without options = %q", out.ReadNow[0].Headline)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s    string
+		max  int
+		want string
+	}{
+		{"short", 10, "short"},
+		{"exactly10!", 10, "exactly10!"},
+		{"héllo wörld", 7, "héllo…"},
+		{"anything", 0, "anything"},
+	}
+	for _, tt := range tests {
+		if got := truncate(tt.s, tt.max); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestJSONFormat_Verification(t *testing.T) {
+	input := DigestInput{Items: []DigestItem{
+		{
+			ScoredPost:   taste.ScoredPost{Post: source.Post{Channel: "news"}, Score: 9, Tier: taste.TierReadNow},
+			Summary:      summarize.Summary{Bullets: []string{"Outage claimed"}},
+			Verification: &Verification{Support: 30, Confidence: "low", Conflict: true},
+		},
+		{
+			ScoredPost: taste.ScoredPost{Post: source.Post{Channel: "blog"}, Score: 8, Tier: taste.TierReadNow},
+			Summary:    summarize.Summary{Bullets: []string{"Release"}},
+		},
+	}}
+	var buf bytes.Buffer
+	if err := NewJSON().Format(&buf, input); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	var out jsonDigest
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if v := out.ReadNow[0].Verification; v == nil || v.Support != 30 || v.Confidence != "low" || !v.Conflict {
+		t.Errorf("verification = %+v", v)
+	}
+	if out.ReadNow[1].Verification != nil {
+		t.Errorf("unverified item has verification %+v", out.ReadNow[1].Verification)
+	}
+}
+
+func TestJSONFormat_Executive(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewJSON().Format(&buf, executiveInput()); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	var out jsonDigest
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if out.Executive == nil || out.Executive.Overview != "A quiet day apart from an OpenSSL fix." || len(out.Executive.Urgent) != 1 {
+		t.Errorf("executive = %+v", out.Executive)
+	}
+}
+
+func TestJSONFormat_Entities(t *testing.T) {
+	item := makeItem(taste.TierReadNow, 9, "@security", nil, []string{"OpenSSL fix"})
+	item.Summary.Products = []string{"OpenSSL"}
+	item.Summary.GHSAs = []string{"GHSA-4m4x-9f2h-vq3c"}
+	bare := makeItem(taste.TierReadNow, 8, "@blog", nil, []string{"Nothing to see"})
+
+	var buf bytes.Buffer
+	if err := NewJSON().Format(&buf, DigestInput{Items: []DigestItem{item, bare}}); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	var out jsonDigest
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if e := out.ReadNow[0].Entities; e == nil || len(e.Products) != 1 || len(e.GHSAs) != 1 {
+		t.Errorf("entities = %+v", e)
+	}
+	if e := out.ReadNow[1].Entities; e != nil {
+		t.Errorf("entities without any = %+v, want omitted", e)
+	}
+}
@@ -16,17 +16,49 @@ func NewMarkdown() *MarkdownFormatter {
 
 // Format writes the digest as Markdown to w.
 func (f *MarkdownFormatter) Format(w io.Writer, input DigestInput) error {
-	readNow, skims, ignoreCount := groupByTier(input.Items)
+	readNow, sections, ignoreCount := groupItems(input)
 
 	sinceStr := formatDuration(input.Since)
 	fmt.Fprintf(w, "# noisepan digest\n\n")
 	fmt.Fprintf(w, "%d channels, %d posts, since %s\n\n", input.Channels, input.TotalPosts, sinceStr)
 
-	if len(readNow) == 0 && len(skims) == 0 && ignoreCount == 0 {
+	if c := input.Changes; !c.Empty() {
+		fmt.Fprintf(w, "## Feed changes\n\n")
+		for _, ch := range c.NewChannels {
+			fmt.Fprintf(w, "- New: %s\n", ch)
+		}
+		for _, sc := range c.SilentChannels {
+			fmt.Fprintf(w, "- Silent: %s (last post %s)\n", sc.Channel, sc.LastPost.Format("2006-01-02"))
+		}
+		for _, ff := range c.FailingFeeds {
+			fmt.Fprintf(w, "- Erroring: %s — %s\n", ff.Feed, ff.Error)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(readNow) == 0 && len(sections) == 0 && ignoreCount == 0 && len(input.StillUnread) == 0 {
 		fmt.Fprintln(w, "No posts found.")
 		return nil
 	}
 
+	if s := input.Executive; s != nil {
+		fmt.Fprintf(w, "## Executive summary\n\n%s\n\n", s.Overview)
+		if len(s.Themes) > 0 {
+			fmt.Fprintf(w, "**Themes**\n\n")
+			for _, t := range s.Themes {
+				fmt.Fprintf(w, "- %s\n", t)
+			}
+			fmt.Fprintln(w)
+		}
+		if len(s.Urgent) > 0 {
+			fmt.Fprintf(w, "**Most urgent**\n\n")
+			for _, u := range s.Urgent {
+				fmt.Fprintf(w, "- %s\n", u)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+
 	if len(input.Trending) > 0 {
 		fmt.Fprintf(w, "## Trending (appeared in %d+ sources)\n\n", 3)
 		for _, tr := range input.Trending {
@@ -43,14 +75,30 @@ func (f *MarkdownFormatter) Format(w io.Writer, input DigestInput) error {
 		}
 	}
 
-	if len(skims) > 0 {
-		fmt.Fprintf(w, "## Skim (%d)\n\n", len(skims))
-		for _, item := range skims {
+	for _, sec := range sections {
+		fmt.Fprintf(w, "## %s\n\n", sec.title())
+		for _, item := range sec.Items {
+			if shownInFull(item) {
+				f.writeReadNowItem(w, item)
+				continue
+			}
 			f.writeSkimItem(w, item)
 		}
 		fmt.Fprintln(w)
 	}
 
+	if len(input.StillUnread) > 0 {
+		fmt.Fprintf(w, "## %s\n\n", stillUnreadTitle(input))
+		for _, item := range input.StillUnread {
+			fmt.Fprintf(w, "- **[%d]** %s — %s", item.Score, item.Post.Channel, headline(item))
+			if item.Post.URL != "" {
+				fmt.Fprintf(w, " ([link](%s))", item.Post.URL)
+			}
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintln(w)
+	}
+
 	if ignoreCount > 0 {
 		fmt.Fprintf(w, "*Ignored: %d posts*\n", ignoreCount)
 	}
@@ -75,6 +123,13 @@ func (f *MarkdownFormatter) writeReadNowItem(w io.Writer, item DigestItem) {
 
 	fmt.Fprintf(w, "### [%d] %s — %s\n\n", item.Score, item.Post.Channel, headline)
 
+	if note := verifiedNote(item); note != "" {
+		if item.Verification.Conflict {
+			fmt.Fprintf(w, "**%s**\n\n", note)
+		} else {
+			fmt.Fprintf(w, "_%s_\n\n", note)
+		}
+	}
 	if labels != "" {
 		fmt.Fprintf(w, "Labels:%s\n\n", labels)
 	}
@@ -85,9 +140,15 @@ func (f *MarkdownFormatter) writeReadNowItem(w io.Writer, item DigestItem) {
 	if len(item.Summary.Bullets) > 1 {
 		fmt.Fprintln(w)
 	}
+	if note := affected(item); note != "" {
+		fmt.Fprintf(w, "_%s_\n\n", note)
+	}
 
 	if len(item.AlsoIn) > 0 {
-		fmt.Fprintf(w, "Also in: %s\n\n", strings.Join(item.AlsoIn, ", "))
+		fmt.Fprintf(w, "%s\n\n", capitalAlsoIn(item))
+	}
+	if item.Changed {
+		fmt.Fprintf(w, "_%s_\n\n", changedNote)
 	}
 
 	if item.Post.URL != "" {
@@ -103,7 +164,10 @@ func (f *MarkdownFormatter) writeSkimItem(w io.Writer, item DigestItem) {
 
 	fmt.Fprintf(w, "- **[%d]** %s — %s", item.Score, item.Post.Channel, headline)
 	if len(item.AlsoIn) > 0 {
-		fmt.Fprintf(w, " _(also in: %s)_", strings.Join(item.AlsoIn, ", "))
+		fmt.Fprintf(w, " _(%s)_", alsoIn(item))
+	}
+	if item.Changed {
+		fmt.Fprintf(w, " _(%s)_", changedNote)
 	}
 	fmt.Fprintln(w)
 }
@@ -145,3 +145,52 @@ func TestMarkdownFormat_URLRendering(t *testing.T) {
 		t.Errorf("link count = %d, want 1 (only for post with URL)", linkCount)
 	}
 }
+
+func TestMarkdownFormat_StillUnread(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewMarkdown().Format(&buf, stillUnreadInput()); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	out := buf.String()
+	if want := "## Still unread (1)\n\n- **[9]** @alerts — Cert expires Friday ([link](https://example.com/cert))\n"; !strings.Contains(out, want) {
+		t.Errorf("output missing %q:\n%s", want, out)
+	}
+}
+
+func TestMarkdownFormat_Verification(t *testing.T) {
+	item := DigestItem{
+		ScoredPost:   taste.ScoredPost{Post: source.Post{Channel: "news"}, Score: 9, Tier: taste.TierReadNow},
+		Summary:      summarize.Summary{Bullets: []string{"Outage claimed"}},
+		Verification: &Verification{Support: 30, Confidence: "low", Conflict: true},
+	}
+	var buf bytes.Buffer
+	if err := NewMarkdown().Format(&buf, DigestInput{Items: []DigestItem{item}}); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	if want := "**⚠ conflicting sources — verified 30/100, low confidence**\n\n"; !strings.Contains(buf.String(), want) {
+		t.Errorf("output missing %q:\n%s", want, buf.String())
+	}
+
+	item.Verification = &Verification{Support: 75, Confidence: "high"}
+	buf.Reset()
+	if err := NewMarkdown().Format(&buf, DigestInput{Items: []DigestItem{item}}); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	if want := "_verified 75/100, high confidence_\n\n"; !strings.Contains(buf.String(), want) {
+		t.Errorf("output missing %q:\n%s", want, buf.String())
+	}
+}
+
+func TestMarkdownFormat_Executive(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewMarkdown().Format(&buf, executiveInput()); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	out := buf.String()
+	want := "## Executive summary\n\nA quiet day apart from an OpenSSL fix.\n\n" +
+		"**Themes**\n\n- OpenSSL patches\n\n" +
+		"**Most urgent**\n\n- Patch OpenSSL (@security)\n\n## Trending"
+	if !strings.Contains(out, want) {
+		t.Errorf("output missing %q:\n%s", want, out)
+	}
+}
@@ -0,0 +1,133 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	notionEndpoint  = "https://api.notion.com/v1"
+	notionVersion   = "2022-06-28"
+	notionMaxBlocks = 100  // children per request
+	notionMaxText   = 2000 // characters per rich text object
+	publishTimeout  = 30 * time.Second
+)
+
+// NotionPublisher creates the digest as a child page of a Notion page. The
+// integration behind token must be shared with that page.
+type NotionPublisher struct {
+	token    string
+	parent   string // parent page ID
+	endpoint string
+	client   *http.Client
+}
+
+// NewNotion creates a Notion publisher.
+func NewNotion(token, parentPage string) *NotionPublisher {
+	return &NotionPublisher{
+		token:    token,
+		parent:   parentPage,
+		endpoint: notionEndpoint,
+		client:   &http.Client{Timeout: publishTimeout},
+	}
+}
+
+// SetTransport replaces the HTTP transport used for API requests.
+func (n *NotionPublisher) SetTransport(rt http.RoundTripper) {
+	n.client.Transport = rt
+}
+
+// Publish creates the page, appending blocks past the first hundred in
+// further requests, as the API takes at most that many at once.
+func (n *NotionPublisher) Publish(ctx context.Context, title string, input DigestInput) (string, error) {
+	var blocks []map[string]any
+	for _, b := range pageBlocks(input) {
+		blocks = append(blocks, notionBlock(b))
+	}
+	first := blocks[:min(len(blocks), notionMaxBlocks)]
+
+	var page struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	}
+	err := n.do(ctx, http.MethodPost, "/pages", map[string]any{
+		"parent": map[string]any{"page_id": n.parent},
+		"properties": map[string]any{
+			"title": map[string]any{"title": notionText(title, "")},
+		},
+		"children": first,
+	}, &page)
+	if err != nil {
+		return "", fmt.Errorf("create notion page: %w", err)
+	}
+
+	for rest := blocks[len(first):]; len(rest) > 0; {
+		chunk := rest[:min(len(rest), notionMaxBlocks)]
+		rest = rest[len(chunk):]
+		if err := n.do(ctx, http.MethodPatch, "/blocks/"+page.ID+"/children", map[string]any{"children": chunk}, nil); err != nil {
+			return page.URL, fmt.Errorf("append notion blocks: %w", err)
+		}
+	}
+	return page.URL, nil
+}
+
+func (n *NotionPublisher) do(ctx context.Context, method, path string, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, n.endpoint+path, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+n.token)
+	req.Header.Set("Notion-Version", notionVersion)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("http request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("api returned status %d: %s", resp.StatusCode, readError(resp.Body))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+func notionBlock(b pageBlock) map[string]any {
+	kind := map[string]string{
+		blockHeading:    "heading_2",
+		blockSubheading: "heading_3",
+		blockParagraph:  "paragraph",
+		blockBullet:     "bulleted_list_item",
+	}[b.Kind]
+	return map[string]any{
+		"object": "block",
+		"type":   kind,
+		kind:     map[string]any{"rich_text": notionText(b.Text, b.URL)},
+	}
+}
+
+// notionText returns text as rich text, cut to the API's length limit.
+func notionText(text, url string) []map[string]any {
+	if r := []rune(text); len(r) > notionMaxText {
+		text = string(r[:notionMaxText-1]) + "…"
+	}
+	content := map[string]any{"content": text}
+	if url != "" {
+		content["link"] = map[string]string{"url": url}
+	}
+	return []map[string]any{{"type": "text", "text": content}}
+}
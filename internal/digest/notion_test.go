@@ -0,0 +1,145 @@
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/source"
+	"github.com/ppiankov/noisepan/internal/summarize"
+	"github.com/ppiankov/noisepan/internal/taste"
+)
+
+func publishTestInput() DigestInput {
+	return DigestInput{
+		Items: []DigestItem{
+			{
+				ScoredPost: taste.ScoredPost{
+					Post:   source.Post{Source: "rss", Channel: "blog", URL: "https://example.com/1"},
+					Score:  9,
+					Tier:   taste.TierReadNow,
+					Labels: []string{"security"},
+				},
+				Summary: summarize.Summary{Bullets: []string{"CVE <found>", "Patch available"}},
+			},
+			{
+				ScoredPost: taste.ScoredPost{
+					Post:  source.Post{Source: "reddit", Channel: "devops", URL: "https://example.com/2"},
+					Score: 4,
+					Tier:  taste.TierSkim,
+				},
+				Summary: summarize.Summary{Bullets: []string{"K8s update"}},
+			},
+			{
+				ScoredPost: taste.ScoredPost{Post: source.Post{Channel: "noise"}, Score: 1, Tier: taste.TierIgnore},
+			},
+		},
+		Channels:   3,
+		TotalPosts: 10,
+		Since:      7 * 24 * time.Hour,
+	}
+}
+
+func TestNotionPublish(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/pages" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("Notion-Version") == "" {
+			t.Errorf("headers = %v", r.Header)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		fmt.Fprint(w, `{"id": "page-1", "url": "https://notion.so/page-1"}`)
+	}))
+	defer srv.Close()
+
+	p := NewNotion("secret", "parent-1")
+	p.endpoint = srv.URL + "/v1"
+	url, err := p.Publish(context.Background(), "noisepan digest", publishTestInput())
+	if err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if url != "https://notion.so/page-1" {
+		t.Errorf("url = %q", url)
+	}
+
+	body, _ := json.Marshal(got)
+	for _, want := range []string{
+		`"page_id":"parent-1"`,
+		`"content":"noisepan digest"`,
+		`"heading_2"`,
+		`"content":"Read Now (1)"`,
+		`"content":"[9] blog — CVE \u003cfound\u003e"`,
+		`"content":"Patch available"`,
+		`"link":{"url":"https://example.com/2"}`,
+		`"content":"Ignored: 1 posts"`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("request missing %s:\n%s", want, body)
+		}
+	}
+}
+
+func TestNotionPublish_AppendsPastBlockLimit(t *testing.T) {
+	input := DigestInput{Channels: 1, TotalPosts: 150, Since: 24 * time.Hour}
+	for i := range 150 {
+		input.Items = append(input.Items, DigestItem{
+			ScoredPost: taste.ScoredPost{Post: source.Post{Channel: "ch"}, Score: 4, Tier: taste.TierSkim},
+			Summary:    summarize.Summary{Bullets: []string{fmt.Sprintf("post %d", i)}},
+		})
+	}
+
+	var sizes []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Children []json.RawMessage `json:"children"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		sizes = append(sizes, len(body.Children))
+		if r.Method == http.MethodPatch && r.URL.Path != "/blocks/page-1/children" {
+			t.Errorf("append path = %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"id": "page-1", "url": "https://notion.so/page-1"}`)
+	}))
+	defer srv.Close()
+
+	p := NewNotion("secret", "parent-1")
+	p.endpoint = srv.URL
+	if _, err := p.Publish(context.Background(), "t", input); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	// Summary line, Skim heading, and 150 bullets.
+	if fmt.Sprint(sizes) != "[100 52]" {
+		t.Errorf("children per request = %v, want [100 52]", sizes)
+	}
+}
+
+func TestNotionPublish_APIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Could not find page"}`, http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	p := NewNotion("secret", "missing")
+	p.endpoint = srv.URL
+	_, err := p.Publish(context.Background(), "t", publishTestInput())
+	if err == nil || !strings.Contains(err.Error(), "404") || !strings.Contains(err.Error(), "Could not find page") {
+		t.Errorf("err = %v, want the status and API message", err)
+	}
+}
+
+func TestNotionText_Truncates(t *testing.T) {
+	rt := notionText(strings.Repeat("x", 3000), "")
+	content := rt[0]["text"].(map[string]any)["content"].(string)
+	if n := len([]rune(content)); n != notionMaxText {
+		t.Errorf("length = %d, want %d", n, notionMaxText)
+	}
+}
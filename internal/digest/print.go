@@ -0,0 +1,182 @@
+package digest
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultPrintWidth fits an A5 page at 10pt in a monospaced font.
+const DefaultPrintWidth = 64
+
+// PrintFormatter formats a digest as plain text for paper: lines wrapped to
+// Width, a form feed before each section so it starts on a new page, and
+// URLs replaced by numbered references listed in a link appendix.
+type PrintFormatter struct {
+	Width int
+}
+
+// NewPrint creates a print formatter with DefaultPrintWidth.
+func NewPrint() *PrintFormatter {
+	return &PrintFormatter{Width: DefaultPrintWidth}
+}
+
+// Format writes the print digest to w.
+func (f *PrintFormatter) Format(w io.Writer, input DigestInput) error {
+	readNow, sections, ignoreCount := groupItems(input)
+	var links []string
+	ref := func(url string) string {
+		if url == "" {
+			return ""
+		}
+		links = append(links, url)
+		return fmt.Sprintf(" [%d]", len(links))
+	}
+
+	fmt.Fprintln(w, "NOISEPAN DIGEST")
+	fmt.Fprintf(w, "%d channels, %d posts, since %s\n\n", input.Channels, input.TotalPosts, formatDuration(input.Since))
+
+	if c := input.Changes; !c.Empty() {
+		fmt.Fprintln(w, "Feed changes")
+		for _, ch := range c.NewChannels {
+			f.wrap(w, "  - ", "New: "+ch)
+		}
+		for _, sc := range c.SilentChannels {
+			f.wrap(w, "  - ", fmt.Sprintf("Silent: %s (last post %s)", sc.Channel, sc.LastPost.Format("2006-01-02")))
+		}
+		for _, ff := range c.FailingFeeds {
+			f.wrap(w, "  - ", fmt.Sprintf("Erroring: %s — %s", ff.Feed, ff.Error))
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(readNow) == 0 && len(sections) == 0 && ignoreCount == 0 && len(input.StillUnread) == 0 {
+		fmt.Fprintln(w, "No posts found.")
+		return nil
+	}
+
+	if len(input.Trending) > 0 {
+		fmt.Fprintf(w, "Trending (appeared in %d+ sources)\n", 3)
+		for _, tr := range input.Trending {
+			f.wrap(w, "  - ", fmt.Sprintf("%q — %d channels: %s", tr.Keyword, len(tr.Channels), strings.Join(tr.Channels, ", ")))
+		}
+		fmt.Fprintln(w)
+	}
+
+	n := 0
+	writeReadNow := func(item DigestItem) {
+		n++
+		marker := fmt.Sprintf("%2d. ", n)
+		f.wrap(w, marker, fmt.Sprintf("[%d] %s — %s%s", item.Score, item.Post.Channel, printHeadline(item), ref(item.Post.URL)))
+		indent := strings.Repeat(" ", len(marker))
+		if note := verifiedNote(item); note != "" {
+			if item.Verification.Conflict {
+				note = strings.ToUpper(note)
+			}
+			f.wrap(w, indent, note)
+		}
+		if len(item.Labels) > 0 {
+			f.wrap(w, indent, "Labels: "+strings.Join(item.Labels, ", "))
+		}
+		if len(item.Summary.Bullets) > 1 {
+			for _, bullet := range item.Summary.Bullets[1:] {
+				f.wrap(w, indent+"- ", bullet)
+			}
+		}
+		if note := affected(item); note != "" {
+			f.wrap(w, indent, note)
+		}
+		if len(item.AlsoIn) > 0 {
+			f.wrap(w, indent, capitalAlsoIn(item))
+		}
+		fmt.Fprintln(w)
+	}
+	if len(readNow) > 0 {
+		fmt.Fprintf(w, "READ NOW (%d)\n\n", len(readNow))
+		for _, item := range readNow {
+			writeReadNow(item)
+		}
+	}
+
+	for i, sec := range sections {
+		if i > 0 || len(readNow) > 0 {
+			fmt.Fprint(w, "\f")
+		}
+		fmt.Fprintf(w, "%s\n\n", strings.ToUpper(sec.title()))
+		for _, item := range sec.Items {
+			if shownInFull(item) {
+				writeReadNow(item)
+				continue
+			}
+			n++
+			text := fmt.Sprintf("[%d] %s — %s%s", item.Score, item.Post.Channel, printHeadline(item), ref(item.Post.URL))
+			if len(item.AlsoIn) > 0 {
+				text += " (" + alsoIn(item) + ")"
+			}
+			if item.Changed {
+				text += " (" + changedNote + ")"
+			}
+			f.wrap(w, fmt.Sprintf("%2d. ", n), text)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(input.StillUnread) > 0 {
+		fmt.Fprintf(w, "%s\n\n", strings.ToUpper(stillUnreadTitle(input)))
+		for _, item := range input.StillUnread {
+			n++
+			f.wrap(w, fmt.Sprintf("%2d. ", n), fmt.Sprintf("[%d] %s — %s%s", item.Score, item.Post.Channel, printHeadline(item), ref(item.Post.URL)))
+		}
+		fmt.Fprintln(w)
+	}
+
+	if ignoreCount > 0 {
+		fmt.Fprintf(w, "Ignored: %d posts\n", ignoreCount)
+	}
+
+	if len(links) > 0 {
+		fmt.Fprint(w, "\fLINKS\n\n")
+		for i, url := range links {
+			// URLs are not wrapped so they can be copied or typed whole.
+			fmt.Fprintf(w, "[%d] %s\n", i+1, url)
+		}
+	}
+
+	return nil
+}
+
+func printHeadline(item DigestItem) string {
+	if len(item.Summary.Bullets) > 0 {
+		return item.Summary.Bullets[0]
+	}
+	return ""
+}
+
+// wrap writes text word-wrapped to the formatter width. The first line
+// starts with prefix; continuation lines are indented to match it.
+func (f *PrintFormatter) wrap(w io.Writer, prefix, text string) {
+	width := f.Width
+	if width <= 0 {
+		width = DefaultPrintWidth
+	}
+	indent := strings.Repeat(" ", utf8.RuneCountInString(prefix))
+	line := prefix
+	lineLen := utf8.RuneCountInString(prefix)
+	empty := true
+	for _, word := range strings.Fields(text) {
+		wordLen := utf8.RuneCountInString(word)
+		if !empty && lineLen+1+wordLen > width {
+			fmt.Fprintln(w, line)
+			line, lineLen, empty = indent, len(indent), true
+		}
+		if !empty {
+			line += " "
+			lineLen++
+		}
+		line += word
+		lineLen += wordLen
+		empty = false
+	}
+	fmt.Fprintln(w, line)
+}
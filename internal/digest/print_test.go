@@ -0,0 +1,114 @@
+package digest
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ppiankov/noisepan/internal/source"
+	"github.com/ppiankov/noisepan/internal/summarize"
+	"github.com/ppiankov/noisepan/internal/taste"
+)
+
+func TestPrintFormat_Full(t *testing.T) {
+	input := DigestInput{
+		Items: []DigestItem{
+			{
+				ScoredPost: taste.ScoredPost{
+					Post:   source.Post{Source: "rss", Channel: "blog", URL: "https://example.com/1"},
+					Score:  9,
+					Tier:   taste.TierReadNow,
+					Labels: []string{"critical", "ops"},
+				},
+				Summary: summarize.Summary{Bullets: []string{
+					"CVE found",
+					"Affects every release of the control plane since v2.0, including the long-term support branches",
+				}},
+				AlsoIn: []string{"telegram/@sec"},
+			},
+			{
+				ScoredPost: taste.ScoredPost{
+					Post:  source.Post{Source: "reddit", Channel: "devops", URL: "https://example.com/2"},
+					Score: 4,
+					Tier:  taste.TierSkim,
+				},
+				Summary: summarize.Summary{Bullets: []string{"K8s update"}},
+			},
+			{
+				ScoredPost: taste.ScoredPost{Post: source.Post{Channel: "noise"}, Score: 1, Tier: taste.TierIgnore},
+				Summary:    summarize.Summary{Bullets: []string{"Ad"}},
+			},
+		},
+		Channels:   3,
+		TotalPosts: 10,
+		Since:      7 * 24 * time.Hour,
+	}
+
+	var buf bytes.Buffer
+	if err := NewPrint().Format(&buf, input); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"NOISEPAN DIGEST\n3 channels, 10 posts, since 7d",
+		"READ NOW (1)",
+		" 1. [9] blog — CVE found [1]\n",
+		"    Labels: critical, ops\n",
+		"    Also in: telegram/@sec\n",
+		"\fSKIM (1)",
+		" 2. [4] devops — K8s update [2]\n",
+		"Ignored: 1 posts",
+		"\fLINKS\n\n[1] https://example.com/1\n[2] https://example.com/2\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	// URLs appear only in the appendix.
+	if strings.Count(out, "https://") != 2 {
+		t.Errorf("expected URLs only in the link appendix:\n%s", out)
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		if n := utf8.RuneCountInString(strings.TrimPrefix(line, "\f")); n > DefaultPrintWidth && !strings.Contains(line, "https://") {
+			t.Errorf("line is %d runes, over width %d: %q", n, DefaultPrintWidth, line)
+		}
+	}
+	requireLine(t, out, "    - Affects every release of the control plane since v2.0,")
+	requireLine(t, out, "      including the long-term support branches")
+}
+
+func TestPrintFormat_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewPrint().Format(&buf, DigestInput{Since: time.Hour}); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	if !strings.Contains(buf.String(), "No posts found.") || strings.Contains(buf.String(), "\f") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+}
+
+func requireLine(t *testing.T, out, line string) {
+	t.Helper()
+	for _, l := range strings.Split(out, "\n") {
+		if l == line {
+			return
+		}
+	}
+	t.Errorf("output has no line %q:\n%s", line, out)
+}
+
+func TestPrintFormat_StillUnread(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewPrint().Format(&buf, stillUnreadInput()); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	out := buf.String()
+	requireLine(t, out, "STILL UNREAD (1)")
+	requireLine(t, out, " 1. [9] @alerts — Cert expires Friday [1]")
+	requireLine(t, out, "[1] https://example.com/cert")
+}
@@ -0,0 +1,210 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// Publisher publishes a digest as a page in a documentation tool and returns
+// the new page's URL.
+type Publisher interface {
+	Publish(ctx context.Context, title string, input DigestInput) (string, error)
+}
+
+// Block kinds of a published page.
+const (
+	blockHeading    = "heading"    // section: Feed changes, Read Now, ...
+	blockSubheading = "subheading" // one read_now post
+	blockParagraph  = "paragraph"
+	blockBullet     = "bullet"
+)
+
+// pageBlock is one block of a published page, independent of the target's
+// markup. URL, when set, links the whole text; Channel is set on the block
+// that opens a post.
+type pageBlock struct {
+	Kind    string
+	Text    string
+	URL     string
+	Channel string
+}
+
+// pageBlocks lays out input the way the Markdown formatter does, minus the
+// top heading, which becomes the page title.
+func pageBlocks(input DigestInput) []pageBlock {
+	readNow, sections, ignoreCount := groupItems(input)
+
+	blocks := []pageBlock{{
+		Kind: blockParagraph,
+		Text: fmt.Sprintf("%d channels, %d posts, since %s", input.Channels, input.TotalPosts, formatDuration(input.Since)),
+	}}
+	add := func(kind, text, url string) {
+		blocks = append(blocks, pageBlock{Kind: kind, Text: text, URL: url})
+	}
+	addPost := func(item DigestItem, kind, text, url string) {
+		blocks = append(blocks, pageBlock{Kind: kind, Text: text, URL: url, Channel: item.Post.Channel})
+	}
+
+	if c := input.Changes; !c.Empty() {
+		add(blockHeading, "Feed changes", "")
+		for _, ch := range c.NewChannels {
+			add(blockBullet, "New: "+ch, "")
+		}
+		for _, sc := range c.SilentChannels {
+			add(blockBullet, fmt.Sprintf("Silent: %s (last post %s)", sc.Channel, sc.LastPost.Format("2006-01-02")), "")
+		}
+		for _, ff := range c.FailingFeeds {
+			add(blockBullet, fmt.Sprintf("Erroring: %s — %s", ff.Feed, ff.Error), "")
+		}
+	}
+
+	if len(readNow) == 0 && len(sections) == 0 && ignoreCount == 0 && len(input.StillUnread) == 0 {
+		add(blockParagraph, "No posts found.", "")
+		return blocks
+	}
+
+	if s := input.Executive; s != nil {
+		add(blockHeading, "Executive summary", "")
+		add(blockParagraph, s.Overview, "")
+		if len(s.Themes) > 0 {
+			add(blockSubheading, "Themes", "")
+			for _, t := range s.Themes {
+				add(blockBullet, t, "")
+			}
+		}
+		if len(s.Urgent) > 0 {
+			add(blockSubheading, "Most urgent", "")
+			for _, u := range s.Urgent {
+				add(blockBullet, u, "")
+			}
+		}
+	}
+
+	if len(input.Trending) > 0 {
+		add(blockHeading, fmt.Sprintf("Trending (appeared in %d+ sources)", 3), "")
+		for _, tr := range input.Trending {
+			add(blockBullet, fmt.Sprintf("%q — mentioned in %d channels: %s",
+				tr.Keyword, len(tr.Channels), strings.Join(tr.Channels, ", ")), "")
+		}
+	}
+
+	addReadNow := func(item DigestItem) {
+		addPost(item, blockSubheading, fmt.Sprintf("[%d] %s — %s", item.Score, item.Post.Channel, headline(item)), "")
+		if note := verifiedNote(item); note != "" {
+			add(blockParagraph, note, "")
+		}
+		if len(item.Labels) > 0 {
+			add(blockParagraph, "Labels: "+strings.Join(item.Labels, ", "), "")
+		}
+		for _, bullet := range bulletsAfterHeadline(item) {
+			add(blockBullet, bullet, "")
+		}
+		if note := affected(item); note != "" {
+			add(blockParagraph, note, "")
+		}
+		if len(item.AlsoIn) > 0 {
+			add(blockParagraph, capitalAlsoIn(item), "")
+		}
+		if item.Changed {
+			add(blockParagraph, changedNote, "")
+		}
+		if item.Post.URL != "" {
+			add(blockParagraph, "Link", item.Post.URL)
+		}
+	}
+	if len(readNow) > 0 {
+		add(blockHeading, fmt.Sprintf("Read Now (%d)", len(readNow)), "")
+		for _, item := range readNow {
+			addReadNow(item)
+		}
+	}
+
+	for _, sec := range sections {
+		add(blockHeading, sec.title(), "")
+		for _, item := range sec.Items {
+			if shownInFull(item) {
+				addReadNow(item)
+				continue
+			}
+			text := fmt.Sprintf("[%d] %s — %s", item.Score, item.Post.Channel, headline(item))
+			if len(item.AlsoIn) > 0 {
+				text += " (" + alsoIn(item) + ")"
+			}
+			if item.Changed {
+				text += fmt.Sprintf(" (%s)", changedNote)
+			}
+			addPost(item, blockBullet, text, item.Post.URL)
+		}
+	}
+
+	if len(input.StillUnread) > 0 {
+		add(blockHeading, stillUnreadTitle(input), "")
+		for _, item := range input.StillUnread {
+			addPost(item, blockBullet, fmt.Sprintf("[%d] %s — %s", item.Score, item.Post.Channel, headline(item)), item.Post.URL)
+		}
+	}
+
+	if ignoreCount > 0 {
+		add(blockParagraph, fmt.Sprintf("Ignored: %d posts", ignoreCount), "")
+	}
+
+	return blocks
+}
+
+func headline(item DigestItem) string {
+	if len(item.Summary.Bullets) > 0 {
+		return item.Summary.Bullets[0]
+	}
+	return ""
+}
+
+func bulletsAfterHeadline(item DigestItem) []string {
+	if len(item.Summary.Bullets) > 1 {
+		return item.Summary.Bullets[1:]
+	}
+	return nil
+}
+
+// pageHTML renders blocks as HTML fragments, which Confluence also accepts as
+// storage format (XHTML). Icons, keyed by channel, are image sources shown
+// before the blocks that open a post in that channel.
+func pageHTML(blocks []pageBlock, icons map[string]string) string {
+	var b strings.Builder
+	inList := false
+	for _, blk := range blocks {
+		if blk.Kind == blockBullet && !inList {
+			b.WriteString("<ul>")
+		} else if blk.Kind != blockBullet && inList {
+			b.WriteString("</ul>")
+		}
+		inList = blk.Kind == blockBullet
+
+		text := html.EscapeString(blk.Text)
+		if blk.URL != "" {
+			text = `<a href="` + html.EscapeString(blk.URL) + `">` + text + "</a>"
+		}
+		if src, ok := icons[blk.Channel]; ok && blk.Channel != "" {
+			text = `<img src="` + html.EscapeString(src) + `" alt="" width="16" height="16" style="vertical-align:middle"/> ` + text
+		}
+		tag := map[string]string{
+			blockHeading:    "h2",
+			blockSubheading: "h3",
+			blockParagraph:  "p",
+			blockBullet:     "li",
+		}[blk.Kind]
+		fmt.Fprintf(&b, "<%s>%s</%s>", tag, text, tag)
+	}
+	if inList {
+		b.WriteString("</ul>")
+	}
+	return b.String()
+}
+
+// readError reads a little of an error response for the error message.
+func readError(r io.Reader) string {
+	b, _ := io.ReadAll(io.LimitReader(r, 512))
+	return strings.TrimSpace(string(b))
+}
@@ -0,0 +1,170 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	telegramEndpoint = "https://api.telegram.org"
+	// telegramMaxMessage is the Bot API limit on a message's text.
+	telegramMaxMessage = 4096
+	// telegramMaxText cuts a single block's text so that any line fits in a
+	// message with room to spare for its link and markup.
+	telegramMaxText = 1000
+)
+
+// telegramSpecial are the characters MarkdownV2 requires escaping in text.
+const telegramSpecial = "_*[]()~`>#+-=|{}.!\\"
+
+// TelegramSender posts the digest to a Telegram chat through a bot, as
+// MarkdownV2 messages split to fit the Bot API's length limit.
+type TelegramSender struct {
+	token    string
+	chatID   string
+	endpoint string
+	client   *http.Client
+}
+
+// NewTelegram creates a Telegram sender. chatID is a numeric chat ID or a
+// public channel's @username; the bot must be allowed to post there.
+func NewTelegram(token, chatID string) *TelegramSender {
+	return &TelegramSender{
+		token:    token,
+		chatID:   chatID,
+		endpoint: telegramEndpoint,
+		client:   &http.Client{Timeout: publishTimeout},
+	}
+}
+
+// SetTransport replaces the HTTP transport used for API requests.
+func (t *TelegramSender) SetTransport(rt http.RoundTripper) {
+	t.client.Transport = rt
+}
+
+// Send posts input with title as a bold first line, in as many messages as
+// it takes. A failed message stops the rest, so the chat never shows a
+// digest with a hole in the middle.
+func (t *TelegramSender) Send(ctx context.Context, title string, input DigestInput) error {
+	messages := telegramMessages(title, input)
+	for i, text := range messages {
+		if err := t.sendMessage(ctx, text); err != nil {
+			return fmt.Errorf("send telegram message %d of %d: %w", i+1, len(messages), err)
+		}
+	}
+	return nil
+}
+
+func (t *TelegramSender) sendMessage(ctx context.Context, text string) error {
+	data, err := json.Marshal(map[string]any{
+		"chat_id":              t.chatID,
+		"text":                 text,
+		"parse_mode":           "MarkdownV2",
+		"link_preview_options": map[string]bool{"is_disabled": true},
+	})
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint+"/bot"+t.token+"/sendMessage", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		// The URL carries the bot token; keep it out of logs.
+		var ue *url.Error
+		if errors.As(err, &ue) {
+			err = ue.Err
+		}
+		return fmt.Errorf("http request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var out struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	if resp.StatusCode != http.StatusOK {
+		if json.NewDecoder(resp.Body).Decode(&out) == nil && out.Description != "" {
+			return fmt.Errorf("api returned status %d: %s", resp.StatusCode, out.Description)
+		}
+		return fmt.Errorf("api returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// telegramMessages renders the page layout as MarkdownV2 and packs it into
+// messages of at most telegramMaxMessage characters. Splits fall between
+// sections or read_now posts where possible, and otherwise between lines,
+// so no escape or link is ever cut in half.
+func telegramMessages(title string, input DigestInput) []string {
+	groups := [][]string{{"*" + telegramEscape(title) + "*"}}
+	for _, b := range pageBlocks(input) {
+		text := telegramEscape(truncate(b.Text, telegramMaxText))
+		if b.URL != "" {
+			text = "[" + text + "](" + telegramEscapeURL(b.URL) + ")"
+		}
+		switch b.Kind {
+		case blockHeading, blockSubheading:
+			groups = append(groups, []string{"", "*" + text + "*"})
+			continue
+		case blockBullet:
+			text = "• " + text
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], text)
+	}
+
+	var messages []string
+	var cur strings.Builder
+	flush := func() {
+		if s := strings.TrimSpace(cur.String()); s != "" {
+			messages = append(messages, s)
+		}
+		cur.Reset()
+	}
+	add := func(s string) {
+		if cur.Len() > 0 && utf8.RuneCountInString(cur.String())+utf8.RuneCountInString(s) > telegramMaxMessage {
+			flush()
+		}
+		cur.WriteString(s)
+	}
+	for _, g := range groups {
+		text := strings.Join(g, "\n") + "\n"
+		if utf8.RuneCountInString(text) <= telegramMaxMessage {
+			add(text)
+			continue
+		}
+		for _, line := range g {
+			add(line + "\n")
+		}
+	}
+	flush()
+	return messages
+}
+
+// telegramEscape escapes s for MarkdownV2 text.
+func telegramEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(telegramSpecial, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// telegramEscapeURL escapes s for the URL part of a MarkdownV2 link, where
+// only ) and \ are special.
+func telegramEscapeURL(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `)`, `\)`).Replace(s)
+}
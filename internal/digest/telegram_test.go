@@ -0,0 +1,113 @@
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ppiankov/noisepan/internal/source"
+	"github.com/ppiankov/noisepan/internal/summarize"
+	"github.com/ppiankov/noisepan/internal/taste"
+)
+
+func TestTelegramSend(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/bot123:abc/sendMessage" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		fmt.Fprint(w, `{"ok": true, "result": {}}`)
+	}))
+	defer srv.Close()
+
+	s := NewTelegram("123:abc", "-100200")
+	s.endpoint = srv.URL
+	if err := s.Send(context.Background(), "noisepan digest 2026-03-01", publishTestInput()); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if got["chat_id"] != "-100200" || got["parse_mode"] != "MarkdownV2" {
+		t.Errorf("request = %v", got)
+	}
+	text, _ := got["text"].(string)
+	for _, want := range []string{
+		`*noisepan digest 2026\-03\-01*`,
+		`*Read Now \(1\)*`,
+		`*\[9\] blog — CVE <found\>*`,
+		"• Patch available",
+		`[Link](https://example.com/1)`,
+		`• [\[4\] devops — K8s update](https://example.com/2)`,
+		"Ignored: 1 posts",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("message missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestTelegramSend_ErrorHidesToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"ok": false, "error_code": 400, "description": "Bad Request: can't parse entities"}`)
+	}))
+	defer srv.Close()
+
+	s := NewTelegram("123:secret", "@me")
+	s.endpoint = srv.URL
+	err := s.Send(context.Background(), "t", publishTestInput())
+	if err == nil || !strings.Contains(err.Error(), "can't parse entities") {
+		t.Fatalf("err = %v, want the API description", err)
+	}
+
+	srv.Close()
+	err = s.Send(context.Background(), "t", publishTestInput())
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("err = %v, want a connection error without the token", err)
+	}
+}
+
+func TestTelegramMessages_Chunking(t *testing.T) {
+	input := DigestInput{Channels: 1, TotalPosts: 50, Since: 24 * time.Hour}
+	long := strings.Repeat("much.", 150)
+	for i := range 50 {
+		input.Items = append(input.Items, DigestItem{
+			ScoredPost: taste.ScoredPost{Post: source.Post{Channel: "ch", URL: fmt.Sprintf("https://example.com/%d", i)}, Score: 9, Tier: taste.TierReadNow},
+			Summary:    summarize.Summary{Bullets: []string{fmt.Sprintf("post %d", i), long}},
+		})
+	}
+
+	messages := telegramMessages("t", input)
+	if len(messages) < 2 {
+		t.Fatalf("messages = %d, want the digest split", len(messages))
+	}
+	posts := 0
+	for i, m := range messages {
+		if n := utf8.RuneCountInString(m); n > telegramMaxMessage {
+			t.Errorf("message %d has %d characters", i, n)
+		}
+		if i > 0 && !strings.HasPrefix(m, `*\[9\] ch — post`) {
+			t.Errorf("message %d splits a post: %.40q", i, m)
+		}
+		posts += strings.Count(m, "*\\[9\\] ch — post ")
+	}
+	if posts != 50 {
+		t.Errorf("posts across messages = %d, want 50", posts)
+	}
+}
+
+func TestTelegramEscape(t *testing.T) {
+	if got := telegramEscape("v2.0 (beta) - 50% off! _a_*b*"); got != `v2\.0 \(beta\) \- 50% off\! \_a\_\*b\*` {
+		t.Errorf("telegramEscape = %q", got)
+	}
+	if got := telegramEscapeURL(`https://example.com/a_(b)\c`); got != `https://example.com/a_(b\)\\c` {
+		t.Errorf("telegramEscapeURL = %q", got)
+	}
+}
@@ -0,0 +1,89 @@
+package digest
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/taste"
+)
+
+// TemplateFormatter renders a digest through a user-supplied Go template.
+// Files ending in .html, .htm or .gohtml use html/template, which escapes
+// post text for HTML; anything else uses text/template.
+type TemplateFormatter struct {
+	tmpl interface {
+		Execute(w io.Writer, data any) error
+	}
+}
+
+// TemplateData is what a digest template is executed with: the digest input
+// and its items grouped into sections as the built-in formats show them.
+type TemplateData struct {
+	DigestInput
+	ReadNow  []DigestItem      // empty in a grouped digest
+	Sections []TemplateSection // tiers between read_now and ignore, highest first, or the groups
+	Ignored  int               // posts ranked ignore, which are not listed
+}
+
+// TemplateSection is one tier's items between read_now and ignore, or one
+// group's items of every tier when the digest is grouped.
+type TemplateSection struct {
+	Tier  string // tier name, e.g. skim; empty in a grouped digest
+	Group string // group name, e.g. a label, in a grouped digest
+	Title string // heading with the post count, e.g. "Skim (4)"
+	Items []DigestItem
+}
+
+// templateFuncs are the helpers templates may call besides the built-ins.
+var templateFuncs = map[string]any{
+	"headline":  headline,
+	"details":   bulletsAfterHeadline,
+	"alsoIn":    alsoIn,
+	"affected":  affected,
+	"verified":  verifiedNote,
+	"tierTitle": taste.TierTitle,
+	"duration":  formatDuration,
+	"join":      strings.Join,
+	"upper":     strings.ToUpper,
+	"lower":     strings.ToLower,
+	"truncate":  func(n int, s string) string { return truncate(s, n) },
+	"repeat":    func(n int, s string) string { return strings.Repeat(s, max(n, 0)) },
+	"date":      func(layout string, t time.Time) string { return t.Format(layout) },
+}
+
+// NewTemplate parses the template at path.
+func NewTemplate(path string) (*TemplateFormatter, error) {
+	name := filepath.Base(path)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html", ".htm", ".gohtml":
+		t, err := htmltemplate.New(name).Funcs(templateFuncs).ParseFiles(path)
+		if err != nil {
+			return nil, fmt.Errorf("parse template: %w", err)
+		}
+		return &TemplateFormatter{tmpl: t}, nil
+	default:
+		t, err := template.New(name).Funcs(templateFuncs).ParseFiles(path)
+		if err != nil {
+			return nil, fmt.Errorf("parse template: %w", err)
+		}
+		return &TemplateFormatter{tmpl: t}, nil
+	}
+}
+
+// Format executes the template with the digest's TemplateData.
+func (f *TemplateFormatter) Format(w io.Writer, input DigestInput) error {
+	readNow, sections, ignoreCount := groupItems(input)
+	data := TemplateData{DigestInput: input, ReadNow: readNow, Ignored: ignoreCount}
+	for _, sec := range sections {
+		data.Sections = append(data.Sections, TemplateSection{Tier: sec.Tier, Group: sec.Group, Title: sec.title(), Items: sec.Items})
+	}
+	if err := f.tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("execute template: %w", err)
+	}
+	return nil
+}
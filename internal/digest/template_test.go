@@ -0,0 +1,111 @@
+package digest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/source"
+	"github.com/ppiankov/noisepan/internal/summarize"
+	"github.com/ppiankov/noisepan/internal/taste"
+)
+
+func templateInput() DigestInput {
+	return DigestInput{
+		Items: []DigestItem{
+			{
+				ScoredPost: taste.ScoredPost{
+					Post:   source.Post{Source: "rss", Channel: "blog", URL: "https://example.com/1", PostedAt: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)},
+					Score:  9,
+					Tier:   taste.TierReadNow,
+					Labels: []string{"critical", "ops"},
+				},
+				Summary: summarize.Summary{Bullets: []string{"CVE <found>", "Patch available"}},
+			},
+			{
+				ScoredPost: taste.ScoredPost{Post: source.Post{Source: "reddit", Channel: "devops"}, Score: 4, Tier: taste.TierSkim},
+				Summary:    summarize.Summary{Bullets: []string{"K8s update"}},
+			},
+			{
+				ScoredPost: taste.ScoredPost{Post: source.Post{Source: "rss", Channel: "noise"}, Score: 1, Tier: taste.TierIgnore},
+			},
+		},
+		Channels:   3,
+		TotalPosts: 10,
+		Since:      48 * time.Hour,
+	}
+}
+
+func writeTemplate(t *testing.T, name, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	return path
+}
+
+func TestTemplateFormat_Text(t *testing.T) {
+	path := writeTemplate(t, "digest.org.tmpl", `#+TITLE: noisepan, {{duration .Since}}
+{{range .ReadNow}}* {{headline .}} [{{.Score}}] :{{join .Labels ":"}}:
+  {{.Post.PostedAt | date "2006-01-02"}}{{range details .}}
+  - {{.}}{{end}}
+{{end}}{{range .Sections}}* {{.Title}}
+{{range .Items}}{{repeat 2 "*"}} {{headline . | upper | truncate 5}}
+{{end}}{{end}}Ignored: {{.Ignored}} of {{.TotalPosts}}
+`)
+	f, err := NewTemplate(path)
+	if err != nil {
+		t.Fatalf("new template: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := f.Format(&buf, templateInput()); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	want := `#+TITLE: noisepan, 2d
+* CVE <found> [9] :critical:ops:
+  2025-01-01
+  - Patch available
+* Skim (1)
+** K8S…
+Ignored: 1 of 10
+`
+	if got := buf.String(); got != want {
+		t.Errorf("output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestTemplateFormat_HTMLEscapes(t *testing.T) {
+	path := writeTemplate(t, "digest.html", `<ul>{{range .ReadNow}}<li><a href="{{.Post.URL}}">{{headline .}}</a></li>{{end}}</ul>`)
+	f, err := NewTemplate(path)
+	if err != nil {
+		t.Fatalf("new template: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := f.Format(&buf, templateInput()); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	if got := buf.String(); !strings.Contains(got, `<a href="https://example.com/1">CVE &lt;found&gt;</a>`) {
+		t.Errorf("output = %s", got)
+	}
+}
+
+func TestTemplateFormat_Errors(t *testing.T) {
+	if _, err := NewTemplate(writeTemplate(t, "bad.tmpl", "{{range .ReadNow}")); err == nil || !strings.Contains(err.Error(), "parse template") {
+		t.Errorf("parse error = %v", err)
+	}
+	if _, err := NewTemplate(filepath.Join(t.TempDir(), "missing.tmpl")); err == nil {
+		t.Error("expected error for a missing file")
+	}
+
+	f, err := NewTemplate(writeTemplate(t, "field.tmpl", "{{.NoSuchField}}"))
+	if err != nil {
+		t.Fatalf("new template: %v", err)
+	}
+	if err := f.Format(&bytes.Buffer{}, templateInput()); err == nil || !strings.Contains(err.Error(), "execute template") {
+		t.Errorf("execute error = %v", err)
+	}
+}
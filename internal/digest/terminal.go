@@ -3,15 +3,18 @@ package digest
 import (
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/ppiankov/noisepan/internal/summarize"
 	"github.com/ppiankov/noisepan/internal/taste"
 )
 
 // TerminalFormatter formats a digest for terminal output.
 type TerminalFormatter struct {
-	color bool
+	color   bool
+	explain bool
 }
 
 // NewTerminal creates a terminal formatter. Set color=true for ANSI colors.
@@ -19,9 +22,15 @@ func NewTerminal(color bool) *TerminalFormatter {
 	return &TerminalFormatter{color: color}
 }
 
+// SetExplain adds a "why:" line with the scoring breakdown under each
+// read_now and skim post.
+func (f *TerminalFormatter) SetExplain(explain bool) {
+	f.explain = explain
+}
+
 // Format writes the digest to w grouped by tier.
 func (f *TerminalFormatter) Format(w io.Writer, input DigestInput) error {
-	readNow, skims, ignoreCount := groupByTier(input.Items)
+	readNow, sections, ignoreCount := groupItems(input)
 
 	// Header
 	sinceStr := formatDuration(input.Since)
@@ -30,11 +39,19 @@ func (f *TerminalFormatter) Format(w io.Writer, input DigestInput) error {
 	fmt.Fprintln(w, f.bold(header))
 	fmt.Fprintln(w)
 
-	if len(readNow) == 0 && len(skims) == 0 && ignoreCount == 0 {
+	if !input.Changes.Empty() {
+		f.writeChanges(w, input.Changes)
+	}
+
+	if len(readNow) == 0 && len(sections) == 0 && ignoreCount == 0 && len(input.StillUnread) == 0 {
 		fmt.Fprintln(w, "No posts found.")
 		return nil
 	}
 
+	if input.Executive != nil {
+		f.writeExecutive(w, *input.Executive)
+	}
+
 	// Trending section
 	if len(input.Trending) > 0 {
 		fmt.Fprintln(w, f.bold(fmt.Sprintf("--- Trending (appeared in %d+ sources) ---", 3)))
@@ -56,16 +73,33 @@ func (f *TerminalFormatter) Format(w io.Writer, input DigestInput) error {
 		}
 	}
 
-	// Skim section
-	if len(skims) > 0 {
-		fmt.Fprintln(w, f.yellow(f.bold(fmt.Sprintf("--- Skim (%d) ---", len(skims)))))
+	// Skim and any other middle tier sections
+	for _, sec := range sections {
+		fmt.Fprintln(w, f.yellow(f.bold(fmt.Sprintf("--- %s ---", sec.title()))))
 		fmt.Fprintln(w)
-		for _, item := range skims {
+		for _, item := range sec.Items {
+			if shownInFull(item) {
+				f.writeReadNowItem(w, item)
+				continue
+			}
 			f.writeSkimItem(w, item)
 		}
 		fmt.Fprintln(w)
 	}
 
+	// Still unread section
+	if len(input.StillUnread) > 0 {
+		fmt.Fprintln(w, f.bold(fmt.Sprintf("--- %s ---", stillUnreadTitle(input))))
+		fmt.Fprintln(w)
+		for _, item := range input.StillUnread {
+			fmt.Fprintf(w, "  [%d] %s — %s\n", item.Score, item.Post.Channel, headline(item))
+			if item.Post.URL != "" {
+				fmt.Fprintf(w, "      %s\n", f.dim(item.Post.URL))
+			}
+		}
+		fmt.Fprintln(w)
+	}
+
 	// Footer
 	if ignoreCount > 0 {
 		fmt.Fprintln(w, f.dim(fmt.Sprintf("Ignored: %d posts (noise suppressed)", ignoreCount)))
@@ -91,17 +125,32 @@ func (f *TerminalFormatter) writeReadNowItem(w io.Writer, item DigestItem) {
 		item.Post.Channel,
 		firstBullet,
 	)
+	if note := verifiedNote(item); note != "" {
+		if item.Verification.Conflict {
+			note = f.bold(f.yellow(note))
+		} else {
+			note = f.dim(note)
+		}
+		fmt.Fprintf(w, "      %s\n", note)
+	}
 
 	// Additional bullets indented
 	for _, bullet := range item.Summary.Bullets[1:] {
 		fmt.Fprintf(w, "      %s\n", f.dim(bullet))
 	}
+	if note := affected(item); note != "" {
+		fmt.Fprintf(w, "      %s\n", f.dim(note))
+	}
 	if item.Post.URL != "" {
 		fmt.Fprintf(w, "      %s\n", f.dim(item.Post.URL))
 	}
 	if len(item.AlsoIn) > 0 {
-		fmt.Fprintf(w, "      %s\n", f.dim("also in: "+strings.Join(item.AlsoIn, ", ")))
+		fmt.Fprintf(w, "      %s\n", f.dim(alsoIn(item)))
+	}
+	if item.Changed {
+		fmt.Fprintf(w, "      %s\n", f.dim(changedNote))
 	}
+	f.writeExplanation(w, item)
 	fmt.Fprintln(w)
 }
 
@@ -116,22 +165,111 @@ func (f *TerminalFormatter) writeSkimItem(w io.Writer, item DigestItem) {
 		fmt.Fprintf(w, "      %s\n", f.dim(item.Post.URL))
 	}
 	if len(item.AlsoIn) > 0 {
-		fmt.Fprintf(w, "      %s\n", f.dim("also in: "+strings.Join(item.AlsoIn, ", ")))
+		fmt.Fprintf(w, "      %s\n", f.dim(alsoIn(item)))
 	}
+	if item.Changed {
+		fmt.Fprintf(w, "      %s\n", f.dim(changedNote))
+	}
+	f.writeExplanation(w, item)
 }
 
-func groupByTier(items []DigestItem) (readNow, skims []DigestItem, ignoreCount int) {
-	for _, item := range items {
+// writeExplanation writes the reasons item scored what it did, with
+// SetExplain.
+func (f *TerminalFormatter) writeExplanation(w io.Writer, item DigestItem) {
+	if !f.explain || len(item.Explanation) == 0 {
+		return
+	}
+	reasons := make([]string, len(item.Explanation))
+	for i, c := range item.Explanation {
+		reasons[i] = fmt.Sprintf("%+d %s", c.Points, c.Reason)
+	}
+	fmt.Fprintf(w, "      %s\n", f.dim("why: "+strings.Join(reasons, "; ")))
+}
+
+func (f *TerminalFormatter) writeChanges(w io.Writer, c FeedChanges) {
+	fmt.Fprintln(w, f.bold("--- Feed changes ---"))
+	fmt.Fprintln(w)
+	for _, ch := range c.NewChannels {
+		fmt.Fprintf(w, "  new:      %s\n", ch)
+	}
+	for _, sc := range c.SilentChannels {
+		fmt.Fprintf(w, "  silent:   %s %s\n", sc.Channel, f.dim("— last post "+sc.LastPost.Format("2006-01-02")))
+	}
+	for _, ff := range c.FailingFeeds {
+		fmt.Fprintf(w, "  erroring: %s %s\n", ff.Feed, f.dim("— "+ff.Error))
+	}
+	fmt.Fprintln(w)
+}
+
+func (f *TerminalFormatter) writeExecutive(w io.Writer, s summarize.DigestSummary) {
+	fmt.Fprintln(w, f.bold("--- Executive summary ---"))
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %s\n", s.Overview)
+	if len(s.Themes) > 0 {
+		fmt.Fprintf(w, "\n  %s\n", f.bold("Themes"))
+		for _, t := range s.Themes {
+			fmt.Fprintf(w, "    • %s\n", t)
+		}
+	}
+	if len(s.Urgent) > 0 {
+		fmt.Fprintf(w, "\n  %s\n", f.bold("Most urgent"))
+		for _, u := range s.Urgent {
+			fmt.Fprintf(w, "    • %s\n", f.green(u))
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// tierSection holds the posts of one tier between read_now and ignore, or
+// of one group when the digest is grouped by topic.
+type tierSection struct {
+	Tier  string
+	Group string // set instead of Tier in a grouped digest
+	Items []DigestItem
+}
+
+// title heads the section, with its post count.
+func (s tierSection) title() string {
+	if s.Group != "" {
+		return fmt.Sprintf("%s (%d)", s.Group, len(s.Items))
+	}
+	return fmt.Sprintf("%s (%d)", taste.TierTitle(s.Tier), len(s.Items))
+}
+
+// groupByTier splits input's items into read_now, a section per middle tier
+// in the order input.Tiers lists them, and a count of the ignored rest.
+// Items from a tier the list lacks, say scored under an older profile, get a
+// section after the known ones.
+func groupByTier(input DigestInput) (readNow []DigestItem, sections []tierSection, ignoreCount int) {
+	tiers := input.Tiers
+	if len(tiers) == 0 {
+		tiers = []string{taste.TierReadNow, taste.TierSkim, taste.TierIgnore}
+	}
+	index := make(map[string]int)
+	for _, tier := range tiers {
+		if tier != taste.TierReadNow && tier != taste.TierIgnore {
+			index[tier] = len(sections)
+			sections = append(sections, tierSection{Tier: tier})
+		}
+	}
+	for _, item := range input.Items {
 		switch item.Tier {
 		case taste.TierReadNow:
 			readNow = append(readNow, item)
-		case taste.TierSkim:
-			skims = append(skims, item)
-		default:
+		case taste.TierIgnore, "":
 			ignoreCount++
+		default:
+			i, ok := index[item.Tier]
+			if !ok {
+				i = len(sections)
+				index[item.Tier] = i
+				sections = append(sections, tierSection{Tier: item.Tier})
+			}
+			sections[i].Items = append(sections[i].Items, item)
 		}
 	}
-	return
+	sections = slices.DeleteFunc(sections, func(s tierSection) bool { return len(s.Items) == 0 })
+	return readNow, sections, ignoreCount
 }
 
 func formatDuration(d time.Duration) string {
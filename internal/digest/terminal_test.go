@@ -2,6 +2,7 @@ package digest
 
 import (
 	"bytes"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -141,6 +142,38 @@ func TestFormat_SkimOneLiner(t *testing.T) {
 	}
 }
 
+func TestFormat_Explain(t *testing.T) {
+	readNow := makeItem(taste.TierReadNow, 9, "sec", nil, []string{"CVE found"})
+	readNow.Explanation = []taste.ScoreContribution{{Reason: "keyword: cve", Points: 5}, {Reason: "rule: cve, zero-day", Points: 5}, {Reason: "keyword: sponsor", Points: -1}}
+	skim := makeItem(taste.TierSkim, 4, "k8s", nil, []string{"New release"})
+	skim.Explanation = []taste.ScoreContribution{{Reason: "keyword: kubernetes", Points: 4}}
+	input := DigestInput{Items: []DigestItem{readNow, skim}, Channels: 2, TotalPosts: 2, Since: 24 * time.Hour}
+
+	var buf bytes.Buffer
+	if err := NewTerminal(false).Format(&buf, input); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	if strings.Contains(buf.String(), "why:") {
+		t.Errorf("explanations shown without SetExplain:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	f := NewTerminal(false)
+	f.SetExplain(true)
+	if err := f.Format(&buf, input); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"      why: +5 keyword: cve; +5 rule: cve, zero-day; -1 keyword: sponsor\n",
+		"      why: +4 keyword: kubernetes\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
 func TestFormat_IgnoreCount(t *testing.T) {
 	f := NewTerminal(false)
 	var buf bytes.Buffer
@@ -165,6 +198,45 @@ func TestFormat_IgnoreCount(t *testing.T) {
 	}
 }
 
+func TestFormat_CustomTiers(t *testing.T) {
+	f := NewTerminal(false)
+	var buf bytes.Buffer
+
+	input := DigestInput{
+		Items: []DigestItem{
+			makeItem(taste.TierReadNow, 9, "security", nil, []string{"CVE found"}),
+			makeItem("weekend", 3, "blog", nil, []string{"Long read"}),
+			makeItem("today", 6, "devops", nil, []string{"Release out"}),
+			makeItem("retired", 4, "old", nil, []string{"Scored under an older profile"}),
+			makeItem(taste.TierIgnore, 0, "spam", nil, []string{"ad"}),
+		},
+		Tiers:      []string{taste.TierReadNow, "today", "weekend", "later", taste.TierIgnore},
+		Channels:   4,
+		TotalPosts: 5,
+		Since:      24 * time.Hour,
+	}
+
+	if err := f.Format(&buf, input); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	out := buf.String()
+
+	var at []int
+	for _, heading := range []string{"--- Read Now (1) ---", "--- Today (1) ---", "--- Weekend (1) ---", "--- Retired (1) ---", "Ignored: 1 posts"} {
+		i := strings.Index(out, heading)
+		if i < 0 {
+			t.Fatalf("missing %q in:\n%s", heading, out)
+		}
+		at = append(at, i)
+	}
+	if !slices.IsSorted(at) {
+		t.Errorf("sections out of tier order:\n%s", out)
+	}
+	if strings.Contains(out, "Later") || strings.Contains(out, "Skim") {
+		t.Errorf("empty or unconfigured tier shown:\n%s", out)
+	}
+}
+
 func TestFormat_EmptyInput(t *testing.T) {
 	f := NewTerminal(false)
 	var buf bytes.Buffer
@@ -286,6 +358,106 @@ func TestFormat_AlsoIn_Skim(t *testing.T) {
 	}
 }
 
+func TestFormat_Affected(t *testing.T) {
+	item := makeItem(taste.TierReadNow, 10, "security", nil, []string{"OpenSSL fix"})
+	item.Summary.Products = []string{"OpenSSL"}
+	item.Summary.Vendors = []string{"OpenSSL Project"}
+	item.Summary.Versions = []string{">= 3.0.0, < 3.5.1"}
+	item.Summary.CVEs = []string{"CVE-2026-1234"}
+	input := DigestInput{Items: []DigestItem{item}, Channels: 1, TotalPosts: 1, Since: 24 * time.Hour}
+
+	const want = "Affected: OpenSSL · >= 3.0.0, < 3.5.1 · CVE-2026-1234"
+	for name, f := range map[string]Formatter{
+		"terminal": NewTerminal(false),
+		"markdown": NewMarkdown(),
+		"print":    NewPrint(),
+	} {
+		var buf bytes.Buffer
+		if err := f.Format(&buf, input); err != nil {
+			t.Fatalf("%s: format: %v", name, err)
+		}
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("%s output = %q, want containing %q", name, buf.String(), want)
+		}
+	}
+	if out := pageHTML(pageBlocks(input), nil); !strings.Contains(out, "<p>Affected: OpenSSL · &gt;= 3.0.0, &lt; 3.5.1 · CVE-2026-1234</p>") {
+		t.Errorf("html = %q, want an Affected paragraph", out)
+	}
+
+	item.Summary.Products = nil
+	if got := affected(item); got != "Affected: OpenSSL Project · >= 3.0.0, < 3.5.1 · CVE-2026-1234" {
+		t.Errorf("no products: affected = %q, want the vendor", got)
+	}
+	if got := affected(makeItem(taste.TierReadNow, 10, "security", nil, []string{"x"})); got != "" {
+		t.Errorf("no entities: affected = %q, want empty", got)
+	}
+}
+
+func TestFormat_AffectedSkipsShownIDs(t *testing.T) {
+	h := &summarize.HeuristicSummarizer{Products: map[string][]string{"OpenSSL Project": {"OpenSSL"}}}
+	item := makeItem(taste.TierReadNow, 10, "security", nil, nil)
+	item.Summary = h.Summarize("OpenSSL ships a fix. The advisory covers CVE-2026-1111 in all builds.")
+	input := DigestInput{Items: []DigestItem{item}, Channels: 1, TotalPosts: 1, Since: 24 * time.Hour}
+
+	for name, f := range map[string]Formatter{"terminal": NewTerminal(false), "markdown": NewMarkdown()} {
+		var buf bytes.Buffer
+		if err := f.Format(&buf, input); err != nil {
+			t.Fatalf("%s: format: %v", name, err)
+		}
+		out := buf.String()
+		if n := strings.Count(out, "CVE-2026-1111"); n != 1 {
+			t.Errorf("%s: CVE shown %d times, want once:\n%s", name, n, out)
+		}
+		if !strings.Contains(out, "Affected: OpenSSL") {
+			t.Errorf("%s output = %q, want an Affected line with the product", name, out)
+		}
+	}
+}
+
+func TestFormat_AlsoIn_Truncated(t *testing.T) {
+	f := NewTerminal(false)
+	var buf bytes.Buffer
+
+	item := makeItem(taste.TierReadNow, 10, "security", nil, []string{"CVE found"})
+	item.AlsoIn = []string{"rss/a", "hn/b", "reddit/c", "reddit/d", "telegram/@e", "telegram/@f"}
+
+	if err := f.Format(&buf, DigestInput{Items: []DigestItem{item}, Channels: 1, TotalPosts: 1, Since: 24 * time.Hour}); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+
+	if want := "also in 6 channels: rss/a, hn/b, reddit/c and 3 more"; !strings.Contains(buf.String(), want) {
+		t.Errorf("output = %q, want containing %q", buf.String(), want)
+	}
+	if strings.Contains(buf.String(), "telegram/@f") {
+		t.Error("truncated channels are still listed")
+	}
+}
+
+func TestFormat_ChangedSinceScoring(t *testing.T) {
+	f := NewTerminal(false)
+	var buf bytes.Buffer
+
+	edited := makeItem(taste.TierReadNow, 9, "security", nil, []string{"Patch"})
+	edited.Changed = true
+	input := DigestInput{
+		Items: []DigestItem{
+			edited,
+			makeItem(taste.TierSkim, 4, "devops", nil, []string{"Update"}),
+		},
+		Channels:   2,
+		TotalPosts: 2,
+		Since:      24 * time.Hour,
+	}
+
+	if err := f.Format(&buf, input); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+
+	if n := strings.Count(buf.String(), changedNote); n != 1 {
+		t.Errorf("%q appears %d times, want 1:\n%s", changedNote, n, buf.String())
+	}
+}
+
 func TestFormat_DurationDays(t *testing.T) {
 	f := NewTerminal(false)
 	var buf bytes.Buffer
@@ -304,3 +476,147 @@ func TestFormat_DurationDays(t *testing.T) {
 		t.Errorf("output = %q, want containing 'since 3d'", buf.String())
 	}
 }
+
+func TestFormat_FeedChanges(t *testing.T) {
+	f := NewTerminal(false)
+	var buf bytes.Buffer
+
+	input := DigestInput{
+		Items: []DigestItem{
+			makeItem(taste.TierReadNow, 10, "security", nil, []string{"CVE found"}),
+		},
+		Changes: FeedChanges{
+			NewChannels:    []string{"rss/Kubernetes Blog"},
+			SilentChannels: []SilentChannel{{Channel: "telegram/@quiet", LastPost: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}},
+			FailingFeeds:   []FailingFeed{{Feed: "rss/https://a/feed", Error: "HTTP 500"}},
+		},
+		Channels:   1,
+		TotalPosts: 1,
+		Since:      24 * time.Hour,
+	}
+
+	if err := f.Format(&buf, input); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"--- Feed changes ---",
+		"new:      rss/Kubernetes Blog",
+		"silent:   telegram/@quiet — last post 2026-03-01",
+		"erroring: rss/https://a/feed — HTTP 500",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormat_NoFeedChangesSection(t *testing.T) {
+	f := NewTerminal(false)
+	var buf bytes.Buffer
+
+	input := DigestInput{
+		Items: []DigestItem{makeItem(taste.TierSkim, 3, "devops", nil, []string{"Helm"})},
+		Since: 24 * time.Hour,
+	}
+	if err := f.Format(&buf, input); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	if strings.Contains(buf.String(), "Feed changes") {
+		t.Error("empty changes should not render a section")
+	}
+}
+
+// stillUnreadInput has only items carried over from earlier digests.
+func stillUnreadInput() DigestInput {
+	item := makeItem(taste.TierReadNow, 9, "@alerts", nil, []string{"Cert expires Friday", "Renew via ACME"})
+	item.Post.URL = "https://example.com/cert"
+	return DigestInput{Channels: 1, Since: 24 * time.Hour, StillUnread: []DigestItem{item}}
+}
+
+func TestFormat_StillUnread(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewTerminal(false).Format(&buf, stillUnreadInput()); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"--- Still unread (1) ---",
+		"  [9] @alerts — Cert expires Friday\n",
+		"      https://example.com/cert\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "No posts found") || strings.Contains(out, "Renew via ACME") {
+		t.Errorf("still unread items should be one line each:\n%s", out)
+	}
+}
+
+func TestFormat_Verification(t *testing.T) {
+	verified := makeItem(taste.TierReadNow, 10, "security", nil, []string{"CVE found"})
+	verified.Verification = &Verification{Support: 75, Confidence: "high"}
+	disputed := makeItem(taste.TierReadNow, 9, "news", nil, []string{"Outage claimed"})
+	disputed.Verification = &Verification{Support: 30, Confidence: "low", Conflict: true}
+	skim := makeItem(taste.TierSkim, 4, "misc", nil, []string{"Side note"})
+	skim.Verification = &Verification{Support: 90}
+
+	var buf bytes.Buffer
+	input := DigestInput{Items: []DigestItem{verified, disputed, skim}, Channels: 3, TotalPosts: 3, Since: 24 * time.Hour}
+	if err := NewTerminal(false).Format(&buf, input); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"verified 75/100, high confidence", "⚠ conflicting sources — verified 30/100, low confidence"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "verified 90/100") {
+		t.Errorf("skim item annotated:\n%s", out)
+	}
+}
+
+func executiveInput() DigestInput {
+	return DigestInput{
+		Channels: 1,
+		Since:    24 * time.Hour,
+		Items:    []DigestItem{makeItem(taste.TierReadNow, 9, "@security", nil, []string{"OpenSSL 3.5.1 <fixes> a crash"})},
+		Trending: []Trend{{Keyword: "openssl", Channels: []string{"a", "b", "c"}}},
+		Executive: &summarize.DigestSummary{
+			Overview: "A quiet day apart from an OpenSSL fix.",
+			Themes:   []string{"OpenSSL patches"},
+			Urgent:   []string{"Patch OpenSSL (@security)"},
+		},
+	}
+}
+
+func TestFormat_Executive(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewTerminal(false).Format(&buf, executiveInput()); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	out := buf.String()
+	want := "--- Executive summary ---\n\n" +
+		"  A quiet day apart from an OpenSSL fix.\n\n" +
+		"  Themes\n    • OpenSSL patches\n\n" +
+		"  Most urgent\n    • Patch OpenSSL (@security)\n\n"
+	if !strings.Contains(out, want) {
+		t.Errorf("output missing %q:\n%s", want, out)
+	}
+	if strings.Index(out, "Executive summary") > strings.Index(out, "Trending") {
+		t.Errorf("executive summary should come before trending:\n%s", out)
+	}
+
+	buf.Reset()
+	input := executiveInput()
+	input.Executive = nil
+	if err := NewTerminal(false).Format(&buf, input); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	if strings.Contains(buf.String(), "Executive summary") {
+		t.Errorf("executive summary without one:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,162 @@
+// Package embed computes embeddings of post text through an
+// OpenAI-compatible /v1/embeddings API — OpenAI itself, or a local server
+// such as Ollama, llama.cpp or LocalAI — and compares them.
+package embed
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"time"
+)
+
+const (
+	httpTimeout = 60 * time.Second
+	// maxErrorBody bounds how much of an error response is quoted.
+	maxErrorBody = 512
+)
+
+// Vector is an embedding.
+type Vector []float32
+
+// Embedder turns texts into vectors, one per text and in order.
+type Embedder interface {
+	// Model names the model, so vectors of different models are never
+	// compared.
+	Model() string
+	Embed(ctx context.Context, texts []string) ([]Vector, error)
+}
+
+// Client is an Embedder for an OpenAI-compatible embeddings endpoint.
+type Client struct {
+	endpoint string
+	apiKey   string // empty sends no Authorization header
+	model    string
+	client   *http.Client
+}
+
+// New creates a client for endpoint and model.
+func New(endpoint, apiKey, model string) *Client {
+	return &Client{
+		endpoint: endpoint,
+		apiKey:   apiKey,
+		model:    model,
+		client:   &http.Client{Timeout: httpTimeout},
+	}
+}
+
+// SetTransport replaces the HTTP transport used for API requests.
+func (c *Client) SetTransport(rt http.RoundTripper) {
+	c.client.Transport = rt
+}
+
+// Model returns the model name.
+func (c *Client) Model() string {
+	return c.model
+}
+
+type embedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type embedResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embed requests the embeddings of texts in one call.
+func (c *Client) Embed(ctx context.Context, texts []string) ([]Vector, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	body, err := json.Marshal(embedRequest{Model: c.model, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return nil, fmt.Errorf("embeddings api returned status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+
+	var out embedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	vectors := make([]Vector, len(texts))
+	for _, d := range out.Data {
+		if d.Index < 0 || d.Index >= len(texts) {
+			return nil, fmt.Errorf("response index %d out of range", d.Index)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	for i, v := range vectors {
+		if len(v) == 0 {
+			return nil, fmt.Errorf("no embedding for input %d", i)
+		}
+	}
+	return vectors, nil
+}
+
+// Cosine returns the cosine similarity of a and b: 1 for the same
+// direction, 0 for unrelated. Vectors of different lengths, or a zero
+// vector, give 0.
+func Cosine(a, b Vector) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		x, y := float64(a[i]), float64(b[i])
+		dot += x * y
+		na += x * x
+		nb += y * y
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// Encode packs v as little-endian float32s for storage.
+func (v Vector) Encode() []byte {
+	b := make([]byte, 4*len(v))
+	for i, x := range v {
+		binary.LittleEndian.PutUint32(b[4*i:], math.Float32bits(x))
+	}
+	return b
+}
+
+// Decode unpacks a vector packed by Encode.
+func Decode(b []byte) (Vector, error) {
+	if len(b)%4 != 0 {
+		return nil, errors.New("embedding is not a whole number of float32s")
+	}
+	v := make(Vector, len(b)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[4*i:]))
+	}
+	return v, nil
+}
@@ -0,0 +1,97 @@
+package embed
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestClientEmbed(t *testing.T) {
+	var got embedRequest
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		// Out of order, as the API allows.
+		_, _ = w.Write([]byte(`{"data":[{"index":1,"embedding":[0,1]},{"index":0,"embedding":[1,0]}]}`))
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "sk-test", "m")
+	vecs, err := c.Embed(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("embed: %v", err)
+	}
+	if got.Model != "m" || !slices.Equal(got.Input, []string{"a", "b"}) || auth != "Bearer sk-test" {
+		t.Errorf("request = %+v, auth %q", got, auth)
+	}
+	if len(vecs) != 2 || !slices.Equal(vecs[0], Vector{1, 0}) || !slices.Equal(vecs[1], Vector{0, 1}) {
+		t.Errorf("vectors = %v", vecs)
+	}
+
+	// A local server gets no key.
+	if _, err := New(srv.URL, "", "m").Embed(context.Background(), []string{"a", "b"}); err != nil {
+		t.Fatalf("embed without key: %v", err)
+	}
+	if auth != "" {
+		t.Errorf("authorization = %q, want none", auth)
+	}
+}
+
+func TestClientEmbed_Errors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/short" {
+			_, _ = w.Write([]byte(`{"data":[{"index":0,"embedding":[1]}]}`))
+			return
+		}
+		http.Error(w, "model not found", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	_, err := New(srv.URL, "", "m").Embed(context.Background(), []string{"a"})
+	if err == nil || !strings.Contains(err.Error(), "404") || !strings.Contains(err.Error(), "model not found") {
+		t.Errorf("err = %v, want status and message", err)
+	}
+	if _, err := New(srv.URL+"/short", "", "m").Embed(context.Background(), []string{"a", "b"}); err == nil {
+		t.Error("expected error for a missing embedding")
+	}
+}
+
+func TestCosine(t *testing.T) {
+	tests := []struct {
+		a, b Vector
+		want float64
+	}{
+		{Vector{1, 0}, Vector{2, 0}, 1},
+		{Vector{1, 0}, Vector{0, 3}, 0},
+		{Vector{1, 1}, Vector{-1, -1}, -1},
+		{Vector{1, 0}, Vector{1, 0, 0}, 0},
+		{Vector{0, 0}, Vector{1, 0}, 0},
+	}
+	for _, tt := range tests {
+		if got := Cosine(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Cosine(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	v := Vector{0.25, -1.5, 3e-7}
+	got, err := Decode(v.Encode())
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !slices.Equal(got, v) {
+		t.Errorf("round trip = %v, want %v", got, v)
+	}
+	if _, err := Decode([]byte{1, 2, 3}); err == nil {
+		t.Error("expected error for a truncated vector")
+	}
+}
@@ -0,0 +1,229 @@
+// Package entities finds what a post is about in its text: products and
+// vendors from a dictionary, versions and semver ranges, CVE and GHSA IDs,
+// IP addresses and CIDR prefixes, and dates.
+package entities
+
+import (
+	"net/netip"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kinds of entity, as taste rules name them in has_entity.
+const (
+	KindProduct = "product"
+	KindVendor  = "vendor"
+	KindVersion = "version"
+	KindCVE     = "cve"
+	KindGHSA    = "ghsa"
+	KindIP      = "ip"
+	KindDate    = "date"
+)
+
+// Kinds lists every kind of entity.
+var Kinds = []string{KindProduct, KindVendor, KindVersion, KindCVE, KindGHSA, KindIP, KindDate}
+
+const (
+	semver = `v?\d+\.\d+(?:\.\d+)?(?:-[0-9A-Za-z]+(?:\.[0-9A-Za-z]+)*)?`
+	months = `January|February|March|April|May|June|July|August|September|October|November|December|` +
+		`Jan|Feb|Mar|Apr|Jun|Jul|Aug|Sept|Sep|Oct|Nov|Dec`
+)
+
+var (
+	cveRe     = regexp.MustCompile(`CVE-\d{4}-\d{4,}`)
+	versionRe = regexp.MustCompile(`v?\d+\.\d+\.\d+`)
+	ghsaRe    = regexp.MustCompile(`\bGHSA(?:-[23456789cfghjmpqrvwx]{4}){3}\b`)
+	// rangeRe matches semver ranges as advisories write them: comparator
+	// chains ("< 2.3.4", ">= 2.0.0, < 2.3.4", "^1.2"), hyphen ranges
+	// ("1.2.0 - 1.2.5"), and wildcards ("1.x", "2.4.*").
+	rangeRe = regexp.MustCompile(`(?:[<>]=?|[=~^])\s*` + semver + `(?:\s*,?\s*[<>]=?\s*` + semver + `)*` +
+		`|\b\d+\.\d+\.\d+\s+-\s+\d+\.\d+\.\d+\b` +
+		`|\b\d+\.(?:\d+\.)?[xX*]`)
+	// addrTokenRe matches the runs of text an address or CIDR prefix can
+	// be, to parse.
+	addrTokenRe = regexp.MustCompile(`[0-9A-Za-z:./%]+`)
+	isoDateRe   = regexp.MustCompile(`\b\d{4}-\d{2}-\d{2}\b`)
+	mdyDateRe   = regexp.MustCompile(`(?i)\b(` + months + `)\.?\s+(\d{1,2})(?:st|nd|rd|th)?,?\s+(\d{4})\b`)
+	dmyDateRe   = regexp.MustCompile(`(?i)\b(\d{1,2})(?:st|nd|rd|th)?\s+(` + months + `)\.?,?\s+(\d{4})\b`)
+)
+
+// Set is the entities found in a text, each kind in order of appearance
+// unless noted, without repeats.
+type Set struct {
+	Products []string // from the dictionary, as spelled there; sorted
+	Vendors  []string // of those products, or named themselves; sorted
+	Versions []string // versions and semver ranges, e.g. ">= 2.0.0, < 2.3.4"
+	CVEs     []string
+	GHSAs    []string // GitHub security advisory IDs
+	IPs      []string // IP addresses and CIDR prefixes
+	Dates    []string // YYYY-MM-DD
+}
+
+// Extract returns the entities of text. Products lists, per vendor, the
+// product names to look for.
+func Extract(text string, products map[string][]string) Set {
+	var s Set
+	s.Products, s.Vendors = findProducts(text, products)
+	s.CVEs = uniq(cveRe.FindAllString(text, -1))
+	s.GHSAs = uniq(ghsaRe.FindAllString(text, -1))
+
+	// Addresses and ranges are blanked before looking for plain versions,
+	// or 10.0.0.1 would read as version 10.0.0.
+	var rest string
+	s.IPs, rest = findAddrs(text)
+	s.Versions = rangeRe.FindAllString(rest, -1)
+	for i, r := range s.Versions {
+		s.Versions[i] = strings.Join(strings.Fields(r), " ")
+	}
+	rest = rangeRe.ReplaceAllStringFunc(rest, blank)
+	s.Versions = uniq(append(s.Versions, versionRe.FindAllString(rest, -1)...))
+
+	s.Dates = findDates(text)
+	return s
+}
+
+// Has reports whether s holds any entity of kind.
+func (s Set) Has(kind string) bool {
+	var found []string
+	switch kind {
+	case KindProduct:
+		found = s.Products
+	case KindVendor:
+		found = s.Vendors
+	case KindVersion:
+		found = s.Versions
+	case KindCVE:
+		found = s.CVEs
+	case KindGHSA:
+		found = s.GHSAs
+	case KindIP:
+		found = s.IPs
+	case KindDate:
+		found = s.Dates
+	}
+	return len(found) > 0
+}
+
+// findProducts returns the products of dictionary, keyed by vendor, that
+// text names as whole words, case-insensitively, and the vendors of those
+// products or named themselves; both sorted, as the dictionary spells them.
+func findProducts(text string, dictionary map[string][]string) (products, vendors []string) {
+	if len(dictionary) == 0 {
+		return nil, nil
+	}
+	lower := strings.ToLower(text)
+	for vendor, names := range dictionary {
+		named := containsWord(lower, strings.ToLower(vendor))
+		for _, name := range names {
+			if containsWord(lower, strings.ToLower(name)) {
+				products = append(products, name)
+				named = true
+			}
+		}
+		if named {
+			vendors = append(vendors, vendor)
+		}
+	}
+	slices.Sort(products)
+	slices.Sort(vendors)
+	return slices.Compact(products), vendors
+}
+
+// containsWord reports whether word occurs in text not inside a longer
+// word: with no letter or digit right before or after it.
+func containsWord(text, word string) bool {
+	if word == "" {
+		return false
+	}
+	for i := 0; ; {
+		j := strings.Index(text[i:], word)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(word)
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if !isWordRune(before) && !isWordRune(after) {
+			return true
+		}
+		i = start + 1
+	}
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// findAddrs returns the IP addresses and CIDR prefixes in text, with a port
+// dropped, and text with them blanked out.
+func findAddrs(text string) (addrs []string, rest string) {
+	rest = addrTokenRe.ReplaceAllStringFunc(text, func(tok string) string {
+		trimmed := strings.TrimRight(tok, ".:")
+		if strings.Count(trimmed, ".") < 3 && strings.Count(trimmed, ":") < 2 {
+			return tok
+		}
+		if p, err := netip.ParsePrefix(trimmed); err == nil {
+			addrs = append(addrs, p.String())
+		} else if a, err := netip.ParseAddr(trimmed); err == nil {
+			addrs = append(addrs, a.String())
+		} else if ap, err := netip.ParseAddrPort(trimmed); err == nil {
+			addrs = append(addrs, ap.Addr().String())
+		} else {
+			return tok
+		}
+		return blank(tok)
+	})
+	return uniq(addrs), rest
+}
+
+// findDates returns the dates in text written as 2026-03-02, March 2, 2026,
+// or 2 Mar 2026, as YYYY-MM-DD in order of appearance.
+func findDates(text string) []string {
+	type found struct {
+		at   int
+		date string
+	}
+	var dates []found
+	for _, m := range isoDateRe.FindAllStringIndex(text, -1) {
+		if d, err := time.Parse(time.DateOnly, text[m[0]:m[1]]); err == nil {
+			dates = append(dates, found{m[0], d.Format(time.DateOnly)})
+		}
+	}
+	add := func(m []int, month, day, year string) {
+		if d, err := time.Parse("Jan 2 2006", strings.ToUpper(month[:1])+strings.ToLower(month[1:3])+" "+day+" "+year); err == nil {
+			dates = append(dates, found{m[0], d.Format(time.DateOnly)})
+		}
+	}
+	for _, m := range mdyDateRe.FindAllStringSubmatchIndex(text, -1) {
+		add(m, text[m[2]:m[3]], text[m[4]:m[5]], text[m[6]:m[7]])
+	}
+	for _, m := range dmyDateRe.FindAllStringSubmatchIndex(text, -1) {
+		add(m, text[m[4]:m[5]], text[m[2]:m[3]], text[m[6]:m[7]])
+	}
+	slices.SortStableFunc(dates, func(a, b found) int { return a.at - b.at })
+	var out []string
+	for _, d := range dates {
+		out = append(out, d.date)
+	}
+	return uniq(out)
+}
+
+// blank replaces s with as many spaces, keeping word boundaries around it.
+func blank(s string) string {
+	return strings.Repeat(" ", len(s))
+}
+
+// uniq drops repeats from xs, keeping the first of each.
+func uniq(xs []string) []string {
+	var out []string
+	for _, x := range xs {
+		if !slices.Contains(out, x) {
+			out = append(out, x)
+		}
+	}
+	return out
+}
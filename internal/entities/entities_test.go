@@ -0,0 +1,69 @@
+package entities
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	s := Extract(`OpenSSL 3.5.1 fixes GHSA-4m4x-9f2h-vq3c and CVE-2026-1234 in versions >= 3.0.0, < 3.5.1 and 1.1.x.
+Microsoft says Exchangeable documents are unaffected; scanning came from 203.0.113.7:443 and 198.51.100.0/24.
+Published 2026-03-02, patch due March 9, 2026 (or 16 Mar 2026). Windows 2.0.1 - 2.0.4 also; see CVE-2026-1234.`,
+		map[string][]string{
+			"OpenSSL Project": {"OpenSSL"},
+			"Microsoft":       {"Exchange", "Windows"},
+			"Kubernetes":      {"kubectl"},
+		})
+
+	for _, tc := range []struct {
+		name      string
+		got, want []string
+	}{
+		{"products", s.Products, []string{"OpenSSL", "Windows"}},
+		{"vendors", s.Vendors, []string{"Microsoft", "OpenSSL Project"}},
+		{"ghsas", s.GHSAs, []string{"GHSA-4m4x-9f2h-vq3c"}},
+		{"cves", s.CVEs, []string{"CVE-2026-1234"}},
+		{"ips", s.IPs, []string{"203.0.113.7", "198.51.100.0/24"}},
+		{"versions", s.Versions, []string{">= 3.0.0, < 3.5.1", "1.1.x", "2.0.1 - 2.0.4", "3.5.1"}},
+		{"dates", s.Dates, []string{"2026-03-02", "2026-03-09", "2026-03-16"}},
+	} {
+		if !slices.Equal(tc.got, tc.want) {
+			t.Errorf("%s = %q, want %q", tc.name, tc.got, tc.want)
+		}
+	}
+}
+
+func TestExtract_None(t *testing.T) {
+	s := Extract("std::vector at 10:30:00 on 2026-02-30, v1.2 notes", nil)
+	for _, kind := range Kinds {
+		if s.Has(kind) {
+			t.Errorf("has %s: entities = %+v, want none", kind, s)
+		}
+	}
+}
+
+func TestSet_Has(t *testing.T) {
+	s := Set{CVEs: []string{"CVE-2026-1234"}, Products: []string{"OpenSSL"}}
+	for kind, want := range map[string]bool{KindCVE: true, KindProduct: true, KindGHSA: false, KindVendor: false, "nonsense": false} {
+		if got := s.Has(kind); got != want {
+			t.Errorf("Has(%q) = %v, want %v", kind, got, want)
+		}
+	}
+}
+
+func TestContainsWord(t *testing.T) {
+	for _, tc := range []struct {
+		text, word string
+		want       bool
+	}{
+		{"patch exchange now", "exchange", true},
+		{"exchangeable", "exchange", false},
+		{"the exchangeable exchange", "exchange", true},
+		{"k8s-kubectl.", "kubectl", true},
+		{"anything", "", false},
+	} {
+		if got := containsWord(tc.text, tc.word); got != tc.want {
+			t.Errorf("containsWord(%q, %q) = %v, want %v", tc.text, tc.word, got, tc.want)
+		}
+	}
+}
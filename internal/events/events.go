@@ -0,0 +1,127 @@
+// Package events is an in-process event bus. The pipeline publishes what
+// happens — a post ingested, a post scored read_now, a source failing, a
+// digest generated — and logging, tracing, the web UI and later
+// integrations subscribe, instead of each being called from every place it
+// happens.
+package events
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Kind names what happened.
+type Kind string
+
+const (
+	PostIngested    Kind = "post.ingested"    // a fetched post was stored
+	PostReadNow     Kind = "post.read_now"    // a post was scored read_now
+	SourceFailed    Kind = "source.failed"    // a source's fetch returned an error
+	DigestGenerated Kind = "digest.generated" // a digest was rendered
+)
+
+// Event is one occurrence. Which fields are set depends on Kind.
+type Event struct {
+	Kind Kind
+	At   time.Time // set by Publish when zero
+
+	// Post events; Source is also the failed source.
+	PostID   int64
+	Source   string
+	Channel  string
+	URL      string
+	PostedAt time.Time
+	Snippet  string
+	Score    int
+	Tier     string
+	Labels   []string
+
+	Err error // SourceFailed
+
+	// DigestGenerated
+	Posts int // posts the digest covered
+	Items int // items it listed above ignore
+}
+
+// Handler receives events. It runs on the publisher's goroutine, so it
+// must be quick and must not publish the event it handles.
+type Handler func(ctx context.Context, ev Event)
+
+type subscription struct {
+	id      int
+	kinds   []Kind
+	handler Handler
+}
+
+// Bus fans events out to subscribers, synchronously and in the order they
+// subscribed. A nil *Bus drops everything, so publishers need no checks.
+type Bus struct {
+	mu     sync.RWMutex
+	subs   []subscription
+	nextID int
+}
+
+// New creates a bus with no subscribers.
+func New() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers h for events of the given kinds, or of every kind
+// when none are given. Call the returned function to unsubscribe.
+func (b *Bus) Subscribe(h Handler, kinds ...Kind) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nextID++
+	id := b.nextID
+	b.subs = append(b.subs, subscription{id: id, kinds: kinds, handler: h})
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			b.subs = slices.DeleteFunc(b.subs, func(s subscription) bool { return s.id == id })
+		})
+	}
+}
+
+// Publish passes ev to the subscribers of its kind.
+func (b *Bus) Publish(ctx context.Context, ev Event) {
+	if b == nil {
+		return
+	}
+	if ev.At.IsZero() {
+		ev.At = time.Now()
+	}
+	b.mu.RLock()
+	subs := slices.Clone(b.subs)
+	b.mu.RUnlock()
+	for _, s := range subs {
+		if len(s.kinds) == 0 || slices.Contains(s.kinds, ev.Kind) {
+			s.handler(ctx, ev)
+		}
+	}
+}
+
+type busKey struct{}
+
+// WithBus returns a copy of ctx that carries b.
+func WithBus(ctx context.Context, b *Bus) context.Context {
+	return context.WithValue(ctx, busKey{}, b)
+}
+
+// FromContext returns the bus ctx carries, or nil.
+func FromContext(ctx context.Context) *Bus {
+	if ctx == nil {
+		return nil
+	}
+	b, _ := ctx.Value(busKey{}).(*Bus)
+	return b
+}
+
+// Publish publishes ev on the bus ctx carries; without one it does nothing.
+func Publish(ctx context.Context, ev Event) {
+	FromContext(ctx).Publish(ctx, ev)
+}
@@ -0,0 +1,46 @@
+package events
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestBus(t *testing.T) {
+	bus := New()
+	var all, failures []Kind
+	bus.Subscribe(func(_ context.Context, ev Event) { all = append(all, ev.Kind) })
+	unsubscribe := bus.Subscribe(func(_ context.Context, ev Event) {
+		if ev.At.IsZero() {
+			t.Error("event published without a time")
+		}
+		failures = append(failures, ev.Kind)
+	}, SourceFailed)
+
+	ctx := WithBus(context.Background(), bus)
+	if FromContext(ctx) != bus {
+		t.Fatal("FromContext did not return the bus")
+	}
+	Publish(ctx, Event{Kind: PostIngested})
+	Publish(ctx, Event{Kind: SourceFailed})
+	unsubscribe()
+	unsubscribe()
+	Publish(ctx, Event{Kind: SourceFailed})
+
+	if want := []Kind{PostIngested, SourceFailed, SourceFailed}; !slices.Equal(all, want) {
+		t.Errorf("all = %v, want %v", all, want)
+	}
+	if want := []Kind{SourceFailed}; !slices.Equal(failures, want) {
+		t.Errorf("failures = %v, want %v", failures, want)
+	}
+}
+
+func TestPublish_NoBus(t *testing.T) {
+	// Publishing without a bus is a no-op, not a panic.
+	Publish(context.Background(), Event{Kind: PostIngested})
+	var bus *Bus
+	bus.Publish(context.Background(), Event{Kind: PostIngested})
+	if FromContext(context.Background()) != nil {
+		t.Error("FromContext returned a bus for a bare context")
+	}
+}
@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSetup_JSON(t *testing.T) {
+	old := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(old) })
+
+	var buf bytes.Buffer
+	if err := Setup(&buf, "info", "json"); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	slog.Debug("hidden")
+	slog.Warn("feed failed", "source", "rss")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want 1 (debug filtered):\n%s", len(lines), buf.String())
+	}
+	var rec map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatalf("parse json log: %v", err)
+	}
+	if rec["msg"] != "feed failed" || rec["source"] != "rss" || rec["level"] != "WARN" {
+		t.Errorf("record = %v", rec)
+	}
+}
+
+func TestSetup_TextDebug(t *testing.T) {
+	old := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(old) })
+
+	var buf bytes.Buffer
+	if err := Setup(&buf, "debug", "text"); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	slog.Debug("details", "n", 3)
+	if !strings.Contains(buf.String(), "level=DEBUG") || !strings.Contains(buf.String(), "n=3") {
+		t.Errorf("output = %q", buf.String())
+	}
+}
+
+func TestSetup_Invalid(t *testing.T) {
+	if err := Setup(&bytes.Buffer{}, "loud", "text"); err == nil {
+		t.Error("expected error for unknown level")
+	}
+	if err := Setup(&bytes.Buffer{}, "info", "xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]slog.Level{
+		"debug":   slog.LevelDebug,
+		"":        slog.LevelInfo,
+		"INFO":    slog.LevelInfo,
+		"warning": slog.LevelWarn,
+		"error":   slog.LevelError,
+	}
+	for in, want := range tests {
+		got, err := ParseLevel(in)
+		if err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+// Package mcp serves tools over the Model Context Protocol: JSON-RPC 2.0
+// messages, one per line, on a stream such as stdio.
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Protocol versions this server speaks, newest last. A client asking for
+// another gets the newest.
+var protocolVersions = []string{"2024-11-05", "2025-03-26", "2025-06-18"}
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// maxMessage bounds one incoming line.
+const maxMessage = 4 << 20
+
+// Tool is a callable tool. Handler gets the call's arguments and returns a
+// value sent back as indented JSON text; an error is reported to the model as
+// a failed call rather than a protocol error.
+type Tool struct {
+	Name        string
+	Description string
+	InputSchema map[string]any // JSON Schema of the arguments object
+	Handler     func(ctx context.Context, args json.RawMessage) (any, error)
+}
+
+// Server answers MCP requests with a fixed set of tools.
+type Server struct {
+	name    string
+	version string
+	tools   []Tool
+}
+
+// New creates a server identifying itself as name and version.
+func New(name, version string, tools ...Tool) *Server {
+	return &Server{name: name, version: version, tools: tools}
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type toolResult struct {
+	Content []content `json:"content"`
+	IsError bool      `json:"isError,omitempty"`
+}
+
+// Serve reads requests from r and writes responses to w until r ends or ctx
+// is cancelled. Requests are handled one at a time, in order.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64<<10), maxMessage)
+	for sc.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line := sc.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if resp := s.handle(ctx, line); resp != nil {
+			if err := s.write(w, resp); err != nil {
+				return err
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("read request: %w", err)
+	}
+	return nil
+}
+
+func (s *Server) write(w io.Writer, resp *response) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("marshal response: %w", err)
+	}
+	if _, err := w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write response: %w", err)
+	}
+	return nil
+}
+
+// handle answers one message, or returns nil for a notification.
+func (s *Server) handle(ctx context.Context, line []byte) *response {
+	var req request
+	if err := json.Unmarshal(line, &req); err != nil {
+		return errorResponse(json.RawMessage("null"), codeParseError, "parse error: "+err.Error())
+	}
+	if len(req.ID) == 0 {
+		return nil // notifications/initialized, notifications/cancelled, ...
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return errorResponse(req.ID, codeInvalidRequest, "invalid request")
+	}
+
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(req.Params, &params)
+		version := protocolVersions[len(protocolVersions)-1]
+		if slices.Contains(protocolVersions, params.ProtocolVersion) {
+			version = params.ProtocolVersion
+		}
+		return result(req.ID, map[string]any{
+			"protocolVersion": version,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]string{"name": s.name, "version": s.version},
+		})
+	case "ping":
+		return result(req.ID, map[string]any{})
+	case "tools/list":
+		tools := make([]map[string]any, 0, len(s.tools))
+		for _, t := range s.tools {
+			tools = append(tools, map[string]any{
+				"name":        t.Name,
+				"description": t.Description,
+				"inputSchema": t.InputSchema,
+			})
+		}
+		return result(req.ID, map[string]any{"tools": tools})
+	case "tools/call":
+		return s.call(ctx, req)
+	default:
+		return errorResponse(req.ID, codeMethodNotFound, "method not found: "+req.Method)
+	}
+}
+
+func (s *Server) call(ctx context.Context, req request) *response {
+	var params struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return errorResponse(req.ID, codeInvalidParams, "invalid params: "+err.Error())
+	}
+	i := slices.IndexFunc(s.tools, func(t Tool) bool { return t.Name == params.Name })
+	if i < 0 {
+		return errorResponse(req.ID, codeInvalidParams, "unknown tool: "+params.Name)
+	}
+	if len(params.Arguments) == 0 || string(params.Arguments) == "null" {
+		params.Arguments = json.RawMessage("{}")
+	}
+
+	out, err := s.tools[i].Handler(ctx, params.Arguments)
+	if err != nil {
+		return result(req.ID, toolResult{Content: []content{{Type: "text", Text: err.Error()}}, IsError: true})
+	}
+	text, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return result(req.ID, toolResult{Content: []content{{Type: "text", Text: "marshal result: " + err.Error()}}, IsError: true})
+	}
+	return result(req.ID, toolResult{Content: []content{{Type: "text", Text: string(text)}}})
+}
+
+func result(id json.RawMessage, v any) *response {
+	return &response{JSONRPC: "2.0", ID: id, Result: v}
+}
+
+func errorResponse(id json.RawMessage, code int, msg string) *response {
+	return &response{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: msg}}
+}
+
+// DecodeArgs unmarshals tool arguments into v, rejecting unknown fields so a
+// misspelled argument is reported instead of ignored.
+func DecodeArgs(args json.RawMessage, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(args))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
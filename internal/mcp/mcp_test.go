@@ -0,0 +1,125 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testServer() *Server {
+	return New("noisepan", "test", Tool{
+		Name:        "echo",
+		Description: "echoes its text",
+		InputSchema: map[string]any{"type": "object"},
+		Handler: func(_ context.Context, raw json.RawMessage) (any, error) {
+			var args struct {
+				Text string `json:"text"`
+			}
+			if err := DecodeArgs(raw, &args); err != nil {
+				return nil, err
+			}
+			if args.Text == "" {
+				return nil, errors.New("text is required")
+			}
+			return map[string]string{"text": args.Text}, nil
+		},
+	})
+}
+
+// exchange sends one message per line and returns the decoded responses.
+func exchange(t *testing.T, lines ...string) []map[string]any {
+	t.Helper()
+	var out bytes.Buffer
+	if err := testServer().Serve(context.Background(), strings.NewReader(strings.Join(lines, "\n")+"\n"), &out); err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+	var resps []map[string]any
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var r map[string]any
+		if err := dec.Decode(&r); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		resps = append(resps, r)
+	}
+	return resps
+}
+
+func TestServe_Handshake(t *testing.T) {
+	resps := exchange(t,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"c","version":"1"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"ping"}`,
+	)
+	if len(resps) != 3 {
+		t.Fatalf("got %d responses, want 3 (none for the notification): %v", len(resps), resps)
+	}
+
+	init := resps[0]["result"].(map[string]any)
+	if init["protocolVersion"] != "2025-03-26" {
+		t.Errorf("protocolVersion = %v, want the client's", init["protocolVersion"])
+	}
+	if info := init["serverInfo"].(map[string]any); info["name"] != "noisepan" || info["version"] != "test" {
+		t.Errorf("serverInfo = %v", info)
+	}
+
+	tools := resps[1]["result"].(map[string]any)["tools"].([]any)
+	if len(tools) != 1 || tools[0].(map[string]any)["name"] != "echo" || tools[0].(map[string]any)["inputSchema"] == nil {
+		t.Errorf("tools = %v", tools)
+	}
+	if resps[2]["id"] != float64(3) || resps[2]["result"] == nil {
+		t.Errorf("ping = %v", resps[2])
+	}
+}
+
+func TestServe_UnknownProtocolVersion(t *testing.T) {
+	resps := exchange(t, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"1999-01-01"}}`)
+	got := resps[0]["result"].(map[string]any)["protocolVersion"]
+	if got != protocolVersions[len(protocolVersions)-1] {
+		t.Errorf("protocolVersion = %v, want the newest supported", got)
+	}
+}
+
+func TestServe_ToolCall(t *testing.T) {
+	resps := exchange(t,
+		`{"jsonrpc":"2.0","id":"a","method":"tools/call","params":{"name":"echo","arguments":{"text":"hi"}}}`,
+		`{"jsonrpc":"2.0","id":"b","method":"tools/call","params":{"name":"echo","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":"c","method":"tools/call","params":{"name":"echo","arguments":{"txt":"typo"}}}`,
+	)
+
+	ok := resps[0]["result"].(map[string]any)
+	text := ok["content"].([]any)[0].(map[string]any)["text"].(string)
+	if ok["isError"] != nil || !strings.Contains(text, `"text": "hi"`) {
+		t.Errorf("call result = %v", ok)
+	}
+
+	for i, want := range []string{"text is required", "unknown field"} {
+		res := resps[i+1]["result"].(map[string]any)
+		msg := res["content"].([]any)[0].(map[string]any)["text"].(string)
+		if res["isError"] != true || !strings.Contains(msg, want) {
+			t.Errorf("response %d = %v, want a tool error containing %q", i+1, res, want)
+		}
+	}
+}
+
+func TestServe_ProtocolErrors(t *testing.T) {
+	resps := exchange(t,
+		`not json`,
+		`{"jsonrpc":"2.0","id":1,"method":"resources/list"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"nope"}}`,
+		`{"jsonrpc":"1.0","id":3,"method":"ping"}`,
+	)
+	for i, want := range []float64{codeParseError, codeMethodNotFound, codeInvalidParams, codeInvalidRequest} {
+		e, ok := resps[i]["error"].(map[string]any)
+		if !ok || e["code"] != want {
+			t.Errorf("response %d = %v, want error code %v", i, resps[i], want)
+		}
+	}
+	if resps[0]["id"] != nil {
+		t.Errorf("parse error id = %v, want null", resps[0]["id"])
+	}
+}
@@ -0,0 +1,195 @@
+// Package network builds HTTP transports from the network section of config.yaml.
+package network
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/ppiankov/noisepan/internal/config"
+)
+
+// NewTransport returns an http.Transport configured with the proxy, CA bundle,
+// and per-host TLS verification settings from cfg. With a zero config it
+// behaves like http.DefaultTransport (proxy taken from the environment).
+func NewTransport(cfg config.NetworkConfig) (*http.Transport, error) {
+	base, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, errors.New("network: default transport is not *http.Transport")
+	}
+	t := base.Clone()
+
+	proxy, err := proxyFunc(cfg)
+	if err != nil {
+		return nil, err
+	}
+	t.Proxy = proxy
+
+	tlsCfg, verifier, err := tlsConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		t.TLSClientConfig = tlsCfg
+	}
+	if verifier != nil {
+		t.DialTLSContext = dialTLS(t, verifier)
+	}
+
+	return t, nil
+}
+
+// dialTLS returns a DialTLSContext for t that verifies each connection
+// against the host it dialed, which for IP literals the TLS state does not
+// carry. It reads t's dialer and TLS config on every call.
+func dialTLS(t *http.Transport, v *hostVerifier) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		dial := t.DialContext
+		if dial == nil {
+			dial = (&net.Dialer{}).DialContext
+		}
+		raw, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		cfg := t.TLSClientConfig.Clone()
+		if cfg.ServerName == "" {
+			cfg.ServerName = host
+		}
+		cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+			return v.verify(host, cs)
+		}
+		conn := tls.Client(raw, cfg)
+		if err := conn.HandshakeContext(ctx); err != nil {
+			_ = raw.Close()
+			return nil, err
+		}
+		return conn, nil
+	}
+}
+
+// proxyFunc picks socks5_proxy over http_proxy, falling back to the
+// HTTP_PROXY/HTTPS_PROXY/NO_PROXY environment variables.
+func proxyFunc(cfg config.NetworkConfig) (func(*http.Request) (*url.URL, error), error) {
+	raw := cfg.SOCKS5Proxy
+	if raw == "" {
+		raw = cfg.HTTPProxy
+	}
+	if raw == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("network: parse proxy %q: %w", raw, err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("network: proxy %q has no host", raw)
+	}
+
+	noProxy := make(map[string]bool, len(cfg.NoProxy))
+	for _, h := range cfg.NoProxy {
+		noProxy[strings.ToLower(h)] = true
+	}
+
+	return func(req *http.Request) (*url.URL, error) {
+		if noProxy[strings.ToLower(req.URL.Hostname())] {
+			return nil, nil
+		}
+		return u, nil
+	}, nil
+}
+
+// tlsConfig returns nil when no TLS customisation is configured so the
+// transport keeps Go's defaults. With a skip list it also returns the
+// verifier that checks the hosts not on it.
+func tlsConfig(cfg config.NetworkConfig) (*tls.Config, *hostVerifier, error) {
+	if cfg.CABundle == "" && len(cfg.InsecureSkipVerify) == 0 {
+		return nil, nil, nil
+	}
+
+	roots, err := x509.SystemCertPool()
+	if err != nil || roots == nil {
+		roots = x509.NewCertPool()
+	}
+	if cfg.CABundle != "" {
+		pem, err := os.ReadFile(cfg.CABundle)
+		if err != nil {
+			return nil, nil, fmt.Errorf("network: read ca_bundle: %w", err)
+		}
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, nil, fmt.Errorf("network: ca_bundle %s contains no certificates", cfg.CABundle)
+		}
+	}
+
+	tc := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    roots,
+	}
+	if len(cfg.InsecureSkipVerify) == 0 {
+		return tc, nil, nil
+	}
+
+	v := &hostVerifier{skip: make(map[string]bool, len(cfg.InsecureSkipVerify)), roots: roots}
+	for _, h := range cfg.InsecureSkipVerify {
+		v.skip[strings.ToLower(h)] = true
+	}
+
+	// Go only supports skipping verification globally, so disable the
+	// built-in check and re-verify every host that is not on the skip list.
+	// Direct connections are verified against the dialed host by dialTLS;
+	// this covers connections through a proxy, where the SNI name is all
+	// there is, so IP literals fail closed there.
+	tc.InsecureSkipVerify = true //nolint:gosec // verification is done in VerifyConnection
+	tc.VerifyConnection = func(cs tls.ConnectionState) error {
+		if cs.ServerName == "" {
+			return errors.New("network: cannot verify a server without a host name")
+		}
+		return v.verify(cs.ServerName, cs)
+	}
+	return tc, v, nil
+}
+
+// hostVerifier verifies server certificates for every host not on the
+// insecure_skip_verify list.
+type hostVerifier struct {
+	skip  map[string]bool
+	roots *x509.CertPool
+}
+
+// verify checks that cs chains to the roots and covers host, a name or IP
+// literal, unless host is on the skip list.
+func (v *hostVerifier) verify(host string, cs tls.ConnectionState) error {
+	host = strings.ToLower(host)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	if v.skip[host] {
+		return nil
+	}
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("network: server presented no certificates")
+	}
+	intermediates := x509.NewCertPool()
+	for _, c := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(c)
+	}
+	_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+		DNSName:       host,
+		Roots:         v.roots,
+		Intermediates: intermediates,
+	})
+	return err
+}
@@ -0,0 +1,203 @@
+package network
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+)
+
+func TestNewTransport_Default(t *testing.T) {
+	tr, err := NewTransport(config.NetworkConfig{})
+	if err != nil {
+		t.Fatalf("new transport: %v", err)
+	}
+	if tr.TLSClientConfig != nil && tr.TLSClientConfig.InsecureSkipVerify {
+		t.Error("default transport must not skip verification")
+	}
+}
+
+func TestNewTransport_ProxyPrecedence(t *testing.T) {
+	tr, err := NewTransport(config.NetworkConfig{
+		HTTPProxy:   "http://proxy.corp:3128",
+		SOCKS5Proxy: "socks5://127.0.0.1:1080",
+		NoProxy:     []string{"internal.example.com"},
+	})
+	if err != nil {
+		t.Fatalf("new transport: %v", err)
+	}
+
+	req := &http.Request{URL: &url.URL{Scheme: "https", Host: "example.com"}}
+	got, err := tr.Proxy(req)
+	if err != nil {
+		t.Fatalf("proxy: %v", err)
+	}
+	if got == nil || got.String() != "socks5://127.0.0.1:1080" {
+		t.Errorf("proxy = %v, want socks5://127.0.0.1:1080", got)
+	}
+
+	req = &http.Request{URL: &url.URL{Scheme: "https", Host: "internal.example.com"}}
+	got, err = tr.Proxy(req)
+	if err != nil {
+		t.Fatalf("proxy: %v", err)
+	}
+	if got != nil {
+		t.Errorf("no_proxy host got proxy %v, want nil", got)
+	}
+}
+
+func TestNewTransport_InvalidProxy(t *testing.T) {
+	if _, err := NewTransport(config.NetworkConfig{HTTPProxy: "proxy-without-scheme"}); err == nil {
+		t.Fatal("expected error for proxy without host")
+	}
+}
+
+func TestNewTransport_MissingCABundle(t *testing.T) {
+	_, err := NewTransport(config.NetworkConfig{CABundle: filepath.Join(t.TempDir(), "missing.pem")})
+	if err == nil {
+		t.Fatal("expected error for missing ca_bundle")
+	}
+}
+
+func TestNewTransport_EmptyCABundle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(path, []byte("not a cert"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewTransport(config.NetworkConfig{CABundle: path}); err == nil {
+		t.Fatal("expected error for ca_bundle without certificates")
+	}
+}
+
+func TestNewTransport_CABundleTrustsServer(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	block := &pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tr, err := NewTransport(config.NetworkConfig{CABundle: path})
+	if err != nil {
+		t.Fatalf("new transport: %v", err)
+	}
+	tr.Proxy = nil
+
+	resp, err := (&http.Client{Transport: tr}).Get(ts.URL)
+	if err != nil {
+		t.Fatalf("get with ca_bundle: %v", err)
+	}
+	_ = resp.Body.Close()
+}
+
+func TestNewTransport_InsecureSkipVerifyPerHost(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer ts.Close()
+
+	// httptest certificates cover example.com but are not trusted, so
+	// route example.com to the test server and toggle the skip list.
+	dialTest := func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, ts.Listener.Addr().String())
+	}
+
+	tr, err := NewTransport(config.NetworkConfig{InsecureSkipVerify: []string{"other.example.com"}})
+	if err != nil {
+		t.Fatalf("new transport: %v", err)
+	}
+	tr.Proxy = nil
+	tr.DialContext = dialTest
+	if _, err := (&http.Client{Transport: tr}).Get("https://example.com/"); err == nil {
+		t.Fatal("expected verification failure for host not in skip list")
+	}
+
+	tr, err = NewTransport(config.NetworkConfig{InsecureSkipVerify: []string{"Example.com"}})
+	if err != nil {
+		t.Fatalf("new transport: %v", err)
+	}
+	tr.Proxy = nil
+	tr.DialContext = dialTest
+	resp, err := (&http.Client{Transport: tr}).Get("https://example.com/")
+	if err != nil {
+		t.Fatalf("get with skip list: %v", err)
+	}
+	_ = resp.Body.Close()
+}
+
+func TestNewTransport_VerifiesIPLiteralHost(t *testing.T) {
+	// A trusted certificate for another name must not pass for an IP
+	// literal URL, which sends no SNI name to check against.
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "other.example.com"},
+		DNSNames:              []string{"other.example.com"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	ts.TLS = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+	ts.StartTLS()
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tr, err := NewTransport(config.NetworkConfig{CABundle: path, InsecureSkipVerify: []string{"legacy.intranet"}})
+	if err != nil {
+		t.Fatalf("new transport: %v", err)
+	}
+	tr.Proxy = nil
+	if _, err := (&http.Client{Transport: tr}).Get(ts.URL); err == nil {
+		t.Fatal("expected verification failure for a certificate of another name")
+	}
+
+	host, _, _ := net.SplitHostPort(ts.Listener.Addr().String())
+	tr, err = NewTransport(config.NetworkConfig{CABundle: path, InsecureSkipVerify: []string{host}})
+	if err != nil {
+		t.Fatalf("new transport: %v", err)
+	}
+	tr.Proxy = nil
+	resp, err := (&http.Client{Transport: tr}).Get(ts.URL)
+	if err != nil {
+		t.Fatalf("get with the IP on the skip list: %v", err)
+	}
+	_ = resp.Body.Close()
+}
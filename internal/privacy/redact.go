@@ -7,6 +7,15 @@ import (
 
 const redactedPlaceholder = "[REDACTED]"
 
+// PIIPatterns match personal data that is always stripped from exports that
+// leave the machine: email addresses, international phone numbers, and IPv4
+// addresses.
+var PIIPatterns = []string{
+	`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`,
+	`\+\d{1,3}[\s.-]?\(?\d{2,4}\)?[\s.-]?\d{3,4}[\s.-]?\d{3,4}`,
+	`\b(?:\d{1,3}\.){3}\d{1,3}\b`,
+}
+
 // Compile compiles a list of regex pattern strings into compiled regexps.
 // Returns an error if any pattern is invalid.
 func Compile(patterns []string) ([]*regexp.Regexp, error) {
@@ -74,3 +74,15 @@ func TestApply_EmptyPatterns(t *testing.T) {
 		t.Errorf("got %q, want unchanged", result)
 	}
 }
+
+func TestPIIPatterns(t *testing.T) {
+	patterns, err := Compile(PIIPatterns)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	got := Apply("mail ops@example.com or call +1 415 555 0100 from 10.0.0.12 about CVE-2026-12345", patterns)
+	want := "mail [REDACTED] or call [REDACTED] from [REDACTED] about CVE-2026-12345"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
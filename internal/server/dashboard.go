@@ -0,0 +1,369 @@
+package server
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults and caps for query parameters.
+const (
+	defaultDigestSince   = 24 * time.Hour
+	defaultChannelsSince = 30 * 24 * time.Hour
+	defaultPostsSince    = 7 * 24 * time.Hour
+	defaultSearchLimit   = 50
+	maxSearchLimit       = 500
+	defaultPostsLimit    = 100
+	maxPostsLimit        = 1000
+)
+
+//go:embed web
+var webFS embed.FS
+
+// ErrNotFound is returned by a Backend when a post does not exist.
+var ErrNotFound = errors.New("not found")
+
+var errUnauthorized = errors.New("missing or invalid API token")
+
+// Digest is the dashboard's digest view: read_now and skim posts, highest
+// score first, and how many were ignored. With custom tiers, Skim holds the
+// posts of every tier between read_now and ignore.
+type Digest struct {
+	Since   string `json:"since"`
+	ReadNow []Item `json:"read_now"`
+	Skim    []Item `json:"skim"`
+	Ignored int    `json:"ignored"`
+}
+
+// ChannelStat is the tier split of one channel's posts, with the channel's
+// configured note and owner.
+type ChannelStat struct {
+	Source  string `json:"source"`
+	Channel string `json:"channel"`
+	Total   int    `json:"total"`
+	ReadNow int    `json:"read_now"`
+	Skim    int    `json:"skim"`
+	Ignored int    `json:"ignored"`
+	Owner   string `json:"owner,omitempty"`
+	Note    string `json:"note,omitempty"`
+}
+
+// Stats is the corpus summary served by /api/stats.
+type Stats struct {
+	Since    string        `json:"since"`
+	Posts    int           `json:"posts"`
+	ReadNow  int           `json:"read_now"`
+	Skim     int           `json:"skim"`
+	Ignored  int           `json:"ignored"`
+	Starred  int           `json:"starred"`
+	Up       int           `json:"feedback_up"`
+	Down     int           `json:"feedback_down"`
+	Channels []ChannelStat `json:"channels"`
+}
+
+// PostQuery selects posts for /api/posts, newest first.
+type PostQuery struct {
+	Since      time.Duration
+	Tier       string
+	Source     string
+	Channel    string
+	UnreadOnly bool
+	Limit      int
+	Offset     int
+}
+
+// Contribution is one line of a post's scoring breakdown.
+type Contribution struct {
+	Reason string `json:"reason"`
+	Points int    `json:"points"`
+}
+
+// PostDetail is a post with its full text and scoring breakdown.
+type PostDetail struct {
+	Item
+	Text        string         `json:"text"`
+	AlsoIn      []string       `json:"also_in,omitempty"`
+	Changed     bool           `json:"changed,omitempty"` // edited after it was scored
+	Explanation []Contribution `json:"explanation"`
+}
+
+// Backend answers the JSON API behind the dashboard.
+type Backend interface {
+	Posts(ctx context.Context, q PostQuery) ([]Item, error)
+	Digest(ctx context.Context, since time.Duration) (Digest, error)
+	// Feed renders the digest as an Atom feed served from self.
+	Feed(ctx context.Context, since time.Duration, self string) ([]byte, error)
+	Stats(ctx context.Context, since time.Duration) (Stats, error)
+	Search(ctx context.Context, query string, limit int) ([]Item, error)
+	Channels(ctx context.Context, since time.Duration) ([]ChannelStat, error)
+	Post(ctx context.Context, id int64) (PostDetail, error)
+	Feedback(ctx context.Context, id int64, up bool) error
+	SetStarred(ctx context.Context, id int64, starred bool) error
+}
+
+// handleDashboard registers the web UI and its JSON endpoints.
+func (s *Server) handleDashboard() {
+	web, _ := fs.Sub(webFS, "web")
+	s.mux.Handle("GET /", http.FileServerFS(web))
+	s.mux.HandleFunc("GET /api/posts", s.handlePosts)
+	s.mux.HandleFunc("GET /api/digest", s.handleDigest)
+	s.mux.HandleFunc("GET /api/feed", s.handleFeed)
+	s.mux.HandleFunc("GET /api/stats", s.handleStats)
+	s.mux.HandleFunc("GET /api/search", s.handleSearch)
+	s.mux.HandleFunc("GET /api/channels", s.handleChannels)
+	s.mux.HandleFunc("GET /api/posts/{id}", s.handlePost)
+	s.mux.HandleFunc("POST /api/posts/{id}/feedback", s.handleFeedback)
+	s.mux.HandleFunc("PUT /api/posts/{id}/star", s.handleStar)
+	s.mux.HandleFunc("DELETE /api/posts/{id}/star", s.handleStar)
+}
+
+func (s *Server) handlePosts(w http.ResponseWriter, r *http.Request) {
+	since, err := sinceParam(r, defaultPostsSince)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	v := r.URL.Query()
+	q := PostQuery{
+		Since:      since,
+		Tier:       v.Get("tier"),
+		Source:     v.Get("source"),
+		Channel:    v.Get("channel"),
+		UnreadOnly: v.Get("unread") == "true",
+	}
+	if q.Tier != "" && !slices.Contains(s.tiers, q.Tier) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid tier %q (want %s)", q.Tier, strings.Join(s.tiers, ", ")))
+		return
+	}
+	if q.Limit, err = intParam(r, "limit", defaultPostsLimit, 1); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	q.Limit = min(q.Limit, maxPostsLimit)
+	if q.Offset, err = intParam(r, "offset", 0, 0); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	items, err := s.backend.Posts(r.Context(), q)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"since": since.String(), "posts": nonNil(items)})
+}
+
+func (s *Server) handleDigest(w http.ResponseWriter, r *http.Request) {
+	since, err := sinceParam(r, defaultDigestSince)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	d, err := s.backend.Digest(r.Context(), since)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	d.ReadNow, d.Skim = nonNil(d.ReadNow), nonNil(d.Skim)
+	writeJSON(w, http.StatusOK, d)
+}
+
+// handleFeed serves the digest as Atom. Feed readers that cannot send a
+// header pass the API token as ?token=, which is kept out of the self link.
+func (s *Server) handleFeed(w http.ResponseWriter, r *http.Request) {
+	since, err := sinceParam(r, defaultDigestSince)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	feed, err := s.backend.Feed(r.Context(), since, scheme+"://"+r.Host+r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	_, _ = w.Write(feed)
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	since, err := sinceParam(r, defaultChannelsSince)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	stats, err := s.backend.Stats(r.Context(), since)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	stats.Channels = nonNil(stats.Channels)
+	writeJSON(w, http.StatusOK, stats)
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		writeError(w, http.StatusBadRequest, errors.New("q is required"))
+		return
+	}
+	limit, err := intParam(r, "limit", defaultSearchLimit, 1)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	items, err := s.backend.Search(r.Context(), q, min(limit, maxSearchLimit))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"query": q, "results": nonNil(items)})
+}
+
+func (s *Server) handleChannels(w http.ResponseWriter, r *http.Request) {
+	since, err := sinceParam(r, defaultChannelsSince)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	stats, err := s.backend.Channels(r.Context(), since)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"since": since.String(), "channels": nonNil(stats)})
+}
+
+func (s *Server) handlePost(w http.ResponseWriter, r *http.Request) {
+	id, ok := postID(w, r)
+	if !ok {
+		return
+	}
+	p, err := s.backend.Post(r.Context(), id)
+	if err != nil {
+		writeBackendError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+// handleFeedback records {"vote": "up"} or {"vote": "down"}. It only takes
+// JSON so that a plain cross-site form cannot vote through a browser.
+func (s *Server) handleFeedback(w http.ResponseWriter, r *http.Request) {
+	id, ok := postID(w, r)
+	if !ok {
+		return
+	}
+	if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt != "application/json" {
+		writeError(w, http.StatusUnsupportedMediaType, errors.New("content type must be application/json"))
+		return
+	}
+	var body struct {
+		Vote string `json:"vote"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<10)).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("decode body: %w", err))
+		return
+	}
+	if body.Vote != "up" && body.Vote != "down" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("vote must be up or down, got %q", body.Vote))
+		return
+	}
+	if err := s.backend.Feedback(r.Context(), id, body.Vote == "up"); err != nil {
+		writeBackendError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"id": id, "vote": body.Vote})
+}
+
+func (s *Server) handleStar(w http.ResponseWriter, r *http.Request) {
+	id, ok := postID(w, r)
+	if !ok {
+		return
+	}
+	starred := r.Method == http.MethodPut
+	if err := s.backend.SetStarred(r.Context(), id, starred); err != nil {
+		writeBackendError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"id": id, "starred": starred})
+}
+
+func postID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id < 1 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid post id %q", r.PathValue("id")))
+		return 0, false
+	}
+	return id, true
+}
+
+// intParam parses the named query parameter, defaulting to def. Values below
+// minimum are rejected.
+func intParam(r *http.Request, name string, def, minimum int) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < minimum {
+		return 0, fmt.Errorf("invalid %s %q", name, v)
+	}
+	return n, nil
+}
+
+// sinceParam parses the since query parameter as a Go duration or a number
+// of days ("7d").
+func sinceParam(r *http.Request, def time.Duration) (time.Duration, error) {
+	v := r.URL.Query().Get("since")
+	if v == "" {
+		return def, nil
+	}
+	if days, ok := strings.CutSuffix(v, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid since %q", v)
+	}
+	return d, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeBackendError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeError(w, http.StatusInternalServerError, err)
+}
+
+// nonNil keeps empty lists encoding as [] rather than null.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
@@ -0,0 +1,285 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeBackend struct {
+	posts   PostQuery
+	since   time.Duration
+	query   string
+	limit   int
+	votes   map[int64]bool
+	starred map[int64]bool
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{votes: map[int64]bool{}, starred: map[int64]bool{}}
+}
+
+func (f *fakeBackend) Posts(_ context.Context, q PostQuery) ([]Item, error) {
+	f.posts = q
+	return []Item{{ID: 2, Tier: "skim"}}, nil
+}
+
+func (f *fakeBackend) Stats(_ context.Context, since time.Duration) (Stats, error) {
+	f.since = since
+	return Stats{Since: since.String(), Posts: 3, ReadNow: 1, Starred: 2, Up: 1}, nil
+}
+
+func (f *fakeBackend) Digest(_ context.Context, since time.Duration) (Digest, error) {
+	f.since = since
+	return Digest{Since: since.String(), ReadNow: []Item{{ID: 1, Tier: "read_now", Snippet: "KEV update"}}, Ignored: 4}, nil
+}
+
+func (f *fakeBackend) Feed(_ context.Context, since time.Duration, self string) ([]byte, error) {
+	f.since, f.query = since, self
+	return []byte("<feed/>"), nil
+}
+
+func (f *fakeBackend) Search(_ context.Context, query string, limit int) ([]Item, error) {
+	f.query, f.limit = query, limit
+	return nil, nil
+}
+
+func (f *fakeBackend) Channels(_ context.Context, since time.Duration) ([]ChannelStat, error) {
+	f.since = since
+	return []ChannelStat{{Source: "rss", Channel: "CISA", Total: 3, ReadNow: 1}}, nil
+}
+
+func (f *fakeBackend) Post(_ context.Context, id int64) (PostDetail, error) {
+	if id != 1 {
+		return PostDetail{}, ErrNotFound
+	}
+	return PostDetail{Item: Item{ID: 1}, Text: "full text", Explanation: []Contribution{{Reason: "keyword: cve", Points: 5}}}, nil
+}
+
+func (f *fakeBackend) Feedback(_ context.Context, id int64, up bool) error {
+	if id != 1 {
+		return ErrNotFound
+	}
+	f.votes[id] = up
+	return nil
+}
+
+func (f *fakeBackend) SetStarred(_ context.Context, id int64, starred bool) error {
+	if id != 1 {
+		return errors.New("db locked")
+	}
+	f.starred[id] = starred
+	return nil
+}
+
+func do(t *testing.T, srv *httptest.Server, method, path, contentType, body string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(data)
+}
+
+func TestDashboard_Index(t *testing.T) {
+	srv := httptest.NewServer(New(NewHub(), newFakeBackend()).Handler())
+	defer srv.Close()
+
+	status, body := do(t, srv, http.MethodGet, "/", "", "")
+	if status != http.StatusOK || !strings.Contains(body, "<title>noisepan</title>") {
+		t.Errorf("GET / = %d %.80q", status, body)
+	}
+
+	// Without a backend only the stream is served.
+	plain := httptest.NewServer(New(NewHub(), nil).Handler())
+	defer plain.Close()
+	if status, _ := do(t, plain, http.MethodGet, "/api/digest", "", ""); status != http.StatusNotFound {
+		t.Errorf("digest without backend = %d, want 404", status)
+	}
+}
+
+func TestDashboard_ReadEndpoints(t *testing.T) {
+	fb := newFakeBackend()
+	srv := httptest.NewServer(New(NewHub(), fb).Handler())
+	defer srv.Close()
+
+	status, body := do(t, srv, http.MethodGet, "/api/digest?since=7d", "", "")
+	if status != http.StatusOK || fb.since != 7*24*time.Hour {
+		t.Fatalf("digest = %d %s (since %v)", status, body, fb.since)
+	}
+	var d Digest
+	if err := json.Unmarshal([]byte(body), &d); err != nil {
+		t.Fatalf("decode digest: %v", err)
+	}
+	if len(d.ReadNow) != 1 || d.Ignored != 4 || !strings.Contains(body, `"skim":[]`) {
+		t.Errorf("digest body = %s", body)
+	}
+
+	if status, _ := do(t, srv, http.MethodGet, "/api/digest", "", ""); status != http.StatusOK || fb.since != defaultDigestSince {
+		t.Errorf("default since = %v", fb.since)
+	}
+	if status, body := do(t, srv, http.MethodGet, "/api/digest?since=soon", "", ""); status != http.StatusBadRequest || !strings.Contains(body, `"error":"invalid since \"soon\""`) {
+		t.Errorf("bad since = %d %s", status, body)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/api/feed?since=48h", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("feed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); resp.StatusCode != http.StatusOK || !strings.HasPrefix(ct, "application/atom+xml") {
+		t.Errorf("feed = %d, content type %q", resp.StatusCode, ct)
+	}
+	if fb.since != 48*time.Hour || fb.query != srv.URL+"/api/feed" {
+		t.Errorf("feed since %v, self %q", fb.since, fb.query)
+	}
+
+	status, body = do(t, srv, http.MethodGet, "/api/search?q=openssl&limit=5000", "", "")
+	if status != http.StatusOK || fb.query != "openssl" || fb.limit != maxSearchLimit || !strings.Contains(body, `"results":[]`) {
+		t.Errorf("search = %d %s (query %q, limit %d)", status, body, fb.query, fb.limit)
+	}
+	if status, _ := do(t, srv, http.MethodGet, "/api/search?q=+", "", ""); status != http.StatusBadRequest {
+		t.Errorf("empty query = %d, want 400", status)
+	}
+
+	status, body = do(t, srv, http.MethodGet, "/api/channels", "", "")
+	if status != http.StatusOK || fb.since != defaultChannelsSince || !strings.Contains(body, `"channel":"CISA"`) {
+		t.Errorf("channels = %d %s", status, body)
+	}
+
+	status, body = do(t, srv, http.MethodGet, "/api/posts/1", "", "")
+	if status != http.StatusOK || !strings.Contains(body, `"text":"full text"`) || !strings.Contains(body, `"reason":"keyword: cve"`) {
+		t.Errorf("post = %d %s", status, body)
+	}
+	if status, _ := do(t, srv, http.MethodGet, "/api/posts/2", "", ""); status != http.StatusNotFound {
+		t.Errorf("missing post = %d, want 404", status)
+	}
+	if status, _ := do(t, srv, http.MethodGet, "/api/posts/abc", "", ""); status != http.StatusBadRequest {
+		t.Errorf("bad id = %d, want 400", status)
+	}
+}
+
+func TestDashboard_FeedbackAndStar(t *testing.T) {
+	fb := newFakeBackend()
+	srv := httptest.NewServer(New(NewHub(), fb).Handler())
+	defer srv.Close()
+
+	if status, body := do(t, srv, http.MethodPost, "/api/posts/1/feedback", "application/json", `{"vote":"down"}`); status != http.StatusOK || !strings.Contains(body, `"vote":"down"`) {
+		t.Errorf("feedback = %d %s", status, body)
+	}
+	if up, ok := fb.votes[1]; !ok || up {
+		t.Errorf("votes = %v, want down on #1", fb.votes)
+	}
+	for _, tc := range []struct {
+		name, path, contentType, body string
+		want                          int
+	}{
+		{"form post", "/api/posts/1/feedback", "application/x-www-form-urlencoded", "vote=up", http.StatusUnsupportedMediaType},
+		{"bad vote", "/api/posts/1/feedback", "application/json", `{"vote":"meh"}`, http.StatusBadRequest},
+		{"bad json", "/api/posts/1/feedback", "application/json", `{`, http.StatusBadRequest},
+		{"missing post", "/api/posts/2/feedback", "application/json; charset=utf-8", `{"vote":"up"}`, http.StatusNotFound},
+	} {
+		if status, body := do(t, srv, http.MethodPost, tc.path, tc.contentType, tc.body); status != tc.want {
+			t.Errorf("%s = %d %s, want %d", tc.name, status, body, tc.want)
+		}
+	}
+
+	if status, _ := do(t, srv, http.MethodPut, "/api/posts/1/star", "", ""); status != http.StatusOK || !fb.starred[1] {
+		t.Errorf("star = %d, starred %v", status, fb.starred)
+	}
+	if status, body := do(t, srv, http.MethodDelete, "/api/posts/1/star", "", ""); status != http.StatusOK || fb.starred[1] || !strings.Contains(body, `"starred":false`) {
+		t.Errorf("unstar = %d %s", status, body)
+	}
+	if status, body := do(t, srv, http.MethodPut, "/api/posts/3/star", "", ""); status != http.StatusInternalServerError || !strings.Contains(body, "db locked") {
+		t.Errorf("star error = %d %s", status, body)
+	}
+}
+
+func TestAPI_PostsAndStats(t *testing.T) {
+	fb := newFakeBackend()
+	srv := httptest.NewServer(New(NewHub(), fb).Handler())
+	defer srv.Close()
+
+	status, body := do(t, srv, http.MethodGet, "/api/posts?since=48h&tier=skim&source=rss&channel=CISA&unread=true&limit=5000&offset=10", "", "")
+	if status != http.StatusOK || !strings.Contains(body, `"posts":[{"id":2`) {
+		t.Fatalf("posts = %d %s", status, body)
+	}
+	want := PostQuery{Since: 48 * time.Hour, Tier: "skim", Source: "rss", Channel: "CISA", UnreadOnly: true, Limit: maxPostsLimit, Offset: 10}
+	if fb.posts != want {
+		t.Errorf("query = %+v, want %+v", fb.posts, want)
+	}
+	if status, _ := do(t, srv, http.MethodGet, "/api/posts", "", ""); status != http.StatusOK || fb.posts.Since != defaultPostsSince || fb.posts.Limit != defaultPostsLimit {
+		t.Errorf("defaults = %+v", fb.posts)
+	}
+	for _, path := range []string{"/api/posts?tier=hot", "/api/posts?limit=0", "/api/posts?offset=-1"} {
+		if status, _ := do(t, srv, http.MethodGet, path, "", ""); status != http.StatusBadRequest {
+			t.Errorf("%s = %d, want 400", path, status)
+		}
+	}
+
+	status, body = do(t, srv, http.MethodGet, "/api/stats?since=7d", "", "")
+	if status != http.StatusOK || fb.since != 7*24*time.Hour {
+		t.Fatalf("stats = %d %s", status, body)
+	}
+	for _, want := range []string{`"posts":3`, `"starred":2`, `"feedback_up":1`, `"channels":[]`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("stats body missing %s: %s", want, body)
+		}
+	}
+}
+
+func TestAPI_Token(t *testing.T) {
+	s := New(NewHub(), newFakeBackend())
+	s.SetToken("s3cret")
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+
+	status, body := do(t, srv, http.MethodGet, "/api/stats", "", "")
+	if status != http.StatusUnauthorized || !strings.Contains(body, "missing or invalid API token") {
+		t.Errorf("no token = %d %s", status, body)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/api/stats", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized || resp.Header.Get("WWW-Authenticate") == "" {
+		t.Errorf("wrong token = %d", resp.StatusCode)
+	}
+
+	req.Header.Set("Authorization", "Bearer s3cret")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("bearer token = %d, want 200", resp.StatusCode)
+	}
+
+	if status, _ := do(t, srv, http.MethodGet, "/api/posts/1?token=s3cret", "", ""); status != http.StatusOK {
+		t.Errorf("query token = %d, want 200", status)
+	}
+	if status, _ := do(t, srv, http.MethodGet, "/", "", ""); status != http.StatusOK {
+		t.Errorf("dashboard page = %d, want 200 without a token", status)
+	}
+}
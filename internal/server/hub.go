@@ -0,0 +1,57 @@
+package server
+
+import "sync"
+
+// subscriberBuffer is how many items a slow subscriber may fall behind
+// before new items are dropped for it.
+const subscriberBuffer = 64
+
+// Hub fans out published items to every subscriber. Publishing never blocks:
+// a subscriber whose buffer is full misses the item.
+type Hub struct {
+	mu   sync.Mutex
+	subs map[chan Item]struct{}
+}
+
+// NewHub creates an empty hub.
+func NewHub() *Hub {
+	return &Hub{subs: make(map[chan Item]struct{})}
+}
+
+// Subscribe registers a new subscriber. Call the returned function to
+// unsubscribe; the channel is closed afterwards.
+func (h *Hub) Subscribe() (<-chan Item, func()) {
+	ch := make(chan Item, subscriberBuffer)
+	h.mu.Lock()
+	h.subs[ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subs, ch)
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Publish sends item to all current subscribers.
+func (h *Hub) Publish(item Item) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs {
+		select {
+		case ch <- item:
+		default:
+		}
+	}
+}
+
+// Subscribers returns the number of connected subscribers.
+func (h *Hub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs)
+}
@@ -0,0 +1,40 @@
+package server
+
+import "testing"
+
+func TestHub_PublishSubscribe(t *testing.T) {
+	h := NewHub()
+	a, unsubA := h.Subscribe()
+	b, unsubB := h.Subscribe()
+	defer unsubB()
+
+	h.Publish(Item{ID: 1})
+	if got := <-a; got.ID != 1 {
+		t.Errorf("a got %d, want 1", got.ID)
+	}
+	if got := <-b; got.ID != 1 {
+		t.Errorf("b got %d, want 1", got.ID)
+	}
+
+	unsubA()
+	unsubA() // idempotent
+	if _, ok := <-a; ok {
+		t.Error("channel should be closed after unsubscribe")
+	}
+	if n := h.Subscribers(); n != 1 {
+		t.Errorf("subscribers = %d, want 1", n)
+	}
+}
+
+func TestHub_SlowSubscriberDoesNotBlock(t *testing.T) {
+	h := NewHub()
+	ch, unsub := h.Subscribe()
+	defer unsub()
+
+	for i := 0; i < subscriberBuffer+10; i++ {
+		h.Publish(Item{ID: int64(i)})
+	}
+	if len(ch) != subscriberBuffer {
+		t.Errorf("buffered = %d, want %d", len(ch), subscriberBuffer)
+	}
+}
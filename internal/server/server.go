@@ -0,0 +1,133 @@
+// Package server implements the HTTP endpoints of "noisepan serve": the
+// event stream and, with a Backend, the web dashboard and its JSON API.
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// heartbeatInterval keeps idle event streams open through proxies.
+const heartbeatInterval = 30 * time.Second
+
+// Item is a scored post as sent to stream clients.
+type Item struct {
+	ID       int64    `json:"id"`
+	Source   string   `json:"source"`
+	Channel  string   `json:"channel"`
+	URL      string   `json:"url,omitempty"`
+	PostedAt string   `json:"posted_at"`
+	Score    int      `json:"score"`
+	Tier     string   `json:"tier"`
+	Labels   []string `json:"labels,omitempty"`
+	Snippet  string   `json:"snippet"`
+	Starred  bool     `json:"starred,omitempty"`
+	Vote     int      `json:"vote,omitempty"` // +1 up, -1 down
+}
+
+// Server serves the noisepan HTTP API.
+type Server struct {
+	hub     *Hub
+	backend Backend
+	token   string
+	tiers   []string
+	mux     *http.ServeMux
+}
+
+// New creates a server that streams items published on hub. With a non-nil
+// backend it also serves the web dashboard at / and its JSON endpoints.
+func New(hub *Hub, backend Backend) *Server {
+	s := &Server{
+		hub: hub, backend: backend, mux: http.NewServeMux(),
+		tiers: []string{"read_now", "skim", "ignore"},
+	}
+	s.mux.HandleFunc("GET /api/stream", s.handleStream)
+	if backend != nil {
+		s.handleDashboard()
+	}
+	return s
+}
+
+// SetToken requires token on every /api request, sent as
+// "Authorization: Bearer <token>" or, for clients that cannot set headers
+// such as EventSource, a token query parameter. An empty token disables
+// authentication. The dashboard page itself is always served.
+func (s *Server) SetToken(token string) {
+	s.token = token
+}
+
+// SetTiers sets the tier names the tier parameter of /api/posts accepts,
+// for a taste profile with custom tiers.
+func (s *Server) SetTiers(tiers []string) {
+	s.tiers = tiers
+}
+
+// Handler returns the root HTTP handler.
+func (s *Server) Handler() http.Handler {
+	if s.token == "" {
+		return s.mux
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/api/") && !s.authorized(r) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="noisepan"`)
+			writeError(w, http.StatusUnauthorized, errUnauthorized)
+			return
+		}
+		s.mux.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		got = r.URL.Query().Get("token")
+	}
+	return subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) == 1
+}
+
+// handleStream sends each published item as a server-sent event named
+// "post" until the client disconnects.
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	items, unsubscribe := s.hub.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case item, ok := <-items:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(item)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "id: %d\nevent: post\ndata: %s\n\n", item.ID, data)
+			flusher.Flush()
+		}
+	}
+}
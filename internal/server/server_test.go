@@ -0,0 +1,80 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStream(t *testing.T) {
+	hub := NewHub()
+	srv := httptest.NewServer(New(hub, nil).Handler())
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/stream", nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("get stream: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("content-type = %q", ct)
+	}
+
+	r := bufio.NewReader(resp.Body)
+	if line, _ := r.ReadString('\n'); line != ": connected\n" {
+		t.Fatalf("first line = %q", line)
+	}
+
+	hub.Publish(Item{ID: 7, Source: "rss", Channel: "CISA", Score: 9, Tier: "read_now", Snippet: "KEV update"})
+
+	var event, data string
+	for data == "" {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read event: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+	if event != "post" {
+		t.Errorf("event = %q, want post", event)
+	}
+	var got Item
+	if err := json.Unmarshal([]byte(data), &got); err != nil {
+		t.Fatalf("unmarshal %q: %v", data, err)
+	}
+	if got.ID != 7 || got.Tier != "read_now" || got.Snippet != "KEV update" {
+		t.Errorf("item = %+v", got)
+	}
+}
+
+func TestStream_MethodNotAllowed(t *testing.T) {
+	srv := httptest.NewServer(New(NewHub(), nil).Handler())
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/api/stream", "text/plain", nil)
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want 405", resp.StatusCode)
+	}
+}
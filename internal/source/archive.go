@@ -0,0 +1,392 @@
+package source
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/cache"
+)
+
+const (
+	archiveSourceName    = "archive"
+	archiveTimeout       = 30 * time.Second
+	archiveDelay         = 1 * time.Second
+	archiveMaxDays       = 31
+	archiveMaxBody       = 1 << 20 // 1 MiB per issue
+	archiveDefaultLayout = "2006-01-02"
+	archiveDatePlacehold = "{date}"
+
+	// geminiPinNamespace and geminiPinTTL govern pinned capsule
+	// certificates. Every verified connection renews its pin.
+	geminiPinNamespace = "gemini-cert"
+	geminiPinTTL       = 365 * 24 * time.Hour
+)
+
+var (
+	// errArchiveNotFound marks an issue that does not exist for a given date.
+	errArchiveNotFound = errors.New("not found")
+	// errCertChanged marks a Gemini capsule presenting a certificate other
+	// than the one pinned for it.
+	errCertChanged = errors.New("certificate changed")
+)
+
+// Newsletter describes a dated plaintext/markdown archive. URLPattern must
+// contain {date}, which is replaced with each day formatted by DateFormat
+// (a Go time layout, default 2006-01-02). http, https, gemini, and gopher
+// URLs are supported. Gemini and Gopher are dialed directly: the proxy and
+// CA settings of the HTTP transport do not apply to them.
+type Newsletter struct {
+	Name       string
+	URLPattern string
+	DateFormat string
+}
+
+// ArchiveSource polls newsletter archives for issues dated within the fetch window.
+type ArchiveSource struct {
+	newsletters []Newsletter
+	client      *http.Client
+	policy      FetchPolicy
+	now         func() time.Time
+	statuses    []FeedStatus
+	location    *time.Location
+	cache       *cache.Cache
+	pins        map[string]geminiPin // host:port -> pin, this run
+}
+
+// geminiPin is the certificate a Gemini capsule presented first.
+type geminiPin struct {
+	Fingerprint string    `json:"sha256"`
+	NotAfter    time.Time `json:"not_after"`
+}
+
+// NewArchive creates a newsletter archive source. At least one newsletter is required.
+func NewArchive(newsletters []Newsletter) (*ArchiveSource, error) {
+	if len(newsletters) == 0 {
+		return nil, errors.New("archive: at least one newsletter is required")
+	}
+	for i, n := range newsletters {
+		if strings.TrimSpace(n.Name) == "" {
+			return nil, fmt.Errorf("archive: newsletter %d: name is required", i)
+		}
+		if !strings.Contains(n.URLPattern, archiveDatePlacehold) {
+			return nil, fmt.Errorf("archive: %s: url_pattern must contain %s", n.Name, archiveDatePlacehold)
+		}
+		if n.DateFormat == "" {
+			newsletters[i].DateFormat = archiveDefaultLayout
+		}
+	}
+	return &ArchiveSource{
+		newsletters: newsletters,
+		client:      &http.Client{Timeout: archiveTimeout},
+		policy:      FetchPolicy{Timeout: archiveTimeout, Delay: archiveDelay, Backoff: time.Second},
+		now:         time.Now,
+		pins:        make(map[string]geminiPin),
+	}, nil
+}
+
+func (a *ArchiveSource) Name() string {
+	return archiveSourceName
+}
+
+// SetLocation sets the zone issue dates are published in (default UTC).
+func (a *ArchiveSource) SetLocation(loc *time.Location) {
+	a.location = loc
+}
+
+// SetTransport replaces the HTTP transport used for http(s) archives.
+func (a *ArchiveSource) SetTransport(rt http.RoundTripper) {
+	a.client.Transport = rt
+}
+
+// SetCache sets the cache Gemini certificates are pinned in between runs.
+// Without one, they are pinned for the run only.
+func (a *ArchiveSource) SetCache(c *cache.Cache) {
+	a.cache = c
+}
+
+// Policy returns the current fetch policy.
+func (a *ArchiveSource) Policy() FetchPolicy {
+	return a.policy
+}
+
+// SetPolicy replaces the fetch policy.
+func (a *ArchiveSource) SetPolicy(p FetchPolicy) {
+	a.policy = p
+	a.client.Timeout = p.Timeout
+}
+
+// Fetch requests one issue per day from since (capped at 31 days back) to
+// today. Missing issues are skipped silently; other errors are logged.
+func (a *ArchiveSource) Fetch(since time.Time) ([]Post, error) {
+	now := a.now().UTC()
+	start := since.UTC().Truncate(24 * time.Hour)
+	if earliest := now.Truncate(24*time.Hour).AddDate(0, 0, -archiveMaxDays); start.Before(earliest) {
+		start = earliest
+	}
+
+	var posts []Post
+	requests := 0
+	a.statuses = nil
+	for _, nl := range a.newsletters {
+		var lastErr error
+		seen := make(map[string]bool)
+		for day := start; !day.After(now); day = day.AddDate(0, 0, 1) {
+			issueURL := strings.ReplaceAll(nl.URLPattern, archiveDatePlacehold, day.Format(nl.DateFormat))
+			if seen[issueURL] {
+				continue // coarse layouts (e.g. monthly) map several days to one URL
+			}
+			seen[issueURL] = true
+
+			if requests > 0 {
+				rssSleepFunc(a.policy.Delay)
+			}
+			requests++
+
+			var (
+				text     string
+				modified time.Time
+			)
+			err := a.policy.retry(rssSleepFunc, func() error {
+				var err error
+				text, modified, err = a.fetchIssue(issueURL)
+				return err
+			})
+			if errors.Is(err, errArchiveNotFound) {
+				continue
+			}
+			if err != nil {
+				slog.Warn("fetch failed", "source", archiveSourceName, "url", issueURL, "err", err)
+				lastErr = err
+				continue
+			}
+
+			text = strings.TrimSpace(text)
+			if text == "" {
+				continue
+			}
+			postedAt := day
+			if a.location != nil {
+				postedAt = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, a.location)
+			}
+			// The URL's date is the issue's; Last-Modified, often the
+			// deploy time of the whole archive, only adds the time of day
+			// when it falls on that date.
+			if m := modified.In(postedAt.Location()); !modified.IsZero() && sameDay(m, postedAt) && m.After(postedAt) {
+				postedAt = m
+			}
+			posts = append(posts, Post{
+				Source:     archiveSourceName,
+				Channel:    nl.Name,
+				ExternalID: issueURL,
+				Text:       text,
+				URL:        issueURL,
+				PostedAt:   postedAt,
+			})
+		}
+		a.statuses = append(a.statuses, FeedStatus{Feed: nl.Name, Err: lastErr})
+	}
+	return posts, nil
+}
+
+// sameDay reports whether a and b fall on the same calendar day in a's
+// location.
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.In(a.Location()).Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// FeedStatuses returns the per-newsletter outcome of the last Fetch. A
+// newsletter counts as failing if any issue request failed for a reason
+// other than the issue not existing.
+func (a *ArchiveSource) FeedStatuses() []FeedStatus {
+	return a.statuses
+}
+
+func (a *ArchiveSource) fetchIssue(rawURL string) (string, time.Time, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("parse url: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.policy.Timeout)
+	defer cancel()
+
+	switch u.Scheme {
+	case "http", "https":
+		return a.fetchHTTP(ctx, rawURL)
+	case "gemini":
+		text, err := a.fetchGemini(ctx, u)
+		return text, time.Time{}, err
+	case "gopher":
+		text, err := fetchGopher(ctx, u)
+		return text, time.Time{}, err
+	}
+	return "", time.Time{}, fmt.Errorf("unsupported scheme %q", u.Scheme)
+}
+
+func (a *ArchiveSource) fetchHTTP(ctx context.Context, rawURL string) (string, time.Time, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("User-Agent", rssUserAgent)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return "", time.Time{}, errArchiveNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", time.Time{}, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, archiveMaxBody))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("read body: %w", err)
+	}
+
+	var modified time.Time
+	if lm := resp.Header.Get("Last-Modified"); lm != "" {
+		modified, _ = http.ParseTime(lm)
+	}
+	return string(body), modified, nil
+}
+
+// fetchGemini performs a single Gemini request. Gemini capsules commonly use
+// self-signed certificates, so instead of a CA chain the certificate is
+// trusted on first use: pinned, and any other one refused until the pinned
+// one expires.
+func (a *ArchiveSource) fetchGemini(ctx context.Context, u *url.URL) (string, error) {
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "1965")
+	}
+
+	d := &tls.Dialer{Config: &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: true, //nolint:gosec // verified against the pin in VerifyConnection
+		ServerName:         u.Hostname(),
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			return a.verifyGeminiCert(ctx, host, cs)
+		},
+	}}
+	conn, err := d.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = conn.Close() }()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if _, err := fmt.Fprintf(conn, "%s\r\n", u.String()); err != nil {
+		return "", fmt.Errorf("write request: %w", err)
+	}
+
+	r := bufio.NewReader(io.LimitReader(conn, archiveMaxBody))
+	header, err := r.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("read header: %w", err)
+	}
+	status := strings.TrimSpace(header)
+	if len(status) < 2 {
+		return "", fmt.Errorf("malformed header %q", status)
+	}
+	switch status[0] {
+	case '2':
+		body, err := io.ReadAll(r)
+		if err != nil {
+			return "", fmt.Errorf("read body: %w", err)
+		}
+		return string(body), nil
+	case '5':
+		if strings.HasPrefix(status, "51") {
+			return "", errArchiveNotFound
+		}
+	}
+	return "", fmt.Errorf("gemini status %s", status)
+}
+
+// verifyGeminiCert checks the certificate host presented against its pin,
+// pinning it when host has none or the pinned certificate has expired.
+func (a *ArchiveSource) verifyGeminiCert(ctx context.Context, host string, cs tls.ConnectionState) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("no certificate")
+	}
+	leaf := cs.PeerCertificates[0]
+	sum := sha256.Sum256(leaf.Raw)
+	got := geminiPin{Fingerprint: hex.EncodeToString(sum[:]), NotAfter: leaf.NotAfter}
+
+	pin, ok := a.pins[host]
+	if !ok {
+		var err error
+		if ok, err = a.cache.GetJSON(ctx, geminiPinNamespace, host, &pin); err != nil {
+			return fmt.Errorf("look up pinned certificate: %w", err)
+		}
+	}
+	if ok && pin.Fingerprint != got.Fingerprint && a.now().Before(pin.NotAfter) {
+		return fmt.Errorf("%w: %s presents sha256 %s, pinned %s until %s; delete cache.path to trust it",
+			errCertChanged, host, got.Fingerprint, pin.Fingerprint, pin.NotAfter.Format(time.DateOnly))
+	}
+	a.pins[host] = got
+	if err := a.cache.SetJSON(ctx, geminiPinNamespace, host, got, geminiPinTTL); err != nil {
+		slog.Debug("cache store failed", "source", archiveSourceName, "err", err)
+	}
+	return nil
+}
+
+// fetchGopher performs a single Gopher request. URLs follow RFC 4266:
+// gopher://host[:port]/<type><selector>.
+func fetchGopher(ctx context.Context, u *url.URL) (string, error) {
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "70")
+	}
+
+	selector := strings.TrimPrefix(u.Path, "/")
+	if len(selector) > 0 {
+		selector = selector[1:] // drop the item type character
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = conn.Close() }()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if _, err := fmt.Fprintf(conn, "%s\r\n", selector); err != nil {
+		return "", fmt.Errorf("write selector: %w", err)
+	}
+	body, err := io.ReadAll(io.LimitReader(conn, archiveMaxBody))
+	if err != nil {
+		return "", fmt.Errorf("read body: %w", err)
+	}
+
+	text := strings.TrimSuffix(strings.TrimRight(string(body), "\r\n"), "\r\n.")
+	text = strings.TrimSuffix(text, "\n.")
+	if strings.HasPrefix(text, "3") && strings.Contains(text, "\t") {
+		return "", errArchiveNotFound // gopher error item
+	}
+	return text, nil
+}
@@ -0,0 +1,296 @@
+package source
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/cache"
+)
+
+func newTestArchive(t *testing.T, newsletters []Newsletter, now time.Time) *ArchiveSource {
+	t.Helper()
+	oldSleep := rssSleepFunc
+	rssSleepFunc = func(_ time.Duration) {}
+	t.Cleanup(func() { rssSleepFunc = oldSleep })
+
+	a, err := NewArchive(newsletters)
+	if err != nil {
+		t.Fatalf("NewArchive: %v", err)
+	}
+	a.now = func() time.Time { return now }
+	return a
+}
+
+func TestNewArchive_Validation(t *testing.T) {
+	if _, err := NewArchive(nil); err == nil {
+		t.Error("expected error for no newsletters")
+	}
+	if _, err := NewArchive([]Newsletter{{URLPattern: "https://x/{date}"}}); err == nil {
+		t.Error("expected error for missing name")
+	}
+	if _, err := NewArchive([]Newsletter{{Name: "x", URLPattern: "https://x/latest"}}); err == nil {
+		t.Error("expected error for pattern without {date}")
+	}
+}
+
+func TestArchiveFetch_HTTP(t *testing.T) {
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		switch r.URL.Path {
+		case "/issues/2026-03-02.md":
+			_, _ = w.Write([]byte("# Issue 12\n\nKubernetes 1.40 released\n"))
+		case "/issues/2026-03-01.md":
+			// Redeployed archives stamp old issues with the deploy time.
+			w.Header().Set("Last-Modified", "Tue, 03 Mar 2026 09:00:00 GMT")
+			_, _ = w.Write([]byte("Issue 11"))
+		case "/issues/2026-03-03.md":
+			w.Header().Set("Last-Modified", "Tue, 03 Mar 2026 08:30:00 GMT")
+			_, _ = w.Write([]byte("Issue 13"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	now := time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC)
+	a := newTestArchive(t, []Newsletter{{Name: "Ops Weekly", URLPattern: srv.URL + "/issues/{date}.md"}}, now)
+
+	posts, err := a.Fetch(now.Add(-48 * time.Hour))
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(requested) != 3 {
+		t.Errorf("requested %d issues, want 3: %v", len(requested), requested)
+	}
+	if len(posts) != 3 {
+		t.Fatalf("got %d posts, want 3", len(posts))
+	}
+	if want := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC); !posts[0].PostedAt.Equal(want) {
+		t.Errorf("PostedAt = %v, want issue date %v despite a later Last-Modified", posts[0].PostedAt, want)
+	}
+	posts = posts[1:]
+
+	p := posts[0]
+	if p.Source != "archive" || p.Channel != "Ops Weekly" {
+		t.Errorf("source/channel = %q/%q", p.Source, p.Channel)
+	}
+	if p.ExternalID != srv.URL+"/issues/2026-03-02.md" || p.URL != p.ExternalID {
+		t.Errorf("ExternalID = %q, URL = %q", p.ExternalID, p.URL)
+	}
+	if !strings.HasPrefix(p.Text, "# Issue 12") {
+		t.Errorf("Text = %q", p.Text)
+	}
+	if !p.PostedAt.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("PostedAt = %v, want issue date", p.PostedAt)
+	}
+	if want := time.Date(2026, 3, 3, 8, 30, 0, 0, time.UTC); !posts[1].PostedAt.Equal(want) {
+		t.Errorf("PostedAt = %v, want Last-Modified %v", posts[1].PostedAt, want)
+	}
+}
+
+func TestArchiveFetch_Location(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("issue"))
+	}))
+	defer srv.Close()
+
+	now := time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC)
+	a := newTestArchive(t, []Newsletter{{Name: "Weekly", URLPattern: srv.URL + "/{date}"}}, now)
+	a.SetLocation(time.FixedZone("PST", -8*60*60))
+
+	posts, err := a.Fetch(now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(posts) != 1 {
+		t.Fatalf("got %d posts, want 1", len(posts))
+	}
+	if want := time.Date(2026, 3, 3, 8, 0, 0, 0, time.UTC); !posts[0].PostedAt.Equal(want) {
+		t.Errorf("PostedAt = %v, want local midnight %v", posts[0].PostedAt, want)
+	}
+}
+
+func TestArchiveFetch_CoarseLayoutRequestsOnce(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte("monthly roundup"))
+	}))
+	defer srv.Close()
+
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	a := newTestArchive(t, []Newsletter{{Name: "Monthly", URLPattern: srv.URL + "/{date}.txt", DateFormat: "2006-01"}}, now)
+
+	posts, err := a.Fetch(now.AddDate(0, 0, -5))
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if calls != 1 || len(posts) != 1 {
+		t.Errorf("calls = %d, posts = %d, want 1 and 1", calls, len(posts))
+	}
+}
+
+func TestArchiveFetch_CapsLookback(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	a := newTestArchive(t, []Newsletter{{Name: "Daily", URLPattern: srv.URL + "/{date}"}}, now)
+
+	if _, err := a.Fetch(now.AddDate(-1, 0, 0)); err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if calls != archiveMaxDays+1 {
+		t.Errorf("calls = %d, want %d", calls, archiveMaxDays+1)
+	}
+}
+
+func TestArchiveFetch_Gopher(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			selector, _ := bufio.NewReader(conn).ReadString('\n')
+			selector = strings.TrimSpace(selector)
+			if selector == "/news/2026-03-03.txt" {
+				_, _ = conn.Write([]byte("Gopher issue\r\n.\r\n"))
+			} else {
+				_, _ = conn.Write([]byte("3not found\t\terror.host\t1\r\n.\r\n"))
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	now := time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC)
+	a := newTestArchive(t, []Newsletter{{Name: "Phlog", URLPattern: "gopher://" + ln.Addr().String() + "/0/news/{date}.txt"}}, now)
+
+	posts, err := a.Fetch(now.Add(-24 * time.Hour))
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(posts) != 1 {
+		t.Fatalf("got %d posts, want 1", len(posts))
+	}
+	if posts[0].Text != "Gopher issue" {
+		t.Errorf("Text = %q", posts[0].Text)
+	}
+}
+
+// selfSignedCert returns a certificate for localhost valid until notAfter.
+func selfSignedCert(t *testing.T, notAfter time.Time) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    notAfter.AddDate(-1, 0, 0),
+		NotAfter:     notAfter,
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestArchiveFetch_GeminiPinsCertificate(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		cert = selfSignedCert(t, time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC))
+	)
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return &cert, nil
+	}})
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			req, _ := bufio.NewReader(conn).ReadString('\n')
+			if strings.HasSuffix(strings.TrimSpace(req), "/2026-03-03.gmi") {
+				_, _ = conn.Write([]byte("20 text/gemini\r\n# Capsule issue\n"))
+			} else {
+				_, _ = conn.Write([]byte("51 Not found\r\n"))
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	c, err := cache.Open(filepath.Join(t.TempDir(), "cache.db"))
+	if err != nil {
+		t.Fatalf("open cache: %v", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	now := time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC)
+	fetch := func(now time.Time) ([]Post, error) {
+		a := newTestArchive(t, []Newsletter{{Name: "Capsule", URLPattern: "gemini://" + ln.Addr().String() + "/{date}.gmi"}}, now)
+		a.SetCache(c)
+		posts, err := a.Fetch(now.Add(-24 * time.Hour))
+		if err != nil {
+			t.Fatalf("Fetch: %v", err)
+		}
+		return posts, a.FeedStatuses()[0].Err
+	}
+
+	// First use pins the certificate; one missing day is skipped.
+	posts, err := fetch(now)
+	if err != nil || len(posts) != 1 || posts[0].Text != "# Capsule issue" {
+		t.Fatalf("first fetch: posts = %+v, err = %v", posts, err)
+	}
+	if posts, err = fetch(now); err != nil || len(posts) != 1 {
+		t.Fatalf("same certificate: posts = %d, err = %v", len(posts), err)
+	}
+
+	mu.Lock()
+	cert = selfSignedCert(t, time.Date(2027, 6, 1, 0, 0, 0, 0, time.UTC))
+	mu.Unlock()
+	if posts, err = fetch(now); !errors.Is(err, errCertChanged) || len(posts) != 0 {
+		t.Errorf("changed certificate: posts = %d, err = %v, want errCertChanged", len(posts), err)
+	}
+
+	// Once the pinned certificate has expired, the new one is pinned.
+	later := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	if _, err = fetch(later); err != nil {
+		t.Errorf("after pinned expiry: err = %v", err)
+	}
+}
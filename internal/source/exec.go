@@ -0,0 +1,54 @@
+package source
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// PythonCommand returns the interpreter and leading arguments used when no
+// python_path is configured: python3 on Unix; on Windows the py launcher
+// ("py -3") when installed, else python.
+func PythonCommand() (string, []string) {
+	return pythonCommand(runtime.GOOS, exec.LookPath)
+}
+
+func pythonCommand(goos string, lookPath func(string) (string, error)) (string, []string) {
+	if goos != "windows" {
+		return "python3", nil
+	}
+	if _, err := lookPath("py"); err == nil {
+		return "py", []string{"-3"}
+	}
+	return "python", nil
+}
+
+// ScriptCommand returns the program and arguments that run the script at
+// path. Unix runs it directly and relies on its shebang. Windows has no
+// shebangs, so the interpreter is chosen by extension: .ps1 via PowerShell,
+// .py via Python, .sh via sh (Git for Windows or MSYS2); anything else
+// (.exe, .bat, .cmd) runs directly.
+func ScriptCommand(path string) (string, []string, error) {
+	return scriptCommand(runtime.GOOS, path, exec.LookPath)
+}
+
+func scriptCommand(goos, path string, lookPath func(string) (string, error)) (string, []string, error) {
+	if goos != "windows" {
+		return path, nil, nil
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ps1":
+		return "powershell", []string{"-NoProfile", "-ExecutionPolicy", "Bypass", "-File", path}, nil
+	case ".py":
+		py, args := pythonCommand(goos, lookPath)
+		return py, append(args, path), nil
+	case ".sh":
+		if _, err := lookPath("sh"); err != nil {
+			return "", nil, fmt.Errorf("%s needs sh, which was not found: install Git for Windows or use a .ps1 or .cmd script", path)
+		}
+		return "sh", []string{path}, nil
+	}
+	return path, nil, nil
+}
@@ -0,0 +1,71 @@
+package source
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func fakeLookPath(found ...string) func(string) (string, error) {
+	return func(name string) (string, error) {
+		for _, f := range found {
+			if f == name {
+				return `C:\bin\` + name + ".exe", nil
+			}
+		}
+		return "", errors.New("not found")
+	}
+}
+
+func TestPythonCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		goos     string
+		found    []string
+		wantName string
+		wantArgs []string
+	}{
+		{"unix", "linux", nil, "python3", nil},
+		{"windows py launcher", "windows", []string{"py", "python"}, "py", []string{"-3"}},
+		{"windows python", "windows", []string{"python"}, "python", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, args := pythonCommand(tt.goos, fakeLookPath(tt.found...))
+			if name != tt.wantName || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got %s %v, want %s %v", name, args, tt.wantName, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestScriptCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		goos     string
+		path     string
+		found    []string
+		wantName string
+		wantArgs []string
+		wantErr  bool
+	}{
+		{"unix runs directly", "darwin", "/opt/forge-plan.sh", nil, "/opt/forge-plan.sh", nil, false},
+		{"powershell", "windows", `C:\forge\plan.PS1`, nil, "powershell",
+			[]string{"-NoProfile", "-ExecutionPolicy", "Bypass", "-File", `C:\forge\plan.PS1`}, false},
+		{"python", "windows", `C:\forge\plan.py`, []string{"py"}, "py", []string{"-3", `C:\forge\plan.py`}, false},
+		{"sh from git", "windows", `C:\forge\plan.sh`, []string{"sh"}, "sh", []string{`C:\forge\plan.sh`}, false},
+		{"sh missing", "windows", `C:\forge\plan.sh`, nil, "", nil, true},
+		{"batch runs directly", "windows", `C:\forge\plan.cmd`, nil, `C:\forge\plan.cmd`, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, args, err := scriptCommand(tt.goos, tt.path, fakeLookPath(tt.found...))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if name != tt.wantName || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got %s %v, want %s %v", name, args, tt.wantName, tt.wantArgs)
+			}
+		})
+	}
+}
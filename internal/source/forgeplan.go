@@ -11,9 +11,12 @@ import (
 	"time"
 )
 
+const forgePlanTimeout = 30 * time.Second
+
 // ForgePlanSource runs forge-plan.sh and ingests suggested actions as posts.
 type ForgePlanSource struct {
 	scriptPath string
+	policy     FetchPolicy
 }
 
 // NewForgePlan creates a forge-plan source. scriptPath must be non-empty.
@@ -21,7 +24,20 @@ func NewForgePlan(scriptPath string) (*ForgePlanSource, error) {
 	if strings.TrimSpace(scriptPath) == "" {
 		return nil, fmt.Errorf("forgeplan: script path is required")
 	}
-	return &ForgePlanSource{scriptPath: scriptPath}, nil
+	return &ForgePlanSource{
+		scriptPath: scriptPath,
+		policy:     FetchPolicy{Timeout: forgePlanTimeout},
+	}, nil
+}
+
+// Policy returns the current fetch policy.
+func (f *ForgePlanSource) Policy() FetchPolicy {
+	return f.policy
+}
+
+// SetPolicy replaces the fetch policy. Only Timeout applies to the script.
+func (f *ForgePlanSource) SetPolicy(p FetchPolicy) {
+	f.policy = p
 }
 
 func (f *ForgePlanSource) Name() string { return "forgeplan" }
@@ -35,11 +51,16 @@ func (f *ForgePlanSource) Fetch(_ time.Time) ([]Post, error) {
 		return nil, fmt.Errorf("forgeplan: %s is a directory, not a script", f.scriptPath)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	name, args, err := ScriptCommand(f.scriptPath)
+	if err != nil {
+		return nil, fmt.Errorf("forgeplan: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), f.policy.Timeout)
 	defer cancel()
 
 	var stdout, stderr bytes.Buffer
-	cmd := exec.CommandContext(ctx, f.scriptPath)
+	cmd := exec.CommandContext(ctx, name, args...)
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
@@ -5,10 +5,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"sync"
 	"time"
+
+	"github.com/ppiankov/noisepan/internal/cache"
 )
 
 const (
@@ -18,11 +21,26 @@ const (
 	hnFetchTimeout = 30 * time.Second
 	hnMaxStories   = 200
 	hnMaxWorkers   = 5
+	hnBackoff      = 1 * time.Second
+
+	// hnItemNamespace and hnItemTTL govern cached item lookups. Scores keep
+	// rising for a while, so a story below min_points is picked up at most
+	// hnItemTTL after it crosses the threshold.
+	hnItemNamespace = "hn-item"
+	hnItemTTL       = 30 * time.Minute
 )
 
-// HNSource fetches top stories from Hacker News via the Firebase API.
+// hnSleepFunc is used for retry delays. It can be overridden in tests.
+var hnSleepFunc = time.Sleep
+
+// HNSource fetches top stories from Hacker News via the Firebase API, or
+// recent stories via the Algolia search API (see SetAPI).
 type HNSource struct {
 	minPoints int
+	api       string
+	client    *http.Client
+	policy    FetchPolicy
+	cache     *cache.Cache
 }
 
 // NewHN creates a Hacker News source. minPoints filters stories below the threshold.
@@ -30,7 +48,37 @@ func NewHN(minPoints int) (*HNSource, error) {
 	if minPoints < 1 {
 		return nil, errors.New("hn: min_points must be at least 1")
 	}
-	return &HNSource{minPoints: minPoints}, nil
+	return &HNSource{
+		minPoints: minPoints,
+		api:       HNAPIFirebase,
+		client:    &http.Client{},
+		policy: FetchPolicy{
+			Timeout: hnFetchTimeout,
+			Backoff: hnBackoff,
+			Workers: hnMaxWorkers,
+		},
+	}, nil
+}
+
+// Policy returns the current fetch policy.
+func (h *HNSource) Policy() FetchPolicy {
+	return h.policy
+}
+
+// SetPolicy replaces the fetch policy. Timeout bounds the whole fetch.
+func (h *HNSource) SetPolicy(p FetchPolicy) {
+	h.policy = p
+}
+
+// SetTransport replaces the HTTP transport used for API requests.
+func (h *HNSource) SetTransport(rt http.RoundTripper) {
+	h.client.Transport = rt
+}
+
+// SetCache makes item lookups reuse responses from earlier runs for
+// hnItemTTL. A nil cache disables caching.
+func (h *HNSource) SetCache(c *cache.Cache) {
+	h.cache = c
 }
 
 func (h *HNSource) Name() string {
@@ -53,11 +101,20 @@ type hnItem struct {
 var hnAPIBaseURL = hnAPIBase
 
 func (h *HNSource) Fetch(since time.Time) ([]Post, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), hnFetchTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), h.policy.Timeout)
 	defer cancel()
 
+	if h.api == HNAPIAlgolia {
+		return h.fetchAlgolia(ctx, since)
+	}
+
 	// Fetch top story IDs.
-	ids, err := h.fetchTopStories(ctx)
+	var ids []int
+	err := h.policy.retry(hnSleepFunc, func() error {
+		var err error
+		ids, err = h.fetchTopStories(ctx)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("hn: fetch top stories: %w", err)
 	}
@@ -75,7 +132,7 @@ func (h *HNSource) Fetch(since time.Time) ([]Post, error) {
 	jobs := make(chan int, len(ids))
 	results := make(chan result, len(ids))
 
-	workers := hnMaxWorkers
+	workers := max(h.policy.Workers, 1)
 	if len(ids) < workers {
 		workers = len(ids)
 	}
@@ -86,7 +143,7 @@ func (h *HNSource) Fetch(since time.Time) ([]Post, error) {
 		go func() {
 			defer wg.Done()
 			for id := range jobs {
-				item, err := h.fetchItem(ctx, id)
+				item, err := h.cachedItem(ctx, id)
 				if err != nil {
 					results <- result{err: err}
 					continue
@@ -106,6 +163,7 @@ func (h *HNSource) Fetch(since time.Time) ([]Post, error) {
 					ExternalID: strconv.Itoa(item.ID),
 					Text:       item.Title,
 					URL:        item.URL,
+					Author:     item.By,
 					PostedAt:   postedAt,
 				}}
 			}
@@ -125,7 +183,7 @@ func (h *HNSource) Fetch(since time.Time) ([]Post, error) {
 	var posts []Post
 	for r := range results {
 		if r.err != nil {
-			fmt.Printf("  hn: %v\n", r.err)
+			slog.Warn("fetch failed", "source", hnSourceName, "err", r.err)
 			continue
 		}
 		if r.post != nil {
@@ -136,13 +194,39 @@ func (h *HNSource) Fetch(since time.Time) ([]Post, error) {
 	return posts, nil
 }
 
+// cachedItem returns the item from the cache, or fetches and caches it.
+// Cache errors only cost the lookup; the API is the source of truth.
+func (h *HNSource) cachedItem(ctx context.Context, id int) (*hnItem, error) {
+	key := strconv.Itoa(id)
+	var cached hnItem
+	if ok, err := h.cache.GetJSON(ctx, hnItemNamespace, key, &cached); err != nil {
+		slog.Debug("cache lookup failed", "source", hnSourceName, "err", err)
+	} else if ok {
+		return &cached, nil
+	}
+
+	var item *hnItem
+	err := h.policy.retry(hnSleepFunc, func() error {
+		var err error
+		item, err = h.fetchItem(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := h.cache.SetJSON(ctx, hnItemNamespace, key, item, hnItemTTL); err != nil {
+		slog.Debug("cache store failed", "source", hnSourceName, "err", err)
+	}
+	return item, nil
+}
+
 func (h *HNSource) fetchTopStories(ctx context.Context) ([]int, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, hnAPIBaseURL+"/topstories.json", nil)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := h.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -166,7 +250,7 @@ func (h *HNSource) fetchItem(ctx context.Context, id int) (*hnItem, error) {
 		return nil, err
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := h.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("item %d: %w", id, err)
 	}
@@ -0,0 +1,128 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// HN APIs selectable with SetAPI.
+const (
+	HNAPIFirebase = "firebase" // top stories, one request per item (default)
+	HNAPIAlgolia  = "algolia"  // search_by_date, one request per page of stories
+)
+
+const (
+	hnAlgoliaBase     = "https://hn.algolia.com/api/v1"
+	hnAlgoliaPageSize = 1000 // Algolia's maximum hitsPerPage
+	hnAlgoliaMaxPages = 3
+)
+
+// hnAlgoliaBaseURL allows tests to override the Algolia endpoint.
+var hnAlgoliaBaseURL = hnAlgoliaBase
+
+// SetAPI selects the API stories are fetched from: HNAPIFirebase (the
+// default) or HNAPIAlgolia. Algolia filters by points and time server-side,
+// so a pull takes one or two requests instead of one per top story, and it
+// finds every story posted since the last pull rather than only those still
+// on the front page.
+func (h *HNSource) SetAPI(api string) error {
+	switch api {
+	case "", HNAPIFirebase:
+		h.api = HNAPIFirebase
+	case HNAPIAlgolia:
+		h.api = HNAPIAlgolia
+	default:
+		return fmt.Errorf("hn: unknown api %q (want %s or %s)", api, HNAPIFirebase, HNAPIAlgolia)
+	}
+	return nil
+}
+
+// hnAlgoliaResult is one page of an Algolia search_by_date response.
+type hnAlgoliaResult struct {
+	Hits []struct {
+		ObjectID  string `json:"objectID"`
+		Title     string `json:"title"`
+		URL       string `json:"url"`
+		Points    int    `json:"points"`
+		CreatedAt int64  `json:"created_at_i"`
+		Author    string `json:"author"`
+	} `json:"hits"`
+	NbPages int `json:"nbPages"`
+}
+
+// fetchAlgolia pages through stories with at least minPoints posted after
+// since, newest first, up to hnAlgoliaMaxPages pages.
+func (h *HNSource) fetchAlgolia(ctx context.Context, since time.Time) ([]Post, error) {
+	var posts []Post
+	for page := 0; page < hnAlgoliaMaxPages; page++ {
+		var res *hnAlgoliaResult
+		err := h.policy.retry(hnSleepFunc, func() error {
+			var err error
+			res, err = h.searchByDate(ctx, since, page)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("hn: search stories: %w", err)
+		}
+
+		for _, hit := range res.Hits {
+			postedAt := time.Unix(hit.CreatedAt, 0)
+			// The API filters both already; guard against stale indexes.
+			if hit.Points < h.minPoints || postedAt.Before(since) {
+				continue
+			}
+			posts = append(posts, Post{
+				Source:     hnSourceName,
+				Channel:    hnChannelName,
+				ExternalID: hit.ObjectID,
+				Text:       hit.Title,
+				URL:        hit.URL,
+				Author:     hit.Author,
+				PostedAt:   postedAt,
+			})
+		}
+		if page+1 >= res.NbPages {
+			break
+		}
+	}
+	return posts, nil
+}
+
+func (h *HNSource) searchByDate(ctx context.Context, since time.Time, page int) (*hnAlgoliaResult, error) {
+	filters := "points>=" + strconv.Itoa(h.minPoints)
+	if !since.IsZero() {
+		filters += ",created_at_i>" + strconv.FormatInt(since.Unix(), 10)
+	}
+	q := url.Values{
+		"tags":           {"story"},
+		"numericFilters": {filters},
+		"hitsPerPage":    {strconv.Itoa(hnAlgoliaPageSize)},
+		"page":           {strconv.Itoa(page)},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, hnAlgoliaBaseURL+"/search_by_date?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("search_by_date: HTTP %d", resp.StatusCode)
+	}
+
+	var res hnAlgoliaResult
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("search_by_date: %w", err)
+	}
+	return &res, nil
+}
@@ -0,0 +1,90 @@
+package source
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestHNFetch_Algolia(t *testing.T) {
+	now := time.Now()
+	since := now.Add(-24 * time.Hour)
+	recent := now.Add(-time.Hour).Unix()
+
+	var queries []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search_by_date" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		q := r.URL.Query()
+		queries = append(queries, q.Get("numericFilters"))
+		if q.Get("tags") != "story" {
+			t.Errorf("tags = %q, want story", q.Get("tags"))
+		}
+
+		page, _ := strconv.Atoi(q.Get("page"))
+		hits := []map[string]any{
+			{"objectID": "1", "title": "Denmark ditching Microsoft", "url": "https://example.com/1", "points": 769, "created_at_i": recent, "author": "pg"},
+			{"objectID": "2", "title": "Stale low score hit", "url": "https://example.com/2", "points": 5, "created_at_i": recent},
+		}
+		if page == 1 {
+			hits = []map[string]any{
+				{"objectID": "3", "title": "Ask HN: anything", "points": 150, "created_at_i": recent},
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"hits": hits, "nbPages": 2, "page": page})
+	}))
+	defer ts.Close()
+
+	oldBase := hnAlgoliaBaseURL
+	hnAlgoliaBaseURL = ts.URL
+	t.Cleanup(func() { hnAlgoliaBaseURL = oldBase })
+
+	h, err := NewHN(100)
+	if err != nil {
+		t.Fatalf("NewHN: %v", err)
+	}
+	if err := h.SetAPI(HNAPIAlgolia); err != nil {
+		t.Fatalf("SetAPI: %v", err)
+	}
+
+	posts, err := h.Fetch(since)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	if len(queries) != 2 {
+		t.Fatalf("requests = %d, want 2 (one per page)", len(queries))
+	}
+	wantFilters := "points>=100,created_at_i>" + strconv.FormatInt(since.Unix(), 10)
+	if queries[0] != wantFilters {
+		t.Errorf("numericFilters = %q, want %q", queries[0], wantFilters)
+	}
+
+	if len(posts) != 2 {
+		t.Fatalf("got %d posts, want 2: %+v", len(posts), posts)
+	}
+	p := posts[0]
+	if p.Source != "hn" || p.Channel != "Hacker News" || p.ExternalID != "1" ||
+		p.Text != "Denmark ditching Microsoft" || p.URL != "https://example.com/1" || p.Author != "pg" || p.PostedAt.Unix() != recent {
+		t.Errorf("post = %+v", p)
+	}
+	if posts[1].ExternalID != "3" || posts[1].URL != "" {
+		t.Errorf("second post = %+v, want Ask HN #3 without url", posts[1])
+	}
+}
+
+func TestHNSetAPI_Unknown(t *testing.T) {
+	h, err := NewHN(100)
+	if err != nil {
+		t.Fatalf("NewHN: %v", err)
+	}
+	if err := h.SetAPI("bigquery"); err == nil {
+		t.Error("expected error for unknown api")
+	}
+}
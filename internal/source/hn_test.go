@@ -5,9 +5,14 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/ppiankov/noisepan/internal/cache"
 )
 
 func TestNewHN(t *testing.T) {
@@ -49,7 +54,7 @@ func TestHNFetch(t *testing.T) {
 	oldUnix := now.Add(-48 * time.Hour).Unix()
 
 	items := map[string]hnItem{
-		"1": {ID: 1, Type: "story", Title: "Denmark ditching Microsoft", URL: "https://example.com/1", Score: 769, Time: recentUnix},
+		"1": {ID: 1, Type: "story", Title: "Denmark ditching Microsoft", URL: "https://example.com/1", Score: 769, Time: recentUnix, By: "pg"},
 		"2": {ID: 2, Type: "story", Title: "Low score post", URL: "https://example.com/2", Score: 5, Time: recentUnix},
 		"3": {ID: 3, Type: "story", Title: "Old post", URL: "https://example.com/3", Score: 500, Time: oldUnix},
 		"4": {ID: 4, Type: "job", Title: "Hiring at BigCo", URL: "https://example.com/4", Score: 200, Time: recentUnix},
@@ -101,6 +106,9 @@ func TestHNFetch(t *testing.T) {
 	titles := make(map[string]bool)
 	for _, p := range posts {
 		titles[p.Text] = true
+		if p.ExternalID == "1" && p.Author != "pg" {
+			t.Errorf("author = %q, want pg", p.Author)
+		}
 		if p.Source != "hn" {
 			t.Errorf("source = %q, want hn", p.Source)
 		}
@@ -144,3 +152,48 @@ func TestHNFetch_Empty(t *testing.T) {
 		t.Errorf("got %d posts, want 0", len(posts))
 	}
 }
+
+func TestHNFetch_Cache(t *testing.T) {
+	now := time.Now()
+	var itemRequests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/topstories.json" {
+			_ = json.NewEncoder(w).Encode([]int{1, 2})
+			return
+		}
+		itemRequests.Add(1)
+		id, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/item/"), ".json"))
+		_ = json.NewEncoder(w).Encode(hnItem{ID: id, Type: "story", Title: "Story " + strconv.Itoa(id), Score: 300, Time: now.Add(-time.Hour).Unix()})
+	}))
+	defer ts.Close()
+
+	oldBase := hnAPIBaseURL
+	hnAPIBaseURL = ts.URL
+	t.Cleanup(func() { hnAPIBaseURL = oldBase })
+
+	c, err := cache.Open(filepath.Join(t.TempDir(), "cache.db"))
+	if err != nil {
+		t.Fatalf("open cache: %v", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	h, err := NewHN(100)
+	if err != nil {
+		t.Fatalf("NewHN: %v", err)
+	}
+	h.SetCache(c)
+
+	for run := 1; run <= 2; run++ {
+		posts, err := h.Fetch(now.Add(-24 * time.Hour))
+		if err != nil {
+			t.Fatalf("run %d: Fetch: %v", run, err)
+		}
+		if len(posts) != 2 {
+			t.Fatalf("run %d: got %d posts, want 2", run, len(posts))
+		}
+	}
+	if n := itemRequests.Load(); n != 2 {
+		t.Errorf("item requests = %d, want 2 (second run served from cache)", n)
+	}
+}
@@ -0,0 +1,29 @@
+package source
+
+import "time"
+
+// FetchPolicy tunes how a source talks to the network. Sources start with
+// their own defaults; use Policy and SetPolicy to adjust individual fields.
+type FetchPolicy struct {
+	Timeout    time.Duration // per-request (or per-script) timeout
+	MaxRetries int           // retries after the first attempt on transient errors
+	Backoff    time.Duration // initial retry delay, doubled on every retry
+	Delay      time.Duration // pause between consecutive requests to the same host
+	Workers    int           // parallel fetchers, where the source supports it
+}
+
+// retry calls fn up to 1+MaxRetries times while it returns a retryable error,
+// sleeping Backoff, 2*Backoff, 4*Backoff, ... between attempts.
+func (p FetchPolicy) retry(sleep func(time.Duration), fn func() error) error {
+	var err error
+	for attempt := 0; attempt <= p.MaxRetries; attempt++ {
+		err = fn()
+		if err == nil || !isRetryableError(err) {
+			return err
+		}
+		if attempt < p.MaxRetries {
+			sleep(p.Backoff << uint(attempt))
+		}
+	}
+	return err
+}
@@ -0,0 +1,63 @@
+package source
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFetchPolicyRetry(t *testing.T) {
+	var sleeps []time.Duration
+	sleep := func(d time.Duration) { sleeps = append(sleeps, d) }
+
+	t.Run("transient then success", func(t *testing.T) {
+		sleeps = nil
+		calls := 0
+		p := FetchPolicy{MaxRetries: 3, Backoff: time.Second}
+		err := p.retry(sleep, func() error {
+			calls++
+			if calls < 3 {
+				return errors.New("HTTP 503")
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("retry: %v", err)
+		}
+		if calls != 3 {
+			t.Errorf("calls = %d, want 3", calls)
+		}
+		if len(sleeps) != 2 || sleeps[0] != time.Second || sleeps[1] != 2*time.Second {
+			t.Errorf("sleeps = %v, want [1s 2s]", sleeps)
+		}
+	})
+
+	t.Run("permanent error stops immediately", func(t *testing.T) {
+		sleeps = nil
+		calls := 0
+		p := FetchPolicy{MaxRetries: 3, Backoff: time.Second}
+		err := p.retry(sleep, func() error {
+			calls++
+			return errors.New("HTTP 404")
+		})
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		if calls != 1 || len(sleeps) != 0 {
+			t.Errorf("calls = %d, sleeps = %v, want 1 call and no sleeps", calls, sleeps)
+		}
+	})
+
+	t.Run("zero retries", func(t *testing.T) {
+		sleeps = nil
+		calls := 0
+		p := FetchPolicy{}
+		_ = p.retry(sleep, func() error {
+			calls++
+			return errors.New("timeout")
+		})
+		if calls != 1 || len(sleeps) != 0 {
+			t.Errorf("calls = %d, sleeps = %v, want 1 call and no sleeps", calls, sleeps)
+		}
+	})
+}
@@ -5,9 +5,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/ppiankov/noisepan/internal/cache"
 )
 
 const (
@@ -16,13 +20,35 @@ const (
 	redditTimeout    = 30 * time.Second
 	redditUserAgent  = "noisepan/1.0"
 	redditRateLimit  = 1 * time.Second
+	redditBackoff    = 1 * time.Second
+	redditLimit      = 100
+
+	// Polite mode (SetPolite) asks for smaller listings less often, reuses
+	// a listing for redditListingTTL, and leaves a subreddit that answered
+	// 429 alone for as long as its Retry-After asked, at least
+	// redditMinBackoff and at most redditMaxBackoff.
+	redditPoliteLimit      = 25
+	redditPoliteDelay      = 5 * time.Second
+	redditListingNamespace = "reddit-listing"
+	redditListingTTL       = 10 * time.Minute
+	redditLimitedNamespace = "reddit-rate-limited"
+	redditMinBackoff       = 30 * time.Minute
+	redditMaxBackoff       = 6 * time.Hour
 )
 
+// redditSleepFunc is used for rate limiting and retry delays.
+// It defaults to time.Sleep but can be overridden in tests.
+var redditSleepFunc = time.Sleep
+
 // RedditSource fetches posts from public subreddits via Reddit's JSON API.
 type RedditSource struct {
 	subreddits []string
 	client     *http.Client
 	baseURL    string
+	policy     FetchPolicy
+	statuses   []FeedStatus
+	polite     bool
+	cache      *cache.Cache
 }
 
 // NewReddit creates a Reddit source. At least one subreddit is required.
@@ -34,59 +60,188 @@ func NewReddit(subreddits []string) (*RedditSource, error) {
 		subreddits: subreddits,
 		client:     &http.Client{Timeout: redditTimeout},
 		baseURL:    redditBaseURL,
+		policy: FetchPolicy{
+			Timeout: redditTimeout,
+			Backoff: redditBackoff,
+			Delay:   redditRateLimit,
+		},
 	}, nil
 }
 
+// Policy returns the current fetch policy.
+func (rs *RedditSource) Policy() FetchPolicy {
+	return rs.policy
+}
+
+// SetPolicy replaces the fetch policy.
+func (rs *RedditSource) SetPolicy(p FetchPolicy) {
+	rs.policy = p
+	rs.client.Timeout = p.Timeout
+}
+
+// SetTransport replaces the HTTP transport used for subreddit requests.
+func (rs *RedditSource) SetTransport(rt http.RoundTripper) {
+	rs.client.Transport = rt
+}
+
+// SetPolite turns polite mode on or off, for anonymous setups that keep
+// getting rate limited. Listings and rate-limited subreddits are remembered
+// across runs in the cache set with SetCache.
+func (rs *RedditSource) SetPolite(polite bool) {
+	rs.polite = polite
+}
+
+// SetCache sets where polite mode keeps listings and rate limits. A nil
+// cache remembers nothing between runs.
+func (rs *RedditSource) SetCache(c *cache.Cache) {
+	rs.cache = c
+}
+
 func (rs *RedditSource) Name() string {
 	return redditSourceName
 }
 
+// FeedStatuses returns the per-subreddit outcome of the last Fetch.
+func (rs *RedditSource) FeedStatuses() []FeedStatus {
+	return rs.statuses
+}
+
 func (rs *RedditSource) Fetch(since time.Time) ([]Post, error) {
 	var posts []Post
+	rs.statuses = nil
 
-	for i, sub := range rs.subreddits {
-		if i > 0 {
-			time.Sleep(redditRateLimit)
-		}
-
-		items, err := rs.fetchSubreddit(sub, since)
-		if err != nil {
-			fmt.Printf("  reddit: r/%s: %v\n", sub, err)
+	requested := false
+	for _, sub := range rs.subreddits {
+		listing, ok := rs.cachedListing(sub)
+		if !ok && rs.rateLimited(sub) {
+			slog.Info("skipping rate-limited subreddit", "source", redditSourceName, "subreddit", sub)
 			continue
 		}
-		posts = append(posts, items...)
+		if !ok {
+			if requested {
+				redditSleepFunc(rs.delay())
+			}
+			requested = true
+			err := rs.policy.retry(redditSleepFunc, func() error {
+				var err error
+				listing, err = rs.fetchListing(sub)
+				return err
+			})
+			rs.statuses = append(rs.statuses, FeedStatus{Feed: sub, Err: err})
+			if err != nil {
+				slog.Warn("fetch failed", "source", redditSourceName, "subreddit", sub, "err", err)
+				rs.noteRateLimit(sub, err)
+				continue
+			}
+			rs.cacheListing(sub, listing)
+		}
+		posts = append(posts, postsFromListing(listing, sub, since)...)
 	}
 
 	return posts, nil
 }
 
-func (rs *RedditSource) fetchSubreddit(subreddit string, since time.Time) ([]Post, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), redditTimeout)
+// delay is the pause between subreddit requests.
+func (rs *RedditSource) delay() time.Duration {
+	if rs.polite {
+		return max(rs.policy.Delay, redditPoliteDelay)
+	}
+	return rs.policy.Delay
+}
+
+// cachedListing returns the listing polite mode fetched for subreddit in
+// the last redditListingTTL. Cache errors only cost the lookup.
+func (rs *RedditSource) cachedListing(subreddit string) (redditListing, bool) {
+	var listing redditListing
+	if !rs.polite {
+		return listing, false
+	}
+	ok, err := rs.cache.GetJSON(context.Background(), redditListingNamespace, subreddit, &listing)
+	if err != nil {
+		slog.Debug("cache lookup failed", "source", redditSourceName, "err", err)
+	}
+	return listing, ok && err == nil
+}
+
+func (rs *RedditSource) cacheListing(subreddit string, listing redditListing) {
+	if !rs.polite {
+		return
+	}
+	if err := rs.cache.SetJSON(context.Background(), redditListingNamespace, subreddit, listing, redditListingTTL); err != nil {
+		slog.Debug("cache store failed", "source", redditSourceName, "err", err)
+	}
+}
+
+// rateLimited reports whether subreddit answered 429 recently enough that
+// polite mode leaves it alone.
+func (rs *RedditSource) rateLimited(subreddit string) bool {
+	if !rs.polite {
+		return false
+	}
+	_, ok, err := rs.cache.Get(context.Background(), redditLimitedNamespace, subreddit)
+	if err != nil {
+		slog.Debug("cache lookup failed", "source", redditSourceName, "err", err)
+	}
+	return ok
+}
+
+// noteRateLimit remembers a 429 from subreddit in polite mode.
+func (rs *RedditSource) noteRateLimit(subreddit string, err error) {
+	var limited *redditRateLimitError
+	if !rs.polite || !errors.As(err, &limited) {
+		return
+	}
+	backoff := min(max(limited.retryAfter, redditMinBackoff), redditMaxBackoff)
+	until := time.Now().Add(backoff).UTC().Format(time.RFC3339)
+	if err := rs.cache.Set(context.Background(), redditLimitedNamespace, subreddit, []byte(until), backoff); err != nil {
+		slog.Debug("cache store failed", "source", redditSourceName, "err", err)
+	}
+}
+
+// redditRateLimitError is a 429 response, with the wait it asked for.
+type redditRateLimitError struct {
+	subreddit  string
+	retryAfter time.Duration
+}
+
+func (e *redditRateLimitError) Error() string {
+	return fmt.Sprintf("r/%s: status %d", e.subreddit, http.StatusTooManyRequests)
+}
+
+func (rs *RedditSource) fetchListing(subreddit string) (redditListing, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), rs.policy.Timeout)
 	defer cancel()
 
-	url := fmt.Sprintf("%s/r/%s/new.json?limit=100", rs.baseURL, subreddit)
+	limit := redditLimit
+	if rs.polite {
+		limit = redditPoliteLimit
+	}
+	url := fmt.Sprintf("%s/r/%s/new.json?limit=%d", rs.baseURL, subreddit, limit)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+		return redditListing{}, fmt.Errorf("create request: %w", err)
 	}
 	req.Header.Set("User-Agent", redditUserAgent)
 
 	resp, err := rs.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("fetch r/%s: %w", subreddit, err)
+		return redditListing{}, fmt.Errorf("fetch r/%s: %w", subreddit, err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		secs, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+		return redditListing{}, &redditRateLimitError{subreddit: subreddit, retryAfter: time.Duration(secs) * time.Second}
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("r/%s: status %d", subreddit, resp.StatusCode)
+		return redditListing{}, fmt.Errorf("r/%s: status %d", subreddit, resp.StatusCode)
 	}
 
 	var listing redditListing
 	if err := json.NewDecoder(resp.Body).Decode(&listing); err != nil {
-		return nil, fmt.Errorf("decode r/%s: %w", subreddit, err)
+		return redditListing{}, fmt.Errorf("decode r/%s: %w", subreddit, err)
 	}
-
-	return postsFromListing(listing, subreddit, since), nil
+	return listing, nil
 }
 
 func postsFromListing(listing redditListing, subreddit string, since time.Time) []Post {
@@ -109,6 +264,7 @@ func postsFromListing(listing redditListing, subreddit string, since time.Time)
 			ExternalID: p.ID,
 			Text:       text,
 			URL:        redditBaseURL + p.Permalink,
+			Author:     redditAuthor(p.Author),
 			PostedAt:   postedAt,
 		})
 	}
@@ -131,5 +287,15 @@ type redditPost struct {
 	Selftext   string  `json:"selftext"`
 	URL        string  `json:"url"`
 	Permalink  string  `json:"permalink"`
+	Author     string  `json:"author"`
 	CreatedUTC float64 `json:"created_utc"`
 }
+
+// redditAuthor returns a post's author, or "" for deleted accounts, which
+// Reddit reports as "[deleted]".
+func redditAuthor(name string) string {
+	if name == "[deleted]" {
+		return ""
+	}
+	return name
+}
@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/ppiankov/noisepan/internal/cache"
 )
 
 func makeListing(posts ...redditPost) redditListing {
@@ -100,6 +103,7 @@ func TestReddit_SuccessfulFetch(t *testing.T) {
 				Title:      "CVE Alert",
 				Selftext:   "Critical vulnerability found",
 				Permalink:  "/r/devops/comments/abc123/cve_alert/",
+				Author:     "secresearcher",
 				CreatedUTC: float64(now.Unix()),
 			},
 			redditPost{
@@ -108,6 +112,7 @@ func TestReddit_SuccessfulFetch(t *testing.T) {
 				Selftext:   "",
 				URL:        "https://example.com",
 				Permalink:  "/r/devops/comments/def456/link_post/",
+				Author:     "[deleted]",
 				CreatedUTC: float64(now.Unix()),
 			},
 		)
@@ -139,6 +144,9 @@ func TestReddit_SuccessfulFetch(t *testing.T) {
 	if !strings.Contains(p.URL, "/r/devops/comments/abc123") {
 		t.Errorf("url = %q", p.URL)
 	}
+	if p.Author != "secresearcher" || posts[1].Author != "" {
+		t.Errorf("authors = %q, %q; want secresearcher and none for [deleted]", p.Author, posts[1].Author)
+	}
 
 	// Link post: no selftext, text should be title only
 	if posts[1].Text != "Link Post" {
@@ -238,3 +246,88 @@ func TestPostsFromListing(t *testing.T) {
 		t.Errorf("url = %q", p.URL)
 	}
 }
+
+func TestReddit_RetriesTransientErrors(t *testing.T) {
+	oldSleep := redditSleepFunc
+	redditSleepFunc = func(_ time.Duration) {}
+	t.Cleanup(func() { redditSleepFunc = oldSleep })
+
+	calls := 0
+	rs := redditWithTransport([]string{"devops"}, func(_ *http.Request) (*http.Response, error) {
+		calls++
+		if calls == 1 {
+			return response(http.StatusBadGateway, ""), nil
+		}
+		return response(http.StatusOK, mustJSON(t, makeListing(redditPost{
+			ID: "abc", Title: "Recovered", Permalink: "/r/devops/comments/abc/",
+			CreatedUTC: float64(time.Now().Unix()),
+		}))), nil
+	})
+	p := rs.Policy()
+	p.MaxRetries = 2
+	rs.SetPolicy(p)
+
+	posts, err := rs.Fetch(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+	if len(posts) != 1 {
+		t.Fatalf("got %d posts, want 1", len(posts))
+	}
+}
+
+func TestReddit_Polite(t *testing.T) {
+	var slept []time.Duration
+	oldSleep := redditSleepFunc
+	redditSleepFunc = func(d time.Duration) { slept = append(slept, d) }
+	t.Cleanup(func() { redditSleepFunc = oldSleep })
+
+	c, err := cache.Open(filepath.Join(t.TempDir(), "cache.db"))
+	if err != nil {
+		t.Fatalf("open cache: %v", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	requests := map[string]int{}
+	rs := redditWithTransport([]string{"devops", "busy"}, func(r *http.Request) (*http.Response, error) {
+		sub := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/r/"), "/new.json")
+		requests[sub]++
+		if got := r.URL.Query().Get("limit"); got != "25" {
+			t.Errorf("limit query = %q, want 25", got)
+		}
+		if sub == "busy" {
+			resp := response(http.StatusTooManyRequests, "")
+			resp.Header.Set("Retry-After", "60")
+			return resp, nil
+		}
+		return response(http.StatusOK, mustJSON(t, makeListing(redditPost{
+			ID: "abc", Title: "Release", Permalink: "/r/devops/comments/abc/",
+			CreatedUTC: float64(time.Now().Unix()),
+		}))), nil
+	})
+	rs.SetPolite(true)
+	rs.SetCache(c)
+
+	// The second run reuses the listing and leaves the 429 subreddit alone.
+	for run := 1; run <= 2; run++ {
+		posts, err := rs.Fetch(time.Now().Add(-time.Hour))
+		if err != nil {
+			t.Fatalf("run %d: fetch: %v", run, err)
+		}
+		if len(posts) != 1 {
+			t.Fatalf("run %d: got %d posts, want 1", run, len(posts))
+		}
+	}
+	if requests["devops"] != 1 || requests["busy"] != 1 {
+		t.Errorf("requests = %v, want one per subreddit", requests)
+	}
+	if len(slept) != 1 || slept[0] != redditPoliteDelay {
+		t.Errorf("slept %v, want one polite delay", slept)
+	}
+	if statuses := rs.FeedStatuses(); len(statuses) != 0 {
+		t.Errorf("second run statuses = %+v, want none", statuses)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"html"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -13,6 +14,8 @@ import (
 	"time"
 
 	"github.com/mmcdole/gofeed"
+
+	"github.com/ppiankov/noisepan/internal/cache"
 )
 
 const (
@@ -20,8 +23,15 @@ const (
 	rssFetchTimeout = 30 * time.Second
 	rssUserAgent    = "Mozilla/5.0 (compatible; noisepan/1.0; +https://github.com/ppiankov/noisepan)"
 	rssMaxWorkers   = 10
-	rssMaxRetries   = 3
+	rssMaxRetries   = 2 // 3 attempts total
+	rssBackoff      = 1 * time.Second
 	rssDomainDelay  = 3 * time.Second
+
+	// rssValidatorNamespace and rssValidatorTTL govern the ETag and
+	// Last-Modified values kept per feed for conditional requests. A feed
+	// left unchanged for longer than rssValidatorTTL is fetched in full.
+	rssValidatorNamespace = "rss-validators"
+	rssValidatorTTL       = 30 * 24 * time.Hour
 )
 
 var (
@@ -31,7 +41,18 @@ var (
 
 // RSSSource fetches posts from RSS/Atom feeds.
 type RSSSource struct {
-	feeds []string
+	feeds     []string
+	transport http.RoundTripper
+	policy    FetchPolicy
+	statuses  []FeedStatus
+	location  *time.Location
+	cache     *cache.Cache
+}
+
+// feedValidators are the cache validators a feed last answered with.
+type feedValidators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
 }
 
 // NewRSS creates an RSS/Atom source. At least one feed URL is required.
@@ -39,7 +60,45 @@ func NewRSS(feeds []string) (*RSSSource, error) {
 	if len(feeds) == 0 {
 		return nil, errors.New("rss: at least one feed URL is required")
 	}
-	return &RSSSource{feeds: feeds}, nil
+	return &RSSSource{
+		feeds:     feeds,
+		transport: http.DefaultTransport,
+		policy: FetchPolicy{
+			Timeout:    rssFetchTimeout,
+			MaxRetries: rssMaxRetries,
+			Backoff:    rssBackoff,
+			Delay:      rssDomainDelay,
+			Workers:    rssMaxWorkers,
+		},
+	}, nil
+}
+
+// Policy returns the current fetch policy.
+func (rs *RSSSource) Policy() FetchPolicy {
+	return rs.policy
+}
+
+// SetPolicy replaces the fetch policy.
+func (rs *RSSSource) SetPolicy(p FetchPolicy) {
+	rs.policy = p
+}
+
+// SetLocation sets the zone for item dates that carry no UTC offset.
+func (rs *RSSSource) SetLocation(loc *time.Location) {
+	rs.location = loc
+}
+
+// SetTransport replaces the HTTP transport used for feed requests
+// (e.g. one configured with a proxy or custom CA bundle).
+func (rs *RSSSource) SetTransport(rt http.RoundTripper) {
+	rs.transport = rt
+}
+
+// SetCache keeps each feed's ETag and Last-Modified between runs so an
+// unchanged feed answers 304 Not Modified instead of the whole document.
+// A nil cache disables conditional requests.
+func (rs *RSSSource) SetCache(c *cache.Cache) {
+	rs.cache = c
 }
 
 func (rs *RSSSource) Name() string {
@@ -63,7 +122,7 @@ func (rs *RSSSource) Fetch(since time.Time) ([]Post, error) {
 	results := make(chan result, len(rs.feeds))
 	domainJobs := make(chan []string, len(domainFeeds))
 
-	workers := rssMaxWorkers
+	workers := max(rs.policy.Workers, 1)
 	if len(domainFeeds) < workers {
 		workers = len(domainFeeds)
 	}
@@ -76,9 +135,9 @@ func (rs *RSSSource) Fetch(since time.Time) ([]Post, error) {
 			for feeds := range domainJobs {
 				for i, feedURL := range feeds {
 					if i > 0 {
-						rssSleepFunc(rssDomainDelay)
+						rssSleepFunc(rs.policy.Delay)
 					}
-					items, err := fetchWithRetry(feedURL, since)
+					items, err := rs.fetchWithRetry(feedURL, since)
 					results <- result{posts: items, err: err, url: feedURL}
 				}
 			}
@@ -96,9 +155,11 @@ func (rs *RSSSource) Fetch(since time.Time) ([]Post, error) {
 	}()
 
 	var posts []Post
+	rs.statuses = nil
 	for r := range results {
+		rs.statuses = append(rs.statuses, FeedStatus{Feed: r.url, Err: r.err})
 		if r.err != nil {
-			fmt.Printf("  rss: %s: %v\n", r.url, r.err)
+			slog.Warn("fetch failed", "source", rssSourceName, "url", r.url, "err", r.err)
 			continue
 		}
 		posts = append(posts, r.posts...)
@@ -107,6 +168,11 @@ func (rs *RSSSource) Fetch(since time.Time) ([]Post, error) {
 	return posts, nil
 }
 
+// FeedStatuses returns the per-feed outcome of the last Fetch.
+func (rs *RSSSource) FeedStatuses() []FeedStatus {
+	return rs.statuses
+}
+
 // feedDomain extracts the host from a feed URL for rate limiting grouping.
 func feedDomain(feedURL string) string {
 	u, err := url.Parse(feedURL)
@@ -130,23 +196,17 @@ func (t *rssTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 // It defaults to time.Sleep but can be overridden in tests.
 var rssSleepFunc = time.Sleep
 
-func fetchWithRetry(feedURL string, since time.Time) ([]Post, error) {
-	var lastErr error
-	for attempt := range rssMaxRetries {
-		posts, err := fetchFeed(feedURL, since)
-		if err == nil {
-			return posts, nil
-		}
-		if !isRetryableError(err) {
-			return nil, err
-		}
-		lastErr = err
-		if attempt < rssMaxRetries-1 {
-			backoff := time.Duration(1<<uint(attempt)) * time.Second // 1s, 2s, 4s
-			rssSleepFunc(backoff)
-		}
+func (rs *RSSSource) fetchWithRetry(feedURL string, since time.Time) ([]Post, error) {
+	var posts []Post
+	err := rs.policy.retry(rssSleepFunc, func() error {
+		var err error
+		posts, err = rs.fetchFeed(feedURL, since)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
-	return nil, lastErr
+	return posts, nil
 }
 
 func isRetryableError(err error) bool {
@@ -170,27 +230,76 @@ func isRetryableError(err error) bool {
 	return false
 }
 
-func fetchFeed(feedURL string, since time.Time) ([]Post, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), rssFetchTimeout)
+func (rs *RSSSource) fetchFeed(feedURL string, since time.Time) ([]Post, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), rs.policy.Timeout)
 	defer cancel()
 
-	fp := gofeed.NewParser()
-	fp.Client = &http.Client{
-		Timeout:   rssFetchTimeout,
-		Transport: &rssTransport{base: http.DefaultTransport},
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", feedURL, err)
+	}
+	var cached feedValidators
+	if ok, err := rs.cache.GetJSON(ctx, rssValidatorNamespace, feedURL, &cached); err != nil {
+		slog.Debug("cache lookup failed", "source", rssSourceName, "err", err)
+	} else if ok {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	client := &http.Client{
+		Timeout:   rs.policy.Timeout,
+		Transport: &rssTransport{base: rs.transport},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", feedURL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotModified {
+		slog.Debug("feed not modified", "source", rssSourceName, "url", feedURL)
+		return nil, nil
 	}
-	feed, err := fp.ParseURLWithContext(feedURL, ctx)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("fetch %s: %w", feedURL, gofeed.HTTPError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+		})
+	}
+
+	feed, err := gofeed.NewParser().Parse(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("fetch %s: %w", feedURL, err)
 	}
 
-	return postsFromFeed(feed, feedURL, since), nil
+	rs.storeValidators(ctx, feedURL, feedValidators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	})
+
+	return postsFromFeed(feed, feedURL, since, rs.location), nil
 }
 
-func postsFromFeed(feed *gofeed.Feed, feedURL string, since time.Time) []Post {
+// storeValidators remembers v for feedURL's next request, or forgets the
+// previous ones when the feed no longer sends any.
+func (rs *RSSSource) storeValidators(ctx context.Context, feedURL string, v feedValidators) {
+	ttl := rssValidatorTTL
+	if v == (feedValidators{}) {
+		ttl = 0
+	}
+	if err := rs.cache.SetJSON(ctx, rssValidatorNamespace, feedURL, v, ttl); err != nil {
+		slog.Debug("cache store failed", "source", rssSourceName, "err", err)
+	}
+}
+
+func postsFromFeed(feed *gofeed.Feed, feedURL string, since time.Time, loc *time.Location) []Post {
 	var posts []Post
 	for _, item := range feed.Items {
-		postedAt := itemPublishedTime(item)
+		postedAt := itemPublishedTime(item, loc)
 		if postedAt.IsZero() || postedAt.Before(since) {
 			continue
 		}
@@ -199,6 +308,7 @@ func postsFromFeed(feed *gofeed.Feed, feedURL string, since time.Time) []Post {
 			Source:     rssSourceName,
 			Channel:    feedLabel(feed, feedURL),
 			ExternalID: itemID(item),
+			Author:     itemAuthor(item),
 			Text:       itemText(item),
 			URL:        item.Link,
 			PostedAt:   postedAt,
@@ -207,16 +317,28 @@ func postsFromFeed(feed *gofeed.Feed, feedURL string, since time.Time) []Post {
 	return posts
 }
 
-func itemPublishedTime(item *gofeed.Item) time.Time {
+// itemPublishedTime returns the item's publish (or update) time. gofeed reads
+// dates without an offset as UTC; when loc is set they are re-read in loc.
+func itemPublishedTime(item *gofeed.Item, loc *time.Location) time.Time {
 	if item.PublishedParsed != nil {
-		return *item.PublishedParsed
+		return inLocation(*item.PublishedParsed, item.Published, loc)
 	}
 	if item.UpdatedParsed != nil {
-		return *item.UpdatedParsed
+		return inLocation(*item.UpdatedParsed, item.Updated, loc)
 	}
 	return time.Time{}
 }
 
+func inLocation(parsed time.Time, raw string, loc *time.Location) time.Time {
+	if loc == nil {
+		return parsed
+	}
+	if t, ok := parseNaive(raw, loc); ok {
+		return t
+	}
+	return parsed
+}
+
 func feedLabel(feed *gofeed.Feed, feedURL string) string {
 	if feed.Title != "" {
 		return feed.Title
@@ -231,6 +353,27 @@ func itemID(item *gofeed.Item) string {
 	return item.Link
 }
 
+// itemAuthor returns the item's first named author, falling back to the
+// legacy single author and then the Dublin Core creator.
+func itemAuthor(item *gofeed.Item) string {
+	for _, p := range item.Authors {
+		if p != nil && strings.TrimSpace(p.Name) != "" {
+			return strings.TrimSpace(p.Name)
+		}
+	}
+	if item.Author != nil && strings.TrimSpace(item.Author.Name) != "" {
+		return strings.TrimSpace(item.Author.Name)
+	}
+	if dc := item.DublinCoreExt; dc != nil {
+		for _, c := range dc.Creator {
+			if strings.TrimSpace(c) != "" {
+				return strings.TrimSpace(c)
+			}
+		}
+	}
+	return ""
+}
+
 func itemText(item *gofeed.Item) string {
 	raw := item.Content
 	if raw == "" {
@@ -4,12 +4,17 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
+
+	"github.com/ppiankov/noisepan/internal/cache"
 )
 
 func TestNewRSS_EmptyFeeds(t *testing.T) {
@@ -72,31 +77,50 @@ func TestItemPublishedTime(t *testing.T) {
 
 	t.Run("published", func(t *testing.T) {
 		item := &gofeed.Item{PublishedParsed: &now}
-		if got := itemPublishedTime(item); !got.Equal(now) {
+		if got := itemPublishedTime(item, nil); !got.Equal(now) {
 			t.Errorf("got %v, want %v", got, now)
 		}
 	})
 
 	t.Run("updated fallback", func(t *testing.T) {
 		item := &gofeed.Item{UpdatedParsed: &earlier}
-		if got := itemPublishedTime(item); !got.Equal(earlier) {
+		if got := itemPublishedTime(item, nil); !got.Equal(earlier) {
 			t.Errorf("got %v, want %v", got, earlier)
 		}
 	})
 
 	t.Run("published preferred", func(t *testing.T) {
 		item := &gofeed.Item{PublishedParsed: &now, UpdatedParsed: &earlier}
-		if got := itemPublishedTime(item); !got.Equal(now) {
+		if got := itemPublishedTime(item, nil); !got.Equal(now) {
 			t.Errorf("got %v (updated), want %v (published)", got, now)
 		}
 	})
 
 	t.Run("zero", func(t *testing.T) {
 		item := &gofeed.Item{}
-		if got := itemPublishedTime(item); !got.IsZero() {
+		if got := itemPublishedTime(item, nil); !got.IsZero() {
 			t.Errorf("got %v, want zero", got)
 		}
 	})
+
+	msk := time.FixedZone("MSK", 3*60*60)
+
+	t.Run("naive reread in location", func(t *testing.T) {
+		parsed := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+		item := &gofeed.Item{Published: "Sun, 01 Mar 2026 10:00:00", PublishedParsed: &parsed}
+		want := time.Date(2026, 3, 1, 7, 0, 0, 0, time.UTC)
+		if got := itemPublishedTime(item, msk); !got.Equal(want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("offset kept", func(t *testing.T) {
+		parsed := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+		item := &gofeed.Item{Published: "Sun, 01 Mar 2026 10:00:00 GMT", PublishedParsed: &parsed}
+		if got := itemPublishedTime(item, msk); !got.Equal(parsed) {
+			t.Errorf("got %v, want %v", got, parsed)
+		}
+	})
 }
 
 func TestItemID(t *testing.T) {
@@ -122,6 +146,51 @@ func TestItemID(t *testing.T) {
 	})
 }
 
+func TestItemAuthor(t *testing.T) {
+	t.Run("first named author", func(t *testing.T) {
+		item := &gofeed.Item{Authors: []*gofeed.Person{{Email: "ops@example.com"}, {Name: "Jane Doe"}}}
+		if got := itemAuthor(item); got != "Jane Doe" {
+			t.Errorf("got %q, want Jane Doe", got)
+		}
+	})
+
+	t.Run("legacy author fallback", func(t *testing.T) {
+		item := &gofeed.Item{Author: &gofeed.Person{Name: "John Roe"}}
+		if got := itemAuthor(item); got != "John Roe" {
+			t.Errorf("got %q, want John Roe", got)
+		}
+	})
+
+	t.Run("dc:creator fallback", func(t *testing.T) {
+		item := &gofeed.Item{DublinCoreExt: &ext.DublinCoreExtension{Creator: []string{" ", "Ann Poe"}}}
+		if got := itemAuthor(item); got != "Ann Poe" {
+			t.Errorf("got %q, want Ann Poe", got)
+		}
+	})
+
+	t.Run("parsed dc:creator", func(t *testing.T) {
+		feed, err := gofeed.NewParser().ParseString(`<?xml version="1.0"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/">
+  <channel>
+    <title>Test Feed</title>
+    <item><title>Post</title><dc:creator>Ann Poe</dc:creator></item>
+  </channel>
+</rss>`)
+		if err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+		if got := itemAuthor(feed.Items[0]); got != "Ann Poe" {
+			t.Errorf("got %q, want Ann Poe", got)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		if got := itemAuthor(&gofeed.Item{}); got != "" {
+			t.Errorf("got %q, want empty", got)
+		}
+	})
+}
+
 func TestFeedLabel(t *testing.T) {
 	t.Run("title", func(t *testing.T) {
 		feed := &gofeed.Feed{Title: "My Blog"}
@@ -203,6 +272,7 @@ func TestPostsFromFeed(t *testing.T) {
 				Title:           "Recent Post",
 				Description:     "Recent content",
 				Link:            "https://example.com/1",
+				Authors:         []*gofeed.Person{{Name: "Jane Doe"}},
 				PublishedParsed: &recent,
 			},
 			{
@@ -219,7 +289,7 @@ func TestPostsFromFeed(t *testing.T) {
 		},
 	}
 
-	posts := postsFromFeed(feed, "https://example.com/feed.xml", since)
+	posts := postsFromFeed(feed, "https://example.com/feed.xml", since, nil)
 
 	if len(posts) != 1 {
 		t.Fatalf("got %d posts, want 1 (only recent)", len(posts))
@@ -238,11 +308,14 @@ func TestPostsFromFeed(t *testing.T) {
 	if p.URL != "https://example.com/1" {
 		t.Errorf("url = %q", p.URL)
 	}
+	if p.Author != "Jane Doe" {
+		t.Errorf("author = %q, want Jane Doe", p.Author)
+	}
 }
 
 func TestPostsFromFeed_Empty(t *testing.T) {
 	feed := &gofeed.Feed{Title: "Empty Feed"}
-	posts := postsFromFeed(feed, "https://example.com/feed.xml", time.Now())
+	posts := postsFromFeed(feed, "https://example.com/feed.xml", time.Now(), nil)
 	if len(posts) != 0 {
 		t.Errorf("got %d posts, want 0", len(posts))
 	}
@@ -306,7 +379,8 @@ func TestFetchWithRetry_TransientThenSuccess(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	posts, err := fetchWithRetry(ts.URL, time.Now().Add(-time.Hour))
+	rs, _ := NewRSS([]string{ts.URL})
+	posts, err := rs.fetchWithRetry(ts.URL, time.Now().Add(-time.Hour))
 	if err != nil {
 		t.Fatalf("fetchWithRetry: %v", err)
 	}
@@ -328,7 +402,8 @@ func TestFetchWithRetry_PermanentFailure(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	_, err := fetchWithRetry(ts.URL, time.Now().Add(-time.Hour))
+	rs, _ := NewRSS([]string{ts.URL})
+	_, err := rs.fetchWithRetry(ts.URL, time.Now().Add(-time.Hour))
 	if err == nil {
 		t.Fatal("expected error for 404")
 	}
@@ -346,7 +421,8 @@ func TestFetchWithRetry_AllRetriesFail(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	_, err := fetchWithRetry(ts.URL, time.Now().Add(-time.Hour))
+	rs, _ := NewRSS([]string{ts.URL})
+	_, err := rs.fetchWithRetry(ts.URL, time.Now().Add(-time.Hour))
 	if err == nil {
 		t.Fatal("expected error after all retries exhausted")
 	}
@@ -491,3 +567,97 @@ func TestFetch_DomainDelay(t *testing.T) {
 		t.Errorf("domain delays = %d, want 2 (between 3 same-domain feeds)", domainDelays)
 	}
 }
+
+func TestFetch_FeedStatuses(t *testing.T) {
+	oldSleep := rssSleepFunc
+	rssSleepFunc = func(_ time.Duration) {}
+	t.Cleanup(func() { rssSleepFunc = oldSleep })
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			http.Error(w, "gone", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprint(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>OK</title></channel></rss>`)
+	}))
+	defer ts.Close()
+
+	rs, err := NewRSS([]string{ts.URL + "/ok", ts.URL + "/broken"})
+	if err != nil {
+		t.Fatalf("NewRSS: %v", err)
+	}
+	if _, err := rs.Fetch(time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	statuses := rs.FeedStatuses()
+	if len(statuses) != 2 {
+		t.Fatalf("got %d statuses, want 2", len(statuses))
+	}
+	for _, st := range statuses {
+		failed := st.Err != nil
+		if wantFail := strings.HasSuffix(st.Feed, "/broken"); failed != wantFail {
+			t.Errorf("%s: err = %v", st.Feed, st.Err)
+		}
+	}
+}
+
+func TestFetch_ConditionalRequest(t *testing.T) {
+	const etag = `"v1"`
+	var conditional atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			conditional.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprintf(w, `<?xml version="1.0"?>
+<rss version="2.0">
+  <channel>
+    <title>Test Feed</title>
+    <item>
+      <title>Test Item</title>
+      <link>https://example.com/1</link>
+      <guid>1</guid>
+      <pubDate>%s</pubDate>
+    </item>
+  </channel>
+</rss>`, time.Now().Format(time.RFC1123Z))
+	}))
+	defer ts.Close()
+
+	c, err := cache.Open(filepath.Join(t.TempDir(), "cache.db"))
+	if err != nil {
+		t.Fatalf("open cache: %v", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	rs, _ := NewRSS([]string{ts.URL})
+	rs.SetCache(c)
+	since := time.Now().Add(-time.Hour)
+
+	posts, err := rs.Fetch(since)
+	if err != nil {
+		t.Fatalf("first fetch: %v", err)
+	}
+	if len(posts) != 1 {
+		t.Fatalf("first fetch got %d posts, want 1", len(posts))
+	}
+
+	posts, err = rs.Fetch(since)
+	if err != nil {
+		t.Fatalf("second fetch: %v", err)
+	}
+	if len(posts) != 0 {
+		t.Errorf("not-modified fetch got %d posts, want 0", len(posts))
+	}
+	if conditional.Load() != 1 {
+		t.Errorf("conditional requests = %d, want 1", conditional.Load())
+	}
+	if st := rs.FeedStatuses(); len(st) != 1 || st[0].Err != nil {
+		t.Errorf("statuses = %+v, want one without error", st)
+	}
+}
@@ -9,6 +9,8 @@ type Post struct {
 	ExternalID string    // source-specific unique ID
 	Text       string    // full message text
 	URL        string    // link to the original item
+	Author     string    // username or signature; empty when the source has none
+	Language   string    // ISO 639-1 code, detected when stored; empty when unknown
 	PostedAt   time.Time // publication timestamp
 }
 
@@ -20,3 +22,16 @@ type Source interface {
 	// Fetch returns posts published after the given time.
 	Fetch(since time.Time) ([]Post, error)
 }
+
+// FeedStatus is the outcome of fetching one configured feed.
+type FeedStatus struct {
+	Feed string // configured identifier: feed URL, subreddit, newsletter name
+	Err  error  // nil on success
+}
+
+// StatusReporter is implemented by sources that fetch several feeds and skip
+// individual failures instead of failing the whole Fetch.
+type StatusReporter interface {
+	// FeedStatuses returns one entry per feed attempted by the last Fetch.
+	FeedStatuses() []FeedStatus
+}
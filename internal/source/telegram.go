@@ -23,15 +23,18 @@ const (
 type TelegramSource struct {
 	scriptPath string
 	pythonPath string
+	pythonArgs []string // leading interpreter arguments, e.g. "-3" for py
 	apiID      string
 	apiHash    string
 	sessionDir string
 	channels   []string
+	policy     FetchPolicy
+	location   *time.Location
 }
 
 // NewTelegram creates a Telegram source. The scriptPath must point to the
 // collector_telegram.py script. pythonPath is the Python interpreter to use
-// (defaults to "python3"). API credentials and channels come from config.
+// (defaults to PythonCommand). API credentials and channels come from config.
 func NewTelegram(scriptPath, pythonPath, apiID, apiHash, sessionDir string, channels []string) (*TelegramSource, error) {
 	if strings.TrimSpace(scriptPath) == "" {
 		return nil, errors.New("telegram: script path is required")
@@ -39,20 +42,38 @@ func NewTelegram(scriptPath, pythonPath, apiID, apiHash, sessionDir string, chan
 	if len(channels) == 0 {
 		return nil, errors.New("telegram: at least one channel is required")
 	}
+	var pythonArgs []string
 	if pythonPath == "" {
-		pythonPath = "python3"
+		pythonPath, pythonArgs = PythonCommand()
 	}
 
 	return &TelegramSource{
 		scriptPath: scriptPath,
 		pythonPath: pythonPath,
+		pythonArgs: pythonArgs,
 		apiID:      apiID,
 		apiHash:    apiHash,
 		sessionDir: sessionDir,
 		channels:   channels,
+		policy:     FetchPolicy{Timeout: fetchTimeout},
 	}, nil
 }
 
+// Policy returns the current fetch policy.
+func (ts *TelegramSource) Policy() FetchPolicy {
+	return ts.policy
+}
+
+// SetPolicy replaces the fetch policy. Only Timeout applies to the collector script.
+func (ts *TelegramSource) SetPolicy(p FetchPolicy) {
+	ts.policy = p
+}
+
+// SetLocation sets the zone for message dates that carry no UTC offset.
+func (ts *TelegramSource) SetLocation(loc *time.Location) {
+	ts.location = loc
+}
+
 // Name returns "telegram".
 func (ts *TelegramSource) Name() string {
 	return sourceName
@@ -60,17 +81,17 @@ func (ts *TelegramSource) Name() string {
 
 // Fetch invokes the Python collector script and parses JSONL output.
 func (ts *TelegramSource) Fetch(since time.Time) ([]Post, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), ts.policy.Timeout)
 	defer cancel()
 
-	args := []string{
+	args := append(append([]string(nil), ts.pythonArgs...),
 		ts.scriptPath,
 		"--api-id", ts.apiID,
 		"--api-hash", ts.apiHash,
 		"--session-dir", ts.sessionDir,
 		"--channels", strings.Join(ts.channels, ","),
 		"--since", since.UTC().Format(time.RFC3339),
-	}
+	)
 
 	cmd := exec.CommandContext(ctx, ts.pythonPath, args...)
 
@@ -84,12 +105,12 @@ func (ts *TelegramSource) Fetch(since time.Time) ([]Post, error) {
 
 	if err := cmd.Start(); err != nil {
 		if errors.Is(err, exec.ErrNotFound) {
-			return nil, fmt.Errorf("telegram: python3 not found: install Python 3 and Telethon to use telegram source")
+			return nil, fmt.Errorf("telegram: %s not found: install Python 3 and Telethon to use telegram source", ts.pythonPath)
 		}
 		return nil, fmt.Errorf("telegram: start collector: %w", err)
 	}
 
-	posts, parseErr := parseJSONL(stdout)
+	posts, parseErr := parseJSONL(stdout, ts.location)
 
 	if err := cmd.Wait(); err != nil {
 		errMsg := strings.TrimSpace(stderr.String())
@@ -107,20 +128,33 @@ func (ts *TelegramSource) Fetch(since time.Time) ([]Post, error) {
 }
 
 // telegramMessage is the JSONL schema emitted by the Python collector.
+// Media fields are optional so older collector output still parses.
 type telegramMessage struct {
 	Channel string `json:"channel"`
 	MsgID   string `json:"msg_id"`
 	Date    string `json:"date"`
 	Text    string `json:"text"`
 	URL     string `json:"url"`
+
+	MediaType    string `json:"media_type,omitempty"`     // photo, video, document, poll, webpage
+	Caption      string `json:"caption,omitempty"`        // media caption when text is empty
+	ForwardFrom  string `json:"forward_from,omitempty"`   // original channel username
+	ForwardMsgID string `json:"forward_msg_id,omitempty"` // message ID in the original channel
+	GroupedID    string `json:"grouped_id,omitempty"`     // album ID shared by all album parts
+	Author       string `json:"author,omitempty"`         // post signature, or the sender in groups
 }
 
-// parseJSONL reads JSONL from r and converts each line to a Post.
-func parseJSONL(r io.Reader) ([]Post, error) {
+// parseJSONL reads JSONL from r and converts each line to a Post. Album
+// parts (same channel and grouped_id) are merged into a single post keyed by
+// the lowest message ID. Media-only messages without text or caption are
+// dropped. Forwards link to the original channel's message, and because their
+// text is unchanged the store's dedup collapses them onto the original.
+func parseJSONL(r io.Reader, loc *time.Location) ([]Post, error) {
 	scanner := bufio.NewScanner(r)
 	scanner.Buffer(make([]byte, 0, maxLineLength), maxLineLength)
 
 	var posts []Post
+	albums := make(map[string]int) // channel/grouped_id -> index in posts
 	lineNum := 0
 
 	for scanner.Scan() {
@@ -137,22 +171,77 @@ func parseJSONL(r io.Reader) ([]Post, error) {
 
 		postedAt, err := time.Parse(time.RFC3339, msg.Date)
 		if err != nil {
-			return nil, fmt.Errorf("line %d: invalid date %q: %w", lineNum, msg.Date, err)
+			naive, ok := parseNaive(msg.Date, loc)
+			if !ok {
+				return nil, fmt.Errorf("line %d: invalid date %q: %w", lineNum, msg.Date, err)
+			}
+			postedAt = naive
+		}
+
+		text := msg.Text
+		if strings.TrimSpace(text) == "" {
+			text = msg.Caption
 		}
 
-		posts = append(posts, Post{
+		url := msg.URL
+		if msg.ForwardFrom != "" && msg.ForwardMsgID != "" {
+			url = fmt.Sprintf("https://t.me/%s/%s", strings.TrimPrefix(msg.ForwardFrom, "@"), msg.ForwardMsgID)
+		}
+
+		post := Post{
 			Source:     sourceName,
 			Channel:    msg.Channel,
 			ExternalID: msg.MsgID,
-			Text:       msg.Text,
-			URL:        msg.URL,
+			Text:       text,
+			URL:        url,
+			Author:     strings.TrimPrefix(msg.Author, "@"),
 			PostedAt:   postedAt,
-		})
+		}
+
+		if msg.GroupedID != "" {
+			key := msg.Channel + "/" + msg.GroupedID
+			if i, ok := albums[key]; ok {
+				mergeAlbumPart(&posts[i], post)
+				continue
+			}
+			albums[key] = len(posts)
+		}
+
+		posts = append(posts, post)
 	}
 
 	if err := scanner.Err(); err != nil {
 		return nil, fmt.Errorf("read jsonl: %w", err)
 	}
 
-	return posts, nil
+	kept := posts[:0]
+	for _, p := range posts {
+		if strings.TrimSpace(p.Text) != "" {
+			kept = append(kept, p)
+		}
+	}
+	return kept, nil
+}
+
+// mergeAlbumPart folds one album part into the album's post. The album takes
+// the first non-empty text, and the identity of its lowest message ID.
+func mergeAlbumPart(album *Post, part Post) {
+	if strings.TrimSpace(album.Text) == "" {
+		album.Text = part.Text
+	}
+	if msgIDLess(part.ExternalID, album.ExternalID) {
+		album.ExternalID = part.ExternalID
+		album.URL = part.URL
+	}
+	if part.PostedAt.Before(album.PostedAt) {
+		album.PostedAt = part.PostedAt
+	}
+}
+
+// msgIDLess compares numeric Telegram message IDs, falling back to string order.
+func msgIDLess(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
 }
@@ -28,7 +28,7 @@ func TestParseJSONL_ValidMessages(t *testing.T) {
 		{Channel: "devops_ru", MsgID: "101", Date: "2026-02-16T12:00:00Z", Text: "CVE-2026-1234 discovered", URL: "https://t.me/devops_ru/101"},
 	}
 
-	posts, err := parseJSONL(jsonlFromMessages(t, msgs))
+	posts, err := parseJSONL(jsonlFromMessages(t, msgs), nil)
 	if err != nil {
 		t.Fatalf("parseJSONL: %v", err)
 	}
@@ -67,7 +67,7 @@ func TestParseJSONL_ValidMessages(t *testing.T) {
 }
 
 func TestParseJSONL_EmptyInput(t *testing.T) {
-	posts, err := parseJSONL(strings.NewReader(""))
+	posts, err := parseJSONL(strings.NewReader(""), nil)
 	if err != nil {
 		t.Fatalf("parseJSONL: %v", err)
 	}
@@ -82,7 +82,7 @@ func TestParseJSONL_BlankLines(t *testing.T) {
 {"channel":"ch","msg_id":"2","date":"2026-02-16T11:00:00Z","text":"second","url":"u2"}
 
 `
-	posts, err := parseJSONL(strings.NewReader(input))
+	posts, err := parseJSONL(strings.NewReader(input), nil)
 	if err != nil {
 		t.Fatalf("parseJSONL: %v", err)
 	}
@@ -97,11 +97,30 @@ func TestParseJSONL_BlankLines(t *testing.T) {
 	}
 }
 
+func TestParseJSONL_NaiveDateUsesLocation(t *testing.T) {
+	input := `{"channel":"ch","msg_id":"1","date":"2026-02-16 13:00:00","text":"local","url":"u1"}
+{"channel":"ch","msg_id":"2","date":"2026-02-16T10:00:00Z","text":"zoned","url":"u2"}
+`
+	posts, err := parseJSONL(strings.NewReader(input), time.FixedZone("MSK", 3*60*60))
+	if err != nil {
+		t.Fatalf("parseJSONL: %v", err)
+	}
+	if len(posts) != 2 {
+		t.Fatalf("got %d posts, want 2", len(posts))
+	}
+	want := time.Date(2026, 2, 16, 10, 0, 0, 0, time.UTC)
+	for _, p := range posts {
+		if !p.PostedAt.Equal(want) {
+			t.Errorf("post %s PostedAt = %v, want %v", p.ExternalID, p.PostedAt, want)
+		}
+	}
+}
+
 func TestParseJSONL_InvalidJSON(t *testing.T) {
 	input := `{"channel":"ch","msg_id":"1","date":"2026-02-16T10:00:00Z","text":"ok","url":"u"}
 {not valid json}
 `
-	_, err := parseJSONL(strings.NewReader(input))
+	_, err := parseJSONL(strings.NewReader(input), nil)
 	if err == nil {
 		t.Fatal("expected error for invalid JSON")
 	}
@@ -116,7 +135,7 @@ func TestParseJSONL_InvalidJSON(t *testing.T) {
 func TestParseJSONL_InvalidDate(t *testing.T) {
 	input := `{"channel":"ch","msg_id":"1","date":"not-a-date","text":"ok","url":"u"}`
 
-	_, err := parseJSONL(strings.NewReader(input))
+	_, err := parseJSONL(strings.NewReader(input), nil)
 	if err == nil {
 		t.Fatal("expected error for invalid date")
 	}
@@ -139,7 +158,7 @@ func TestParseJSONL_LargeMessage(t *testing.T) {
 		URL:     "u",
 	}
 
-	posts, err := parseJSONL(jsonlFromMessages(t, []telegramMessage{msg}))
+	posts, err := parseJSONL(jsonlFromMessages(t, []telegramMessage{msg}), nil)
 	if err != nil {
 		t.Fatalf("parseJSONL: %v", err)
 	}
@@ -151,6 +170,101 @@ func TestParseJSONL_LargeMessage(t *testing.T) {
 	}
 }
 
+func TestParseJSONL_CaptionFallback(t *testing.T) {
+	msgs := []telegramMessage{
+		{Channel: "ch", MsgID: "1", Date: "2026-02-16T10:00:00Z", MediaType: "photo", Caption: "Long photo caption about a CVE", URL: "https://t.me/ch/1"},
+		{Channel: "ch", MsgID: "2", Date: "2026-02-16T10:05:00Z", MediaType: "photo", URL: "https://t.me/ch/2"},
+	}
+
+	posts, err := parseJSONL(jsonlFromMessages(t, msgs), nil)
+	if err != nil {
+		t.Fatalf("parseJSONL: %v", err)
+	}
+	if len(posts) != 1 {
+		t.Fatalf("got %d posts, want 1 (media without caption dropped)", len(posts))
+	}
+	if posts[0].Text != "Long photo caption about a CVE" {
+		t.Errorf("text = %q, want caption", posts[0].Text)
+	}
+}
+
+func TestParseJSONL_AlbumGrouping(t *testing.T) {
+	// Collector emits newest first; only one album part carries the caption.
+	msgs := []telegramMessage{
+		{Channel: "ch", MsgID: "12", Date: "2026-02-16T10:00:02Z", MediaType: "photo", GroupedID: "777", URL: "https://t.me/ch/12"},
+		{Channel: "ch", MsgID: "11", Date: "2026-02-16T10:00:01Z", MediaType: "photo", GroupedID: "777", Caption: "Release screenshots", URL: "https://t.me/ch/11"},
+		{Channel: "ch", MsgID: "10", Date: "2026-02-16T10:00:00Z", MediaType: "photo", GroupedID: "777", URL: "https://t.me/ch/10"},
+		{Channel: "other", MsgID: "5", Date: "2026-02-16T10:00:00Z", MediaType: "photo", GroupedID: "777", Caption: "Different channel", URL: "https://t.me/other/5"},
+	}
+
+	posts, err := parseJSONL(jsonlFromMessages(t, msgs), nil)
+	if err != nil {
+		t.Fatalf("parseJSONL: %v", err)
+	}
+	if len(posts) != 2 {
+		t.Fatalf("got %d posts, want 2", len(posts))
+	}
+	album := posts[0]
+	if album.Text != "Release screenshots" {
+		t.Errorf("text = %q", album.Text)
+	}
+	if album.ExternalID != "10" || album.URL != "https://t.me/ch/10" {
+		t.Errorf("album identity = %q %q, want lowest message 10", album.ExternalID, album.URL)
+	}
+	if want := time.Date(2026, 2, 16, 10, 0, 0, 0, time.UTC); !album.PostedAt.Equal(want) {
+		t.Errorf("posted_at = %v, want %v", album.PostedAt, want)
+	}
+	if posts[1].Channel != "other" {
+		t.Errorf("albums from different channels must not merge")
+	}
+}
+
+func TestParseJSONL_Forward(t *testing.T) {
+	msgs := []telegramMessage{
+		{Channel: "aggregator", MsgID: "50", Date: "2026-02-16T10:00:00Z", Text: "original text", URL: "https://t.me/aggregator/50", ForwardFrom: "k8s_news", ForwardMsgID: "200"},
+		{Channel: "aggregator", MsgID: "51", Date: "2026-02-16T10:01:00Z", Text: "private forward", URL: "https://t.me/aggregator/51", ForwardFrom: "somegroup"},
+	}
+
+	posts, err := parseJSONL(jsonlFromMessages(t, msgs), nil)
+	if err != nil {
+		t.Fatalf("parseJSONL: %v", err)
+	}
+	if posts[0].URL != "https://t.me/k8s_news/200" {
+		t.Errorf("forward url = %q, want original post", posts[0].URL)
+	}
+	if posts[0].Channel != "aggregator" || posts[0].Text != "original text" {
+		t.Errorf("forward post = %+v", posts[0])
+	}
+	if posts[1].URL != "https://t.me/aggregator/51" {
+		t.Errorf("forward without message id should keep own url, got %q", posts[1].URL)
+	}
+}
+
+func TestParseJSONL_Author(t *testing.T) {
+	msgs := []telegramMessage{
+		{Channel: "ch", MsgID: "1", Date: "2026-02-16T10:00:00Z", Text: "signed", Author: "Jane Doe"},
+		{Channel: "group", MsgID: "2", Date: "2026-02-16T10:01:00Z", Text: "from a member", Author: "@jdoe"},
+		{Channel: "ch", MsgID: "3", Date: "2026-02-16T10:02:00Z", Text: "unsigned"},
+	}
+
+	posts, err := parseJSONL(jsonlFromMessages(t, msgs), nil)
+	if err != nil {
+		t.Fatalf("parseJSONL: %v", err)
+	}
+	if posts[0].Author != "Jane Doe" || posts[1].Author != "jdoe" || posts[2].Author != "" {
+		t.Errorf("authors = %q, %q, %q", posts[0].Author, posts[1].Author, posts[2].Author)
+	}
+}
+
+func TestMsgIDLess(t *testing.T) {
+	if !msgIDLess("9", "10") {
+		t.Error("9 should sort before 10")
+	}
+	if msgIDLess("10", "10") {
+		t.Error("equal ids are not less")
+	}
+}
+
 func TestNewTelegram_EmptyScriptPath(t *testing.T) {
 	_, err := NewTelegram("", "", "id", "hash", "session", []string{"ch"})
 	if err == nil {
@@ -0,0 +1,38 @@
+package source
+
+import (
+	"strings"
+	"time"
+)
+
+// naiveLayouts are timestamp formats seen in feeds and collectors that carry
+// no UTC offset or zone name.
+var naiveLayouts = []string{
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"Mon, 2 Jan 2006 15:04:05",
+	"Mon, 02 Jan 2006 15:04:05",
+	"2 Jan 2006 15:04:05",
+	"02 Jan 2006 15:04:05",
+	"2006-01-02",
+}
+
+// parseNaive parses s as wall-clock time in loc (UTC if nil). It returns
+// false when s carries an offset or zone, or matches no known layout, so
+// callers keep whatever the original parser produced.
+func parseNaive(s string, loc *time.Location) (time.Time, bool) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	s = strings.TrimSpace(s)
+	for _, layout := range naiveLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,381 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Backend is a SQL database the store can run on. Queries in this package
+// are written with ? placeholders and portable SQL; a backend rewrites the
+// few constructs that differ between dialects.
+type Backend interface {
+	// Name is the storage.driver value selecting this backend.
+	Name() string
+	// Open connects to the database described by dsn (a file path for sqlite).
+	Open(dsn string) (*sql.DB, error)
+	// Migrate creates or upgrades the schema in tx, which the caller commits.
+	Migrate(ctx context.Context, tx *sql.Tx) error
+	// Rebind rewrites ? placeholders into the backend's bind syntax.
+	Rebind(query string) string
+	// Least returns an expression for the smaller of two values.
+	Least(a, b string) string
+	// FullText returns the clauses that match and rank posts against query.
+	FullText(query string) TextSearch
+	// TablesQuery lists the store's tables, one name per row.
+	TablesQuery() string
+	// SizeQuery returns the database size in bytes as a single value.
+	SizeQuery() string
+	// IntegrityCheck returns the problems found in the database file, or
+	// errors.ErrUnsupported when the backend has no such check.
+	IntegrityCheck(ctx context.Context, db *sql.DB) ([]string, error)
+}
+
+// TextSearch is a backend's full-text query. From yields posts aliased as p,
+// Match is a WHERE condition, and Rank orders matches (lower is better).
+// Args bind the placeholders in Rank followed by those in Match, the order
+// they appear in the query.
+type TextSearch struct {
+	From  string
+	Match string
+	Rank  string
+	Args  []any
+}
+
+// SQLite is the default embedded backend.
+type SQLite struct{}
+
+// sqliteBusyTimeout is how long a connection waits for another process's
+// write lock (say, "run --every" pulling while "digest" runs by hand)
+// before failing with SQLITE_BUSY.
+const sqliteBusyTimeout = 10 * time.Second
+
+// sqliteParams are applied by the driver to every pooled connection. WAL lets
+// readers proceed while another process writes, and immediate transactions
+// take the write lock up front so busy_timeout applies instead of failing
+// mid-transaction when a read lock cannot be upgraded.
+var sqliteParams = url.Values{
+	"_pragma": {
+		fmt.Sprintf("busy_timeout(%d)", sqliteBusyTimeout.Milliseconds()),
+		"journal_mode(WAL)",
+		"synchronous(NORMAL)",
+		"foreign_keys(1)",
+	},
+	"_txlock": {"immediate"},
+}
+
+func (SQLite) Name() string { return "sqlite" }
+
+func (SQLite) Open(path string) (*sql.DB, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, errors.New("path is required")
+	}
+
+	dir := filepath.Dir(path)
+	if dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create db dir: %w", err)
+		}
+	}
+
+	db, err := sql.Open("sqlite", path+"?"+sqliteParams.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	if err := db.PingContext(context.Background()); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	return db, nil
+}
+
+func (SQLite) Migrate(ctx context.Context, tx *sql.Tx) error {
+	return migrate(ctx, tx)
+}
+
+func (SQLite) Rebind(query string) string { return query }
+
+func (SQLite) Least(a, b string) string { return "MIN(" + a + ", " + b + ")" }
+
+func (SQLite) FullText(query string) TextSearch {
+	return TextSearch{
+		From:  "posts_fts JOIN posts p ON p.id = posts_fts.rowid",
+		Match: "posts_fts MATCH ?",
+		Rank:  "bm25(posts_fts)",
+		Args:  []any{ftsQuery(query)},
+	}
+}
+
+// TablesQuery skips sqlite's internal tables and the FTS index, which
+// mirrors posts.
+func (SQLite) TablesQuery() string {
+	return "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name NOT LIKE 'posts_fts%' ORDER BY name"
+}
+
+func (SQLite) SizeQuery() string {
+	return "SELECT page_count * page_size FROM pragma_page_count(), pragma_page_size()"
+}
+
+func (SQLite) IntegrityCheck(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx, "PRAGMA integrity_check")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var problems []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, err
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	return problems, rows.Err()
+}
+
+// postgresDriver is the database/sql driver name registered by pgx.
+const postgresDriver = "pgx"
+
+// Postgres stores posts in a shared PostgreSQL database so several machines
+// can pull into and read from one corpus.
+type Postgres struct{}
+
+func (Postgres) Name() string { return "postgres" }
+
+func (Postgres) Open(dsn string) (*sql.DB, error) {
+	if strings.TrimSpace(dsn) == "" {
+		return nil, errors.New("dsn is required")
+	}
+
+	db, err := sql.Open(postgresDriver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open postgres: %w", err)
+	}
+	if err := db.PingContext(context.Background()); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("connect postgres: %w", err)
+	}
+	return db, nil
+}
+
+func (Postgres) Migrate(ctx context.Context, tx *sql.Tx) error {
+	return migratePostgres(ctx, tx)
+}
+
+// Rebind numbers placeholders as $1, $2, ... Queries in this package never
+// contain ? inside string literals, so no quoting rules are needed.
+func (Postgres) Rebind(query string) string {
+	if !strings.Contains(query, "?") {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r != '?' {
+			b.WriteRune(r)
+			continue
+		}
+		n++
+		b.WriteString("$" + strconv.Itoa(n))
+	}
+	return b.String()
+}
+
+func (Postgres) Least(a, b string) string { return "LEAST(" + a + ", " + b + ")" }
+
+// postgresDocument is the text search document for a post; it matches the
+// expression index in schema_postgres.sql.
+const postgresDocument = "to_tsvector('simple', COALESCE(p.text, '') || ' ' || p.snippet)"
+
+func (Postgres) FullText(query string) TextSearch {
+	return TextSearch{
+		From:  "posts p",
+		Match: postgresDocument + " @@ plainto_tsquery('simple', ?)",
+		Rank:  "-ts_rank(" + postgresDocument + ", plainto_tsquery('simple', ?))",
+		Args:  []any{query, query},
+	}
+}
+
+func (Postgres) TablesQuery() string {
+	return "SELECT tablename FROM pg_tables WHERE schemaname = current_schema() ORDER BY tablename"
+}
+
+func (Postgres) SizeQuery() string {
+	return "SELECT pg_database_size(current_database())"
+}
+
+// IntegrityCheck is unsupported: the server checksums its own pages and has
+// no single-command equivalent of sqlite's integrity_check.
+func (Postgres) IntegrityCheck(context.Context, *sql.DB) ([]string, error) {
+	return nil, errors.ErrUnsupported
+}
+
+// BackendFor returns the backend registered under driver; empty means sqlite.
+func BackendFor(driver string) (Backend, error) {
+	switch driver {
+	case "", "sqlite":
+		return SQLite{}, nil
+	case "postgres":
+		return Postgres{}, nil
+	}
+	return nil, fmt.Errorf("unknown storage driver %q (want sqlite or postgres)", driver)
+}
+
+// conn rebinds every statement for the backend before handing it to
+// database/sql, so store methods can keep writing ? placeholders. It also
+// serializes writers within the process: Exec calls and transactions hold
+// writeMu, so concurrent goroutines queue here rather than contending for
+// the database lock.
+type conn struct {
+	*sql.DB
+	rebind  func(string) string
+	writeMu sync.Mutex
+	dry     *sql.Tx // set by BeginDryRun or OpenDryRun: every statement runs here
+	system  string  // backend name, for trace spans
+}
+
+// dryRunSavepoint stands in for a transaction during a dry run. Only one is
+// open at a time, since transactions hold writeMu.
+const dryRunSavepoint = "dry_run_tx"
+
+func (c *conn) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	span := statementSpan(ctx, c.system, query)
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	var res sql.Result
+	var err error
+	if c.dry != nil {
+		res, err = c.dry.ExecContext(ctx, c.rebind(query), args...)
+	} else {
+		res, err = c.DB.ExecContext(ctx, c.rebind(query), args...)
+	}
+	telemetry.End(span, err)
+	return res, err
+}
+
+// QueryContext's span covers running the query, not reading its rows.
+func (c *conn) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	span := statementSpan(ctx, c.system, query)
+	var rows *sql.Rows
+	var err error
+	if c.dry != nil {
+		rows, err = c.dry.QueryContext(ctx, c.rebind(query), args...)
+	} else {
+		rows, err = c.DB.QueryContext(ctx, c.rebind(query), args...)
+	}
+	telemetry.End(span, err)
+	return rows, err
+}
+
+func (c *conn) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	span := statementSpan(ctx, c.system, query)
+	defer span.End()
+	if c.dry != nil {
+		return c.dry.QueryRowContext(ctx, c.rebind(query), args...)
+	}
+	return c.DB.QueryRowContext(ctx, c.rebind(query), args...)
+}
+
+// statementSpan starts the trace span of one SQL statement, named by its
+// first keyword ("store INSERT").
+func statementSpan(ctx context.Context, system, query string) trace.Span {
+	verb, _, _ := strings.Cut(strings.TrimSpace(query), " ")
+	_, span := telemetry.Start(ctx, "store "+strings.ToUpper(verb),
+		attribute.String("db.system.name", system),
+		attribute.String("db.query.text", query))
+	return span
+}
+
+// BeginTx starts a transaction holding the write lock until Commit or
+// Rollback. Store methods must not call other Store methods while it is open.
+// During a dry run it opens a savepoint in the dry-run transaction instead.
+func (c *conn) BeginTx(ctx context.Context, opts *sql.TxOptions) (*txConn, error) {
+	c.writeMu.Lock()
+	if c.dry != nil {
+		if _, err := c.dry.ExecContext(ctx, "SAVEPOINT "+dryRunSavepoint); err != nil {
+			c.writeMu.Unlock()
+			return nil, err
+		}
+		return &txConn{Tx: c.dry, rebind: c.rebind, system: c.system, release: sync.OnceFunc(c.writeMu.Unlock), savepoint: true}, nil
+	}
+	t, err := c.DB.BeginTx(ctx, opts)
+	if err != nil {
+		c.writeMu.Unlock()
+		return nil, err
+	}
+	return &txConn{Tx: t, rebind: c.rebind, system: c.system, release: sync.OnceFunc(c.writeMu.Unlock)}, nil
+}
+
+// txConn is the transaction counterpart of conn.
+type txConn struct {
+	*sql.Tx
+	rebind    func(string) string
+	system    string
+	release   func()
+	savepoint bool // a dry-run savepoint rather than a transaction of its own
+	done      bool
+}
+
+func (t *txConn) Commit() error {
+	defer t.release()
+	if !t.savepoint {
+		return t.Tx.Commit()
+	}
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	_, err := t.Tx.ExecContext(context.Background(), "RELEASE SAVEPOINT "+dryRunSavepoint)
+	return err
+}
+
+func (t *txConn) Rollback() error {
+	defer t.release()
+	if !t.savepoint {
+		return t.Tx.Rollback()
+	}
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	if _, err := t.Tx.ExecContext(context.Background(), "ROLLBACK TO SAVEPOINT "+dryRunSavepoint); err != nil {
+		return err
+	}
+	_, err := t.Tx.ExecContext(context.Background(), "RELEASE SAVEPOINT "+dryRunSavepoint)
+	return err
+}
+
+func (t *txConn) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	span := statementSpan(ctx, t.system, query)
+	res, err := t.Tx.ExecContext(ctx, t.rebind(query), args...)
+	telemetry.End(span, err)
+	return res, err
+}
+
+func (t *txConn) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	span := statementSpan(ctx, t.system, query)
+	rows, err := t.Tx.QueryContext(ctx, t.rebind(query), args...)
+	telemetry.End(span, err)
+	return rows, err
+}
+
+func (t *txConn) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	span := statementSpan(ctx, t.system, query)
+	defer span.End()
+	return t.Tx.QueryRowContext(ctx, t.rebind(query), args...)
+}
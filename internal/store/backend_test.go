@@ -0,0 +1,158 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPostgresRebind(t *testing.T) {
+	got := Postgres{}.Rebind("SELECT id FROM posts WHERE source = ? AND id IN (?, ?) LIMIT ?")
+	want := "SELECT id FROM posts WHERE source = $1 AND id IN ($2, $3) LIMIT $4"
+	if got != want {
+		t.Errorf("Rebind = %q, want %q", got, want)
+	}
+	if got := (SQLite{}).Rebind("a = ?"); got != "a = ?" {
+		t.Errorf("sqlite Rebind = %q", got)
+	}
+}
+
+func TestLeast(t *testing.T) {
+	if got := (SQLite{}).Least("a", "b"); got != "MIN(a, b)" {
+		t.Errorf("sqlite Least = %q", got)
+	}
+	if got := (Postgres{}).Least("a", "b"); got != "LEAST(a, b)" {
+		t.Errorf("postgres Least = %q", got)
+	}
+}
+
+func TestBackendFor(t *testing.T) {
+	for driver, want := range map[string]string{"": "sqlite", "sqlite": "sqlite", "postgres": "postgres"} {
+		b, err := BackendFor(driver)
+		if err != nil {
+			t.Fatalf("BackendFor(%q): %v", driver, err)
+		}
+		if b.Name() != want {
+			t.Errorf("BackendFor(%q) = %s, want %s", driver, b.Name(), want)
+		}
+	}
+	if _, err := BackendFor("mysql"); err == nil {
+		t.Error("expected error for unknown driver")
+	}
+}
+
+func TestSQLiteOpen_WALAndBusyTimeout(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+
+	var mode string
+	if err := st.db.QueryRowContext(ctx, "PRAGMA journal_mode").Scan(&mode); err != nil {
+		t.Fatalf("journal_mode: %v", err)
+	}
+	if mode != "wal" {
+		t.Errorf("journal_mode = %q, want wal", mode)
+	}
+	var timeout int64
+	if err := st.db.QueryRowContext(ctx, "PRAGMA busy_timeout").Scan(&timeout); err != nil {
+		t.Fatalf("busy_timeout: %v", err)
+	}
+	if timeout != sqliteBusyTimeout.Milliseconds() {
+		t.Errorf("busy_timeout = %d, want %d", timeout, sqliteBusyTimeout.Milliseconds())
+	}
+}
+
+// TestConcurrentWriters writes from goroutines sharing one Store and from a
+// second Store on the same file, standing in for "run --every" and a manual
+// "digest" in another terminal.
+func TestConcurrentWriters(t *testing.T) {
+	st, path := openTestStore(t)
+	other, err := Open(path)
+	if err != nil {
+		t.Fatalf("open second store: %v", err)
+	}
+	defer func() { _ = other.Close() }()
+
+	ctx := context.Background()
+	now := time.Now()
+	const perWriter = 25
+	errs := make(chan error, 4*perWriter)
+	var wg sync.WaitGroup
+	for w, db := range []*Store{st, st, other, other} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				p, err := db.InsertPost(ctx, PostInput{
+					Source: "rss", Channel: "c", ExternalID: fmt.Sprintf("%d-%d", w, i),
+					Text: "post", PostedAt: now, FetchedAt: now,
+				})
+				if err != nil {
+					errs <- err
+					continue
+				}
+				if err := db.MarkRead(ctx, now, p.ID); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("concurrent write: %v", err)
+	}
+
+	var n int
+	if err := st.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM read_state").Scan(&n); err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if n != 4*perWriter {
+		t.Errorf("read_state rows = %d, want %d", n, 4*perWriter)
+	}
+}
+
+func TestPostgresOpen_RequiresDSN(t *testing.T) {
+	if _, err := OpenBackend(Postgres{}, " "); err == nil {
+		t.Fatal("expected error for empty dsn")
+	}
+}
+
+// TestPostgresRoundTrip runs against a real server when
+// NOISEPAN_TEST_POSTGRES_DSN is set, e.g. postgres://localhost/noisepan_test.
+func TestPostgresRoundTrip(t *testing.T) {
+	dsn := os.Getenv("NOISEPAN_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("NOISEPAN_TEST_POSTGRES_DSN not set")
+	}
+
+	st, err := OpenBackend(Postgres{}, dsn)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+	ctx := context.Background()
+	if _, err := st.db.ExecContext(ctx, "TRUNCATE posts, scores, post_also_in, read_state, stars, feedback CASCADE"); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	cve, _ := insertSearchFixtures(t, st)
+
+	results, err := st.Search(ctx, "openssl", SearchFilter{})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+
+	posts, err := st.GetPosts(ctx, cve.PostedAt.Add(-time.Hour), "")
+	if err != nil {
+		t.Fatalf("get posts: %v", err)
+	}
+	if len(posts) != 1 || posts[0].Post.ID != cve.ID {
+		t.Fatalf("posts = %+v, want only the cve post", posts)
+	}
+}
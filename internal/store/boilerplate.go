@@ -0,0 +1,136 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// BoilerplateBlock is a text block learned to repeat across a channel's
+// posts. Posts of Sample recent posts contained it.
+type BoilerplateBlock struct {
+	Source    string
+	Channel   string
+	Text      string
+	Posts     int
+	Sample    int
+	LearnedAt time.Time
+}
+
+// ReplaceBoilerplate replaces the learned blocks of one channel. An empty
+// blocks slice forgets what was learned before.
+func (s *Store) ReplaceBoilerplate(ctx context.Context, source, channel string, blocks []BoilerplateBlock) error {
+	if s == nil || s.db == nil {
+		return errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM boilerplate WHERE source = ? AND channel = ?", source, channel); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("clear boilerplate: %w", err)
+	}
+	for _, b := range blocks {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO boilerplate(source, channel, block, posts, sample, learned_at)
+			VALUES(?, ?, ?, ?, ?, ?)
+			ON CONFLICT DO NOTHING`,
+			source, channel, b.Text, b.Posts, b.Sample, formatTime(b.LearnedAt),
+		); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("insert boilerplate: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetBoilerplate returns every learned block ordered by source, channel,
+// then how many posts contained it (most first).
+func (s *Store) GetBoilerplate(ctx context.Context) ([]BoilerplateBlock, error) {
+	if s == nil || s.db == nil {
+		return nil, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT source, channel, block, posts, sample, learned_at FROM boilerplate
+		ORDER BY source, channel, posts DESC, block`)
+	if err != nil {
+		return nil, fmt.Errorf("get boilerplate: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []BoilerplateBlock
+	for rows.Next() {
+		var (
+			b         BoilerplateBlock
+			learnedAt string
+		)
+		if err := rows.Scan(&b.Source, &b.Channel, &b.Text, &b.Posts, &b.Sample, &learnedAt); err != nil {
+			return nil, fmt.Errorf("scan boilerplate: %w", err)
+		}
+		if b.LearnedAt, err = parseTime(learnedAt); err != nil {
+			return nil, fmt.Errorf("parse learned_at: %w", err)
+		}
+		out = append(out, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate boilerplate: %w", err)
+	}
+	return out, nil
+}
+
+// RecentTexts returns the text (or snippet, if full text is not stored) of
+// a channel's newest posts by fetch time, at most limit of them.
+func (s *Store) RecentTexts(ctx context.Context, source, channel string, limit int) ([]string, error) {
+	if s == nil || s.db == nil {
+		return nil, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT text, snippet FROM posts
+		WHERE source = ? AND channel = ? AND deleted_at IS NULL
+		ORDER BY fetched_at DESC, id DESC
+		LIMIT ?`,
+		source, channel, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get recent texts: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []string
+	for rows.Next() {
+		var (
+			text    sql.NullString
+			snippet string
+		)
+		if err := rows.Scan(&text, &snippet); err != nil {
+			return nil, fmt.Errorf("scan recent text: %w", err)
+		}
+		if text.String != "" {
+			out = append(out, text.String)
+		} else {
+			out = append(out, snippet)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate recent texts: %w", err)
+	}
+	return out, nil
+}
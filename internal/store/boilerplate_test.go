@@ -0,0 +1,80 @@
+package store
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestReplaceBoilerplate(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	at := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	blocks := []BoilerplateBlock{
+		{Text: "Unsubscribe at example.com", Posts: 9, Sample: 10, LearnedAt: at},
+		{Text: "Sponsored by Acme", Posts: 7, Sample: 10, LearnedAt: at},
+	}
+	if err := st.ReplaceBoilerplate(ctx, "rss", "Ops Weekly", blocks); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	if err := st.ReplaceBoilerplate(ctx, "rss", "Other", blocks[:1]); err != nil {
+		t.Fatalf("replace other: %v", err)
+	}
+
+	got, err := st.GetBoilerplate(ctx)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if len(got) != 3 || got[0].Channel != "Ops Weekly" || got[0].Posts != 9 || got[1].Text != "Sponsored by Acme" {
+		t.Fatalf("blocks = %+v", got)
+	}
+	if !got[0].LearnedAt.Equal(at) || got[0].Source != "rss" || got[0].Sample != 10 {
+		t.Errorf("block = %+v", got[0])
+	}
+
+	// Relearning replaces only that channel's blocks.
+	if err := st.ReplaceBoilerplate(ctx, "rss", "Ops Weekly", nil); err != nil {
+		t.Fatalf("clear: %v", err)
+	}
+	got, err = st.GetBoilerplate(ctx)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if len(got) != 1 || got[0].Channel != "Other" {
+		t.Fatalf("blocks after clear = %+v", got)
+	}
+}
+
+func TestRecentTexts(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		in := PostInput{
+			Source: "rss", Channel: "news", ExternalID: strconv.Itoa(i),
+			Text: "post " + strconv.Itoa(i), PostedAt: base, FetchedAt: base.Add(time.Duration(i) * time.Hour),
+		}
+		if i == 2 {
+			in.Text, in.Snippet = "", "snippet only"
+		}
+		if _, err := st.InsertPost(ctx, in); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+	}
+	if _, err := st.InsertPost(ctx, PostInput{
+		Source: "rss", Channel: "elsewhere", ExternalID: "x", Text: "other", PostedAt: base, FetchedAt: base,
+	}); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	texts, err := st.RecentTexts(ctx, "rss", "news", 2)
+	if err != nil {
+		t.Fatalf("recent: %v", err)
+	}
+	if len(texts) != 2 || texts[0] != "snippet only" || texts[1] != "post 1" {
+		t.Errorf("texts = %q", texts)
+	}
+}
@@ -0,0 +1,91 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// trackingParams are query parameters that identify the campaign or click,
+// not the page. utm_* parameters are matched by prefix.
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "dclid": true, "msclkid": true, "yclid": true,
+	"igshid": true, "mc_cid": true, "mc_eid": true, "_hsenc": true, "_hsmi": true,
+	"ref_src": true,
+}
+
+// canonicalURL normalizes a post URL so reposts of the same page compare
+// equal: http and https match, the host is lowercased without "www." or a
+// default port, tracking parameters, credentials and the fragment are
+// dropped, remaining parameters are sorted, and trailing slashes are
+// trimmed. URLs that do not parse with a host are returned trimmed.
+func canonicalURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+
+	if u.Scheme == "http" || u.Scheme == "https" {
+		u.Scheme = "https"
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host += ":" + port
+	}
+	u.Host = host
+	u.User = nil
+	u.Fragment, u.RawFragment = "", ""
+
+	q := u.Query()
+	for k := range q {
+		if strings.HasPrefix(strings.ToLower(k), "utm_") || trackingParams[strings.ToLower(k)] {
+			q.Del(k)
+		}
+	}
+	u.RawQuery = q.Encode()
+	u.ForceQuery = false
+
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = ""
+	return u.String()
+}
+
+// backfillCanonicalURL fills canonical_url for posts stored before the
+// column existed.
+func backfillCanonicalURL(ctx context.Context, tx *txConn) error {
+	rows, err := tx.QueryContext(ctx, "SELECT id, url FROM posts WHERE canonical_url IS NULL AND url IS NOT NULL")
+	if err != nil {
+		return fmt.Errorf("query posts without canonical url: %w", err)
+	}
+	type pending struct {
+		id  int64
+		url string
+	}
+	var todo []pending
+	for rows.Next() {
+		var (
+			id  int64
+			raw sql.NullString
+		)
+		if err := rows.Scan(&id, &raw); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("scan post url: %w", err)
+		}
+		todo = append(todo, pending{id: id, url: canonicalURL(raw.String)})
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return fmt.Errorf("iterate post urls: %w", err)
+	}
+	_ = rows.Close()
+
+	for _, p := range todo {
+		if _, err := tx.ExecContext(ctx, "UPDATE posts SET canonical_url = ? WHERE id = ?", p.url, p.id); err != nil {
+			return fmt.Errorf("backfill canonical url: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package store
+
+import "testing"
+
+func TestCanonicalURL(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"https://example.com/post/", "https://example.com/post"},
+		{"http://WWW.Example.com:80/post?utm_source=tg&utm_medium=social#comments", "https://example.com/post"},
+		{"https://example.com/post?id=7&fbclid=abc&a=1", "https://example.com/post?a=1&id=7"},
+		{"https://user:pw@example.com:8443/x", "https://example.com:8443/x"},
+		{"https://example.com/", "https://example.com"},
+		{"  https://example.com/a  ", "https://example.com/a"},
+		{"gemini://example.org/log/", "gemini://example.org/log"},
+		{"not a url", "not a url"},
+	}
+	for _, tt := range tests {
+		if got := canonicalURL(tt.in); got != tt.want {
+			t.Errorf("canonicalURL(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
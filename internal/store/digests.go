@@ -0,0 +1,183 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DigestSnapshot is a generated digest kept for later: what it covered and
+// its input, from which it can be rendered again however the taste profile
+// has changed since.
+type DigestSnapshot struct {
+	ID         int64
+	CreatedAt  time.Time
+	Since      time.Duration  // the digest window
+	Format     string         // the format or template it was rendered with
+	TotalPosts int            // posts the digest covered
+	Counts     map[string]int // listed items per tier
+	OutputHash string         // SHA-256 of the rendered output, hex
+	Input      []byte         // the digest input as JSON; empty in ListDigests
+	PostIDs    []int64        // posts it listed, which PostFilter.NewOnly skips; not loaded back
+}
+
+// ErrDigestNotFound is returned by GetDigest when no digest has the ID.
+var ErrDigestNotFound = errors.New("digest not found")
+
+// SaveDigest stores d, whose ID is ignored, and returns its new ID.
+func (s *Store) SaveDigest(ctx context.Context, d DigestSnapshot) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if len(d.Input) == 0 {
+		return 0, errors.New("digest input is required")
+	}
+
+	counts := d.Counts
+	if counts == nil {
+		counts = map[string]int{}
+	}
+	countsJSON, err := json.Marshal(counts)
+	if err != nil {
+		return 0, fmt.Errorf("encode counts: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	var id int64
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO digests(created_at, since_secs, format, total_posts, counts, output_hash, input)
+		VALUES(?, ?, ?, ?, ?, ?, ?)
+		RETURNING id`,
+		formatTime(d.CreatedAt), int64(d.Since/time.Second), d.Format, d.TotalPosts,
+		string(countsJSON), d.OutputHash, string(d.Input),
+	).Scan(&id)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, fmt.Errorf("save digest: %w", err)
+	}
+	for _, postID := range d.PostIDs {
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO digest_items(digest_id, post_id) SELECT ?, id FROM posts WHERE id = ? ON CONFLICT DO NOTHING",
+			id, postID,
+		); err != nil {
+			_ = tx.Rollback()
+			return 0, fmt.Errorf("save digest items: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit save digest: %w", err)
+	}
+	return id, nil
+}
+
+// ListDigests returns up to limit saved digests, newest first, without
+// their input. limit <= 0 returns all.
+func (s *Store) ListDigests(ctx context.Context, limit int) ([]DigestSnapshot, error) {
+	if s == nil || s.db == nil {
+		return nil, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	query := `
+		SELECT id, created_at, since_secs, format, total_posts, counts, output_hash, ''
+		FROM digests
+		ORDER BY created_at DESC, id DESC`
+	var args []any
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list digests: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var digests []DigestSnapshot
+	for rows.Next() {
+		d, err := scanDigest(rows)
+		if err != nil {
+			return nil, err
+		}
+		digests = append(digests, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate digests: %w", err)
+	}
+	return digests, nil
+}
+
+// GetDigest returns the saved digest with the ID, input included.
+func (s *Store) GetDigest(ctx context.Context, id int64) (DigestSnapshot, error) {
+	if s == nil || s.db == nil {
+		return DigestSnapshot{}, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	row := s.db.QueryRowContext(ctx, `
+		SELECT id, created_at, since_secs, format, total_posts, counts, output_hash, input
+		FROM digests
+		WHERE id = ?`, id)
+	d, err := scanDigest(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return DigestSnapshot{}, fmt.Errorf("get digest %d: %w", id, ErrDigestNotFound)
+	}
+	return d, err
+}
+
+// PruneDigests deletes the digests saved before before, which hold copies
+// of post text, and returns how many were deleted.
+func (s *Store) PruneDigests(ctx context.Context, before time.Time) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM digests WHERE created_at < ?", formatTime(before))
+	if err != nil {
+		return 0, fmt.Errorf("prune digests: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("prune digests: %w", err)
+	}
+	return n, nil
+}
+
+func scanDigest(row rowScanner) (DigestSnapshot, error) {
+	var (
+		d                       DigestSnapshot
+		createdAt, counts, body string
+		sinceSecs               int64
+	)
+	if err := row.Scan(&d.ID, &createdAt, &sinceSecs, &d.Format, &d.TotalPosts, &counts, &d.OutputHash, &body); err != nil {
+		return DigestSnapshot{}, fmt.Errorf("scan digest: %w", err)
+	}
+	var err error
+	if d.CreatedAt, err = parseTime(createdAt); err != nil {
+		return DigestSnapshot{}, fmt.Errorf("parse created_at: %w", err)
+	}
+	if err := json.Unmarshal([]byte(counts), &d.Counts); err != nil {
+		return DigestSnapshot{}, fmt.Errorf("decode counts: %w", err)
+	}
+	d.Since = time.Duration(sinceSecs) * time.Second
+	if body != "" {
+		d.Input = []byte(body)
+	}
+	return d, nil
+}
@@ -0,0 +1,104 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSaveAndGetDigest(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	day1 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+
+	firstID, err := st.SaveDigest(ctx, DigestSnapshot{
+		CreatedAt: day1, Since: 24 * time.Hour, Format: "terminal", TotalPosts: 40,
+		Counts: map[string]int{"read_now": 2, "skim": 5}, OutputHash: "aa", Input: []byte(`{"Items":[]}`),
+	})
+	if err != nil {
+		t.Fatalf("save first: %v", err)
+	}
+	secondID, err := st.SaveDigest(ctx, DigestSnapshot{
+		CreatedAt: day2, Since: 48 * time.Hour, Format: "markdown", TotalPosts: 7,
+		OutputHash: "bb", Input: []byte(`{}`),
+	})
+	if err != nil {
+		t.Fatalf("save second: %v", err)
+	}
+	if _, err := st.SaveDigest(ctx, DigestSnapshot{CreatedAt: day2}); err == nil {
+		t.Fatal("expected error saving a digest without input")
+	}
+
+	list, err := st.ListDigests(ctx, 0)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(list) != 2 || list[0].ID != secondID || list[1].ID != firstID {
+		t.Fatalf("list = %+v, want newest first", list)
+	}
+	if list[1].Input != nil || list[1].Counts["skim"] != 5 || list[1].Since != 24*time.Hour || !list[1].CreatedAt.Equal(day1) {
+		t.Errorf("listed digest = %+v", list[1])
+	}
+	if list[0].Counts == nil {
+		t.Error("nil counts should load as an empty map")
+	}
+	if limited, err := st.ListDigests(ctx, 1); err != nil || len(limited) != 1 {
+		t.Fatalf("limited list = %v, %v", limited, err)
+	}
+
+	d, err := st.GetDigest(ctx, firstID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if string(d.Input) != `{"Items":[]}` || d.Format != "terminal" || d.TotalPosts != 40 || d.OutputHash != "aa" {
+		t.Errorf("digest = %+v", d)
+	}
+	if _, err := st.GetDigest(ctx, 999); !errors.Is(err, ErrDigestNotFound) {
+		t.Fatalf("get missing = %v, want ErrDigestNotFound", err)
+	}
+
+	n, err := st.PruneDigests(ctx, day2)
+	if err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("pruned %d digests, want 1", n)
+	}
+	if _, err := st.GetDigest(ctx, firstID); !errors.Is(err, ErrDigestNotFound) {
+		t.Fatalf("pruned digest still there: %v", err)
+	}
+}
+
+func TestGetPosts_NewOnly(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	cve, helm := insertSearchFixtures(t, st)
+
+	if _, err := st.SaveDigest(ctx, DigestSnapshot{
+		CreatedAt: time.Now(), Input: []byte("{}"), PostIDs: []int64{cve.ID, cve.ID, 999},
+	}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	posts, err := st.GetPosts(ctx, time.Time{}, "", PostFilter{NewOnly: true})
+	if err != nil {
+		t.Fatalf("get posts: %v", err)
+	}
+	if len(posts) != 1 || posts[0].Post.ID != helm.ID {
+		t.Fatalf("new-only posts = %+v, want only %d", posts, helm.ID)
+	}
+
+	// Deleting the digest makes its posts new again.
+	if _, err := st.PruneDigests(ctx, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	posts, err = st.GetPosts(ctx, time.Time{}, "", PostFilter{NewOnly: true})
+	if err != nil {
+		t.Fatalf("get posts: %v", err)
+	}
+	if len(posts) != 2 {
+		t.Fatalf("got %d posts after prune, want 2", len(posts))
+	}
+}
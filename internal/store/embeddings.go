@@ -0,0 +1,292 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/embed"
+)
+
+// ErrNoEmbedding is returned by GetEmbedding when the post has no embedding
+// from the model.
+var ErrNoEmbedding = errors.New("post has no embedding")
+
+// SaveEmbeddings stores the model's vectors by post ID, replacing earlier
+// ones.
+func (s *Store) SaveEmbeddings(ctx context.Context, model string, vectors map[int64]embed.Vector, at time.Time) error {
+	if s == nil || s.db == nil {
+		return errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if len(vectors) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	for id, v := range vectors {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO embeddings(post_id, model, vector, created_at)
+			VALUES(?, ?, ?, ?)
+			ON CONFLICT(post_id, model) DO UPDATE SET
+				vector = excluded.vector,
+				created_at = excluded.created_at`,
+			id, model, v.Encode(), formatTime(at),
+		)
+		if err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("save embedding: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit embeddings: %w", err)
+	}
+	return nil
+}
+
+// PostsWithoutEmbedding returns up to limit live posts fetched at or after
+// since that have no embedding from the model, newest first. A limit of 0
+// means no limit.
+func (s *Store) PostsWithoutEmbedding(ctx context.Context, model string, since time.Time, limit int) ([]Post, error) {
+	if s == nil || s.db == nil {
+		return nil, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	q := `
+		SELECT p.id, p.source, p.channel, p.external_id, p.text, p.snippet, p.text_hash, p.url, p.author, p.language, p.posted_at, p.fetched_at
+		FROM posts p
+		LEFT JOIN embeddings e ON e.post_id = p.id AND e.model = ?
+		WHERE e.post_id IS NULL AND p.fetched_at >= ?` + liveClause + `
+		ORDER BY p.id DESC`
+	args := []any{model, formatTime(since)}
+	if limit > 0 {
+		q += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get posts without embedding: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var posts []Post
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, post)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate posts without embedding: %w", err)
+	}
+	return posts, nil
+}
+
+// GetEmbedding returns the model's vector for a post.
+func (s *Store) GetEmbedding(ctx context.Context, postID int64, model string) (embed.Vector, error) {
+	if s == nil || s.db == nil {
+		return nil, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var raw []byte
+	err := s.db.QueryRowContext(ctx,
+		"SELECT vector FROM embeddings WHERE post_id = ? AND model = ?", postID, model,
+	).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("get embedding of post %d: %w", postID, ErrNoEmbedding)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get embedding: %w", err)
+	}
+	return embed.Decode(raw)
+}
+
+// GetEmbeddings returns the model's vectors of the posts that have one, by
+// post ID.
+func (s *Store) GetEmbeddings(ctx context.Context, model string, postIDs []int64) (map[int64]embed.Vector, error) {
+	if s == nil || s.db == nil {
+		return nil, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if len(postIDs) == 0 {
+		return nil, nil
+	}
+
+	placeholders := make([]string, len(postIDs))
+	args := []any{model}
+	for i, id := range postIDs {
+		placeholders[i] = "?"
+		args = append(args, id)
+	}
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT post_id, vector
+		FROM embeddings
+		WHERE model = ? AND post_id IN (%s)`, strings.Join(placeholders, ",")), args...)
+	if err != nil {
+		return nil, fmt.Errorf("get embeddings: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	vectors := make(map[int64]embed.Vector)
+	for rows.Next() {
+		var (
+			id  int64
+			raw []byte
+		)
+		if err := rows.Scan(&id, &raw); err != nil {
+			return nil, fmt.Errorf("scan embedding: %w", err)
+		}
+		v, err := embed.Decode(raw)
+		if err != nil {
+			return nil, fmt.Errorf("decode embedding of post %d: %w", id, err)
+		}
+		vectors[id] = v
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate embeddings: %w", err)
+	}
+	return vectors, nil
+}
+
+// SemanticSearch returns the posts whose embeddings from the model are
+// closest to query, most similar first. Rank is 1 minus the cosine
+// similarity, so lower is better as with Search. Posts without an embedding
+// are never matched.
+func (s *Store) SemanticSearch(ctx context.Context, model string, query embed.Vector, filter SearchFilter) ([]SearchResult, error) {
+	if s == nil || s.db == nil {
+		return nil, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if len(query) == 0 {
+		return nil, errors.New("search vector is empty")
+	}
+
+	join := "LEFT JOIN"
+	if filter.Tier != "" {
+		join = "JOIN"
+	}
+
+	q := fmt.Sprintf(`
+		SELECT p.id, p.source, p.channel, p.external_id, p.text, p.snippet, p.text_hash, p.url, p.author, p.language, p.posted_at, p.fetched_at,
+			s.score, s.labels, s.tier, s.scored_at, s.explanation, s.text_hash, s.taste_hash, e.vector
+		FROM posts p
+		JOIN embeddings e ON e.post_id = p.id AND e.model = ?
+		%s scores s ON s.post_id = p.id`+s.scoreProfile()+`
+		WHERE `+s.effectiveTime()+` >= ?`+liveClause, join)
+	args := []any{model, formatTime(filter.Since)}
+
+	if filter.Tier != "" {
+		q += " AND s.tier = ?"
+		args = append(args, filter.Tier)
+	}
+	if filter.StarredOnly {
+		q += starredClause
+	}
+	if filter.ExcludeID != 0 {
+		q += " AND p.id <> ?"
+		args = append(args, filter.ExcludeID)
+	}
+
+	rows, err := s.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("semantic search: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var results []SearchResult
+	for rows.Next() {
+		var raw []byte
+		post, score, err := scanPostWithScore(vectorScanner{rows, &raw})
+		if err != nil {
+			return nil, err
+		}
+		v, err := embed.Decode(raw)
+		if err != nil {
+			return nil, fmt.Errorf("decode embedding of post %d: %w", post.ID, err)
+		}
+		results = append(results, SearchResult{
+			PostWithScore: PostWithScore{Post: post, Score: score},
+			Rank:          1 - embed.Cosine(query, v),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate semantic search: %w", err)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank < results[j].Rank
+		}
+		return results[i].Post.PostedAt.After(results[j].Post.PostedAt)
+	})
+	if filter.Limit > 0 && len(results) > filter.Limit {
+		results = results[:filter.Limit]
+	}
+	return results, nil
+}
+
+// vectorScanner appends the trailing vector column to a post-with-score scan.
+type vectorScanner struct {
+	rows   *sql.Rows
+	vector *[]byte
+}
+
+func (r vectorScanner) Scan(dest ...any) error {
+	return r.rows.Scan(append(dest, r.vector)...)
+}
+
+// loadEmbeddings returns the model's vectors of live posts fetched at or
+// after since, by post ID.
+func loadEmbeddings(ctx context.Context, tx *txConn, model string, since time.Time) (map[int64]embed.Vector, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT e.post_id, e.vector
+		FROM embeddings e
+		JOIN posts p ON p.id = e.post_id
+		WHERE e.model = ? AND p.fetched_at >= ?`+liveClause,
+		model, formatTime(since))
+	if err != nil {
+		return nil, fmt.Errorf("load embeddings: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	vectors := make(map[int64]embed.Vector)
+	for rows.Next() {
+		var (
+			id  int64
+			raw []byte
+		)
+		if err := rows.Scan(&id, &raw); err != nil {
+			return nil, fmt.Errorf("scan embedding: %w", err)
+		}
+		v, err := embed.Decode(raw)
+		if err != nil {
+			return nil, fmt.Errorf("decode embedding of post %d: %w", id, err)
+		}
+		vectors[id] = v
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate embeddings: %w", err)
+	}
+	return vectors, nil
+}
@@ -0,0 +1,128 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/embed"
+)
+
+func TestSaveAndGetEmbedding(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	cve, helm := insertSearchFixtures(t, st)
+	at := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	missing, err := st.PostsWithoutEmbedding(ctx, "m", time.Time{}, 0)
+	if err != nil || len(missing) != 2 {
+		t.Fatalf("without embedding = %d posts, err %v; want 2", len(missing), err)
+	}
+
+	if err := st.SaveEmbeddings(ctx, "m", map[int64]embed.Vector{cve.ID: {1, 0}}, at); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if err := st.SaveEmbeddings(ctx, "m", map[int64]embed.Vector{cve.ID: {0.5, 0.5}}, at); err != nil {
+		t.Fatalf("save again: %v", err)
+	}
+	got, err := st.GetEmbedding(ctx, cve.ID, "m")
+	if err != nil || !slices.Equal(got, embed.Vector{0.5, 0.5}) {
+		t.Errorf("embedding = %v, %v", got, err)
+	}
+
+	// Vectors are kept per model.
+	if _, err := st.GetEmbedding(ctx, cve.ID, "other"); !errors.Is(err, ErrNoEmbedding) {
+		t.Errorf("other model err = %v, want ErrNoEmbedding", err)
+	}
+	byID, err := st.GetEmbeddings(ctx, "m", []int64{cve.ID, helm.ID})
+	if err != nil || len(byID) != 1 || !slices.Equal(byID[cve.ID], embed.Vector{0.5, 0.5}) {
+		t.Errorf("embeddings = %v, %v; want the cve post's", byID, err)
+	}
+	missing, err = st.PostsWithoutEmbedding(ctx, "m", time.Time{}, 0)
+	if err != nil || len(missing) != 1 || missing[0].ID != helm.ID {
+		t.Errorf("without embedding = %+v, %v; want the helm post", missing, err)
+	}
+}
+
+func TestSemanticSearch(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	cve, helm := insertSearchFixtures(t, st)
+	if err := st.SaveEmbeddings(ctx, "m", map[int64]embed.Vector{cve.ID: {1, 0}, helm.ID: {0.6, 0.8}}, time.Now()); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	results, err := st.SemanticSearch(ctx, "m", embed.Vector{0, 1}, SearchFilter{})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 2 || results[0].Post.ID != helm.ID || results[0].Rank >= results[1].Rank {
+		t.Fatalf("results = %+v, want helm first", results)
+	}
+
+	results, err = st.SemanticSearch(ctx, "m", embed.Vector{0, 1}, SearchFilter{ExcludeID: helm.ID, Limit: 5})
+	if err != nil || len(results) != 1 || results[0].Post.ID != cve.ID {
+		t.Errorf("excluding helm = %+v, %v", results, err)
+	}
+	if results, err := st.SemanticSearch(ctx, "other", embed.Vector{0, 1}, SearchFilter{}); err != nil || len(results) != 0 {
+		t.Errorf("other model = %+v, %v; want none", results, err)
+	}
+	if _, err := st.SemanticSearch(ctx, "m", nil, SearchFilter{}); err == nil {
+		t.Error("expected error for an empty vector")
+	}
+}
+
+func TestDeduplicateWith_Semantic(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+
+	// Semantic dedup looks at recent fetches only.
+	now := time.Now().UTC().Truncate(time.Second)
+	vectors := make(map[int64]embed.Vector)
+	for i, in := range []struct {
+		post PostInput
+		vec  embed.Vector
+	}{
+		{PostInput{Source: "telegram", Channel: "chan1", ExternalID: "1", PostedAt: now.Add(-2 * time.Hour),
+			Text: "Cloudflare is down across Europe"}, embed.Vector{1, 0.1, 0}},
+		{PostInput{Source: "rss", Channel: "status", ExternalID: "2", PostedAt: now.Add(-time.Hour),
+			Text: "Major CDN outage takes EU sites offline"}, embed.Vector{0.98, 0.15, 0}},
+		{PostInput{Source: "rss", Channel: "k8s", ExternalID: "3", PostedAt: now,
+			Text: "Kubernetes 1.33 released"}, embed.Vector{0, 0.2, 1}},
+	} {
+		in.post.FetchedAt = now
+		p, err := st.InsertPost(ctx, in.post)
+		if err != nil {
+			t.Fatalf("insert %d: %v", i, err)
+		}
+		vectors[p.ID] = in.vec
+	}
+	if err := st.SaveEmbeddings(ctx, "m", vectors, now); err != nil {
+		t.Fatalf("save embeddings: %v", err)
+	}
+
+	// Without the semantic option the rewording stays.
+	if deleted, err := st.DeduplicateWith(ctx, DedupKeeper{Similarity: 0.8}); err != nil || deleted != 0 {
+		t.Fatalf("fingerprint dedup: deleted %d, err %v", deleted, err)
+	}
+
+	deleted, err := st.DeduplicateWith(ctx, DedupKeeper{SemanticModel: "m", SemanticSimilarity: 0.95})
+	if err != nil {
+		t.Fatalf("deduplicate: %v", err)
+	}
+	if deleted != 1 {
+		t.Fatalf("deleted = %d, want 1", deleted)
+	}
+	alsoIn, err := st.GetAlsoIn(ctx, []int64{1})
+	if err != nil {
+		t.Fatalf("get also_in: %v", err)
+	}
+	if got := alsoIn[1]; len(got) != 1 || got[0] != "rss/status" {
+		t.Errorf("also_in = %v, want [rss/status]", got)
+	}
+	if got := remainingSources(t, st); len(got) != 2 {
+		t.Errorf("remaining = %v, want the kept outage post and the k8s post", got)
+	}
+}
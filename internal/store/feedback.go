@@ -0,0 +1,212 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Feedback votes.
+const (
+	FeedbackUp   = 1
+	FeedbackDown = -1
+)
+
+// Feedback is a user's verdict on a post: up if it was worth reading, down
+// if it was noise. Each post keeps only its latest vote.
+type Feedback struct {
+	PostID    int64
+	Vote      int
+	Reason    string
+	CreatedAt time.Time
+}
+
+// TierFeedback counts votes on posts scored into one tier.
+type TierFeedback struct {
+	Tier string
+	Up   int
+	Down int
+}
+
+// SaveFeedback records a vote for a post, replacing any earlier vote.
+func (s *Store) SaveFeedback(ctx context.Context, fb Feedback) error {
+	if s == nil || s.db == nil {
+		return errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if fb.Vote != FeedbackUp && fb.Vote != FeedbackDown {
+		return fmt.Errorf("invalid vote %d", fb.Vote)
+	}
+
+	var reason any
+	if fb.Reason != "" {
+		reason = fb.Reason
+	}
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO feedback(post_id, vote, reason, created_at)
+		SELECT id, ?, ?, ? FROM posts WHERE id = ? AND deleted_at IS NULL
+		ON CONFLICT(post_id) DO UPDATE SET
+			vote = excluded.vote,
+			reason = excluded.reason,
+			created_at = excluded.created_at`,
+		fb.Vote, reason, formatTime(fb.CreatedAt), fb.PostID,
+	)
+	if err != nil {
+		return fmt.Errorf("save feedback: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("post %d not found", fb.PostID)
+	}
+	return nil
+}
+
+// GetFeedback returns votes recorded at or after since (zero means all),
+// newest first.
+func (s *Store) GetFeedback(ctx context.Context, since time.Time) ([]Feedback, error) {
+	if s == nil || s.db == nil {
+		return nil, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT post_id, vote, reason, created_at FROM feedback
+		WHERE created_at >= ?
+		ORDER BY created_at DESC, post_id DESC`,
+		formatTime(since),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get feedback: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []Feedback
+	for rows.Next() {
+		var (
+			fb        Feedback
+			reason    sql.NullString
+			createdAt string
+		)
+		if err := rows.Scan(&fb.PostID, &fb.Vote, &reason, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan feedback: %w", err)
+		}
+		fb.Reason = reason.String
+		if fb.CreatedAt, err = parseTime(createdAt); err != nil {
+			return nil, fmt.Errorf("parse created_at: %w", err)
+		}
+		out = append(out, fb)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate feedback: %w", err)
+	}
+	return out, nil
+}
+
+// GetFeedbackByTier counts votes recorded at or after since, grouped by the
+// tier the post was scored into. Unscored and pruned posts are skipped.
+func (s *Store) GetFeedbackByTier(ctx context.Context, since time.Time) ([]TierFeedback, error) {
+	if s == nil || s.db == nil {
+		return nil, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT s.tier,
+			SUM(CASE WHEN f.vote > 0 THEN 1 ELSE 0 END),
+			SUM(CASE WHEN f.vote < 0 THEN 1 ELSE 0 END)
+		FROM feedback f
+		JOIN posts p ON p.id = f.post_id
+		JOIN scores s ON s.post_id = p.id`+s.scoreProfile()+`
+		WHERE f.created_at >= ?`+liveClause+`
+		GROUP BY s.tier
+		ORDER BY s.tier`,
+		formatTime(since),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get feedback by tier: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []TierFeedback
+	for rows.Next() {
+		var tf TierFeedback
+		if err := rows.Scan(&tf.Tier, &tf.Up, &tf.Down); err != nil {
+			return nil, fmt.Errorf("scan feedback by tier: %w", err)
+		}
+		out = append(out, tf)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate feedback by tier: %w", err)
+	}
+	return out, nil
+}
+
+// FeedbackPost is a voted post with its score (nil if unscored).
+type FeedbackPost struct {
+	PostWithScore
+	Feedback Feedback
+}
+
+// GetFeedbackPosts returns voted posts with their scores for votes recorded
+// at or after since (zero means all), newest vote first.
+func (s *Store) GetFeedbackPosts(ctx context.Context, since time.Time) ([]FeedbackPost, error) {
+	if s == nil || s.db == nil {
+		return nil, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT p.id, p.source, p.channel, p.external_id, p.text, p.snippet, p.text_hash, p.url, p.author, p.language, p.posted_at, p.fetched_at,
+			s.score, s.labels, s.tier, s.scored_at, s.explanation, s.text_hash, s.taste_hash,
+			f.vote, f.reason, f.created_at
+		FROM feedback f
+		JOIN posts p ON p.id = f.post_id
+		LEFT JOIN scores s ON s.post_id = p.id`+s.scoreProfile()+`
+		WHERE f.created_at >= ?`+liveClause+`
+		ORDER BY f.created_at DESC, p.id DESC`,
+		formatTime(since),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get feedback posts: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []FeedbackPost
+	for rows.Next() {
+		fs := &feedbackScanner{rows: rows}
+		post, score, err := scanPostWithScore(fs)
+		if err != nil {
+			return nil, err
+		}
+		fb := Feedback{PostID: post.ID, Vote: fs.vote, Reason: fs.reason.String}
+		if fb.CreatedAt, err = parseTime(fs.createdAt); err != nil {
+			return nil, fmt.Errorf("parse created_at: %w", err)
+		}
+		out = append(out, FeedbackPost{PostWithScore: PostWithScore{Post: post, Score: score}, Feedback: fb})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate feedback posts: %w", err)
+	}
+	return out, nil
+}
+
+// feedbackScanner appends the feedback columns to a post-with-score scan.
+type feedbackScanner struct {
+	rows      *sql.Rows
+	vote      int
+	reason    sql.NullString
+	createdAt string
+}
+
+func (fs *feedbackScanner) Scan(dest ...any) error {
+	return fs.rows.Scan(append(dest, &fs.vote, &fs.reason, &fs.createdAt)...)
+}
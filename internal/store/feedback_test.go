@@ -0,0 +1,114 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSaveFeedback(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	cve, helm := insertSearchFixtures(t, st)
+	at := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	if err := st.SaveFeedback(ctx, Feedback{PostID: 9999, Vote: FeedbackUp, CreatedAt: at}); err == nil {
+		t.Error("expected error for missing post")
+	}
+	if err := st.SaveFeedback(ctx, Feedback{PostID: cve.ID, Vote: 0, CreatedAt: at}); err == nil {
+		t.Error("expected error for invalid vote")
+	}
+
+	if err := st.SaveFeedback(ctx, Feedback{PostID: cve.ID, Vote: FeedbackDown, Reason: "old news", CreatedAt: at}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	// A second vote replaces the first.
+	if err := st.SaveFeedback(ctx, Feedback{PostID: cve.ID, Vote: FeedbackUp, CreatedAt: at.Add(time.Hour)}); err != nil {
+		t.Fatalf("save again: %v", err)
+	}
+	if err := st.SaveFeedback(ctx, Feedback{PostID: helm.ID, Vote: FeedbackDown, CreatedAt: at}); err != nil {
+		t.Fatalf("save helm: %v", err)
+	}
+
+	all, err := st.GetFeedback(ctx, time.Time{})
+	if err != nil {
+		t.Fatalf("get feedback: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("feedback = %+v, want 2 rows", all)
+	}
+	if all[0].PostID != cve.ID || all[0].Vote != FeedbackUp || all[0].Reason != "" {
+		t.Errorf("latest vote = %+v, want cve up with no reason", all[0])
+	}
+
+	recent, err := st.GetFeedback(ctx, at.Add(30*time.Minute))
+	if err != nil {
+		t.Fatalf("get recent feedback: %v", err)
+	}
+	if len(recent) != 1 {
+		t.Errorf("recent feedback = %+v, want 1 row", recent)
+	}
+}
+
+func TestGetFeedbackByTier(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	cve, helm := insertSearchFixtures(t, st)
+	now := time.Now()
+
+	if err := st.SaveScore(ctx, Score{PostID: cve.ID, Score: 9, Tier: "read_now", ScoredAt: now}); err != nil {
+		t.Fatalf("save score: %v", err)
+	}
+	if err := st.SaveFeedback(ctx, Feedback{PostID: cve.ID, Vote: FeedbackUp, CreatedAt: now}); err != nil {
+		t.Fatalf("save feedback: %v", err)
+	}
+	// Unscored posts are not counted.
+	if err := st.SaveFeedback(ctx, Feedback{PostID: helm.ID, Vote: FeedbackDown, CreatedAt: now}); err != nil {
+		t.Fatalf("save feedback: %v", err)
+	}
+
+	tiers, err := st.GetFeedbackByTier(ctx, time.Time{})
+	if err != nil {
+		t.Fatalf("by tier: %v", err)
+	}
+	if len(tiers) != 1 || tiers[0] != (TierFeedback{Tier: "read_now", Up: 1}) {
+		t.Errorf("tiers = %+v", tiers)
+	}
+
+	// Nor are pruned ones.
+	if _, err := st.db.Exec("UPDATE posts SET deleted_at = ? WHERE id = ?", formatTime(now), cve.ID); err != nil {
+		t.Fatalf("tombstone: %v", err)
+	}
+	if tiers, err := st.GetFeedbackByTier(ctx, time.Time{}); err != nil || len(tiers) != 0 {
+		t.Errorf("tiers after prune = %+v, %v; want none", tiers, err)
+	}
+}
+
+func TestGetFeedbackPosts(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	cve, _ := insertSearchFixtures(t, st)
+	now := time.Now()
+
+	if err := st.SaveScore(ctx, Score{PostID: cve.ID, Score: 9, Tier: "read_now", ScoredAt: now}); err != nil {
+		t.Fatalf("save score: %v", err)
+	}
+	if err := st.SaveFeedback(ctx, Feedback{PostID: cve.ID, Vote: FeedbackDown, Reason: "noise", CreatedAt: now}); err != nil {
+		t.Fatalf("save feedback: %v", err)
+	}
+
+	posts, err := st.GetFeedbackPosts(ctx, time.Time{})
+	if err != nil {
+		t.Fatalf("get feedback posts: %v", err)
+	}
+	if len(posts) != 1 {
+		t.Fatalf("posts = %d, want 1", len(posts))
+	}
+	fp := posts[0]
+	if fp.Post.ID != cve.ID || fp.Post.Text == "" || fp.Score == nil || fp.Score.Tier != "read_now" {
+		t.Errorf("post = %+v", fp.PostWithScore)
+	}
+	if fp.Feedback.Vote != FeedbackDown || fp.Feedback.Reason != "noise" || fp.Feedback.CreatedAt.IsZero() {
+		t.Errorf("feedback = %+v", fp.Feedback)
+	}
+}
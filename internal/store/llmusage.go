@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// LLMUsage is the LLM API calls made with one model on one UTC day and the
+// tokens they used.
+type LLMUsage struct {
+	Day              time.Time
+	Model            string
+	Calls            int64
+	PromptTokens     int64
+	CompletionTokens int64
+}
+
+// AddLLMUsage records one API call with model at at, which used prompt and
+// completion tokens.
+func (s *Store) AddLLMUsage(ctx context.Context, at time.Time, model string, prompt, completion int64) error {
+	if s == nil || s.db == nil {
+		return errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO llm_usage(day, model, calls, prompt_tokens, completion_tokens) VALUES(?, ?, 1, ?, ?)
+		ON CONFLICT(day, model) DO UPDATE SET
+			calls = llm_usage.calls + 1,
+			prompt_tokens = llm_usage.prompt_tokens + excluded.prompt_tokens,
+			completion_tokens = llm_usage.completion_tokens + excluded.completion_tokens`,
+		usageDay(at), model, prompt, completion,
+	); err != nil {
+		return fmt.Errorf("add llm usage: %w", err)
+	}
+	return nil
+}
+
+// GetLLMUsage returns the LLM usage from the day of since onwards (zero
+// means all), by day and then model.
+func (s *Store) GetLLMUsage(ctx context.Context, since time.Time) ([]LLMUsage, error) {
+	if s == nil || s.db == nil {
+		return nil, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	from := ""
+	if !since.IsZero() {
+		from = usageDay(since)
+	}
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT day, model, calls, prompt_tokens, completion_tokens
+		FROM llm_usage
+		WHERE day >= ?
+		ORDER BY day, model`, from)
+	if err != nil {
+		return nil, fmt.Errorf("get llm usage: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var usage []LLMUsage
+	for rows.Next() {
+		var (
+			u   LLMUsage
+			day string
+		)
+		if err := rows.Scan(&day, &u.Model, &u.Calls, &u.PromptTokens, &u.CompletionTokens); err != nil {
+			return nil, fmt.Errorf("scan llm usage: %w", err)
+		}
+		if u.Day, err = time.Parse(time.DateOnly, day); err != nil {
+			return nil, fmt.Errorf("parse llm usage day: %w", err)
+		}
+		usage = append(usage, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate llm usage: %w", err)
+	}
+	return usage, nil
+}
@@ -0,0 +1,46 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLLMUsage(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	day1 := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	for _, c := range []struct {
+		at                 time.Time
+		model              string
+		prompt, completion int64
+	}{
+		{day1, "gpt-4.1-mini", 100, 20},
+		{day1.Add(time.Hour), "gpt-4.1-mini", 50, 10},
+		{day1, "gpt-4.1-nano", 10, 1},
+		{day2, "gpt-4.1-mini", 70, 15},
+	} {
+		if err := st.AddLLMUsage(ctx, c.at, c.model, c.prompt, c.completion); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+	}
+
+	all, err := st.GetLLMUsage(ctx, time.Time{})
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("usage = %+v, want 3 day/model rows", all)
+	}
+	want := LLMUsage{Day: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Model: "gpt-4.1-mini", Calls: 2, PromptTokens: 150, CompletionTokens: 30}
+	if all[0] != want || all[1].Model != "gpt-4.1-nano" || all[2].Day.Day() != 2 {
+		t.Errorf("usage = %+v", all)
+	}
+
+	recent, err := st.GetLLMUsage(ctx, day2)
+	if err != nil || len(recent) != 1 || recent[0].PromptTokens != 70 {
+		t.Errorf("since day 2 = %+v, %v", recent, err)
+	}
+}
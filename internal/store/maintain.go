@@ -0,0 +1,130 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// TableRows is the number of rows in one table.
+type TableRows struct {
+	Table string
+	Rows  int64
+}
+
+// Size returns the database size in bytes. For sqlite that is the main
+// file; pages held in the WAL are not counted.
+func (s *Store) Size(ctx context.Context) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var size int64
+	if err := s.db.QueryRowContext(ctx, s.backend.SizeQuery()).Scan(&size); err != nil {
+		return 0, fmt.Errorf("query database size: %w", err)
+	}
+	return size, nil
+}
+
+// TableRowCounts returns the row count of every store table, by name.
+func (s *Store) TableRowCounts(ctx context.Context) ([]TableRows, error) {
+	if s == nil || s.db == nil {
+		return nil, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	rows, err := s.db.QueryContext(ctx, s.backend.TablesQuery())
+	if err != nil {
+		return nil, fmt.Errorf("list tables: %w", err)
+	}
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("scan table name: %w", err)
+		}
+		tables = append(tables, name)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, fmt.Errorf("iterate tables: %w", err)
+	}
+	_ = rows.Close()
+
+	counts := make([]TableRows, 0, len(tables))
+	for _, name := range tables {
+		var n int64
+		// Table names come from the catalog, not user input.
+		if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM "`+name+`"`).Scan(&n); err != nil {
+			return nil, fmt.Errorf("count %s: %w", name, err)
+		}
+		counts = append(counts, TableRows{Table: name, Rows: n})
+	}
+	return counts, nil
+}
+
+// IntegrityCheck returns the problems the backend finds in the database,
+// nil if it is sound, or errors.ErrUnsupported if the backend cannot check.
+func (s *Store) IntegrityCheck(ctx context.Context) ([]string, error) {
+	if s == nil || s.db == nil {
+		return nil, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	problems, err := s.backend.IntegrityCheck(ctx, s.db.DB)
+	if err != nil {
+		if errors.Is(err, errors.ErrUnsupported) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("integrity check: %w", err)
+	}
+	return problems, nil
+}
+
+// Analyze refreshes the query planner's statistics.
+func (s *Store) Analyze(ctx context.Context) error {
+	if s == nil || s.db == nil {
+		return errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if _, err := s.db.ExecContext(ctx, "ANALYZE"); err != nil {
+		return fmt.Errorf("analyze: %w", err)
+	}
+	return nil
+}
+
+// Vacuum rebuilds the database to return space freed by pruning. On sqlite
+// it also truncates the WAL so the files on disk actually shrink. It needs
+// free disk space about the size of the database while it runs.
+func (s *Store) Vacuum(ctx context.Context) error {
+	if s == nil || s.db == nil {
+		return errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if s.DryRun() {
+		return errors.New("vacuum: not possible in a dry run")
+	}
+
+	if _, err := s.db.ExecContext(ctx, "VACUUM"); err != nil {
+		return fmt.Errorf("vacuum: %w", err)
+	}
+	if s.backend.Name() == "sqlite" {
+		if _, err := s.db.ExecContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+			return fmt.Errorf("checkpoint wal: %w", err)
+		}
+	}
+	return nil
+}
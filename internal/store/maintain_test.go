@@ -0,0 +1,62 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMaintenance(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	insertDedupFixtures(t, st)
+
+	counts, err := st.TableRowCounts(ctx)
+	if err != nil {
+		t.Fatalf("table row counts: %v", err)
+	}
+	rows := make(map[string]int64, len(counts))
+	for _, c := range counts {
+		rows[c.Table] = c.Rows
+	}
+	if rows["posts"] != 2 {
+		t.Errorf("posts rows = %d, want 2 (counts %+v)", rows["posts"], counts)
+	}
+	if _, ok := rows["scores"]; !ok {
+		t.Errorf("scores missing from %+v", counts)
+	}
+	for table := range rows {
+		if table == "posts_fts" || table == "sqlite_sequence" {
+			t.Errorf("internal table %s listed", table)
+		}
+	}
+
+	before, err := st.Size(ctx)
+	if err != nil {
+		t.Fatalf("size: %v", err)
+	}
+	if before <= 0 {
+		t.Errorf("size = %d, want > 0", before)
+	}
+
+	problems, err := st.IntegrityCheck(ctx)
+	if err != nil {
+		t.Fatalf("integrity check: %v", err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("problems = %v, want none", problems)
+	}
+
+	if err := st.Analyze(ctx); err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	if err := st.Vacuum(ctx); err != nil {
+		t.Fatalf("vacuum: %v", err)
+	}
+	after, err := st.Size(ctx)
+	if err != nil {
+		t.Fatalf("size after vacuum: %v", err)
+	}
+	if after <= 0 {
+		t.Errorf("size after vacuum = %d, want > 0", after)
+	}
+}
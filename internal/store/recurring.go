@@ -0,0 +1,58 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RepeatedText is a text a channel has posted more than once.
+type RepeatedText struct {
+	Source      string
+	Channel     string
+	TextHash    string
+	FirstPosted time.Time // the earliest stored copy
+}
+
+// GetRepeatedTexts returns the texts shared by more than one live post of
+// the same channel, which pruning bounds to the retention window. Posts
+// without text are left out.
+func (s *Store) GetRepeatedTexts(ctx context.Context) ([]RepeatedText, error) {
+	if s == nil || s.db == nil {
+		return nil, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT p.source, p.channel, p.text_hash, MIN(p.posted_at)
+		FROM posts p
+		WHERE (COALESCE(p.text, '') <> '' OR p.snippet <> '')`+liveClause+`
+		GROUP BY p.source, p.channel, p.text_hash
+		HAVING COUNT(*) > 1
+		ORDER BY p.source, p.channel, p.text_hash
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("get repeated texts: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var texts []RepeatedText
+	for rows.Next() {
+		var rt RepeatedText
+		var first string
+		if err := rows.Scan(&rt.Source, &rt.Channel, &rt.TextHash, &first); err != nil {
+			return nil, fmt.Errorf("scan repeated text: %w", err)
+		}
+		if rt.FirstPosted, err = parseTime(first); err != nil {
+			return nil, fmt.Errorf("parse posted_at: %w", err)
+		}
+		texts = append(texts, rt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate repeated texts: %w", err)
+	}
+	return texts, nil
+}
@@ -0,0 +1,41 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGetRepeatedTexts(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+
+	base := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	for _, p := range []struct {
+		channel, id, text string
+		at                time.Time
+	}{
+		{"standup", "1", "Daily standup notes", base.Add(24 * time.Hour)},
+		{"standup", "2", "Daily standup notes", base},
+		{"standup", "3", "Release 1.2 is out", base},
+		{"other", "1", "Daily standup notes", base},
+	} {
+		if _, err := st.InsertPost(ctx, PostInput{
+			Source: "rss", Channel: p.channel, ExternalID: p.id,
+			Text: p.text, PostedAt: p.at, FetchedAt: p.at,
+		}); err != nil {
+			t.Fatalf("insert %s/%s: %v", p.channel, p.id, err)
+		}
+	}
+
+	texts, err := st.GetRepeatedTexts(ctx)
+	if err != nil {
+		t.Fatalf("get repeated texts: %v", err)
+	}
+	if len(texts) != 1 {
+		t.Fatalf("repeated texts = %+v, want only standup's notes", texts)
+	}
+	if rt := texts[0]; rt.Channel != "standup" || !rt.FirstPosted.Equal(base) || rt.TextHash == "" {
+		t.Errorf("repeated text = %+v, want standup first posted %v", rt, base)
+	}
+}
@@ -0,0 +1,332 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RetentionRule keeps posts of one source, one channel, or one channel of a
+// source for Days days. An empty Source matches the channel on every source;
+// an empty Channel matches every channel of the source. Days <= 0 keeps
+// matching posts forever.
+type RetentionRule struct {
+	Source  string
+	Channel string
+	Days    int
+}
+
+// RetentionPolicy decides how many days an unstarred post is kept. The most
+// specific matching rule wins (source and channel, then channel, then
+// source); posts matching no rule are kept DefaultDays, or forever when
+// DefaultDays <= 0.
+type RetentionPolicy struct {
+	DefaultDays int
+	Rules       []RetentionRule
+}
+
+// Extend returns a copy of the policy keeping every post days longer.
+// Rules that keep posts forever are left alone.
+func (p RetentionPolicy) Extend(days int) RetentionPolicy {
+	ext := RetentionPolicy{Rules: make([]RetentionRule, len(p.Rules))}
+	if p.DefaultDays > 0 {
+		ext.DefaultDays = p.DefaultDays + days
+	}
+	for i, r := range p.Rules {
+		if r.Days > 0 {
+			r.Days += days
+		}
+		ext.Rules[i] = r
+	}
+	return ext
+}
+
+// specificity orders rules for matching: both fields set beats a channel
+// alone, which beats a source alone.
+func (r RetentionRule) specificity() int {
+	switch {
+	case r.Source != "" && r.Channel != "":
+		return 2
+	case r.Channel != "":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// cutoffExpr returns a SQL expression yielding the posted_at cutoff of each
+// row of posts, NULL for posts kept forever, with its arguments. ok is false
+// when the policy keeps everything.
+func (p RetentionPolicy) cutoffExpr(now time.Time) (expr string, args []any, ok bool) {
+	rules := make([]RetentionRule, 0, len(p.Rules))
+	for _, r := range p.Rules {
+		if r.Source == "" && r.Channel == "" {
+			continue
+		}
+		rules = append(rules, r)
+		ok = ok || r.Days > 0
+	}
+	ok = ok || p.DefaultDays > 0
+	if !ok {
+		return "", nil, false
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].specificity() > rules[j].specificity()
+	})
+
+	cutoff := func(days int) string {
+		return formatTime(now.AddDate(0, 0, -days))
+	}
+	if len(rules) == 0 {
+		return "?", []any{cutoff(p.DefaultDays)}, true
+	}
+
+	var b strings.Builder
+	b.WriteString("CASE")
+	for _, r := range rules {
+		var conds []string
+		if r.Source != "" {
+			conds = append(conds, "source = ?")
+			args = append(args, r.Source)
+		}
+		if r.Channel != "" {
+			conds = append(conds, "channel = ?")
+			args = append(args, r.Channel)
+		}
+		b.WriteString(" WHEN " + strings.Join(conds, " AND "))
+		if r.Days > 0 {
+			b.WriteString(" THEN ?")
+			args = append(args, cutoff(r.Days))
+		} else {
+			b.WriteString(" THEN NULL")
+		}
+	}
+	if p.DefaultDays > 0 {
+		b.WriteString(" ELSE ?")
+		args = append(args, cutoff(p.DefaultDays))
+	}
+	b.WriteString(" END")
+	return b.String(), args, true
+}
+
+// PruneWithPolicy tombstones unstarred posts older than the policy keeps
+// them. Tombstoned posts drop out of digests, search, and stats but keep
+// their text, score, and votes until Purge removes them; Restore brings them
+// back. Returns the number of posts tombstoned.
+func (s *Store) PruneWithPolicy(ctx context.Context, policy RetentionPolicy) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	cutoff, args, ok := policy.cutoffExpr(time.Now())
+	if !ok {
+		return 0, nil
+	}
+
+	// Starred posts are kept regardless of age.
+	res, err := s.db.ExecContext(ctx,
+		"UPDATE posts SET deleted_at = ? WHERE deleted_at IS NULL AND posted_at < "+cutoff+
+			" AND id NOT IN (SELECT post_id FROM stars)",
+		append([]any{formatTime(time.Now())}, args...)...,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("prune old posts: %w", err)
+	}
+
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
+// PruneImpact is what a retention policy would prune from one channel.
+type PruneImpact struct {
+	Source  string
+	Channel string
+	Posts   int64
+	Scores  int64
+	Bytes   int64 // stored text and snippet
+}
+
+// PruneSimulation is the effect a retention policy would have, computed
+// without changing anything.
+type PruneSimulation struct {
+	Channels   []PruneImpact // most posts first
+	LivePosts  int64         // posts not tombstoned
+	LiveBytes  int64         // their stored text and snippet
+	Tombstoned int64         // posts already pruned, awaiting Purge
+}
+
+// SimulatePrune reports, per channel, the posts and scores PruneWithPolicy
+// would tombstone for policy (and Purge would then delete), without
+// changing anything.
+func (s *Store) SimulatePrune(ctx context.Context, policy RetentionPolicy) (PruneSimulation, error) {
+	var sim PruneSimulation
+	if s == nil || s.db == nil {
+		return sim, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	const textBytes = "COALESCE(SUM(COALESCE(LENGTH(text), 0) + LENGTH(snippet)), 0)"
+	if err := s.db.QueryRowContext(ctx,
+		"SELECT COUNT(*), "+textBytes+" FROM posts WHERE deleted_at IS NULL",
+	).Scan(&sim.LivePosts, &sim.LiveBytes); err != nil {
+		return sim, fmt.Errorf("count live posts: %w", err)
+	}
+	tombstoned, err := s.Tombstoned(ctx)
+	if err != nil {
+		return sim, err
+	}
+	sim.Tombstoned = tombstoned
+
+	cutoff, args, ok := policy.cutoffExpr(time.Now())
+	if !ok {
+		return sim, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT source, channel, COUNT(*),
+			COALESCE(SUM(CASE WHEN id IN (SELECT post_id FROM scores) THEN 1 ELSE 0 END), 0),
+			`+textBytes+`
+		FROM posts
+		WHERE deleted_at IS NULL AND posted_at < `+cutoff+`
+			AND id NOT IN (SELECT post_id FROM stars)
+		GROUP BY source, channel
+		ORDER BY COUNT(*) DESC, source, channel`,
+		args...,
+	)
+	if err != nil {
+		return sim, fmt.Errorf("simulate prune: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var im PruneImpact
+		if err := rows.Scan(&im.Source, &im.Channel, &im.Posts, &im.Scores, &im.Bytes); err != nil {
+			return sim, fmt.Errorf("scan prune impact: %w", err)
+		}
+		sim.Channels = append(sim.Channels, im)
+	}
+	if err := rows.Err(); err != nil {
+		return sim, fmt.Errorf("iterate prune impact: %w", err)
+	}
+	return sim, nil
+}
+
+// Purge permanently deletes posts tombstoned at or before before, with
+// their scores, and LLM summaries saved at or before before. post_also_in,
+// read_state, digest_shown, and feedback rows are cascade-deleted. Returns
+// the number of posts removed.
+func (s *Store) Purge(ctx context.Context, before time.Time) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin purge transaction: %w", err)
+	}
+
+	const gone = "SELECT id FROM posts WHERE deleted_at IS NOT NULL AND deleted_at <= ?"
+	cutoff := formatTime(before)
+
+	// Delete scores first (no CASCADE on scores FK)
+	if _, err := tx.ExecContext(ctx, "DELETE FROM scores WHERE post_id IN ("+gone+")", cutoff); err != nil {
+		_ = tx.Rollback()
+		return 0, fmt.Errorf("purge scores: %w", err)
+	}
+
+	// Delete tombstoned posts (post_also_in cascades)
+	res, err := tx.ExecContext(ctx, "DELETE FROM posts WHERE id IN ("+gone+")", cutoff)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, fmt.Errorf("purge posts: %w", err)
+	}
+
+	// Summaries are keyed by text, not post; a post summarized again is
+	// summarized anew.
+	if _, err := tx.ExecContext(ctx, "DELETE FROM summaries WHERE created_at <= ?", cutoff); err != nil {
+		_ = tx.Rollback()
+		return 0, fmt.Errorf("purge summaries: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit purge: %w", err)
+	}
+
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
+// Restore clears every tombstone, bringing pruned posts back. Posts still
+// past their retention are pruned again on the next pull. Returns the number
+// of posts restored.
+func (s *Store) Restore(ctx context.Context) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	res, err := s.db.ExecContext(ctx, "UPDATE posts SET deleted_at = NULL WHERE deleted_at IS NOT NULL")
+	if err != nil {
+		return 0, fmt.Errorf("restore posts: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
+// Tombstoned returns the number of posts pruned but not yet purged.
+func (s *Store) Tombstoned(ctx context.Context) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var n int64
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM posts WHERE deleted_at IS NOT NULL").Scan(&n); err != nil {
+		return 0, fmt.Errorf("count tombstoned posts: %w", err)
+	}
+	return n, nil
+}
+
+// SlimWithPolicy drops the full text of unstarred posts older than the
+// policy allows, keeping the snippet, score, and metadata. Returns the
+// number of posts slimmed.
+func (s *Store) SlimWithPolicy(ctx context.Context, policy RetentionPolicy) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	cutoff, args, ok := policy.cutoffExpr(time.Now())
+	if !ok {
+		return 0, nil
+	}
+
+	res, err := s.db.ExecContext(ctx,
+		"UPDATE posts SET text = NULL WHERE posted_at < "+cutoff+
+			" AND text IS NOT NULL AND id NOT IN (SELECT post_id FROM stars)",
+		args...,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("slim old posts: %w", err)
+	}
+
+	n, _ := res.RowsAffected()
+	return n, nil
+}
@@ -0,0 +1,235 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestPruneWithPolicy(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	insert := func(source, channel string, age int) int64 {
+		t.Helper()
+		at := now.AddDate(0, 0, -age)
+		p, err := st.InsertPost(ctx, PostInput{
+			Source: source, Channel: channel, ExternalID: fmt.Sprint(source, channel, age),
+			Text: channel + " post", PostedAt: at, FetchedAt: at,
+		})
+		if err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+		return p.ID
+	}
+
+	advisory := insert("rss", "Security Advisories", 200) // channel rule: 365 days
+	insert("rss", "blog", 60)                             // default: 30 days
+	recentBlog := insert("rss", "blog", 10)
+	insert("reddit", "devops", 20)               // source rule: 14 days
+	pinned := insert("reddit", "kubernetes", 20) // source+channel rule: forever
+	redditAdvisory := insert("reddit", "Security Advisories", 200)
+
+	pruned, err := st.PruneWithPolicy(ctx, RetentionPolicy{
+		DefaultDays: 30,
+		Rules: []RetentionRule{
+			{Source: "reddit", Days: 14},
+			{Channel: "Security Advisories", Days: 365},
+			{Source: "reddit", Channel: "kubernetes"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if pruned != 2 {
+		t.Errorf("pruned = %d, want 2", pruned)
+	}
+
+	rows, err := st.db.QueryContext(ctx, "SELECT id FROM posts WHERE deleted_at IS NULL ORDER BY id")
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	defer func() { _ = rows.Close() }()
+	var kept []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		kept = append(kept, id)
+	}
+	want := []int64{advisory, recentBlog, pinned, redditAdvisory}
+	if len(kept) != len(want) {
+		t.Fatalf("kept = %v, want %v", kept, want)
+	}
+	for i := range want {
+		if kept[i] != want[i] {
+			t.Errorf("kept = %v, want %v", kept, want)
+			break
+		}
+	}
+}
+
+func TestSlimWithPolicy(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+
+	at := time.Now().UTC().AddDate(0, 0, -40)
+	for _, ch := range []string{"blog", "Security Advisories"} {
+		if _, err := st.InsertPost(ctx, PostInput{
+			Source: "rss", Channel: ch, ExternalID: ch, Text: ch + " post", PostedAt: at, FetchedAt: at,
+		}); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+	}
+
+	slimmed, err := st.SlimWithPolicy(ctx, RetentionPolicy{
+		DefaultDays: 30,
+		Rules:       []RetentionRule{{Channel: "Security Advisories", Days: 365}},
+	})
+	if err != nil {
+		t.Fatalf("slim: %v", err)
+	}
+	if slimmed != 1 {
+		t.Errorf("slimmed = %d, want 1", slimmed)
+	}
+}
+
+func TestRetentionPolicy_Extend(t *testing.T) {
+	p := RetentionPolicy{
+		DefaultDays: 30,
+		Rules:       []RetentionRule{{Source: "reddit", Days: 14}, {Channel: "pinned"}},
+	}
+	ext := p.Extend(10)
+	if ext.DefaultDays != 40 || ext.Rules[0].Days != 24 || ext.Rules[1].Days != 0 {
+		t.Errorf("extended = %+v", ext)
+	}
+	if p.Rules[0].Days != 14 {
+		t.Error("Extend modified the original policy")
+	}
+
+	if got := (RetentionPolicy{}).Extend(10); got.DefaultDays != 0 {
+		t.Errorf("keep-forever default extended to %d", got.DefaultDays)
+	}
+}
+
+func TestPurgeAndRestore(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+
+	old := time.Now().UTC().AddDate(0, 0, -60)
+	p, err := st.InsertPost(ctx, PostInput{
+		Source: "rss", Channel: "blog", ExternalID: "1", Text: "old post", PostedAt: old, FetchedAt: old,
+	})
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if err := st.SaveScore(ctx, Score{PostID: p.ID, Score: 5, Tier: "skim", ScoredAt: old}); err != nil {
+		t.Fatalf("save score: %v", err)
+	}
+
+	if _, err := st.PruneOld(ctx, 30); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if n, err := st.Tombstoned(ctx); err != nil || n != 1 {
+		t.Fatalf("tombstoned = %d, %v; want 1", n, err)
+	}
+
+	// Tombstones newer than the purge cutoff are kept.
+	if n, err := st.Purge(ctx, time.Now().Add(-time.Hour)); err != nil || n != 0 {
+		t.Errorf("purge before tombstone = %d, %v; want 0", n, err)
+	}
+
+	if n, err := st.Restore(ctx); err != nil || n != 1 {
+		t.Fatalf("restore = %d, %v; want 1", n, err)
+	}
+	got, err := st.GetPostByID(ctx, p.ID)
+	if err != nil {
+		t.Fatalf("get restored post: %v", err)
+	}
+	if got.Score == nil || got.Score.Score != 5 {
+		t.Errorf("restored score = %+v, want 5", got.Score)
+	}
+
+	if _, err := st.PruneOld(ctx, 30); err != nil {
+		t.Fatalf("prune again: %v", err)
+	}
+	if n, err := st.Purge(ctx, time.Now()); err != nil || n != 1 {
+		t.Errorf("purge = %d, %v; want 1", n, err)
+	}
+	if n, err := st.Tombstoned(ctx); err != nil || n != 0 {
+		t.Errorf("tombstoned after purge = %d, %v; want 0", n, err)
+	}
+}
+
+func TestSimulatePrune(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	insert := func(channel string, age int, text string) int64 {
+		t.Helper()
+		at := now.AddDate(0, 0, -age)
+		p, err := st.InsertPost(ctx, PostInput{
+			Source: "rss", Channel: channel, ExternalID: fmt.Sprint(channel, age, text),
+			Text: text, PostedAt: at, FetchedAt: at,
+		})
+		if err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+		return p.ID
+	}
+
+	oldBlog := insert("blog", 60, "old blog post")
+	insert("blog", 50, "older blog post two")
+	insert("blog", 5, "fresh blog post")
+	insert("news", 45, "old news")
+	starred := insert("news", 90, "kept by star")
+	if err := st.SaveScore(ctx, Score{PostID: oldBlog, Score: 1, Tier: "skim", ScoredAt: now}); err != nil {
+		t.Fatalf("save score: %v", err)
+	}
+	if err := st.Star(ctx, starred, now); err != nil {
+		t.Fatalf("star: %v", err)
+	}
+
+	policy := RetentionPolicy{DefaultDays: 30}
+	sim, err := st.SimulatePrune(ctx, policy)
+	if err != nil {
+		t.Fatalf("simulate: %v", err)
+	}
+	if sim.LivePosts != 5 || sim.Tombstoned != 0 || sim.LiveBytes == 0 {
+		t.Errorf("totals = %+v", sim)
+	}
+	if len(sim.Channels) != 2 {
+		t.Fatalf("channels = %+v", sim.Channels)
+	}
+	blog := sim.Channels[0]
+	if blog.Channel != "blog" || blog.Posts != 2 || blog.Scores != 1 {
+		t.Errorf("blog impact = %+v", blog)
+	}
+	// Snippets of short posts equal their text, so each counts twice.
+	if want := int64(2 * (len("old blog post") + len("older blog post two"))); blog.Bytes != want {
+		t.Errorf("blog bytes = %d, want %d", blog.Bytes, want)
+	}
+	if news := sim.Channels[1]; news.Channel != "news" || news.Posts != 1 || news.Scores != 0 {
+		t.Errorf("news impact = %+v", news)
+	}
+
+	// Nothing changed, and the real prune agrees.
+	pruned, err := st.PruneWithPolicy(ctx, policy)
+	if err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if pruned != 3 {
+		t.Errorf("pruned = %d, want the simulated 3", pruned)
+	}
+	if sim, err = st.SimulatePrune(ctx, policy); err != nil || len(sim.Channels) != 0 || sim.Tombstoned != 3 || sim.LivePosts != 2 {
+		t.Errorf("after prune = %+v, err %v", sim, err)
+	}
+
+	if sim, err = st.SimulatePrune(ctx, RetentionPolicy{}); err != nil || sim.Channels != nil || sim.LivePosts != 2 {
+		t.Errorf("keep-forever policy = %+v, err %v", sim, err)
+	}
+}
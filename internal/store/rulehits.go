@@ -0,0 +1,106 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RuleHit records when a taste rule with a cooldown last added points to a
+// post of a channel. Rule is the rule's key (see taste.RuleKey).
+type RuleHit struct {
+	Rule    string
+	Source  string
+	Channel string
+	HitAt   time.Time // the post's time
+}
+
+// SaveRuleHits stores hits of the store's profile, replacing the previous hit
+// of the same rule and channel.
+func (s *Store) SaveRuleHits(ctx context.Context, hits []RuleHit) error {
+	if s == nil || s.db == nil {
+		return errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if len(hits) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	for _, h := range hits {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO rule_hits(profile, rule, source, channel, hit_at) VALUES(?, ?, ?, ?, ?)
+			ON CONFLICT(profile, rule, source, channel) DO UPDATE SET hit_at = excluded.hit_at`,
+			s.profile, h.Rule, h.Source, h.Channel, formatTime(h.HitAt),
+		); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("save rule hit: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetRuleHits returns the stored rule hits of the store's profile ordered by
+// rule, source, and channel.
+func (s *Store) GetRuleHits(ctx context.Context) ([]RuleHit, error) {
+	if s == nil || s.db == nil {
+		return nil, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT rule, source, channel, hit_at FROM rule_hits WHERE profile = ? ORDER BY rule, source, channel", s.profile)
+	if err != nil {
+		return nil, fmt.Errorf("get rule hits: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []RuleHit
+	for rows.Next() {
+		var (
+			h     RuleHit
+			hitAt string
+		)
+		if err := rows.Scan(&h.Rule, &h.Source, &h.Channel, &hitAt); err != nil {
+			return nil, fmt.Errorf("scan rule hit: %w", err)
+		}
+		if h.HitAt, err = parseTime(hitAt); err != nil {
+			return nil, fmt.Errorf("parse hit_at: %w", err)
+		}
+		out = append(out, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rule hits: %w", err)
+	}
+	return out, nil
+}
+
+// DeleteRuleHits forgets the rule hits of the store's profile, so that
+// rescoring applies cooldowns afresh. Returns the number of rows deleted.
+func (s *Store) DeleteRuleHits(ctx context.Context) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM rule_hits WHERE profile = ?", s.profile)
+	if err != nil {
+		return 0, fmt.Errorf("delete rule hits: %w", err)
+	}
+
+	n, _ := res.RowsAffected()
+	return n, nil
+}
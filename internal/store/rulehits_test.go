@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSaveAndGetRuleHits(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	at := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	if err := st.SaveRuleHits(ctx, []RuleHit{
+		{Rule: "certificate expired", Source: "telegram", Channel: "@alerts", HitAt: at},
+		{Rule: "certificate expired", Source: "rss", Channel: "status", HitAt: at},
+	}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	// A later hit replaces the earlier one.
+	later := at.Add(25 * time.Hour)
+	if err := st.SaveRuleHits(ctx, []RuleHit{
+		{Rule: "certificate expired", Source: "telegram", Channel: "@alerts", HitAt: later},
+	}); err != nil {
+		t.Fatalf("save again: %v", err)
+	}
+
+	hits, err := st.GetRuleHits(ctx)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if len(hits) != 2 || hits[0].Source != "rss" || !hits[0].HitAt.Equal(at) {
+		t.Fatalf("hits = %+v", hits)
+	}
+	if hits[1].Channel != "@alerts" || !hits[1].HitAt.Equal(later) {
+		t.Errorf("replaced hit = %+v", hits[1])
+	}
+
+	n, err := st.DeleteRuleHits(ctx)
+	if err != nil || n != 2 {
+		t.Fatalf("delete = %d, %v; want 2", n, err)
+	}
+	if hits, _ := st.GetRuleHits(ctx); len(hits) != 0 {
+		t.Errorf("hits after delete = %+v", hits)
+	}
+}
+
+func TestRuleHits_PerProfile(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	at := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	if err := st.SaveRuleHits(ctx, []RuleHit{{Rule: "outage", Source: "rss", Channel: "status", HitAt: at}}); err != nil {
+		t.Fatalf("save default: %v", err)
+	}
+	st.SetProfile("work")
+	if err := st.SaveRuleHits(ctx, []RuleHit{{Rule: "outage", Source: "rss", Channel: "status", HitAt: at.Add(time.Hour)}}); err != nil {
+		t.Fatalf("save work: %v", err)
+	}
+	if n, err := st.DeleteRuleHits(ctx); err != nil || n != 1 {
+		t.Fatalf("delete work = %d, %v; want 1", n, err)
+	}
+
+	st.SetProfile("")
+	hits, err := st.GetRuleHits(ctx)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if len(hits) != 1 || !hits[0].HitAt.Equal(at) {
+		t.Errorf("default profile hits = %+v, want the one at %v", hits, at)
+	}
+}
+
+func TestRuleHits_NilStore(t *testing.T) {
+	var st *Store
+	if err := st.SaveRuleHits(context.Background(), []RuleHit{{Rule: "x"}}); err == nil {
+		t.Error("expected error from nil store")
+	}
+	if _, err := st.GetRuleHits(context.Background()); err == nil {
+		t.Error("expected error from nil store")
+	}
+	if _, err := st.DeleteRuleHits(context.Background()); err == nil {
+		t.Error("expected error from nil store")
+	}
+}
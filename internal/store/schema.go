@@ -13,52 +13,257 @@ import (
 //go:embed schema.sql
 var schemaSQL string
 
-const schemaVersion = 2
+//go:embed schema_postgres.sql
+var schemaPostgresSQL string
 
-func migrate(ctx context.Context, db *sql.DB) error {
+const schemaVersion = 17
+
+// ftsSchemaVersion is the first version with the posts_fts index. Older
+// databases get the index backfilled from existing posts on upgrade.
+const ftsSchemaVersion = 4
+
+// simhashSchemaVersion is the first version with posts.simhash. Older
+// databases get the column added; Deduplicate fills it in for old rows.
+const simhashSchemaVersion = 9
+
+// canonicalURLSchemaVersion is the first version with posts.canonical_url,
+// added and filled in the same way.
+const canonicalURLSchemaVersion = 10
+
+// scoreHashSchemaVersion is the first version with scores.text_hash. Older
+// scores keep it NULL and are never reported as changed.
+const scoreHashSchemaVersion = 11
+
+// tombstoneSchemaVersion is the first version with posts.deleted_at, set by
+// pruning instead of deleting rows. Older databases have no tombstones.
+const tombstoneSchemaVersion = 12
+
+// authorSchemaVersion is the first version with posts.author. Older posts
+// keep it NULL until they are fetched again.
+const authorSchemaVersion = 13
+
+// profileSchemaVersion is the first version with scores.profile, part of
+// the primary key. Older scores become the default profile's.
+const profileSchemaVersion = 14
+
+// languageSchemaVersion is the first version with posts.language. Older
+// posts keep it NULL until they are fetched again; scoring detects it.
+const languageSchemaVersion = 15
+
+// tasteHashSchemaVersion is the first version with scores.taste_hash and the
+// taste_snapshots table. Older scores keep it NULL, so an incremental
+// rescore rescores them.
+const tasteHashSchemaVersion = 16
+
+// ruleHitProfileSchemaVersion is the first version with rule_hits.profile,
+// part of the primary key. Older hits become the default profile's.
+const ruleHitProfileSchemaVersion = 17
+
+// migrate creates or upgrades the SQLite schema in tx; the caller commits.
+func migrate(ctx context.Context, tx *sql.Tx) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
-	tx, err := db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("begin transaction: %w", err)
-	}
-
 	if _, err := tx.ExecContext(ctx, schemaSQL); err != nil {
-		_ = tx.Rollback()
 		return fmt.Errorf("apply schema: %w", err)
 	}
 
 	var versionStr string
-	err = tx.QueryRowContext(ctx, "SELECT value FROM metadata WHERE key = 'schema_version'").Scan(&versionStr)
+	err := tx.QueryRowContext(ctx, "SELECT value FROM metadata WHERE key = 'schema_version'").Scan(&versionStr)
 	if errors.Is(err, sql.ErrNoRows) {
 		if _, err := tx.ExecContext(ctx, "INSERT INTO metadata(key, value) VALUES('schema_version', ?)", strconv.Itoa(schemaVersion)); err != nil {
-			_ = tx.Rollback()
 			return fmt.Errorf("insert schema version: %w", err)
 		}
-		return tx.Commit()
+		return nil
 	}
 	if err != nil {
-		_ = tx.Rollback()
 		return fmt.Errorf("read schema version: %w", err)
 	}
 
 	version, err := strconv.Atoi(versionStr)
 	if err != nil {
-		_ = tx.Rollback()
 		return fmt.Errorf("parse schema version: %w", err)
 	}
 	if version > schemaVersion {
-		_ = tx.Rollback()
 		return fmt.Errorf("database schema version %d is newer than supported %d", version, schemaVersion)
 	}
+	if version < ftsSchemaVersion {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO posts_fts(posts_fts) VALUES('rebuild')"); err != nil {
+			return fmt.Errorf("rebuild fts index: %w", err)
+		}
+	}
+	if version < simhashSchemaVersion {
+		if err := addColumn(ctx, tx, "posts", "simhash", "INTEGER"); err != nil {
+			return err
+		}
+	}
+	if version < canonicalURLSchemaVersion {
+		if err := addColumn(ctx, tx, "posts", "canonical_url", "TEXT"); err != nil {
+			return err
+		}
+	}
+	if version < scoreHashSchemaVersion {
+		if err := addColumn(ctx, tx, "scores", "text_hash", "TEXT"); err != nil {
+			return err
+		}
+	}
+	if version < tombstoneSchemaVersion {
+		if err := addColumn(ctx, tx, "posts", "deleted_at", "DATETIME"); err != nil {
+			return err
+		}
+	}
+	if version < authorSchemaVersion {
+		if err := addColumn(ctx, tx, "posts", "author", "TEXT"); err != nil {
+			return err
+		}
+	}
+	if version < profileSchemaVersion {
+		if err := addScoreProfile(ctx, tx); err != nil {
+			return err
+		}
+	}
+	if version < languageSchemaVersion {
+		if err := addColumn(ctx, tx, "posts", "language", "TEXT"); err != nil {
+			return err
+		}
+	}
+	if version < tasteHashSchemaVersion {
+		if err := addColumn(ctx, tx, "scores", "taste_hash", "TEXT"); err != nil {
+			return err
+		}
+	}
+	if version < ruleHitProfileSchemaVersion {
+		if err := addRuleHitProfile(ctx, tx); err != nil {
+			return err
+		}
+	}
 	if version < schemaVersion {
 		if _, err := tx.ExecContext(ctx, "UPDATE metadata SET value = ? WHERE key = 'schema_version'", strconv.Itoa(schemaVersion)); err != nil {
-			_ = tx.Rollback()
 			return fmt.Errorf("update schema version: %w", err)
 		}
 	}
 
-	return tx.Commit()
+	return nil
+}
+
+// addColumn adds a column to an existing SQLite table unless it is already
+// there (a database re-created from the current schema has it).
+func addColumn(ctx context.Context, tx *sql.Tx, table, column, decl string) error {
+	var n int
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&n); err != nil {
+		return fmt.Errorf("check %s.%s column: %w", table, column, err)
+	}
+	if n > 0 {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, "ALTER TABLE "+table+" ADD COLUMN "+column+" "+decl); err != nil {
+		return fmt.Errorf("add %s.%s column: %w", table, column, err)
+	}
+	return nil
+}
+
+// addScoreProfile rebuilds the scores table with the profile column in its
+// primary key, which SQLite cannot add in place. Existing scores become the
+// default profile's.
+func addScoreProfile(ctx context.Context, tx *sql.Tx) error {
+	var n int
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM pragma_table_info('scores') WHERE name = 'profile'").Scan(&n); err != nil {
+		return fmt.Errorf("check scores.profile column: %w", err)
+	}
+	if n > 0 {
+		return nil
+	}
+	for _, stmt := range []string{
+		`CREATE TABLE scores_profiled (
+			post_id      INTEGER NOT NULL REFERENCES posts(id),
+			profile      TEXT NOT NULL DEFAULT '',
+			score        INTEGER NOT NULL DEFAULT 0,
+			labels       TEXT,
+			tier         TEXT NOT NULL DEFAULT 'ignore',
+			scored_at    DATETIME NOT NULL,
+			explanation  TEXT,
+			text_hash    TEXT,
+			PRIMARY KEY (post_id, profile)
+		)`,
+		`INSERT INTO scores_profiled (post_id, score, labels, tier, scored_at, explanation, text_hash)
+			SELECT post_id, score, labels, tier, scored_at, explanation, text_hash FROM scores`,
+		"DROP TABLE scores",
+		"ALTER TABLE scores_profiled RENAME TO scores",
+		"CREATE INDEX IF NOT EXISTS idx_scores_tier ON scores(tier)",
+	} {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("add scores.profile column: %w", err)
+		}
+	}
+	return nil
+}
+
+// addRuleHitProfile rebuilds the rule_hits table with the profile column in
+// its primary key. Existing hits become the default profile's.
+func addRuleHitProfile(ctx context.Context, tx *sql.Tx) error {
+	var n int
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM pragma_table_info('rule_hits') WHERE name = 'profile'").Scan(&n); err != nil {
+		return fmt.Errorf("check rule_hits.profile column: %w", err)
+	}
+	if n > 0 {
+		return nil
+	}
+	for _, stmt := range []string{
+		`CREATE TABLE rule_hits_profiled (
+			profile  TEXT NOT NULL DEFAULT '',
+			rule     TEXT NOT NULL,
+			source   TEXT NOT NULL,
+			channel  TEXT NOT NULL,
+			hit_at   DATETIME NOT NULL,
+			PRIMARY KEY(profile, rule, source, channel)
+		)`,
+		`INSERT INTO rule_hits_profiled (rule, source, channel, hit_at)
+			SELECT rule, source, channel, hit_at FROM rule_hits`,
+		"DROP TABLE rule_hits",
+		"ALTER TABLE rule_hits_profiled RENAME TO rule_hits",
+	} {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("add rule_hits.profile column: %w", err)
+		}
+	}
+	return nil
+}
+
+// migratePostgres applies the PostgreSQL schema in tx. Every table is
+// created with IF NOT EXISTS, so upgrades only need the recorded version
+// bumped.
+func migratePostgres(ctx context.Context, tx *sql.Tx) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if _, err := tx.ExecContext(ctx, schemaPostgresSQL); err != nil {
+		return fmt.Errorf("apply schema: %w", err)
+	}
+
+	var versionStr string
+	err := tx.QueryRowContext(ctx, "SELECT value FROM metadata WHERE key = 'schema_version'").Scan(&versionStr)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	if err == nil {
+		version, err := strconv.Atoi(versionStr)
+		if err != nil {
+			return fmt.Errorf("parse schema version: %w", err)
+		}
+		if version > schemaVersion {
+			return fmt.Errorf("database schema version %d is newer than supported %d", version, schemaVersion)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO metadata(key, value) VALUES('schema_version', $1)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value`,
+		strconv.Itoa(schemaVersion),
+	); err != nil {
+		return fmt.Errorf("update schema version: %w", err)
+	}
+
+	return nil
 }
@@ -8,19 +8,29 @@ CREATE TABLE IF NOT EXISTS posts (
     text         TEXT,
     snippet      TEXT NOT NULL,
     text_hash    TEXT NOT NULL,
+    simhash      INTEGER,
     url          TEXT,
+    canonical_url TEXT,
+    author       TEXT,
+    language     TEXT,
     posted_at    DATETIME NOT NULL,
     fetched_at   DATETIME NOT NULL,
+    deleted_at   DATETIME,
     UNIQUE(source, channel, external_id)
 );
 
+-- One score per post and taste profile; '' is the default profile.
 CREATE TABLE IF NOT EXISTS scores (
-    post_id      INTEGER PRIMARY KEY REFERENCES posts(id),
+    post_id      INTEGER NOT NULL REFERENCES posts(id),
+    profile      TEXT NOT NULL DEFAULT '',
     score        INTEGER NOT NULL DEFAULT 0,
     labels       TEXT,
     tier         TEXT NOT NULL DEFAULT 'ignore',
     scored_at    DATETIME NOT NULL,
-    explanation  TEXT
+    explanation  TEXT,
+    text_hash    TEXT,
+    taste_hash   TEXT,
+    PRIMARY KEY (post_id, profile)
 );
 
 CREATE TABLE IF NOT EXISTS post_also_in (
@@ -30,12 +40,168 @@ CREATE TABLE IF NOT EXISTS post_also_in (
     UNIQUE(post_id, source, channel)
 );
 
+CREATE TABLE IF NOT EXISTS read_state (
+    post_id  INTEGER PRIMARY KEY REFERENCES posts(id) ON DELETE CASCADE,
+    read_at  DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS stars (
+    post_id     INTEGER PRIMARY KEY REFERENCES posts(id) ON DELETE CASCADE,
+    starred_at  DATETIME NOT NULL
+);
+
+-- Posts a digest listed as read_now, and when it first did, for the
+-- "Still unread" section.
+CREATE TABLE IF NOT EXISTS digest_shown (
+    post_id  INTEGER PRIMARY KEY REFERENCES posts(id) ON DELETE CASCADE,
+    shown_at DATETIME NOT NULL
+);
+
+-- The last verify (entropia) result for each post, shown in digests.
+CREATE TABLE IF NOT EXISTS verifications (
+    post_id     INTEGER PRIMARY KEY REFERENCES posts(id) ON DELETE CASCADE,
+    support     INTEGER NOT NULL,
+    confidence  TEXT NOT NULL,
+    conflict    INTEGER NOT NULL,
+    signals     TEXT NOT NULL,
+    checked_at  DATETIME NOT NULL
+);
+
+-- Embeddings of post text, per model, for similar, semantic search and
+-- semantic dedup.
+CREATE TABLE IF NOT EXISTS embeddings (
+    post_id     INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    model       TEXT NOT NULL,
+    vector      BLOB NOT NULL,
+    created_at  DATETIME NOT NULL,
+    PRIMARY KEY (post_id, model)
+);
+
+CREATE TABLE IF NOT EXISTS feedback (
+    post_id     INTEGER PRIMARY KEY REFERENCES posts(id) ON DELETE CASCADE,
+    vote        INTEGER NOT NULL,
+    reason      TEXT,
+    created_at  DATETIME NOT NULL
+);
+
+-- Text blocks repeated across most of a channel's recent posts (footers,
+-- promos), relearned on every pull and ignored when scoring.
+CREATE TABLE IF NOT EXISTS boilerplate (
+    source      TEXT NOT NULL,
+    channel     TEXT NOT NULL,
+    block       TEXT NOT NULL,
+    posts       INTEGER NOT NULL,
+    sample      INTEGER NOT NULL,
+    learned_at  DATETIME NOT NULL,
+    PRIMARY KEY(source, channel, block)
+);
+
+-- When each taste rule with a cooldown last added points in a channel, per
+-- taste profile.
+CREATE TABLE IF NOT EXISTS rule_hits (
+    profile  TEXT NOT NULL DEFAULT '',
+    rule     TEXT NOT NULL,
+    source   TEXT NOT NULL,
+    channel  TEXT NOT NULL,
+    hit_at   DATETIME NOT NULL,
+    PRIMARY KEY(profile, rule, source, channel)
+);
+
+-- Local usage counters per UTC day (digests, reads, stars), for stats --me.
+CREATE TABLE IF NOT EXISTS usage_counters (
+    day      TEXT NOT NULL,
+    counter  TEXT NOT NULL,
+    value    INTEGER NOT NULL,
+    PRIMARY KEY(day, counter)
+);
+
+-- LLM API calls and the tokens they used, per UTC day and model, for costs
+-- and summarize.llm.monthly_budget. Kept like usage_counters.
+CREATE TABLE IF NOT EXISTS llm_usage (
+    day                TEXT NOT NULL,
+    model              TEXT NOT NULL,
+    calls              INTEGER NOT NULL,
+    prompt_tokens      INTEGER NOT NULL,
+    completion_tokens  INTEGER NOT NULL,
+    PRIMARY KEY(day, model)
+);
+
+-- Digests kept by digest --save, listed and re-rendered by history.
+CREATE TABLE IF NOT EXISTS digests (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at   DATETIME NOT NULL,
+    since_secs   INTEGER NOT NULL,
+    format       TEXT NOT NULL,
+    total_posts  INTEGER NOT NULL,
+    counts       TEXT NOT NULL,
+    output_hash  TEXT NOT NULL,
+    input        TEXT NOT NULL
+);
+
+-- Posts each saved digest listed, for digest --new-only.
+CREATE TABLE IF NOT EXISTS digest_items (
+    digest_id  INTEGER NOT NULL REFERENCES digests(id) ON DELETE CASCADE,
+    post_id    INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    PRIMARY KEY(digest_id, post_id)
+);
+
 CREATE TABLE IF NOT EXISTS metadata (
     key   TEXT PRIMARY KEY,
     value TEXT NOT NULL
 );
 
+-- Each taste profile scores were computed with, by scores.taste_hash, as
+-- YAML, so an incremental rescore can diff it against the current one.
+CREATE TABLE IF NOT EXISTS taste_snapshots (
+    hash       TEXT PRIMARY KEY,
+    profile    TEXT NOT NULL,
+    saved_at   DATETIME NOT NULL
+);
+
+-- LLM summaries by the sha256 of the summarized text and the model, so
+-- regenerating a digest does not call the API again for the same posts.
+CREATE TABLE IF NOT EXISTS summaries (
+    text_hash  TEXT NOT NULL,
+    model      TEXT NOT NULL,
+    bullets    TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY(text_hash, model)
+);
+
+CREATE TABLE IF NOT EXISTS feed_status (
+    source         TEXT NOT NULL,
+    feed           TEXT NOT NULL,
+    last_error     TEXT,
+    failing_since  DATETIME,
+    last_ok_at     DATETIME,
+    checked_at     DATETIME NOT NULL,
+    PRIMARY KEY(source, feed)
+);
+
+-- Full-text index over post text, kept in sync by triggers.
+CREATE VIRTUAL TABLE IF NOT EXISTS posts_fts USING fts5(
+    text, snippet, content='posts', content_rowid='id'
+);
+
+CREATE TRIGGER IF NOT EXISTS posts_fts_insert AFTER INSERT ON posts BEGIN
+    INSERT INTO posts_fts(rowid, text, snippet) VALUES (new.id, new.text, new.snippet);
+END;
+
+CREATE TRIGGER IF NOT EXISTS posts_fts_delete AFTER DELETE ON posts BEGIN
+    INSERT INTO posts_fts(posts_fts, rowid, text, snippet) VALUES ('delete', old.id, old.text, old.snippet);
+END;
+
+CREATE TRIGGER IF NOT EXISTS posts_fts_update AFTER UPDATE OF text, snippet ON posts BEGIN
+    INSERT INTO posts_fts(posts_fts, rowid, text, snippet) VALUES ('delete', old.id, old.text, old.snippet);
+    INSERT INTO posts_fts(rowid, text, snippet) VALUES (new.id, new.text, new.snippet);
+END;
+
 CREATE INDEX IF NOT EXISTS idx_posts_posted_at ON posts(posted_at);
+-- Time windows read the effective time; the expression must match
+-- Store.effectiveTime in store.go.
+CREATE INDEX IF NOT EXISTS idx_posts_effective_at ON posts(MIN(posted_at, fetched_at));
 CREATE INDEX IF NOT EXISTS idx_posts_text_hash ON posts(text_hash);
 CREATE INDEX IF NOT EXISTS idx_posts_source_channel ON posts(source, channel);
 CREATE INDEX IF NOT EXISTS idx_scores_tier ON scores(tier);
+CREATE INDEX IF NOT EXISTS idx_digests_created_at ON digests(created_at);
+CREATE INDEX IF NOT EXISTS idx_digest_items_post_id ON digest_items(post_id);
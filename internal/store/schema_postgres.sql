@@ -0,0 +1,223 @@
+CREATE TABLE IF NOT EXISTS posts (
+    id           BIGSERIAL PRIMARY KEY,
+    source       TEXT NOT NULL,
+    channel      TEXT NOT NULL,
+    external_id  TEXT NOT NULL,
+    text         TEXT,
+    snippet      TEXT NOT NULL,
+    text_hash    TEXT NOT NULL,
+    simhash      BIGINT,
+    url          TEXT,
+    canonical_url TEXT,
+    author       TEXT,
+    language     TEXT,
+    posted_at    TEXT NOT NULL,
+    fetched_at   TEXT NOT NULL,
+    deleted_at   TEXT,
+    UNIQUE(source, channel, external_id)
+);
+
+-- Added in schema versions 9, 10 and 12.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS simhash BIGINT;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS canonical_url TEXT;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS deleted_at TEXT;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS author TEXT;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS language TEXT;
+
+-- One score per post and taste profile; '' is the default profile.
+CREATE TABLE IF NOT EXISTS scores (
+    post_id      BIGINT NOT NULL REFERENCES posts(id),
+    profile      TEXT NOT NULL DEFAULT '',
+    score        INTEGER NOT NULL DEFAULT 0,
+    labels       TEXT,
+    tier         TEXT NOT NULL DEFAULT 'ignore',
+    scored_at    TEXT NOT NULL,
+    explanation  TEXT,
+    text_hash    TEXT,
+    taste_hash   TEXT,
+    PRIMARY KEY (post_id, profile)
+);
+
+-- Added in schema version 11.
+ALTER TABLE scores ADD COLUMN IF NOT EXISTS text_hash TEXT;
+
+-- Added in schema version 16.
+ALTER TABLE scores ADD COLUMN IF NOT EXISTS taste_hash TEXT;
+
+-- Added in schema version 14: the profile joins the primary key.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'scores' AND column_name = 'profile') THEN
+        ALTER TABLE scores ADD COLUMN profile TEXT NOT NULL DEFAULT '';
+        ALTER TABLE scores DROP CONSTRAINT scores_pkey;
+        ALTER TABLE scores ADD PRIMARY KEY (post_id, profile);
+    END IF;
+END $$;
+
+-- Added in schema version 17: rule hits are kept per profile.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'rule_hits' AND column_name = 'profile') THEN
+        ALTER TABLE rule_hits ADD COLUMN profile TEXT NOT NULL DEFAULT '';
+        ALTER TABLE rule_hits DROP CONSTRAINT rule_hits_pkey;
+        ALTER TABLE rule_hits ADD PRIMARY KEY (profile, rule, source, channel);
+    END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS post_also_in (
+    post_id  BIGINT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    source   TEXT NOT NULL,
+    channel  TEXT NOT NULL,
+    UNIQUE(post_id, source, channel)
+);
+
+CREATE TABLE IF NOT EXISTS read_state (
+    post_id  BIGINT PRIMARY KEY REFERENCES posts(id) ON DELETE CASCADE,
+    read_at  TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS stars (
+    post_id     BIGINT PRIMARY KEY REFERENCES posts(id) ON DELETE CASCADE,
+    starred_at  TEXT NOT NULL
+);
+
+-- Posts a digest listed as read_now, and when it first did, for the
+-- "Still unread" section.
+CREATE TABLE IF NOT EXISTS digest_shown (
+    post_id  BIGINT PRIMARY KEY REFERENCES posts(id) ON DELETE CASCADE,
+    shown_at TEXT NOT NULL
+);
+
+-- The last verify (entropia) result for each post, shown in digests.
+CREATE TABLE IF NOT EXISTS verifications (
+    post_id     BIGINT PRIMARY KEY REFERENCES posts(id) ON DELETE CASCADE,
+    support     INTEGER NOT NULL,
+    confidence  TEXT NOT NULL,
+    conflict    INTEGER NOT NULL,
+    signals     TEXT NOT NULL,
+    checked_at  TEXT NOT NULL
+);
+
+-- Embeddings of post text, per model, for similar, semantic search and
+-- semantic dedup.
+CREATE TABLE IF NOT EXISTS embeddings (
+    post_id     BIGINT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    model       TEXT NOT NULL,
+    vector      BYTEA NOT NULL,
+    created_at  TEXT NOT NULL,
+    PRIMARY KEY (post_id, model)
+);
+
+CREATE TABLE IF NOT EXISTS feedback (
+    post_id     BIGINT PRIMARY KEY REFERENCES posts(id) ON DELETE CASCADE,
+    vote        INTEGER NOT NULL,
+    reason      TEXT,
+    created_at  TEXT NOT NULL
+);
+
+-- Text blocks repeated across most of a channel's recent posts (footers,
+-- promos), relearned on every pull and ignored when scoring.
+CREATE TABLE IF NOT EXISTS boilerplate (
+    source      TEXT NOT NULL,
+    channel     TEXT NOT NULL,
+    block       TEXT NOT NULL,
+    posts       INTEGER NOT NULL,
+    sample      INTEGER NOT NULL,
+    learned_at  TEXT NOT NULL,
+    PRIMARY KEY(source, channel, block)
+);
+
+-- When each taste rule with a cooldown last added points in a channel, per
+-- taste profile.
+CREATE TABLE IF NOT EXISTS rule_hits (
+    profile  TEXT NOT NULL DEFAULT '',
+    rule     TEXT NOT NULL,
+    source   TEXT NOT NULL,
+    channel  TEXT NOT NULL,
+    hit_at   TEXT NOT NULL,
+    PRIMARY KEY(profile, rule, source, channel)
+);
+
+-- Local usage counters per UTC day (digests, reads, stars), for stats --me.
+CREATE TABLE IF NOT EXISTS usage_counters (
+    day      TEXT NOT NULL,
+    counter  TEXT NOT NULL,
+    value    BIGINT NOT NULL,
+    PRIMARY KEY(day, counter)
+);
+
+CREATE TABLE IF NOT EXISTS llm_usage (
+    day                TEXT NOT NULL,
+    model              TEXT NOT NULL,
+    calls              BIGINT NOT NULL,
+    prompt_tokens      BIGINT NOT NULL,
+    completion_tokens  BIGINT NOT NULL,
+    PRIMARY KEY(day, model)
+);
+
+-- Digests kept by digest --save, listed and re-rendered by history.
+CREATE TABLE IF NOT EXISTS digests (
+    id           BIGSERIAL PRIMARY KEY,
+    created_at   TEXT NOT NULL,
+    since_secs   BIGINT NOT NULL,
+    format       TEXT NOT NULL,
+    total_posts  BIGINT NOT NULL,
+    counts       TEXT NOT NULL,
+    output_hash  TEXT NOT NULL,
+    input        TEXT NOT NULL
+);
+
+-- Posts each saved digest listed, for digest --new-only.
+CREATE TABLE IF NOT EXISTS digest_items (
+    digest_id  BIGINT NOT NULL REFERENCES digests(id) ON DELETE CASCADE,
+    post_id    BIGINT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    PRIMARY KEY(digest_id, post_id)
+);
+
+CREATE TABLE IF NOT EXISTS metadata (
+    key   TEXT PRIMARY KEY,
+    value TEXT NOT NULL
+);
+
+-- Each taste profile scores were computed with, by scores.taste_hash, as
+-- YAML, so an incremental rescore can diff it against the current one.
+CREATE TABLE IF NOT EXISTS taste_snapshots (
+    hash       TEXT PRIMARY KEY,
+    profile    TEXT NOT NULL,
+    saved_at   TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS summaries (
+    text_hash  TEXT NOT NULL,
+    model      TEXT NOT NULL,
+    bullets    TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    PRIMARY KEY(text_hash, model)
+);
+
+CREATE TABLE IF NOT EXISTS feed_status (
+    source         TEXT NOT NULL,
+    feed           TEXT NOT NULL,
+    last_error     TEXT,
+    failing_since  TEXT,
+    last_ok_at     TEXT,
+    checked_at     TEXT NOT NULL,
+    PRIMARY KEY(source, feed)
+);
+
+-- Timestamps are RFC 3339 text, as in the sqlite schema, so ordering and
+-- range comparisons behave identically on both backends.
+
+-- Full-text index; the expression must match postgresDocument in backend.go.
+CREATE INDEX IF NOT EXISTS idx_posts_fts ON posts
+    USING GIN (to_tsvector('simple', COALESCE(text, '') || ' ' || snippet));
+
+CREATE INDEX IF NOT EXISTS idx_posts_posted_at ON posts(posted_at);
+-- Time windows read the effective time; the expression must match
+-- Store.effectiveTime in store.go.
+CREATE INDEX IF NOT EXISTS idx_posts_effective_at ON posts(LEAST(posted_at, fetched_at));
+CREATE INDEX IF NOT EXISTS idx_posts_text_hash ON posts(text_hash);
+CREATE INDEX IF NOT EXISTS idx_posts_source_channel ON posts(source, channel);
+CREATE INDEX IF NOT EXISTS idx_scores_tier ON scores(tier);
+CREATE INDEX IF NOT EXISTS idx_digests_created_at ON digests(created_at);
+CREATE INDEX IF NOT EXISTS idx_digest_items_post_id ON digest_items(post_id);
@@ -0,0 +1,92 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MarkShown records that a digest listed the posts as read_now at at. The
+// first time is kept; posts already recorded are left alone.
+func (s *Store) MarkShown(ctx context.Context, at time.Time, postIDs ...int64) error {
+	if s == nil || s.db == nil {
+		return errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if len(postIDs) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	shownAt := formatTime(at)
+	for _, id := range postIDs {
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO digest_shown(post_id, shown_at) SELECT id, ? FROM posts WHERE id = ? AND deleted_at IS NULL ON CONFLICT DO NOTHING",
+			shownAt, id,
+		); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("mark shown: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit mark shown: %w", err)
+	}
+	return nil
+}
+
+// GetStillUnread returns posts a digest listed as read_now at or after
+// since that are neither read nor starred, most recently shown first. Only
+// the Source and Channel of filter apply.
+func (s *Store) GetStillUnread(ctx context.Context, since time.Time, filter PostFilter) ([]PostWithScore, error) {
+	if s == nil || s.db == nil {
+		return nil, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	query := `
+		SELECT p.id, p.source, p.channel, p.external_id, p.text, p.snippet, p.text_hash, p.url, p.author, p.language, p.posted_at, p.fetched_at,
+			s.score, s.labels, s.tier, s.scored_at, s.explanation, s.text_hash, s.taste_hash
+		FROM digest_shown ds
+		JOIN posts p ON p.id = ds.post_id
+		LEFT JOIN scores s ON s.post_id = p.id` + s.scoreProfile() + `
+		WHERE ds.shown_at >= ?` + liveClause + `
+			AND NOT EXISTS (SELECT 1 FROM read_state r WHERE r.post_id = p.id)
+			AND NOT EXISTS (SELECT 1 FROM stars st WHERE st.post_id = p.id)`
+	args := []any{formatTime(since)}
+	if filter.Source != "" {
+		query += " AND p.source = ?"
+		args = append(args, filter.Source)
+	}
+	if filter.Channel != "" {
+		query += " AND p.channel = ?"
+		args = append(args, filter.Channel)
+	}
+	query += " ORDER BY ds.shown_at DESC, p.id DESC"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get still unread: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var posts []PostWithScore
+	for rows.Next() {
+		post, score, err := scanPostWithScore(rows)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, PostWithScore{Post: post, Score: score})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate still unread: %w", err)
+	}
+	return posts, nil
+}
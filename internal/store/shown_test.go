@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGetStillUnread(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	cve, helm := insertSearchFixtures(t, st)
+	day1 := time.Date(2026, 3, 1, 7, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+
+	if err := st.MarkShown(ctx, day1, cve.ID, helm.ID); err != nil {
+		t.Fatalf("mark shown: %v", err)
+	}
+	// Shown again later: the first time is kept.
+	if err := st.MarkShown(ctx, day2, cve.ID); err != nil {
+		t.Fatalf("mark shown again: %v", err)
+	}
+
+	ids := func(since time.Time, filter PostFilter) []int64 {
+		t.Helper()
+		posts, err := st.GetStillUnread(ctx, since, filter)
+		if err != nil {
+			t.Fatalf("get still unread: %v", err)
+		}
+		var out []int64
+		for _, p := range posts {
+			out = append(out, p.Post.ID)
+		}
+		return out
+	}
+
+	if got := ids(day1, PostFilter{}); len(got) != 2 || got[0] != helm.ID {
+		t.Errorf("still unread = %v, want both, newest shown first", got)
+	}
+	if got := ids(day2, PostFilter{}); len(got) != 0 {
+		t.Errorf("still unread since day 2 = %v, want none", got)
+	}
+	if got := ids(day1, PostFilter{Source: "rss"}); len(got) != 1 || got[0] != cve.ID {
+		t.Errorf("still unread from rss = %v, want the cve post", got)
+	}
+
+	// Reading or starring settles a post.
+	if err := st.MarkRead(ctx, day2, cve.ID); err != nil {
+		t.Fatalf("mark read: %v", err)
+	}
+	if err := st.Star(ctx, helm.ID, day2); err != nil {
+		t.Fatalf("star: %v", err)
+	}
+	if got := ids(day1, PostFilter{}); len(got) != 0 {
+		t.Errorf("still unread after read and star = %v, want none", got)
+	}
+}
+
+func TestStillUnread_NilStore(t *testing.T) {
+	var st *Store
+	if err := st.MarkShown(context.Background(), time.Now(), 1); err == nil {
+		t.Error("expected error from nil store")
+	}
+	if _, err := st.GetStillUnread(context.Background(), time.Time{}, PostFilter{}); err == nil {
+		t.Error("expected error from nil store")
+	}
+}
@@ -0,0 +1,178 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"strings"
+	"unicode"
+)
+
+// simhash returns a 64-bit SimHash of text over its distinct lowercase words
+// of three or more letters or digits, stopwords excluded, so rewording or
+// reordering a few words flips only a few bits. Texts with no such words
+// hash to 0.
+func simhash(text string) uint64 {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var tokens []string
+	seen := make(map[string]bool, len(words))
+	for _, w := range words {
+		if len([]rune(w)) >= 3 && !simhashStopwords[w] && !seen[w] {
+			seen[w] = true
+			tokens = append(tokens, w)
+		}
+	}
+	if len(tokens) == 0 {
+		return 0
+	}
+
+	var weights [64]int
+	add := func(feature string) {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(feature))
+		sum := h.Sum64()
+		for i := range weights {
+			if sum&(1<<i) != 0 {
+				weights[i]++
+			} else {
+				weights[i]--
+			}
+		}
+	}
+	for _, tok := range tokens {
+		add(tok)
+	}
+
+	var out uint64
+	for i, w := range weights {
+		if w > 0 {
+			out |= 1 << i
+		}
+	}
+	return out
+}
+
+// maxSimhashDistance converts a similarity in (0, 1] to the largest number
+// of differing fingerprint bits it allows.
+func maxSimhashDistance(similarity float64) int {
+	return int(math.Floor((1 - similarity) * 64))
+}
+
+// simhashDistance is the number of bits in which a and b differ.
+func simhashDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// simhashIndex finds an added fingerprint within maxDist bits of a query.
+// Fingerprints are split into maxDist+1 bands; any two within maxDist bits
+// agree on at least one whole band, so only fingerprints sharing a band with
+// the query are compared.
+type simhashIndex struct {
+	maxDist int
+	bands   int
+	added   int
+	buckets map[[2]uint64][]simhashEntry
+}
+
+type simhashEntry struct {
+	fp  uint64
+	id  int64
+	seq int
+}
+
+func newSimhashIndex(maxDist int) *simhashIndex {
+	bands := maxDist + 1
+	if bands > 64 {
+		bands = 64
+	}
+	return &simhashIndex{maxDist: maxDist, bands: bands, buckets: make(map[[2]uint64][]simhashEntry)}
+}
+
+// bandKey returns band i of fp together with its index.
+func (ix *simhashIndex) bandKey(fp uint64, i int) [2]uint64 {
+	lo := i * 64 / ix.bands
+	hi := (i + 1) * 64 / ix.bands
+	mask := uint64(1)<<(hi-lo) - 1
+	if hi-lo == 64 {
+		mask = math.MaxUint64
+	}
+	return [2]uint64{uint64(i), (fp >> lo) & mask}
+}
+
+func (ix *simhashIndex) add(fp uint64, id int64) {
+	e := simhashEntry{fp: fp, id: id, seq: ix.added}
+	ix.added++
+	for i := 0; i < ix.bands; i++ {
+		k := ix.bandKey(fp, i)
+		ix.buckets[k] = append(ix.buckets[k], e)
+	}
+}
+
+// find returns the ID of the earliest added fingerprint within maxDist bits.
+func (ix *simhashIndex) find(fp uint64) (int64, bool) {
+	var (
+		best  simhashEntry
+		found bool
+	)
+	for i := 0; i < ix.bands; i++ {
+		for _, e := range ix.buckets[ix.bandKey(fp, i)] {
+			if (!found || e.seq < best.seq) && simhashDistance(fp, e.fp) <= ix.maxDist {
+				best, found = e, true
+			}
+		}
+	}
+	return best.id, found
+}
+
+// backfillSimhash fingerprints posts stored before the simhash column existed.
+func backfillSimhash(ctx context.Context, tx *txConn) error {
+	rows, err := tx.QueryContext(ctx, "SELECT id, text, snippet FROM posts WHERE simhash IS NULL")
+	if err != nil {
+		return fmt.Errorf("query unfingerprinted posts: %w", err)
+	}
+	type pending struct {
+		id int64
+		fp uint64
+	}
+	var todo []pending
+	for rows.Next() {
+		var (
+			id      int64
+			text    sql.NullString
+			snippet string
+		)
+		if err := rows.Scan(&id, &text, &snippet); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("scan unfingerprinted post: %w", err)
+		}
+		if text.String == "" {
+			text.String = snippet
+		}
+		todo = append(todo, pending{id: id, fp: simhash(text.String)})
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return fmt.Errorf("iterate unfingerprinted posts: %w", err)
+	}
+	_ = rows.Close()
+
+	for _, p := range todo {
+		if _, err := tx.ExecContext(ctx, "UPDATE posts SET simhash = ? WHERE id = ?", int64(p.fp), p.id); err != nil {
+			return fmt.Errorf("backfill simhash: %w", err)
+		}
+	}
+	return nil
+}
+
+// simhashStopwords are common English words that carry no story content.
+var simhashStopwords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "was": true, "with": true,
+	"now": true, "new": true, "out": true, "has": true, "have": true, "this": true,
+	"that": true, "from": true, "its": true, "our": true, "you": true, "your": true,
+	"can": true, "will": true, "plus": true, "into": true, "but": true, "not": true,
+}
@@ -0,0 +1,43 @@
+package store
+
+import "testing"
+
+func TestSimhash(t *testing.T) {
+	a := simhash("Kubernetes 1.33 released with sidecar containers GA and new scheduling features for large clusters.")
+	b := simhash("Kubernetes 1.33 is out: sidecar containers are now GA, plus new scheduling features for large clusters.")
+	c := simhash("Join our webinar on cloud cost savings next Tuesday")
+
+	if d := simhashDistance(a, b); d > maxSimhashDistance(0.8) {
+		t.Errorf("reworded distance = %d, want <= %d", d, maxSimhashDistance(0.8))
+	}
+	if d := simhashDistance(a, c); d <= maxSimhashDistance(0.8) {
+		t.Errorf("unrelated distance = %d, want > %d", d, maxSimhashDistance(0.8))
+	}
+	if simhash("Same WORDS, same order.") != simhash("same words same order") {
+		t.Error("case and punctuation should not change the fingerprint")
+	}
+	if simhash("🔥 !!") != 0 {
+		t.Error("text without words should hash to 0")
+	}
+}
+
+func TestSimhashIndex(t *testing.T) {
+	ix := newSimhashIndex(3)
+	ix.add(0b1111, 1)
+	ix.add(0b0111, 2)
+	ix.add(0xFFFF_0000_0000_0000, 3)
+
+	// Within 3 bits of both 1 and 2; the earlier one wins.
+	if id, ok := ix.find(0b0011); !ok || id != 1 {
+		t.Errorf("find = %d, %v; want 1", id, ok)
+	}
+	if id, ok := ix.find(0xFFFF_0000_0000_000F); ok {
+		t.Errorf("find = %d, want no match 4+ bits away", id)
+	}
+	if id, ok := ix.find(0xFFFF_0000_0000_0001); !ok || id != 3 {
+		t.Errorf("find = %d, %v; want 3", id, ok)
+	}
+	if _, ok := newSimhashIndex(0).find(1); ok {
+		t.Error("empty index should not match")
+	}
+}
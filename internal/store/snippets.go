@@ -0,0 +1,87 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RewriteSnippets replaces every stored post's snippet, tombstoned posts
+// included, with rewrite(text, snippet), where text is empty for posts
+// stored without their full text. Posts with no text keep their identity
+// in the snippet, so their text hash and fingerprint are recomputed too,
+// and so is the hash their score was made for when it matched the old one:
+// the post is no more changed since scoring than it was. An empty result
+// leaves the post alone. It returns how many posts changed.
+func (s *Store) RewriteSnippets(ctx context.Context, rewrite func(text, snippet string) string) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	rows, err := tx.QueryContext(ctx, "SELECT id, text, snippet, text_hash FROM posts ORDER BY id")
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, fmt.Errorf("query snippets: %w", err)
+	}
+	type pending struct {
+		id      int64
+		text    string
+		snippet string
+		hash    string
+	}
+	var todo []pending
+	for rows.Next() {
+		var (
+			p    pending
+			text sql.NullString
+		)
+		if err := rows.Scan(&p.id, &text, &p.snippet, &p.hash); err != nil {
+			_ = rows.Close()
+			_ = tx.Rollback()
+			return 0, fmt.Errorf("scan snippet: %w", err)
+		}
+		next := strings.TrimSpace(rewrite(text.String, p.snippet))
+		if next == "" || next == p.snippet {
+			continue
+		}
+		p.text, p.snippet = text.String, next
+		todo = append(todo, p)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		_ = tx.Rollback()
+		return 0, fmt.Errorf("iterate snippets: %w", err)
+	}
+	_ = rows.Close()
+
+	for _, p := range todo {
+		if p.text != "" {
+			_, err = tx.ExecContext(ctx, "UPDATE posts SET snippet = ? WHERE id = ?", p.snippet, p.id)
+		} else {
+			hash := textHash("", p.snippet)
+			_, err = tx.ExecContext(ctx, "UPDATE posts SET snippet = ?, text_hash = ?, simhash = ? WHERE id = ?",
+				p.snippet, hash, int64(simhash(p.snippet)), p.id)
+			if err == nil {
+				_, err = tx.ExecContext(ctx, "UPDATE scores SET text_hash = ? WHERE post_id = ? AND text_hash = ?",
+					hash, p.id, p.hash)
+			}
+		}
+		if err != nil {
+			_ = tx.Rollback()
+			return 0, fmt.Errorf("update snippet: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+	return int64(len(todo)), nil
+}
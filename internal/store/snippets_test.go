@@ -0,0 +1,84 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRewriteSnippets(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	at := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	full, err := st.InsertPost(ctx, PostInput{
+		Source: "rss", Channel: "ops", ExternalID: "full",
+		Text: "token=abc deployed the fix", PostedAt: at, FetchedAt: at,
+	})
+	if err != nil {
+		t.Fatalf("insert full: %v", err)
+	}
+	slim, err := st.InsertPost(ctx, PostInput{
+		Source: "rss", Channel: "ops", ExternalID: "slim",
+		Snippet: "password=hunter2 rotated", PostedAt: at, FetchedAt: at,
+	})
+	if err != nil {
+		t.Fatalf("insert slim: %v", err)
+	}
+
+	if err := st.SaveScore(ctx, Score{PostID: slim.ID, Score: 3, Tier: "skim", ScoredAt: at}); err != nil {
+		t.Fatalf("save score: %v", err)
+	}
+
+	var texts []string
+	changed, err := st.RewriteSnippets(ctx, func(text, snippet string) string {
+		texts = append(texts, text)
+		source := text
+		if source == "" {
+			source = snippet
+		}
+		_, rest, _ := strings.Cut(source, " ")
+		return "[REDACTED] " + rest
+	})
+	if err != nil {
+		t.Fatalf("rewrite: %v", err)
+	}
+	if changed != 2 || len(texts) != 2 || texts[0] != full.Text || texts[1] != "" {
+		t.Fatalf("changed %d, texts %q", changed, texts)
+	}
+
+	pws, err := st.GetPostByID(ctx, full.ID)
+	if err != nil {
+		t.Fatalf("get full: %v", err)
+	}
+	got := pws.Post
+	if got.Snippet != "[REDACTED] deployed the fix" || got.TextHash != full.TextHash {
+		t.Errorf("full post = snippet %q, hash changed %v", got.Snippet, got.TextHash != full.TextHash)
+	}
+	pws, err = st.GetPostByID(ctx, slim.ID)
+	if err != nil {
+		t.Fatalf("get slim: %v", err)
+	}
+	got = pws.Post
+	if got.Snippet != "[REDACTED] rotated" || got.TextHash != textHash("", got.Snippet) {
+		t.Errorf("slim post = snippet %q, hash %q", got.Snippet, got.TextHash)
+	}
+	// Its score follows the new hash, so the post does not read as edited.
+	if pws.Score == nil || pws.Changed() {
+		t.Errorf("slim post score = %+v, changed since scoring = %v", pws.Score, pws.Changed())
+	}
+
+	// The search index follows the new snippets.
+	if results, err := st.Search(ctx, "hunter2", SearchFilter{}); err != nil || len(results) != 0 {
+		t.Errorf("search for the old snippet = %d results, %v", len(results), err)
+	}
+
+	// Unchanged or empty results leave posts alone.
+	if changed, err := st.RewriteSnippets(ctx, func(_, snippet string) string { return snippet }); err != nil || changed != 0 {
+		t.Errorf("identity rewrite = %d, %v", changed, err)
+	}
+	if changed, err := st.RewriteSnippets(ctx, func(string, string) string { return " " }); err != nil || changed != 0 {
+		t.Errorf("empty rewrite = %d, %v", changed, err)
+	}
+}
@@ -8,16 +8,19 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
+	"math"
 	"strings"
 	"time"
 
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/ppiankov/noisepan/internal/embed"
 	_ "modernc.org/sqlite"
 )
 
 type Store struct {
-	db *sql.DB
+	db      *conn
+	backend Backend
+	profile string // taste profile whose scores are read and written
 }
 
 type Post struct {
@@ -29,6 +32,8 @@ type Post struct {
 	Snippet    string
 	TextHash   string
 	URL        string
+	Author     string // empty when the source does not report one
+	Language   string // ISO 639-1 code detected at fetch; empty when unknown
 	PostedAt   time.Time
 	FetchedAt  time.Time
 }
@@ -40,6 +45,8 @@ type PostInput struct {
 	Text       string
 	Snippet    string
 	URL        string
+	Author     string
+	Language   string
 	PostedAt   time.Time
 	FetchedAt  time.Time
 }
@@ -51,6 +58,13 @@ type Score struct {
 	Tier        string
 	ScoredAt    time.Time
 	Explanation json.RawMessage
+	// TextHash is the post's text hash when the score was saved; SaveScore
+	// fills it in. Empty for scores saved before it was recorded.
+	TextHash string
+	// TasteHash identifies the taste profile the score was computed with
+	// (see config.TasteProfile.Hash), so "rescore --incremental" can tell
+	// what changed since. Empty for scores saved before it was recorded.
+	TasteHash string
 }
 
 type PostWithScore struct {
@@ -58,44 +72,130 @@ type PostWithScore struct {
 	Score *Score
 }
 
+// Changed reports whether the post's text was edited (re-pulled with
+// different text) after it was scored, so the score and its explanation
+// describe text the post no longer has.
+func (p PostWithScore) Changed() bool {
+	return p.Score != nil && p.Score.TextHash != "" && p.Score.TextHash != p.Post.TextHash
+}
+
+// Open opens (creating if needed) the sqlite database at path.
 func Open(path string) (*Store, error) {
-	if strings.TrimSpace(path) == "" {
-		return nil, errors.New("path is required")
-	}
+	return OpenBackend(SQLite{}, path)
+}
 
-	dir := filepath.Dir(path)
-	if dir != "." {
-		if err := os.MkdirAll(dir, 0o755); err != nil {
-			return nil, fmt.Errorf("create db dir: %w", err)
-		}
-	}
+// OpenBackend connects to dsn with the given backend and migrates the schema.
+func OpenBackend(b Backend, dsn string) (*Store, error) {
+	return open(b, dsn, false)
+}
 
-	db, err := sql.Open("sqlite", path)
+// OpenDryRun connects to dsn like OpenBackend and begins a dry run (see
+// BeginDryRun) that the migration is part of, so a database that needed one
+// is left as it was too.
+func OpenDryRun(b Backend, dsn string) (*Store, error) {
+	return open(b, dsn, true)
+}
+
+func open(b Backend, dsn string, dry bool) (*Store, error) {
+	db, err := b.Open(dsn)
 	if err != nil {
-		return nil, fmt.Errorf("open sqlite: %w", err)
+		return nil, err
 	}
 
 	ctx := context.Background()
-	if _, err := db.ExecContext(ctx, "PRAGMA foreign_keys = ON"); err != nil {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
 		_ = db.Close()
-		return nil, fmt.Errorf("enable foreign keys: %w", err)
+		return nil, fmt.Errorf("begin transaction: %w", err)
 	}
-
-	if err := migrate(ctx, db); err != nil {
+	if err := b.Migrate(ctx, tx); err != nil {
+		_ = tx.Rollback()
 		_ = db.Close()
 		return nil, err
 	}
 
-	return &Store{db: db}, nil
+	s := &Store{db: &conn{DB: db, rebind: b.Rebind, system: b.Name()}, backend: b}
+	if dry {
+		s.db.dry = tx
+		return s, nil
+	}
+	if err := tx.Commit(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("commit migration: %w", err)
+	}
+	return s, nil
+}
+
+// effectiveTime is the SQL expression used for time windows and ordering. It
+// caps posted_at at the first fetch time so posts dated in the future (feeds
+// with broken timezones) cannot pin themselves to the top of every digest.
+// idx_posts_effective_at indexes the same expression, so windows stay
+// indexed.
+func (s *Store) effectiveTime() string {
+	return s.backend.Least("p.posted_at", "p.fetched_at")
+}
+
+// SetProfile selects the taste profile whose scores the store reads and
+// writes; "" is the default profile. Each profile keeps its own score per
+// post, so rescoring one leaves the others alone.
+func (s *Store) SetProfile(name string) {
+	s.profile = name
+}
+
+// Profile returns the taste profile selected with SetProfile.
+func (s *Store) Profile() string {
+	return s.profile
+}
+
+// scoreProfile is the join condition that limits the scores joined as s to
+// the store's profile.
+func (s *Store) scoreProfile() string {
+	return " AND s.profile = '" + strings.ReplaceAll(s.profile, "'", "''") + "'"
 }
 
 func (s *Store) Close() error {
 	if s == nil || s.db == nil {
 		return nil
 	}
+	if s.db.dry != nil {
+		_ = s.db.dry.Rollback()
+	}
 	return s.db.Close()
 }
 
+// BeginDryRun routes every later statement through one transaction that
+// Close rolls back, so commands run as usual, and see their own writes, but
+// leave the database as it was. VACUUM cannot run inside it.
+func (s *Store) BeginDryRun(ctx context.Context) error {
+	if s == nil || s.db == nil {
+		return errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if s.db.dry != nil {
+		return nil
+	}
+
+	tx, err := s.db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin dry run: %w", err)
+	}
+	s.db.dry = tx
+	return nil
+}
+
+// DryRun reports whether BeginDryRun was called.
+func (s *Store) DryRun() bool {
+	return s != nil && s.db != nil && s.db.dry != nil
+}
+
+// InsertPost inserts a post or updates an existing one with the same
+// source, channel, and external ID. On update, fetched_at keeps the first
+// sighting, and posted_at takes the new time if it is no later than that,
+// so a feed that fixes a wrong date corrects it, but never moves later past
+// it, so re-fetching a feed that stamps items with the current time does not
+// resurface them.
 func (s *Store) InsertPost(ctx context.Context, in PostInput) (Post, error) {
 	if s == nil || s.db == nil {
 		return Post{}, errors.New("store is not initialized")
@@ -129,15 +229,28 @@ func (s *Store) InsertPost(ctx context.Context, in PostInput) (Post, error) {
 	}
 
 	hash := textHash(in.Text, snippet)
+	fingerprint := simhash(in.Text)
+	if in.Text == "" {
+		fingerprint = simhash(snippet)
+	}
 
 	var textVal sql.NullString
 	if in.Text != "" {
 		textVal = sql.NullString{String: in.Text, Valid: true}
 	}
 
-	var urlVal sql.NullString
+	var urlVal, canonicalVal sql.NullString
 	if strings.TrimSpace(in.URL) != "" {
 		urlVal = sql.NullString{String: strings.TrimSpace(in.URL), Valid: true}
+		canonicalVal = sql.NullString{String: canonicalURL(in.URL), Valid: true}
+	}
+
+	var authorVal, languageVal sql.NullString
+	if author := strings.TrimSpace(in.Author); author != "" {
+		authorVal = sql.NullString{String: author, Valid: true}
+	}
+	if in.Language != "" {
+		languageVal = sql.NullString{String: in.Language, Valid: true}
 	}
 
 	postedAt := formatTime(in.PostedAt)
@@ -145,15 +258,20 @@ func (s *Store) InsertPost(ctx context.Context, in PostInput) (Post, error) {
 
 	_, err := s.db.ExecContext(ctx, `
 		INSERT INTO posts (
-			source, channel, external_id, text, snippet, text_hash, url, posted_at, fetched_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			source, channel, external_id, text, snippet, text_hash, simhash, url, canonical_url, author, language, posted_at, fetched_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(source, channel, external_id) DO UPDATE SET
 			text = excluded.text,
 			snippet = excluded.snippet,
 			text_hash = excluded.text_hash,
+			simhash = excluded.simhash,
 			url = excluded.url,
-			posted_at = excluded.posted_at,
-			fetched_at = excluded.fetched_at
+			canonical_url = excluded.canonical_url,
+			author = COALESCE(excluded.author, posts.author),
+			language = COALESCE(excluded.language, posts.language),
+			posted_at = CASE WHEN excluded.posted_at <= posts.fetched_at THEN excluded.posted_at
+				ELSE `+s.backend.Least("posts.posted_at", "excluded.posted_at")+` END,
+			fetched_at = posts.fetched_at
 	`,
 		in.Source,
 		in.Channel,
@@ -161,7 +279,11 @@ func (s *Store) InsertPost(ctx context.Context, in PostInput) (Post, error) {
 		textVal,
 		snippet,
 		hash,
+		int64(fingerprint),
 		urlVal,
+		canonicalVal,
+		authorVal,
+		languageVal,
 		postedAt,
 		fetchedAt,
 	)
@@ -170,7 +292,7 @@ func (s *Store) InsertPost(ctx context.Context, in PostInput) (Post, error) {
 	}
 
 	row := s.db.QueryRowContext(ctx, `
-		SELECT id, source, channel, external_id, text, snippet, text_hash, url, posted_at, fetched_at
+		SELECT id, source, channel, external_id, text, snippet, text_hash, url, author, language, posted_at, fetched_at
 		FROM posts
 		WHERE source = ? AND channel = ? AND external_id = ?
 	`, in.Source, in.Channel, in.ExternalID)
@@ -192,10 +314,10 @@ func (s *Store) GetUnscored(ctx context.Context) ([]Post, error) {
 	}
 
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT p.id, p.source, p.channel, p.external_id, p.text, p.snippet, p.text_hash, p.url, p.posted_at, p.fetched_at
+		SELECT p.id, p.source, p.channel, p.external_id, p.text, p.snippet, p.text_hash, p.url, p.author, p.language, p.posted_at, p.fetched_at
 		FROM posts p
-		LEFT JOIN scores s ON s.post_id = p.id
-		WHERE s.post_id IS NULL
+		LEFT JOIN scores s ON s.post_id = p.id`+s.scoreProfile()+`
+		WHERE s.post_id IS NULL`+liveClause+`
 		ORDER BY p.posted_at ASC
 	`)
 	if err != nil {
@@ -246,27 +368,35 @@ func (s *Store) SaveScore(ctx context.Context, in Score) error {
 		return fmt.Errorf("encode labels: %w", err)
 	}
 
-	var explanationVal sql.NullString
+	var explanationVal, tasteHashVal sql.NullString
 	if len(in.Explanation) > 0 {
 		explanationVal = sql.NullString{String: string(in.Explanation), Valid: true}
 	}
+	if in.TasteHash != "" {
+		tasteHashVal = sql.NullString{String: in.TasteHash, Valid: true}
+	}
 
 	_, err = s.db.ExecContext(ctx, `
-		INSERT INTO scores (post_id, score, labels, tier, scored_at, explanation)
-		VALUES (?, ?, ?, ?, ?, ?)
-		ON CONFLICT(post_id) DO UPDATE SET
+		INSERT INTO scores (post_id, profile, score, labels, tier, scored_at, explanation, text_hash, taste_hash)
+		VALUES (?, ?, ?, ?, ?, ?, ?, (SELECT text_hash FROM posts WHERE id = ?), ?)
+		ON CONFLICT(post_id, profile) DO UPDATE SET
 			score = excluded.score,
 			labels = excluded.labels,
 			tier = excluded.tier,
 			scored_at = excluded.scored_at,
-			explanation = excluded.explanation
+			explanation = excluded.explanation,
+			text_hash = excluded.text_hash,
+			taste_hash = excluded.taste_hash
 	`,
 		in.PostID,
+		s.profile,
 		in.Score,
 		string(labelsJSON),
 		in.Tier,
 		formatTime(in.ScoredAt),
 		explanationVal,
+		in.PostID,
+		tasteHashVal,
 	)
 	if err != nil {
 		return fmt.Errorf("save score: %w", err)
@@ -275,10 +405,26 @@ func (s *Store) SaveScore(ctx context.Context, in Score) error {
 	return nil
 }
 
-// PostFilter holds optional filters for GetPosts.
+// Post orders for PostFilter.Order.
+const (
+	OrderNewest   = "newest"   // newest posted_at first (default)
+	OrderOldest   = "oldest"   // oldest posted_at first
+	OrderIngested = "ingested" // ascending post ID, for paging with AfterID
+)
+
+// PostFilter holds optional filters for GetPosts. Ties in post time are
+// broken by ID so Limit/Offset pages are stable; for large walks prefer
+// OrderIngested with AfterID set to the last ID of the previous page.
 type PostFilter struct {
-	Source  string // filter by source (e.g. "rss", "telegram")
-	Channel string // filter by channel name
+	Source      string // filter by source (e.g. "rss", "telegram")
+	Channel     string // filter by channel name
+	UnreadOnly  bool   // skip posts marked read
+	StarredOnly bool   // only starred posts
+	NewOnly     bool   // skip posts a saved digest listed
+	AfterID     int64  // only posts ingested after this post ID
+	Order       string // OrderNewest, OrderOldest or OrderIngested
+	Limit       int    // maximum posts returned, 0 for all
+	Offset      int    // posts skipped before the first returned
 }
 
 func (s *Store) GetPosts(ctx context.Context, since time.Time, tier string, filters ...PostFilter) ([]PostWithScore, error) {
@@ -297,11 +443,11 @@ func (s *Store) GetPosts(ctx context.Context, since time.Time, tier string, filt
 	}
 
 	query := fmt.Sprintf(`
-		SELECT p.id, p.source, p.channel, p.external_id, p.text, p.snippet, p.text_hash, p.url, p.posted_at, p.fetched_at,
-			s.score, s.labels, s.tier, s.scored_at, s.explanation
+		SELECT p.id, p.source, p.channel, p.external_id, p.text, p.snippet, p.text_hash, p.url, p.author, p.language, p.posted_at, p.fetched_at,
+			s.score, s.labels, s.tier, s.scored_at, s.explanation, s.text_hash, s.taste_hash
 		FROM posts p
-		%s scores s ON s.post_id = p.id
-		WHERE p.posted_at >= ?`, join)
+		%s scores s ON s.post_id = p.id`+s.scoreProfile()+`
+		WHERE `+s.effectiveTime()+` >= ?`+liveClause, join)
 	args := []any{sinceValue}
 
 	if tier != "" {
@@ -321,8 +467,42 @@ func (s *Store) GetPosts(ctx context.Context, since time.Time, tier string, filt
 		query += " AND p.channel = ?"
 		args = append(args, filter.Channel)
 	}
+	if filter.UnreadOnly {
+		query += " AND NOT EXISTS (SELECT 1 FROM read_state r WHERE r.post_id = p.id)"
+	}
+	if filter.StarredOnly {
+		query += starredClause
+	}
+	if filter.NewOnly {
+		query += " AND NOT EXISTS (SELECT 1 FROM digest_items di WHERE di.post_id = p.id)"
+	}
+	if filter.AfterID > 0 {
+		query += " AND p.id > ?"
+		args = append(args, filter.AfterID)
+	}
 
-	query += " ORDER BY p.posted_at DESC"
+	switch filter.Order {
+	case "", OrderNewest:
+		query += " ORDER BY " + s.effectiveTime() + " DESC, p.id DESC"
+	case OrderOldest:
+		query += " ORDER BY " + s.effectiveTime() + ", p.id"
+	case OrderIngested:
+		query += " ORDER BY p.id"
+	default:
+		return nil, fmt.Errorf("get posts: unknown order %q", filter.Order)
+	}
+	if filter.Limit < 0 || filter.Offset < 0 {
+		return nil, errors.New("get posts: limit and offset must not be negative")
+	}
+	if filter.Limit > 0 || filter.Offset > 0 {
+		// SQLite only accepts OFFSET after a LIMIT.
+		limit := int64(math.MaxInt64)
+		if filter.Limit > 0 {
+			limit = int64(filter.Limit)
+		}
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, limit, filter.Offset)
+	}
 
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -347,7 +527,128 @@ func (s *Store) GetPosts(ctx context.Context, since time.Time, tier string, filt
 	return posts, nil
 }
 
+// ErrPostNotFound is returned by GetPostByID when no post has the ID.
+var ErrPostNotFound = errors.New("post not found")
+
+// GetPostByID returns one post with its score, if scored.
+func (s *Store) GetPostByID(ctx context.Context, id int64) (PostWithScore, error) {
+	if s == nil || s.db == nil {
+		return PostWithScore{}, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	row := s.db.QueryRowContext(ctx, `
+		SELECT p.id, p.source, p.channel, p.external_id, p.text, p.snippet, p.text_hash, p.url, p.author, p.language, p.posted_at, p.fetched_at,
+			s.score, s.labels, s.tier, s.scored_at, s.explanation, s.text_hash, s.taste_hash
+		FROM posts p
+		LEFT JOIN scores s ON s.post_id = p.id`+s.scoreProfile()+`
+		WHERE p.id = ?`+liveClause, id)
+	post, score, err := scanPostWithScore(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return PostWithScore{}, fmt.Errorf("get post %d: %w", id, ErrPostNotFound)
+	}
+	if err != nil {
+		return PostWithScore{}, err
+	}
+	return PostWithScore{Post: post, Score: score}, nil
+}
+
+// Dedup keeper strategies.
+const (
+	DedupEarliest = "earliest" // earliest posted_at wins
+	DedupSource   = "source"   // first match in SourceOrder wins, then earliest
+	DedupLongest  = "longest"  // longest stored text wins, then earliest
+)
+
+// DedupKeeper selects which post in a group of duplicates survives. With
+// Similarity set, posts whose fingerprints are at least that similar count
+// as duplicates too, and with ByURL so do posts linking to the same page.
+// With SemanticModel and SemanticSimilarity set, so do recent posts whose
+// embeddings from that model are at least that similar. Posts are visited in
+// the strategy's order, so each of these groups keeps its preferred post;
+// identical texts are the same length, so DedupLongest picks only among near
+// duplicates, shared URLs, and similar meaning.
+// With RecurringGap set, a channel's identical texts posted at least that far
+// apart are recurrences rather than duplicates and all stay. The zero value
+// keeps the earliest of identical texts.
+type DedupKeeper struct {
+	Strategy     string
+	SourceOrder  []string      // source names, most preferred first
+	Similarity   float64       // 0 merges identical text only; 0.8 catches rewordings
+	ByURL        bool          // merge posts sharing a canonical URL
+	RecurringGap time.Duration // 0 merges recurrences too
+
+	SemanticModel      string  // embedding model compared
+	SemanticSimilarity float64 // minimum cosine similarity; 0 is off
+}
+
+// semanticDedupWindow bounds semantic dedup to posts fetched this recently:
+// every post is compared with every kept one, and the same story is
+// retold within days, not months.
+const semanticDedupWindow = 72 * time.Hour
+
+// orderBy returns the ORDER BY clause and its arguments listing posts from
+// most to least preferred, so the first post of each group is its keeper.
+func (k DedupKeeper) orderBy() (string, []any) {
+	switch k.Strategy {
+	case DedupSource:
+		var (
+			b    strings.Builder
+			args []any
+		)
+		b.WriteString("CASE source")
+		for i, src := range k.SourceOrder {
+			b.WriteString(" WHEN ? THEN ?")
+			args = append(args, src, i)
+		}
+		b.WriteString(" ELSE ? END, posted_at, id")
+		args = append(args, len(k.SourceOrder))
+		return b.String(), args
+	case DedupLongest:
+		return "length(COALESCE(text, snippet)) DESC, posted_at, id", nil
+	}
+	return "posted_at, id", nil
+}
+
+// recurs reports whether two posted_at values are at least gap apart. An
+// unparsable time never recurs, so the posts merge as before.
+func recurs(a, b string, gap time.Duration) bool {
+	ta, errA := parseTime(a)
+	tb, errB := parseTime(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	d := tb.Sub(ta)
+	return d >= gap || -d >= gap
+}
+
+// LatestPostID returns the highest post ID, or 0 if the store is empty.
+func (s *Store) LatestPostID(ctx context.Context) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var id int64
+	if err := s.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(id), 0) FROM posts").Scan(&id); err != nil {
+		return 0, fmt.Errorf("latest post id: %w", err)
+	}
+	return id, nil
+}
+
+// Deduplicate removes posts with identical text, keeping the earliest one.
 func (s *Store) Deduplicate(ctx context.Context) (int, error) {
+	return s.DeduplicateWith(ctx, DedupKeeper{})
+}
+
+// DeduplicateWith removes posts with identical text, or near-identical text,
+// similar meaning or the same canonical URL as keeper allows, keeping the post chosen by
+// keeper. Removed posts are recorded in the keeper's also-in list.
+func (s *Store) DeduplicateWith(ctx context.Context, keeper DedupKeeper) (int, error) {
 	if s == nil || s.db == nil {
 		return 0, errors.New("store is not initialized")
 	}
@@ -360,11 +661,37 @@ func (s *Store) Deduplicate(ctx context.Context) (int, error) {
 		return 0, fmt.Errorf("begin transaction: %w", err)
 	}
 
+	var near *simhashIndex
+	if keeper.Similarity > 0 {
+		if err := backfillSimhash(ctx, tx); err != nil {
+			_ = tx.Rollback()
+			return 0, err
+		}
+		near = newSimhashIndex(maxSimhashDistance(keeper.Similarity))
+	}
+
+	if keeper.ByURL {
+		if err := backfillCanonicalURL(ctx, tx); err != nil {
+			_ = tx.Rollback()
+			return 0, err
+		}
+	}
+
+	var vectors map[int64]embed.Vector
+	if keeper.SemanticModel != "" && keeper.SemanticSimilarity > 0 {
+		vectors, err = loadEmbeddings(ctx, tx, keeper.SemanticModel, time.Now().Add(-semanticDedupWindow))
+		if err != nil {
+			_ = tx.Rollback()
+			return 0, err
+		}
+	}
+
+	order, args := keeper.orderBy()
 	rows, err := tx.QueryContext(ctx, `
-		SELECT id, source, channel, text_hash, posted_at
+		SELECT id, source, channel, text_hash, simhash, canonical_url, posted_at
 		FROM posts
-		ORDER BY text_hash, posted_at, id
-	`)
+		WHERE deleted_at IS NULL
+		ORDER BY `+order, args...)
 	if err != nil {
 		_ = tx.Rollback()
 		return 0, fmt.Errorf("query duplicates: %w", err)
@@ -380,30 +707,83 @@ func (s *Store) Deduplicate(ctx context.Context) (int, error) {
 		channel  string
 	}
 
+	// seen is where and when a kept text was posted.
+	type seen struct {
+		source, channel string
+		postedAt        string
+	}
+
+	// keptVector is the embedding of a kept post.
+	type keptVector struct {
+		id     int64
+		vector embed.Vector
+	}
+
 	var (
-		lastHash string
-		keeperID int64
-		toDelete []dupEntry
+		keepers    = make(map[string]int64) // text_hash -> keeper ID
+		firsts     = make(map[string]seen)  // text_hash -> keeper
+		urlKeepers = make(map[string]int64) // canonical_url -> keeper ID
+		kept       []keptVector
+		toDelete   []dupEntry
 	)
 
 	for rows.Next() {
 		var (
-			id             int64
-			src, ch        string
-			hash, postedAt string
+			id          int64
+			src, ch     string
+			hash        string
+			fingerprint sql.NullInt64
+			canonical   sql.NullString
+			postedAt    string
 		)
-		if err := rows.Scan(&id, &src, &ch, &hash, &postedAt); err != nil {
+		if err := rows.Scan(&id, &src, &ch, &hash, &fingerprint, &canonical, &postedAt); err != nil {
 			_ = tx.Rollback()
 			return 0, fmt.Errorf("scan duplicate: %w", err)
 		}
-		if hash == lastHash {
+		keeperID, dup := keepers[hash]
+		// The channel posting its own text again is a recurrence to keep,
+		// not a copy to merge.
+		if dup && keeper.RecurringGap > 0 {
+			if first := firsts[hash]; first.source == src && first.channel == ch && recurs(first.postedAt, postedAt, keeper.RecurringGap) {
+				continue
+			}
+		}
+		useURL := keeper.ByURL && canonical.String != ""
+		if !dup && useURL {
+			keeperID, dup = urlKeepers[canonical.String]
+		}
+		// A zero fingerprint means no words to compare.
+		fp := uint64(fingerprint.Int64)
+		useNear := near != nil && fingerprint.Valid && fp != 0
+		if !dup && useNear {
+			keeperID, dup = near.find(fp)
+		}
+		vector, useSemantic := vectors[id]
+		if !dup && useSemantic {
+			for _, k := range kept {
+				if embed.Cosine(vector, k.vector) >= keeper.SemanticSimilarity {
+					keeperID, dup = k.id, true
+					break
+				}
+			}
+		}
+		if dup {
 			toDelete = append(toDelete, dupEntry{
 				dupID: id, keeperID: keeperID, source: src, channel: ch,
 			})
 			continue
 		}
-		lastHash = hash
-		keeperID = id
+		keepers[hash] = id
+		firsts[hash] = seen{source: src, channel: ch, postedAt: postedAt}
+		if useURL {
+			urlKeepers[canonical.String] = id
+		}
+		if useNear {
+			near.add(fp, id)
+		}
+		if useSemantic {
+			kept = append(kept, keptVector{id: id, vector: vector})
+		}
 	}
 	if err := rows.Err(); err != nil {
 		_ = tx.Rollback()
@@ -413,7 +793,7 @@ func (s *Store) Deduplicate(ctx context.Context) (int, error) {
 	deleted := 0
 	for _, dup := range toDelete {
 		_, err := tx.ExecContext(ctx,
-			"INSERT OR IGNORE INTO post_also_in(post_id, source, channel) VALUES(?, ?, ?)",
+			"INSERT INTO post_also_in(post_id, source, channel) VALUES(?, ?, ?) ON CONFLICT DO NOTHING",
 			dup.keeperID, dup.source, dup.channel,
 		)
 		if err != nil {
@@ -421,6 +801,64 @@ func (s *Store) Deduplicate(ctx context.Context) (int, error) {
 			return 0, fmt.Errorf("insert also_in: %w", err)
 		}
 
+		// A former keeper may lose to a newer, preferred copy; carry its
+		// also_in entries over before the cascade deletes them.
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO post_also_in(post_id, source, channel)
+			SELECT ?, source, channel FROM post_also_in WHERE post_id = ?
+			ON CONFLICT DO NOTHING`,
+			dup.keeperID, dup.dupID,
+		)
+		if err != nil {
+			_ = tx.Rollback()
+			return 0, fmt.Errorf("move also_in: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO stars(post_id, starred_at)
+			SELECT ?, starred_at FROM stars WHERE post_id = ?
+			ON CONFLICT DO NOTHING`,
+			dup.keeperID, dup.dupID,
+		)
+		if err != nil {
+			_ = tx.Rollback()
+			return 0, fmt.Errorf("move star: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO feedback(post_id, vote, reason, created_at)
+			SELECT ?, vote, reason, created_at FROM feedback WHERE post_id = ?
+			ON CONFLICT DO NOTHING`,
+			dup.keeperID, dup.dupID,
+		)
+		if err != nil {
+			_ = tx.Rollback()
+			return 0, fmt.Errorf("move feedback: %w", err)
+		}
+
+		// Reading any copy counts as reading the story.
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO read_state(post_id, read_at)
+			SELECT ?, read_at FROM read_state WHERE post_id = ?
+			ON CONFLICT DO NOTHING`,
+			dup.keeperID, dup.dupID,
+		)
+		if err != nil {
+			_ = tx.Rollback()
+			return 0, fmt.Errorf("move read state: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO digest_shown(post_id, shown_at)
+			SELECT ?, shown_at FROM digest_shown WHERE post_id = ?
+			ON CONFLICT DO NOTHING`,
+			dup.keeperID, dup.dupID,
+		)
+		if err != nil {
+			_ = tx.Rollback()
+			return 0, fmt.Errorf("move digest shown: %w", err)
+		}
+
 		if _, err := tx.ExecContext(ctx, "DELETE FROM scores WHERE post_id = ?", dup.dupID); err != nil {
 			_ = tx.Rollback()
 			return 0, fmt.Errorf("delete duplicate score: %w", err)
@@ -439,50 +877,175 @@ func (s *Store) Deduplicate(ctx context.Context) (int, error) {
 	return deleted, nil
 }
 
-// PruneOld deletes posts older than retainDays and their associated scores.
-// post_also_in rows are cascade-deleted. Returns the number of posts removed.
+// PruneOld tombstones unstarred posts older than retainDays; see
+// PruneWithPolicy. Returns the number of posts tombstoned.
 func (s *Store) PruneOld(ctx context.Context, retainDays int) (int64, error) {
+	return s.PruneWithPolicy(ctx, RetentionPolicy{DefaultDays: retainDays})
+}
+
+// SlimOld drops the full text of unstarred posts older than afterDays,
+// keeping the snippet, score, and metadata for stats and history. Slimmed
+// posts have an empty Text. Returns the number of posts slimmed.
+func (s *Store) SlimOld(ctx context.Context, afterDays int) (int64, error) {
+	return s.SlimWithPolicy(ctx, RetentionPolicy{DefaultDays: afterDays})
+}
+
+// MarkRead marks the given posts as read at time at. Posts already read keep
+// their original read time; newly read ones are counted in UsageRead.
+func (s *Store) MarkRead(ctx context.Context, at time.Time, postIDs ...int64) error {
 	if s == nil || s.db == nil {
-		return 0, errors.New("store is not initialized")
+		return errors.New("store is not initialized")
 	}
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	if retainDays <= 0 {
-		return 0, nil
+	if len(postIDs) == 0 {
+		return nil
 	}
 
-	cutoff := formatTime(time.Now().AddDate(0, 0, -retainDays))
-
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return 0, fmt.Errorf("begin prune transaction: %w", err)
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	readAt := formatTime(at)
+	var marked int64
+	for _, id := range postIDs {
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO read_state(post_id, read_at) SELECT id, ? FROM posts WHERE id = ? AND deleted_at IS NULL ON CONFLICT DO NOTHING",
+			readAt, id,
+		)
+		if err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("mark read: %w", err)
+		}
+		n, _ := res.RowsAffected()
+		marked += n
+	}
+	if marked > 0 {
+		if _, err := tx.ExecContext(ctx, addUsageQuery, usageDay(at), UsageRead, marked); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("add usage: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit mark read: %w", err)
+	}
+	return nil
+}
+
+// MarkAllRead marks every unread post fetched at or before at as read.
+// Returns the number of posts newly marked. Catching up this way is not
+// reading, so it is not counted in UsageRead.
+func (s *Store) MarkAllRead(ctx context.Context, at time.Time) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	readAt := formatTime(at)
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO read_state(post_id, read_at)
+		SELECT id, ? FROM posts WHERE fetched_at <= ? AND deleted_at IS NULL
+		ON CONFLICT DO NOTHING`, readAt, readAt)
+	if err != nil {
+		return 0, fmt.Errorf("mark all read: %w", err)
 	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
 
-	// Delete scores for old posts (no CASCADE on scores FK)
-	if _, err := tx.ExecContext(ctx,
-		"DELETE FROM scores WHERE post_id IN (SELECT id FROM posts WHERE posted_at < ?)", cutoff,
-	); err != nil {
-		_ = tx.Rollback()
-		return 0, fmt.Errorf("prune old scores: %w", err)
+const starredClause = " AND EXISTS (SELECT 1 FROM stars st WHERE st.post_id = p.id)"
+
+// liveClause hides posts tombstoned by pruning from reads.
+const liveClause = " AND p.deleted_at IS NULL"
+
+// Star adds a post to the reading queue. Starring twice keeps the first time
+// and counts once in UsageStarred.
+func (s *Store) Star(ctx context.Context, postID int64, at time.Time) error {
+	if s == nil || s.db == nil {
+		return errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
 	}
 
-	// Delete old posts (post_also_in cascades)
-	res, err := tx.ExecContext(ctx, "DELETE FROM posts WHERE posted_at < ?", cutoff)
+	res, err := s.db.ExecContext(ctx,
+		"INSERT INTO stars(post_id, starred_at) SELECT id, ? FROM posts WHERE id = ? AND deleted_at IS NULL ON CONFLICT DO NOTHING",
+		formatTime(at), postID,
+	)
 	if err != nil {
-		_ = tx.Rollback()
-		return 0, fmt.Errorf("prune old posts: %w", err)
+		return fmt.Errorf("star post: %w", err)
 	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		return s.AddUsage(ctx, at, UsageStarred, n)
+	}
+	var exists int
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM posts WHERE id = ? AND deleted_at IS NULL", postID).Scan(&exists); err != nil {
+		return fmt.Errorf("check post: %w", err)
+	}
+	if exists == 0 {
+		return fmt.Errorf("post %d not found", postID)
+	}
+	return nil
+}
 
-	if err := tx.Commit(); err != nil {
-		return 0, fmt.Errorf("commit prune: %w", err)
+// Unstar removes a post from the reading queue. Returns false if it was not starred.
+func (s *Store) Unstar(ctx context.Context, postID int64) (bool, error) {
+	if s == nil || s.db == nil {
+		return false, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
 	}
 
+	res, err := s.db.ExecContext(ctx, "DELETE FROM stars WHERE post_id = ?", postID)
+	if err != nil {
+		return false, fmt.Errorf("unstar post: %w", err)
+	}
 	n, _ := res.RowsAffected()
-	return n, nil
+	return n > 0, nil
 }
 
-// DeleteAllScores removes all rows from the scores table.
+// GetStarred returns all starred posts, most recently starred first.
+func (s *Store) GetStarred(ctx context.Context) ([]PostWithScore, error) {
+	if s == nil || s.db == nil {
+		return nil, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT p.id, p.source, p.channel, p.external_id, p.text, p.snippet, p.text_hash, p.url, p.author, p.language, p.posted_at, p.fetched_at,
+			s.score, s.labels, s.tier, s.scored_at, s.explanation, s.text_hash, s.taste_hash
+		FROM stars st
+		JOIN posts p ON p.id = st.post_id
+		LEFT JOIN scores s ON s.post_id = p.id`+s.scoreProfile()+`
+		WHERE p.deleted_at IS NULL
+		ORDER BY st.starred_at DESC, p.id DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("get starred: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var posts []PostWithScore
+	for rows.Next() {
+		post, score, err := scanPostWithScore(rows)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, PostWithScore{Post: post, Score: score})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate starred: %w", err)
+	}
+	return posts, nil
+}
+
+// DeleteAllScores removes all scores of the store's taste profile.
 // Returns the number of rows deleted.
 func (s *Store) DeleteAllScores(ctx context.Context) (int64, error) {
 	if s == nil || s.db == nil {
@@ -492,7 +1055,7 @@ func (s *Store) DeleteAllScores(ctx context.Context) (int64, error) {
 		ctx = context.Background()
 	}
 
-	res, err := s.db.ExecContext(ctx, "DELETE FROM scores")
+	res, err := s.db.ExecContext(ctx, "DELETE FROM scores WHERE profile = ?", s.profile)
 	if err != nil {
 		return 0, fmt.Errorf("delete all scores: %w", err)
 	}
@@ -502,7 +1065,7 @@ func (s *Store) DeleteAllScores(ctx context.Context) (int64, error) {
 }
 
 // GetAlsoIn returns "also seen in" channels for the given post IDs.
-// Returns a map of postID → ["source/channel", ...].
+// Returns a map of postID → ["source/channel", ...], sorted by source and channel.
 func (s *Store) GetAlsoIn(ctx context.Context, postIDs []int64) (map[int64][]string, error) {
 	if len(postIDs) == 0 {
 		return nil, nil
@@ -516,7 +1079,7 @@ func (s *Store) GetAlsoIn(ctx context.Context, postIDs []int64) (map[int64][]str
 	}
 
 	query := fmt.Sprintf(
-		"SELECT post_id, source, channel FROM post_also_in WHERE post_id IN (%s)",
+		"SELECT post_id, source, channel FROM post_also_in WHERE post_id IN (%s) ORDER BY source, channel",
 		strings.Join(placeholders, ","),
 	)
 
@@ -542,16 +1105,391 @@ func (s *Store) GetAlsoIn(ctx context.Context, postIDs []int64) (map[int64][]str
 	return result, nil
 }
 
+// SearchFilter narrows full-text search results.
+type SearchFilter struct {
+	Since       time.Time // only posts published at or after Since; zero means all
+	Tier        string    // only posts scored into this tier; empty means any
+	Limit       int       // maximum results; 0 means no limit
+	StarredOnly bool      // only starred posts
+	ExcludeID   int64     // leave out this post, e.g. the one similar posts are sought for
+}
+
+// SearchResult is a post matched by Search, best matches first.
+type SearchResult struct {
+	PostWithScore
+	Rank float64 // backend relevance rank (bm25 on sqlite); lower is better
+}
+
+// Search returns posts whose text or snippet matches query, ranked by
+// relevance. Each whitespace-separated term is matched literally (no FTS
+// operators) and all terms must match.
+func (s *Store) Search(ctx context.Context, query string, filter SearchFilter) ([]SearchResult, error) {
+	if s == nil || s.db == nil {
+		return nil, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if strings.TrimSpace(query) == "" {
+		return nil, errors.New("search query is empty")
+	}
+	fts := s.backend.FullText(query)
+
+	join := "LEFT JOIN"
+	if filter.Tier != "" {
+		join = "JOIN"
+	}
+
+	q := fmt.Sprintf(`
+		SELECT p.id, p.source, p.channel, p.external_id, p.text, p.snippet, p.text_hash, p.url, p.author, p.language, p.posted_at, p.fetched_at,
+			s.score, s.labels, s.tier, s.scored_at, s.explanation, s.text_hash, s.taste_hash, %s AS rank
+		FROM %s
+		%s scores s ON s.post_id = p.id`+s.scoreProfile()+`
+		WHERE %s AND `+s.effectiveTime()+` >= ?`+liveClause, fts.Rank, fts.From, join, fts.Match)
+	args := append(fts.Args, formatTime(filter.Since))
+
+	if filter.Tier != "" {
+		q += " AND s.tier = ?"
+		args = append(args, filter.Tier)
+	}
+	if filter.StarredOnly {
+		q += starredClause
+	}
+	q += " ORDER BY rank, " + s.effectiveTime() + " DESC"
+	if filter.Limit > 0 {
+		q += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("search posts: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var results []SearchResult
+	for rows.Next() {
+		var rank float64
+		post, score, err := scanPostWithScore(rankScanner{rows, &rank})
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, SearchResult{
+			PostWithScore: PostWithScore{Post: post, Score: score},
+			Rank:          rank,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate search results: %w", err)
+	}
+
+	return results, nil
+}
+
+// rankScanner appends the trailing rank column to a post-with-score scan.
+type rankScanner struct {
+	rows *sql.Rows
+	rank *float64
+}
+
+func (r rankScanner) Scan(dest ...any) error {
+	return r.rows.Scan(append(dest, r.rank)...)
+}
+
+// ftsQuery quotes every term so user input such as "CVE-2026-1234" or
+// "c++" is matched literally instead of being parsed as FTS5 syntax.
+func ftsQuery(query string) string {
+	terms := strings.Fields(query)
+	for i, term := range terms {
+		terms[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+	}
+	return strings.Join(terms, " ")
+}
+
+const lastPullKey = "last_pull_at"
+
+// SetLastPull records the time of the most recent successful pull.
+func (s *Store) SetLastPull(ctx context.Context, t time.Time) error {
+	if s == nil || s.db == nil {
+		return errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO metadata(key, value) VALUES(?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value
+	`, lastPullKey, formatTime(t))
+	if err != nil {
+		return fmt.Errorf("set last pull: %w", err)
+	}
+	return nil
+}
+
+// LastPull returns the time of the most recent successful pull.
+// Returns the zero time if no pull has been recorded.
+func (s *Store) LastPull(ctx context.Context) (time.Time, error) {
+	if s == nil || s.db == nil {
+		return time.Time{}, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var value string
+	err := s.db.QueryRowContext(ctx, "SELECT value FROM metadata WHERE key = ?", lastPullKey).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("get last pull: %w", err)
+	}
+
+	t, err := parseTime(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse last pull: %w", err)
+	}
+	return t, nil
+}
+
+// Ping verifies the database connection is usable.
+func (s *Store) Ping(ctx context.Context) error {
+	if s == nil || s.db == nil {
+		return errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("ping: %w", err)
+	}
+	var n int
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM metadata").Scan(&n); err != nil {
+		return fmt.Errorf("query metadata: %w", err)
+	}
+	return nil
+}
+
+const lastDigestKey = "last_digest_at"
+
+// SetLastDigest records the time of the most recent digest.
+func (s *Store) SetLastDigest(ctx context.Context, t time.Time) error {
+	if s == nil || s.db == nil {
+		return errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO metadata(key, value) VALUES(?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value
+	`, lastDigestKey, formatTime(t))
+	if err != nil {
+		return fmt.Errorf("set last digest: %w", err)
+	}
+	return nil
+}
+
+// LastDigest returns the time of the most recent digest.
+// Returns the zero time if no digest has been recorded.
+func (s *Store) LastDigest(ctx context.Context) (time.Time, error) {
+	if s == nil || s.db == nil {
+		return time.Time{}, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var value string
+	err := s.db.QueryRowContext(ctx, "SELECT value FROM metadata WHERE key = ?", lastDigestKey).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("get last digest: %w", err)
+	}
+
+	t, err := parseTime(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse last digest: %w", err)
+	}
+	return t, nil
+}
+
+// FeedStatus is the last known fetch state of one configured feed.
+type FeedStatus struct {
+	Source       string
+	Feed         string
+	LastError    string    // empty when the last fetch succeeded
+	FailingSince time.Time // zero when the last fetch succeeded
+	LastOK       time.Time // zero if the feed never succeeded
+}
+
+// RecordFeedStatus stores the outcome of fetching one feed at time at.
+// An empty fetchErr marks the feed healthy; a non-empty one starts (or
+// continues) a failure streak.
+func (s *Store) RecordFeedStatus(ctx context.Context, source, feed, fetchErr string, at time.Time) error {
+	if s == nil || s.db == nil {
+		return errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var err error
+	if fetchErr == "" {
+		_, err = s.db.ExecContext(ctx, `
+			INSERT INTO feed_status(source, feed, last_error, failing_since, last_ok_at, checked_at)
+			VALUES(?, ?, NULL, NULL, ?, ?)
+			ON CONFLICT(source, feed) DO UPDATE SET
+				last_error = NULL, failing_since = NULL,
+				last_ok_at = excluded.last_ok_at, checked_at = excluded.checked_at
+		`, source, feed, formatTime(at), formatTime(at))
+	} else {
+		_, err = s.db.ExecContext(ctx, `
+			INSERT INTO feed_status(source, feed, last_error, failing_since, last_ok_at, checked_at)
+			VALUES(?, ?, ?, ?, NULL, ?)
+			ON CONFLICT(source, feed) DO UPDATE SET
+				last_error = excluded.last_error,
+				failing_since = COALESCE(feed_status.failing_since, excluded.failing_since),
+				checked_at = excluded.checked_at
+		`, source, feed, fetchErr, formatTime(at), formatTime(at))
+	}
+	if err != nil {
+		return fmt.Errorf("record feed status: %w", err)
+	}
+	return nil
+}
+
+// GetFailingFeeds returns feeds that are currently failing and whose failure
+// streak began at or after since.
+func (s *Store) GetFailingFeeds(ctx context.Context, since time.Time) ([]FeedStatus, error) {
+	if s == nil || s.db == nil {
+		return nil, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT source, feed, last_error, failing_since, last_ok_at
+		FROM feed_status
+		WHERE failing_since IS NOT NULL AND failing_since >= ?
+		ORDER BY source, feed
+	`, formatTime(since))
+	if err != nil {
+		return nil, fmt.Errorf("get failing feeds: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var feeds []FeedStatus
+	for rows.Next() {
+		var (
+			fs          FeedStatus
+			failing     string
+			lastErr, ok sql.NullString
+		)
+		if err := rows.Scan(&fs.Source, &fs.Feed, &lastErr, &failing, &ok); err != nil {
+			return nil, fmt.Errorf("scan feed status: %w", err)
+		}
+		fs.LastError = lastErr.String
+		if fs.FailingSince, err = parseTime(failing); err != nil {
+			return nil, fmt.Errorf("parse failing_since: %w", err)
+		}
+		if ok.Valid {
+			if fs.LastOK, err = parseTime(ok.String); err != nil {
+				return nil, fmt.Errorf("parse last_ok_at: %w", err)
+			}
+		}
+		feeds = append(feeds, fs)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate feed status: %w", err)
+	}
+	return feeds, nil
+}
+
+// ChannelActivity is the first and last time a channel was seen.
+type ChannelActivity struct {
+	Source    string
+	Channel   string
+	FirstSeen time.Time // earliest fetched_at
+	LastPost  time.Time // latest posted_at
+}
+
+// GetNewChannels returns channels whose first post was fetched at or after since.
+func (s *Store) GetNewChannels(ctx context.Context, since time.Time) ([]ChannelActivity, error) {
+	return s.channelActivity(ctx, "HAVING MIN(p.fetched_at) >= ?", formatTime(since))
+}
+
+// GetSilentChannels returns channels whose latest post became older than
+// silence between prev and now — that is, channels that went quiet since prev.
+func (s *Store) GetSilentChannels(ctx context.Context, prev, now time.Time, silence time.Duration) ([]ChannelActivity, error) {
+	return s.channelActivity(ctx, "HAVING MAX("+s.effectiveTime()+") >= ? AND MAX("+s.effectiveTime()+") < ?",
+		formatTime(prev.Add(-silence)), formatTime(now.Add(-silence)))
+}
+
+func (s *Store) channelActivity(ctx context.Context, having string, args ...any) ([]ChannelActivity, error) {
+	if s == nil || s.db == nil {
+		return nil, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT p.source, p.channel, MIN(p.fetched_at), MAX(`+s.effectiveTime()+`)
+		FROM posts p
+		WHERE p.deleted_at IS NULL
+		GROUP BY p.source, p.channel
+		`+having+`
+		ORDER BY p.source, p.channel
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get channel activity: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []ChannelActivity
+	for rows.Next() {
+		var (
+			ca              ChannelActivity
+			first, lastPost string
+		)
+		if err := rows.Scan(&ca.Source, &ca.Channel, &first, &lastPost); err != nil {
+			return nil, fmt.Errorf("scan channel activity: %w", err)
+		}
+		if ca.FirstSeen, err = parseTime(first); err != nil {
+			return nil, fmt.Errorf("parse first fetched: %w", err)
+		}
+		if ca.LastPost, err = parseTime(lastPost); err != nil {
+			return nil, fmt.Errorf("parse last post: %w", err)
+		}
+		out = append(out, ca)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate channel activity: %w", err)
+	}
+	return out, nil
+}
+
 // ChannelStats holds aggregated scoring stats for one channel.
 type ChannelStats struct {
 	Source    string
 	Channel   string
 	Total     int
 	ReadNow   int
-	Skim      int
+	Skim      int // every tier between read_now and ignore
 	Ignored   int
 	FirstSeen time.Time
 	LastSeen  time.Time
+
+	// Tiers counts posts per tier name, unscored posts as ignore, for
+	// profiles with tiers beyond the default three.
+	Tiers map[string]int
 }
 
 // GetChannelStats returns per-channel scoring aggregates for posts since the given time.
@@ -567,13 +1505,13 @@ func (s *Store) GetChannelStats(ctx context.Context, since time.Time) ([]Channel
 		SELECT p.source, p.channel,
 			COUNT(*) AS total,
 			SUM(CASE WHEN s.tier = 'read_now' THEN 1 ELSE 0 END) AS read_now,
-			SUM(CASE WHEN s.tier = 'skim' THEN 1 ELSE 0 END) AS skim,
+			SUM(CASE WHEN s.tier NOT IN ('read_now', 'ignore') THEN 1 ELSE 0 END) AS skim,
 			SUM(CASE WHEN s.tier = 'ignore' OR s.tier IS NULL THEN 1 ELSE 0 END) AS ignored,
-			MIN(p.posted_at) AS first_seen,
-			MAX(p.posted_at) AS last_seen
+			MIN(`+s.effectiveTime()+`) AS first_seen,
+			MAX(`+s.effectiveTime()+`) AS last_seen
 		FROM posts p
-		LEFT JOIN scores s ON s.post_id = p.id
-		WHERE p.posted_at >= ?
+		LEFT JOIN scores s ON s.post_id = p.id`+s.scoreProfile()+`
+		WHERE `+s.effectiveTime()+` >= ?`+liveClause+`
 		GROUP BY p.source, p.channel
 		ORDER BY p.source, p.channel
 	`, formatTime(since))
@@ -602,10 +1540,53 @@ func (s *Store) GetChannelStats(ctx context.Context, since time.Time) ([]Channel
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("iterate channel stats: %w", err)
 	}
+	if err := s.fillTierCounts(ctx, since, stats); err != nil {
+		return nil, err
+	}
 
 	return stats, nil
 }
 
+// fillTierCounts sets the per-tier counts of stats, which are ordered by
+// source and channel as GetChannelStats returns them.
+func (s *Store) fillTierCounts(ctx context.Context, since time.Time, stats []ChannelStats) error {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT p.source, p.channel, COALESCE(s.tier, 'ignore') AS tier, COUNT(*)
+		FROM posts p
+		LEFT JOIN scores s ON s.post_id = p.id`+s.scoreProfile()+`
+		WHERE `+s.effectiveTime()+` >= ?`+liveClause+`
+		GROUP BY p.source, p.channel, tier
+	`, formatTime(since))
+	if err != nil {
+		return fmt.Errorf("get tier counts: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	index := make(map[[2]string]int, len(stats))
+	for i, cs := range stats {
+		index[[2]string{cs.Source, cs.Channel}] = i
+	}
+	for rows.Next() {
+		var source, channel, tier string
+		var n int
+		if err := rows.Scan(&source, &channel, &tier, &n); err != nil {
+			return fmt.Errorf("scan tier counts: %w", err)
+		}
+		i, ok := index[[2]string{source, channel}]
+		if !ok {
+			continue
+		}
+		if stats[i].Tiers == nil {
+			stats[i].Tiers = make(map[string]int)
+		}
+		stats[i].Tiers[tier] += n
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate tier counts: %w", err)
+	}
+	return nil
+}
+
 type rowScanner interface {
 	Scan(dest ...any) error
 }
@@ -614,6 +1595,7 @@ func scanPost(scanner rowScanner) (Post, error) {
 	var (
 		post                Post
 		textVal, urlVal     sql.NullString
+		authorVal, langVal  sql.NullString
 		postedAt, fetchedAt string
 	)
 
@@ -626,6 +1608,8 @@ func scanPost(scanner rowScanner) (Post, error) {
 		&post.Snippet,
 		&post.TextHash,
 		&urlVal,
+		&authorVal,
+		&langVal,
 		&postedAt,
 		&fetchedAt,
 	); err != nil {
@@ -638,6 +1622,8 @@ func scanPost(scanner rowScanner) (Post, error) {
 	if urlVal.Valid {
 		post.URL = urlVal.String
 	}
+	post.Author = authorVal.String
+	post.Language = langVal.String
 
 	var err error
 	post.PostedAt, err = parseTime(postedAt)
@@ -656,10 +1642,12 @@ func scanPostWithScore(scanner rowScanner) (Post, *Score, error) {
 	var (
 		post                        Post
 		textVal, urlVal             sql.NullString
+		authorVal, langVal          sql.NullString
 		postedAt, fetchedAt         string
 		scoreVal                    sql.NullInt64
 		labelsVal, tierVal          sql.NullString
 		scoredAtVal, explanationVal sql.NullString
+		scoredHashVal, tasteHashVal sql.NullString
 	)
 
 	if err := scanner.Scan(
@@ -671,6 +1659,8 @@ func scanPostWithScore(scanner rowScanner) (Post, *Score, error) {
 		&post.Snippet,
 		&post.TextHash,
 		&urlVal,
+		&authorVal,
+		&langVal,
 		&postedAt,
 		&fetchedAt,
 		&scoreVal,
@@ -678,6 +1668,8 @@ func scanPostWithScore(scanner rowScanner) (Post, *Score, error) {
 		&tierVal,
 		&scoredAtVal,
 		&explanationVal,
+		&scoredHashVal,
+		&tasteHashVal,
 	); err != nil {
 		return Post{}, nil, fmt.Errorf("scan post with score: %w", err)
 	}
@@ -688,6 +1680,8 @@ func scanPostWithScore(scanner rowScanner) (Post, *Score, error) {
 	if urlVal.Valid {
 		post.URL = urlVal.String
 	}
+	post.Author = authorVal.String
+	post.Language = langVal.String
 
 	var err error
 	post.PostedAt, err = parseTime(postedAt)
@@ -731,6 +1725,8 @@ func scanPostWithScore(scanner rowScanner) (Post, *Score, error) {
 		Tier:        tierVal.String,
 		ScoredAt:    scoredAt,
 		Explanation: explanation,
+		TextHash:    scoredHashVal.String,
+		TasteHash:   tasteHashVal.String,
 	}
 
 	return post, score, nil
@@ -4,8 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -35,7 +39,7 @@ func TestOpenAndMigrate(t *testing.T) {
 	if err := st.db.QueryRow("SELECT value FROM metadata WHERE key = 'schema_version'").Scan(&version); err != nil {
 		t.Fatalf("read schema version: %v", err)
 	}
-	if version != "2" {
+	if version != "17" {
 		t.Fatalf("unexpected schema version: %s", version)
 	}
 }
@@ -362,6 +366,63 @@ func TestGetPosts_FilterCombined(t *testing.T) {
 	}
 }
 
+func TestGetPosts_OrderLimitOffset(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+
+	base := time.Date(2026, 2, 16, 8, 0, 0, 0, time.UTC)
+
+	// Inserted out of posted order: IDs 1..4 posted at +2h, +0h, +3h, +1h.
+	for i, h := range []int{2, 0, 3, 1} {
+		at := base.Add(time.Duration(h) * time.Hour)
+		if _, err := st.InsertPost(ctx, PostInput{
+			Source: "rss", Channel: "blog", ExternalID: fmt.Sprint(i),
+			Text: fmt.Sprintf("post %d", i), PostedAt: at, FetchedAt: at.Add(time.Minute),
+		}); err != nil {
+			t.Fatalf("insert %d: %v", i, err)
+		}
+	}
+
+	ids := func(f PostFilter) []int64 {
+		t.Helper()
+		posts, err := st.GetPosts(ctx, time.Time{}, "", f)
+		if err != nil {
+			t.Fatalf("get posts %+v: %v", f, err)
+		}
+		var out []int64
+		for _, p := range posts {
+			out = append(out, p.Post.ID)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name   string
+		filter PostFilter
+		want   []int64
+	}{
+		{"newest", PostFilter{}, []int64{3, 1, 4, 2}},
+		{"newest page 2", PostFilter{Limit: 2, Offset: 2}, []int64{4, 2}},
+		{"oldest limit", PostFilter{Order: OrderOldest, Limit: 3}, []int64{2, 4, 1}},
+		{"offset only", PostFilter{Order: OrderOldest, Offset: 3}, []int64{3}},
+		{"keyset", PostFilter{Order: OrderIngested, AfterID: 2, Limit: 1}, []int64{3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ids(tt.filter); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("ids = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := st.GetPosts(ctx, time.Time{}, "", PostFilter{Order: "sideways"}); err == nil {
+		t.Error("expected error for unknown order")
+	}
+	if _, err := st.GetPosts(ctx, time.Time{}, "", PostFilter{Limit: -1}); err == nil {
+		t.Error("expected error for negative limit")
+	}
+}
+
 func TestDeduplicate(t *testing.T) {
 	st, _ := openTestStore(t)
 	ctx := context.Background()
@@ -527,6 +588,289 @@ func TestDeduplicate_MultipleAlsoIn(t *testing.T) {
 	}
 }
 
+func insertDedupFixtures(t *testing.T, st *Store) {
+	t.Helper()
+	base := time.Date(2026, 2, 16, 14, 0, 0, 0, time.UTC)
+	tg := PostInput{
+		Source: "telegram", Channel: "chan1", ExternalID: "1",
+		Text: "same content", PostedAt: base, FetchedAt: base,
+	}
+	rss := PostInput{
+		Source: "rss", Channel: "feed1", ExternalID: "a",
+		Text: "same content", PostedAt: base.Add(time.Hour), FetchedAt: base.Add(time.Hour),
+	}
+	for _, in := range []PostInput{tg, rss} {
+		if _, err := st.InsertPost(context.Background(), in); err != nil {
+			t.Fatalf("insert %s: %v", in.Source, err)
+		}
+	}
+}
+
+func remainingSources(t *testing.T, st *Store) []string {
+	t.Helper()
+	rows, err := st.db.Query("SELECT source FROM posts ORDER BY id")
+	if err != nil {
+		t.Fatalf("query posts: %v", err)
+	}
+	defer func() { _ = rows.Close() }()
+	var out []string
+	for rows.Next() {
+		var src string
+		if err := rows.Scan(&src); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		out = append(out, src)
+	}
+	return out
+}
+
+func TestDeduplicateWith_SourceOrder(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	insertDedupFixtures(t, st)
+
+	deleted, err := st.DeduplicateWith(ctx, DedupKeeper{Strategy: DedupSource, SourceOrder: []string{"rss", "telegram"}})
+	if err != nil {
+		t.Fatalf("deduplicate: %v", err)
+	}
+	if deleted != 1 {
+		t.Fatalf("expected 1 deleted, got %d", deleted)
+	}
+	if got := remainingSources(t, st); len(got) != 1 || got[0] != "rss" {
+		t.Errorf("remaining = %v, want [rss]", got)
+	}
+}
+
+func TestDeduplicateWith_Longest(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+
+	base := time.Date(2026, 2, 16, 14, 0, 0, 0, time.UTC)
+	short, err := st.InsertPost(ctx, PostInput{
+		Source: "telegram", Channel: "chan1", ExternalID: "1",
+		Text: "Kubernetes 1.40 released", PostedAt: base, FetchedAt: base,
+	})
+	if err != nil {
+		t.Fatalf("insert short: %v", err)
+	}
+	long, err := st.InsertPost(ctx, PostInput{
+		Source: "rss", Channel: "feed1", ExternalID: "a",
+		Text: "Kubernetes 1.40 released", PostedAt: base.Add(time.Hour), FetchedAt: base.Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("insert long: %v", err)
+	}
+	// Same hash, but the RSS copy stored the full body.
+	if _, err := st.db.Exec("UPDATE posts SET text = ?, text_hash = ? WHERE id = ?",
+		"Kubernetes 1.40 released with a long changelog", "h", long.ID); err != nil {
+		t.Fatalf("update long: %v", err)
+	}
+	if _, err := st.db.Exec("UPDATE posts SET text_hash = ? WHERE id = ?", "h", short.ID); err != nil {
+		t.Fatalf("update short: %v", err)
+	}
+
+	if _, err := st.DeduplicateWith(ctx, DedupKeeper{Strategy: DedupLongest}); err != nil {
+		t.Fatalf("deduplicate: %v", err)
+	}
+	if got := remainingSources(t, st); len(got) != 1 || got[0] != "rss" {
+		t.Errorf("remaining = %v, want [rss]", got)
+	}
+}
+
+func TestDeduplicateWith_LongestNearDuplicate(t *testing.T) {
+	// A crosspost reworded and cut short, posted first, and the full story:
+	// near duplicates, so the strategy picks between them.
+	base := time.Date(2026, 2, 16, 14, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		strategy, want string
+	}{
+		{DedupEarliest, "telegram"},
+		{DedupLongest, "rss"},
+	} {
+		st, _ := openTestStore(t)
+		ctx := context.Background()
+		for _, in := range []PostInput{
+			{Source: "telegram", Channel: "chan1", ExternalID: "1", PostedAt: base,
+				Text: "Critical vulnerability CVE-2026-1234 found in OpenSSL 3.2, attackers can execute remote code. Patch now available, upgrade immediately."},
+			{Source: "rss", Channel: "feed1", ExternalID: "a", PostedAt: base.Add(time.Hour),
+				Text: "CVE-2026-1234: critical vulnerability found in OpenSSL 3.2 — attackers can execute remote code. A patch is now available, upgrade immediately! Details of the affected builds follow."},
+		} {
+			in.FetchedAt = in.PostedAt
+			if _, err := st.InsertPost(ctx, in); err != nil {
+				t.Fatalf("insert %s: %v", in.ExternalID, err)
+			}
+		}
+
+		if _, err := st.DeduplicateWith(ctx, DedupKeeper{Strategy: tc.strategy, Similarity: 0.8}); err != nil {
+			t.Fatalf("%s: deduplicate: %v", tc.strategy, err)
+		}
+		if got := remainingSources(t, st); len(got) != 1 || got[0] != tc.want {
+			t.Errorf("%s kept %v, want [%s]", tc.strategy, got, tc.want)
+		}
+	}
+}
+
+func TestDeduplicateWith_Similarity(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+
+	base := time.Date(2026, 2, 16, 14, 0, 0, 0, time.UTC)
+	for i, in := range []PostInput{
+		{Source: "telegram", Channel: "chan1", ExternalID: "1", PostedAt: base,
+			Text: "Critical vulnerability CVE-2026-1234 found in OpenSSL 3.2, attackers can execute remote code. Patch now available, upgrade immediately."},
+		{Source: "telegram", Channel: "chan2", ExternalID: "2", PostedAt: base.Add(time.Hour),
+			Text: "CVE-2026-1234: critical vulnerability found in OpenSSL 3.2 — attackers can execute remote code. A patch is now available, upgrade immediately!"},
+		{Source: "rss", Channel: "k8s", ExternalID: "3", PostedAt: base.Add(2 * time.Hour),
+			Text: "Kubernetes 1.33 released with sidecar containers GA and new scheduling features for large clusters."},
+	} {
+		in.FetchedAt = in.PostedAt
+		if _, err := st.InsertPost(ctx, in); err != nil {
+			t.Fatalf("insert %d: %v", i, err)
+		}
+	}
+
+	// Exact matching leaves the reworded copy alone.
+	if deleted, err := st.Deduplicate(ctx); err != nil || deleted != 0 {
+		t.Fatalf("exact dedup: deleted %d, err %v", deleted, err)
+	}
+
+	// Simulate rows stored before fingerprints existed.
+	if _, err := st.db.Exec("UPDATE posts SET simhash = NULL"); err != nil {
+		t.Fatalf("clear simhash: %v", err)
+	}
+
+	deleted, err := st.DeduplicateWith(ctx, DedupKeeper{Similarity: 0.8})
+	if err != nil {
+		t.Fatalf("deduplicate: %v", err)
+	}
+	if deleted != 1 {
+		t.Fatalf("deleted = %d, want 1", deleted)
+	}
+	alsoIn, err := st.GetAlsoIn(ctx, []int64{1})
+	if err != nil {
+		t.Fatalf("get also_in: %v", err)
+	}
+	if got := alsoIn[1]; len(got) != 1 || got[0] != "telegram/chan2" {
+		t.Errorf("also_in = %v, want [telegram/chan2]", got)
+	}
+	if got := remainingSources(t, st); len(got) != 2 {
+		t.Errorf("remaining = %v, want the kept CVE post and the k8s post", got)
+	}
+}
+
+func TestDeduplicateWith_ByURL(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+
+	base := time.Date(2026, 2, 16, 14, 0, 0, 0, time.UTC)
+	day1, err := st.InsertPost(ctx, PostInput{
+		Source: "hn", Channel: "frontpage", ExternalID: "1", PostedAt: base, FetchedAt: base,
+		Text: "Show HN: a faster log shipper", URL: "https://example.com/shipper/",
+	})
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	// A later run picks up a repost with a different title and tracking link.
+	if _, err := st.InsertPost(ctx, PostInput{
+		Source: "telegram", Channel: "devops", ExternalID: "9", PostedAt: base.Add(24 * time.Hour), FetchedAt: base.Add(24 * time.Hour),
+		Text: "This new log shipper is worth a look", URL: "http://www.example.com/shipper?utm_source=telegram",
+	}); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	// Text-only dedup keeps both.
+	if deleted, err := st.Deduplicate(ctx); err != nil || deleted != 0 {
+		t.Fatalf("text dedup: deleted %d, err %v", deleted, err)
+	}
+
+	// Simulate rows stored before canonical URLs existed.
+	if _, err := st.db.Exec("UPDATE posts SET canonical_url = NULL"); err != nil {
+		t.Fatalf("clear canonical_url: %v", err)
+	}
+
+	deleted, err := st.DeduplicateWith(ctx, DedupKeeper{ByURL: true})
+	if err != nil {
+		t.Fatalf("deduplicate: %v", err)
+	}
+	if deleted != 1 {
+		t.Fatalf("deleted = %d, want 1", deleted)
+	}
+	alsoIn, err := st.GetAlsoIn(ctx, []int64{day1.ID})
+	if err != nil {
+		t.Fatalf("get also_in: %v", err)
+	}
+	if got := alsoIn[day1.ID]; len(got) != 1 || got[0] != "telegram/devops" {
+		t.Errorf("also_in = %v, want [telegram/devops]", got)
+	}
+}
+
+func TestDeduplicateWith_MovesAlsoIn(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	insertDedupFixtures(t, st)
+
+	// First run keeps telegram (earliest), second prefers reddit.
+	if _, err := st.Deduplicate(ctx); err != nil {
+		t.Fatalf("first deduplicate: %v", err)
+	}
+	base := time.Date(2026, 2, 16, 16, 0, 0, 0, time.UTC)
+	rd, err := st.InsertPost(ctx, PostInput{
+		Source: "reddit", Channel: "r/devops", ExternalID: "x",
+		Text: "same content", PostedAt: base, FetchedAt: base,
+	})
+	if err != nil {
+		t.Fatalf("insert reddit: %v", err)
+	}
+	if _, err := st.DeduplicateWith(ctx, DedupKeeper{Strategy: DedupSource, SourceOrder: []string{"reddit"}}); err != nil {
+		t.Fatalf("second deduplicate: %v", err)
+	}
+
+	alsoIn, err := st.GetAlsoIn(ctx, []int64{rd.ID})
+	if err != nil {
+		t.Fatalf("get also_in: %v", err)
+	}
+	got := strings.Join(alsoIn[rd.ID], ",")
+	if !strings.Contains(got, "telegram/chan1") || !strings.Contains(got, "rss/feed1") {
+		t.Errorf("also_in = %q, want telegram/chan1 and rss/feed1", got)
+	}
+}
+
+func TestDeduplicateWith_RecurringGap(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+
+	base := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	for i, at := range []time.Time{base, base.Add(time.Hour), base.Add(24 * time.Hour)} {
+		if _, err := st.InsertPost(ctx, PostInput{
+			Source: "rss", Channel: "standup", ExternalID: fmt.Sprint(i),
+			Text: "Daily standup notes", PostedAt: at, FetchedAt: at,
+		}); err != nil {
+			t.Fatalf("insert %d: %v", i, err)
+		}
+	}
+
+	// The copy an hour later is a duplicate; the one a day later recurs.
+	deleted, err := st.DeduplicateWith(ctx, DedupKeeper{RecurringGap: 20 * time.Hour})
+	if err != nil {
+		t.Fatalf("deduplicate: %v", err)
+	}
+	if deleted != 1 {
+		t.Fatalf("deleted = %d, want 1", deleted)
+	}
+	posts, err := st.GetPosts(ctx, base.Add(-time.Hour), "")
+	if err != nil {
+		t.Fatalf("get posts: %v", err)
+	}
+	if len(posts) != 2 {
+		t.Fatalf("remaining = %d posts, want 2", len(posts))
+	}
+
+	// Without a gap recurrences merge too.
+	if deleted, err = st.DeduplicateWith(ctx, DedupKeeper{}); err != nil || deleted != 1 {
+		t.Errorf("deduplicate without gap = %d, %v, want 1 deleted", deleted, err)
+	}
+}
+
 func TestPruneOld(t *testing.T) {
 	st, _ := openTestStore(t)
 	ctx := context.Background()
@@ -577,6 +921,26 @@ func TestPruneOld(t *testing.T) {
 		t.Errorf("pruned = %d, want 1", pruned)
 	}
 
+	// The old post is tombstoned: hidden, but kept with its score.
+	visible, err := st.GetPosts(ctx, time.Time{}, "")
+	if err != nil {
+		t.Fatalf("get posts: %v", err)
+	}
+	if len(visible) != 1 || visible[0].Post.Text != "new post" {
+		t.Errorf("visible posts = %+v, want only the recent one", visible)
+	}
+	if _, err := st.GetPostByID(ctx, oldPost.ID); !errors.Is(err, ErrPostNotFound) {
+		t.Errorf("tombstoned post: err = %v, want ErrPostNotFound", err)
+	}
+
+	purged, err := st.Purge(ctx, time.Now())
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("purged = %d, want 1", purged)
+	}
+
 	// Verify only recent post remains
 	var count int
 	if err := st.db.QueryRow("SELECT COUNT(*) FROM posts").Scan(&count); err != nil {
@@ -736,6 +1100,45 @@ func TestGetChannelStats_UnscoredCountsAsIgnored(t *testing.T) {
 	}
 }
 
+func TestGetChannelStats_CustomTiers(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+
+	base := time.Date(2026, 2, 16, 8, 0, 0, 0, time.UTC)
+
+	for i, tier := range []string{"read_now", "today", "today", "weekend", "ignore", ""} {
+		p, err := st.InsertPost(ctx, PostInput{
+			Source: "rss", Channel: "feed", ExternalID: fmt.Sprint(i),
+			Text: "post", PostedAt: base, FetchedAt: base.Add(time.Minute),
+		})
+		if err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+		if tier == "" {
+			continue
+		}
+		if err := st.SaveScore(ctx, Score{PostID: p.ID, Tier: tier, ScoredAt: base.Add(time.Hour)}); err != nil {
+			t.Fatalf("save score: %v", err)
+		}
+	}
+
+	stats, err := st.GetChannelStats(ctx, base.Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("get channel stats: %v", err)
+	}
+	if len(stats) != 1 {
+		t.Fatalf("expected 1 channel, got %d", len(stats))
+	}
+	cs := stats[0]
+	if cs.ReadNow != 1 || cs.Skim != 3 || cs.Ignored != 2 {
+		t.Errorf("read_now/skim/ignored = %d/%d/%d, want 1/3/2", cs.ReadNow, cs.Skim, cs.Ignored)
+	}
+	want := map[string]int{"read_now": 1, "today": 2, "weekend": 1, "ignore": 2}
+	if !maps.Equal(cs.Tiers, want) {
+		t.Errorf("tiers = %v, want %v", cs.Tiers, want)
+	}
+}
+
 func TestDeleteAllScores(t *testing.T) {
 	st, _ := openTestStore(t)
 	ctx := context.Background()
@@ -815,3 +1218,1023 @@ func TestGetAlsoIn_NilIDs(t *testing.T) {
 		t.Errorf("expected nil, got %v", alsoIn)
 	}
 }
+
+func TestLastPull(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+
+	last, err := st.LastPull(ctx)
+	if err != nil {
+		t.Fatalf("last pull: %v", err)
+	}
+	if !last.IsZero() {
+		t.Fatalf("last pull = %v, want zero before any pull", last)
+	}
+
+	first := time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+	if err := st.SetLastPull(ctx, first); err != nil {
+		t.Fatalf("set last pull: %v", err)
+	}
+	second := first.Add(time.Hour)
+	if err := st.SetLastPull(ctx, second); err != nil {
+		t.Fatalf("set last pull again: %v", err)
+	}
+
+	last, err = st.LastPull(ctx)
+	if err != nil {
+		t.Fatalf("last pull: %v", err)
+	}
+	if !last.Equal(second) {
+		t.Errorf("last pull = %v, want %v", last, second)
+	}
+}
+
+func TestPing(t *testing.T) {
+	st, _ := openTestStore(t)
+	if err := st.Ping(context.Background()); err != nil {
+		t.Fatalf("ping: %v", err)
+	}
+
+	_ = st.Close()
+	if err := st.Ping(context.Background()); err == nil {
+		t.Fatal("expected ping error on closed store")
+	}
+}
+
+func TestLastDigest(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+
+	last, err := st.LastDigest(ctx)
+	if err != nil {
+		t.Fatalf("last digest: %v", err)
+	}
+	if !last.IsZero() {
+		t.Errorf("expected zero time before first digest, got %v", last)
+	}
+
+	at := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	if err := st.SetLastDigest(ctx, at); err != nil {
+		t.Fatalf("set last digest: %v", err)
+	}
+	last, err = st.LastDigest(ctx)
+	if err != nil {
+		t.Fatalf("last digest: %v", err)
+	}
+	if !last.Equal(at) {
+		t.Errorf("last digest = %v, want %v", last, at)
+	}
+}
+
+func TestRecordFeedStatus_FailureStreak(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+
+	t0 := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	if err := st.RecordFeedStatus(ctx, "rss", "https://a/feed", "", t0); err != nil {
+		t.Fatalf("record ok: %v", err)
+	}
+	if err := st.RecordFeedStatus(ctx, "rss", "https://a/feed", "HTTP 500", t0.Add(time.Hour)); err != nil {
+		t.Fatalf("record failure: %v", err)
+	}
+	if err := st.RecordFeedStatus(ctx, "rss", "https://a/feed", "HTTP 503", t0.Add(2*time.Hour)); err != nil {
+		t.Fatalf("record failure: %v", err)
+	}
+
+	failing, err := st.GetFailingFeeds(ctx, t0)
+	if err != nil {
+		t.Fatalf("get failing: %v", err)
+	}
+	if len(failing) != 1 {
+		t.Fatalf("expected 1 failing feed, got %d", len(failing))
+	}
+	fs := failing[0]
+	if fs.LastError != "HTTP 503" {
+		t.Errorf("last error = %q, want HTTP 503", fs.LastError)
+	}
+	if !fs.FailingSince.Equal(t0.Add(time.Hour)) {
+		t.Errorf("failing since = %v, want start of streak", fs.FailingSince)
+	}
+	if !fs.LastOK.Equal(t0) {
+		t.Errorf("last ok = %v, want %v", fs.LastOK, t0)
+	}
+
+	// Streak began before the window: not a new failure.
+	failing, err = st.GetFailingFeeds(ctx, t0.Add(90*time.Minute))
+	if err != nil {
+		t.Fatalf("get failing: %v", err)
+	}
+	if len(failing) != 0 {
+		t.Errorf("expected no newly failing feeds, got %d", len(failing))
+	}
+
+	// Recovery clears the streak.
+	if err := st.RecordFeedStatus(ctx, "rss", "https://a/feed", "", t0.Add(3*time.Hour)); err != nil {
+		t.Fatalf("record recovery: %v", err)
+	}
+	failing, err = st.GetFailingFeeds(ctx, t0)
+	if err != nil {
+		t.Fatalf("get failing: %v", err)
+	}
+	if len(failing) != 0 {
+		t.Errorf("expected recovered feed to be cleared, got %d", len(failing))
+	}
+}
+
+func TestGetNewAndSilentChannels(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+
+	now := time.Date(2026, 3, 20, 8, 0, 0, 0, time.UTC)
+	prev := now.Add(-24 * time.Hour)
+	silence := 7 * 24 * time.Hour
+
+	inputs := []PostInput{
+		// Old channel, still active.
+		{Source: "rss", Channel: "active", ExternalID: "1", Text: "a", PostedAt: now.Add(-48 * time.Hour), FetchedAt: now.Add(-48 * time.Hour)},
+		{Source: "rss", Channel: "active", ExternalID: "2", Text: "b", PostedAt: now.Add(-time.Hour), FetchedAt: now.Add(-time.Hour)},
+		// Last post 7.5 days ago: crossed the silence threshold since prev.
+		{Source: "telegram", Channel: "@quiet", ExternalID: "3", Text: "c", PostedAt: now.Add(-silence - 12*time.Hour), FetchedAt: now.Add(-silence - 12*time.Hour)},
+		// Silent for a long time already: not a change.
+		{Source: "telegram", Channel: "@dead", ExternalID: "4", Text: "d", PostedAt: now.Add(-20 * 24 * time.Hour), FetchedAt: now.Add(-20 * 24 * time.Hour)},
+		// First fetched since prev.
+		{Source: "reddit", Channel: "r/newsub", ExternalID: "5", Text: "e", PostedAt: now.Add(-2 * time.Hour), FetchedAt: now.Add(-2 * time.Hour)},
+	}
+	for _, in := range inputs {
+		if _, err := st.InsertPost(ctx, in); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+	}
+
+	newChannels, err := st.GetNewChannels(ctx, prev)
+	if err != nil {
+		t.Fatalf("new channels: %v", err)
+	}
+	if len(newChannels) != 1 || newChannels[0].Channel != "r/newsub" {
+		t.Errorf("new channels = %+v, want r/newsub", newChannels)
+	}
+
+	silent, err := st.GetSilentChannels(ctx, prev, now, silence)
+	if err != nil {
+		t.Fatalf("silent channels: %v", err)
+	}
+	if len(silent) != 1 || silent[0].Channel != "@quiet" {
+		t.Errorf("silent channels = %+v, want @quiet", silent)
+	}
+}
+
+func insertSearchFixtures(t *testing.T, st *Store) (cve, helm Post) {
+	t.Helper()
+	ctx := context.Background()
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	var err error
+	cve, err = st.InsertPost(ctx, PostInput{
+		Source: "rss", Channel: "security", ExternalID: "1",
+		Text: "CVE-2026-1234 in OpenSSL: patch now", PostedAt: base, FetchedAt: base,
+	})
+	if err != nil {
+		t.Fatalf("insert cve: %v", err)
+	}
+	helm, err = st.InsertPost(ctx, PostInput{
+		Source: "telegram", Channel: "devops", ExternalID: "2",
+		Text: "Helm 4 released with OpenSSL bump", PostedAt: base.Add(-48 * time.Hour), FetchedAt: base,
+	})
+	if err != nil {
+		t.Fatalf("insert helm: %v", err)
+	}
+	return cve, helm
+}
+
+func TestSearch(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	cve, helm := insertSearchFixtures(t, st)
+
+	results, err := st.Search(ctx, "openssl", SearchFilter{})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	results, err = st.Search(ctx, "CVE-2026-1234", SearchFilter{})
+	if err != nil {
+		t.Fatalf("search with hyphens: %v", err)
+	}
+	if len(results) != 1 || results[0].Post.ID != cve.ID {
+		t.Fatalf("expected cve post, got %+v", results)
+	}
+
+	results, err = st.Search(ctx, "openssl", SearchFilter{Since: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("search since: %v", err)
+	}
+	if len(results) != 1 || results[0].Post.ID != cve.ID {
+		t.Errorf("since filter should exclude helm post %d", helm.ID)
+	}
+}
+
+func TestSearch_TierFilter(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	cve, _ := insertSearchFixtures(t, st)
+
+	if err := st.SaveScore(ctx, Score{PostID: cve.ID, Score: 9, Tier: "read_now", ScoredAt: time.Now()}); err != nil {
+		t.Fatalf("save score: %v", err)
+	}
+
+	results, err := st.Search(ctx, "openssl", SearchFilter{Tier: "read_now"})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 1 || results[0].Score == nil || results[0].Score.Score != 9 {
+		t.Fatalf("expected scored cve post, got %+v", results)
+	}
+}
+
+func TestSearch_IndexFollowsDeletes(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	insertSearchFixtures(t, st)
+
+	if _, err := st.db.Exec("DELETE FROM posts WHERE channel = 'devops'"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	results, err := st.Search(ctx, "helm", SearchFilter{})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("deleted post still indexed: %+v", results)
+	}
+}
+
+func TestSearch_EmptyQuery(t *testing.T) {
+	st, _ := openTestStore(t)
+	if _, err := st.Search(context.Background(), "   ", SearchFilter{}); err == nil {
+		t.Error("expected error for empty query")
+	}
+}
+
+func TestMigrate_BackfillsSearchIndex(t *testing.T) {
+	st, path := openTestStore(t)
+	ctx := context.Background()
+	insertSearchFixtures(t, st)
+
+	// Simulate a database created before the index existed.
+	if _, err := st.db.Exec("INSERT INTO posts_fts(posts_fts) VALUES('delete-all')"); err != nil {
+		t.Fatalf("clear index: %v", err)
+	}
+	if _, err := st.db.Exec("UPDATE metadata SET value = '3' WHERE key = 'schema_version'"); err != nil {
+		t.Fatalf("downgrade version: %v", err)
+	}
+	_ = st.Close()
+
+	st, err := Open(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+
+	results, err := st.Search(ctx, "openssl", SearchFilter{})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("expected backfilled index with 2 results, got %d", len(results))
+	}
+}
+
+func TestScoreProfiles(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	at := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	post, err := st.InsertPost(ctx, PostInput{Source: "rss", Channel: "sec", ExternalID: "1", Text: "cve", PostedAt: at, FetchedAt: at})
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if err := st.SaveScore(ctx, Score{PostID: post.ID, Score: 9, Tier: "read_now", ScoredAt: at}); err != nil {
+		t.Fatalf("save default score: %v", err)
+	}
+
+	st.SetProfile("hobby")
+	if unscored, err := st.GetUnscored(ctx); err != nil || len(unscored) != 1 {
+		t.Fatalf("hobby unscored = %d, %v; want the post", len(unscored), err)
+	}
+	if err := st.SaveScore(ctx, Score{PostID: post.ID, Score: 1, Tier: "ignore", ScoredAt: at}); err != nil {
+		t.Fatalf("save hobby score: %v", err)
+	}
+	if n, err := st.DeleteAllScores(ctx); err != nil || n != 1 {
+		t.Fatalf("delete hobby scores = %d, %v; want 1", n, err)
+	}
+
+	st.SetProfile("")
+	got, err := st.GetPostByID(ctx, post.ID)
+	if err != nil || got.Score == nil || got.Score.Score != 9 {
+		t.Errorf("default score = %+v, %v; want 9 kept", got.Score, err)
+	}
+}
+
+func TestMigrate_ScoreProfile(t *testing.T) {
+	st, path := openTestStore(t)
+	ctx := context.Background()
+	at := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	post, err := st.InsertPost(ctx, PostInput{Source: "rss", Channel: "sec", ExternalID: "1", Text: "cve", PostedAt: at, FetchedAt: at})
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	// Simulate a version 13 database, scored before profiles existed.
+	for _, stmt := range []string{
+		"DROP TABLE scores",
+		`CREATE TABLE scores (post_id INTEGER PRIMARY KEY REFERENCES posts(id), score INTEGER NOT NULL DEFAULT 0,
+			labels TEXT, tier TEXT NOT NULL DEFAULT 'ignore', scored_at DATETIME NOT NULL, explanation TEXT, text_hash TEXT)`,
+		"INSERT INTO scores (post_id, score, labels, tier, scored_at) VALUES (1, 7, '[]', 'read_now', '2026-03-01T08:00:00Z')",
+		"UPDATE metadata SET value = '13' WHERE key = 'schema_version'",
+	} {
+		if _, err := st.db.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	_ = st.Close()
+
+	st, err = Open(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+	got, err := st.GetPostByID(ctx, post.ID)
+	if err != nil || got.Score == nil || got.Score.Score != 7 {
+		t.Fatalf("migrated score = %+v, %v; want 7 in the default profile", got.Score, err)
+	}
+	st.SetProfile("work")
+	if err := st.SaveScore(ctx, Score{PostID: post.ID, Score: 2, Tier: "ignore", ScoredAt: at}); err != nil {
+		t.Errorf("save work score: %v", err)
+	}
+}
+
+func TestMigrate_RuleHitProfile(t *testing.T) {
+	st, path := openTestStore(t)
+	ctx := context.Background()
+
+	// Simulate a version 16 database, with rule hits shared by all profiles.
+	for _, stmt := range []string{
+		"DROP TABLE rule_hits",
+		`CREATE TABLE rule_hits (rule TEXT NOT NULL, source TEXT NOT NULL, channel TEXT NOT NULL,
+			hit_at DATETIME NOT NULL, PRIMARY KEY(rule, source, channel))`,
+		"INSERT INTO rule_hits (rule, source, channel, hit_at) VALUES ('outage', 'rss', 'status', '2026-03-01T08:00:00Z')",
+		"UPDATE metadata SET value = '16' WHERE key = 'schema_version'",
+	} {
+		if _, err := st.db.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	_ = st.Close()
+
+	st, err := Open(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+	if hits, err := st.GetRuleHits(ctx); err != nil || len(hits) != 1 {
+		t.Fatalf("migrated hits = %+v, %v; want 1 in the default profile", hits, err)
+	}
+	st.SetProfile("work")
+	if hits, err := st.GetRuleHits(ctx); err != nil || len(hits) != 0 {
+		t.Errorf("work hits = %+v, %v; want none", hits, err)
+	}
+}
+
+func TestGetPosts_FutureDatedPostCappedAtFetchTime(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+
+	fetched := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	future, err := st.InsertPost(ctx, PostInput{
+		Source: "rss", Channel: "skewed", ExternalID: "1",
+		Text: "dated +13h", PostedAt: fetched.Add(13 * time.Hour), FetchedAt: fetched,
+	})
+	if err != nil {
+		t.Fatalf("insert future: %v", err)
+	}
+	recent, err := st.InsertPost(ctx, PostInput{
+		Source: "rss", Channel: "normal", ExternalID: "2",
+		Text: "normal post", PostedAt: fetched.Add(time.Hour), FetchedAt: fetched.Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("insert recent: %v", err)
+	}
+
+	// Re-fetching later must not refresh the future post's window position.
+	if _, err := st.InsertPost(ctx, PostInput{
+		Source: "rss", Channel: "skewed", ExternalID: "1",
+		Text: "dated +13h", PostedAt: fetched.Add(13 * time.Hour), FetchedAt: fetched.Add(6 * time.Hour),
+	}); err != nil {
+		t.Fatalf("refetch future: %v", err)
+	}
+
+	posts, err := st.GetPosts(ctx, fetched.Add(-time.Hour), "")
+	if err != nil {
+		t.Fatalf("get posts: %v", err)
+	}
+	if len(posts) != 2 || posts[0].Post.ID != recent.ID || posts[1].Post.ID != future.ID {
+		t.Fatalf("future post should sort by fetch time, got %+v", posts)
+	}
+
+	posts, err = st.GetPosts(ctx, fetched.Add(30*time.Minute), "")
+	if err != nil {
+		t.Fatalf("get posts: %v", err)
+	}
+	if len(posts) != 1 || posts[0].Post.ID != recent.ID {
+		t.Errorf("future post fetched before window should be excluded, got %d posts", len(posts))
+	}
+
+	// Once the feed fixes its timezone, the real date replaces the future one.
+	corrected := fetched.Add(-2 * time.Hour)
+	got, err := st.InsertPost(ctx, PostInput{
+		Source: "rss", Channel: "skewed", ExternalID: "1",
+		Text: "dated +13h", PostedAt: corrected, FetchedAt: fetched.Add(7 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("refetch corrected: %v", err)
+	}
+	if !got.PostedAt.Equal(corrected) {
+		t.Errorf("posted_at = %v, want corrected %v", got.PostedAt, corrected)
+	}
+}
+
+func TestGetPosts_WindowUsesIndex(t *testing.T) {
+	st, _ := openTestStore(t)
+	rows, err := st.db.QueryContext(context.Background(),
+		"EXPLAIN QUERY PLAN SELECT p.id FROM posts p WHERE "+st.effectiveTime()+" >= ?"+liveClause, formatTime(time.Now()))
+	if err != nil {
+		t.Fatalf("explain: %v", err)
+	}
+	defer func() { _ = rows.Close() }()
+	var plan []string
+	for rows.Next() {
+		var (
+			id, parent, unused int
+			detail             string
+		)
+		if err := rows.Scan(&id, &parent, &unused, &detail); err != nil {
+			t.Fatalf("scan plan: %v", err)
+		}
+		plan = append(plan, detail)
+	}
+	if !strings.Contains(strings.Join(plan, "\n"), "idx_posts_effective_at") {
+		t.Errorf("window query plan = %q, want idx_posts_effective_at", plan)
+	}
+}
+
+func TestInsertPost_Author(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	at := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	in := PostInput{Source: "hn", Channel: "Hacker News", ExternalID: "1", Text: "Show HN", Author: "pg", PostedAt: at, FetchedAt: at}
+
+	post, err := st.InsertPost(ctx, in)
+	if err != nil || post.Author != "pg" {
+		t.Fatalf("insert = %+v, %v; want author pg", post, err)
+	}
+
+	// A refetch that does not report the author keeps the stored one.
+	in.Author = ""
+	if post, err = st.InsertPost(ctx, in); err != nil || post.Author != "pg" {
+		t.Errorf("refetch without author = %q, %v; want pg", post.Author, err)
+	}
+	posts, err := st.GetPosts(ctx, at.Add(-time.Hour), "")
+	if err != nil || len(posts) != 1 || posts[0].Post.Author != "pg" {
+		t.Errorf("get posts = %+v, %v", posts, err)
+	}
+}
+
+func TestInsertPost_Language(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	at := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	in := PostInput{Source: "telegram", Channel: "@ru", ExternalID: "1", Text: "Новый релиз", Language: "ru", PostedAt: at, FetchedAt: at}
+
+	if post, err := st.InsertPost(ctx, in); err != nil || post.Language != "ru" {
+		t.Fatalf("insert = %+v, %v; want language ru", post, err)
+	}
+	// A refetch that cannot tell the language keeps the stored one.
+	in.Language = ""
+	if _, err := st.InsertPost(ctx, in); err != nil {
+		t.Fatalf("refetch: %v", err)
+	}
+	posts, err := st.GetPosts(ctx, at.Add(-time.Hour), "")
+	if err != nil || len(posts) != 1 || posts[0].Post.Language != "ru" {
+		t.Errorf("get posts = %+v, %v; want language ru", posts, err)
+	}
+}
+
+func TestInsertPost_PostedAtNeverMovesLater(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+
+	first := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	for i, postedAt := range []time.Time{first, first.Add(time.Hour)} {
+		if _, err := st.InsertPost(ctx, PostInput{
+			Source: "rss", Channel: "restamp", ExternalID: "1",
+			Text: "same item", PostedAt: postedAt, FetchedAt: postedAt,
+		}); err != nil {
+			t.Fatalf("insert %d: %v", i, err)
+		}
+	}
+
+	posts, err := st.GetPosts(ctx, time.Time{}, "")
+	if err != nil {
+		t.Fatalf("get posts: %v", err)
+	}
+	if len(posts) != 1 || !posts[0].Post.PostedAt.Equal(first) || !posts[0].Post.FetchedAt.Equal(first) {
+		t.Errorf("posted/fetched = %v/%v, want both %v", posts[0].Post.PostedAt, posts[0].Post.FetchedAt, first)
+	}
+}
+
+func TestMarkReadAndUnreadFilter(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	cve, helm := insertSearchFixtures(t, st)
+
+	readAt := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	if err := st.MarkRead(ctx, readAt, cve.ID, 9999); err != nil {
+		t.Fatalf("mark read: %v", err)
+	}
+
+	posts, err := st.GetPosts(ctx, time.Time{}, "", PostFilter{UnreadOnly: true})
+	if err != nil {
+		t.Fatalf("get unread: %v", err)
+	}
+	if len(posts) != 1 || posts[0].Post.ID != helm.ID {
+		t.Fatalf("unread posts = %+v, want only helm", posts)
+	}
+
+	posts, err = st.GetPosts(ctx, time.Time{}, "")
+	if err != nil {
+		t.Fatalf("get all: %v", err)
+	}
+	if len(posts) != 2 {
+		t.Errorf("unfiltered posts = %d, want 2", len(posts))
+	}
+
+	// Re-marking keeps the original read time.
+	if err := st.MarkRead(ctx, readAt.Add(time.Hour), cve.ID); err != nil {
+		t.Fatalf("re-mark read: %v", err)
+	}
+	var got string
+	if err := st.db.QueryRow("SELECT read_at FROM read_state WHERE post_id = ?", cve.ID).Scan(&got); err != nil {
+		t.Fatalf("read_at: %v", err)
+	}
+	if got != formatTime(readAt) {
+		t.Errorf("read_at = %s, want %s", got, formatTime(readAt))
+	}
+}
+
+func TestMarkAllRead(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	insertSearchFixtures(t, st)
+
+	n, err := st.MarkAllRead(ctx, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("mark all read: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("marked = %d, want 2", n)
+	}
+	posts, err := st.GetPosts(ctx, time.Time{}, "", PostFilter{UnreadOnly: true})
+	if err != nil {
+		t.Fatalf("get unread: %v", err)
+	}
+	if len(posts) != 0 {
+		t.Errorf("unread = %d, want 0", len(posts))
+	}
+}
+
+func TestDeduplicate_CarriesReadState(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	insertDedupFixtures(t, st)
+
+	posts, err := st.GetPosts(ctx, time.Time{}, "", PostFilter{Source: "telegram"})
+	if err != nil || len(posts) != 1 {
+		t.Fatalf("get telegram post: %v (%d)", err, len(posts))
+	}
+	if err := st.MarkRead(ctx, time.Now(), posts[0].Post.ID); err != nil {
+		t.Fatalf("mark read: %v", err)
+	}
+
+	// Prefer the RSS copy; the read mark must follow the story.
+	if _, err := st.DeduplicateWith(ctx, DedupKeeper{Strategy: DedupSource, SourceOrder: []string{"rss"}}); err != nil {
+		t.Fatalf("deduplicate: %v", err)
+	}
+	unread, err := st.GetPosts(ctx, time.Time{}, "", PostFilter{UnreadOnly: true})
+	if err != nil {
+		t.Fatalf("get unread: %v", err)
+	}
+	if len(unread) != 0 {
+		t.Errorf("keeper should inherit read state, got %d unread", len(unread))
+	}
+}
+
+func TestStarUnstarAndFilters(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	cve, helm := insertSearchFixtures(t, st)
+
+	if err := st.Star(ctx, 9999, time.Now()); err == nil {
+		t.Error("expected error starring missing post")
+	}
+	starredAt := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	if err := st.Star(ctx, helm.ID, starredAt); err != nil {
+		t.Fatalf("star helm: %v", err)
+	}
+	if err := st.Star(ctx, cve.ID, starredAt.Add(time.Hour)); err != nil {
+		t.Fatalf("star cve: %v", err)
+	}
+	// Starring twice is a no-op.
+	if err := st.Star(ctx, helm.ID, starredAt.Add(2*time.Hour)); err != nil {
+		t.Fatalf("re-star: %v", err)
+	}
+
+	starred, err := st.GetStarred(ctx)
+	if err != nil {
+		t.Fatalf("get starred: %v", err)
+	}
+	if len(starred) != 2 || starred[0].Post.ID != cve.ID || starred[1].Post.ID != helm.ID {
+		t.Fatalf("starred = %+v, want cve then helm", starred)
+	}
+
+	removed, err := st.Unstar(ctx, cve.ID)
+	if err != nil || !removed {
+		t.Fatalf("unstar = %v, %v", removed, err)
+	}
+	if removed, _ := st.Unstar(ctx, cve.ID); removed {
+		t.Error("second unstar should report not starred")
+	}
+
+	posts, err := st.GetPosts(ctx, time.Time{}, "", PostFilter{StarredOnly: true})
+	if err != nil {
+		t.Fatalf("get starred posts: %v", err)
+	}
+	if len(posts) != 1 || posts[0].Post.ID != helm.ID {
+		t.Errorf("starred filter = %+v, want only helm", posts)
+	}
+
+	results, err := st.Search(ctx, "helm", SearchFilter{StarredOnly: true})
+	if err != nil {
+		t.Fatalf("search starred: %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("starred search = %d results, want 1", len(results))
+	}
+	results, err = st.Search(ctx, "cve", SearchFilter{StarredOnly: true})
+	if err != nil {
+		t.Fatalf("search unstarred: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("unstarred post matched starred search: %+v", results)
+	}
+}
+
+func TestPruneOld_KeepsStarred(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+
+	old := time.Now().UTC().AddDate(0, 0, -60)
+	kept, err := st.InsertPost(ctx, PostInput{
+		Source: "rss", Channel: "blog", ExternalID: "starred",
+		Text: "keep me", PostedAt: old, FetchedAt: old,
+	})
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if _, err := st.InsertPost(ctx, PostInput{
+		Source: "rss", Channel: "blog", ExternalID: "plain",
+		Text: "drop me", PostedAt: old, FetchedAt: old,
+	}); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if err := st.Star(ctx, kept.ID, time.Now()); err != nil {
+		t.Fatalf("star: %v", err)
+	}
+
+	pruned, err := st.PruneOld(ctx, 30)
+	if err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if pruned != 1 {
+		t.Errorf("pruned = %d, want 1", pruned)
+	}
+	starred, err := st.GetStarred(ctx)
+	if err != nil {
+		t.Fatalf("get starred: %v", err)
+	}
+	if len(starred) != 1 || starred[0].Post.ID != kept.ID {
+		t.Errorf("starred after prune = %+v", starred)
+	}
+}
+
+func TestSlimOld(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	old := now.AddDate(0, 0, -45)
+	insert := func(id, text string, at time.Time) Post {
+		t.Helper()
+		p, err := st.InsertPost(ctx, PostInput{
+			Source: "rss", Channel: "blog", ExternalID: id,
+			Text: text, PostedAt: at, FetchedAt: at,
+		})
+		if err != nil {
+			t.Fatalf("insert %s: %v", id, err)
+		}
+		return p
+	}
+	slim := insert("old", "Kubernetes deprecation notice\nlong body", old)
+	starred := insert("starred", "Starred long read", old)
+	recent := insert("recent", "Fresh post", now)
+	if err := st.SaveScore(ctx, Score{PostID: slim.ID, Score: 4, Tier: "skim", ScoredAt: old}); err != nil {
+		t.Fatalf("save score: %v", err)
+	}
+	if err := st.Star(ctx, starred.ID, now); err != nil {
+		t.Fatalf("star: %v", err)
+	}
+
+	n, err := st.SlimOld(ctx, 30)
+	if err != nil {
+		t.Fatalf("slim: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("slimmed = %d, want 1", n)
+	}
+	// Already slim posts are not counted again.
+	if n, _ := st.SlimOld(ctx, 30); n != 0 {
+		t.Errorf("second slim = %d, want 0", n)
+	}
+
+	posts, err := st.GetPosts(ctx, time.Time{}, "")
+	if err != nil {
+		t.Fatalf("get posts: %v", err)
+	}
+	byID := make(map[int64]PostWithScore)
+	for _, p := range posts {
+		byID[p.Post.ID] = p
+	}
+	got := byID[slim.ID]
+	if got.Post.Text != "" || got.Post.Snippet == "" {
+		t.Errorf("slimmed post text = %q, snippet = %q", got.Post.Text, got.Post.Snippet)
+	}
+	if got.Score == nil || got.Score.Tier != "skim" {
+		t.Errorf("slimmed post lost its score: %+v", got.Score)
+	}
+	if byID[starred.ID].Post.Text == "" || byID[recent.ID].Post.Text == "" {
+		t.Error("starred and recent posts must keep full text")
+	}
+
+	// The snippet stays searchable.
+	results, err := st.Search(ctx, "kubernetes", SearchFilter{})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 1 || results[0].Post.ID != slim.ID {
+		t.Errorf("search after slim = %+v", results)
+	}
+}
+
+func TestLatestPostIDAndAfterFilter(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+
+	if id, err := st.LatestPostID(ctx); err != nil || id != 0 {
+		t.Fatalf("empty store latest = %d, %v", id, err)
+	}
+	cve, helm := insertSearchFixtures(t, st)
+
+	latest, err := st.LatestPostID(ctx)
+	if err != nil {
+		t.Fatalf("latest: %v", err)
+	}
+	if want := max(cve.ID, helm.ID); latest != want {
+		t.Errorf("latest = %d, want %d", latest, want)
+	}
+
+	posts, err := st.GetPosts(ctx, time.Time{}, "", PostFilter{AfterID: min(cve.ID, helm.ID)})
+	if err != nil {
+		t.Fatalf("get after: %v", err)
+	}
+	if len(posts) != 1 || posts[0].Post.ID != latest {
+		t.Errorf("posts after = %+v, want only #%d", posts, latest)
+	}
+}
+
+func TestGetPostByID(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+
+	at := time.Date(2026, 2, 16, 8, 0, 0, 0, time.UTC)
+	post, err := st.InsertPost(ctx, PostInput{
+		Source: "rss", Channel: "blog", ExternalID: "1",
+		Text: "kernel patch", PostedAt: at, FetchedAt: at.Add(time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	got, err := st.GetPostByID(ctx, post.ID)
+	if err != nil {
+		t.Fatalf("get post: %v", err)
+	}
+	if got.Post.Text != "kernel patch" || got.Score != nil {
+		t.Errorf("got %+v, want unscored kernel patch", got)
+	}
+
+	if err := st.SaveScore(ctx, Score{PostID: post.ID, Score: 4, Tier: "skim", ScoredAt: at}); err != nil {
+		t.Fatalf("save score: %v", err)
+	}
+	got, err = st.GetPostByID(ctx, post.ID)
+	if err != nil {
+		t.Fatalf("get post: %v", err)
+	}
+	if got.Score == nil || got.Score.Score != 4 {
+		t.Errorf("score = %+v, want 4", got.Score)
+	}
+
+	if _, err := st.GetPostByID(ctx, post.ID+1); !errors.Is(err, ErrPostNotFound) {
+		t.Errorf("missing post: err = %v, want ErrPostNotFound", err)
+	}
+}
+
+func TestPostWithScore_Changed(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+
+	at := time.Date(2026, 2, 16, 8, 0, 0, 0, time.UTC)
+	in := PostInput{
+		Source: "rss", Channel: "blog", ExternalID: "1",
+		Text: "kernel patch", PostedAt: at, FetchedAt: at,
+	}
+	post, err := st.InsertPost(ctx, in)
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if err := st.SaveScore(ctx, Score{PostID: post.ID, Score: 4, Tier: "skim", ScoredAt: at}); err != nil {
+		t.Fatalf("save score: %v", err)
+	}
+
+	got, err := st.GetPostByID(ctx, post.ID)
+	if err != nil {
+		t.Fatalf("get post: %v", err)
+	}
+	if got.Changed() {
+		t.Error("freshly scored post reported as changed")
+	}
+
+	in.Text = "kernel patch, now with a CVE"
+	if _, err := st.InsertPost(ctx, in); err != nil {
+		t.Fatalf("re-insert: %v", err)
+	}
+	got, err = st.GetPostByID(ctx, post.ID)
+	if err != nil {
+		t.Fatalf("get post: %v", err)
+	}
+	if !got.Changed() {
+		t.Error("edited post not reported as changed")
+	}
+
+	if err := st.SaveScore(ctx, Score{PostID: post.ID, Score: 8, Tier: "read_now", ScoredAt: at}); err != nil {
+		t.Fatalf("rescore: %v", err)
+	}
+	got, err = st.GetPostByID(ctx, post.ID)
+	if err != nil {
+		t.Fatalf("get post: %v", err)
+	}
+	if got.Changed() {
+		t.Error("rescored post still reported as changed")
+	}
+
+	// Scores written before text hashes were recorded are never flagged.
+	if _, err := st.db.ExecContext(ctx, "UPDATE scores SET text_hash = NULL"); err != nil {
+		t.Fatalf("clear hash: %v", err)
+	}
+	in.Text = "kernel patch, edited again"
+	if _, err := st.InsertPost(ctx, in); err != nil {
+		t.Fatalf("re-insert: %v", err)
+	}
+	got, err = st.GetPostByID(ctx, post.ID)
+	if err != nil {
+		t.Fatalf("get post: %v", err)
+	}
+	if got.Changed() {
+		t.Error("legacy score without hash reported as changed")
+	}
+}
+
+func TestBeginDryRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "noisepan.db")
+	ctx := context.Background()
+	st, err := Open(path)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	insert := func(id string) {
+		t.Helper()
+		if _, err := st.InsertPost(ctx, PostInput{
+			Source: "rss", Channel: "blog", ExternalID: id, Text: "same text " + id, PostedAt: time.Now(), FetchedAt: time.Now(),
+		}); err != nil {
+			t.Fatalf("insert %s: %v", id, err)
+		}
+	}
+	insert("kept")
+
+	if err := st.BeginDryRun(ctx); err != nil {
+		t.Fatalf("begin dry run: %v", err)
+	}
+	if !st.DryRun() {
+		t.Fatal("DryRun() = false after BeginDryRun")
+	}
+	insert("dry")
+
+	// Store transactions become savepoints: a rolled-back one undoes only its
+	// own writes, a committed one stays visible to the rest of the run.
+	tx, err := st.db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("begin tx: %v", err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM posts"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+	if err := tx.Rollback(); !errors.Is(err, sql.ErrTxDone) {
+		t.Errorf("second rollback err = %v, want ErrTxDone", err)
+	}
+	insert("dry2")
+	if n, err := st.Deduplicate(ctx); err != nil {
+		t.Fatalf("deduplicate: %v", err)
+	} else if n != 0 {
+		t.Errorf("deduplicate removed %d posts", n)
+	}
+
+	posts, err := st.GetPosts(ctx, time.Time{}, "")
+	if err != nil {
+		t.Fatalf("get posts: %v", err)
+	}
+	if len(posts) != 3 {
+		t.Errorf("dry run sees %d posts, want its own writes (3)", len(posts))
+	}
+	if err := st.Vacuum(ctx); err == nil {
+		t.Error("vacuum succeeded in a dry run")
+	}
+	if err := st.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	st, err = Open(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+	posts, err = st.GetPosts(ctx, time.Time{}, "")
+	if err != nil {
+		t.Fatalf("get posts: %v", err)
+	}
+	if len(posts) != 1 || posts[0].Post.ExternalID != "kept" {
+		t.Errorf("after a dry run the store has %d posts, want only the one written before it", len(posts))
+	}
+}
+
+func TestOpenDryRun_RollsBackMigration(t *testing.T) {
+	st, path := openTestStore(t)
+	if _, err := st.db.Exec("UPDATE metadata SET value = '16' WHERE key = 'schema_version'"); err != nil {
+		t.Fatalf("set version: %v", err)
+	}
+	_ = st.Close()
+
+	dry, err := OpenDryRun(SQLite{}, path)
+	if err != nil {
+		t.Fatalf("open dry run: %v", err)
+	}
+	if !dry.DryRun() {
+		t.Error("DryRun() = false after OpenDryRun")
+	}
+	_ = dry.Close()
+
+	db, err := SQLite{}.Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+	var version string
+	if err := db.QueryRow("SELECT value FROM metadata WHERE key = 'schema_version'").Scan(&version); err != nil {
+		t.Fatalf("read schema version: %v", err)
+	}
+	if version != "16" {
+		t.Errorf("schema version after dry run = %s, want 16 kept", version)
+	}
+}
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNoSummary is returned by GetSummary when no summary of the text from
+// the model is saved.
+var ErrNoSummary = errors.New("no saved summary")
+
+// SaveSummary stores the model's summary bullets of the text with
+// textHash, replacing an earlier one.
+func (s *Store) SaveSummary(ctx context.Context, textHash, model string, bullets []string, at time.Time) error {
+	if s == nil || s.db == nil {
+		return errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if textHash == "" {
+		return errors.New("text hash is required")
+	}
+
+	data, err := json.Marshal(bullets)
+	if err != nil {
+		return fmt.Errorf("marshal summary: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO summaries(text_hash, model, bullets, created_at) VALUES(?, ?, ?, ?)
+		ON CONFLICT(text_hash, model) DO UPDATE SET
+			bullets = excluded.bullets,
+			created_at = excluded.created_at`,
+		textHash, model, string(data), formatTime(at),
+	); err != nil {
+		return fmt.Errorf("save summary: %w", err)
+	}
+	return nil
+}
+
+// GetSummary returns the model's saved summary bullets of the text with
+// textHash.
+func (s *Store) GetSummary(ctx context.Context, textHash, model string) ([]string, error) {
+	if s == nil || s.db == nil {
+		return nil, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var data string
+	err := s.db.QueryRowContext(ctx,
+		"SELECT bullets FROM summaries WHERE text_hash = ? AND model = ?", textHash, model,
+	).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("get summary %s: %w", textHash, ErrNoSummary)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get summary: %w", err)
+	}
+	var bullets []string
+	if err := json.Unmarshal([]byte(data), &bullets); err != nil {
+		return nil, fmt.Errorf("decode summary %s: %w", textHash, err)
+	}
+	return bullets, nil
+}
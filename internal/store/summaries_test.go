@@ -0,0 +1,42 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSummaries(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	old := time.Now().UTC().AddDate(0, 0, -60)
+
+	if err := st.SaveSummary(ctx, "h1", "gpt-4.1-mini", []string{"first"}, old); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if err := st.SaveSummary(ctx, "h1", "gpt-4.1-mini", []string{"one", "two"}, old); err != nil {
+		t.Fatalf("save again: %v", err)
+	}
+	if err := st.SaveSummary(ctx, "h1", "llama3.2", []string{"local"}, time.Now()); err != nil {
+		t.Fatalf("save other model: %v", err)
+	}
+	if got, err := st.GetSummary(ctx, "h1", "gpt-4.1-mini"); err != nil || !slices.Equal(got, []string{"one", "two"}) {
+		t.Errorf("get = %q, %v; want the replacement", got, err)
+	}
+	if _, err := st.GetSummary(ctx, "h2", "gpt-4.1-mini"); !errors.Is(err, ErrNoSummary) {
+		t.Errorf("missing: err = %v, want ErrNoSummary", err)
+	}
+
+	// Purge drops summaries saved before the cutoff.
+	if _, err := st.Purge(ctx, time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if _, err := st.GetSummary(ctx, "h1", "gpt-4.1-mini"); !errors.Is(err, ErrNoSummary) {
+		t.Errorf("after purge: err = %v, want ErrNoSummary", err)
+	}
+	if got, err := st.GetSummary(ctx, "h1", "llama3.2"); err != nil || !slices.Equal(got, []string{"local"}) {
+		t.Errorf("recent after purge = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,91 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrSnapshotNotFound is returned by GetTasteSnapshot when no profile was
+// saved under the hash.
+var ErrSnapshotNotFound = errors.New("taste snapshot not found")
+
+// SaveTasteSnapshot stores profile, a taste profile as YAML, under hash,
+// the Score.TasteHash of the scores computed with it. A hash already saved
+// is kept.
+func (s *Store) SaveTasteSnapshot(ctx context.Context, hash string, profile []byte) error {
+	if s == nil || s.db == nil {
+		return errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if hash == "" {
+		return errors.New("hash is required")
+	}
+
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO taste_snapshots(hash, profile, saved_at) VALUES(?, ?, ?)
+		ON CONFLICT(hash) DO NOTHING`,
+		hash, string(profile), formatTime(time.Now()),
+	); err != nil {
+		return fmt.Errorf("save taste snapshot: %w", err)
+	}
+	return nil
+}
+
+// GetTasteSnapshot returns the taste profile saved under hash.
+func (s *Store) GetTasteSnapshot(ctx context.Context, hash string) ([]byte, error) {
+	if s == nil || s.db == nil {
+		return nil, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var profile string
+	err := s.db.QueryRowContext(ctx, "SELECT profile FROM taste_snapshots WHERE hash = ?", hash).Scan(&profile)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("get taste snapshot %s: %w", hash, ErrSnapshotNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get taste snapshot: %w", err)
+	}
+	return []byte(profile), nil
+}
+
+// SetScoreTasteHash records hash as the taste profile of the active
+// profile's scores of postIDs, for scores a profile change leaves as they
+// are. Returns the number of scores updated.
+func (s *Store) SetScoreTasteHash(ctx context.Context, postIDs []int64, hash string) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if len(postIDs) == 0 {
+		return 0, nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	var n int64
+	for _, id := range postIDs {
+		res, err := tx.ExecContext(ctx, "UPDATE scores SET taste_hash = ? WHERE post_id = ? AND profile = ?", hash, id, s.profile)
+		if err != nil {
+			_ = tx.Rollback()
+			return 0, fmt.Errorf("set score taste hash: %w", err)
+		}
+		m, _ := res.RowsAffected()
+		n += m
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+	return n, nil
+}
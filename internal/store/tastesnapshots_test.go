@@ -0,0 +1,53 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTasteSnapshots(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+
+	if err := st.SaveTasteSnapshot(ctx, "abc", []byte("weights: {}\n")); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	// A hash already saved keeps its profile.
+	if err := st.SaveTasteSnapshot(ctx, "abc", []byte("other\n")); err != nil {
+		t.Fatalf("save again: %v", err)
+	}
+	got, err := st.GetTasteSnapshot(ctx, "abc")
+	if err != nil || string(got) != "weights: {}\n" {
+		t.Errorf("get = %q, %v", got, err)
+	}
+	if _, err := st.GetTasteSnapshot(ctx, "missing"); !errors.Is(err, ErrSnapshotNotFound) {
+		t.Errorf("missing: err = %v, want ErrSnapshotNotFound", err)
+	}
+}
+
+func TestScoreTasteHash(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	at := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	post, err := st.InsertPost(ctx, PostInput{Source: "rss", Channel: "blog", ExternalID: "1", Text: "hello", PostedAt: at, FetchedAt: at})
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if err := st.SaveScore(ctx, Score{PostID: post.ID, Score: 1, Tier: "ignore", ScoredAt: at, TasteHash: "old"}); err != nil {
+		t.Fatalf("save score: %v", err)
+	}
+	pws, err := st.GetPostByID(ctx, post.ID)
+	if err != nil || pws.Score == nil || pws.Score.TasteHash != "old" {
+		t.Fatalf("get = %+v, %v; want taste hash old", pws.Score, err)
+	}
+
+	if n, err := st.SetScoreTasteHash(ctx, []int64{post.ID, post.ID + 1}, "new"); err != nil || n != 1 {
+		t.Fatalf("set = %d, %v; want 1", n, err)
+	}
+	pws, err = st.GetPostByID(ctx, post.ID)
+	if err != nil || pws.Score.TasteHash != "new" || pws.Score.Score != 1 {
+		t.Errorf("after set = %+v, %v", pws.Score, err)
+	}
+}
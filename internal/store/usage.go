@@ -0,0 +1,117 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Usage counters, kept per UTC day in the usage_counters table. They never
+// leave the store and are not tied to posts, so pruning and purging keep them.
+const (
+	UsageDigests          = "digests"            // digests generated
+	UsageDigestPosts      = "digest_posts"       // posts a digest covered
+	UsageDigestShown      = "digest_shown"       // of those, read_now and skim posts listed
+	UsageDigestWords      = "digest_words"       // words in the posts a digest covered
+	UsageDigestShownWords = "digest_shown_words" // words in the posts it listed
+	UsageRead             = "read"               // posts newly marked read
+	UsageStarred          = "starred"            // posts newly starred
+)
+
+// Usage sums the usage counters over a window.
+type Usage struct {
+	Digests          int64
+	DigestPosts      int64
+	DigestShown      int64
+	DigestWords      int64
+	DigestShownWords int64
+	Read             int64
+	Starred          int64
+	Days             int       // days with any recorded activity
+	First            time.Time // first such day; zero when there is none
+}
+
+func usageDay(at time.Time) string {
+	return at.UTC().Format(time.DateOnly)
+}
+
+// AddUsage adds n to counter for the day of at. Zero and negative amounts
+// are ignored.
+func (s *Store) AddUsage(ctx context.Context, at time.Time, counter string, n int64) error {
+	if s == nil || s.db == nil {
+		return errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	if _, err := s.db.ExecContext(ctx, addUsageQuery, usageDay(at), counter, n); err != nil {
+		return fmt.Errorf("add usage: %w", err)
+	}
+	return nil
+}
+
+const addUsageQuery = `
+	INSERT INTO usage_counters(day, counter, value) VALUES(?, ?, ?)
+	ON CONFLICT(day, counter) DO UPDATE SET value = usage_counters.value + excluded.value`
+
+// GetUsage sums the usage counters from the day of since onwards (zero
+// means all).
+func (s *Store) GetUsage(ctx context.Context, since time.Time) (Usage, error) {
+	if s == nil || s.db == nil {
+		return Usage{}, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	from := ""
+	if !since.IsZero() {
+		from = usageDay(since)
+	}
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT day, counter, value FROM usage_counters WHERE day >= ? ORDER BY day", from)
+	if err != nil {
+		return Usage{}, fmt.Errorf("get usage: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var u Usage
+	fields := map[string]*int64{
+		UsageDigests:          &u.Digests,
+		UsageDigestPosts:      &u.DigestPosts,
+		UsageDigestShown:      &u.DigestShown,
+		UsageDigestWords:      &u.DigestWords,
+		UsageDigestShownWords: &u.DigestShownWords,
+		UsageRead:             &u.Read,
+		UsageStarred:          &u.Starred,
+	}
+	lastDay := ""
+	for rows.Next() {
+		var day, counter string
+		var value int64
+		if err := rows.Scan(&day, &counter, &value); err != nil {
+			return Usage{}, fmt.Errorf("scan usage: %w", err)
+		}
+		if day != lastDay {
+			if u.Days == 0 {
+				if u.First, err = time.Parse(time.DateOnly, day); err != nil {
+					return Usage{}, fmt.Errorf("parse usage day: %w", err)
+				}
+			}
+			u.Days++
+			lastDay = day
+		}
+		if f, ok := fields[counter]; ok {
+			*f += value
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return Usage{}, fmt.Errorf("iterate usage: %w", err)
+	}
+	return u, nil
+}
@@ -0,0 +1,96 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAddUsageAndGetUsage(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	day1 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	day2 := day1.Add(26 * time.Hour)
+
+	for _, add := range []struct {
+		at      time.Time
+		counter string
+		n       int64
+	}{
+		{day1, UsageDigests, 1},
+		{day1, UsageDigestPosts, 40},
+		{day1, UsageDigestShown, 5},
+		{day1.Add(time.Hour), UsageDigests, 1},
+		{day2, UsageDigests, 1},
+		{day2, UsageDigestWords, 900},
+		{day2, UsageDigestShownWords, 120},
+		{day2, UsageDigestShown, 0}, // ignored
+	} {
+		if err := st.AddUsage(ctx, add.at, add.counter, add.n); err != nil {
+			t.Fatalf("add %s: %v", add.counter, err)
+		}
+	}
+
+	all, err := st.GetUsage(ctx, time.Time{})
+	if err != nil {
+		t.Fatalf("get usage: %v", err)
+	}
+	want := Usage{
+		Digests: 3, DigestPosts: 40, DigestShown: 5, DigestWords: 900, DigestShownWords: 120,
+		Days: 2, First: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if all != want {
+		t.Errorf("usage = %+v, want %+v", all, want)
+	}
+
+	recent, err := st.GetUsage(ctx, day2)
+	if err != nil {
+		t.Fatalf("get recent usage: %v", err)
+	}
+	if recent.Digests != 1 || recent.DigestPosts != 0 || recent.Days != 1 {
+		t.Errorf("recent usage = %+v, want one digest on one day", recent)
+	}
+}
+
+func TestUsage_ReadAndStarred(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	cve, helm := insertSearchFixtures(t, st)
+	at := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	if err := st.MarkRead(ctx, at, cve.ID, helm.ID); err != nil {
+		t.Fatalf("mark read: %v", err)
+	}
+	// Already read: not counted again.
+	if err := st.MarkRead(ctx, at.Add(time.Hour), cve.ID); err != nil {
+		t.Fatalf("mark read again: %v", err)
+	}
+	if err := st.Star(ctx, cve.ID, at); err != nil {
+		t.Fatalf("star: %v", err)
+	}
+	if err := st.Star(ctx, cve.ID, at); err != nil {
+		t.Fatalf("star again: %v", err)
+	}
+	// Catching up is not reading.
+	if _, err := st.MarkAllRead(ctx, time.Now()); err != nil {
+		t.Fatalf("mark all read: %v", err)
+	}
+
+	u, err := st.GetUsage(ctx, time.Time{})
+	if err != nil {
+		t.Fatalf("get usage: %v", err)
+	}
+	if u.Read != 2 || u.Starred != 1 {
+		t.Errorf("read = %d, starred = %d, want 2 and 1", u.Read, u.Starred)
+	}
+}
+
+func TestUsage_NilStore(t *testing.T) {
+	var st *Store
+	if err := st.AddUsage(context.Background(), time.Now(), UsageDigests, 1); err == nil {
+		t.Error("expected error from nil store")
+	}
+	if _, err := st.GetUsage(context.Background(), time.Time{}); err == nil {
+		t.Error("expected error from nil store")
+	}
+}
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Verification is the last verify result for a post's URL.
+type Verification struct {
+	PostID     int64
+	Support    int    // support index, 0-100
+	Confidence string // e.g. "high"
+	Conflict   bool   // sources disagree
+	Signals    []string
+	CheckedAt  time.Time
+}
+
+// SaveVerification records v, replacing the post's earlier result.
+func (s *Store) SaveVerification(ctx context.Context, v Verification) error {
+	if s == nil || s.db == nil {
+		return errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	signals := v.Signals
+	if signals == nil {
+		signals = []string{}
+	}
+	signalsJSON, err := json.Marshal(signals)
+	if err != nil {
+		return fmt.Errorf("encode signals: %w", err)
+	}
+	conflict := 0
+	if v.Conflict {
+		conflict = 1
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO verifications(post_id, support, confidence, conflict, signals, checked_at)
+		VALUES(?, ?, ?, ?, ?, ?)
+		ON CONFLICT(post_id) DO UPDATE SET
+			support = excluded.support,
+			confidence = excluded.confidence,
+			conflict = excluded.conflict,
+			signals = excluded.signals,
+			checked_at = excluded.checked_at`,
+		v.PostID, v.Support, v.Confidence, conflict, string(signalsJSON), formatTime(v.CheckedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("save verification: %w", err)
+	}
+	return nil
+}
+
+// GetVerifications returns the verify results of the posts that have one,
+// by post ID.
+func (s *Store) GetVerifications(ctx context.Context, postIDs []int64) (map[int64]Verification, error) {
+	if s == nil || s.db == nil {
+		return nil, errors.New("store is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if len(postIDs) == 0 {
+		return nil, nil
+	}
+
+	placeholders := make([]string, len(postIDs))
+	args := make([]any, len(postIDs))
+	for i, id := range postIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT post_id, support, confidence, conflict, signals, checked_at
+		FROM verifications
+		WHERE post_id IN (%s)`, strings.Join(placeholders, ",")), args...)
+	if err != nil {
+		return nil, fmt.Errorf("get verifications: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	result := make(map[int64]Verification)
+	for rows.Next() {
+		var (
+			v                  Verification
+			conflict           int
+			signals, checkedAt string
+		)
+		if err := rows.Scan(&v.PostID, &v.Support, &v.Confidence, &conflict, &signals, &checkedAt); err != nil {
+			return nil, fmt.Errorf("scan verification: %w", err)
+		}
+		v.Conflict = conflict != 0
+		if err := json.Unmarshal([]byte(signals), &v.Signals); err != nil {
+			return nil, fmt.Errorf("decode signals: %w", err)
+		}
+		if v.CheckedAt, err = parseTime(checkedAt); err != nil {
+			return nil, fmt.Errorf("parse checked_at: %w", err)
+		}
+		result[v.PostID] = v
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate verifications: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,41 @@
+package store
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSaveAndGetVerifications(t *testing.T) {
+	st, _ := openTestStore(t)
+	ctx := context.Background()
+	cve, helm := insertSearchFixtures(t, st)
+	at := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	if err := st.SaveVerification(ctx, Verification{PostID: cve.ID, Support: 40, Confidence: "low", CheckedAt: at}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	// A later check replaces the earlier one.
+	if err := st.SaveVerification(ctx, Verification{
+		PostID: cve.ID, Support: 75, Confidence: "high", Conflict: true, Signals: []string{"vendor advisory"}, CheckedAt: at.Add(time.Hour),
+	}); err != nil {
+		t.Fatalf("save again: %v", err)
+	}
+
+	got, err := st.GetVerifications(ctx, []int64{cve.ID, helm.ID})
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d verifications, want 1", len(got))
+	}
+	v := got[cve.ID]
+	if v.Support != 75 || v.Confidence != "high" || !v.Conflict || !slices.Equal(v.Signals, []string{"vendor advisory"}) || !v.CheckedAt.Equal(at.Add(time.Hour)) {
+		t.Errorf("verification = %+v", v)
+	}
+
+	if none, err := st.GetVerifications(ctx, nil); err != nil || none != nil {
+		t.Errorf("no IDs = %v, %v", none, err)
+	}
+}
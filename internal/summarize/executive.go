@@ -0,0 +1,89 @@
+package summarize
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	executivePrompt = "You are briefing a senior DevOps engineer on today's digest of posts, listed by tier with their summaries. " +
+		"Answer in exactly this format and nothing else:\n" +
+		"OVERVIEW: one paragraph on what happened overall\n" +
+		"THEMES:\n- up to 4 recurring themes, one per line\n" +
+		"URGENT:\n- up to 3 items to act on first, one per line, naming the channel"
+	executiveMaxTokens = 500
+)
+
+// DigestSummary is an LLM's overview of a whole digest, shown above its
+// sections: one paragraph, the top themes, and the most urgent items.
+type DigestSummary struct {
+	Overview string   `json:"overview"`
+	Themes   []string `json:"themes,omitempty"`
+	Urgent   []string `json:"urgent,omitempty"`
+}
+
+// SummarizeDigest asks the LLM for a DigestSummary of digest, the listed
+// items as text. It shares the summarizer's cache and meter; unlike
+// Summarize it has no fallback, so errors are returned.
+func (l *LLMSummarizer) SummarizeDigest(digest string) (DigestSummary, error) {
+	var hash string
+	if l.cache != nil {
+		hash = TextHash(executivePrompt + "\x00" + digest)
+		if lines, ok := l.cache.Summary(hash, l.model); ok {
+			if s := parseDigestSummary(strings.Join(lines, "\n")); s.Overview != "" {
+				return s, nil
+			}
+		}
+	}
+	content, err := complete(l.provider, l.meter, l.client, l.model, executivePrompt, digest, executiveMaxTokens)
+	if err != nil {
+		return DigestSummary{}, err
+	}
+	s := parseDigestSummary(content)
+	if s.Overview == "" {
+		return DigestSummary{}, errors.New("no overview in response")
+	}
+	if l.cache != nil {
+		l.cache.SaveSummary(hash, l.model, strings.Split(strings.TrimSpace(content), "\n"))
+	}
+	return s, nil
+}
+
+// parseDigestSummary reads the OVERVIEW, THEMES, and URGENT sections of an
+// executive summary reply. Overview lines are joined; list lines start with
+// "-".
+func parseDigestSummary(content string) DigestSummary {
+	var (
+		s        DigestSummary
+		section  string
+		overview []string
+	)
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		key, rest, found := strings.Cut(line, ":")
+		if found {
+			switch strings.ToUpper(strings.Trim(key, "*# ")) {
+			case "OVERVIEW", "THEMES", "URGENT":
+				section = strings.ToUpper(strings.Trim(key, "*# "))
+				line = strings.TrimSpace(strings.Trim(rest, "* "))
+			}
+		}
+		if line == "" {
+			continue
+		}
+		switch section {
+		case "OVERVIEW":
+			overview = append(overview, line)
+		case "THEMES":
+			if b, ok := strings.CutPrefix(line, "-"); ok {
+				s.Themes = append(s.Themes, strings.TrimSpace(b))
+			}
+		case "URGENT":
+			if b, ok := strings.CutPrefix(line, "-"); ok {
+				s.Urgent = append(s.Urgent, strings.TrimSpace(b))
+			}
+		}
+	}
+	s.Overview = strings.Join(overview, " ")
+	return s
+}
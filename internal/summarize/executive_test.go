@@ -0,0 +1,70 @@
+package summarize
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseDigestSummary(t *testing.T) {
+	got := parseDigestSummary(`**OVERVIEW:** A quiet day apart from an OpenSSL fix.
+Kubernetes 1.34 is out.
+
+THEMES:
+- OpenSSL patches
+- Kubernetes releases
+
+URGENT:
+- Patch OpenSSL (security)
+`)
+	if got.Overview != "A quiet day apart from an OpenSSL fix. Kubernetes 1.34 is out." {
+		t.Errorf("overview = %q", got.Overview)
+	}
+	if !slices.Equal(got.Themes, []string{"OpenSSL patches", "Kubernetes releases"}) {
+		t.Errorf("themes = %q", got.Themes)
+	}
+	if !slices.Equal(got.Urgent, []string{"Patch OpenSSL (security)"}) {
+		t.Errorf("urgent = %q", got.Urgent)
+	}
+
+	if got := parseDigestSummary("- just bullets"); got.Overview != "" {
+		t.Errorf("no sections: overview = %q", got.Overview)
+	}
+}
+
+func TestLLM_SummarizeDigest(t *testing.T) {
+	calls := 0
+	s := llmWithTransport(func(r *http.Request) (*http.Response, error) {
+		calls++
+		var req chatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.Messages[0].Content != executivePrompt || !strings.Contains(req.Messages[1].Content, "OpenSSL") {
+			t.Errorf("messages = %+v", req.Messages)
+		}
+		return responseJSON("OVERVIEW: OpenSSL shipped a fix.\nTHEMES:\n- Security\nURGENT:\n- Patch OpenSSL")
+	})
+	s.SetCache(mapCache{})
+
+	digest := "[read_now] security — OpenSSL 3.5.1 fixes a crash"
+	for range 2 {
+		got, err := s.SummarizeDigest(digest)
+		if err != nil {
+			t.Fatalf("summarize digest: %v", err)
+		}
+		if got.Overview != "OpenSSL shipped a fix." || len(got.Themes) != 1 || len(got.Urgent) != 1 {
+			t.Errorf("summary = %+v", got)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("api calls = %d, want 1 (second from cache)", calls)
+	}
+
+	bad := llmWithTransport(func(*http.Request) (*http.Response, error) { return responseJSON("- no overview") })
+	if _, err := bad.SummarizeDigest(digest); err == nil {
+		t.Error("reply without overview: want error")
+	}
+}
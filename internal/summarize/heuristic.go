@@ -4,14 +4,12 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
-)
 
-var (
-	urlRe     = regexp.MustCompile(`https?://\S+`)
-	cveRe     = regexp.MustCompile(`CVE-\d{4}-\d{4,}`)
-	versionRe = regexp.MustCompile(`v?\d+\.\d+\.\d+`)
+	"github.com/ppiankov/noisepan/internal/entities"
 )
 
+var urlRe = regexp.MustCompile(`https?://\S+`)
+
 const (
 	maxBullets       = 3
 	maxFirstSentence = 120
@@ -20,15 +18,18 @@ const (
 var alertKeywords = []string{"breaking change", "deprecated", "removed"}
 
 // HeuristicSummarizer summarizes text using rule-based extraction.
-type HeuristicSummarizer struct{}
+type HeuristicSummarizer struct {
+	// Products lists, per vendor, the product names to find in text.
+	Products map[string][]string
+}
 
-// Summarize extracts key points, URLs, and CVE IDs from text.
+// Summarize extracts key points, URLs, CVE IDs, and other entities from
+// text.
 func (h *HeuristicSummarizer) Summarize(text string) Summary {
 	text = strings.TrimSpace(text)
 
-	links := urlRe.FindAllString(text, -1)
-	cves := cveRe.FindAllString(text, -1)
-	versions := versionRe.FindAllString(text, -1)
+	s := Summary{Links: urlRe.FindAllString(text, -1)}
+	addEntities(&s, text, h.Products)
 
 	var bullets []string
 
@@ -46,12 +47,12 @@ func (h *HeuristicSummarizer) Summarize(text string) Summary {
 
 	// Bullet 3: metadata summary
 	if len(bullets) < maxBullets {
-		if len(cves) > 0 {
-			bullets = append(bullets, "CVE: "+strings.Join(cves, ", "))
-		} else if len(versions) > 0 {
-			bullets = append(bullets, "Versions: "+strings.Join(versions, ", "))
-		} else if len(links) > 3 {
-			bullets = append(bullets, fmt.Sprintf("%d links included", len(links)))
+		if len(s.CVEs) > 0 {
+			bullets = append(bullets, "CVE: "+strings.Join(s.CVEs, ", "))
+		} else if len(s.Versions) > 0 {
+			bullets = append(bullets, "Versions: "+strings.Join(s.Versions, ", "))
+		} else if len(s.Links) > 3 {
+			bullets = append(bullets, fmt.Sprintf("%d links included", len(s.Links)))
 		}
 	}
 
@@ -60,11 +61,8 @@ func (h *HeuristicSummarizer) Summarize(text string) Summary {
 		bullets = bullets[:maxBullets]
 	}
 
-	return Summary{
-		Bullets: bullets,
-		Links:   links,
-		CVEs:    cves,
-	}
+	s.Bullets = bullets
+	return s
 }
 
 // firstSentence returns text up to the first sentence boundary, capped at maxLen.
@@ -150,3 +148,11 @@ func splitSentences(text string) []string {
 
 	return sentences
 }
+
+// addEntities fills in the entities of s found in text, with products as the
+// dictionary of products per vendor.
+func addEntities(s *Summary, text string, products map[string][]string) {
+	e := entities.Extract(text, products)
+	s.CVEs, s.Products, s.Vendors, s.Versions = e.CVEs, e.Products, e.Vendors, e.Versions
+	s.GHSAs, s.IPs, s.Dates = e.GHSAs, e.IPs, e.Dates
+}
@@ -29,6 +29,16 @@ func TestSummarize_WithURLs(t *testing.T) {
 	}
 }
 
+func TestSummarize_Entities(t *testing.T) {
+	s := &HeuristicSummarizer{Products: map[string][]string{"OpenSSL Project": {"OpenSSL"}}}
+	result := s.Summarize("OpenSSL 3.5.1 fixes GHSA-4m4x-9f2h-vq3c, reported from 203.0.113.7 on 2026-03-02.")
+
+	if len(result.Products) != 1 || len(result.Vendors) != 1 || len(result.GHSAs) != 1 ||
+		len(result.IPs) != 1 || len(result.Dates) != 1 || len(result.Versions) != 1 {
+		t.Errorf("entities = %+v", result)
+	}
+}
+
 func TestSummarize_WithCVEs(t *testing.T) {
 	s := &HeuristicSummarizer{}
 	result := s.Summarize("Critical vulnerability CVE-2026-1234 found in libfoo. Update immediately.")
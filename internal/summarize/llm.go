@@ -1,51 +1,95 @@
 package summarize
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"io"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
 	"net/http"
 	"strings"
 	"time"
 )
 
 const (
-	defaultEndpoint = "https://api.openai.com/v1/chat/completions"
-	httpTimeout     = 30 * time.Second
-	systemPrompt    = "Summarize for senior DevOps engineer. Focus on: breaking changes, incidents, security, architectural shifts. Max 4 bullets. Return only bullet points, one per line, starting with -"
+	httpTimeout  = 30 * time.Second
+	systemPrompt = "Summarize for senior DevOps engineer. Focus on: breaking changes, incidents, security, architectural shifts. Max 4 bullets. Return only bullet points, one per line, starting with -"
 )
 
-// LLMSummarizer sends post text to an OpenAI-compatible API for summarization.
+// Cache keeps LLM summary bullets across runs, by the TextHash of the
+// summarized text and the model. Implementations must be safe for
+// concurrent use; a failed save only costs a later API call.
+type Cache interface {
+	Summary(textHash, model string) ([]string, bool)
+	SaveSummary(textHash, model string, bullets []string)
+}
+
+// TextHash returns the hex sha256 of text, the key Cache uses.
+func TextHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// LLMSummarizer sends post text to an LLM API for summarization.
 // Falls back to the provided heuristic summarizer on any error.
 type LLMSummarizer struct {
-	apiKey    string
+	provider  Provider
 	model     string
 	maxTokens int
-	endpoint  string
 	fallback  Summarizer
 	client    *http.Client
+	cache     Cache // nil: always call the API
+	meter     Meter // nil: no usage recorded, no budget
 }
 
-// NewLLM creates an LLM summarizer with a heuristic fallback.
-func NewLLM(apiKey, model string, maxTokens int, fallback Summarizer) *LLMSummarizer {
+// NewLLM creates an LLM summarizer calling provider, with a heuristic
+// fallback.
+func NewLLM(provider Provider, model string, maxTokens int, fallback Summarizer) *LLMSummarizer {
 	return &LLMSummarizer{
-		apiKey:    apiKey,
+		provider:  provider,
 		model:     model,
 		maxTokens: maxTokens,
-		endpoint:  defaultEndpoint,
 		fallback:  fallback,
 		client:    &http.Client{Timeout: httpTimeout},
 	}
 }
 
+// SetTransport replaces the HTTP transport used for API requests.
+func (l *LLMSummarizer) SetTransport(rt http.RoundTripper) {
+	l.client.Transport = rt
+}
+
+// SetTimeout replaces the per-request timeout, 30s by default.
+func (l *LLMSummarizer) SetTimeout(d time.Duration) {
+	l.client.Timeout = d
+}
+
+// SetMeter records the token usage of every API call with meter, and falls
+// back to the heuristic summarizer once it reports the budget spent.
+func (l *LLMSummarizer) SetMeter(meter Meter) {
+	l.meter = meter
+}
+
+// SetCache makes Summarize reuse the bullets cache holds for the same text
+// and model, and save the ones it gets from the API. Fallback summaries are
+// not saved.
+func (l *LLMSummarizer) SetCache(cache Cache) {
+	l.cache = cache
+}
+
 // Summarize calls the LLM API and parses the response into bullets.
-// Links and CVEs are extracted via heuristic (LLM doesn't return structured data).
+// Links, CVEs, and, with a heuristic fallback, the other entities are
+// extracted via heuristic (LLM doesn't return structured data).
 // On any error, falls back to the heuristic summarizer.
 func (l *LLMSummarizer) Summarize(text string) Summary {
-	bullets, err := l.callAPI(text)
+	return l.SummarizeWith(text, "")
+}
+
+// SummarizeWith is Summarize with prompt as the system prompt instead of the
+// built-in one, which an empty prompt keeps. The prompt should still ask for
+// bullets starting with "-".
+func (l *LLMSummarizer) SummarizeWith(text, prompt string) Summary {
+	bullets, err := l.callAPI(text, prompt)
 	if err != nil {
-		fmt.Fprintf(io.Discard, "llm summarize: %v\n", err)
+		slog.Debug("llm summarize failed, using fallback", "err", err)
 		return l.fallback.Summarize(text)
 	}
 
@@ -53,59 +97,43 @@ func (l *LLMSummarizer) Summarize(text string) Summary {
 		return l.fallback.Summarize(text)
 	}
 
-	// Extract links and CVEs via heuristic
-	links := urlRe.FindAllString(text, -1)
-	cves := cveRe.FindAllString(text, -1)
-
-	return Summary{
+	// Extract links and entities via heuristic
+	s := Summary{
 		Bullets: bullets,
-		Links:   links,
-		CVEs:    cves,
+		Links:   urlRe.FindAllString(text, -1),
 	}
-}
-
-func (l *LLMSummarizer) callAPI(text string) ([]string, error) {
-	reqBody := chatRequest{
-		Model: l.model,
-		Messages: []chatMessage{
-			{Role: "system", Content: systemPrompt},
-			{Role: "user", Content: text},
-		},
-		MaxTokens: l.maxTokens,
+	var products map[string][]string
+	if h, ok := l.fallback.(*HeuristicSummarizer); ok {
+		products = h.Products
 	}
+	addEntities(&s, text, products)
+	return s
+}
 
-	body, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("marshal request: %w", err)
+func (l *LLMSummarizer) callAPI(text, prompt string) ([]string, error) {
+	var hash string
+	if l.cache != nil {
+		// A custom prompt's summaries are kept apart from the built-in one's.
+		hash = TextHash(text)
+		if prompt != "" {
+			hash = TextHash(prompt + "\x00" + text)
+		}
+		if bullets, ok := l.cache.Summary(hash, l.model); ok && len(bullets) > 0 {
+			return bullets, nil
+		}
 	}
-
-	req, err := http.NewRequest(http.MethodPost, l.endpoint, bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+	if prompt == "" {
+		prompt = systemPrompt
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+l.apiKey)
-
-	resp, err := l.client.Do(req)
+	content, err := complete(l.provider, l.meter, l.client, l.model, prompt, text, l.maxTokens)
 	if err != nil {
-		return nil, fmt.Errorf("http request: %w", err)
+		return nil, err
 	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("api returned status %d", resp.StatusCode)
-	}
-
-	var chatResp chatResponse
-	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
-	}
-
-	if len(chatResp.Choices) == 0 {
-		return nil, fmt.Errorf("empty choices in response")
+	bullets := parseBullets(content)
+	if l.cache != nil && len(bullets) > 0 {
+		l.cache.SaveSummary(hash, l.model, bullets)
 	}
-
-	return parseBullets(chatResp.Choices[0].Message.Content), nil
+	return bullets, nil
 }
 
 // parseBullets extracts lines starting with "-" from LLM output.
@@ -121,22 +149,3 @@ func parseBullets(content string) []string {
 	}
 	return bullets
 }
-
-type chatRequest struct {
-	Model     string        `json:"model"`
-	Messages  []chatMessage `json:"messages"`
-	MaxTokens int           `json:"max_tokens"`
-}
-
-type chatMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
-
-type chatResponse struct {
-	Choices []chatChoice `json:"choices"`
-}
-
-type chatChoice struct {
-	Message chatMessage `json:"message"`
-}
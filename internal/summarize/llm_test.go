@@ -18,8 +18,7 @@ func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
 
 func llmWithTransport(rt roundTripFunc) *LLMSummarizer {
 	fallback := &HeuristicSummarizer{}
-	s := NewLLM("test-key", "gpt-4", 200, fallback)
-	s.endpoint = "https://llm.test/v1/chat/completions"
+	s := NewLLM(&openAIProvider{endpoint: "https://llm.test/v1/chat/completions", apiKey: "test-key"}, "gpt-4", 200, fallback)
 	s.client = &http.Client{
 		Timeout:   httpTimeout,
 		Transport: rt,
@@ -69,6 +68,10 @@ func TestLLM_SuccessfulResponse(t *testing.T) {
 	if len(result.CVEs) != 1 || result.CVEs[0] != "CVE-2026-1234" {
 		t.Errorf("cves = %v", result.CVEs)
 	}
+	// and the other entities, with the heuristic fallback
+	if len(result.Versions) != 1 || result.Versions[0] != "v2.1.0" {
+		t.Errorf("versions = %v", result.Versions)
+	}
 }
 
 func TestLLM_APIError(t *testing.T) {
@@ -142,6 +145,109 @@ func TestLLM_MalformedJSON(t *testing.T) {
 	}
 }
 
+// mapCache is a Cache in memory.
+type mapCache map[string][]string
+
+func (c mapCache) Summary(textHash, model string) ([]string, bool) {
+	b, ok := c[textHash+"/"+model]
+	return b, ok
+}
+
+func (c mapCache) SaveSummary(textHash, model string, bullets []string) {
+	c[textHash+"/"+model] = bullets
+}
+
+func TestLLM_Cache(t *testing.T) {
+	calls := 0
+	s := llmWithTransport(func(_ *http.Request) (*http.Response, error) {
+		calls++
+		if calls > 1 {
+			return &http.Response{StatusCode: http.StatusInternalServerError, Body: io.NopCloser(strings.NewReader(""))}, nil
+		}
+		return responseJSON("- Patch released\n- Upgrade now")
+	})
+	cache := mapCache{}
+	s.SetCache(cache)
+
+	text := "Patch released for CVE-2026-1234."
+	first := s.Summarize(text)
+	second := s.Summarize(text)
+	if calls != 1 {
+		t.Errorf("api calls = %d, want 1", calls)
+	}
+	if len(second.Bullets) != 2 || second.Bullets[0] != first.Bullets[0] || len(second.CVEs) != 1 {
+		t.Errorf("cached summary = %+v, want %+v", second, first)
+	}
+	if _, ok := cache[TextHash(text)+"/gpt-4"]; !ok {
+		t.Errorf("cache = %v, want entry for text hash and model", cache)
+	}
+
+	// A failed call falls back without saving the fallback.
+	s.Summarize("Another post entirely.")
+	if calls != 2 || len(cache) != 1 {
+		t.Errorf("after failure: calls = %d, cache entries = %d; want 2, 1", calls, len(cache))
+	}
+}
+
+func TestLLM_SummarizeWith(t *testing.T) {
+	var systems []string
+	s := llmWithTransport(func(r *http.Request) (*http.Response, error) {
+		var req chatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		systems = append(systems, req.Messages[0].Content)
+		return responseJSON("- " + req.Messages[0].Content)
+	})
+	s.SetCache(mapCache{})
+
+	text := "OpenSSL 3.5.1 fixes CVE-2026-1234."
+	custom := s.SummarizeWith(text, "Extract affected versions and mitigations.")
+	builtin := s.Summarize(text)
+	again := s.SummarizeWith(text, "Extract affected versions and mitigations.")
+	if len(systems) != 2 || systems[0] != "Extract affected versions and mitigations." || systems[1] != systemPrompt {
+		t.Fatalf("system prompts = %q", systems)
+	}
+	// Each prompt's summary is cached on its own.
+	if custom.Bullets[0] == builtin.Bullets[0] || again.Bullets[0] != custom.Bullets[0] {
+		t.Errorf("custom = %q, builtin = %q, again = %q", custom.Bullets, builtin.Bullets, again.Bullets)
+	}
+}
+
+// budgetMeter is a Meter spent once it has recorded limit calls.
+type budgetMeter struct {
+	limit int
+	calls []Usage
+}
+
+func (m *budgetMeter) Record(_ string, u Usage) { m.calls = append(m.calls, u) }
+func (m *budgetMeter) Exhausted() bool          { return len(m.calls) >= m.limit }
+
+func TestLLM_Meter(t *testing.T) {
+	calls := 0
+	s := llmWithTransport(func(_ *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`{"choices":[{"message":{"role":"assistant","content":"- From the API"}}],
+				"usage":{"prompt_tokens":120,"completion_tokens":20}}`)),
+		}, nil
+	})
+	meter := &budgetMeter{limit: 1}
+	s.SetMeter(meter)
+
+	if got := s.Summarize("First post. It has a sentence."); len(got.Bullets) != 1 || got.Bullets[0] != "From the API" {
+		t.Errorf("first = %q, want the API's", got.Bullets)
+	}
+	// The budget is spent: the heuristic summarizes without calling the API.
+	if got := s.Summarize("Second post. It has a sentence."); len(got.Bullets) == 0 || got.Bullets[0] == "From the API" {
+		t.Errorf("second = %q, want fallback bullets", got.Bullets)
+	}
+	if calls != 1 || len(meter.calls) != 1 || meter.calls[0] != (Usage{PromptTokens: 120, CompletionTokens: 20}) {
+		t.Errorf("calls = %d, recorded = %+v", calls, meter.calls)
+	}
+}
+
 func TestParseBullets(t *testing.T) {
 	tests := []struct {
 		name  string
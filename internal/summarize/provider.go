@@ -0,0 +1,243 @@
+package summarize
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	defaultEndpoint          = "https://api.openai.com/v1/chat/completions"
+	defaultAnthropicEndpoint = "https://api.anthropic.com/v1/messages"
+	defaultOllamaEndpoint    = "http://localhost:11434/v1/chat/completions"
+	anthropicVersion         = "2023-06-01"
+)
+
+// Provider speaks one LLM API: it sends a system prompt and a user message
+// and returns the text of the reply and the tokens the call used.
+type Provider interface {
+	Complete(client *http.Client, model, system, user string, maxTokens int) (string, Usage, error)
+}
+
+// Usage is the token count of one API call, from the reply's usage field.
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// ErrBudget is returned instead of calling the API once the Meter reports
+// the budget spent.
+var ErrBudget = errors.New("llm: monthly budget spent")
+
+// Meter records the token usage of every API call and reports when the
+// budget for calls is spent. Implementations must be safe for concurrent
+// use.
+type Meter interface {
+	Record(model string, usage Usage)
+	Exhausted() bool
+}
+
+// complete calls provider unless meter, when set, reports the budget spent,
+// and records the usage of a successful call with it.
+func complete(provider Provider, meter Meter, client *http.Client, model, system, user string, maxTokens int) (string, error) {
+	if meter != nil && meter.Exhausted() {
+		return "", ErrBudget
+	}
+	content, usage, err := provider.Complete(client, model, system, user, maxTokens)
+	if err != nil {
+		return "", err
+	}
+	if meter != nil {
+		meter.Record(model, usage)
+	}
+	return content, nil
+}
+
+// NewProvider returns the provider summarize.llm.provider names: openai
+// (the default), anthropic, or ollama, which speaks the OpenAI API on a
+// local server and needs no key. An empty endpoint is the provider's own.
+func NewProvider(name, endpoint, apiKey string) (Provider, error) {
+	or := func(def string) string {
+		if endpoint != "" {
+			return endpoint
+		}
+		return def
+	}
+	switch name {
+	case "", "openai":
+		return &openAIProvider{endpoint: or(defaultEndpoint), apiKey: apiKey}, nil
+	case "anthropic":
+		return &anthropicProvider{endpoint: or(defaultAnthropicEndpoint), apiKey: apiKey}, nil
+	case "ollama":
+		return &openAIProvider{endpoint: or(defaultOllamaEndpoint), apiKey: apiKey}, nil
+	}
+	return nil, fmt.Errorf("unknown llm provider %q (want openai, anthropic, or ollama)", name)
+}
+
+// openAIProvider calls an OpenAI-compatible chat completions endpoint,
+// without an Authorization header when it has no key.
+type openAIProvider struct {
+	endpoint string
+	apiKey   string
+}
+
+// Complete sends a single system+user exchange and returns the first
+// choice's content.
+func (p *openAIProvider) Complete(client *http.Client, model, system, user string, maxTokens int) (string, Usage, error) {
+	reqBody := chatRequest{
+		Model: model,
+		Messages: []chatMessage{
+			{Role: "system", Content: system},
+			{Role: "user", Content: user},
+		},
+		MaxTokens: maxTokens,
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", Usage{}, fmt.Errorf("marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", Usage{}, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", Usage{}, fmt.Errorf("http request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", Usage{}, fmt.Errorf("api returned status %d", resp.StatusCode)
+	}
+
+	var chatResp chatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
+		return "", Usage{}, fmt.Errorf("decode response: %w", err)
+	}
+
+	if len(chatResp.Choices) == 0 {
+		return "", Usage{}, fmt.Errorf("empty choices in response")
+	}
+
+	usage := Usage{PromptTokens: chatResp.Usage.PromptTokens, CompletionTokens: chatResp.Usage.CompletionTokens}
+	return chatResp.Choices[0].Message.Content, usage, nil
+}
+
+type chatRequest struct {
+	Model     string        `json:"model"`
+	Messages  []chatMessage `json:"messages"`
+	MaxTokens int           `json:"max_tokens"`
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatResponse struct {
+	Choices []chatChoice `json:"choices"`
+	Usage   struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+type chatChoice struct {
+	Message chatMessage `json:"message"`
+}
+
+// anthropicProvider calls the Anthropic Messages API, which takes the
+// system prompt outside the messages, authenticates with an x-api-key
+// header, and returns the reply as content blocks.
+type anthropicProvider struct {
+	endpoint string
+	apiKey   string
+}
+
+// Complete sends a single system+user exchange and returns the reply's
+// text blocks joined.
+func (p *anthropicProvider) Complete(client *http.Client, model, system, user string, maxTokens int) (string, Usage, error) {
+	body, err := json.Marshal(messagesRequest{
+		Model:     model,
+		System:    system,
+		Messages:  []chatMessage{{Role: "user", Content: user}},
+		MaxTokens: maxTokens,
+	})
+	if err != nil {
+		return "", Usage{}, fmt.Errorf("marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", Usage{}, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Api-Key", p.apiKey)
+	req.Header.Set("Anthropic-Version", anthropicVersion)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", Usage{}, fmt.Errorf("http request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var msgResp messagesResponse
+	decodeErr := json.NewDecoder(resp.Body).Decode(&msgResp)
+	if resp.StatusCode != http.StatusOK {
+		if decodeErr == nil && msgResp.Error != nil {
+			return "", Usage{}, fmt.Errorf("api returned status %d: %s: %s", resp.StatusCode, msgResp.Error.Type, msgResp.Error.Message)
+		}
+		return "", Usage{}, fmt.Errorf("api returned status %d", resp.StatusCode)
+	}
+	if decodeErr != nil {
+		return "", Usage{}, fmt.Errorf("decode response: %w", decodeErr)
+	}
+
+	var text []string
+	for _, block := range msgResp.Content {
+		if block.Type == "text" {
+			text = append(text, block.Text)
+		}
+	}
+	if len(text) == 0 {
+		return "", Usage{}, errors.New("no text content in response")
+	}
+	usage := Usage{PromptTokens: msgResp.Usage.InputTokens, CompletionTokens: msgResp.Usage.OutputTokens}
+	return strings.Join(text, ""), usage, nil
+}
+
+type messagesRequest struct {
+	Model     string        `json:"model"`
+	System    string        `json:"system,omitempty"`
+	Messages  []chatMessage `json:"messages"`
+	MaxTokens int           `json:"max_tokens"`
+}
+
+type messagesResponse struct {
+	Content []contentBlock `json:"content"`
+	Usage   struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+	Error *apiError `json:"error"`
+}
+
+type contentBlock struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type apiError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
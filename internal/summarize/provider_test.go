@@ -0,0 +1,133 @@
+package summarize
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNewProvider(t *testing.T) {
+	for name, want := range map[string]string{"": defaultEndpoint, "openai": defaultEndpoint} {
+		p, err := NewProvider(name, "", "k")
+		if err != nil || p.(*openAIProvider).endpoint != want {
+			t.Errorf("NewProvider(%q) = %+v, %v", name, p, err)
+		}
+	}
+	if p, err := NewProvider("anthropic", "", "k"); err != nil || p.(*anthropicProvider).endpoint != defaultAnthropicEndpoint {
+		t.Errorf("NewProvider(anthropic) = %+v, %v", p, err)
+	}
+	if p, err := NewProvider("ollama", "", ""); err != nil || p.(*openAIProvider).endpoint != defaultOllamaEndpoint {
+		t.Errorf("NewProvider(ollama) = %+v, %v", p, err)
+	}
+	if p, err := NewProvider("anthropic", "http://proxy.test/v1/messages", "k"); err != nil || p.(*anthropicProvider).endpoint != "http://proxy.test/v1/messages" {
+		t.Errorf("NewProvider(anthropic, endpoint) = %+v, %v", p, err)
+	}
+	if _, err := NewProvider("gemini", "", "k"); err == nil {
+		t.Error("NewProvider(gemini): want error")
+	}
+}
+
+func TestOpenAIProvider_NoKey(t *testing.T) {
+	p, err := NewProvider("ollama", "http://gpu.local:11434/v1/chat/completions", "")
+	if err != nil {
+		t.Fatalf("provider: %v", err)
+	}
+	client := anthropicClient(func(r *http.Request) (*http.Response, error) {
+		if r.URL.String() != "http://gpu.local:11434/v1/chat/completions" {
+			t.Errorf("url = %s", r.URL)
+		}
+		if _, ok := r.Header["Authorization"]; ok {
+			t.Errorf("authorization = %q, want none", r.Header.Get("Authorization"))
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`{"choices":[{"message":{"role":"assistant","content":"- Local point"}}],
+				"usage":{"prompt_tokens":30,"completion_tokens":5,"total_tokens":35}}`)),
+		}, nil
+	})
+
+	got, usage, err := p.Complete(client, "llama3.2", "be brief", "post text", 200)
+	if err != nil || got != "- Local point" || usage != (Usage{PromptTokens: 30, CompletionTokens: 5}) {
+		t.Errorf("complete = %q, %+v, %v", got, usage, err)
+	}
+}
+
+func anthropicClient(rt roundTripFunc) *http.Client {
+	return &http.Client{Timeout: httpTimeout, Transport: rt}
+}
+
+func TestAnthropicProvider_Complete(t *testing.T) {
+	p := &anthropicProvider{endpoint: "https://llm.test/v1/messages", apiKey: "test-key"}
+	client := anthropicClient(func(r *http.Request) (*http.Response, error) {
+		if got := r.Header.Get("X-Api-Key"); got != "test-key" {
+			t.Errorf("x-api-key = %q", got)
+		}
+		if got := r.Header.Get("Anthropic-Version"); got != anthropicVersion {
+			t.Errorf("anthropic-version = %q", got)
+		}
+		if got := r.Header.Get("Authorization"); got != "" {
+			t.Errorf("authorization = %q, want none", got)
+		}
+		var req messagesRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.System != "be brief" || len(req.Messages) != 1 || req.Messages[0].Role != "user" ||
+			req.Messages[0].Content != "post text" || req.MaxTokens != 200 || req.Model != "claude-haiku-4-5" {
+			t.Errorf("request = %+v", req)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`{"id":"msg_1","type":"message","role":"assistant",
+				"content":[{"type":"text","text":"- First point\n"},{"type":"text","text":"- Second point"}],
+				"stop_reason":"end_turn","usage":{"input_tokens":42,"output_tokens":9}}`)),
+		}, nil
+	})
+
+	got, usage, err := p.Complete(client, "claude-haiku-4-5", "be brief", "post text", 200)
+	if err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if got != "- First point\n- Second point" {
+		t.Errorf("content = %q", got)
+	}
+	if usage != (Usage{PromptTokens: 42, CompletionTokens: 9}) {
+		t.Errorf("usage = %+v", usage)
+	}
+}
+
+func TestAnthropicProvider_Errors(t *testing.T) {
+	p := &anthropicProvider{endpoint: "https://llm.test/v1/messages", apiKey: "bad"}
+	for _, tc := range []struct {
+		status int
+		body   string
+		want   string
+	}{
+		{http.StatusUnauthorized, `{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`,
+			"api returned status 401: authentication_error: invalid x-api-key"},
+		{http.StatusBadGateway, `<html>`, "api returned status 502"},
+		{http.StatusOK, `{"content":[]}`, "no text content in response"},
+	} {
+		client := anthropicClient(func(_ *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: tc.status, Body: io.NopCloser(strings.NewReader(tc.body))}, nil
+		})
+		if _, _, err := p.Complete(client, "m", "s", "u", 10); err == nil || err.Error() != tc.want {
+			t.Errorf("status %d: err = %v, want %q", tc.status, err, tc.want)
+		}
+	}
+}
+
+func TestLLM_AnthropicProvider(t *testing.T) {
+	s := NewLLM(&anthropicProvider{endpoint: "https://llm.test/v1/messages", apiKey: "k"}, "claude-haiku-4-5", 200, &HeuristicSummarizer{})
+	s.SetTransport(roundTripFunc(func(_ *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"content":[{"type":"text","text":"- Patch libfoo now"}]}`)),
+		}, nil
+	}))
+	if got := s.Summarize("CVE-2026-1234 in libfoo"); len(got.Bullets) != 1 || got.Bullets[0] != "Patch libfoo now" {
+		t.Errorf("summary = %+v", got)
+	}
+}
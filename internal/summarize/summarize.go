@@ -5,6 +5,15 @@ type Summary struct {
 	Bullets []string // 1-3 key points
 	Links   []string // extracted URLs
 	CVEs    []string // extracted CVE IDs
+
+	// Entities the heuristic finds in the text, with either summarizer.
+	// See entities.Set.
+	Products []string // from summarize.products, as spelled there
+	Vendors  []string // of those products, or named themselves
+	Versions []string // versions and semver ranges, e.g. ">= 2.0.0, < 2.3.4"
+	GHSAs    []string // GitHub security advisory IDs
+	IPs      []string // IP addresses and CIDR prefixes
+	Dates    []string // YYYY-MM-DD
 }
 
 // Summarizer produces a summary from post text.
@@ -0,0 +1,110 @@
+package summarize
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/taste"
+)
+
+const (
+	triagePrompt    = "Classify this headline for a senior DevOps engineer. Answer with exactly one word: read_now (must read: incidents, security, breaking changes, major releases), skim (mildly interesting), or ignore (noise, marketing, off-topic)."
+	triageMaxTokens = 5
+)
+
+// ErrTriageBudget is returned once a Triager has used its per-run call budget.
+var ErrTriageBudget = errors.New("llm triage: call budget exhausted")
+
+// Triager classifies headlines into tiers via a cheap LLM call. Calls are
+// spaced at least interval apart and capped at maxCalls per Triager.
+type Triager struct {
+	provider Provider
+	model    string
+	client   *http.Client
+	interval time.Duration
+	maxCalls int
+
+	mu    sync.Mutex
+	calls int
+	last  time.Time
+	sleep func(time.Duration)
+	meter Meter // nil: no usage recorded, no budget
+}
+
+// NewTriage creates a rate-limited headline triager calling provider.
+func NewTriage(provider Provider, model string, interval time.Duration, maxCalls int) *Triager {
+	return &Triager{
+		provider: provider,
+		model:    model,
+		client:   &http.Client{Timeout: httpTimeout},
+		interval: interval,
+		maxCalls: maxCalls,
+		sleep:    time.Sleep,
+	}
+}
+
+// SetTransport replaces the HTTP transport used for API requests.
+func (t *Triager) SetTransport(rt http.RoundTripper) {
+	t.client.Transport = rt
+}
+
+// SetTimeout replaces the per-request timeout, 30s by default.
+func (t *Triager) SetTimeout(d time.Duration) {
+	t.client.Timeout = d
+}
+
+// SetMeter records the token usage of every API call with meter; once it
+// reports the budget spent, Classify returns ErrBudget.
+func (t *Triager) SetMeter(meter Meter) {
+	t.meter = meter
+}
+
+// Classify returns taste.TierReadNow, taste.TierSkim, or taste.TierIgnore
+// for the headline. Errors leave the caller's keyword score in place.
+func (t *Triager) Classify(headline string) (string, error) {
+	if err := t.wait(); err != nil {
+		return "", err
+	}
+
+	content, err := complete(t.provider, t.meter, t.client, t.model, triagePrompt, headline, triageMaxTokens)
+	if err != nil {
+		return "", err
+	}
+	return parseTier(content)
+}
+
+// wait enforces the call budget and minimum spacing between calls.
+func (t *Triager) wait() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.maxCalls > 0 && t.calls >= t.maxCalls {
+		return ErrTriageBudget
+	}
+	if !t.last.IsZero() {
+		if gap := t.interval - time.Since(t.last); gap > 0 {
+			t.sleep(gap)
+		}
+	}
+	t.calls++
+	t.last = time.Now()
+	return nil
+}
+
+func parseTier(content string) (string, error) {
+	answer := strings.ToLower(strings.TrimSpace(content))
+	answer = strings.Trim(answer, ".\"'`")
+	switch {
+	case strings.HasPrefix(answer, taste.TierReadNow), strings.HasPrefix(answer, "read now"):
+		return taste.TierReadNow, nil
+	case strings.HasPrefix(answer, taste.TierSkim):
+		return taste.TierSkim, nil
+	case strings.HasPrefix(answer, taste.TierIgnore):
+		return taste.TierIgnore, nil
+	}
+	return "", fmt.Errorf("llm triage: unrecognized answer %q", answer)
+}
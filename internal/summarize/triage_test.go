@@ -0,0 +1,119 @@
+package summarize
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func triageWithTransport(rt roundTripFunc, interval time.Duration, maxCalls int) *Triager {
+	tr := NewTriage(&openAIProvider{endpoint: "https://llm.test/v1/chat/completions", apiKey: "test-key"}, "gpt-4.1-nano", interval, maxCalls)
+	tr.client = &http.Client{Timeout: httpTimeout, Transport: rt}
+	return tr
+}
+
+func TestTriage_Classify(t *testing.T) {
+	tr := triageWithTransport(func(r *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "Kernel regression in 6.9") {
+			t.Errorf("request body missing headline: %s", body)
+		}
+		return responseJSON("Skim.")
+	}, 0, 0)
+
+	tier, err := tr.Classify("Kernel regression in 6.9")
+	if err != nil {
+		t.Fatalf("classify: %v", err)
+	}
+	if tier != "skim" {
+		t.Errorf("tier = %q, want skim", tier)
+	}
+}
+
+func TestTriage_Budget(t *testing.T) {
+	calls := 0
+	tr := triageWithTransport(func(_ *http.Request) (*http.Response, error) {
+		calls++
+		return responseJSON("ignore")
+	}, 0, 2)
+
+	for i := 0; i < 2; i++ {
+		if _, err := tr.Classify("headline"); err != nil {
+			t.Fatalf("classify %d: %v", i, err)
+		}
+	}
+	if _, err := tr.Classify("headline"); !errors.Is(err, ErrTriageBudget) {
+		t.Fatalf("err = %v, want ErrTriageBudget", err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
+
+func TestTriage_Meter(t *testing.T) {
+	calls := 0
+	tr := triageWithTransport(func(_ *http.Request) (*http.Response, error) {
+		calls++
+		return responseJSON("ignore")
+	}, 0, 0)
+	meter := &budgetMeter{limit: 1}
+	tr.SetMeter(meter)
+
+	if _, err := tr.Classify("headline"); err != nil {
+		t.Fatalf("classify: %v", err)
+	}
+	if _, err := tr.Classify("headline"); !errors.Is(err, ErrBudget) {
+		t.Fatalf("err = %v, want ErrBudget", err)
+	}
+	if calls != 1 || len(meter.calls) != 1 {
+		t.Errorf("calls = %d, recorded = %d; want 1, 1", calls, len(meter.calls))
+	}
+}
+
+func TestTriage_RateLimit(t *testing.T) {
+	tr := triageWithTransport(func(_ *http.Request) (*http.Response, error) {
+		return responseJSON("read_now")
+	}, time.Minute, 0)
+	var slept []time.Duration
+	tr.sleep = func(d time.Duration) { slept = append(slept, d) }
+
+	for i := 0; i < 3; i++ {
+		if _, err := tr.Classify("headline"); err != nil {
+			t.Fatalf("classify: %v", err)
+		}
+	}
+	if len(slept) != 2 {
+		t.Fatalf("slept %d times, want 2", len(slept))
+	}
+	for _, d := range slept {
+		if d <= 0 || d > time.Minute {
+			t.Errorf("sleep = %v, want within (0, 1m]", d)
+		}
+	}
+}
+
+func TestParseTier(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"read_now", "read_now", false},
+		{"Read now", "read_now", false},
+		{"  SKIM\n", "skim", false},
+		{"\"ignore\".", "ignore", false},
+		{"maybe", "", true},
+	}
+	for _, tt := range tests {
+		got, err := parseTier(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseTier(%q) err = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("parseTier(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package taste
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/ppiankov/noisepan/internal/config"
+)
+
+// ProfileChange is how the current taste profile differs from one posts
+// were scored with, for rescoring only the posts the difference reaches.
+type ProfileChange struct {
+	// All is set when more changed than top-level keywords and rules
+	// (thresholds, modifiers, languages, match options, ...), which can
+	// move any post.
+	All bool
+
+	gone     map[string]bool // reasons of old keywords and rules that changed
+	keywords []string        // current keywords that are new or reweighted
+	rules    []config.RuleCondition
+	match    config.MatchConfig
+	products map[string][]string
+}
+
+// Changes compares old, a profile posts were scored with, to cur.
+func Changes(old, cur *config.TasteProfile) *ProfileChange {
+	c := &ProfileChange{gone: make(map[string]bool), match: cur.Match, products: cur.Products}
+
+	// Everything but keywords and rules (and labels, which do not score)
+	// is compared whole.
+	rest := func(tp *config.TasteProfile) []byte {
+		cp := *tp
+		cp.Weights, cp.Rules, cp.Labels = config.Weights{}, nil, nil
+		data, _ := cp.Snapshot()
+		return data
+	}
+	if !bytes.Equal(rest(old), rest(cur)) {
+		c.All = true
+		return c
+	}
+
+	for _, sec := range []struct{ old, cur map[string]int }{
+		{old.Weights.HighSignal, cur.Weights.HighSignal},
+		{old.Weights.LowSignal, cur.Weights.LowSignal},
+	} {
+		for _, kw := range slices.Sorted(maps.Keys(sec.old)) {
+			if w, ok := sec.cur[kw]; !ok || w != sec.old[kw] {
+				c.gone["keyword: "+kw] = true
+			}
+		}
+		for _, kw := range slices.Sorted(maps.Keys(sec.cur)) {
+			if w, ok := sec.old[kw]; !ok || w != sec.cur[kw] {
+				c.keywords = append(c.keywords, kw)
+			}
+		}
+	}
+
+	// Rules are told apart by their whole content, so editing one counts as
+	// removing it and adding another.
+	ruleKey := func(r config.Rule) string {
+		return fmt.Sprintf("%q %q %q %q %d %q %s", r.If.ContainsAny, r.If.ContainsAll, r.If.NotContainsAny, r.If.HasEntity,
+			r.Then.ScoreAdd, r.Then.Labels, r.Cooldown)
+	}
+	oldRules := make(map[string]int)
+	for _, r := range old.Rules {
+		oldRules[ruleKey(r)]++
+	}
+	for _, r := range cur.Rules {
+		if k := ruleKey(r); oldRules[k] > 0 {
+			oldRules[k]--
+		} else {
+			c.rules = append(c.rules, r.If)
+		}
+	}
+	for _, r := range old.Rules {
+		if k := ruleKey(r); oldRules[k] > 0 {
+			oldRules[k]--
+			c.gone[ruleReason(r)] = true
+		}
+	}
+	return c
+}
+
+// Affects reports whether a post with text, scored with the old profile
+// into explanation, may score differently with the current one: a keyword
+// or rule it matched changed or went, or a new or changed one matches it.
+func (c *ProfileChange) Affects(text string, explanation []ScoreContribution) bool {
+	if c.All {
+		return true
+	}
+	for _, e := range explanation {
+		if c.gone[strings.TrimSuffix(e.Reason, " (cooldown)")] {
+			return true
+		}
+	}
+	mt := newMatchText(text)
+	mt.products = c.products
+	for _, kw := range c.keywords {
+		if mt.contains(kw, c.match.For(kw)) {
+			return true
+		}
+	}
+	return slices.ContainsFunc(c.rules, func(cond config.RuleCondition) bool {
+		return ruleMatches(mt, cond, c.match)
+	})
+}
+
+// ruleReason is the explanation reason Score gives a rule.
+func ruleReason(r config.Rule) string {
+	if terms := r.If.Terms(); len(terms) > 0 {
+		return "rule: " + terms[0]
+	}
+	if len(r.If.HasEntity) > 0 {
+		return "rule: has " + r.If.HasEntity[0]
+	}
+	return "rule"
+}
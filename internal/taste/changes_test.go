@@ -0,0 +1,43 @@
+package taste
+
+import "testing"
+
+func TestChanges(t *testing.T) {
+	old := testProfile()
+	cur := testProfile()
+	cur.Weights.HighSignal["kubernetes"] = 4
+	cur.Weights.HighSignal["postgres"] = 3
+	delete(cur.Weights.LowSignal, "hiring")
+	cur.Rules[1].Then.ScoreAdd = -8 // the webinar rule
+	cur.Labels = map[string][]string{"db": {"postgres"}}
+
+	c := Changes(old, cur)
+	if c.All {
+		t.Fatal("All set for keyword and rule changes only")
+	}
+	for _, tc := range []struct {
+		text string
+		want bool
+	}{
+		{"kubernetes release", true},     // reweighted keyword matched
+		{"we are hiring", true},          // removed keyword matched
+		{"postgres vacuum notes", true},  // new keyword matches
+		{"join us for a webinar", true},  // changed rule matched
+		{"cve in openssl", false},        // matched only what did not change
+		{"expired certificate", false},   // unchanged rule
+		{"nothing relevant here", false}, // matched nothing
+	} {
+		sp := Score(post(tc.text), old)
+		if got := c.Affects(tc.text, sp.Explanation); got != tc.want {
+			t.Errorf("Affects(%q) = %v, want %v (explanation %+v)", tc.text, got, tc.want, sp.Explanation)
+		}
+	}
+
+	cur.Thresholds.ReadNow = 8
+	if c := Changes(old, cur); !c.All || !c.Affects("nothing relevant here", nil) {
+		t.Error("a threshold change does not reach every post")
+	}
+	if c := Changes(old, testProfile()); c.Affects("kubernetes cve webinar", Score(post("kubernetes cve webinar"), old).Explanation) {
+		t.Error("an unchanged profile reaches a post")
+	}
+}
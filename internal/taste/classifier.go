@@ -0,0 +1,155 @@
+package taste
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/ppiankov/noisepan/internal/config"
+)
+
+// classifierVersion is bumped when the model file layout changes.
+const classifierVersion = 1
+
+// Example is a labeled post used to train a Classifier. Weight scales how
+// much it counts; explicit feedback should outweigh inferred labels.
+type Example struct {
+	Text     string
+	Positive bool // worth reading
+	Weight   float64
+}
+
+// Classifier is a naive Bayes model over post tokens that estimates how
+// likely a post is worth reading. Each token counts once per post.
+type Classifier struct {
+	Version  int         `json:"version"`
+	Positive classCounts `json:"positive"`
+	Negative classCounts `json:"negative"`
+	Vocab    int         `json:"vocab"`
+}
+
+type classCounts struct {
+	Docs   float64            `json:"docs"`
+	Total  float64            `json:"total"`
+	Tokens map[string]float64 `json:"tokens"`
+}
+
+// Train fits a classifier to examples. Both classes need at least one example.
+func Train(examples []Example) (*Classifier, error) {
+	c := &Classifier{
+		Version:  classifierVersion,
+		Positive: classCounts{Tokens: make(map[string]float64)},
+		Negative: classCounts{Tokens: make(map[string]float64)},
+	}
+	vocab := make(map[string]bool)
+	for _, ex := range examples {
+		w := ex.Weight
+		if w <= 0 {
+			w = 1
+		}
+		cls := &c.Negative
+		if ex.Positive {
+			cls = &c.Positive
+		}
+		cls.Docs += w
+		for tok := range tokenSet(ex.Text) {
+			cls.Tokens[tok] += w
+			cls.Total += w
+			vocab[tok] = true
+		}
+	}
+	if c.Positive.Docs == 0 || c.Negative.Docs == 0 {
+		return nil, errors.New("need examples of both worth-reading and noise posts")
+	}
+	c.Vocab = len(vocab)
+	return c, nil
+}
+
+// Probability returns the estimated probability that text is worth reading.
+// Tokens never seen in training are ignored.
+func (c *Classifier) Probability(text string) float64 {
+	docs := c.Positive.Docs + c.Negative.Docs
+	logPos := math.Log(c.Positive.Docs / docs)
+	logNeg := math.Log(c.Negative.Docs / docs)
+	vocab := float64(c.Vocab)
+	for tok := range tokenSet(text) {
+		p, pok := c.Positive.Tokens[tok]
+		n, nok := c.Negative.Tokens[tok]
+		if !pok && !nok {
+			continue
+		}
+		// Laplace smoothing keeps tokens seen in only one class finite.
+		logPos += math.Log((p + 1) / (c.Positive.Total + vocab))
+		logNeg += math.Log((n + 1) / (c.Negative.Total + vocab))
+	}
+	return 1 / (1 + math.Exp(logNeg-logPos))
+}
+
+// Apply adds the classifier's contribution to a scored post: from
+// -maxPoints (certain noise) through 0 (undecided) to +maxPoints (certainly
+// worth reading). The tier is recomputed from the new score, within what
+// the post allows (see ScoredPost.BoundTier).
+func (c *Classifier) Apply(sp ScoredPost, maxPoints int, tiers []config.Tier) ScoredPost {
+	p := c.Probability(sp.Post.Text)
+	points := int(math.Round((2*p - 1) * float64(maxPoints)))
+	if points == 0 {
+		return sp
+	}
+	sp.Score += points
+	sp.Tier = sp.BoundTier(tierOf(sp.Score, tiers), tiers)
+	sp.Explanation = append(sp.Explanation, ScoreContribution{
+		Reason: fmt.Sprintf("classifier: %.0f%% worth reading", p*100),
+		Points: points,
+	})
+	return sp
+}
+
+// LoadClassifier reads a model written by Save.
+func LoadClassifier(path string) (*Classifier, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read classifier: %w", err)
+	}
+	var c Classifier
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parse classifier: %w", err)
+	}
+	if c.Version != classifierVersion {
+		return nil, fmt.Errorf("classifier version %d is not supported (retrain with 'noisepan taste train')", c.Version)
+	}
+	if c.Positive.Docs == 0 || c.Negative.Docs == 0 {
+		return nil, errors.New("classifier has no training data")
+	}
+	return &c, nil
+}
+
+// Save writes the model as JSON.
+func (c *Classifier) Save(path string) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("encode classifier: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("write classifier: %w", err)
+	}
+	return nil
+}
+
+// tokenSet returns the distinct lowercase words of text with at least three
+// letters or digits.
+func tokenSet(text string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		if len([]rune(w)) >= 3 {
+			set[w] = true
+		}
+	}
+	return set
+}
@@ -0,0 +1,85 @@
+package taste
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/source"
+)
+
+func trainTestClassifier(t *testing.T) *Classifier {
+	t.Helper()
+	c, err := Train([]Example{
+		{Text: "Critical kernel exploit patched", Positive: true},
+		{Text: "Kernel 7.2 released with exploit mitigations", Positive: true},
+		{Text: "Postmortem: kernel outage at scale", Positive: true},
+		{Text: "Join our webinar on cloud savings", Positive: false},
+		{Text: "Webinar recording: sponsored cloud tools", Positive: false},
+		{Text: "Top 10 cloud tips, sponsored", Positive: false, Weight: 2},
+	})
+	if err != nil {
+		t.Fatalf("Train: %v", err)
+	}
+	return c
+}
+
+func TestClassifierProbability(t *testing.T) {
+	c := trainTestClassifier(t)
+
+	if p := c.Probability("New kernel exploit in the wild"); p < 0.8 {
+		t.Errorf("kernel exploit: p = %.2f, want > 0.8", p)
+	}
+	if p := c.Probability("Free webinar: cloud"); p > 0.2 {
+		t.Errorf("webinar: p = %.2f, want < 0.2", p)
+	}
+	// Only unknown tokens: falls back to the class prior (3 vs 4 docs).
+	if p := c.Probability("zzz qqq"); p < 0.4 || p > 0.5 {
+		t.Errorf("unknown: p = %.2f, want prior 3/7", p)
+	}
+}
+
+func TestTrain_NeedsBothClasses(t *testing.T) {
+	if _, err := Train([]Example{{Text: "only good", Positive: true}}); err == nil {
+		t.Fatal("expected error with one class")
+	}
+}
+
+func TestClassifierApply(t *testing.T) {
+	c := trainTestClassifier(t)
+	tiers := (&config.TasteProfile{Thresholds: config.Thresholds{ReadNow: 7, Skim: 3, Ignore: 0}}).TierSet()
+
+	sp := ScoredPost{Post: source.Post{Text: "kernel exploit postmortem"}, Score: 2, Tier: TierIgnore}
+	got := c.Apply(sp, 3, tiers)
+	if got.Score != 5 || got.Tier != TierSkim {
+		t.Errorf("score = %d, tier = %s, want 5 skim", got.Score, got.Tier)
+	}
+	if len(got.Explanation) != 1 || got.Explanation[0].Points != 3 {
+		t.Errorf("explanation = %+v", got.Explanation)
+	}
+
+	got = c.Apply(ScoredPost{Post: source.Post{Text: "sponsored webinar"}, Score: 4, Tier: TierSkim}, 3, tiers)
+	if got.Score >= 4 || got.Tier != TierIgnore {
+		t.Errorf("score = %d, tier = %s, want lowered to ignore", got.Score, got.Tier)
+	}
+}
+
+func TestClassifierSaveLoad(t *testing.T) {
+	c := trainTestClassifier(t)
+	path := filepath.Join(t.TempDir(), "classifier.json")
+	if err := c.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := LoadClassifier(path)
+	if err != nil {
+		t.Fatalf("LoadClassifier: %v", err)
+	}
+	text := "kernel webinar"
+	if loaded.Probability(text) != c.Probability(text) {
+		t.Errorf("loaded model disagrees: %v vs %v", loaded.Probability(text), c.Probability(text))
+	}
+
+	if _, err := LoadClassifier(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
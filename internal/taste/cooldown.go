@@ -0,0 +1,104 @@
+package taste
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/source"
+)
+
+// CooldownHit is the time of the post a rule with a cooldown last added
+// points to in a channel.
+type CooldownHit struct {
+	Rule    string // RuleKey of the rule
+	Source  string
+	Channel string
+	At      time.Time
+}
+
+type cooldownKey struct {
+	rule, source, channel string
+}
+
+// Cooldowns remembers when rules with a cooldown last fired per channel. It
+// is not safe for concurrent use.
+type Cooldowns struct {
+	last    map[cooldownKey]time.Time
+	changed map[cooldownKey]bool
+}
+
+// NewCooldowns returns cooldowns seeded with previously saved hits.
+func NewCooldowns(hits []CooldownHit) *Cooldowns {
+	c := &Cooldowns{last: make(map[cooldownKey]time.Time), changed: make(map[cooldownKey]bool)}
+	for _, h := range hits {
+		c.last[cooldownKey{h.Rule, h.Source, h.Channel}] = h.At
+	}
+	return c
+}
+
+// RuleKey identifies a rule across runs by its keywords, so reordering
+// rules keeps their cooldowns.
+func RuleKey(rule config.Rule) string {
+	key := strings.Join(rule.If.ContainsAny, "|")
+	if len(rule.If.ContainsAll) > 0 {
+		key += "&" + strings.Join(rule.If.ContainsAll, "&")
+	}
+	if len(rule.If.NotContainsAny) > 0 {
+		key += "!" + strings.Join(rule.If.NotContainsAny, "!")
+	}
+	if len(rule.If.HasEntity) > 0 {
+		key += "#" + strings.Join(rule.If.HasEntity, "#")
+	}
+	return strings.ToLower(key)
+}
+
+// allow reports whether rule may add points to post: it has no cooldown, or
+// did not fire in the post's channel within the cooldown of the post's time.
+// A hit at exactly the post's time is the post's own, as when an edited post
+// is rescored, so it allows the post. Allowed posts newer than the last hit
+// become the last hit.
+func (c *Cooldowns) allow(rule config.Rule, post source.Post) bool {
+	if c == nil || rule.Cooldown.Duration <= 0 {
+		return true
+	}
+	k := cooldownKey{RuleKey(rule), post.Source, post.Channel}
+	last, ok := c.last[k]
+	if ok && !post.PostedAt.Equal(last) {
+		gap := post.PostedAt.Sub(last)
+		if gap < 0 {
+			gap = -gap
+		}
+		if gap < rule.Cooldown.Duration {
+			return false
+		}
+	}
+	if !ok || post.PostedAt.After(last) {
+		c.last[k] = post.PostedAt
+		c.changed[k] = true
+	}
+	return true
+}
+
+// Changed returns the hits recorded since NewCooldowns, to be saved.
+func (c *Cooldowns) Changed() []CooldownHit {
+	if c == nil {
+		return nil
+	}
+	var out []CooldownHit
+	for k := range c.changed {
+		out = append(out, CooldownHit{Rule: k.rule, Source: k.source, Channel: k.channel, At: c.last[k]})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		return a.Channel < b.Channel
+	})
+	return out
+}
@@ -0,0 +1,94 @@
+package taste
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/source"
+)
+
+func TestScoreWithCooldowns(t *testing.T) {
+	profile := testProfile()
+	profile.Rules[0].Cooldown = config.Duration{Duration: 24 * time.Hour}
+	day := time.Date(2026, 3, 2, 6, 0, 0, 0, time.UTC)
+	botPost := func(channel string, at time.Time) source.Post {
+		return source.Post{Source: "telegram", Channel: channel, Text: "certificate expired on api.example.com", PostedAt: at}
+	}
+
+	cd := NewCooldowns(nil)
+	first := ScoreWithCooldowns(botPost("@alerts", day), profile, cd)
+	if first.Score != 4 {
+		t.Errorf("first score = %d, want 4", first.Score)
+	}
+
+	again := ScoreWithCooldowns(botPost("@alerts", day.Add(6*time.Hour)), profile, cd)
+	if again.Score != 0 {
+		t.Errorf("score within cooldown = %d, want 0", again.Score)
+	}
+	if !slices.Contains(again.Labels, "certs") {
+		t.Errorf("labels within cooldown = %v, want the rule's labels kept", again.Labels)
+	}
+	if len(again.Explanation) != 1 || again.Explanation[0].Reason != "rule: expired (cooldown)" || again.Explanation[0].Points != 0 {
+		t.Errorf("explanation = %+v", again.Explanation)
+	}
+
+	// Other channels have their own window; a new window boosts again.
+	if sp := ScoreWithCooldowns(botPost("@ops", day.Add(time.Hour)), profile, cd); sp.Score != 4 {
+		t.Errorf("other channel score = %d, want 4", sp.Score)
+	}
+	if sp := ScoreWithCooldowns(botPost("@alerts", day.Add(25*time.Hour)), profile, cd); sp.Score != 4 {
+		t.Errorf("next day score = %d, want 4", sp.Score)
+	}
+
+	changed := cd.Changed()
+	if len(changed) != 2 || changed[0].Channel != "@alerts" || !changed[0].At.Equal(day.Add(25*time.Hour)) {
+		t.Errorf("changed = %+v", changed)
+	}
+	if changed[0].Rule != "expired|certificate" {
+		t.Errorf("rule key = %q", changed[0].Rule)
+	}
+}
+
+func TestCooldowns_SeededHits(t *testing.T) {
+	profile := testProfile()
+	profile.Rules[0].Cooldown = config.Duration{Duration: 24 * time.Hour}
+	at := time.Date(2026, 3, 2, 6, 0, 0, 0, time.UTC)
+	cd := NewCooldowns([]CooldownHit{{Rule: RuleKey(profile.Rules[0]), Source: "test", Channel: "ch", At: at}})
+
+	p := post("certificate expired")
+	p.PostedAt = at.Add(-time.Hour) // backfilled, still within the window
+	if sp := ScoreWithCooldowns(p, profile, cd); sp.Score != 0 {
+		t.Errorf("score = %d, want 0 within a saved hit's window", sp.Score)
+	}
+	if len(cd.Changed()) != 0 {
+		t.Errorf("changed = %+v, want none", cd.Changed())
+	}
+
+	// Without cooldowns, rules always fire.
+	if sp := Score(p, profile); sp.Score != 4 {
+		t.Errorf("Score = %d, want 4", sp.Score)
+	}
+
+	// Rescoring the post that was the saved hit keeps its points.
+	p.PostedAt = at
+	if sp := ScoreWithCooldowns(p, profile, cd); sp.Score != 4 {
+		t.Errorf("rescored hit: score = %d, want 4", sp.Score)
+	}
+}
+
+func TestRuleKey_Conditions(t *testing.T) {
+	rule := config.Rule{If: config.RuleCondition{
+		ContainsAny:    []string{"postgres"},
+		ContainsAll:    []string{"release"},
+		NotContainsAny: []string{"webinar"},
+	}}
+	if got := RuleKey(rule); got != "postgres&release!webinar" {
+		t.Errorf("key = %q", got)
+	}
+	rule.If.ContainsAll, rule.If.NotContainsAny = nil, nil
+	if got := RuleKey(rule); got != "postgres" {
+		t.Errorf("key without new conditions = %q, want postgres", got)
+	}
+}
@@ -0,0 +1,55 @@
+package taste
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+)
+
+// Decay lowers sp's positive score by the post's age at now, halving it
+// every half-life the profile's decay gives the post's source and tier. The
+// tier follows the new score down, but never up, so a post held back by an
+// earlier step stays there; tiers pinned by a channel or author, and label
+// tier bounds, hold. Posts without a half-life, or without points, are
+// returned unchanged.
+func Decay(sp ScoredPost, profile *config.TasteProfile, now time.Time) ScoredPost {
+	if sp.Score <= 0 {
+		return sp
+	}
+	halfLife := profile.Decay.HalfLifeFor(sp.Post.Source, sp.Tier)
+	age := now.Sub(sp.Post.PostedAt)
+	if halfLife <= 0 || age <= 0 {
+		return sp
+	}
+
+	decayed := int(math.Round(float64(sp.Score) * math.Exp2(-float64(age)/float64(halfLife))))
+	if decayed == sp.Score {
+		return sp
+	}
+	sp.Explanation = append(sp.Explanation, ScoreContribution{
+		Reason: fmt.Sprintf("decay: %s old (half-life %s)", formatAge(age), formatAge(halfLife)),
+		Points: decayed - sp.Score,
+	})
+	sp.Score = decayed
+
+	if tier := pinnedTier(sp.Post, profile); tier != "" {
+		sp.forcedTier = tier
+	}
+	sp.bounds = labelBounds(sp.Labels, profile)
+	tiers := profile.TierSet()
+	if tier := sp.BoundTier(tierOf(decayed, tiers), tiers); tierRank(tier, tiers) > tierRank(sp.Tier, tiers) || sp.forcedTier != "" {
+		sp.Tier = tier
+	}
+	return sp
+}
+
+// formatAge renders d in whole days, or whole hours under two days: "3d",
+// "36h".
+func formatAge(d time.Duration) string {
+	if d >= 48*time.Hour {
+		return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	}
+	return fmt.Sprintf("%dh", int(d/time.Hour))
+}
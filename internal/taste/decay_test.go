@@ -0,0 +1,56 @@
+package taste
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/source"
+)
+
+func TestDecay(t *testing.T) {
+	now := time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC)
+	profile := testProfile()
+	profile.Decay = config.DecayConfig{
+		HalfLife: config.Duration{Duration: 48 * time.Hour},
+		Tiers:    map[string]config.Duration{TierSkim: {Duration: 24 * time.Hour}},
+		Sources:  map[string]config.Duration{"github": {}},
+	}
+	scored := func(src string, score int, tier string, age time.Duration) ScoredPost {
+		return ScoredPost{Post: source.Post{Source: src, Channel: "ch", PostedAt: now.Add(-age)}, Score: score, Tier: tier}
+	}
+
+	// A three-day-old skim post halves three times and drops out.
+	got := Decay(scored("rss", 6, TierSkim, 72*time.Hour), profile, now)
+	if got.Score != 1 || got.Tier != TierIgnore {
+		t.Errorf("old skim = score %d tier %s, want 1 ignore", got.Score, got.Tier)
+	}
+	if len(got.Explanation) != 1 || got.Explanation[0].Reason != "decay: 3d old (half-life 24h)" || got.Explanation[0].Points != -5 {
+		t.Errorf("explanation = %+v", got.Explanation)
+	}
+
+	// The same post fresh keeps its score.
+	if got := Decay(scored("rss", 6, TierSkim, 10*time.Minute), profile, now); got.Score != 6 || got.Tier != TierSkim {
+		t.Errorf("fresh skim = score %d tier %s, want 6 skim", got.Score, got.Tier)
+	}
+	// read_now uses the default half-life.
+	if got := Decay(scored("rss", 10, TierReadNow, 48*time.Hour), profile, now); got.Score != 5 || got.Tier != TierSkim {
+		t.Errorf("read_now = score %d tier %s, want 5 skim", got.Score, got.Tier)
+	}
+	// A zero source half-life turns decay off; so do scores without points.
+	if got := Decay(scored("github", 10, TierReadNow, 30*24*time.Hour), profile, now); got.Score != 10 {
+		t.Errorf("github score = %d, want 10", got.Score)
+	}
+	if got := Decay(scored("rss", -4, TierIgnore, 30*24*time.Hour), profile, now); got.Score != -4 {
+		t.Errorf("negative score = %d, want -4", got.Score)
+	}
+
+	// Tiers never rise, and pinned ones hold.
+	if got := Decay(scored("rss", 9, TierIgnore, 24*time.Hour), profile, now); got.Tier != TierIgnore {
+		t.Errorf("suppressed post tier = %s, want ignore", got.Tier)
+	}
+	profile.Channels = map[string]config.ScoreModifier{"rss/ch": {Tier: TierReadNow}}
+	if got := Decay(scored("rss", 10, TierReadNow, 96*time.Hour), profile, now); got.Score != 3 || got.Tier != TierReadNow {
+		t.Errorf("pinned = score %d tier %s, want 3 read_now", got.Score, got.Tier)
+	}
+}
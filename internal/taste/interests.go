@@ -0,0 +1,51 @@
+package taste
+
+import (
+	"fmt"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/embed"
+)
+
+// Interests scores posts by meaning: each interest of the profile, embedded
+// once, adds its points to posts whose embedding is close enough to it.
+type Interests struct {
+	interests []config.Interest
+	vectors   []embed.Vector // by index into interests
+}
+
+// NewInterests pairs the profile's interests with their embeddings, given
+// in the same order.
+func NewInterests(interests []config.Interest, vectors []embed.Vector) (*Interests, error) {
+	if len(interests) != len(vectors) {
+		return nil, fmt.Errorf("%d interests but %d embeddings", len(interests), len(vectors))
+	}
+	return &Interests{interests: interests, vectors: vectors}, nil
+}
+
+// Apply adds the points of every interest post's embedding reaches and
+// recomputes the tier from the new score, within what the post allows (see
+// ScoredPost.BoundTier). A post without an embedding, or a nil Interests, is
+// returned unchanged.
+func (in *Interests) Apply(sp ScoredPost, post embed.Vector, tiers []config.Tier) ScoredPost {
+	if in == nil || len(post) == 0 {
+		return sp
+	}
+	changed := false
+	for i, interest := range in.interests {
+		sim := embed.Cosine(post, in.vectors[i])
+		if sim < interest.MinSimilarity {
+			continue
+		}
+		sp.Score += interest.Points
+		sp.Explanation = append(sp.Explanation, ScoreContribution{
+			Reason: fmt.Sprintf("interest: %s (%.2f)", interest.Description, sim),
+			Points: interest.Points,
+		})
+		changed = true
+	}
+	if changed {
+		sp.Tier = sp.BoundTier(tierOf(sp.Score, tiers), tiers)
+	}
+	return sp
+}
@@ -0,0 +1,49 @@
+package taste
+
+import (
+	"testing"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/embed"
+	"github.com/ppiankov/noisepan/internal/source"
+)
+
+func TestInterestsApply(t *testing.T) {
+	profile := &config.TasteProfile{Thresholds: config.Thresholds{ReadNow: 5, Skim: 2, Ignore: 0}}
+	in, err := NewInterests([]config.Interest{
+		{Description: "postgres internals", Points: 3, MinSimilarity: 0.8},
+		{Description: "crypto prices", Points: -2, MinSimilarity: 0.8},
+	}, []embed.Vector{{1, 0}, {0, 1}})
+	if err != nil {
+		t.Fatalf("NewInterests: %v", err)
+	}
+
+	// The post uses no keyword but reads like the first interest.
+	sp := Score(source.Post{Text: "How the WAL writer batches fsyncs"}, profile)
+	got := in.Apply(sp, embed.Vector{0.95, 0.1}, profile.TierSet())
+	if got.Score != 3 || got.Tier != TierSkim {
+		t.Errorf("score %d tier %s, want 3 skim", got.Score, got.Tier)
+	}
+	if len(got.Explanation) != 1 || got.Explanation[0].Reason != "interest: postgres internals (0.99)" || got.Explanation[0].Points != 3 {
+		t.Errorf("explanation = %+v", got.Explanation)
+	}
+
+	// Negative interests push a post down.
+	got = in.Apply(ScoredPost{Score: 2, Tier: TierSkim}, embed.Vector{0, 1}, profile.TierSet())
+	if got.Score != 0 || got.Tier != TierIgnore {
+		t.Errorf("score %d tier %s, want 0 ignore", got.Score, got.Tier)
+	}
+
+	// Without an embedding, or interests, nothing changes.
+	if got := in.Apply(sp, nil, profile.TierSet()); got.Score != 0 || len(got.Explanation) != 0 {
+		t.Errorf("no embedding changed the score: %+v", got)
+	}
+	var none *Interests
+	if got := none.Apply(sp, embed.Vector{1, 0}, profile.TierSet()); got.Score != 0 {
+		t.Errorf("nil interests changed the score: %+v", got)
+	}
+
+	if _, err := NewInterests([]config.Interest{{Description: "x"}}, nil); err == nil {
+		t.Error("expected error for missing embeddings")
+	}
+}
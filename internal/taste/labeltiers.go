@@ -0,0 +1,75 @@
+package taste
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/ppiankov/noisepan/internal/config"
+)
+
+// tierBounds is the tier range a post's labels allow, with the labels that
+// set each end. Empty ends do not bound.
+type tierBounds struct {
+	min, minLabel string
+	max, maxLabel string
+}
+
+// labelBounds combines the label_tiers of labels: the highest min_tier and
+// the lowest max_tier. When they cross, min_tier wins, so a critical post is
+// never held back by another label.
+func labelBounds(labels []string, profile *config.TasteProfile) tierBounds {
+	var b tierBounds
+	tiers := profile.TierSet()
+	for _, label := range labels {
+		lt, ok := profile.LabelTiers[label]
+		if !ok {
+			continue
+		}
+		if lt.MinTier != "" && (b.min == "" || tierRank(lt.MinTier, tiers) < tierRank(b.min, tiers)) {
+			b.min, b.minLabel = lt.MinTier, label
+		}
+		if lt.MaxTier != "" && (b.max == "" || tierRank(lt.MaxTier, tiers) > tierRank(b.max, tiers)) {
+			b.max, b.maxLabel = lt.MaxTier, label
+		}
+	}
+	return b
+}
+
+// RebindLabels re-applies label_tiers after labels were added to sp past
+// scoring, such as by a hook: it moves sp's tier into the range all its
+// labels allow, unless a channel or author modifier pinned it.
+func RebindLabels(sp ScoredPost, profile *config.TasteProfile) ScoredPost {
+	sp.bounds = labelBounds(sp.Labels, profile)
+	if sp.forcedTier != "" {
+		return sp
+	}
+	if tier := sp.bounds.apply(sp.Tier, profile.TierSet()); tier != sp.Tier {
+		sp.Tier = tier
+		sp.Explanation = append(sp.Explanation, ScoreContribution{Reason: sp.bounds.reason(tier)})
+	}
+	return sp
+}
+
+// apply moves tier into the bounds.
+func (b tierBounds) apply(tier string, tiers []config.Tier) string {
+	if b.max != "" && tierRank(tier, tiers) < tierRank(b.max, tiers) {
+		tier = b.max
+	}
+	if b.min != "" && tierRank(tier, tiers) > tierRank(b.min, tiers) {
+		tier = b.min
+	}
+	return tier
+}
+
+// reason explains a tier the bounds moved a post to.
+func (b tierBounds) reason(tier string) string {
+	if tier == b.min {
+		return fmt.Sprintf("label: %s (at least %s)", b.minLabel, b.min)
+	}
+	return fmt.Sprintf("label: %s (at most %s)", b.maxLabel, b.max)
+}
+
+// tierRank is the position of tier in tiers, highest first, or -1.
+func tierRank(tier string, tiers []config.Tier) int {
+	return slices.IndexFunc(tiers, func(t config.Tier) bool { return t.Name == tier })
+}
@@ -0,0 +1,84 @@
+package taste
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ppiankov/noisepan/internal/config"
+)
+
+func TestScore_LabelTiers(t *testing.T) {
+	profile := testProfile()
+	profile.Rules = append(profile.Rules, config.Rule{
+		If:   config.RuleCondition{ContainsAny: []string{"advisory"}},
+		Then: config.RuleAction{Labels: []string{"critical"}},
+	})
+	profile.LabelTiers = map[string]config.LabelTier{
+		"critical": {MinTier: TierReadNow},
+		"noise":    {MaxTier: TierSkim},
+	}
+
+	// No points, but the critical label lifts the post to read_now.
+	got := Score(post("security advisory published"), profile)
+	if got.Score != 0 || got.Tier != TierReadNow {
+		t.Errorf("critical = score %d tier %s, want 0 read_now", got.Score, got.Tier)
+	}
+	if last := got.Explanation[len(got.Explanation)-1]; last.Reason != "label: critical (at least read_now)" || last.Points != 0 {
+		t.Errorf("explanation = %+v", got.Explanation)
+	}
+
+	// A high-scoring noise post is held at skim.
+	profile.Weights.HighSignal["kubernetes"] = 15
+	got = Score(post("join us: kubernetes at scale"), profile)
+	if got.Score != 9 || got.Tier != TierSkim {
+		t.Errorf("noise = score %d tier %s, want 9 skim", got.Score, got.Tier)
+	}
+	if last := got.Explanation[len(got.Explanation)-1]; last.Reason != "label: noise (at most skim)" {
+		t.Errorf("explanation = %+v", got.Explanation)
+	}
+
+	// Both labels: the floor wins.
+	if got := Score(post("join us for the advisory briefing"), profile); got.Tier != TierReadNow {
+		t.Errorf("critical noise tier = %s, want read_now", got.Tier)
+	}
+
+	// Later stages keep the bounds.
+	sp := Score(post("security advisory published"), profile)
+	clf, err := Train([]Example{
+		{Text: "security advisory published", Positive: false},
+		{Text: "postgres release", Positive: true},
+	})
+	if err != nil {
+		t.Fatalf("train: %v", err)
+	}
+	if got := clf.Apply(sp, 3, profile.TierSet()); got.Score >= 0 || got.Tier != TierReadNow {
+		t.Errorf("after classifier = score %d tier %s, want negative read_now", got.Score, got.Tier)
+	}
+	profile.Decay.HalfLife = config.Duration{Duration: time.Hour}
+	noisy := Score(post("join us: kubernetes at scale"), profile)
+	noisy.Tier = TierReadNow // an earlier version of the profile
+	if got := Decay(noisy, profile, noisy.Post.PostedAt.Add(30*time.Minute)); got.Tier != TierSkim {
+		t.Errorf("after decay tier = %s, want skim", got.Tier)
+	}
+}
+
+func TestRebindLabels(t *testing.T) {
+	profile := testProfile()
+	profile.LabelTiers = map[string]config.LabelTier{"critical": {MinTier: TierReadNow}}
+
+	sp := Score(post("nothing of note"), profile)
+	if sp.Tier != TierIgnore {
+		t.Fatalf("tier = %s, want ignore", sp.Tier)
+	}
+	sp.Labels = append(sp.Labels, "critical") // e.g. from a hook
+	got := RebindLabels(sp, profile)
+	if got.Tier != TierReadNow {
+		t.Errorf("rebound tier = %s, want read_now", got.Tier)
+	}
+	if last := got.Explanation[len(got.Explanation)-1]; last.Reason != "label: critical (at least read_now)" {
+		t.Errorf("explanation = %+v", got.Explanation)
+	}
+	if again := RebindLabels(got, profile); len(again.Explanation) != len(got.Explanation) {
+		t.Errorf("rebinding twice added %+v", again.Explanation)
+	}
+}
@@ -0,0 +1,94 @@
+package taste
+
+import (
+	"strings"
+	"unicode"
+)
+
+// scriptLanguages names the language of scripts written mostly in one.
+var scriptLanguages = map[string]string{
+	"Greek":      "el",
+	"Arabic":     "ar",
+	"Hebrew":     "he",
+	"Hangul":     "ko",
+	"Devanagari": "hi",
+	"Thai":       "th",
+	"Georgian":   "ka",
+	"Armenian":   "hy",
+}
+
+// stopwords are frequent short words that tell Latin-script languages
+// apart. A language needs two of them in a text to be detected.
+var stopwords = map[string][]string{
+	"en": {"the", "and", "is", "are", "of", "to", "for", "with", "this", "that", "it", "was", "on", "have", "not", "you", "from"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "mit", "ein", "eine", "für", "auf", "den", "von", "zu", "sich", "auch"},
+	"fr": {"le", "la", "les", "et", "est", "une", "des", "du", "pour", "dans", "pas", "qui", "sur", "avec", "ce", "il"},
+	"es": {"el", "los", "las", "y", "es", "una", "del", "para", "por", "con", "que", "se", "está", "como"},
+	"it": {"il", "lo", "gli", "e", "è", "una", "della", "per", "con", "che", "non", "sono", "di", "anche"},
+	"pt": {"o", "os", "um", "uma", "do", "da", "dos", "para", "com", "que", "não", "em", "é", "mais"},
+}
+
+// stopwordLanguages maps each stopword to the languages listing it.
+var stopwordLanguages = func() map[string][]string {
+	m := make(map[string][]string)
+	for lang, words := range stopwords {
+		for _, w := range words {
+			m[w] = append(m[w], lang)
+		}
+	}
+	return m
+}()
+
+// DetectLanguage returns the ISO 639-1 code of the language text is most
+// likely written in, or "" when it cannot tell. The script decides for
+// most languages; Cyrillic is Russian unless Ukrainian or Belarusian
+// letters show, and Latin-script text is told apart by stopwords (English,
+// German, French, Spanish, Italian, Portuguese), so a short headline may
+// come back "".
+func DetectLanguage(text string) string {
+	switch script := DominantScript(text); script {
+	case "":
+		return ""
+	case "Cyrillic":
+		switch {
+		case strings.ContainsAny(text, "іїєґІЇЄҐ"):
+			return "uk"
+		case strings.ContainsAny(text, "ўЎ"):
+			return "be"
+		}
+		return "ru"
+	case "Han", "Kana":
+		if strings.IndexFunc(text, func(r rune) bool { return unicode.In(r, unicode.Hiragana, unicode.Katakana) }) >= 0 {
+			return "ja"
+		}
+		return "zh"
+	case "Latin":
+		return latinLanguage(text)
+	default:
+		return scriptLanguages[script]
+	}
+}
+
+// latinLanguage picks the language whose stopwords text uses most, if it
+// uses at least two and more than any other language's.
+func latinLanguage(text string) string {
+	counts := make(map[string]int)
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		for _, lang := range stopwordLanguages[w] {
+			counts[lang]++
+		}
+	}
+	best, bestN, tie := "", 0, false
+	for lang, n := range counts {
+		switch {
+		case n > bestN:
+			best, bestN, tie = lang, n, false
+		case n == bestN:
+			tie = true
+		}
+	}
+	if bestN < 2 || tie {
+		return ""
+	}
+	return best
+}
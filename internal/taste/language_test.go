@@ -0,0 +1,24 @@
+package taste
+
+import "testing"
+
+func TestDetectLanguage(t *testing.T) {
+	for text, want := range map[string]string{
+		"Вышел новый релиз Kubernetes с исправлением уязвимости":        "ru",
+		"Вийшов новий реліз, він виправляє вразливість":                 "uk",
+		"The new release of Kubernetes is out, with a fix for the CVE":  "en",
+		"Die neue Version ist da und das Update behebt eine Lücke":      "de",
+		"La nouvelle version est là et elle corrige une faille des API": "fr",
+		"La nueva versión está aquí y corrige un fallo de los API":      "es",
+		"新しいリリースが公開されました":                                               "ja",
+		"新版本已经发布":         "zh",
+		"새로운 릴리스":         "ko",
+		"Kubernetes 1.33": "",
+		"":                "",
+		"12345 !!!":       "",
+	} {
+		if got := DetectLanguage(text); got != want {
+			t.Errorf("DetectLanguage(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
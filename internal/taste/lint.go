@@ -0,0 +1,254 @@
+package taste
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ppiankov/noisepan/internal/config"
+)
+
+// Lint issue severities: an error is a part of the profile that cannot work
+// as written, a warning one that likely does not do what was meant.
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+)
+
+// LintIssue is one problem Lint found, at a yaml path of the profile.
+type LintIssue struct {
+	Severity string `json:"severity"`
+	Path     string `json:"path"` // "rules[2]", "weights.low_signal.cve"
+	Message  string `json:"message"`
+}
+
+// regexLike matches terms written as regular expressions. Keywords and rule
+// terms match as plain substrings, so such a term rarely matches anything.
+var regexLike = regexp.MustCompile(`\.\*|\.\+|\\[bdswBDSW]|^\^|\$$|\[[^\]]*-[^\]]*\]|\(\?|\|`)
+
+// Lint checks a validated profile for parts that cannot fire or conflict:
+// keywords in both weight sections, rules that never match, labels used but
+// not defined under labels, regex-looking terms, and tiers no score reaches;
+// languages' weights and rules included.
+// Issues come in profile order.
+func Lint(profile *config.TasteProfile) []LintIssue {
+	var issues []LintIssue
+	add := func(severity, path, format string, args ...any) {
+		issues = append(issues, LintIssue{Severity: severity, Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+	checkTerm := func(path, term string) {
+		if regexLike.MatchString(term) {
+			add(LintWarning, path, "%q looks like a regular expression, but terms match literally", term)
+		}
+	}
+	checkLabels := func(path string, labels []string) {
+		for _, l := range labels {
+			if _, ok := profile.Labels[l]; !ok {
+				add(LintWarning, path, "label %q is not defined under labels", l)
+			}
+		}
+	}
+
+	// Terms differing in case are the same term unless either matches case.
+	sameTerm := func(a, b string) bool {
+		return a == b || (strings.EqualFold(a, b) && !profile.Match.For(a).CaseSensitive && !profile.Match.For(b).CaseSensitive)
+	}
+
+	// Weights and rules are checked for the profile and then for each of its
+	// languages, whose paths start with prefix.
+	lintWeights := func(prefix string, weights config.Weights) {
+		high, low := weights.HighSignal, weights.LowSignal
+		for _, sec := range []struct {
+			name    string
+			weights map[string]int
+			other   map[string]int
+		}{{"high_signal", high, low}, {"low_signal", low, high}} {
+			var seen []string
+			for _, kw := range slices.Sorted(maps.Keys(sec.weights)) {
+				path := prefix + "weights." + sec.name + "." + kw
+				w := sec.weights[kw]
+				if i := slices.IndexFunc(seen, func(prev string) bool { return sameTerm(prev, kw) }); i >= 0 {
+					add(LintWarning, path, "duplicates %q (keywords ignore case); both apply", seen[i])
+				}
+				seen = append(seen, kw)
+				if sec.name == "high_signal" {
+					for _, other := range slices.Sorted(maps.Keys(sec.other)) {
+						if sameTerm(kw, other) {
+							add(LintError, path, "also in low_signal as %q (%+d); both apply, netting %+d", other, sec.other[other], w+sec.other[other])
+						}
+					}
+				}
+				switch {
+				case w == 0:
+					add(LintWarning, path, "weight 0 has no effect")
+				case sec.name == "high_signal" && w < 0:
+					add(LintWarning, path, "negative weight %d in high_signal", w)
+				case sec.name == "low_signal" && w > 0:
+					add(LintWarning, path, "positive weight %+d in low_signal", w)
+				}
+				checkTerm(path, kw)
+			}
+		}
+	}
+	lintWeights("", profile.Weights)
+
+	for _, label := range slices.Sorted(maps.Keys(profile.Labels)) {
+		if len(profile.Labels[label]) == 0 {
+			add(LintWarning, "labels."+label, "no keywords")
+		}
+		for _, term := range profile.Labels[label] {
+			checkTerm("labels."+label, term)
+		}
+	}
+
+	added := make(map[string]bool)
+	lintRules := func(prefix string, rules []config.Rule) {
+		conditions := make(map[string]int)
+		for i, r := range rules {
+			path := fmt.Sprintf("%srules[%d]", prefix, i)
+			if reason := unreachable(r.If); reason != "" {
+				add(LintError, path, "never fires: %s", reason)
+			}
+			if r.Then.ScoreAdd == 0 && len(r.Then.Labels) == 0 {
+				add(LintWarning, path+".then", "adds no points and no labels")
+			}
+			key := ruleConditionKey(r.If)
+			if j, ok := conditions[key]; ok {
+				add(LintWarning, path+".if", "same condition as %srules[%d]; both apply", prefix, j)
+			} else {
+				conditions[key] = i
+			}
+			for _, term := range append(r.If.Terms(), r.If.NotContainsAny...) {
+				checkTerm(path+".if", term)
+			}
+			checkLabels(path+".then.labels", r.Then.Labels)
+			for _, l := range r.Then.Labels {
+				added[l] = true
+			}
+		}
+	}
+	lintRules("", profile.Rules)
+	for _, mods := range []struct {
+		name string
+		m    map[string]config.ScoreModifier
+	}{{"channels", profile.Channels}, {"authors", profile.Authors}} {
+		for _, key := range slices.Sorted(maps.Keys(mods.m)) {
+			checkLabels(mods.name+"."+key+".labels", mods.m[key].Labels)
+			for _, l := range mods.m[key].Labels {
+				added[l] = true
+			}
+		}
+	}
+	for _, lang := range slices.Sorted(maps.Keys(profile.Languages)) {
+		lp := profile.Languages[lang]
+		lintWeights("languages."+lang+".", lp.Weights)
+		lintRules("languages."+lang+".", lp.Rules)
+	}
+	for _, label := range slices.Sorted(maps.Keys(profile.LabelTiers)) {
+		if !added[label] {
+			add(LintWarning, "label_tiers."+label, "no rule or modifier adds label %q, so the bound never applies", label)
+		}
+	}
+
+	issues = append(issues, lintTiers(profile)...)
+	return issues
+}
+
+// unreachable returns why a rule condition can never match, or "".
+func unreachable(cond config.RuleCondition) string {
+	if len(cond.Terms()) == 0 && len(cond.HasEntity) == 0 {
+		return "no contains_any, contains_all, or has_entity"
+	}
+	// A post containing a term also contains every not_contains_any term
+	// that is part of it.
+	excludedBy := func(term string) string {
+		for _, not := range cond.NotContainsAny {
+			if strings.Contains(strings.ToLower(term), strings.ToLower(not)) {
+				return not
+			}
+		}
+		return ""
+	}
+	for _, term := range cond.ContainsAll {
+		if not := excludedBy(term); not != "" {
+			return fmt.Sprintf("contains_all %q always contains not_contains_any %q", term, not)
+		}
+	}
+	if len(cond.ContainsAny) > 0 && !slices.ContainsFunc(cond.ContainsAny, func(term string) bool { return excludedBy(term) == "" }) {
+		return "every contains_any term contains a not_contains_any term"
+	}
+	return ""
+}
+
+// ruleConditionKey identifies a rule condition whatever its term order or
+// case.
+func ruleConditionKey(cond config.RuleCondition) string {
+	norm := func(terms []string) string {
+		lower := make([]string, len(terms))
+		for i, t := range terms {
+			lower[i] = strings.ToLower(t)
+		}
+		slices.Sort(lower)
+		return strings.Join(slices.Compact(lower), "\x00")
+	}
+	return norm(cond.ContainsAny) + "\x01" + norm(cond.ContainsAll) + "\x01" + norm(cond.NotContainsAny) + "\x01" + norm(cond.HasEntity)
+}
+
+// lintTiers reports tiers no score reaches and a default tier other than
+// ignore for posts nothing matched.
+func lintTiers(profile *config.TasteProfile) []LintIssue {
+	// The highest score a post can reach: every positive keyword, rule,
+	// modifier, and interest at once, plus the classifier's maximum. A post
+	// has one language, so only the best language's terms add up.
+	setBest := func(weights config.Weights, rules []config.Rule) int {
+		n := 0
+		for _, ws := range []map[string]int{weights.HighSignal, weights.LowSignal} {
+			for _, w := range ws {
+				n += max(w, 0)
+			}
+		}
+		for _, r := range rules {
+			n += max(r.Then.ScoreAdd, 0)
+		}
+		return n
+	}
+	best, langBest := setBest(profile.Weights, profile.Rules), 0
+	for _, lp := range profile.Languages {
+		langBest = max(langBest, setBest(lp.Weights, lp.Rules))
+	}
+	best += langBest
+	for _, mods := range []map[string]config.ScoreModifier{profile.Channels, profile.Authors} {
+		for _, m := range mods {
+			best += max(m.ScoreAdd, 0)
+		}
+	}
+	for _, in := range profile.Interests {
+		best += max(in.Points, 0)
+	}
+	if profile.Classifier.Enabled {
+		best += profile.Classifier.MaxPoints
+	}
+
+	var issues []LintIssue
+	tiers := profile.TierSet()
+	path := func(i int) string {
+		if len(profile.Tiers) > 0 {
+			return fmt.Sprintf("tiers[%d].min_score", i)
+		}
+		return "thresholds." + tiers[i].Name
+	}
+	for i, t := range tiers[:len(tiers)-1] {
+		if t.MinScore > best {
+			issues = append(issues, LintIssue{Severity: LintError, Path: path(i),
+				Message: fmt.Sprintf("%s needs %d, above the highest score a post can reach (%d)", t.Name, t.MinScore, best)})
+		}
+	}
+	if tier := tierOf(0, tiers); tier != tiers[len(tiers)-1].Name {
+		i := slices.IndexFunc(tiers, func(t config.Tier) bool { return t.Name == tier })
+		issues = append(issues, LintIssue{Severity: LintWarning, Path: path(i),
+			Message: fmt.Sprintf("a post nothing matches scores 0 and lands in %s", tier)})
+	}
+	return issues
+}
@@ -0,0 +1,118 @@
+package taste
+
+import (
+	"testing"
+
+	"github.com/ppiankov/noisepan/internal/config"
+)
+
+func TestLint(t *testing.T) {
+	profile := &config.TasteProfile{
+		Weights: config.Weights{
+			HighSignal: map[string]int{"cve": 5, "k8s.*release": 3},
+			LowSignal:  map[string]int{"cve": -4, "Webinar": -2, "webinar": 1},
+		},
+		Labels: map[string][]string{"critical": {"cve"}},
+		Rules: []config.Rule{
+			{If: config.RuleCondition{ContainsAll: []string{"kubernetes cve"}, NotContainsAny: []string{"cve"}},
+				Then: config.RuleAction{ScoreAdd: 2}},
+			{If: config.RuleCondition{ContainsAny: []string{"outage"}}, Then: config.RuleAction{Labels: []string{"incident"}}},
+			{If: config.RuleCondition{ContainsAny: []string{"Outage"}}, Then: config.RuleAction{ScoreAdd: 1}},
+			{If: config.RuleCondition{ContainsAny: []string{"webinar"}}},
+		},
+		LabelTiers: map[string]config.LabelTier{"critical": {MinTier: TierReadNow}},
+		Thresholds: config.Thresholds{ReadNow: 20, Skim: 0, Ignore: -1},
+	}
+
+	want := []LintIssue{
+		{LintError, "weights.high_signal.cve", `also in low_signal as "cve" (-4); both apply, netting +1`},
+		{LintWarning, "weights.high_signal.k8s.*release", `"k8s.*release" looks like a regular expression, but terms match literally`},
+		{LintWarning, "weights.low_signal.webinar", `duplicates "Webinar" (keywords ignore case); both apply`},
+		{LintWarning, "weights.low_signal.webinar", "positive weight +1 in low_signal"},
+		{LintError, "rules[0]", `never fires: contains_all "kubernetes cve" always contains not_contains_any "cve"`},
+		{LintWarning, "rules[1].then.labels", `label "incident" is not defined under labels`},
+		{LintWarning, "rules[2].if", "same condition as rules[1]; both apply"},
+		{LintWarning, "rules[3].then", "adds no points and no labels"},
+		{LintWarning, "label_tiers.critical", `no rule or modifier adds label "critical", so the bound never applies`},
+		{LintError, "thresholds.read_now", "read_now needs 20, above the highest score a post can reach (12)"},
+		{LintWarning, "thresholds.skim", "a post nothing matches scores 0 and lands in skim"},
+	}
+	got := Lint(profile)
+	if len(got) != len(want) {
+		t.Fatalf("got %d issues, want %d:\n%+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("issue %d = %+v\nwant %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLint_Clean(t *testing.T) {
+	profile := &config.TasteProfile{
+		Weights:    config.Weights{HighSignal: map[string]int{"cve": 5}, LowSignal: map[string]int{"webinar": -4}},
+		Labels:     map[string][]string{"critical": {"cve"}},
+		Rules:      []config.Rule{{If: config.RuleCondition{ContainsAny: []string{"rce"}}, Then: config.RuleAction{ScoreAdd: 3, Labels: []string{"critical"}}}},
+		Thresholds: config.Thresholds{ReadNow: 7, Skim: 3, Ignore: 0},
+	}
+	if got := Lint(profile); len(got) != 0 {
+		t.Errorf("issues = %+v, want none", got)
+	}
+}
+
+func TestLint_Languages(t *testing.T) {
+	profile := &config.TasteProfile{
+		Weights: config.Weights{HighSignal: map[string]int{"cve": 2}},
+		Labels:  map[string][]string{"critical": {"cve"}},
+		Languages: map[string]config.LanguageProfile{
+			"ru": {
+				Weights: config.Weights{HighSignal: map[string]int{"уязвимость": 6, "патч.*": 1}},
+				Rules: []config.Rule{
+					{If: config.RuleCondition{ContainsAny: []string{"эксплойт"}}, Then: config.RuleAction{ScoreAdd: 1, Labels: []string{"critical"}}},
+					{If: config.RuleCondition{ContainsAll: []string{"сбой"}, NotContainsAny: []string{"сбой"}}, Then: config.RuleAction{Labels: []string{"outage"}}},
+				},
+			},
+			"de": {Weights: config.Weights{HighSignal: map[string]int{"sicherheitslücke": 3}}},
+		},
+		LabelTiers: map[string]config.LabelTier{"critical": {MinTier: TierReadNow}},
+		Thresholds: config.Thresholds{ReadNow: 9, Skim: 3, Ignore: 0},
+	}
+
+	// read_now is reachable only through languages.ru (2 + 6 + 1 + 1), and
+	// its rule adds the bounded label.
+	want := []LintIssue{
+		{LintWarning, "languages.ru.weights.high_signal.патч.*", `"патч.*" looks like a regular expression, but terms match literally`},
+		{LintError, "languages.ru.rules[1]", `never fires: contains_all "сбой" always contains not_contains_any "сбой"`},
+		{LintWarning, "languages.ru.rules[1].then.labels", `label "outage" is not defined under labels`},
+	}
+	got := Lint(profile)
+	if len(got) != len(want) {
+		t.Fatalf("got %d issues, want %d:\n%+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("issue %d = %+v\nwant %+v", i, got[i], want[i])
+		}
+	}
+
+	profile.Thresholds.ReadNow = 11
+	if got := Lint(profile); len(got) != 4 || got[3].Message != "read_now needs 11, above the highest score a post can reach (10)" {
+		t.Errorf("unreachable read_now: issues = %+v", got)
+	}
+}
+
+func TestUnreachable(t *testing.T) {
+	for _, tc := range []struct {
+		cond      config.RuleCondition
+		reachable bool
+	}{
+		{config.RuleCondition{}, false},
+		{config.RuleCondition{ContainsAny: []string{"cve", "rce"}, NotContainsAny: []string{"CVE"}}, true},
+		{config.RuleCondition{ContainsAny: []string{"cve-2026", "CVE"}, NotContainsAny: []string{"cve"}}, false},
+		{config.RuleCondition{ContainsAll: []string{"helm"}, NotContainsAny: []string{"helm chart"}}, true},
+	} {
+		if got := unreachable(tc.cond) == ""; got != tc.reachable {
+			t.Errorf("unreachable(%+v) = %q, want reachable %v", tc.cond, unreachable(tc.cond), tc.reachable)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package taste
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ppiankov/noisepan/internal/config"
+	"github.com/ppiankov/noisepan/internal/entities"
+)
+
+// matchText is a post's text prepared for matching taste terms. Stemmed
+// words are split out on first use, as written and lowercased.
+type matchText struct {
+	raw, lower string
+	words      [2][]word // [0] lowercased, [1] as written
+	split      [2]bool
+	runes      []rune // raw, for match text; split on first use
+
+	products map[string][]string // dictionary for has_entity product and vendor
+	found    *entities.Set       // extracted on first use
+}
+
+// word is a stemmed word of a text, at byte offsets into it.
+type word struct {
+	stem       string
+	start, end int
+}
+
+func newMatchText(text string) *matchText {
+	return &matchText{raw: text, lower: strings.ToLower(text)}
+}
+
+// entitySet returns the entities of the text, with products as the
+// dictionary.
+func (t *matchText) entitySet() entities.Set {
+	if t.found == nil {
+		s := entities.Extract(t.raw, t.products)
+		t.found = &s
+	}
+	return *t.found
+}
+
+// contains reports whether term occurs in the text as opt says.
+func (t *matchText) contains(term string, opt config.KeywordMatch) bool {
+	return t.find(term, opt) != nil
+}
+
+// find returns where term first occurs in the text as opt says, or nil.
+func (t *matchText) find(term string, opt config.KeywordMatch) *TextMatch {
+	i, text := 0, t.lower
+	if opt.CaseSensitive {
+		i, text = 1, t.raw
+	} else {
+		term = strings.ToLower(term)
+	}
+	var start, end int
+	switch {
+	case opt.Stem:
+		start, end = t.findStems(i, text, term)
+	case opt.WordBoundary:
+		start = indexWord(text, term)
+		end = start + len(term)
+	default:
+		start = strings.Index(text, term)
+		end = start + len(term)
+	}
+	if start < 0 {
+		return nil
+	}
+	return t.span(text, start, end)
+}
+
+// findStems returns the byte offsets in text of the stemmed words of term
+// occurring in a row in the stemmed words of the text, or -1, -1.
+func (t *matchText) findStems(i int, text, term string) (int, int) {
+	if !t.split[i] {
+		t.words[i], t.split[i] = splitWords(text), true
+	}
+	want := stemWords(term)
+	if len(want) == 0 {
+		return -1, -1
+	}
+	hay := t.words[i]
+	for start := 0; start+len(want) <= len(hay); start++ {
+		if slices.EqualFunc(hay[start:start+len(want)], want, func(w word, s string) bool { return w.stem == s }) {
+			return hay[start].start, hay[start+len(want)-1].end
+		}
+	}
+	return -1, -1
+}
+
+// span turns byte offsets into text, the raw or lowercased text, into a
+// match of the raw text. Lowercasing keeps the number of runes, so rune
+// offsets agree between the two.
+func (t *matchText) span(text string, start, end int) *TextMatch {
+	if t.runes == nil {
+		t.runes = []rune(t.raw)
+	}
+	rs := utf8.RuneCountInString(text[:start])
+	re := rs + utf8.RuneCountInString(text[start:end])
+	return &TextMatch{Text: string(t.runes[rs:re]), Start: rs, End: re}
+}
+
+// indexWord returns the byte index of the first occurrence of term in text
+// with no letter or digit right before or after it, where the term itself
+// starts or ends with one, the way \b works in a regular expression; or -1.
+func indexWord(text, term string) int {
+	if term == "" {
+		return -1
+	}
+	first, _ := utf8.DecodeRuneInString(term)
+	last, _ := utf8.DecodeLastRuneInString(term)
+	for from := 0; from <= len(text)-len(term); {
+		i := strings.Index(text[from:], term)
+		if i < 0 {
+			return -1
+		}
+		start, end := from+i, from+i+len(term)
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if (start == 0 || !isWordRune(first) || !isWordRune(before)) &&
+			(end == len(text) || !isWordRune(last) || !isWordRune(after)) {
+			return start
+		}
+		_, size := utf8.DecodeRuneInString(text[start:])
+		from = start + size
+	}
+	return -1
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// splitWords splits text into words and stems each.
+func splitWords(text string) []word {
+	var words []word
+	start := -1
+	for i, r := range text {
+		switch {
+		case isWordRune(r) && start < 0:
+			start = i
+		case !isWordRune(r) && start >= 0:
+			words = append(words, word{stem(text[start:i]), start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, word{stem(text[start:]), start, len(text)})
+	}
+	return words
+}
+
+// stemWords returns the stems of the words of text.
+func stemWords(text string) []string {
+	words := splitWords(text)
+	stems := make([]string, len(words))
+	for i, w := range words {
+		stems[i] = w.stem
+	}
+	return stems
+}
+
+// stem strips common English inflections from a word so its forms compare
+// equal: "deploys", "deployed", and "deploying" all become "deploy", and
+// "releases" and "release" become "releas". Words of three letters or fewer
+// are kept as they are, and -ing and -ed are only cut from a stem of four
+// letters or more, so "string" and "speed" stay whole. It is deliberately
+// small: a keyword should catch its plural and tenses, no more.
+func stem(w string) string {
+	if utf8.RuneCountInString(w) <= 3 {
+		return w
+	}
+	lower := strings.ToLower(w)
+	cut := func(n int) { w, lower = w[:len(w)-n], lower[:len(lower)-n] }
+	switch {
+	case (strings.HasSuffix(lower, "ies") || strings.HasSuffix(lower, "ied")) && len(lower) > 4:
+		return w[:len(w)-3] + "y"
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "xes"),
+		strings.HasSuffix(lower, "ches"), strings.HasSuffix(lower, "shes"), strings.HasSuffix(lower, "zes"):
+		cut(2)
+	case strings.HasSuffix(lower, "ing") && len(lower) >= 7:
+		cut(3)
+		undouble(&w, &lower)
+	case strings.HasSuffix(lower, "ed") && len(lower) >= 6:
+		cut(2)
+		undouble(&w, &lower)
+	case strings.HasSuffix(lower, "s") && !strings.HasSuffix(lower, "ss") &&
+		!strings.HasSuffix(lower, "us") && !strings.HasSuffix(lower, "is"):
+		cut(1)
+	}
+	// A final e is dropped so "release" meets "releasing".
+	if strings.HasSuffix(lower, "e") && len(lower) > 3 {
+		w = w[:len(w)-1]
+	}
+	return w
+}
+
+// undouble drops a doubled final consonant left by a cut suffix, so
+// "running" meets "run".
+func undouble(w, lower *string) {
+	n := len(*lower)
+	if n < 3 {
+		return
+	}
+	c := (*lower)[n-1]
+	if c == (*lower)[n-2] && !strings.ContainsRune("aeiouslz", rune(c)) {
+		*w, *lower = (*w)[:len(*w)-1], (*lower)[:n-1]
+	}
+}
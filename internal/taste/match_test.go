@@ -0,0 +1,96 @@
+package taste
+
+import (
+	"testing"
+
+	"github.com/ppiankov/noisepan/internal/config"
+)
+
+func TestMatchTextContains(t *testing.T) {
+	word := config.KeywordMatch{WordBoundary: true}
+	stemmed := config.KeywordMatch{Stem: true}
+	exact := config.KeywordMatch{WordBoundary: true, CaseSensitive: true}
+	for _, tc := range []struct {
+		text, term string
+		opt        config.KeywordMatch
+		want       bool
+	}{
+		{"Google ships a new go release", "go", config.KeywordMatch{}, true},
+		{"Google ships a new gopher", "go", word, false},
+		{"Google ships Go 1.26", "go", word, true},
+		{"go: modules", "go", word, true},
+		{"Upgrading to C++23", "c++", word, true},
+		{"See ASP.NET docs", ".net", word, true},
+		{"ci/cd pipelines", "ci/cd", word, true},
+		{"Kubernetes deploys rolled back", "deployed", stemmed, true},
+		{"We are releasing 2.0", "release", stemmed, true},
+		{"Patched two CVEs", "patch", stemmed, true},
+		{"Running the migration", "run", stemmed, true},
+		{"New policy updates", "policies updated", stemmed, true},
+		{"Deployment notes", "deploy", stemmed, false},
+		{"A string of releases", "str", stemmed, false},
+		{"AWS outage", "AWS", exact, true},
+		{"aws outage", "AWS", exact, false},
+	} {
+		if got := newMatchText(tc.text).contains(tc.term, tc.opt); got != tc.want {
+			t.Errorf("contains(%q, %q, %+v) = %v, want %v", tc.text, tc.term, tc.opt, got, tc.want)
+		}
+	}
+}
+
+func TestMatchTextFind(t *testing.T) {
+	for _, tc := range []struct {
+		text, term string
+		opt        config.KeywordMatch
+		want       TextMatch
+	}{
+		{"Patch for CVE-2026-1", "cve", config.KeywordMatch{}, TextMatch{"CVE", 10, 13}},
+		{"Google ships Go 1.26", "go", config.KeywordMatch{WordBoundary: true}, TextMatch{"Go", 13, 15}},
+		{"Kubernetes deploys rolled back", "deployed", config.KeywordMatch{Stem: true}, TextMatch{"deploys", 11, 18}},
+		{"New policy updates today", "policies updated", config.KeywordMatch{Stem: true}, TextMatch{"policy updates", 4, 18}},
+		{"Найдена Уязвимость", "уязвимость", config.KeywordMatch{}, TextMatch{"Уязвимость", 8, 18}},
+	} {
+		got := newMatchText(tc.text).find(tc.term, tc.opt)
+		if got == nil || *got != tc.want {
+			t.Errorf("find(%q, %q) = %+v, want %+v", tc.text, tc.term, got, tc.want)
+		}
+	}
+	if got := newMatchText("no match here").find("cve", config.KeywordMatch{}); got != nil {
+		t.Errorf("find = %+v, want nil", got)
+	}
+}
+
+func TestStem(t *testing.T) {
+	for word, want := range map[string]string{
+		"deploys": "deploy", "deployed": "deploy", "deploying": "deploy",
+		"release": "releas", "releases": "releas", "released": "releas",
+		"patches": "patch", "classes": "class", "policies": "policy",
+		"running": "run", "status": "status", "string": "string", "speed": "speed", "cve": "cve",
+	} {
+		if got := stem(word); got != want {
+			t.Errorf("stem(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+func TestScore_MatchOptions(t *testing.T) {
+	profile := testProfile()
+	profile.Weights.HighSignal["go"] = 2
+	on := true
+	profile.Match = config.MatchConfig{
+		KeywordMatch: config.KeywordMatch{WordBoundary: true},
+		Keywords:     map[string]config.KeywordOverride{"expired": {Stem: &on}},
+	}
+
+	// "go" is a whole word only; "google" no longer scores.
+	if got := Score(post("google cloud pricing"), profile); got.Score != 0 {
+		t.Errorf("google scored %d: %+v", got.Score, got.Explanation)
+	}
+	if got := Score(post("Go 1.26 is out"), profile); got.Score != 2 {
+		t.Errorf("go scored %d, want 2", got.Score)
+	}
+	// The rule term "expired" is stemmed, so another tense fires it.
+	if got := Score(post("certs expiring tonight"), profile); got.Score != 4 {
+		t.Errorf("expiring scored %d, want 4: %+v", got.Score, got.Explanation)
+	}
+}
@@ -0,0 +1,151 @@
+package taste
+
+import (
+	"sort"
+
+	"github.com/ppiankov/noisepan/internal/config"
+)
+
+// ReportPost is a stored post as seen by Report: its text and the score,
+// tier, and labels it was given.
+type ReportPost struct {
+	Text   string
+	Score  int
+	Tier   string
+	Labels []string
+}
+
+// KeywordStat is how often a taste keyword matched.
+type KeywordStat struct {
+	Section string // "high_signal" or "low_signal"
+	Keyword string
+	Weight  int
+	Hits    int // posts containing the keyword
+	ReadNow int // of those, posts scored read_now
+	Ignore  int // of those, posts scored ignore
+}
+
+// RuleStat is how often a rule fired.
+type RuleStat struct {
+	Index  int      // position in taste.yaml rules, from 1
+	Match  []string // the rule's contains_any and contains_all terms
+	Fired  int
+	Labels []string
+}
+
+// LabelStat counts posts carrying a label.
+type LabelStat struct {
+	Label string
+	Posts int
+}
+
+// ThresholdShift is the tier split stored scores would get under moved
+// thresholds.
+type ThresholdShift struct {
+	Name       string // "current", "read_now -1", ...
+	Thresholds config.Thresholds
+	ReadNow    int
+	Skim       int
+	Ignore     int
+}
+
+// ProfileReport summarizes how a taste profile performs on stored posts.
+type ProfileReport struct {
+	Posts    int
+	Keywords []KeywordStat    // most hits first
+	Rules    []RuleStat       // in profile order
+	Labels   []LabelStat      // most posts first
+	Shifts   []ThresholdShift // current first, then each threshold moved by ±1
+}
+
+// Report measures profile against posts: keyword hit rates, rules that fired
+// and never fired, the label distribution, and how the tier split moves if a
+// threshold moves by one point. Tiers under shifted thresholds are recomputed
+// from the stored scores, so classifier and script adjustments are kept.
+func Report(profile *config.TasteProfile, posts []ReportPost) ProfileReport {
+	rep := ProfileReport{Posts: len(posts)}
+
+	texts := make([]*matchText, len(posts))
+	for i, p := range posts {
+		texts[i] = newMatchText(p.Text)
+		texts[i].products = profile.Products
+	}
+
+	keywords := func(section string, weights map[string]int) {
+		for kw, weight := range weights {
+			ks := KeywordStat{Section: section, Keyword: kw, Weight: weight}
+			for i, p := range posts {
+				if !texts[i].contains(kw, profile.Match.For(kw)) {
+					continue
+				}
+				ks.Hits++
+				switch p.Tier {
+				case TierReadNow:
+					ks.ReadNow++
+				case TierIgnore:
+					ks.Ignore++
+				}
+			}
+			rep.Keywords = append(rep.Keywords, ks)
+		}
+	}
+	keywords("high_signal", profile.Weights.HighSignal)
+	keywords("low_signal", profile.Weights.LowSignal)
+	sort.Slice(rep.Keywords, func(i, j int) bool {
+		a, b := rep.Keywords[i], rep.Keywords[j]
+		if a.Hits != b.Hits {
+			return a.Hits > b.Hits
+		}
+		return a.Keyword < b.Keyword
+	})
+
+	for i, rule := range profile.Rules {
+		rs := RuleStat{Index: i + 1, Match: rule.If.Terms(), Labels: rule.Then.Labels}
+		for _, text := range texts {
+			if ruleMatches(text, rule.If, profile.Match) {
+				rs.Fired++
+			}
+		}
+		rep.Rules = append(rep.Rules, rs)
+	}
+
+	labels := make(map[string]int)
+	for _, p := range posts {
+		for _, l := range p.Labels {
+			labels[l]++
+		}
+	}
+	for l, n := range labels {
+		rep.Labels = append(rep.Labels, LabelStat{Label: l, Posts: n})
+	}
+	sort.Slice(rep.Labels, func(i, j int) bool {
+		if rep.Labels[i].Posts != rep.Labels[j].Posts {
+			return rep.Labels[i].Posts > rep.Labels[j].Posts
+		}
+		return rep.Labels[i].Label < rep.Labels[j].Label
+	})
+
+	t := profile.Thresholds
+	shifts := []ThresholdShift{
+		{Name: "current", Thresholds: t},
+		{Name: "read_now -1", Thresholds: config.Thresholds{ReadNow: t.ReadNow - 1, Skim: t.Skim, Ignore: t.Ignore}},
+		{Name: "read_now +1", Thresholds: config.Thresholds{ReadNow: t.ReadNow + 1, Skim: t.Skim, Ignore: t.Ignore}},
+		{Name: "skim -1", Thresholds: config.Thresholds{ReadNow: t.ReadNow, Skim: t.Skim - 1, Ignore: t.Ignore}},
+		{Name: "skim +1", Thresholds: config.Thresholds{ReadNow: t.ReadNow, Skim: t.Skim + 1, Ignore: t.Ignore}},
+	}
+	for i := range shifts {
+		for _, p := range posts {
+			switch assignTier(p.Score, shifts[i].Thresholds) {
+			case TierReadNow:
+				shifts[i].ReadNow++
+			case TierSkim:
+				shifts[i].Skim++
+			default:
+				shifts[i].Ignore++
+			}
+		}
+	}
+	rep.Shifts = shifts
+
+	return rep
+}
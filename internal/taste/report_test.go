@@ -0,0 +1,73 @@
+package taste
+
+import (
+	"testing"
+
+	"github.com/ppiankov/noisepan/internal/config"
+)
+
+func TestReport(t *testing.T) {
+	profile := &config.TasteProfile{
+		Weights: config.Weights{
+			HighSignal: map[string]int{"cve": 5, "terraform": 3},
+			LowSignal:  map[string]int{"webinar": -4},
+		},
+		Rules: []config.Rule{
+			{If: config.RuleCondition{ContainsAny: []string{"outage"}}, Then: config.RuleAction{ScoreAdd: 2, Labels: []string{"incidents"}}},
+			{If: config.RuleCondition{ContainsAny: []string{"deprecat"}}, Then: config.RuleAction{ScoreAdd: 1}},
+		},
+		Thresholds: config.Thresholds{ReadNow: 7, Skim: 3},
+	}
+	posts := []ReportPost{
+		{Text: "CVE-2026-1 in openssl", Score: 7, Tier: TierReadNow},
+		{Text: "cve roundup and outage", Score: 7, Tier: TierReadNow, Labels: []string{"incidents"}},
+		{Text: "CVE where nothing else matched", Score: 6, Tier: TierSkim},
+		{Text: "Outage postmortem", Score: 3, Tier: TierSkim, Labels: []string{"incidents"}},
+		{Text: "webinar invite", Score: -4, Tier: TierIgnore},
+	}
+
+	rep := Report(profile, posts)
+	if rep.Posts != 5 {
+		t.Errorf("posts = %d, want 5", rep.Posts)
+	}
+
+	if len(rep.Keywords) != 3 {
+		t.Fatalf("keywords = %+v", rep.Keywords)
+	}
+	cve := rep.Keywords[0]
+	if cve.Keyword != "cve" || cve.Hits != 3 || cve.ReadNow != 2 || cve.Ignore != 0 || cve.Weight != 5 {
+		t.Errorf("cve = %+v", cve)
+	}
+	if tf := rep.Keywords[2]; tf.Keyword != "terraform" || tf.Hits != 0 {
+		t.Errorf("last keyword = %+v, want unmatched terraform", tf)
+	}
+	if wb := rep.Keywords[1]; wb.Keyword != "webinar" || wb.Section != "low_signal" || wb.Ignore != 1 {
+		t.Errorf("webinar = %+v", wb)
+	}
+
+	if len(rep.Rules) != 2 || rep.Rules[0].Fired != 2 || rep.Rules[1].Fired != 0 || rep.Rules[1].Index != 2 {
+		t.Errorf("rules = %+v", rep.Rules)
+	}
+	if len(rep.Labels) != 1 || rep.Labels[0] != (LabelStat{Label: "incidents", Posts: 2}) {
+		t.Errorf("labels = %+v", rep.Labels)
+	}
+
+	want := map[string][3]int{
+		"current":     {2, 2, 1},
+		"read_now -1": {3, 1, 1},
+		"read_now +1": {0, 4, 1},
+		"skim -1":     {2, 2, 1},
+		"skim +1":     {2, 1, 2},
+	}
+	if len(rep.Shifts) != len(want) || rep.Shifts[0].Name != "current" {
+		t.Fatalf("shifts = %+v", rep.Shifts)
+	}
+	for _, s := range rep.Shifts {
+		if got := [3]int{s.ReadNow, s.Skim, s.Ignore}; got != want[s.Name] {
+			t.Errorf("%s = %v, want %v", s.Name, got, want[s.Name])
+		}
+	}
+	if s := rep.Shifts[2].Thresholds; s.ReadNow != 8 || s.Skim != 3 {
+		t.Errorf("read_now +1 thresholds = %+v", s)
+	}
+}
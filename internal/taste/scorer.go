@@ -2,6 +2,7 @@ package taste
 
 import (
 	"fmt"
+	"maps"
 	"slices"
 	"strings"
 
@@ -9,30 +10,93 @@ import (
 	"github.com/ppiankov/noisepan/internal/source"
 )
 
+// The default tiers. A taste profile's tiers always start with read_now and
+// end with ignore; skim is replaced by any tiers defined between them.
 const (
 	TierReadNow = "read_now"
 	TierSkim    = "skim"
 	TierIgnore  = "ignore"
 )
 
+// TierNames returns the names of profile's tiers, highest first.
+func TierNames(profile *config.TasteProfile) []string {
+	tiers := profile.TierSet()
+	names := make([]string, len(tiers))
+	for i, t := range tiers {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// TierTitle returns the display name of a tier: "Read Now" for read_now,
+// others capitalized with underscores as spaces ("weekend_reads" becomes
+// "Weekend reads").
+func TierTitle(tier string) string {
+	if tier == TierReadNow {
+		return "Read Now"
+	}
+	title := strings.ReplaceAll(tier, "_", " ")
+	if title == "" {
+		return title
+	}
+	return strings.ToUpper(title[:1]) + title[1:]
+}
+
 // ScoredPost is a post with its computed score, labels, tier, and explanation.
 type ScoredPost struct {
 	Post        source.Post
 	Score       int
 	Labels      []string
-	Tier        string // "read_now", "skim", "ignore"
+	Tier        string // "read_now", "skim", "ignore", or a custom tier
 	Explanation []ScoreContribution
+
+	// forcedTier is the tier a channel or author modifier pins the post
+	// to; later score changes keep it.
+	forcedTier string
+	// bounds is the tier range the post's labels allow.
+	bounds tierBounds
+}
+
+// Pinned reports whether a channel or author modifier set the post's tier.
+func (sp ScoredPost) Pinned() bool {
+	return sp.forcedTier != ""
+}
+
+// BoundTier returns tier, the tier of sp's score among tiers, as sp allows
+// it: the tier a channel or author modifier pinned, or tier moved into the
+// range its labels' label_tiers allow.
+func (sp ScoredPost) BoundTier(tier string, tiers []config.Tier) string {
+	if sp.forcedTier != "" {
+		return sp.forcedTier
+	}
+	return sp.bounds.apply(tier, tiers)
 }
 
 // ScoreContribution records a single scoring reason and its point value.
 type ScoreContribution struct {
 	Reason string // "keyword: kubernetes" or "rule: contains cve"
 	Points int
+	Match  *TextMatch `json:",omitempty"` // where a keyword or rule term matched
+}
+
+// TextMatch is where a term matched in a post's text: the text as written
+// there and its offsets in characters (runes), end exclusive.
+type TextMatch struct {
+	Text       string
+	Start, End int
 }
 
 // Score evaluates a post against a taste profile and returns a scored result.
+// Rule cooldowns are not applied.
 func Score(post source.Post, profile *config.TasteProfile) ScoredPost {
-	textLower := strings.ToLower(post.Text)
+	return ScoreWithCooldowns(post, profile, nil)
+}
+
+// ScoreWithCooldowns is Score with rule cooldowns tracked in cooldowns: a rule
+// on cooldown in the post's channel still adds its labels, but no points.
+func ScoreWithCooldowns(post source.Post, profile *config.TasteProfile, cooldowns *Cooldowns) ScoredPost {
+	text := newMatchText(post.Text)
+	text.products = profile.Products
 
 	var (
 		total       int
@@ -40,64 +104,198 @@ func Score(post source.Post, profile *config.TasteProfile) ScoredPost {
 		explanation []ScoreContribution
 	)
 
-	// High signal keywords
-	for kw, weight := range profile.Weights.HighSignal {
-		if strings.Contains(textLower, strings.ToLower(kw)) {
-			total += weight
-			explanation = append(explanation, ScoreContribution{
-				Reason: fmt.Sprintf("keyword: %s", kw),
-				Points: weight,
-			})
+	// The profile's keywords and rules, then those of the post's language.
+	sets := []termSet{{"", profile.Weights, profile.Rules}}
+	if lang := postLanguage(post, profile); lang != "" {
+		if lp, ok := profile.Languages[lang]; ok {
+			sets = append(sets, termSet{lang, lp.Weights, lp.Rules})
 		}
 	}
 
-	// Low signal keywords
-	for kw, weight := range profile.Weights.LowSignal {
-		if strings.Contains(textLower, strings.ToLower(kw)) {
-			total += weight
-			explanation = append(explanation, ScoreContribution{
-				Reason: fmt.Sprintf("keyword: %s", kw),
-				Points: weight,
-			})
+	for _, set := range sets {
+		suffix := ""
+		if set.lang != "" {
+			suffix = " (" + set.lang + ")"
+		}
+
+		// High signal keywords, then low signal ones. Keys are visited in
+		// order so the explanation of a post is the same on every run.
+		for _, weights := range []map[string]int{set.weights.HighSignal, set.weights.LowSignal} {
+			for _, kw := range slices.Sorted(maps.Keys(weights)) {
+				if m := text.find(kw, profile.Match.For(kw)); m != nil {
+					total += weights[kw]
+					explanation = append(explanation, ScoreContribution{
+						Reason: fmt.Sprintf("keyword: %s%s", kw, suffix),
+						Points: weights[kw],
+						Match:  m,
+					})
+				}
+			}
 		}
-	}
 
-	// Rules
-	for _, rule := range profile.Rules {
-		if ruleMatches(textLower, rule.If) {
-			total += rule.Then.ScoreAdd
-			labels = append(labels, rule.Then.Labels...)
-			reason := "rule"
-			if len(rule.If.ContainsAny) > 0 {
-				reason = fmt.Sprintf("rule: %s", rule.If.ContainsAny[0])
+		// Rules
+		for _, rule := range set.rules {
+			if m, ok := ruleMatch(text, rule.If, profile.Match); ok {
+				points := rule.Then.ScoreAdd
+				labels = append(labels, rule.Then.Labels...)
+				reason := ruleReason(rule) + suffix
+				if !cooldowns.allow(rule, post) {
+					points = 0
+					reason += " (cooldown)"
+				}
+				total += points
+				explanation = append(explanation, ScoreContribution{
+					Reason: reason,
+					Points: points,
+					Match:  m,
+				})
 			}
-			explanation = append(explanation, ScoreContribution{
-				Reason: reason,
-				Points: rule.Then.ScoreAdd,
-			})
 		}
 	}
 
+	// Source modifiers, then channel ones, then author ones; the last
+	// pinned tier wins.
+	var forcedTier string
+	eachModifier(post, profile, func(kind, key string, m config.ScoreModifier) {
+		total += m.ScoreAdd
+		labels = append(labels, m.Labels...)
+		reason := fmt.Sprintf("%s: %s", kind, key)
+		if m.Tier != "" {
+			forcedTier = m.Tier
+			reason += fmt.Sprintf(" (tier %s)", m.Tier)
+		}
+		explanation = append(explanation, ScoreContribution{
+			Reason: reason,
+			Points: m.ScoreAdd,
+		})
+	})
+
 	// Deduplicate and sort labels
 	slices.Sort(labels)
 	labels = slices.Compact(labels)
 
-	return ScoredPost{
+	sp := ScoredPost{
 		Post:        post,
 		Score:       total,
 		Labels:      labels,
-		Tier:        assignTier(total, profile.Thresholds),
 		Explanation: explanation,
+		forcedTier:  forcedTier,
+		bounds:      labelBounds(labels, profile),
+	}
+	tiers := profile.TierSet()
+	tier := tierOf(total, tiers)
+	sp.Tier = sp.BoundTier(tier, tiers)
+	if sp.Tier != tier && forcedTier == "" {
+		sp.Explanation = append(sp.Explanation, ScoreContribution{Reason: sp.bounds.reason(sp.Tier)})
+	}
+	return sp
+}
+
+// termSet is the keywords and rules of a profile, or of one of its
+// languages.
+type termSet struct {
+	lang    string // "" for the profile's own
+	weights config.Weights
+	rules   []config.Rule
+}
+
+// postLanguage returns the language of post: the one stored with it, or,
+// for posts stored before languages were, detected from its text when the
+// profile has any language-scoped terms.
+func postLanguage(post source.Post, profile *config.TasteProfile) string {
+	if post.Language != "" || len(profile.Languages) == 0 {
+		return post.Language
+	}
+	return DetectLanguage(post.Text)
+}
+
+func ruleMatches(text *matchText, cond config.RuleCondition, match config.MatchConfig) bool {
+	_, ok := ruleMatch(text, cond, match)
+	return ok
+}
+
+// ruleMatch reports whether cond matches text, and where its first
+// matching contains_any term, or else its first contains_all term, did.
+func ruleMatch(text *matchText, cond config.RuleCondition, match config.MatchConfig) (*TextMatch, bool) {
+	if len(cond.ContainsAny) == 0 && len(cond.ContainsAll) == 0 && len(cond.HasEntity) == 0 {
+		return nil, false
+	}
+	var first *TextMatch
+	if len(cond.ContainsAny) > 0 {
+		for _, kw := range cond.ContainsAny {
+			if first = text.find(kw, match.For(kw)); first != nil {
+				break
+			}
+		}
+		if first == nil {
+			return nil, false
+		}
+	}
+	for _, kw := range cond.ContainsAll {
+		m := text.find(kw, match.For(kw))
+		if m == nil {
+			return nil, false
+		}
+		if first == nil {
+			first = m
+		}
+	}
+	if len(cond.HasEntity) > 0 && !slices.ContainsFunc(cond.HasEntity, text.entitySet().Has) {
+		return nil, false
+	}
+	if slices.ContainsFunc(cond.NotContainsAny, func(kw string) bool { return text.contains(kw, match.For(kw)) }) {
+		return nil, false
+	}
+	return first, true
+}
+
+// eachModifier calls fn with every channel and author modifier of profile
+// that applies to post: its source's, its channel's, then its author's by
+// key.
+func eachModifier(post source.Post, profile *config.TasteProfile, fn func(kind, key string, m config.ScoreModifier)) {
+	for _, key := range []string{post.Source, post.Source + "/" + post.Channel} {
+		if m, ok := profile.Channels[key]; ok {
+			fn("channel", key, m)
+		}
+	}
+	if post.Author == "" {
+		return
+	}
+	for _, key := range slices.Sorted(maps.Keys(profile.Authors)) {
+		if authorMatches(key, post) {
+			fn("author", key, profile.Authors[key])
+		}
+	}
+}
+
+// pinnedTier returns the tier the modifiers of profile pin post to, or "".
+func pinnedTier(post source.Post, profile *config.TasteProfile) string {
+	var tier string
+	eachModifier(post, profile, func(_, _ string, m config.ScoreModifier) {
+		if m.Tier != "" {
+			tier = m.Tier
+		}
+	})
+	return tier
+}
+
+// authorMatches reports whether an authors key, "name" or "source/name",
+// names post's author. Names compare case-insensitively.
+func authorMatches(key string, post source.Post) bool {
+	if src, name, ok := strings.Cut(key, "/"); ok {
+		return src == post.Source && strings.EqualFold(name, post.Author)
 	}
+	return strings.EqualFold(key, post.Author)
 }
 
-func ruleMatches(textLower string, cond config.RuleCondition) bool {
-	for _, kw := range cond.ContainsAny {
-		if strings.Contains(textLower, strings.ToLower(kw)) {
-			return true
+// tierOf returns the first of tiers whose bar score reaches, or the last.
+func tierOf(score int, tiers []config.Tier) string {
+	for _, t := range tiers[:len(tiers)-1] {
+		if score >= t.MinScore {
+			return t.Name
 		}
 	}
-	return false
+	return tiers[len(tiers)-1].Name
 }
 
 func assignTier(score int, t config.Thresholds) string {
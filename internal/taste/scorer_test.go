@@ -107,6 +107,160 @@ func TestScore_RuleNoMatch(t *testing.T) {
 	}
 }
 
+func TestScore_RuleAllAndNot(t *testing.T) {
+	profile := &config.TasteProfile{
+		Rules: []config.Rule{{
+			If: config.RuleCondition{
+				ContainsAll:    []string{"kubernetes", "deprecat"},
+				NotContainsAny: []string{"webinar"},
+			},
+			Then: config.RuleAction{ScoreAdd: 5, Labels: []string{"ops"}},
+		}, {
+			If: config.RuleCondition{
+				ContainsAny: []string{"postgres", "mysql"},
+				ContainsAll: []string{"release"},
+			},
+			Then: config.RuleAction{ScoreAdd: 2},
+		}},
+		Thresholds: config.Thresholds{ReadNow: 7, Skim: 3, Ignore: 0},
+	}
+
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"Kubernetes 1.36 deprecates the legacy API", 5},
+		{"Kubernetes 1.36 released", 0},                         // only one of contains_all
+		{"Webinar: Kubernetes deprecations explained", 0},       // not_contains_any
+		{"Postgres 18 release notes", 2},                        // any and all
+		{"Postgres tuning tips", 0},                             // any without all
+		{"Kubernetes deprecations and the Postgres release", 7}, // both rules
+	}
+	for _, tt := range tests {
+		if got := Score(post(tt.text), profile); got.Score != tt.want {
+			t.Errorf("Score(%q) = %d, want %d (%+v)", tt.text, got.Score, tt.want, got.Explanation)
+		}
+	}
+	if got := Score(post("Kubernetes 1.36 deprecates the legacy API"), profile); len(got.Explanation) != 1 || got.Explanation[0].Reason != "rule: kubernetes" {
+		t.Errorf("explanation = %+v, want rule: kubernetes", got.Explanation)
+	}
+}
+
+func TestScore_RuleHasEntity(t *testing.T) {
+	profile := &config.TasteProfile{
+		Rules: []config.Rule{{
+			If:   config.RuleCondition{HasEntity: []string{"cve", "ghsa"}},
+			Then: config.RuleAction{ScoreAdd: 4, Labels: []string{"security"}},
+		}, {
+			If:   config.RuleCondition{ContainsAny: []string{"patch"}, HasEntity: []string{"vendor"}},
+			Then: config.RuleAction{ScoreAdd: 2},
+		}},
+		Thresholds: config.Thresholds{ReadNow: 7, Skim: 3, Ignore: 0},
+		Products:   map[string][]string{"Microsoft": {"Exchange"}},
+	}
+
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"Advisory CVE-2026-1111 is out", 4},
+		{"GHSA-4m4x-9f2h-vq3c affects the parser", 4},
+		{"Patch Exchange today", 2},                      // vendor of a product
+		{"Patch Tuesday notes", 0},                       // no vendor
+		{"Exchange fix for CVE-2026-1111, patch now", 6}, // both rules
+		{"cve-less patch notes", 0},
+	}
+	for _, tt := range tests {
+		if got := Score(post(tt.text), profile); got.Score != tt.want {
+			t.Errorf("Score(%q) = %d, want %d (%+v)", tt.text, got.Score, tt.want, got.Explanation)
+		}
+	}
+	if got := Score(post("Advisory CVE-2026-1111"), profile); len(got.Explanation) != 1 || got.Explanation[0].Reason != "rule: has cve" {
+		t.Errorf("explanation = %+v, want rule: has cve", got.Explanation)
+	}
+
+	profile.Products = nil
+	if got := Score(post("Patch Exchange today"), profile); got.Score != 0 {
+		t.Errorf("without products: score = %d, want 0", got.Score)
+	}
+}
+
+func TestScore_ScoreModifiers(t *testing.T) {
+	profile := testProfile()
+	profile.Channels = map[string]config.ScoreModifier{
+		"test":           {ScoreAdd: 1},
+		"test/@vendor":   {ScoreAdd: -3, Labels: []string{"vendor"}},
+		"test/@security": {Tier: TierReadNow},
+	}
+
+	vendor := post("kubernetes deployment tips")
+	vendor.Channel = "@vendor"
+	got := Score(vendor, profile)
+	if got.Score != 1 || got.Tier != TierIgnore || !slices.Contains(got.Labels, "vendor") {
+		t.Errorf("vendor post = score %d tier %s labels %v, want 1 ignore [vendor]", got.Score, got.Tier, got.Labels)
+	}
+	reasons := make([]string, len(got.Explanation))
+	for i, c := range got.Explanation {
+		reasons[i] = c.Reason
+	}
+	if !slices.Equal(reasons, []string{"keyword: kubernetes", "channel: test", "channel: test/@vendor"}) {
+		t.Errorf("reasons = %v", reasons)
+	}
+
+	// A pinned tier holds whatever the score, also through the classifier.
+	sec := post("weekly newsletter")
+	sec.Channel = "@security"
+	got = Score(sec, profile)
+	if got.Tier != TierReadNow || !got.Pinned() || got.Explanation[len(got.Explanation)-1].Reason != "channel: test/@security (tier read_now)" {
+		t.Errorf("security post = %+v", got)
+	}
+	clf, err := Train([]Example{
+		{Text: "weekly newsletter", Positive: false},
+		{Text: "postgres release", Positive: true},
+	})
+	if err != nil {
+		t.Fatalf("train: %v", err)
+	}
+	if got := clf.Apply(got, 3, profile.TierSet()); got.Score >= 0 || got.Tier != TierReadNow {
+		t.Errorf("after classifier = score %d tier %s, want negative read_now", got.Score, got.Tier)
+	}
+
+	if got := Score(post("kubernetes deployment tips"), profile); got.Score != 4 || got.Pinned() {
+		t.Errorf("other channel = score %d pinned %v, want 4 unpinned", got.Score, got.Pinned())
+	}
+}
+
+func TestScore_Authors(t *testing.T) {
+	profile := testProfile()
+	profile.Authors = map[string]config.ScoreModifier{
+		"SecResearcher":  {ScoreAdd: 4, Labels: []string{"trusted"}},
+		"test/spammer":   {ScoreAdd: -5, Tier: TierIgnore},
+		"reddit/spammer": {ScoreAdd: -9},
+	}
+
+	p := post("kubernetes deployment tips")
+	p.Author = "secresearcher"
+	got := Score(p, profile)
+	if got.Score != 7 || got.Tier != TierReadNow || !slices.Contains(got.Labels, "trusted") {
+		t.Errorf("trusted author = score %d tier %s labels %v, want 7 read_now [trusted]", got.Score, got.Tier, got.Labels)
+	}
+	if last := got.Explanation[len(got.Explanation)-1]; last.Reason != "author: SecResearcher" || last.Points != 4 {
+		t.Errorf("explanation = %+v", got.Explanation)
+	}
+
+	// Source-scoped keys only match their source.
+	p = post("CVE in kubernetes")
+	p.Author = "spammer"
+	got = Score(p, profile)
+	if got.Score != 3 || got.Tier != TierIgnore || !got.Pinned() {
+		t.Errorf("spammer = score %d tier %s, want 3 ignore pinned", got.Score, got.Tier)
+	}
+
+	if got := Score(post("kubernetes deployment tips"), profile); got.Score != 3 {
+		t.Errorf("no author score = %d, want 3", got.Score)
+	}
+}
+
 func TestScore_LabelsDeduplicated(t *testing.T) {
 	profile := &config.TasteProfile{
 		Rules: []config.Rule{
@@ -168,6 +322,38 @@ func TestScore_TierIgnore(t *testing.T) {
 	}
 }
 
+func TestScore_CustomTiers(t *testing.T) {
+	profile := testProfile()
+	profile.Tiers = []config.Tier{{Name: TierReadNow, MinScore: 8}, {Name: "today", MinScore: 5}, {Name: "weekend", MinScore: 3}, {Name: TierIgnore}}
+
+	for _, tt := range []struct{ text, want string }{
+		{"kubernetes cve alert", TierReadNow}, // 8
+		{"cve advisory", "today"},             // 5
+		{"kubernetes news", "weekend"},        // 3
+		{"random unrelated text", TierIgnore}, // 0
+	} {
+		if got := Score(post(tt.text), profile).Tier; got != tt.want {
+			t.Errorf("%q: tier = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+	if got, want := TierNames(profile), []string{TierReadNow, "today", "weekend", TierIgnore}; !slices.Equal(got, want) {
+		t.Errorf("TierNames = %v, want %v", got, want)
+	}
+}
+
+func TestTierTitle(t *testing.T) {
+	for tier, want := range map[string]string{
+		TierReadNow:     "Read Now",
+		TierSkim:        "Skim",
+		TierIgnore:      "Ignore",
+		"weekend_reads": "Weekend reads",
+	} {
+		if got := TierTitle(tier); got != want {
+			t.Errorf("TierTitle(%q) = %q, want %q", tier, got, want)
+		}
+	}
+}
+
 func TestScore_EmptyProfile(t *testing.T) {
 	profile := &config.TasteProfile{
 		Thresholds: config.Thresholds{ReadNow: 7, Skim: 3, Ignore: 0},
@@ -206,6 +392,25 @@ func TestScore_ExplanationCompleteness(t *testing.T) {
 	}
 }
 
+func TestScore_ExplanationOrder(t *testing.T) {
+	profile := testProfile()
+	profile.Weights.HighSignal["helm"] = 1
+	profile.Weights.HighSignal["argo"] = 1
+	want := []string{"keyword: argo", "keyword: cve", "keyword: helm", "keyword: kubernetes", "keyword: hiring", "rule: expired"}
+
+	// Map order changes between runs; the explanation must not.
+	for range 20 {
+		result := Score(post("kubernetes helm argo cve expired, hiring"), profile)
+		var got []string
+		for _, e := range result.Explanation {
+			got = append(got, e.Reason)
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("explanation = %v, want %v", got, want)
+		}
+	}
+}
+
 func TestScore_CaseInsensitive(t *testing.T) {
 	result := Score(post("KUBERNETES CLUSTER UPDATE"), testProfile())
 
@@ -213,3 +418,52 @@ func TestScore_CaseInsensitive(t *testing.T) {
 		t.Errorf("score = %d, want 3", result.Score)
 	}
 }
+
+func TestScore_Languages(t *testing.T) {
+	profile := testProfile()
+	profile.Languages = map[string]config.LanguageProfile{
+		"ru": {Weights: config.Weights{HighSignal: map[string]int{"уязвимость": 5}}},
+	}
+
+	p := post("Найдена уязвимость в kubernetes")
+	p.Language = "ru"
+	result := Score(p, profile)
+	if result.Score != 8 {
+		t.Errorf("score = %d, want 8 (kubernetes 3 + уязвимость 5)", result.Score)
+	}
+	if !slices.ContainsFunc(result.Explanation, func(c ScoreContribution) bool { return c.Reason == "keyword: уязвимость (ru)" }) {
+		t.Errorf("explanation = %+v, want keyword: уязвимость (ru)", result.Explanation)
+	}
+
+	// Another language's terms do not apply.
+	p.Language = "uk"
+	if result := Score(p, profile); result.Score != 3 {
+		t.Errorf("uk score = %d, want 3", result.Score)
+	}
+
+	// Without a stored language, it is detected from the text.
+	if result := Score(post("Найдена уязвимость в kubernetes, это важно"), profile); result.Score != 8 {
+		t.Errorf("detected score = %d, want 8", result.Score)
+	}
+}
+
+func TestScore_MatchLocations(t *testing.T) {
+	result := Score(post("Expired cert on the kubernetes API"), testProfile())
+	want := map[string]TextMatch{
+		"keyword: kubernetes": {"kubernetes", 20, 30},
+		"rule: expired":       {"Expired", 0, 7},
+	}
+	for _, c := range result.Explanation {
+		w, ok := want[c.Reason]
+		if !ok {
+			continue
+		}
+		if c.Match == nil || *c.Match != w {
+			t.Errorf("%s: match = %+v, want %+v", c.Reason, c.Match, w)
+		}
+		delete(want, c.Reason)
+	}
+	if len(want) > 0 {
+		t.Errorf("missing contributions %v in %+v", want, result.Explanation)
+	}
+}
@@ -0,0 +1,96 @@
+package taste
+
+import (
+	"unicode"
+
+	"github.com/ppiankov/noisepan/internal/config"
+)
+
+// ScriptOther covers letters of scripts DominantScript does not name.
+const ScriptOther = "Other"
+
+// scriptTables are the scripts DominantScript recognizes. Hiragana and
+// Katakana are reported together as Kana.
+var scriptTables = []struct {
+	name  string
+	table *unicode.RangeTable
+}{
+	{"Latin", unicode.Latin},
+	{"Cyrillic", unicode.Cyrillic},
+	{"Greek", unicode.Greek},
+	{"Arabic", unicode.Arabic},
+	{"Hebrew", unicode.Hebrew},
+	{"Han", unicode.Han},
+	{"Kana", unicode.Hiragana},
+	{"Kana", unicode.Katakana},
+	{"Hangul", unicode.Hangul},
+	{"Devanagari", unicode.Devanagari},
+	{"Thai", unicode.Thai},
+	{"Georgian", unicode.Georgian},
+	{"Armenian", unicode.Armenian},
+}
+
+// DominantScript returns the script most letters of text are written in, or
+// "" if text has no letters. It is a cheap stand-in for language detection:
+// it tells Cyrillic from Latin posts, not English from German.
+func DominantScript(text string) string {
+	counts := make(map[string]int)
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		name := ScriptOther
+		for _, st := range scriptTables {
+			if unicode.Is(st.table, r) {
+				name = st.name
+				break
+			}
+		}
+		counts[name]++
+	}
+
+	best, bestN := "", 0
+	for _, st := range scriptTables {
+		if n := counts[st.name]; n > bestN {
+			best, bestN = st.name, n
+		}
+	}
+	if counts[ScriptOther] > bestN {
+		best = ScriptOther
+	}
+	return best
+}
+
+// ProfileScripts returns the scripts the profile's keywords, label terms and
+// rule terms (its languages' too) are written in, i.e. the scripts scoring can match at all.
+func ProfileScripts(p *config.TasteProfile) map[string]bool {
+	scripts := make(map[string]bool)
+	add := func(term string) {
+		if s := DominantScript(term); s != "" {
+			scripts[s] = true
+		}
+	}
+	addSet := func(w config.Weights, rules []config.Rule) {
+		for kw := range w.HighSignal {
+			add(kw)
+		}
+		for kw := range w.LowSignal {
+			add(kw)
+		}
+		for _, r := range rules {
+			for _, t := range append(r.If.Terms(), r.If.NotContainsAny...) {
+				add(t)
+			}
+		}
+	}
+	addSet(p.Weights, p.Rules)
+	for _, lp := range p.Languages {
+		addSet(lp.Weights, lp.Rules)
+	}
+	for _, terms := range p.Labels {
+		for _, t := range terms {
+			add(t)
+		}
+	}
+	return scripts
+}
@@ -0,0 +1,52 @@
+package taste
+
+import (
+	"testing"
+
+	"github.com/ppiankov/noisepan/internal/config"
+)
+
+func TestDominantScript(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Kubernetes 1.31 released", "Latin"},
+		{"Вышел Kubernetes 1.31 с новыми функциями", "Cyrillic"},
+		{"ニュース: Kubernetes", "Latin"},
+		{"カーネルのアップデート", "Kana"},
+		{"新版本发布", "Han"},
+		{"쿠버네티스 업데이트", "Hangul"},
+		{"Ελληνικά νέα", "Greek"},
+		{"ሰላም", ScriptOther},
+		{"1234 — !!!", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := DominantScript(tt.text); got != tt.want {
+			t.Errorf("DominantScript(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestProfileScripts(t *testing.T) {
+	p := &config.TasteProfile{
+		Weights: config.Weights{
+			HighSignal: map[string]int{"kubernetes": 3},
+			LowSignal:  map[string]int{"вебинар": -3},
+		},
+		Labels: map[string][]string{"security": {"脆弱性"}},
+		Rules: []config.Rule{
+			{If: config.RuleCondition{ContainsAny: []string{"cve", "42"}}},
+		},
+	}
+	got := ProfileScripts(p)
+	for _, s := range []string{"Latin", "Cyrillic", "Han"} {
+		if !got[s] {
+			t.Errorf("missing script %s in %v", s, got)
+		}
+	}
+	if len(got) != 3 {
+		t.Errorf("got %d scripts, want 3: %v", len(got), got)
+	}
+}
@@ -0,0 +1,99 @@
+package taste
+
+import (
+	"sort"
+
+	"github.com/ppiankov/noisepan/internal/config"
+)
+
+const (
+	suggestStep     = 2    // weight change per suggestion
+	suggestMajority = 0.75 // share of votes that must point the same way
+)
+
+// Sample is a post the user voted on, with the tier it was scored into.
+type Sample struct {
+	Text string
+	Tier string
+	Vote int // +1 up, -1 down
+}
+
+// Suggestion proposes a new weight for a taste keyword.
+type Suggestion struct {
+	Section   string // "high_signal" or "low_signal"
+	Keyword   string
+	Current   int
+	Suggested int
+	Up        int // upvotes on posts containing the keyword
+	Down      int // downvotes on posts containing the keyword
+	Disagreed int // votes that contradict the assigned tier
+}
+
+// Suggest proposes keyword weight changes from voted samples. A keyword
+// qualifies once it appears in at least minVotes voted posts, at least three
+// quarters of those votes point the same way, and some of them contradict the
+// tier the post got (downvoted read_now/skim, upvoted ignore); if every vote
+// already agrees with its tier the weight is left alone. Results are sorted by
+// vote count, then keyword.
+func Suggest(profile *config.TasteProfile, samples []Sample, minVotes int) []Suggestion {
+	if minVotes < 1 {
+		minVotes = 1
+	}
+
+	texts := make([]*matchText, len(samples))
+	for i, s := range samples {
+		texts[i] = newMatchText(s.Text)
+	}
+
+	var out []Suggestion
+	check := func(section string, weights map[string]int) {
+		for kw, weight := range weights {
+			sg := Suggestion{Section: section, Keyword: kw, Current: weight}
+			upDisagree, downDisagree := 0, 0
+			for i, s := range samples {
+				if !texts[i].contains(kw, profile.Match.For(kw)) {
+					continue
+				}
+				switch {
+				case s.Vote > 0:
+					sg.Up++
+					if s.Tier == TierIgnore {
+						upDisagree++
+					}
+				case s.Vote < 0:
+					sg.Down++
+					if s.Tier != TierIgnore {
+						downDisagree++
+					}
+				}
+			}
+
+			total := sg.Up + sg.Down
+			if total < minVotes {
+				continue
+			}
+			switch {
+			case float64(sg.Down) >= suggestMajority*float64(total) && downDisagree > 0:
+				sg.Suggested = weight - suggestStep
+				sg.Disagreed = downDisagree
+			case float64(sg.Up) >= suggestMajority*float64(total) && upDisagree > 0:
+				sg.Suggested = weight + suggestStep
+				sg.Disagreed = upDisagree
+			default:
+				continue
+			}
+			out = append(out, sg)
+		}
+	}
+	check("high_signal", profile.Weights.HighSignal)
+	check("low_signal", profile.Weights.LowSignal)
+
+	sort.Slice(out, func(i, j int) bool {
+		ti, tj := out[i].Up+out[i].Down, out[j].Up+out[j].Down
+		if ti != tj {
+			return ti > tj
+		}
+		return out[i].Keyword < out[j].Keyword
+	})
+	return out
+}
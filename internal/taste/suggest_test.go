@@ -0,0 +1,51 @@
+package taste
+
+import (
+	"testing"
+
+	"github.com/ppiankov/noisepan/internal/config"
+)
+
+func TestSuggest(t *testing.T) {
+	profile := &config.TasteProfile{
+		Weights: config.Weights{
+			HighSignal: map[string]int{"terraform": 3, "cve": 5, "kubernetes": 3},
+			LowSignal:  map[string]int{"webinar": -4},
+		},
+	}
+	samples := []Sample{
+		// terraform: consistently downvoted while scored skim
+		{Text: "Terraform provider 5.1 released", Tier: TierSkim, Vote: -1},
+		{Text: "terraform module tips", Tier: TierSkim, Vote: -1},
+		{Text: "Terraform state drift", Tier: TierReadNow, Vote: -1},
+		// webinar: upvoted though ignored
+		{Text: "Webinar: postmortem of the big outage", Tier: TierIgnore, Vote: 1},
+		{Text: "webinar recording on etcd", Tier: TierIgnore, Vote: 1},
+		{Text: "Webinar on CI", Tier: TierIgnore, Vote: 1},
+		// cve: votes agree with tiers, nothing to change
+		{Text: "CVE-2026-1", Tier: TierReadNow, Vote: 1},
+		{Text: "CVE-2026-2", Tier: TierReadNow, Vote: 1},
+		{Text: "CVE-2026-3", Tier: TierReadNow, Vote: 1},
+		// kubernetes: mixed votes
+		{Text: "kubernetes 1.40", Tier: TierSkim, Vote: 1},
+		{Text: "kubernetes meetup", Tier: TierSkim, Vote: -1},
+		{Text: "kubernetes docs", Tier: TierSkim, Vote: -1},
+	}
+
+	got := Suggest(profile, samples, 3)
+	if len(got) != 2 {
+		t.Fatalf("suggestions = %+v, want terraform and webinar", got)
+	}
+	tf := got[0]
+	if tf.Keyword != "terraform" || tf.Section != "high_signal" || tf.Current != 3 || tf.Suggested != 1 || tf.Down != 3 || tf.Disagreed != 3 {
+		t.Errorf("terraform = %+v", tf)
+	}
+	wb := got[1]
+	if wb.Keyword != "webinar" || wb.Section != "low_signal" || wb.Suggested != -2 || wb.Up != 3 {
+		t.Errorf("webinar = %+v", wb)
+	}
+
+	if got := Suggest(profile, samples, 4); len(got) != 0 {
+		t.Errorf("minVotes 4: suggestions = %+v, want none", got)
+	}
+}
@@ -33,13 +33,13 @@ func FindTrending(posts []ScoredPost, profile *config.TasteProfile, minSources i
 	kwChannels := make(map[string]map[string]bool)
 
 	for _, sp := range posts {
-		if sp.Tier != TierReadNow && sp.Tier != TierSkim {
+		if sp.Tier == TierIgnore {
 			continue
 		}
-		textLower := strings.ToLower(sp.Post.Text)
+		text := newMatchText(sp.Post.Text)
 
 		for _, kw := range keywords {
-			if strings.Contains(textLower, strings.ToLower(kw)) {
+			if text.contains(kw, profile.Match.For(kw)) {
 				if kwChannels[kw] == nil {
 					kwChannels[kw] = make(map[string]bool)
 				}
@@ -102,7 +102,7 @@ func collectKeywords(profile *config.TasteProfile) []string {
 	}
 
 	for _, rule := range profile.Rules {
-		for _, kw := range rule.If.ContainsAny {
+		for _, kw := range rule.If.Terms() {
 			lower := strings.ToLower(kw)
 			if !seen[lower] {
 				keywords = append(keywords, kw)
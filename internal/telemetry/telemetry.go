@@ -0,0 +1,187 @@
+// Package telemetry sets up OpenTelemetry tracing from the standard OTEL_*
+// environment variables and provides the spans noisepan records.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/ppiankov/noisepan"
+
+// Setup installs a global tracer provider and returns a function that
+// flushes and stops it. Tracing is off (spans are no-ops) unless
+// OTEL_TRACES_EXPORTER is "otlp" or "console", or an OTLP endpoint is set
+// with OTEL_EXPORTER_OTLP_ENDPOINT or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT.
+// The exporter, sampler, batching, and resource read their usual OTEL_*
+// variables; OTLP is sent over HTTP/protobuf. Console spans go to stderr.
+func Setup(ctx context.Context, version string) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return noop, nil
+	}
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch name := exporterName(); name {
+	case "none":
+		return noop, nil
+	case "otlp":
+		if p := otlpProtocol(); p != "http/protobuf" {
+			return nil, fmt.Errorf("OTEL_EXPORTER_OTLP_PROTOCOL %q is not supported (want http/protobuf)", p)
+		}
+		exporter, err = otlptracehttp.New(ctx)
+	case "console":
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stderr))
+	default:
+		return nil, fmt.Errorf("OTEL_TRACES_EXPORTER %q is not supported (want otlp, console, or none)", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create trace exporter: %w", err)
+	}
+
+	// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override these.
+	res, err := resource.Merge(
+		resource.NewSchemaless(semconv.ServiceName("noisepan"), semconv.ServiceVersion(version)),
+		resource.Environment(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("build trace resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// exporterName picks the exporter: OTEL_TRACES_EXPORTER when set (only its
+// first entry), otherwise otlp if an endpoint is configured, else none.
+func exporterName() string {
+	if v := strings.TrimSpace(os.Getenv("OTEL_TRACES_EXPORTER")); v != "" {
+		first, _, _ := strings.Cut(v, ",")
+		return strings.ToLower(strings.TrimSpace(first))
+	}
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != "" {
+		return "otlp"
+	}
+	return "none"
+}
+
+func otlpProtocol() string {
+	for _, key := range []string{"OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", "OTEL_EXPORTER_OTLP_PROTOCOL"} {
+		if v := os.Getenv(key); v != "" {
+			return v
+		}
+	}
+	return "http/protobuf"
+}
+
+// Start starts a span named name as a child of any span in ctx.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartRoot starts a span named name in a trace of its own, linked to any
+// span in ctx, for work repeated by a long-running command.
+func StartRoot(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithNewRoot(),
+		trace.WithLinks(trace.LinkFromContext(ctx)), trace.WithAttributes(attrs...))
+}
+
+// End records err, if any, on span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Transport records a client span for every HTTP request, so each feed,
+// API page, or LLM call shows up with its URL, status, and duration.
+// Sources build requests without a context, so spans are parented to
+// the context parent returns unless the request carries a span of its own.
+type Transport struct {
+	name   string
+	base   http.RoundTripper
+	parent func() context.Context
+}
+
+// NewTransport wraps base. name prefixes span names ("rss GET", "llm POST");
+// parent may be nil.
+func NewTransport(name string, base http.RoundTripper, parent func() context.Context) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{name: name, base: base, parent: parent}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if !trace.SpanContextFromContext(ctx).IsValid() && t.parent != nil {
+		ctx = t.parent()
+	}
+	// No trace headers are sent: feeds and APIs are third parties.
+	_, span := otel.Tracer(tracerName).Start(ctx, t.name+" "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.URLFull(redactURL(req)),
+			semconv.ServerAddress(req.URL.Hostname()),
+		))
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		End(span, err)
+		return nil, err
+	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	// A slow feed is often slow to download, not to answer.
+	resp.Body = &spanBody{ReadCloser: resp.Body, span: span}
+	return resp, nil
+}
+
+// spanBody ends its span when the response body is closed.
+type spanBody struct {
+	io.ReadCloser
+	span trace.Span
+	once sync.Once
+}
+
+func (b *spanBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() { b.span.End() })
+	return err
+}
+
+// redactURL drops credentials and the query string, which may carry API
+// keys or tokens.
+func redactURL(req *http.Request) string {
+	u := *req.URL
+	u.User = nil
+	u.RawQuery = ""
+	return u.String()
+}
@@ -0,0 +1,188 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+		_ = tp.Shutdown(context.Background())
+	})
+	return rec
+}
+
+func attr(span sdktrace.ReadOnlySpan, key attribute.Key) string {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestExporterName(t *testing.T) {
+	tests := []struct {
+		name     string
+		exporter string
+		endpoint string
+		want     string
+	}{
+		{"nothing set", "", "", "none"},
+		{"endpoint implies otlp", "", "http://localhost:4318", "otlp"},
+		{"explicit", "Console", "", "console"},
+		{"first of list", "otlp,console", "", "otlp"},
+		{"explicit none wins", "none", "http://localhost:4318", "none"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OTEL_TRACES_EXPORTER", tt.exporter)
+			t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", tt.endpoint)
+			t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+			if got := exporterName(); got != tt.want {
+				t.Errorf("exporterName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetup_Disabled(t *testing.T) {
+	t.Setenv("OTEL_SDK_DISABLED", "true")
+	t.Setenv("OTEL_TRACES_EXPORTER", "bogus")
+	shutdown, err := Setup(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown: %v", err)
+	}
+}
+
+func TestSetup_Unsupported(t *testing.T) {
+	t.Setenv("OTEL_SDK_DISABLED", "")
+
+	t.Setenv("OTEL_TRACES_EXPORTER", "zipkin")
+	if _, err := Setup(context.Background(), "test"); err == nil {
+		t.Error("expected error for unsupported exporter")
+	}
+
+	t.Setenv("OTEL_TRACES_EXPORTER", "otlp")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "grpc")
+	if _, err := Setup(context.Background(), "test"); err == nil {
+		t.Error("expected error for unsupported protocol")
+	}
+}
+
+func TestEnd_RecordsError(t *testing.T) {
+	rec := recordSpans(t)
+
+	_, span := Start(nil, "fetch rss") //nolint:staticcheck // nil ctx is allowed
+	End(span, errors.New("boom"))
+
+	spans := rec.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("ended spans = %d, want 1", len(spans))
+	}
+	if spans[0].Name() != "fetch rss" || spans[0].Status().Code != codes.Error {
+		t.Errorf("span = %q status %v, want fetch rss with error", spans[0].Name(), spans[0].Status().Code)
+	}
+}
+
+func TestStartRoot_NewTraceLinked(t *testing.T) {
+	rec := recordSpans(t)
+
+	ctx, parent := Start(context.Background(), "noisepan run")
+	_, cycle := StartRoot(ctx, "noisepan run cycle")
+	cycle.End()
+	parent.End()
+
+	spans := rec.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("ended spans = %d, want 2", len(spans))
+	}
+	c, p := spans[0], spans[1]
+	if c.SpanContext().TraceID() == p.SpanContext().TraceID() {
+		t.Error("cycle span shares the command's trace")
+	}
+	if len(c.Links()) != 1 || c.Links()[0].SpanContext.SpanID() != p.SpanContext().SpanID() {
+		t.Errorf("cycle links = %v, want the command span", c.Links())
+	}
+}
+
+func TestTransport(t *testing.T) {
+	rec := recordSpans(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Traceparent") != "" {
+			t.Error("trace header sent to a third party")
+		}
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	parentCtx, parent := Start(context.Background(), "fetch rss")
+	client := &http.Client{Transport: NewTransport("rss", nil, func() context.Context { return parentCtx })}
+
+	resp, err := client.Get(srv.URL + "/feed?api_key=secret")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if len(rec.Ended()) != 0 {
+		t.Error("span ended before the body was closed")
+	}
+	_, _ = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	_ = resp.Body.Close()
+	parent.End()
+
+	spans := rec.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("ended spans = %d, want 2", len(spans))
+	}
+	s := spans[0]
+	if s.Name() != "rss GET" {
+		t.Errorf("name = %q, want rss GET", s.Name())
+	}
+	if s.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("request span is not a child of the parent context")
+	}
+	if got, want := attr(s, "url.full"), srv.URL+"/feed"; got != want {
+		t.Errorf("url.full = %q, want %q", got, want)
+	}
+	if got := attr(s, "http.response.status_code"); got != "200" {
+		t.Errorf("status code = %q, want 200", got)
+	}
+}
+
+func TestTransport_Error(t *testing.T) {
+	rec := recordSpans(t)
+
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	client := &http.Client{Transport: NewTransport("llm", nil, nil)}
+	if _, err := client.Post(srv.URL, "application/json", nil); err == nil {
+		t.Fatal("expected error from closed server")
+	}
+
+	spans := rec.Ended()
+	if len(spans) != 1 || spans[0].Status().Code != codes.Error {
+		t.Fatalf("spans = %d, want one failed span", len(spans))
+	}
+}
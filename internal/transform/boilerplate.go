@@ -0,0 +1,154 @@
+package transform
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// minBlockRunes keeps short repeated lines ("Read more", sign-offs) that say
+// nothing about a channel's boilerplate from being learned.
+const minBlockRunes = 20
+
+// Block is a normalized text block and the number of posts containing it.
+type Block struct {
+	Text  string
+	Posts int
+}
+
+// DetectBoilerplate returns the paragraphs and lines that occur in at least
+// minShare of texts, provided there are at least minPosts texts to compare.
+// A block found as a whole paragraph is not repeated for each of its lines.
+// Results are sorted by post count, then text.
+func DetectBoilerplate(texts []string, minShare float64, minPosts int) []Block {
+	if len(texts) < minPosts || len(texts) == 0 {
+		return nil
+	}
+	need := int(math.Ceil(minShare * float64(len(texts))))
+	if need < 2 {
+		need = 2 // a block in a single post is content, not boilerplate
+	}
+
+	counts := make(map[string]int)
+	for _, text := range texts {
+		for block := range candidateBlocks(text) {
+			counts[block]++
+		}
+	}
+
+	var found []Block
+	for text, n := range counts {
+		if n >= need {
+			found = append(found, Block{Text: text, Posts: n})
+		}
+	}
+
+	// Drop lines already covered by a learned paragraph.
+	paragraphs := make(map[string]bool)
+	for _, b := range found {
+		if strings.Contains(b.Text, "\n") {
+			paragraphs[b.Text] = true
+		}
+	}
+	kept := found[:0]
+	for _, b := range found {
+		covered := false
+		for p := range paragraphs {
+			if p != b.Text && containsLine(p, b.Text) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			kept = append(kept, b)
+		}
+	}
+
+	sort.Slice(kept, func(i, j int) bool {
+		if kept[i].Posts != kept[j].Posts {
+			return kept[i].Posts > kept[j].Posts
+		}
+		return kept[i].Text < kept[j].Text
+	})
+	return kept
+}
+
+// StripBoilerplate removes from text every paragraph or line whose
+// normalized form is in blocks. A post that is nothing but boilerplate is
+// returned unchanged.
+func StripBoilerplate(text string, blocks map[string]bool) string {
+	if len(blocks) == 0 {
+		return text
+	}
+	var out []string
+	for _, para := range splitParagraphs(text) {
+		if blocks[normalizeParagraph(para)] {
+			continue
+		}
+		var lines []string
+		for _, line := range strings.Split(para, "\n") {
+			if !blocks[normalizeLine(line)] {
+				lines = append(lines, line)
+			}
+		}
+		if len(lines) > 0 {
+			out = append(out, strings.Join(lines, "\n"))
+		}
+	}
+	if len(out) == 0 {
+		return text
+	}
+	return strings.Join(out, "\n\n")
+}
+
+// candidateBlocks returns the distinct normalized paragraphs and lines of
+// text long enough to be learned.
+func candidateBlocks(text string) map[string]bool {
+	set := make(map[string]bool)
+	for _, para := range splitParagraphs(text) {
+		if p := normalizeParagraph(para); utf8.RuneCountInString(p) >= minBlockRunes {
+			set[p] = true
+		}
+		for _, line := range strings.Split(para, "\n") {
+			if l := normalizeLine(line); utf8.RuneCountInString(l) >= minBlockRunes {
+				set[l] = true
+			}
+		}
+	}
+	return set
+}
+
+func splitParagraphs(text string) []string {
+	var paras []string
+	for _, p := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		if strings.TrimSpace(p) != "" {
+			paras = append(paras, p)
+		}
+	}
+	return paras
+}
+
+// normalizeLine collapses whitespace so re-wrapped copies of a block match.
+func normalizeLine(line string) string {
+	return strings.Join(strings.Fields(line), " ")
+}
+
+func normalizeParagraph(para string) string {
+	var lines []string
+	for _, line := range strings.Split(para, "\n") {
+		if l := normalizeLine(line); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func containsLine(paragraph, line string) bool {
+	for _, l := range strings.Split(paragraph, "\n") {
+		if l == line {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package transform
+
+import (
+	"fmt"
+	"testing"
+)
+
+const footer = "You are receiving this because you subscribed.\nUnsubscribe: https://example.com/u"
+
+func newsletterIssues(n int) []string {
+	texts := make([]string, n)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("Issue %d: Kubernetes news roundup number %d\n\nSponsored by Acme Observability Platform\n\n%s", i, i, footer)
+	}
+	return texts
+}
+
+func TestDetectBoilerplate(t *testing.T) {
+	texts := newsletterIssues(5)
+	// One issue re-wraps the footer and skips the sponsor.
+	texts[4] = "Issue 4: something different entirely today\n\nYou are receiving   this because you subscribed.\nUnsubscribe: https://example.com/u"
+
+	got := DetectBoilerplate(texts, 0.8, 5)
+	if len(got) != 2 {
+		t.Fatalf("blocks = %+v, want footer paragraph and sponsor", got)
+	}
+	if got[0].Text != footer || got[0].Posts != 5 {
+		t.Errorf("first block = %+v, want footer in 5 posts", got[0])
+	}
+	if got[1].Text != "Sponsored by Acme Observability Platform" || got[1].Posts != 4 {
+		t.Errorf("second block = %+v", got[1])
+	}
+}
+
+func TestDetectBoilerplate_TooFewPosts(t *testing.T) {
+	if got := DetectBoilerplate(newsletterIssues(3), 0.6, 5); got != nil {
+		t.Errorf("blocks = %+v, want none below min posts", got)
+	}
+}
+
+func TestStripBoilerplate(t *testing.T) {
+	blocks := map[string]bool{}
+	for _, b := range DetectBoilerplate(newsletterIssues(6), 0.6, 5) {
+		blocks[b.Text] = true
+	}
+
+	text := "Fresh issue: Helm 4 released\n\nSponsored by   Acme Observability Platform\n\n" + footer
+	if got, want := StripBoilerplate(text, blocks), "Fresh issue: Helm 4 released"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// Lines learned alone are removed from inside other paragraphs.
+	lineOnly := map[string]bool{"Sponsored by Acme Observability Platform": true}
+	if got, want := StripBoilerplate("News item\nSponsored by Acme Observability Platform", lineOnly), "News item"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// Nothing but boilerplate: keep the post as is.
+	if got := StripBoilerplate(footer, blocks); got != footer {
+		t.Errorf("got %q, want original text", got)
+	}
+}
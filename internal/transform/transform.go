@@ -0,0 +1,107 @@
+// Package transform applies the config-driven text cleanups (transforms:)
+// that run on posts between fetch and store.
+package transform
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ppiankov/noisepan/internal/config"
+)
+
+// blankRuns matches three or more line breaks left behind by removals.
+var blankRuns = regexp.MustCompile(`\n\s*\n(\s*\n)+`)
+
+type replacement struct {
+	re   *regexp.Regexp
+	with string
+}
+
+type step struct {
+	channels  map[string]bool // nil applies to all channels
+	dropAfter []string
+	strip     []*regexp.Regexp
+	replace   []replacement
+}
+
+// Pipeline is a compiled list of transforms.
+type Pipeline struct {
+	steps []step
+}
+
+// Compile builds a pipeline from config. An empty config yields a pipeline
+// that leaves text unchanged.
+func Compile(cfgs []config.TransformConfig) (*Pipeline, error) {
+	p := &Pipeline{}
+	for i, c := range cfgs {
+		st := step{dropAfter: c.DropAfter}
+		if len(c.Channels) > 0 {
+			st.channels = make(map[string]bool, len(c.Channels))
+			for _, ch := range c.Channels {
+				st.channels[ch] = true
+			}
+		}
+		for _, pat := range c.Strip {
+			re, err := regexp.Compile(pat)
+			if err != nil {
+				return nil, fmt.Errorf("transforms[%d]: compile strip pattern %q: %w", i, pat, err)
+			}
+			st.strip = append(st.strip, re)
+		}
+		for _, r := range c.Replace {
+			re, err := regexp.Compile(r.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("transforms[%d]: compile replace pattern %q: %w", i, r.Pattern, err)
+			}
+			st.replace = append(st.replace, replacement{re: re, with: r.With})
+		}
+		p.steps = append(p.steps, st)
+	}
+	return p, nil
+}
+
+// Apply runs every transform that covers channel over text. Runs of blank
+// lines left by removals collapse to one and surrounding space is trimmed.
+func (p *Pipeline) Apply(channel, text string) string {
+	if p == nil || len(p.steps) == 0 {
+		return text
+	}
+	changed := false
+	for _, st := range p.steps {
+		if st.channels != nil && !st.channels[channel] {
+			continue
+		}
+		before := text
+		text = dropAfter(text, st.dropAfter)
+		for _, re := range st.strip {
+			text = re.ReplaceAllString(text, "")
+		}
+		for _, r := range st.replace {
+			text = r.re.ReplaceAllString(text, r.with)
+		}
+		changed = changed || text != before
+	}
+	if !changed {
+		return text
+	}
+	return strings.TrimSpace(blankRuns.ReplaceAllString(text, "\n\n"))
+}
+
+// dropAfter cuts text at the first line that starts with a marker. A marker
+// on the first line is ignored so a post is never emptied entirely.
+func dropAfter(text string, markers []string) string {
+	if len(markers) == 0 {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimLeft(lines[i], " \t")
+		for _, m := range markers {
+			if strings.HasPrefix(line, m) {
+				return strings.Join(lines[:i], "\n")
+			}
+		}
+	}
+	return text
+}
@@ -0,0 +1,71 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/ppiankov/noisepan/internal/config"
+)
+
+const newsletter = `Kubernetes 1.40 released
+
+Highlights inside.
+
+--
+You are receiving this because you subscribed.
+Unsubscribe: https://example.com/u/123`
+
+func TestApply_DropAfter(t *testing.T) {
+	p, err := Compile([]config.TransformConfig{{DropAfter: []string{"--"}}})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if got, want := p.Apply("any", newsletter), "Kubernetes 1.40 released\n\nHighlights inside."; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	// A marker on the first line never empties the post.
+	if got := p.Apply("any", "-- draft\nbody"); got != "-- draft\nbody" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestApply_StripAndReplace(t *testing.T) {
+	p, err := Compile([]config.TransformConfig{{
+		Strip:   []string{`(?m)^Unsubscribe:.*$`, `(?m)^You are receiving.*$`},
+		Replace: []config.ReplaceConfig{{Pattern: `Kubernetes (\d+\.\d+)`, With: "k8s $1"}},
+	}})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if got, want := p.Apply("any", newsletter), "k8s 1.40 released\n\nHighlights inside.\n\n--"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestApply_ChannelScope(t *testing.T) {
+	p, err := Compile([]config.TransformConfig{{Channels: []string{"Ops Weekly"}, DropAfter: []string{"--"}}})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if got := p.Apply("@other", newsletter); got != newsletter {
+		t.Errorf("other channel changed: %q", got)
+	}
+	if got := p.Apply("Ops Weekly", newsletter); got == newsletter {
+		t.Error("scoped channel unchanged")
+	}
+}
+
+func TestCompile_InvalidPattern(t *testing.T) {
+	if _, err := Compile([]config.TransformConfig{{Strip: []string{"[bad"}}}); err == nil {
+		t.Error("expected error for invalid strip pattern")
+	}
+	if _, err := Compile([]config.TransformConfig{{Replace: []config.ReplaceConfig{{Pattern: "(bad"}}}}); err == nil {
+		t.Error("expected error for invalid replace pattern")
+	}
+}
+
+func TestApply_Empty(t *testing.T) {
+	var p *Pipeline
+	if got := p.Apply("c", "  text  "); got != "  text  " {
+		t.Errorf("nil pipeline changed text: %q", got)
+	}
+}
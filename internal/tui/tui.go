@@ -0,0 +1,337 @@
+// Package tui is the interactive terminal reader behind "noisepan tui": a
+// scrollable list of scored posts grouped by tier, with keys to expand
+// summaries, mark read, star, vote, and open links. It holds no storage
+// itself; every change goes through Actions.
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ppiankov/noisepan/internal/taste"
+)
+
+// Item is one post in the reader.
+type Item struct {
+	ID      int64
+	Source  string
+	Channel string
+	Title   string // one-line headline or snippet
+	URL     string
+	Tier    string
+	Score   int
+	Summary []string // bullets shown when the item is expanded
+
+	Read    bool
+	Starred bool
+	Vote    int // +1 up, -1 down, 0 none
+}
+
+// Actions persists what the reader does to a post.
+type Actions interface {
+	MarkRead(id int64) error
+	SetStarred(id int64, starred bool) error
+	Vote(id int64, up bool) error
+	Open(url string) error
+}
+
+// helpLine lists the keys; it is the last line of the view.
+const helpLine = "j/k move  enter summary  o open  r read  s star  +/- vote  q quit"
+
+// Model is the bubbletea model of the reader.
+type Model struct {
+	items    []Item
+	actions  Actions
+	color    bool
+	tiers    []string // tier names, highest first
+	cursor   int
+	expanded map[int]bool
+	offset   int // first body line shown
+	width    int
+	height   int
+	status   string
+}
+
+// New returns a reader over items, read_now first and highest score first
+// within a tier.
+func New(items []Item, actions Actions, color bool) *Model {
+	sorted := make([]Item, len(items))
+	copy(sorted, items)
+	m := &Model{
+		items: sorted, actions: actions, color: color, expanded: make(map[int]bool),
+		tiers: []string{taste.TierReadNow, taste.TierSkim, taste.TierIgnore},
+	}
+	m.sortItems()
+	return m
+}
+
+// SetTiers orders the tiers by a taste profile's tier names, highest first,
+// instead of the default read_now, skim and ignore.
+func (m *Model) SetTiers(tiers []string) {
+	m.tiers = tiers
+	m.sortItems()
+}
+
+// sortItems orders items by tier, then by score, highest first. Tiers
+// missing from m.tiers go last.
+func (m *Model) sortItems() {
+	order := func(tier string) int {
+		if i := slices.Index(m.tiers, tier); i >= 0 {
+			return i
+		}
+		return len(m.tiers)
+	}
+	sort.SliceStable(m.items, func(i, j int) bool {
+		if ri, rj := order(m.items[i].Tier), order(m.items[j].Tier); ri != rj {
+			return ri < rj
+		}
+		return m.items[i].Score > m.items[j].Score
+	})
+}
+
+// Items returns the items with the state changed by the reader.
+func (m *Model) Items() []Item {
+	return m.items
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case tea.KeyMsg:
+		if len(m.items) == 0 {
+			if key := msg.String(); key == "q" || key == "ctrl+c" || key == "esc" {
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+		m.status = ""
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			return m, tea.Quit
+		case "up", "k":
+			m.cursor = max(m.cursor-1, 0)
+		case "down", "j":
+			m.cursor = min(m.cursor+1, len(m.items)-1)
+		case "home", "g":
+			m.cursor = 0
+		case "end", "G":
+			m.cursor = len(m.items) - 1
+		case "enter", " ":
+			m.expanded[m.cursor] = !m.expanded[m.cursor]
+		case "r":
+			m.markRead()
+		case "s":
+			m.toggleStar()
+		case "+", "u":
+			m.vote(true)
+		case "-", "d":
+			m.vote(false)
+		case "o":
+			m.open()
+		}
+	}
+	m.scroll()
+	return m, nil
+}
+
+func (m *Model) markRead() {
+	it := &m.items[m.cursor]
+	if it.Read {
+		return
+	}
+	if err := m.actions.MarkRead(it.ID); err != nil {
+		m.status = "mark read failed: " + err.Error()
+		return
+	}
+	it.Read = true
+}
+
+func (m *Model) toggleStar() {
+	it := &m.items[m.cursor]
+	if err := m.actions.SetStarred(it.ID, !it.Starred); err != nil {
+		m.status = "star failed: " + err.Error()
+		return
+	}
+	it.Starred = !it.Starred
+}
+
+func (m *Model) vote(up bool) {
+	it := &m.items[m.cursor]
+	if err := m.actions.Vote(it.ID, up); err != nil {
+		m.status = "vote failed: " + err.Error()
+		return
+	}
+	it.Vote = -1
+	if up {
+		it.Vote = 1
+	}
+}
+
+func (m *Model) open() {
+	it := &m.items[m.cursor]
+	if it.URL == "" {
+		m.status = "no link to open"
+		return
+	}
+	if err := m.actions.Open(it.URL); err != nil {
+		m.status = "open failed: " + err.Error()
+		return
+	}
+	m.markRead()
+}
+
+// bodyHeight is the number of list lines that fit between the header and
+// the status and help lines, or 0 when the size is unknown.
+func (m *Model) bodyHeight() int {
+	if m.height == 0 {
+		return 0
+	}
+	return max(m.height-3, 1)
+}
+
+// scroll moves the window so the cursor item is visible.
+func (m *Model) scroll() {
+	h := m.bodyHeight()
+	if h == 0 {
+		return
+	}
+	_, start, end := m.body()
+	if start < m.offset {
+		m.offset = start
+	}
+	if end >= m.offset+h {
+		m.offset = max(end-h+1, start)
+	}
+}
+
+// body lays out the list and reports which lines hold the cursor item,
+// counting the tier header above the first item of a tier.
+func (m *Model) body() (lines []string, cursorStart, cursorEnd int) {
+	tier := ""
+	for i, it := range m.items {
+		if i == m.cursor {
+			cursorStart = len(lines)
+		}
+		if it.Tier != tier {
+			tier = it.Tier
+			lines = append(lines, m.bold(fmt.Sprintf("%s (%d)", taste.TierTitle(tier), m.countTier(tier))))
+		}
+		lines = append(lines, m.itemLine(i, it))
+		if m.expanded[i] {
+			for _, b := range it.Summary {
+				lines = append(lines, m.dim("      - "+b))
+			}
+			if it.URL != "" {
+				lines = append(lines, m.dim("      "+it.URL))
+			}
+		}
+		if i == m.cursor {
+			cursorEnd = len(lines) - 1
+		}
+	}
+	return lines, cursorStart, cursorEnd
+}
+
+func (m *Model) itemLine(i int, it Item) string {
+	pointer := "  "
+	if i == m.cursor {
+		pointer = "> "
+	}
+	marks := []rune("    ")
+	if !it.Read {
+		marks[0] = '•'
+	}
+	if it.Starred {
+		marks[1] = '★'
+	}
+	switch it.Vote {
+	case 1:
+		marks[2] = '▲'
+	case -1:
+		marks[2] = '▼'
+	}
+	line := fmt.Sprintf("%s%s[%d] %s/%s — %s", pointer, string(marks), it.Score, it.Source, it.Channel, it.Title)
+	line = m.truncate(line)
+	switch {
+	case i == m.cursor:
+		return m.bold(line)
+	case it.Read:
+		return m.dim(line)
+	}
+	return line
+}
+
+func (m *Model) View() string {
+	var b strings.Builder
+	unread := 0
+	for _, it := range m.items {
+		if !it.Read {
+			unread++
+		}
+	}
+	fmt.Fprintf(&b, "noisepan — %d posts, %d unread\n", len(m.items), unread)
+
+	if len(m.items) == 0 {
+		b.WriteString("\nNothing to show.\n\nq quit\n")
+		return b.String()
+	}
+
+	lines, _, _ := m.body()
+	if h := m.bodyHeight(); h > 0 {
+		end := min(m.offset+h, len(lines))
+		lines = lines[m.offset:end]
+		for len(lines) < h {
+			lines = append(lines, "")
+		}
+	}
+	for _, l := range lines {
+		b.WriteString(l + "\n")
+	}
+	b.WriteString(m.status + "\n")
+	b.WriteString(m.dim(m.truncate(helpLine)))
+	return b.String()
+}
+
+func (m *Model) countTier(tier string) int {
+	n := 0
+	for _, it := range m.items {
+		if it.Tier == tier {
+			n++
+		}
+	}
+	return n
+}
+
+// truncate cuts s to the terminal width, if known.
+func (m *Model) truncate(s string) string {
+	if m.width <= 0 {
+		return s
+	}
+	r := []rune(s)
+	if len(r) <= m.width {
+		return s
+	}
+	return string(r[:max(m.width-1, 0)]) + "…"
+}
+
+func (m *Model) bold(s string) string {
+	if !m.color {
+		return s
+	}
+	return "\033[1m" + s + "\033[0m"
+}
+
+func (m *Model) dim(s string) string {
+	if !m.color {
+		return s
+	}
+	return "\033[2m" + s + "\033[0m"
+}
@@ -0,0 +1,186 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ppiankov/noisepan/internal/taste"
+)
+
+type fakeActions struct {
+	read    []int64
+	starred map[int64]bool
+	votes   map[int64]bool
+	opened  []string
+	err     error
+}
+
+func newFakeActions() *fakeActions {
+	return &fakeActions{starred: map[int64]bool{}, votes: map[int64]bool{}}
+}
+
+func (f *fakeActions) MarkRead(id int64) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.read = append(f.read, id)
+	return nil
+}
+
+func (f *fakeActions) SetStarred(id int64, starred bool) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.starred[id] = starred
+	return nil
+}
+
+func (f *fakeActions) Vote(id int64, up bool) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.votes[id] = up
+	return nil
+}
+
+func (f *fakeActions) Open(url string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.opened = append(f.opened, url)
+	return nil
+}
+
+func testItems() []Item {
+	return []Item{
+		{ID: 1, Source: "rss", Channel: "blog", Title: "New Helm chart", Tier: taste.TierSkim, Score: 4},
+		{ID: 2, Source: "rss", Channel: "security", Title: "OpenSSL CVE", URL: "https://example.com/2",
+			Tier: taste.TierReadNow, Score: 9, Summary: []string{"Patch now"}},
+		{ID: 3, Source: "hn", Channel: "Hacker News", Title: "Kernel release", Tier: taste.TierReadNow, Score: 7, Read: true},
+	}
+}
+
+func press(m *Model, keys ...string) {
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		m.Update(msg)
+	}
+}
+
+func TestModel_OrderAndView(t *testing.T) {
+	m := New(testItems(), newFakeActions(), false)
+
+	var ids []int64
+	for _, it := range m.Items() {
+		ids = append(ids, it.ID)
+	}
+	if len(ids) != 3 || ids[0] != 2 || ids[1] != 3 || ids[2] != 1 {
+		t.Fatalf("order = %v, want [2 3 1]", ids)
+	}
+
+	view := m.View()
+	for _, want := range []string{
+		"noisepan — 3 posts, 2 unread",
+		"Read Now (2)",
+		"> •   [9] rss/security — OpenSSL CVE",
+		"      [7] hn/Hacker News — Kernel release",
+		"Skim (1)",
+		helpLine,
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "Patch now") {
+		t.Error("summary shown before expanding")
+	}
+
+	press(m, "enter")
+	if view := m.View(); !strings.Contains(view, "      - Patch now") || !strings.Contains(view, "      https://example.com/2") {
+		t.Errorf("expanded view missing summary:\n%s", view)
+	}
+}
+
+func TestModel_Actions(t *testing.T) {
+	fa := newFakeActions()
+	m := New(testItems(), fa, false)
+
+	press(m, "s", "+", "o")
+	if !fa.starred[2] || !fa.votes[2] {
+		t.Errorf("star/vote not saved: %+v %+v", fa.starred, fa.votes)
+	}
+	if len(fa.opened) != 1 || fa.opened[0] != "https://example.com/2" {
+		t.Errorf("opened = %v", fa.opened)
+	}
+	if len(fa.read) != 1 || fa.read[0] != 2 {
+		t.Errorf("read = %v, want open to mark #2 read", fa.read)
+	}
+	it := m.Items()[0]
+	if !it.Read || !it.Starred || it.Vote != 1 {
+		t.Errorf("item state = %+v", it)
+	}
+
+	// Already read posts are not marked again; the cursor stops at the ends.
+	press(m, "j", "r", "j", "j", "-", "r")
+	if len(fa.read) != 2 || fa.read[1] != 1 {
+		t.Errorf("read = %v, want [2 1]", fa.read)
+	}
+	if !strings.Contains(m.View(), ">   ▼ [4] rss/blog") {
+		t.Errorf("cursor not on last item with down vote:\n%s", m.View())
+	}
+
+	press(m, "o")
+	if !strings.Contains(m.View(), "no link to open") {
+		t.Errorf("missing no-link status:\n%s", m.View())
+	}
+
+	fa.err = errors.New("db locked")
+	press(m, "s")
+	if !strings.Contains(m.View(), "star failed: db locked") || m.Items()[2].Starred {
+		t.Errorf("star error not reported:\n%s", m.View())
+	}
+}
+
+func TestModel_ScrollKeepsCursorVisible(t *testing.T) {
+	var items []Item
+	for i := range 20 {
+		items = append(items, Item{ID: int64(i + 1), Title: "post", Tier: taste.TierSkim, Score: 20 - i})
+	}
+	m := New(items, newFakeActions(), false)
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 8})
+
+	press(m, "G")
+	view := m.View()
+	if !strings.Contains(view, "> •   [1] /") {
+		t.Errorf("cursor item not visible after G:\n%s", view)
+	}
+	if strings.Contains(view, "Skim (20)") {
+		t.Errorf("view did not scroll:\n%s", view)
+	}
+	if lines := strings.Count(view, "\n") + 1; lines != 8 {
+		t.Errorf("view has %d lines, want 8", lines)
+	}
+
+	press(m, "g")
+	if !strings.Contains(m.View(), "Skim (20)") {
+		t.Errorf("view did not scroll back to top:\n%s", m.View())
+	}
+}
+
+func TestModel_QuitAndEmpty(t *testing.T) {
+	m := New(nil, newFakeActions(), false)
+	if !strings.Contains(m.View(), "Nothing to show.") {
+		t.Errorf("empty view = %q", m.View())
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}); cmd == nil {
+		t.Error("q did not quit")
+	}
+}
@@ -49,15 +49,69 @@ async def fetch_channel(client, channel_name, since):
     ):
         if message.date.replace(tzinfo=timezone.utc) < since:
             break
-        if not message.text:
+        media_type = media_kind(message)
+        # Album parts without a caption are kept so noisepan can merge them
+        # with the part that carries the caption.
+        if not message.text and not message.grouped_id:
             continue
-        yield {
+        record = {
             "channel": clean_name,
             "msg_id": str(message.id),
             "date": message.date.replace(tzinfo=timezone.utc).isoformat(),
-            "text": message.text,
+            "text": message.text or "",
             "url": f"https://t.me/{clean_name}/{message.id}",
         }
+        if media_type:
+            record["media_type"] = media_type
+            if message.message:
+                record["caption"] = message.message
+        if message.grouped_id:
+            record["grouped_id"] = str(message.grouped_id)
+        author = message_author(message)
+        if author:
+            record["author"] = author
+        forward_from, forward_msg_id = forward_origin(message)
+        if forward_from:
+            record["forward_from"] = forward_from
+            if forward_msg_id:
+                record["forward_msg_id"] = forward_msg_id
+        yield record
+
+
+def media_kind(message):
+    """Return a short media type name, or None for plain text messages."""
+    if message.photo:
+        return "photo"
+    if message.video:
+        return "video"
+    if message.poll:
+        return "poll"
+    if message.web_preview:
+        return "webpage"
+    if message.document:
+        return "document"
+    return None
+
+
+def message_author(message):
+    """Return the post signature in channels, or the sender's username."""
+    if message.post_author:
+        return message.post_author
+    sender = message.sender
+    return getattr(sender, "username", None) if sender else None
+
+
+def forward_origin(message):
+    """Return (channel username, message id) of a forwarded channel post."""
+    fwd = message.forward
+    if fwd is None:
+        return None, None
+    chat = getattr(fwd, "chat", None)
+    username = getattr(chat, "username", None) if chat else None
+    if not username:
+        return None, None
+    post_id = fwd.channel_post
+    return username, str(post_id) if post_id else None
 
 
 async def main():